@@ -82,13 +82,13 @@ func addMusicServiceAccount(c *cli.Context) error {
 		credentials.DisplayName = displayName
 	}
 
-	fmt.Printf("  Service: %s\n", credentials.GetDescription())
-	fmt.Printf("  User: %s\n", user)
+	output.Printf("  Service: %s\n", credentials.GetDescription())
+	output.Printf("  User: %s\n", user)
 
 	if source == "STORED_MUSIC" {
-		fmt.Printf("  Type: Network Music Library\n")
+		output.Printf("  Type: Network Music Library\n")
 	} else {
-		fmt.Printf("  Type: Streaming Service\n")
+		output.Printf("  Type: Streaming Service\n")
 	}
 
 	err = client.SetMusicServiceAccount(credentials)
@@ -99,9 +99,9 @@ func addMusicServiceAccount(c *cli.Context) error {
 	PrintSuccess(fmt.Sprintf("%s account added successfully", source))
 
 	// Show next steps
-	fmt.Printf("\n💡 Next Steps:\n")
-	fmt.Printf("   • Check available sources: soundtouch-cli --host %s source list\n", clientConfig.Host)
-	fmt.Printf("   • Select this source: soundtouch-cli --host %s source select --source %s --account %s\n", clientConfig.Host, source, user)
+	output.Printf("\n💡 Next Steps:\n")
+	output.Printf("   • Check available sources: soundtouch-cli --host %s source list\n", clientConfig.Host)
+	output.Printf("   • Select this source: soundtouch-cli --host %s source select --source %s --account %s\n", clientConfig.Host, source, user)
 
 	return nil
 }
@@ -163,8 +163,8 @@ func removeMusicServiceAccount(c *cli.Context) error {
 		credentials.DisplayName = displayName
 	}
 
-	fmt.Printf("  Service: %s\n", credentials.GetDescription())
-	fmt.Printf("  User: %s\n", user)
+	output.Printf("  Service: %s\n", credentials.GetDescription())
+	output.Printf("  User: %s\n", user)
 
 	err = client.RemoveMusicServiceAccount(credentials)
 	if err != nil {
@@ -198,8 +198,8 @@ func addSpotifyAccount(c *cli.Context) error {
 
 	PrintDeviceHeader("Adding Spotify Premium account", clientConfig.Host, clientConfig.Port)
 
-	fmt.Printf("  User: %s\n", user)
-	fmt.Printf("  Service: Spotify Premium\n")
+	output.Printf("  User: %s\n", user)
+	output.Printf("  Service: Spotify Premium\n")
 
 	err = client.AddSpotifyAccount(user, password)
 	if err != nil {
@@ -209,9 +209,9 @@ func addSpotifyAccount(c *cli.Context) error {
 	PrintSuccess("Spotify account added successfully")
 
 	// Show next steps
-	fmt.Printf("\n💡 Next Steps:\n")
-	fmt.Printf("   • Check available sources: soundtouch-cli --host %s source list\n", clientConfig.Host)
-	fmt.Printf("   • Select Spotify: soundtouch-cli --host %s source spotify\n", clientConfig.Host)
+	output.Printf("\n💡 Next Steps:\n")
+	output.Printf("   • Check available sources: soundtouch-cli --host %s source list\n", clientConfig.Host)
+	output.Printf("   • Select Spotify: soundtouch-cli --host %s source spotify\n", clientConfig.Host)
 
 	return nil
 }
@@ -233,7 +233,7 @@ func removeSpotifyAccount(c *cli.Context) error {
 
 	PrintDeviceHeader("Removing Spotify account", clientConfig.Host, clientConfig.Port)
 
-	fmt.Printf("  User: %s\n", user)
+	output.Printf("  User: %s\n", user)
 
 	err = client.RemoveSpotifyAccount(user)
 	if err != nil {
@@ -267,8 +267,8 @@ func addPandoraAccount(c *cli.Context) error {
 
 	PrintDeviceHeader("Adding Pandora account", clientConfig.Host, clientConfig.Port)
 
-	fmt.Printf("  User: %s\n", user)
-	fmt.Printf("  Service: Pandora Music Service\n")
+	output.Printf("  User: %s\n", user)
+	output.Printf("  Service: Pandora Music Service\n")
 
 	err = client.AddPandoraAccount(user, password)
 	if err != nil {
@@ -278,9 +278,9 @@ func addPandoraAccount(c *cli.Context) error {
 	PrintSuccess("Pandora account added successfully")
 
 	// Show next steps
-	fmt.Printf("\n💡 Next Steps:\n")
-	fmt.Printf("   • Check available sources: soundtouch-cli --host %s source list\n", clientConfig.Host)
-	fmt.Printf("   • Select Pandora: soundtouch-cli --host %s source select --source PANDORA --account %s\n", clientConfig.Host, user)
+	output.Printf("\n💡 Next Steps:\n")
+	output.Printf("   • Check available sources: soundtouch-cli --host %s source list\n", clientConfig.Host)
+	output.Printf("   • Select Pandora: soundtouch-cli --host %s source select --source PANDORA --account %s\n", clientConfig.Host, user)
 
 	return nil
 }
@@ -302,7 +302,7 @@ func removePandoraAccount(c *cli.Context) error {
 
 	PrintDeviceHeader("Removing Pandora account", clientConfig.Host, clientConfig.Port)
 
-	fmt.Printf("  User: %s\n", user)
+	output.Printf("  User: %s\n", user)
 
 	err = client.RemovePandoraAccount(user)
 	if err != nil {
@@ -336,9 +336,9 @@ func addStoredMusicAccount(c *cli.Context) error {
 
 	PrintDeviceHeader("Adding network music library", clientConfig.Host, clientConfig.Port)
 
-	fmt.Printf("  Server ID: %s\n", user)
-	fmt.Printf("  Display Name: %s\n", displayName)
-	fmt.Printf("  Type: UPnP/DLNA Media Server\n")
+	output.Printf("  Server ID: %s\n", user)
+	output.Printf("  Display Name: %s\n", displayName)
+	output.Printf("  Type: UPnP/DLNA Media Server\n")
 
 	err = client.AddStoredMusicAccount(user, displayName)
 	if err != nil {
@@ -348,9 +348,9 @@ func addStoredMusicAccount(c *cli.Context) error {
 	PrintSuccess("Network music library added successfully")
 
 	// Show next steps
-	fmt.Printf("\n💡 Next Steps:\n")
-	fmt.Printf("   • Check available sources: soundtouch-cli --host %s source list\n", clientConfig.Host)
-	fmt.Printf("   • Browse library: soundtouch-cli --host %s browse stored-music --account %s\n", clientConfig.Host, user)
+	output.Printf("\n💡 Next Steps:\n")
+	output.Printf("   • Check available sources: soundtouch-cli --host %s source list\n", clientConfig.Host)
+	output.Printf("   • Browse library: soundtouch-cli --host %s browse stored-music --account %s\n", clientConfig.Host, user)
 
 	return nil
 }
@@ -377,8 +377,8 @@ func addAmazonMusicAccount(c *cli.Context) error {
 
 	PrintDeviceHeader("Adding Amazon Music account", clientConfig.Host, clientConfig.Port)
 
-	fmt.Printf("  User: %s\n", user)
-	fmt.Printf("  Service: Amazon Music\n")
+	output.Printf("  User: %s\n", user)
+	output.Printf("  Service: Amazon Music\n")
 
 	err = client.AddAmazonMusicAccount(user, password)
 	if err != nil {
@@ -388,9 +388,9 @@ func addAmazonMusicAccount(c *cli.Context) error {
 	PrintSuccess("Amazon Music account added successfully")
 
 	// Show next steps
-	fmt.Printf("\n💡 Next Steps:\n")
-	fmt.Printf("   • Check available sources: soundtouch-cli --host %s source list\n", clientConfig.Host)
-	fmt.Printf("   • Select Amazon Music: soundtouch-cli --host %s source select --source AMAZON --account %s\n", clientConfig.Host, user)
+	output.Printf("\n💡 Next Steps:\n")
+	output.Printf("   • Check available sources: soundtouch-cli --host %s source list\n", clientConfig.Host)
+	output.Printf("   • Select Amazon Music: soundtouch-cli --host %s source select --source AMAZON --account %s\n", clientConfig.Host, user)
 
 	return nil
 }
@@ -412,7 +412,7 @@ func removeAmazonMusicAccount(c *cli.Context) error {
 
 	PrintDeviceHeader("Removing Amazon Music account", clientConfig.Host, clientConfig.Port)
 
-	fmt.Printf("  User: %s\n", user)
+	output.Printf("  User: %s\n", user)
 
 	err = client.RemoveAmazonMusicAccount(user)
 	if err != nil {
@@ -446,8 +446,8 @@ func addDeezerAccount(c *cli.Context) error {
 
 	PrintDeviceHeader("Adding Deezer Premium account", clientConfig.Host, clientConfig.Port)
 
-	fmt.Printf("  User: %s\n", user)
-	fmt.Printf("  Service: Deezer Premium\n")
+	output.Printf("  User: %s\n", user)
+	output.Printf("  Service: Deezer Premium\n")
 
 	err = client.AddDeezerAccount(user, password)
 	if err != nil {
@@ -457,9 +457,9 @@ func addDeezerAccount(c *cli.Context) error {
 	PrintSuccess("Deezer account added successfully")
 
 	// Show next steps
-	fmt.Printf("\n💡 Next Steps:\n")
-	fmt.Printf("   • Check available sources: soundtouch-cli --host %s source list\n", clientConfig.Host)
-	fmt.Printf("   • Select Deezer: soundtouch-cli --host %s source select --source DEEZER --account %s\n", clientConfig.Host, user)
+	output.Printf("\n💡 Next Steps:\n")
+	output.Printf("   • Check available sources: soundtouch-cli --host %s source list\n", clientConfig.Host)
+	output.Printf("   • Select Deezer: soundtouch-cli --host %s source select --source DEEZER --account %s\n", clientConfig.Host, user)
 
 	return nil
 }
@@ -481,7 +481,7 @@ func removeDeezerAccount(c *cli.Context) error {
 
 	PrintDeviceHeader("Removing Deezer account", clientConfig.Host, clientConfig.Port)
 
-	fmt.Printf("  User: %s\n", user)
+	output.Printf("  User: %s\n", user)
 
 	err = client.RemoveDeezerAccount(user)
 	if err != nil {
@@ -515,8 +515,8 @@ func addIHeartRadioAccount(c *cli.Context) error {
 
 	PrintDeviceHeader("Adding iHeartRadio account", clientConfig.Host, clientConfig.Port)
 
-	fmt.Printf("  User: %s\n", user)
-	fmt.Printf("  Service: iHeartRadio\n")
+	output.Printf("  User: %s\n", user)
+	output.Printf("  Service: iHeartRadio\n")
 
 	err = client.AddIHeartRadioAccount(user, password)
 	if err != nil {
@@ -526,9 +526,9 @@ func addIHeartRadioAccount(c *cli.Context) error {
 	PrintSuccess("iHeartRadio account added successfully")
 
 	// Show next steps
-	fmt.Printf("\n💡 Next Steps:\n")
-	fmt.Printf("   • Check available sources: soundtouch-cli --host %s source list\n", clientConfig.Host)
-	fmt.Printf("   • Select iHeartRadio: soundtouch-cli --host %s source select --source IHEART --account %s\n", clientConfig.Host, user)
+	output.Printf("\n💡 Next Steps:\n")
+	output.Printf("   • Check available sources: soundtouch-cli --host %s source list\n", clientConfig.Host)
+	output.Printf("   • Select iHeartRadio: soundtouch-cli --host %s source select --source IHEART --account %s\n", clientConfig.Host, user)
 
 	return nil
 }
@@ -550,7 +550,7 @@ func removeIHeartRadioAccount(c *cli.Context) error {
 
 	PrintDeviceHeader("Removing iHeartRadio account", clientConfig.Host, clientConfig.Port)
 
-	fmt.Printf("  User: %s\n", user)
+	output.Printf("  User: %s\n", user)
 
 	err = client.RemoveIHeartRadioAccount(user)
 	if err != nil {
@@ -584,8 +584,8 @@ func removeStoredMusicAccount(c *cli.Context) error {
 
 	PrintDeviceHeader("Removing network music library", clientConfig.Host, clientConfig.Port)
 
-	fmt.Printf("  Server ID: %s\n", user)
-	fmt.Printf("  Display Name: %s\n", displayName)
+	output.Printf("  Server ID: %s\n", user)
+	output.Printf("  Display Name: %s\n", displayName)
 
 	err = client.RemoveStoredMusicAccount(user, displayName)
 	if err != nil {
@@ -623,7 +623,7 @@ func listMusicServiceAccounts(c *cli.Context) error {
 		if len(sourcesOfType) > 0 {
 			found = true
 
-			fmt.Printf("\n📱 %s:\n", getServiceDisplayName(musicSource))
+			output.Printf("\n📱 %s:\n", getServiceDisplayName(musicSource))
 
 			for _, source := range sourcesOfType {
 				status := "🔴 Unavailable"
@@ -636,17 +636,17 @@ func listMusicServiceAccounts(c *cli.Context) error {
 					accountInfo = fmt.Sprintf(" (%s)", source.SourceAccount)
 				}
 
-				fmt.Printf("    %s %s%s\n", status, source.GetDisplayName(), accountInfo)
+				output.Printf("    %s %s%s\n", status, source.GetDisplayName(), accountInfo)
 			}
 		}
 	}
 
 	if !found {
-		fmt.Printf("  📭 No music service accounts configured\n")
-		fmt.Printf("\n💡 Add accounts with:\n")
-		fmt.Printf("   • soundtouch-cli --host %s account add-spotify --user <email> --password <pass>\n", clientConfig.Host)
-		fmt.Printf("   • soundtouch-cli --host %s account add-pandora --user <user> --password <pass>\n", clientConfig.Host)
-		fmt.Printf("   • soundtouch-cli --host %s account add --source AMAZON --user <user> --password <pass>\n", clientConfig.Host)
+		output.Printf("  📭 No music service accounts configured\n")
+		output.Printf("\n💡 Add accounts with:\n")
+		output.Printf("   • soundtouch-cli --host %s account add-spotify --user <email> --password <pass>\n", clientConfig.Host)
+		output.Printf("   • soundtouch-cli --host %s account add-pandora --user <user> --password <pass>\n", clientConfig.Host)
+		output.Printf("   • soundtouch-cli --host %s account add --source AMAZON --user <user> --password <pass>\n", clientConfig.Host)
 	}
 
 	return nil
@@ -25,13 +25,15 @@ func getAudioDSPControls(c *cli.Context) error {
 		return err
 	}
 
-	fmt.Println("DSP Audio Controls:")
-	fmt.Printf("  Audio Mode: %s\n", dspControls.AudioMode)
-	fmt.Printf("  Video Sync Audio Delay: %d ms\n", dspControls.VideoSyncAudioDelay)
+	emitResult(c, dspControls)
+
+	output.Println("DSP Audio Controls:")
+	output.Printf("  Audio Mode: %s\n", dspControls.AudioMode)
+	output.Printf("  Video Sync Audio Delay: %d ms\n", dspControls.VideoSyncAudioDelay)
 
 	supportedModes := dspControls.GetSupportedAudioModes()
 	if len(supportedModes) > 0 {
-		fmt.Printf("  Supported Audio Modes: %s\n", strings.Join(supportedModes, ", "))
+		output.Printf("  Supported Audio Modes: %s\n", strings.Join(supportedModes, ", "))
 	}
 
 	return nil
@@ -61,14 +63,14 @@ func setAudioDSPControls(c *cli.Context) error {
 		return err
 	}
 
-	fmt.Println("✅ DSP controls updated successfully")
+	output.Println("✅ DSP controls updated successfully")
 
 	if audioMode != "" {
-		fmt.Printf("   Audio Mode: %s\n", audioMode)
+		output.Printf("   Audio Mode: %s\n", audioMode)
 	}
 
 	if videoSyncDelay != 0 {
-		fmt.Printf("   Video Sync Delay: %d ms\n", videoSyncDelay)
+		output.Printf("   Video Sync Delay: %d ms\n", videoSyncDelay)
 	}
 
 	return nil
@@ -97,7 +99,7 @@ func setAudioMode(c *cli.Context) error {
 		return err
 	}
 
-	fmt.Printf("✅ Audio mode set to '%s'\n", audioMode)
+	output.Printf("✅ Audio mode set to '%s'\n", audioMode)
 
 	return nil
 }
@@ -121,7 +123,7 @@ func setVideoSyncDelay(c *cli.Context) error {
 		return err
 	}
 
-	fmt.Printf("✅ Video sync audio delay set to %d ms\n", delay)
+	output.Printf("✅ Video sync audio delay set to %d ms\n", delay)
 
 	return nil
 }
@@ -143,10 +145,12 @@ func getAudioToneControls(c *cli.Context) error {
 		return err
 	}
 
-	fmt.Println("Advanced Tone Controls:")
-	fmt.Printf("  Bass: %d (range: %d to %d, step: %d)\n",
+	emitResult(c, toneControls)
+
+	output.Println("Advanced Tone Controls:")
+	output.Printf("  Bass: %d (range: %d to %d, step: %d)\n",
 		toneControls.Bass.Value, toneControls.Bass.MinValue, toneControls.Bass.MaxValue, toneControls.Bass.Step)
-	fmt.Printf("  Treble: %d (range: %d to %d, step: %d)\n",
+	output.Printf("  Treble: %d (range: %d to %d, step: %d)\n",
 		toneControls.Treble.Value, toneControls.Treble.MinValue, toneControls.Treble.MaxValue, toneControls.Treble.Step)
 
 	return nil
@@ -198,14 +202,14 @@ func setAudioToneControls(c *cli.Context) error {
 		return err
 	}
 
-	fmt.Println("✅ Advanced tone controls updated successfully")
+	output.Println("✅ Advanced tone controls updated successfully")
 
 	if bass != nil {
-		fmt.Printf("   Bass: %d\n", *bass)
+		output.Printf("   Bass: %d\n", *bass)
 	}
 
 	if treble != nil {
-		fmt.Printf("   Treble: %d\n", *treble)
+		output.Printf("   Treble: %d\n", *treble)
 	}
 
 	return nil
@@ -230,7 +234,7 @@ func setAdvancedBass(c *cli.Context) error {
 		return err
 	}
 
-	fmt.Printf("✅ Advanced bass set to %d\n", level)
+	output.Printf("✅ Advanced bass set to %d\n", level)
 
 	return nil
 }
@@ -254,7 +258,7 @@ func setAdvancedTreble(c *cli.Context) error {
 		return err
 	}
 
-	fmt.Printf("✅ Advanced treble set to %d\n", level)
+	output.Printf("✅ Advanced treble set to %d\n", level)
 
 	return nil
 }
@@ -276,13 +280,15 @@ func getAudioLevelControls(c *cli.Context) error {
 		return err
 	}
 
-	fmt.Println("Speaker Level Controls:")
-	fmt.Printf("  Front-Center Speaker: %d (range: %d to %d, step: %d)\n",
+	emitResult(c, levelControls)
+
+	output.Println("Speaker Level Controls:")
+	output.Printf("  Front-Center Speaker: %d (range: %d to %d, step: %d)\n",
 		levelControls.FrontCenterSpeakerLevel.Value,
 		levelControls.FrontCenterSpeakerLevel.MinValue,
 		levelControls.FrontCenterSpeakerLevel.MaxValue,
 		levelControls.FrontCenterSpeakerLevel.Step)
-	fmt.Printf("  Rear-Surround Speakers: %d (range: %d to %d, step: %d)\n",
+	output.Printf("  Rear-Surround Speakers: %d (range: %d to %d, step: %d)\n",
 		levelControls.RearSurroundSpeakersLevel.Value,
 		levelControls.RearSurroundSpeakersLevel.MinValue,
 		levelControls.RearSurroundSpeakersLevel.MaxValue,
@@ -337,14 +343,14 @@ func setAudioLevelControls(c *cli.Context) error {
 		return err
 	}
 
-	fmt.Println("✅ Speaker level controls updated successfully")
+	output.Println("✅ Speaker level controls updated successfully")
 
 	if frontCenter != nil {
-		fmt.Printf("   Front-Center Speaker: %d\n", *frontCenter)
+		output.Printf("   Front-Center Speaker: %d\n", *frontCenter)
 	}
 
 	if rearSurround != nil {
-		fmt.Printf("   Rear-Surround Speakers: %d\n", *rearSurround)
+		output.Printf("   Rear-Surround Speakers: %d\n", *rearSurround)
 	}
 
 	return nil
@@ -369,7 +375,7 @@ func setFrontCenterLevel(c *cli.Context) error {
 		return err
 	}
 
-	fmt.Printf("✅ Front-center speaker level set to %d\n", level)
+	output.Printf("✅ Front-center speaker level set to %d\n", level)
 
 	return nil
 }
@@ -393,7 +399,7 @@ func setRearSurroundLevel(c *cli.Context) error {
 		return err
 	}
 
-	fmt.Printf("✅ Rear-surround speakers level set to %d\n", level)
+	output.Printf("✅ Rear-surround speakers level set to %d\n", level)
 
 	return nil
 }
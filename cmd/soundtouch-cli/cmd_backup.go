@@ -0,0 +1,256 @@
+// Package main provides the soundtouch-cli backup and restore commands.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/gesellix/bose-soundtouch/pkg/client"
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/urfave/cli/v2"
+)
+
+// backupPath replaces the {name} and {id} placeholders, so a group backup writes one file per device
+func backupPath(pattern string, backup *models.DeviceBackup) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>| `, r) {
+			return '-'
+		}
+
+		return r
+	}, backup.Device.Name)
+
+	return strings.NewReplacer("{name}", name, "{id}", backup.Device.DeviceID).Replace(pattern)
+}
+
+// sectionsFlag reads a comma separated list of backup sections
+func sectionsFlag(c *cli.Context, flag string) ([]string, error) {
+	var sections []string
+
+	for _, value := range c.StringSlice(flag) {
+		for _, section := range strings.Split(value, ",") {
+			if section = strings.ToLower(strings.TrimSpace(section)); section != "" {
+				sections = append(sections, section)
+			}
+		}
+	}
+
+	if err := models.ValidateBackupSections(sections); err != nil {
+		return nil, &usageError{err}
+	}
+
+	return sections, nil
+}
+
+// backupDevice writes the configuration of the device to a backup file
+func backupDevice(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return &usageError{fmt.Errorf("usage: backup <file>, use - for stdout")}
+	}
+
+	sections, err := sectionsFlag(c, "sections")
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	// Writing to stdout keeps it free of anything but the backup
+	toStdout := c.Args().First() == "-"
+
+	clientConfig := GetClientConfig(c)
+	if !toStdout {
+		PrintDeviceHeader("Backing up configuration", clientConfig.Host, clientConfig.Port)
+	}
+
+	soundTouchClient, err := CreateSoundTouchClient(clientConfig)
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to create client: %v", err))
+		return err
+	}
+
+	backup, err := soundTouchClient.Backup(sections)
+	if backup == nil {
+		PrintError(fmt.Sprintf("Failed to back up device: %v", err))
+		return err
+	}
+
+	if err != nil {
+		if toStdout {
+			_, _ = fmt.Fprintf(os.Stderr, "⚠️  Backup is incomplete: %v\n", err)
+		} else {
+			PrintWarning(fmt.Sprintf("Backup is incomplete: %v", err))
+		}
+	}
+
+	data, err := json.MarshalIndent(backup, "", "  ")
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to encode backup: %v", err))
+		return err
+	}
+
+	if toStdout {
+		// The backup itself is the result; structured output wraps it instead of printing it twice
+		if structuredOutput() {
+			emitResult(c, backup)
+		} else {
+			output.Println(string(data))
+		}
+
+		return nil
+	}
+
+	path := backupPath(c.Args().First(), backup)
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		PrintError(fmt.Sprintf("Failed to write backup: %v", err))
+		return err
+	}
+
+	emitResult(c, map[string]any{"file": path, "sections": backup.Sections})
+
+	output.Printf("Sections: %s\n", strings.Join(backup.Sections, ", "))
+	output.Printf("Presets: %d, accounts: %d\n", len(backup.Presets), len(backup.Accounts))
+	PrintSuccess(fmt.Sprintf("Backup of %s written to %s", backup.Device.Name, path))
+
+	return nil
+}
+
+// readBackup loads a backup file, or stdin for -
+func readBackup(path string) (*models.DeviceBackup, error) {
+	var (
+		data []byte
+		err  error
+	)
+
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	return models.ParseDeviceBackup(data)
+}
+
+// restoreDevice applies a backup file to the device, or shows the differences with --dry-run
+func restoreDevice(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return &usageError{fmt.Errorf("usage: restore <file>, use - for stdin")}
+	}
+
+	only, err := sectionsFlag(c, "only")
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	skip, err := sectionsFlag(c, "skip")
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	backup, err := readBackup(c.Args().First())
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	sections := restoreSections(backup, only, skip)
+
+	clientConfig := GetClientConfig(c)
+	PrintDeviceHeader("Restoring configuration", clientConfig.Host, clientConfig.Port)
+
+	soundTouchClient, err := CreateSoundTouchClient(clientConfig)
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to create client: %v", err))
+		return err
+	}
+
+	output.Printf("Backup of %s (%s) from %s\n", backup.Device.Name, backup.Device.Type, backup.CreatedAt.Local().Format("2006-01-02 15:04"))
+
+	if len(sections) == 0 {
+		PrintWarning("Nothing to restore: none of the selected sections is part of the backup")
+		return nil
+	}
+
+	plan, err := soundTouchClient.PlanRestore(backup, sections)
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to compare backup: %v", err))
+		return err
+	}
+
+	emitResult(c, plan)
+	printRestorePlan(plan)
+
+	pending := len(plan.Pending())
+
+	switch {
+	case c.Bool("dry-run"):
+		output.Printf("Dry run: %d change(s) would be applied\n", pending)
+		return nil
+	case pending == 0:
+		PrintSuccess("Device already matches the backup")
+		return nil
+	}
+
+	if err := plan.Apply(); err != nil {
+		PrintError(fmt.Sprintf("Restore failed partially: %v", err))
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Restored %d setting(s) to %s", pending, plan.Device.Name))
+
+	return nil
+}
+
+// restoreSections returns the sections of the backup selected by --only and --skip
+func restoreSections(backup *models.DeviceBackup, only, skip []string) []string {
+	var sections []string
+
+	for _, section := range models.BackupSections {
+		if !backup.HasSection(section) || len(only) > 0 && !slices.Contains(only, section) || slices.Contains(skip, section) {
+			continue
+		}
+
+		sections = append(sections, section)
+	}
+
+	return sections
+}
+
+// printRestorePlan lists the changes grouped by section
+func printRestorePlan(plan *client.RestorePlan) {
+	if len(plan.Changes) == 0 {
+		output.Printf("No differences (%d setting(s) unchanged)\n", len(plan.Unchanged))
+		return
+	}
+
+	output.Printf("Changes for %s (%s):\n", plan.Device.Name, plan.Device.Type)
+
+	section := ""
+
+	for _, change := range plan.Changes {
+		if change.Section != section {
+			section = change.Section
+			output.Printf("  [%s]\n", section)
+		}
+
+		marker := "~"
+		if change.Skipped != "" {
+			marker = "!"
+		}
+
+		output.Printf("    %s %s\n", marker, change.String())
+	}
+
+	if len(plan.Unchanged) > 0 {
+		output.Printf("  %d setting(s) unchanged\n", len(plan.Unchanged))
+	}
+}
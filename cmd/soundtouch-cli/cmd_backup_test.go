@@ -0,0 +1,43 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+func TestBackupPath(t *testing.T) {
+	backup := &models.DeviceBackup{Device: models.BackupDevice{DeviceID: "AABBCC", Name: "Living Room/TV"}}
+
+	if got := backupPath("backups/{name}-{id}.json", backup); got != "backups/Living-Room-TV-AABBCC.json" {
+		t.Errorf("Unexpected path %q", got)
+	}
+
+	if got := backupPath("kitchen.json", backup); got != "kitchen.json" {
+		t.Errorf("Expected a path without placeholders to stay unchanged, got %q", got)
+	}
+}
+
+func TestRestoreSections(t *testing.T) {
+	backup := &models.DeviceBackup{Sections: []string{models.BackupSectionZone, models.BackupSectionName, models.BackupSectionPresets, models.BackupSectionBass}}
+
+	tests := []struct {
+		name     string
+		only     []string
+		skip     []string
+		expected []string
+	}{
+		{"all in restore order", nil, nil, []string{"name", "presets", "bass", "zone"}},
+		{"only", []string{"bass", "clock"}, nil, []string{"bass"}},
+		{"skip", nil, []string{"zone", "name"}, []string{"presets", "bass"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := restoreSections(backup, tt.only, tt.skip); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("restoreSections() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -24,20 +24,22 @@ func getBalance(c *cli.Context) error {
 		return err
 	}
 
-	fmt.Printf("Current balance level: %d\n", balance.ActualBalance)
+	emitResult(c, balance)
+
+	output.Printf("Current balance level: %d\n", balance.ActualBalance)
 
 	if balance.TargetBalance != balance.ActualBalance {
-		fmt.Printf("Target balance level: %d\n", balance.TargetBalance)
+		output.Printf("Target balance level: %d\n", balance.TargetBalance)
 	}
 
 	// Display balance direction
 	switch {
 	case balance.ActualBalance > 0:
-		fmt.Printf("Balance direction: Right (+%d)\n", balance.ActualBalance)
+		output.Printf("Balance direction: Right (+%d)\n", balance.ActualBalance)
 	case balance.ActualBalance < 0:
-		fmt.Printf("Balance direction: Left (%d)\n", balance.ActualBalance)
+		output.Printf("Balance direction: Left (%d)\n", balance.ActualBalance)
 	default:
-		fmt.Println("Balance direction: Center (0)")
+		output.Println("Balance direction: Center (0)")
 	}
 
 	return nil
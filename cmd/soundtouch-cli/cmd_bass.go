@@ -23,10 +23,12 @@ func getBass(c *cli.Context) error {
 		return err
 	}
 
-	fmt.Printf("Current bass level: %d\n", bass.ActualBass)
+	emitResult(c, bass)
+
+	output.Printf("Current bass level: %d\n", bass.ActualBass)
 
 	if bass.TargetBass != bass.ActualBass {
-		fmt.Printf("Target bass level: %d\n", bass.TargetBass)
+		output.Printf("Target bass level: %d\n", bass.TargetBass)
 	}
 
 	return nil
@@ -136,12 +138,14 @@ func getBassCapabilities(c *cli.Context) error {
 		return err
 	}
 
-	fmt.Println("Bass Capabilities:")
-	fmt.Printf("  Available: %t\n", capabilities.BassAvailable)
+	emitResult(c, capabilities)
+
+	output.Println("Bass Capabilities:")
+	output.Printf("  Available: %t\n", capabilities.BassAvailable)
 
 	if capabilities.BassAvailable {
-		fmt.Printf("  Range: %d to %d\n", capabilities.BassMin, capabilities.BassMax)
-		fmt.Printf("  Default: %d\n", capabilities.BassDefault)
+		output.Printf("  Range: %d to %d\n", capabilities.BassMin, capabilities.BassMax)
+		output.Printf("  Default: %d\n", capabilities.BassDefault)
 	}
 
 	return nil
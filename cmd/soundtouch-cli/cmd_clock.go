@@ -2,10 +2,16 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/gesellix/bose-soundtouch/pkg/config"
+	"github.com/gesellix/bose-soundtouch/pkg/discovery"
 	"github.com/gesellix/bose-soundtouch/pkg/models"
 	"github.com/urfave/cli/v2"
 )
@@ -27,51 +33,53 @@ func getClockTime(c *cli.Context) error {
 		return err
 	}
 
-	fmt.Println("Clock Time Information:")
+	emitResult(c, clockTime)
+
+	output.Println("Clock Time Information:")
 
 	if timeObj, err := clockTime.GetTime(); err == nil {
-		fmt.Printf("  Current time: %s\n", timeObj.Format("2006-01-02 15:04:05"))
-		fmt.Printf("  Local time: %02d:%02d:%02d\n", timeObj.Hour(), timeObj.Minute(), timeObj.Second())
+		output.Printf("  Current time: %s\n", timeObj.Format("2006-01-02 15:04:05"))
+		output.Printf("  Local time: %02d:%02d:%02d\n", timeObj.Hour(), timeObj.Minute(), timeObj.Second())
 	} else {
-		fmt.Printf("  Parse error: %v\n", err)
+		output.Printf("  Parse error: %v\n", err)
 
 		if clockTime.Value != "" {
-			fmt.Printf("  Raw value: %s\n", clockTime.Value)
+			output.Printf("  Raw value: %s\n", clockTime.Value)
 		}
 	}
 
 	if clockTime.GetLocalTime() != nil {
 		lt := clockTime.GetLocalTime()
 
-		fmt.Printf("  Local time details:\n")
-		fmt.Printf("    Date: %04d-%02d-%02d (day %d)\n", lt.Year, lt.Month+1, lt.DayOfMonth, lt.DayOfWeek)
-		fmt.Printf("    Time: %02d:%02d:%02d\n", lt.Hour, lt.Minute, lt.Second)
+		output.Printf("  Local time details:\n")
+		output.Printf("    Date: %04d-%02d-%02d (day %d)\n", lt.Year, lt.Month+1, lt.DayOfMonth, lt.DayOfWeek)
+		output.Printf("    Time: %02d:%02d:%02d\n", lt.Hour, lt.Minute, lt.Second)
 	}
 
 	if clockTime.GetUTC() > 0 {
 		utcTime := time.Unix(clockTime.GetUTC(), 0)
-		fmt.Printf("  UTC timestamp: %d (%s)\n", clockTime.GetUTC(), utcTime.Format("2006-01-02 15:04:05 MST"))
+		output.Printf("  UTC timestamp: %d (%s)\n", clockTime.GetUTC(), utcTime.Format("2006-01-02 15:04:05 MST"))
 	}
 
 	if clockTime.GetTimeFormat() != "" {
-		fmt.Printf("  Time format: %s\n", clockTime.GetTimeFormat())
+		output.Printf("  Time format: %s\n", clockTime.GetTimeFormat())
 	}
 
 	if clockTime.GetBrightness() > 0 {
-		fmt.Printf("  Brightness: %d\n", clockTime.GetBrightness())
+		output.Printf("  Brightness: %d\n", clockTime.GetBrightness())
 	}
 
 	if clockTime.GetUTCSyncTime() > 0 {
 		syncTime := time.Unix(clockTime.GetUTCSyncTime(), 0)
-		fmt.Printf("  Last sync: %s\n", syncTime.Format("2006-01-02 15:04:05 MST"))
+		output.Printf("  Last sync: %s\n", syncTime.Format("2006-01-02 15:04:05 MST"))
 	}
 
 	if clockTime.GetClockError() != 0 {
-		fmt.Printf("  Clock error: %d\n", clockTime.GetClockError())
+		output.Printf("  Clock error: %d\n", clockTime.GetClockError())
 	}
 
 	if clockTime.GetZone() != "" {
-		fmt.Printf("  Time zone: %s\n", clockTime.GetZone())
+		output.Printf("  Time zone: %s\n", clockTime.GetZone())
 	}
 
 	return nil
@@ -174,17 +182,19 @@ func getClockDisplay(c *cli.Context) error {
 		return err
 	}
 
-	fmt.Println("Clock Display Settings:")
-	fmt.Printf("  Enabled: %t\n", clockDisplay.IsEnabled())
-	fmt.Printf("  Brightness: %d (%s)\n", clockDisplay.GetBrightness(), clockDisplay.GetBrightnessLevel())
-	fmt.Printf("  Format: %s (%s)\n", clockDisplay.GetFormat(), clockDisplay.GetFormatDescription())
+	emitResult(c, clockDisplay)
+
+	output.Println("Clock Display Settings:")
+	output.Printf("  Enabled: %t\n", clockDisplay.IsEnabled())
+	output.Printf("  Brightness: %d (%s)\n", clockDisplay.GetBrightness(), clockDisplay.GetBrightnessLevel())
+	output.Printf("  Format: %s (%s)\n", clockDisplay.GetFormat(), clockDisplay.GetFormatDescription())
 
 	if clockDisplay.IsAutoDimEnabled() {
-		fmt.Printf("  Auto-dim: enabled\n")
+		output.Printf("  Auto-dim: enabled\n")
 	}
 
 	if clockDisplay.GetTimeZone() != "" {
-		fmt.Printf("  Time zone: %s\n", clockDisplay.GetTimeZone())
+		output.Printf("  Time zone: %s\n", clockDisplay.GetTimeZone())
 	}
 
 	return nil
@@ -312,6 +322,209 @@ func setClockDisplayFormat(c *cli.Context) error {
 	return nil
 }
 
+// requireHostUnlessAll requires a host unless the command runs on all discovered speakers
+func requireHostUnlessAll(c *cli.Context) error {
+	if c.Bool("all") {
+		return nil
+	}
+
+	return RequireHost(c)
+}
+
+// syncClock checks the clock of the device, or of all discovered speakers with --all,
+// and sets it to the current time in the given zone if it drifted more than the threshold
+// or applies a wrong UTC offset, e.g. after a DST transition
+func syncClock(c *cli.Context) error {
+	loc, err := models.ParseClockZone(c.String("zone"))
+	if err != nil {
+		return &usageError{err}
+	}
+
+	if c.Bool("all") {
+		return syncAllClocks(c, loc)
+	}
+
+	clientConfig := GetClientConfig(c)
+	PrintDeviceHeader("Syncing clock", clientConfig.Host, clientConfig.Port)
+
+	check, err := syncDeviceClock(c, clientConfig, loc)
+	if check != nil {
+		emitResult(c, check)
+		printClockCheck(c, clientConfig.Host, check)
+	}
+
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to sync clock: %v", err))
+		return err
+	}
+
+	return nil
+}
+
+// syncAllClocks discovers all speakers and syncs their clocks.
+// Speakers with a time zone stored in the configured soundtouch-service are synced in that zone, all others in loc.
+func syncAllClocks(c *cli.Context, loc *time.Location) error {
+	zones, err := loadServiceClockZones(c)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	cfg, err := config.LoadFromEnv()
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+
+	updateConfigFromCLI(c, cfg)
+
+	discoveryService := discovery.NewUnifiedDiscoveryService(cfg)
+	if registry := openDeviceRegistry(); registry != nil {
+		discoveryService.SetRegistry(registry)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.DiscoveryTimeout+5*time.Second)
+	defer cancel()
+
+	output.Println("Discovering speakers...")
+
+	devices, err := discoveryService.DiscoverDevices(ctx)
+	if err != nil {
+		PrintError(fmt.Sprintf("Discovery failed: %v", err))
+		return err
+	}
+
+	if len(devices) == 0 {
+		err := fmt.Errorf("no speakers found")
+		PrintError(err.Error())
+
+		return err
+	}
+
+	checks := make([]models.ClockCheck, 0, len(devices))
+	failed := 0
+
+	for _, device := range devices {
+		clientConfig := &ClientConfig{Host: device.Host, Port: device.Port, Timeout: c.Duration("timeout")}
+
+		zone := speakerClockZone(clientConfig, zones, loc)
+
+		check, err := syncDeviceClock(c, clientConfig, zone)
+		if check == nil {
+			check = &models.ClockCheck{Zone: zone.String(), CheckedAt: time.Now().UTC()}
+		}
+
+		if err != nil {
+			check.Error = err.Error()
+			failed++
+		}
+
+		check.Speaker = device.Name
+		checks = append(checks, *check)
+
+		printClockCheck(c, device.Name, check)
+	}
+
+	emitResult(c, checks)
+
+	if failed > 0 {
+		err := fmt.Errorf("failed to sync %d of %d clocks", failed, len(checks))
+		PrintError(err.Error())
+
+		return err
+	}
+
+	return nil
+}
+
+// syncDeviceClock checks the clock of one device and corrects it unless --dry-run is given
+func syncDeviceClock(c *cli.Context, clientConfig *ClientConfig, loc *time.Location) (*models.ClockCheck, error) {
+	client, err := CreateSoundTouchClient(clientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	if c.Bool("dry-run") {
+		return client.CheckClock(loc)
+	}
+
+	return client.SyncClock(loc, c.Duration("threshold"))
+}
+
+// loadServiceClockZones returns the time zones per device ID stored in the configured soundtouch-service,
+// or nil if no service is configured
+func loadServiceClockZones(c *cli.Context) (map[string]string, error) {
+	service := configuredService(c)
+	if service == "" {
+		return nil, nil
+	}
+
+	serviceURL, err := resolveServiceURL(service)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpClient.Get(serviceURL + "/api/clock/zones")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load clock zones from soundtouch-service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to load clock zones from soundtouch-service: unexpected status code: %d", resp.StatusCode)
+	}
+
+	zones := make(map[string]string)
+	if err := json.NewDecoder(resp.Body).Decode(&zones); err != nil {
+		return nil, fmt.Errorf("failed to decode clock zones: %w", err)
+	}
+
+	return zones, nil
+}
+
+// speakerClockZone returns the time zone stored for the device in zones, or loc if there is none
+func speakerClockZone(clientConfig *ClientConfig, zones map[string]string, loc *time.Location) *time.Location {
+	if len(zones) == 0 {
+		return loc
+	}
+
+	client, err := CreateSoundTouchClient(clientConfig)
+	if err != nil {
+		return loc
+	}
+
+	// A speaker that does not answer fails the sync itself
+	info, err := client.GetDeviceInfo()
+	if err != nil {
+		return loc
+	}
+
+	name := zones[strings.ToUpper(info.DeviceID)]
+	if name == "" {
+		return loc
+	}
+
+	zone, err := models.ParseClockZone(name)
+	if err != nil {
+		PrintWarning(fmt.Sprintf("%v, using %s for %s", err, loc, info.Name))
+		return loc
+	}
+
+	return zone
+}
+
+func printClockCheck(c *cli.Context, name string, check *models.ClockCheck) {
+	switch {
+	case check.Error != "":
+		PrintWarning(fmt.Sprintf("%s: %s", name, check.String()))
+	case check.Corrected:
+		PrintSuccess(fmt.Sprintf("%s: %s", name, check.String()))
+	case c.Bool("dry-run") && check.NeedsCorrection(c.Duration("threshold")):
+		output.Printf("  %s: %s, would be corrected\n", name, check.String())
+	default:
+		output.Printf("  %s: %s\n", name, check.String())
+	}
+}
+
 // parseTimeString parses a time string in HH:MM format
 func parseTimeString(timeStr string) (int, int, error) {
 	if len(timeStr) != 5 || timeStr[2] != ':' {
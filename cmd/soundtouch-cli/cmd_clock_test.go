@@ -0,0 +1,76 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+func TestSyncAllClocks_ServiceZones(t *testing.T) {
+	service := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/clock/zones" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"AABBCCDDEEFF": "America/New_York"}`))
+	}))
+	defer service.Close()
+
+	speaker := func(deviceID string) *ClientConfig {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`<info deviceID="` + deviceID + `"><name>Speaker</name></info>`))
+		}))
+		t.Cleanup(server.Close)
+
+		host, port := parseHostPort(server.Listener.Addr().String(), 8090)
+
+		return &ClientConfig{Host: host, Port: port, Timeout: time.Second}
+	}
+
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+
+	var zones []string
+
+	app := &cli.App{
+		Flags: CommonFlags,
+		Action: func(c *cli.Context) error {
+			stored, err := loadServiceClockZones(c)
+			if err != nil {
+				return err
+			}
+
+			// Device IDs are matched case-insensitively; speakers without a stored zone use the given one
+			for _, deviceID := range []string{"aabbccddeeff", "112233445566"} {
+				zones = append(zones, speakerClockZone(speaker(deviceID), stored, berlin).String())
+			}
+
+			return nil
+		},
+	}
+
+	if err := app.Run([]string{"soundtouch-cli", "--service", service.URL + "/"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(zones) != 2 || zones[0] != "America/New_York" || zones[1] != "Europe/Berlin" {
+		t.Errorf("Unexpected zones: %v", zones)
+	}
+
+	// Without a service the zones are not looked up
+	app.Action = func(c *cli.Context) error {
+		stored, err := loadServiceClockZones(c)
+		if stored != nil || err != nil {
+			t.Errorf("Expected no zones without a service, got %v, %v", stored, err)
+		}
+
+		return nil
+	}
+
+	if err := app.Run([]string{"soundtouch-cli", "--config", t.TempDir() + "/profiles.yaml"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,372 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gesellix/bose-soundtouch/pkg/config"
+	"github.com/urfave/cli/v2"
+)
+
+// loadProfiles reads the config file given by --config or the default one
+func loadProfiles(c *cli.Context) (*config.Profiles, error) {
+	path := c.String("config")
+	if path == "" {
+		path = config.DefaultProfilesPath()
+	}
+
+	return config.LoadProfiles(path)
+}
+
+// openProfiles reads the config file like loadProfiles. It returns nil if the file cannot be read.
+func openProfiles(c *cli.Context) *config.Profiles {
+	profiles, err := loadProfiles(c)
+	if err != nil {
+		PrintWarning(err.Error())
+		return nil
+	}
+
+	return profiles
+}
+
+// applyGroupSelector lets device commands, the ones guarded by a Before hook, run on every device of --group
+func applyGroupSelector(commands []*cli.Command) {
+	for _, cmd := range commands {
+		if cmd.Action != nil && cmd.Before != nil {
+			cmd.Action = forEachGroupDevice(cmd.Action)
+		}
+
+		applyGroupSelector(cmd.Subcommands)
+	}
+}
+
+// forEachGroupDevice runs action once per device of --group, or once without a group
+func forEachGroupDevice(action cli.ActionFunc) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		group := c.String("group")
+		if group == "" {
+			return action(c)
+		}
+
+		profiles, err := loadProfiles(c)
+		if err != nil {
+			PrintError(err.Error())
+			return err
+		}
+
+		members, ok := profiles.Group(group)
+		if !ok {
+			err := fmt.Errorf("group %q is not configured in %s", group, profiles.Path())
+			PrintError(err.Error())
+
+			return err
+		}
+
+		var (
+			failed []string
+			errs   []error
+		)
+
+		for i, member := range members {
+			if i > 0 {
+				output.Println()
+			}
+
+			output.Printf("=== %s ===\n", member)
+
+			if err := c.Set("device", member); err != nil {
+				return fmt.Errorf("failed to select device %s: %w", member, err)
+			}
+
+			if err := action(c); err != nil {
+				PrintError(fmt.Sprintf("%s: %v", member, err))
+				failed = append(failed, member)
+				errs = append(errs, err)
+			}
+		}
+
+		if len(failed) > 0 {
+			return &groupError{
+				message: fmt.Sprintf("command failed on %d of %d devices of group %s: %s", len(failed), len(members), group, strings.Join(failed, ", ")),
+				errs:    errs,
+			}
+		}
+
+		return nil
+	}
+}
+
+// groupError reports the devices of a group a command failed on; it unwraps to their errors
+type groupError struct {
+	message string
+	errs    []error
+}
+
+func (e *groupError) Error() string {
+	return e.message
+}
+
+func (e *groupError) Unwrap() []error {
+	return e.errs
+}
+
+// showConfig prints the config file with its devices, groups and defaults
+func showConfig(c *cli.Context) error {
+	profiles, err := loadProfiles(c)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	emitResult(c, profiles)
+
+	output.Printf("Config file: %s\n", profiles.Path())
+
+	if profiles.DefaultDevice != "" {
+		output.Printf("Default device: %s\n", profiles.DefaultDevice)
+	}
+
+	if profiles.Service != "" {
+		output.Printf("Service: %s\n", profiles.Service)
+	}
+
+	if len(profiles.Devices) == 0 {
+		output.Println("No devices configured")
+		output.Println("Add one with: soundtouch-cli config device set --name <name> --host <host>")
+
+		return nil
+	}
+
+	output.Printf("\nDevices (%d):\n", len(profiles.Devices))
+
+	for _, name := range profiles.DeviceNames() {
+		device := profiles.Devices[name]
+
+		address := device.Host
+		if device.Port > 0 {
+			address = fmt.Sprintf("%s (port %d)", device.Host, device.Port)
+		}
+
+		output.Printf("  %s: %s", name, address)
+
+		if len(device.Aliases) > 0 {
+			output.Printf(", aliases: %s", strings.Join(device.Aliases, ", "))
+		}
+
+		if device.Timeout != "" {
+			output.Printf(", timeout: %s", device.Timeout)
+		}
+
+		output.Println()
+	}
+
+	if len(profiles.Groups) > 0 {
+		output.Printf("\nGroups (%d):\n", len(profiles.Groups))
+
+		for _, name := range profiles.GroupNames() {
+			output.Printf("  %s: %s\n", name, strings.Join(profiles.Groups[name], ", "))
+		}
+	}
+
+	return nil
+}
+
+// setConfigDevice adds a named device to the config file or updates it
+func setConfigDevice(c *cli.Context) error {
+	name := c.String("name")
+
+	profiles, err := loadProfiles(c)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	device := &config.DeviceProfile{}
+	if existing, ok := profiles.Devices[name]; ok {
+		copied := *existing
+		device = &copied
+	}
+
+	if c.IsSet("host") {
+		device.Host = c.String("host")
+	}
+
+	if c.IsSet("port") {
+		device.Port = c.Int("port")
+	}
+
+	if c.IsSet("alias") {
+		device.Aliases = c.StringSlice("alias")
+	}
+
+	if c.IsSet("timeout") {
+		device.Timeout = c.Duration("timeout").String()
+		if c.Duration("timeout") == 0 {
+			device.Timeout = ""
+		}
+	}
+
+	profiles.SetDevice(name, device)
+
+	if err := profiles.Save(); err != nil {
+		PrintError(fmt.Sprintf("Failed to save config: %v", err))
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Saved device %s (%s)", name, device.Host))
+
+	return nil
+}
+
+// removeConfigDevice removes a named device from the config file
+func removeConfigDevice(c *cli.Context) error {
+	name := c.String("name")
+
+	profiles, err := loadProfiles(c)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	if !profiles.RemoveDevice(name) {
+		err := fmt.Errorf("device %q is not configured", name)
+		PrintError(err.Error())
+
+		return err
+	}
+
+	if err := profiles.Save(); err != nil {
+		PrintError(fmt.Sprintf("Failed to save config: %v", err))
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Removed device %s", name))
+
+	return nil
+}
+
+// setConfigGroup adds a group of devices to the config file or replaces it
+func setConfigGroup(c *cli.Context) error {
+	name := c.String("name")
+
+	profiles, err := loadProfiles(c)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	profiles.SetGroup(name, c.StringSlice("devices"))
+
+	if err := profiles.Save(); err != nil {
+		PrintError(fmt.Sprintf("Failed to save config: %v", err))
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Saved group %s: %s", name, strings.Join(c.StringSlice("devices"), ", ")))
+
+	return nil
+}
+
+// removeConfigGroup removes a group from the config file
+func removeConfigGroup(c *cli.Context) error {
+	name := c.String("name")
+
+	profiles, err := loadProfiles(c)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	if !profiles.RemoveGroup(name) {
+		err := fmt.Errorf("group %q is not configured", name)
+		PrintError(err.Error())
+
+		return err
+	}
+
+	if err := profiles.Save(); err != nil {
+		PrintError(fmt.Sprintf("Failed to save config: %v", err))
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Removed group %s", name))
+
+	return nil
+}
+
+// setConfigDefault sets or clears the device used when neither --host nor --device is given
+func setConfigDefault(c *cli.Context) error {
+	profiles, err := loadProfiles(c)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	name := c.Args().First()
+	if name == "" && !c.Bool("clear") {
+		if profiles.DefaultDevice == "" {
+			output.Println("No default device configured")
+		} else {
+			output.Printf("Default device: %s\n", profiles.DefaultDevice)
+		}
+
+		return nil
+	}
+
+	profiles.DefaultDevice = name
+
+	if err := profiles.Save(); err != nil {
+		PrintError(fmt.Sprintf("Failed to save config: %v", err))
+		return err
+	}
+
+	if name == "" {
+		PrintSuccess("Cleared default device")
+	} else {
+		PrintSuccess(fmt.Sprintf("Default device is now %s", name))
+	}
+
+	return nil
+}
+
+// setConfigService sets or clears the soundtouch-service requests are sent through
+func setConfigService(c *cli.Context) error {
+	profiles, err := loadProfiles(c)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	service := c.Args().First()
+	if service == "" && !c.Bool("clear") {
+		if profiles.Service == "" {
+			output.Println("No service configured")
+		} else {
+			output.Printf("Service: %s\n", profiles.Service)
+		}
+
+		return nil
+	}
+
+	if service != "" && service != "auto" && !strings.HasPrefix(service, "http://") && !strings.HasPrefix(service, "https://") {
+		err := fmt.Errorf("service must be an http(s) URL or \"auto\", got %q", service)
+		PrintError(err.Error())
+
+		return err
+	}
+
+	profiles.Service = service
+
+	if err := profiles.Save(); err != nil {
+		PrintError(fmt.Sprintf("Failed to save config: %v", err))
+		return err
+	}
+
+	if service == "" {
+		PrintSuccess("Cleared service")
+	} else {
+		PrintSuccess(fmt.Sprintf("Service is now %s", service))
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/config"
+	"github.com/urfave/cli/v2"
+)
+
+// runWithProfiles runs a device command against a temporary config file and returns the client configs it saw
+func runWithProfiles(t *testing.T, args ...string) ([]*ClientConfig, error) {
+	t.Helper()
+
+	dir := t.TempDir()
+	t.Setenv("DEVICE_REGISTRY", filepath.Join(dir, "devices.json"))
+	t.Setenv("SOUNDTOUCH_HOST", "")
+	t.Setenv("SOUNDTOUCH_DEVICE", "")
+	t.Setenv("SOUNDTOUCH_GROUP", "")
+	t.Setenv("SOUNDTOUCH_SERVICE", "")
+
+	profiles, err := config.LoadProfiles(filepath.Join(dir, "config.json"))
+	if err != nil {
+		t.Fatalf("Failed to load profiles: %v", err)
+	}
+
+	profiles.SetDevice("Kitchen", &config.DeviceProfile{Host: "192.168.1.10", Aliases: []string{"k"}, Timeout: "3s"})
+	profiles.SetDevice("Office", &config.DeviceProfile{Host: "192.168.1.11:8091"})
+	profiles.SetGroup("downstairs", []string{"k", "Office"})
+	profiles.DefaultDevice = "Office"
+	profiles.Service = "http://nas:8000"
+
+	if err := profiles.Save(); err != nil {
+		t.Fatalf("Failed to save profiles: %v", err)
+	}
+
+	var seen []*ClientConfig
+
+	app := &cli.App{
+		Flags: CommonFlags,
+		Commands: []*cli.Command{
+			{
+				Name:   "probe",
+				Before: RequireHost,
+				Action: func(c *cli.Context) error {
+					seen = append(seen, GetClientConfig(c))
+					return nil
+				},
+			},
+		},
+	}
+	applyGroupSelector(app.Commands)
+
+	err = app.Run(append([]string{"soundtouch-cli", "--config", profiles.Path()}, append(args, "probe")...))
+
+	return seen, err
+}
+
+func TestGetClientConfig_Profiles(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            []string
+		expectedHost    string
+		expectedPort    int
+		expectedTimeout time.Duration
+		expectedService string
+	}{
+		{"default device", nil, "192.168.1.11", 8091, 10 * time.Second, "http://nas:8000"},
+		{"device alias", []string{"--device", "K"}, "192.168.1.10", 8090, 3 * time.Second, "http://nas:8000"},
+		{"explicit timeout", []string{"--device", "kitchen", "--timeout", "1s"}, "192.168.1.10", 8090, time.Second, "http://nas:8000"},
+		{"host wins over default", []string{"--host", "10.0.0.5"}, "10.0.0.5", 8090, 10 * time.Second, "http://nas:8000"},
+		{"unknown device is a host", []string{"--device", "10.0.0.6:8095"}, "10.0.0.6", 8095, 10 * time.Second, "http://nas:8000"},
+		{"explicit service", []string{"--service", "auto"}, "192.168.1.11", 8091, 10 * time.Second, "auto"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen, err := runWithProfiles(t, tt.args...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(seen) != 1 {
+				t.Fatalf("Expected one run, got %d", len(seen))
+			}
+
+			got := seen[0]
+			if got.Host != tt.expectedHost || got.Port != tt.expectedPort || got.Timeout != tt.expectedTimeout || got.Service != tt.expectedService {
+				t.Errorf("Got %+v, expected %s:%d timeout %v service %s", got, tt.expectedHost, tt.expectedPort, tt.expectedTimeout, tt.expectedService)
+			}
+		})
+	}
+}
+
+func TestGroupSelector(t *testing.T) {
+	seen, err := runWithProfiles(t, "--group", "Downstairs")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(seen) != 2 || seen[0].Host != "192.168.1.10" || seen[1].Host != "192.168.1.11" {
+		t.Fatalf("Expected a run per group member, got %+v", seen)
+	}
+
+	if _, err := runWithProfiles(t, "--group", "upstairs"); err == nil {
+		t.Error("Expected an error for an unknown group")
+	}
+}
@@ -0,0 +1,430 @@
+// Package main provides the soundtouch-cli diagnose command, a connectivity and health report for a speaker.
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/client"
+	"github.com/gesellix/bose-soundtouch/pkg/discovery"
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/urfave/cli/v2"
+)
+
+// Results of a diagnose check
+const (
+	checkPass = "pass"
+	checkWarn = "warn"
+	checkFail = "fail"
+)
+
+// Limits above which diagnose warns or fails
+const (
+	slowInfoLatency       = time.Second
+	clockDriftWarn        = time.Minute
+	clockDriftFail        = 10 * time.Minute
+	webSocketFirstMessage = 5 * time.Second
+)
+
+// diagnosis is the outcome of one check
+type diagnosis struct {
+	Check  string `json:"check"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+	Hint   string `json:"hint,omitempty"`
+}
+
+// diagnosePort is a TCP port of a speaker and what a closed port means
+type diagnosePort struct {
+	port   int
+	name   string
+	closed string
+	hint   string
+}
+
+// speakerPorts are checked besides the REST port
+var speakerPorts = []diagnosePort{
+	{8080, "WebSocket", checkFail, "Live events (events, shell, zone apply) need port 8080; power-cycle the speaker"},
+	{8200, "ZeroConf", checkWarn, "Spotify Connect discovery runs on port 8200; restart the speaker if it does not show up in Spotify"},
+	{22, "SSH", checkWarn, "SSH is only open with remote services enabled, which the migration to soundtouch-service needs (see docs/DEVICE-LOGGING.md)"},
+	{17000, "TAP console", checkWarn, "The TAP console is only open on older firmware; it can enable remote services with 'remote_services on'"},
+}
+
+// diagnoser runs the checks against one speaker
+type diagnoser struct {
+	host         string
+	port         int
+	service      string
+	probeTimeout time.Duration
+	client       *client.Client
+	results      []diagnosis
+}
+
+func (d *diagnoser) add(check, status, detail, hint string) {
+	d.results = append(d.results, diagnosis{Check: check, Status: status, Detail: detail, Hint: hint})
+}
+
+// diagnoseDevice checks ports, API, WebSocket, cloud redirection, clock and Wi-Fi of a speaker
+func diagnoseDevice(c *cli.Context) error {
+	clientConfig := GetClientConfig(c)
+	PrintDeviceHeader("Diagnosing", clientConfig.Host, clientConfig.Port)
+
+	// The speaker itself is diagnosed, not the way through the service proxy
+	direct := *clientConfig
+	direct.Service = ""
+
+	soundTouchClient, err := CreateSoundTouchClient(&direct)
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to create client: %v", err))
+		return err
+	}
+
+	d := &diagnoser{
+		host:         clientConfig.Host,
+		port:         clientConfig.Port,
+		service:      clientConfig.Service,
+		probeTimeout: c.Duration("probe-timeout"),
+		client:       soundTouchClient,
+	}
+
+	d.checkDNS()
+	d.checkPorts()
+
+	if info := d.checkInfo(); info != nil {
+		d.checkWebSocket()
+		d.checkServiceAvailability()
+		d.checkCloud(info.MargeURL)
+		d.checkClock()
+		d.checkNetwork()
+	}
+
+	emitResult(c, d.results)
+	printDiagnosis(d.results)
+
+	if failed := countDiagnosis(d.results, checkFail); failed > 0 {
+		return fmt.Errorf("diagnosis found %d failed check(s)", failed)
+	}
+
+	return nil
+}
+
+// checkDNS resolves the host name of the speaker
+func (d *diagnoser) checkDNS() {
+	if net.ParseIP(d.host) != nil {
+		d.add("DNS", checkPass, fmt.Sprintf("%s is an IP address", d.host), "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.probeTimeout)
+	defer cancel()
+
+	addrs, err := net.DefaultResolver.LookupHost(ctx, d.host)
+	if err != nil {
+		d.add("DNS", checkFail, fmt.Sprintf("%s does not resolve: %v", d.host, err),
+			"Use the IP address of the speaker, or find it with 'soundtouch-cli discover devices'")
+
+		return
+	}
+
+	d.add("DNS", checkPass, fmt.Sprintf("%s resolves to %s", d.host, strings.Join(addrs, ", ")), "")
+}
+
+// checkPorts probes the REST port and the other speaker ports
+func (d *diagnoser) checkPorts() {
+	ports := append([]diagnosePort{{d.port, "REST API", checkFail,
+		"The speaker is off the network or the address is wrong; power-cycle it and check its IP address in the router"}}, speakerPorts...)
+
+	for _, p := range ports {
+		check := fmt.Sprintf("Port %d (%s)", p.port, p.name)
+		address := net.JoinHostPort(d.host, strconv.Itoa(p.port))
+		start := time.Now()
+
+		conn, err := net.DialTimeout("tcp", address, d.probeTimeout)
+		if err != nil {
+			d.add(check, p.closed, fmt.Sprintf("closed: %v", err), p.hint)
+			continue
+		}
+
+		_ = conn.Close()
+
+		d.add(check, checkPass, fmt.Sprintf("open (%v)", time.Since(start).Round(time.Millisecond)), "")
+	}
+}
+
+// checkInfo measures the latency of /info; the remaining REST checks need it to pass
+func (d *diagnoser) checkInfo() *models.DeviceInfo {
+	start := time.Now()
+
+	info, err := d.client.GetDeviceInfo()
+	latency := time.Since(start).Round(time.Millisecond)
+
+	switch {
+	case err != nil:
+		d.add("/info", checkFail, fmt.Sprintf("request failed: %v", err),
+			"The REST API does not answer; the remaining checks are skipped. Power-cycle the speaker")
+
+		return nil
+	case latency > slowInfoLatency:
+		d.add("/info", checkWarn, fmt.Sprintf("%s (%s) answered after %v", info.Name, info.Type, latency),
+			"Slow answers usually mean a weak Wi-Fi signal or a busy speaker")
+	default:
+		d.add("/info", checkPass, fmt.Sprintf("%s (%s) answered after %v", info.Name, info.Type, latency), "")
+	}
+
+	return info
+}
+
+// checkWebSocket connects to the WebSocket and waits for the first message
+func (d *diagnoser) checkWebSocket() {
+	ws := d.client.NewWebSocketClient(newWebSocketConfig(false, false))
+
+	first := make(chan string, 1)
+	notify := func(kind string) {
+		select {
+		case first <- kind:
+		default:
+		}
+	}
+
+	ws.OnSpecialMessage(func(message *models.SpecialMessage) { notify(string(message.Type)) })
+	ws.ObserveEvents(func(_ *models.WebSocketEvent) { notify("update") })
+
+	start := time.Now()
+
+	if err := ws.Connect(); err != nil {
+		d.add("WebSocket", checkFail, fmt.Sprintf("handshake failed: %v", err),
+			"Live events need the WebSocket on port 8080; power-cycle the speaker")
+
+		return
+	}
+
+	handshake := time.Since(start).Round(time.Millisecond)
+
+	defer func() { _ = ws.Disconnect() }()
+
+	select {
+	case kind := <-first:
+		d.add("WebSocket", checkPass, fmt.Sprintf("handshake after %v, first message (%s) after %v",
+			handshake, kind, time.Since(start).Round(time.Millisecond)), "")
+	case <-time.After(webSocketFirstMessage):
+		d.add("WebSocket", checkWarn, fmt.Sprintf("handshake after %v, but no message within %v", handshake, webSocketFirstMessage),
+			"The speaker accepts connections but sends no events; restart it if the app shows no live updates")
+	}
+}
+
+// checkServiceAvailability reports streaming services the speaker marks as unavailable
+func (d *diagnoser) checkServiceAvailability() {
+	availability, err := d.client.GetServiceAvailability()
+	if err != nil {
+		d.add("Services", checkWarn, fmt.Sprintf("service availability unknown: %v", err), "Older firmware does not report service availability")
+		return
+	}
+
+	var unavailable []string
+
+	for _, service := range availability.GetStreamingServices() {
+		if !service.IsAvailable {
+			unavailable = append(unavailable, fmt.Sprintf("%s (%s)", service.Type, service.GetReason()))
+		}
+	}
+
+	if len(unavailable) > 0 {
+		d.add("Services", checkWarn, fmt.Sprintf("%d of %d services available; unavailable streaming: %s",
+			availability.GetAvailableServiceCount(), availability.GetServiceCount(), strings.Join(unavailable, ", ")),
+			"Streaming services become unavailable when the speaker cannot reach its cloud server; check the cloud redirection")
+
+		return
+	}
+
+	d.add("Services", checkPass, fmt.Sprintf("%d of %d services available", availability.GetAvailableServiceCount(), availability.GetServiceCount()), "")
+}
+
+// checkCloud checks whether margeURL points at the soundtouch-service
+func (d *diagnoser) checkCloud(margeURL string) {
+	serviceHost := ""
+
+	if d.service != "" {
+		host, err := serviceHostname(d.service, d.probeTimeout)
+		if err != nil {
+			d.add("Cloud", checkWarn, fmt.Sprintf("margeURL is %s; %v", margeURL, err), "Check --service or the service of the config file")
+			return
+		}
+
+		serviceHost = host
+	}
+
+	status, detail, hint := evaluateMargeURL(margeURL, serviceHost, func(host string) []string {
+		addrs, _ := net.LookupHost(host)
+		return addrs
+	})
+	d.add("Cloud", status, detail, hint)
+}
+
+// serviceHostname returns the host of the soundtouch-service given by URL, or found via mDNS for "auto"
+func serviceHostname(service string, timeout time.Duration) (string, error) {
+	if service == "auto" {
+		instance, err := discovery.ResolveService(context.Background(), timeout)
+		if err != nil {
+			return "", fmt.Errorf("failed to find soundtouch-service: %w", err)
+		}
+
+		return instance.Host, nil
+	}
+
+	parsed, err := url.Parse(service)
+	if err != nil || parsed.Hostname() == "" {
+		return "", fmt.Errorf("invalid service URL %q", service)
+	}
+
+	return parsed.Hostname(), nil
+}
+
+// evaluateMargeURL compares the cloud server of the speaker with the soundtouch-service.
+// A margeURL host that resolves to the service counts as redirected via DNS.
+func evaluateMargeURL(margeURL, serviceHost string, lookup func(host string) []string) (string, string, string) {
+	if margeURL == "" {
+		return checkWarn, "the speaker reports no margeURL", "The speaker has no cloud server configured; run the setup of soundtouch-service"
+	}
+
+	parsed, err := url.Parse(margeURL)
+	if err != nil || parsed.Hostname() == "" {
+		return checkWarn, fmt.Sprintf("margeURL %q is not a URL", margeURL), ""
+	}
+
+	host := parsed.Hostname()
+	isBose := host == "bose.com" || strings.HasSuffix(host, ".bose.com")
+
+	if serviceHost == "" {
+		if isBose {
+			return checkWarn, fmt.Sprintf("margeURL points at the Bose cloud (%s)", host),
+				"Pass --service or set the service in the config file to check the redirection to your soundtouch-service"
+		}
+
+		return checkPass, fmt.Sprintf("margeURL points at %s", host), ""
+	}
+
+	if strings.EqualFold(host, serviceHost) {
+		return checkPass, fmt.Sprintf("margeURL points at soundtouch-service (%s)", host), ""
+	}
+
+	serviceAddrs := lookup(serviceHost)
+	if net.ParseIP(serviceHost) != nil {
+		serviceAddrs = append(serviceAddrs, serviceHost)
+	}
+
+	for _, addr := range lookup(host) {
+		if slices.Contains(serviceAddrs, addr) {
+			return checkPass, fmt.Sprintf("margeURL host %s resolves to soundtouch-service (%s) via DNS redirection", host, addr), ""
+		}
+	}
+
+	return checkFail, fmt.Sprintf("margeURL %s does not point at soundtouch-service (%s)", margeURL, serviceHost),
+		"Migrate the speaker on the setup page of soundtouch-service, or redirect the Bose domains with its DNS server"
+}
+
+// checkClock compares the clock of the speaker with this host
+func (d *diagnoser) checkClock() {
+	clock, err := d.client.GetClockTime()
+	if err != nil {
+		d.add("Clock", checkWarn, fmt.Sprintf("clock unknown: %v", err), "Not every speaker has a clock")
+		return
+	}
+
+	status, detail, hint := evaluateClockDrift(clock.GetUTC(), time.Now())
+	d.add("Clock", status, detail, hint)
+}
+
+// evaluateClockDrift rates the difference between the speaker clock and now
+func evaluateClockDrift(utc int64, now time.Time) (string, string, string) {
+	if utc <= 0 {
+		return checkWarn, "the speaker reports no time", "The clock is set from the network; check that the speaker can reach the internet"
+	}
+
+	drift := time.Unix(utc, 0).Sub(now).Round(time.Second)
+	abs := drift.Abs()
+	detail := fmt.Sprintf("%v off the time of this host", drift)
+
+	switch {
+	case abs >= clockDriftFail:
+		return checkFail, detail, "A wrong clock breaks TLS to the cloud server; set it with 'soundtouch-cli clock now'"
+	case abs >= clockDriftWarn:
+		return checkWarn, detail, "Set the clock with 'soundtouch-cli clock now'"
+	default:
+		return checkPass, detail, ""
+	}
+}
+
+// checkNetwork rates the Wi-Fi signal, or reports a wired connection
+func (d *diagnoser) checkNetwork() {
+	info, err := d.client.GetNetworkInfo()
+	if err != nil {
+		d.add("Wi-Fi", checkWarn, fmt.Sprintf("network info unknown: %v", err), "Older firmware does not report network information")
+		return
+	}
+
+	status, detail, hint := evaluateNetwork(info)
+	d.add("Wi-Fi", status, detail, hint)
+}
+
+// evaluateNetwork rates the connection reported by networkInfo
+func evaluateNetwork(info *models.NetworkInformation) (string, string, string) {
+	wifi := info.GetConnectedWiFiInterface()
+	if wifi == nil {
+		if ethernet := info.GetConnectedEthernetInterface(); ethernet != nil {
+			return checkPass, fmt.Sprintf("wired connection (%s)", ethernet.GetIPAddress()), ""
+		}
+
+		return checkWarn, "no connected interface reported", ""
+	}
+
+	detail := fmt.Sprintf("%s signal on %q (%s)", wifi.GetSignalDescription(), wifi.GetSSID(), wifi.FormatFrequency())
+
+	switch wifi.Signal {
+	case models.SignalExcellent, models.SignalGood:
+		return checkPass, detail, ""
+	case models.SignalFair:
+		return checkWarn, detail, "Move the speaker or the access point closer, or use a 2.4 GHz network for more range"
+	default:
+		return checkFail, detail, "Dropouts and slow answers are likely; move the speaker closer to the access point"
+	}
+}
+
+// countDiagnosis counts results with the given status
+func countDiagnosis(results []diagnosis, status string) int {
+	count := 0
+
+	for _, result := range results {
+		if result.Status == status {
+			count++
+		}
+	}
+
+	return count
+}
+
+// printDiagnosis prints the report with a hint below each warning and failure
+func printDiagnosis(results []diagnosis) {
+	labels := map[string]string{checkPass: "✓ PASS", checkWarn: "⚠ WARN", checkFail: "✗ FAIL"}
+
+	width := 0
+	for _, result := range results {
+		width = max(width, len(result.Check))
+	}
+
+	for _, result := range results {
+		output.Printf("%s  %-*s  %s\n", labels[result.Status], width, result.Check, result.Detail)
+
+		if result.Hint != "" && result.Status != checkPass {
+			output.Printf("        %-*s  → %s\n", width, "", result.Hint)
+		}
+	}
+
+	output.Printf("\n%d passed, %d warning(s), %d failed\n",
+		countDiagnosis(results, checkPass), countDiagnosis(results, checkWarn), countDiagnosis(results, checkFail))
+}
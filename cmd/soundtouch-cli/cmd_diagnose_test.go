@@ -0,0 +1,104 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+func TestEvaluateMargeURL(t *testing.T) {
+	lookup := func(host string) []string {
+		switch host {
+		case "streaming.bose.com":
+			return []string{"192.168.1.20"}
+		case "soundtouch.local":
+			return []string{"192.168.1.20"}
+		default:
+			return nil
+		}
+	}
+
+	tests := []struct {
+		name        string
+		margeURL    string
+		serviceHost string
+		want        string
+	}{
+		{"no margeURL", "", "", checkWarn},
+		{"bose cloud without service", "https://streaming.bose.com", "", checkWarn},
+		{"custom server without service", "http://192.168.1.20:8000", "", checkPass},
+		{"service host", "http://192.168.1.20:8000/marge", "192.168.1.20", checkPass},
+		{"DNS redirection", "https://streaming.bose.com", "soundtouch.local", checkPass},
+		{"DNS redirection to service IP", "https://streaming.bose.com", "192.168.1.20", checkPass},
+		{"other server", "http://192.168.1.30:8000", "192.168.1.20", checkFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, detail, _ := evaluateMargeURL(tt.margeURL, tt.serviceHost, lookup)
+			if status != tt.want {
+				t.Errorf("Expected %s, got %s (%s)", tt.want, status, detail)
+			}
+		})
+	}
+}
+
+func TestEvaluateClockDrift(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		utc  int64
+		want string
+	}{
+		{"not set", 0, checkWarn},
+		{"in sync", now.Add(5 * time.Second).Unix(), checkPass},
+		{"minutes behind", now.Add(-3 * time.Minute).Unix(), checkWarn},
+		{"hours ahead", now.Add(2 * time.Hour).Unix(), checkFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status, detail, _ := evaluateClockDrift(tt.utc, now); status != tt.want {
+				t.Errorf("Expected %s, got %s (%s)", tt.want, status, detail)
+			}
+		})
+	}
+}
+
+func TestEvaluateNetwork(t *testing.T) {
+	wifi := func(signal string) *models.NetworkInformation {
+		return &models.NetworkInformation{Interfaces: models.NetworkInterfaces{Interfaces: []models.NetworkInterface{{
+			Type:         models.InterfaceTypeWiFi,
+			SSID:         "Home",
+			FrequencyKHz: 2412000,
+			State:        models.StateWiFiConnected,
+			Signal:       signal,
+		}}}}
+	}
+
+	tests := []struct {
+		name string
+		info *models.NetworkInformation
+		want string
+	}{
+		{"excellent", wifi(models.SignalExcellent), checkPass},
+		{"fair", wifi(models.SignalFair), checkWarn},
+		{"poor", wifi(models.SignalPoor), checkFail},
+		{"ethernet", &models.NetworkInformation{Interfaces: models.NetworkInterfaces{Interfaces: []models.NetworkInterface{{
+			Type:      models.InterfaceTypeEthernet,
+			IPAddress: "192.168.1.10",
+			State:     models.StateEthernetConnected,
+		}}}}, checkPass},
+		{"nothing connected", &models.NetworkInformation{}, checkWarn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status, detail, _ := evaluateNetwork(tt.info); status != tt.want {
+				t.Errorf("Expected %s, got %s (%s)", tt.want, status, detail)
+			}
+		})
+	}
+}
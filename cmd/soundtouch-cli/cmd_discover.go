@@ -3,6 +3,11 @@ package main
 import (
 	"context"
 	"fmt"
+	"net"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/gesellix/bose-soundtouch/pkg/config"
@@ -13,7 +18,7 @@ import (
 
 // discoverDevices handles device discovery command
 func discoverDevices(c *cli.Context) error {
-	fmt.Printf("Discovering SoundTouch devices...\n")
+	output.Printf("Discovering SoundTouch devices...\n")
 
 	// Load configuration
 	cfg, err := config.LoadFromEnv()
@@ -28,11 +33,15 @@ func discoverDevices(c *cli.Context) error {
 		printDiscoveryContext(cfg)
 	}
 
-	fmt.Println()
+	output.Println()
 
 	// Create discovery service
 	discoveryService := discovery.NewUnifiedDiscoveryService(cfg)
 
+	if registry := openDeviceRegistry(); registry != nil {
+		discoveryService.SetRegistry(registry)
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.DiscoveryTimeout+5*time.Second)
 	defer cancel()
 
@@ -42,6 +51,8 @@ func discoverDevices(c *cli.Context) error {
 		return fmt.Errorf("discovery failed: %w", err)
 	}
 
+	emitResult(c, append([]*models.DiscoveredDevice{}, devices...))
+
 	if len(devices) == 0 {
 		printNoDevicesMessage()
 		return nil
@@ -53,6 +64,220 @@ func discoverDevices(c *cli.Context) error {
 	return nil
 }
 
+// scanSubnets finds devices by probing every address of the given subnets
+func scanSubnets(c *cli.Context) error {
+	cfg, err := config.LoadFromEnv()
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+
+	subnets := c.StringSlice("subnet")
+	if len(subnets) == 0 {
+		subnets = cfg.ScanSubnets
+	}
+
+	if len(subnets) == 0 {
+		subnets = discovery.LocalSubnets()
+	}
+
+	if len(subnets) == 0 {
+		PrintError("No subnets to scan, use --subnet 192.168.1.0/24")
+		return fmt.Errorf("no subnets to scan")
+	}
+
+	if c.IsSet("concurrency") {
+		cfg.ScanConcurrency = c.Int("concurrency")
+	}
+
+	if c.IsSet("probe-timeout") {
+		cfg.ScanTimeout = c.Duration("probe-timeout")
+	}
+
+	scanner, err := discovery.NewSubnetScanner(subnets, cfg.ScanConcurrency, cfg.ScanTimeout)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	output.Printf("Scanning %s (%d addresses)...\n\n", strings.Join(subnets, ", "), scanner.Hosts())
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	devices, err := scanner.DiscoverDevices(ctx)
+	if err != nil {
+		PrintWarning(err.Error())
+	}
+
+	emitResult(c, append([]*models.DiscoveredDevice{}, devices...))
+
+	if len(devices) == 0 {
+		output.Println("No SoundTouch devices answered on :8090/info.")
+		return nil
+	}
+
+	if registry := openDeviceRegistry(); registry != nil {
+		for _, device := range devices {
+			registry.Record(device)
+		}
+
+		if err := registry.Save(); err != nil {
+			PrintWarning(err.Error())
+		}
+	}
+
+	printDiscoveryResults(devices, c.Bool("all"))
+
+	return nil
+}
+
+// watchDevices prints discovery events until interrupted
+func watchDevices(c *cli.Context) error {
+	cfg, err := config.LoadFromEnv()
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+
+	updateConfigFromCLI(c, cfg)
+
+	discoveryService := discovery.NewUnifiedDiscoveryService(cfg)
+	discoveryService.SetWatchInterval(c.Duration("interval"))
+
+	if registry := openDeviceRegistry(); registry != nil {
+		discoveryService.SetRegistry(registry)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	output.Printf("Watching for SoundTouch devices (probing every %v, press Ctrl+C to stop)...\n", c.Duration("interval"))
+
+	for event := range discoveryService.Watch(ctx) {
+		device := event.Device
+		timestamp := time.Now().Format("15:04:05")
+
+		emitEvent(c, net.JoinHostPort(device.Host, strconv.Itoa(device.Port)), string(event.Type), event)
+
+		switch event.Type {
+		case discovery.DeviceAppeared:
+			output.Printf("[%s] + %s at %s:%d (firmware %s)\n", timestamp, device.Name, device.Host, device.Port, device.Metadata["firmware"])
+		case discovery.DeviceDisappeared:
+			output.Printf("[%s] - %s at %s:%d\n", timestamp, device.Name, device.Host, device.Port)
+		case discovery.DeviceChanged:
+			output.Printf("[%s] ~ %s: %s\n", timestamp, device.Name, describeDeviceChanges(event))
+		}
+	}
+
+	return nil
+}
+
+// listKnownDevices prints the devices remembered in the device registry with their address history
+func listKnownDevices(c *cli.Context) error {
+	registry := openDeviceRegistry()
+	if registry == nil {
+		return fmt.Errorf("device registry not available")
+	}
+
+	entries := registry.Entries()
+	emitResult(c, append([]discovery.RegistryEntry{}, entries...))
+
+	if len(entries) == 0 {
+		output.Printf("No devices in %s yet.\n", registry.Path())
+		output.Println("Devices are recorded by 'discover devices', 'discover scan' and 'discover watch'.")
+
+		return nil
+	}
+
+	output.Printf("Known devices (%s):\n\n", registry.Path())
+
+	for _, entry := range entries {
+		output.Printf("%s (%s)\n", entry.Name, entry.DeviceID)
+		output.Printf("  Address:    %s:%d\n", entry.Host, entry.Port)
+
+		if entry.MAC != "" {
+			output.Printf("  MAC:        %s\n", entry.MAC)
+		}
+
+		if entry.ModelID != "" {
+			output.Printf("  Model:      %s\n", entry.ModelID)
+		}
+
+		output.Printf("  First seen: %s\n", entry.FirstSeen.Format(time.RFC3339))
+		output.Printf("  Last seen:  %s\n", entry.LastSeen.Format(time.RFC3339))
+		output.Printf("  Found via:  %s\n", strings.Join(entry.DiscoveryMethods, ", "))
+
+		if len(entry.Addresses) > 1 {
+			output.Println("  Address history:")
+
+			for _, address := range entry.Addresses {
+				output.Printf("    %s:%d (%s - %s)\n", address.Host, address.Port,
+					address.FirstSeen.Format(time.RFC3339), address.LastSeen.Format(time.RFC3339))
+			}
+		}
+
+		output.Println()
+	}
+
+	return nil
+}
+
+// discoverServices lists soundtouch-service instances announced via mDNS
+func discoverServices(c *cli.Context) error {
+	timeout := c.Duration("timeout")
+	output.Printf("Looking for soundtouch-service instances (timeout: %v)...\n", timeout)
+
+	instances, err := discovery.ResolveServices(context.Background(), timeout)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	emitResult(c, append([]*discovery.ServiceInstance{}, instances...))
+
+	if len(instances) == 0 {
+		output.Println("No soundtouch-service found.")
+		return nil
+	}
+
+	output.Printf("Found %d service(s):\n\n", len(instances))
+
+	for _, instance := range instances {
+		output.Printf("%s\n", instance.Name)
+		output.Printf("  URL:     %s\n", instance.URL())
+
+		if instance.HTTPSPort != 0 {
+			output.Printf("  HTTPS:   port %d\n", instance.HTTPSPort)
+		}
+
+		output.Printf("  Version: %s\n", instance.Version)
+		output.Printf("  API:     %s%s\n", instance.URL(), instance.APIPath)
+		output.Printf("  Proxy:   %s\n", instance.ProxyURL())
+		output.Println()
+	}
+
+	output.Println("Use --service auto (or SOUNDTOUCH_SERVICE=auto) to send requests through the service.")
+
+	return nil
+}
+
+// describeDeviceChanges renders the changed fields of a DeviceChanged event
+func describeDeviceChanges(event discovery.WatchEvent) string {
+	changes := make([]string, 0, len(event.Changes))
+
+	for _, change := range event.Changes {
+		switch change {
+		case "host":
+			changes = append(changes, fmt.Sprintf("host %s:%d -> %s:%d", event.Previous.Host, event.Previous.Port, event.Device.Host, event.Device.Port))
+		case "name":
+			changes = append(changes, fmt.Sprintf("name %q -> %q", event.Previous.Name, event.Device.Name))
+		case "firmware":
+			changes = append(changes, fmt.Sprintf("firmware %s -> %s", event.Previous.Metadata["firmware"], event.Device.Metadata["firmware"]))
+		}
+	}
+
+	return strings.Join(changes, ", ")
+}
+
 func updateConfigFromCLI(c *cli.Context, cfg *config.Config) {
 	if c.IsSet("timeout") {
 		httpTimeout := c.Duration("timeout")
@@ -72,77 +297,77 @@ func updateConfigFromCLI(c *cli.Context, cfg *config.Config) {
 }
 
 func printDiscoveryContext(cfg *config.Config) {
-	fmt.Printf("HTTP Timeout: %v\n", cfg.HTTPTimeout)
-	fmt.Printf("Discovery Timeout: %v\n", cfg.DiscoveryTimeout)
-	fmt.Printf("Mode: Detailed information\n")
+	output.Printf("HTTP Timeout: %v\n", cfg.HTTPTimeout)
+	output.Printf("Discovery Timeout: %v\n", cfg.DiscoveryTimeout)
+	output.Printf("Mode: Detailed information\n")
 }
 
 func printNoDevicesMessage() {
-	fmt.Println("No SoundTouch devices found on the network.")
-	fmt.Println()
-	fmt.Println("This could mean:")
-	fmt.Println("- No SoundTouch devices are powered on")
-	fmt.Println("- Devices are on a different network segment")
-	fmt.Println("- Network blocks multicast traffic")
-	fmt.Println("- Firewall is blocking discovery ports")
+	output.Println("No SoundTouch devices found on the network.")
+	output.Println()
+	output.Println("This could mean:")
+	output.Println("- No SoundTouch devices are powered on")
+	output.Println("- Devices are on a different network segment")
+	output.Println("- Network blocks multicast traffic")
+	output.Println("- Firewall is blocking discovery ports")
 }
 
 func printDiscoveryResults(devices []*models.DiscoveredDevice, showAll bool) {
-	fmt.Printf("Found %d SoundTouch device(s):\n\n", len(devices))
+	output.Printf("Found %d SoundTouch device(s):\n\n", len(devices))
 
 	for i, device := range devices {
-		fmt.Printf("%d. %s\n", i+1, device.Name)
-		fmt.Printf("   Host: %s:%d\n", device.Host, device.Port)
-		fmt.Printf("   Model: %s\n", device.ModelID)
+		output.Printf("%d. %s\n", i+1, device.Name)
+		output.Printf("   Host: %s:%d\n", device.Host, device.Port)
+		output.Printf("   Model: %s\n", device.ModelID)
 
 		if device.SerialNo != "" {
-			fmt.Printf("   Serial: %s\n", device.SerialNo)
+			output.Printf("   Serial: %s\n", device.SerialNo)
 		}
 
 		if device.APIBaseURL != "" {
-			fmt.Printf("   API Base URL: %s\n", device.APIBaseURL)
+			output.Printf("   API Base URL: %s\n", device.APIBaseURL)
 		}
 
 		if device.InfoURL != "" {
-			fmt.Printf("   Info URL: %s\n", device.InfoURL)
+			output.Printf("   Info URL: %s\n", device.InfoURL)
 		}
 
 		if device.DiscoveryMethod != "" {
-			fmt.Printf("   Discovery Method: %s\n", device.DiscoveryMethod)
+			output.Printf("   Discovery Method: %s\n", device.DiscoveryMethod)
 		}
 
 		if showAll {
 			// Show protocol-specific details in verbose mode
 			if device.UPnPLocation != "" {
-				fmt.Printf("   UPnP Location: %s\n", device.UPnPLocation)
+				output.Printf("   UPnP Location: %s\n", device.UPnPLocation)
 			}
 
 			if device.UPnPUSN != "" {
-				fmt.Printf("   UPnP USN: %s\n", device.UPnPUSN)
+				output.Printf("   UPnP USN: %s\n", device.UPnPUSN)
 			}
 
 			if device.MDNSHostname != "" {
-				fmt.Printf("   mDNS Hostname: %s\n", device.MDNSHostname)
+				output.Printf("   mDNS Hostname: %s\n", device.MDNSHostname)
 			}
 
 			if device.MDNSService != "" {
-				fmt.Printf("   mDNS Service: %s\n", device.MDNSService)
+				output.Printf("   mDNS Service: %s\n", device.MDNSService)
 			}
 
 			if device.ConfigName != "" {
-				fmt.Printf("   Config Name: %s\n", device.ConfigName)
+				output.Printf("   Config Name: %s\n", device.ConfigName)
 			}
 
-			fmt.Printf("   Last Seen: %s\n", device.LastSeen.Format("2006-01-02 15:04:05"))
+			output.Printf("   Last Seen: %s\n", device.LastSeen.Format("2006-01-02 15:04:05"))
 		}
 
 		// Add spacing between devices
 		if i < len(devices)-1 {
-			fmt.Println()
+			output.Println()
 		}
 	}
 
-	fmt.Println()
-	fmt.Printf("Use any of these hosts with other commands:\n")
-	fmt.Printf("Example: soundtouch-cli info --host %s\n", devices[0].Host)
+	output.Println()
+	output.Printf("Use any of these hosts with other commands:\n")
+	output.Printf("Example: soundtouch-cli info --host %s\n", devices[0].Host)
 }
@@ -4,8 +4,10 @@ package main
 import (
 	"context"
 	"fmt"
+	"net"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -21,7 +23,10 @@ func eventSubscribe(c *cli.Context) error {
 
 	// Parse filters
 	filterStr := c.String("filter")
-	filters := parseEventFilters(filterStr)
+	filters, err := parseEventFilters(filterStr)
+	if err != nil {
+		return err
+	}
 
 	// Parse duration
 	duration := c.Duration("duration")
@@ -38,7 +43,7 @@ func eventSubscribe(c *cli.Context) error {
 	}
 
 	// Test basic connectivity
-	fmt.Println("Testing device connectivity...")
+	output.Println("Testing device connectivity...")
 
 	deviceInfo, err := soundTouchClient.GetDeviceInfo()
 	if err != nil {
@@ -51,17 +56,20 @@ func eventSubscribe(c *cli.Context) error {
 		macAddress = deviceInfo.NetworkInfo[0].MacAddress
 	}
 
-	fmt.Printf("✅ Connected to: %s (Type: %s, MAC: %s)\n",
+	output.Printf("✅ Connected to: %s (Type: %s, MAC: %s)\n",
 		deviceInfo.Name, deviceInfo.Type, macAddress)
 
 	// Create WebSocket client
 	wsClient := setupWebSocketClient(soundTouchClient, reconnect, verbose)
 
-	// Set up event handlers
-	setupEventHandlers(wsClient, filters, verbose)
+	// Set up event handlers; with structured output, every event is written as it arrives
+	device := net.JoinHostPort(clientConfig.Host, strconv.Itoa(clientConfig.Port))
+	setupEventHandlers(wsClient, filters, verbose, func(kind string, event any) {
+		emitEvent(c, device, kind, event)
+	})
 
 	// Connect to WebSocket
-	fmt.Println("🔌 Connecting to WebSocket...")
+	output.Println("🔌 Connecting to WebSocket...")
 
 	err = wsClient.Connect()
 	if err != nil {
@@ -69,16 +77,16 @@ func eventSubscribe(c *cli.Context) error {
 		return err
 	}
 
-	fmt.Println("✅ Connected! Listening for events...")
+	output.Println("✅ Connected! Listening for events...")
 
 	if len(filters) > 0 {
-		fmt.Printf("📋 Filtering events: %s\n", strings.Join(getFilterKeys(filters), ", "))
+		output.Printf("📋 Filtering events: %s\n", strings.Join(getFilterKeys(filters), ", "))
 	}
 
 	if duration > 0 {
-		fmt.Printf("⏰ Will listen for %v\n", duration)
+		output.Printf("⏰ Will listen for %v\n", duration)
 	} else {
-		fmt.Println("⏸️  Press Ctrl+C to stop")
+		output.Println("⏸️  Press Ctrl+C to stop")
 	}
 
 	// Set up graceful shutdown
@@ -90,7 +98,7 @@ func eventSubscribe(c *cli.Context) error {
 		go func() {
 			select {
 			case <-time.After(duration):
-				fmt.Println("\n⏰ Duration limit reached, shutting down...")
+				output.Println("\n⏰ Duration limit reached, shutting down...")
 				cancel()
 			case <-ctx.Done():
 				return
@@ -105,7 +113,7 @@ func eventSubscribe(c *cli.Context) error {
 	go func() {
 		select {
 		case sig := <-sigChan:
-			fmt.Printf("\n🛑 Received signal %v, shutting down...\n", sig)
+			output.Printf("\n🛑 Received signal %v, shutting down...\n", sig)
 			cancel()
 		case <-ctx.Done():
 			return
@@ -116,27 +124,28 @@ func eventSubscribe(c *cli.Context) error {
 	<-ctx.Done()
 
 	// Disconnect WebSocket
-	fmt.Println("🔌 Disconnecting...")
+	output.Println("🔌 Disconnecting...")
 
 	if err := wsClient.Disconnect(); err != nil {
 		PrintError(fmt.Sprintf("Error during disconnect: %v", err))
 	}
 
-	fmt.Println("✅ Disconnected successfully")
+	output.Println("✅ Disconnected successfully")
 
 	return nil
 }
 
 // parseEventFilters validates and parses the filter string
-func parseEventFilters(eventFilter string) map[string]bool {
+func parseEventFilters(eventFilter string) (map[string]bool, error) {
 	validFilters := map[string]bool{
 		"nowPlaying": true, "volume": true, "connection": true,
 		"preset": true, "zone": true, "bass": true,
+		"name": true, "recents": true, "sources": true,
 		"sdkInfo": true, "userActivity": true,
 	}
 
 	if eventFilter == "" {
-		return nil
+		return nil, nil
 	}
 
 	filters := make(map[string]bool)
@@ -145,19 +154,23 @@ func parseEventFilters(eventFilter string) map[string]bool {
 	for _, f := range filterList {
 		f = strings.TrimSpace(f)
 		if !validFilters[f] {
-			PrintError(fmt.Sprintf("Invalid filter '%s'. Valid filters: %s",
-				f, strings.Join(getFilterKeys(validFilters), ", ")))
-			os.Exit(1)
+			return nil, &usageError{fmt.Errorf("invalid filter '%s'. Valid filters: %s",
+				f, strings.Join(getFilterKeys(validFilters), ", "))}
 		}
 
 		filters[f] = true
 	}
 
-	return filters
+	return filters, nil
 }
 
 // setupWebSocketClient creates and configures the WebSocket client
 func setupWebSocketClient(soundTouchClient *client.Client, reconnect, verbose bool) *client.WebSocketClient {
+	return soundTouchClient.NewWebSocketClient(newWebSocketConfig(reconnect, verbose))
+}
+
+// newWebSocketConfig returns the WebSocket configuration of the CLI
+func newWebSocketConfig(reconnect, verbose bool) *client.WebSocketConfig {
 	wsConfig := &client.WebSocketConfig{
 		ReconnectInterval:    5 * time.Second,
 		MaxReconnectAttempts: 0, // Unlimited if reconnect enabled
@@ -177,14 +190,15 @@ func setupWebSocketClient(soundTouchClient *client.Client, reconnect, verbose bo
 		wsConfig.MaxReconnectAttempts = 1
 	}
 
-	return soundTouchClient.NewWebSocketClient(wsConfig)
+	return wsConfig
 }
 
-// setupEventHandlers configures all event handlers
-func setupEventHandlers(wsClient *client.WebSocketClient, filters map[string]bool, verbose bool) {
+// setupEventHandlers configures all event handlers; emit receives every handled event with its filter name
+func setupEventHandlers(wsClient *client.WebSocketClient, filters map[string]bool, verbose bool, emit func(kind string, event any)) {
 	// Now Playing events
 	if filters == nil || filters["nowPlaying"] {
 		wsClient.OnNowPlaying(func(event *models.NowPlayingUpdatedEvent) {
+			emit("nowPlaying", event)
 			handleNowPlayingEvent(event, verbose)
 		})
 	}
@@ -192,6 +206,7 @@ func setupEventHandlers(wsClient *client.WebSocketClient, filters map[string]boo
 	// Volume events
 	if filters == nil || filters["volume"] {
 		wsClient.OnVolumeUpdated(func(event *models.VolumeUpdatedEvent) {
+			emit("volume", event)
 			handleVolumeEvent(event, verbose)
 		})
 	}
@@ -199,6 +214,7 @@ func setupEventHandlers(wsClient *client.WebSocketClient, filters map[string]boo
 	// Connection state events
 	if filters == nil || filters["connection"] {
 		wsClient.OnConnectionState(func(event *models.ConnectionStateUpdatedEvent) {
+			emit("connection", event)
 			handleConnectionEvent(event)
 		})
 	}
@@ -206,6 +222,7 @@ func setupEventHandlers(wsClient *client.WebSocketClient, filters map[string]boo
 	// Preset events
 	if filters == nil || filters["preset"] {
 		wsClient.OnPresetUpdated(func(event *models.PresetUpdatedEvent) {
+			emit("preset", event)
 			handlePresetEvent(event, verbose)
 		})
 	}
@@ -213,6 +230,7 @@ func setupEventHandlers(wsClient *client.WebSocketClient, filters map[string]boo
 	// Zone/Multiroom events
 	if filters == nil || filters["zone"] {
 		wsClient.OnZoneUpdated(func(event *models.ZoneUpdatedEvent) {
+			emit("zone", event)
 			handleZoneEvent(event)
 		})
 	}
@@ -220,98 +238,125 @@ func setupEventHandlers(wsClient *client.WebSocketClient, filters map[string]boo
 	// Bass events
 	if filters == nil || filters["bass"] {
 		wsClient.OnBassUpdated(func(event *models.BassUpdatedEvent) {
+			emit("bass", event)
 			handleBassEvent(event)
 		})
 	}
 
+	// Name events
+	if filters == nil || filters["name"] {
+		wsClient.OnNameUpdated(func(event *models.NameUpdatedEvent) {
+			emit("name", event)
+			handleNameEvent(event)
+		})
+	}
+
+	// Recents events
+	if filters == nil || filters["recents"] {
+		wsClient.OnRecentsUpdated(func(event *models.RecentsUpdatedEvent) {
+			emit("recents", event)
+			handleRecentsEvent(event, verbose)
+		})
+	}
+
+	// Sources events
+	if filters == nil || filters["sources"] {
+		wsClient.OnSourcesUpdated(func(event *models.SourcesUpdatedEvent) {
+			emit("sources", event)
+			handleSourcesEvent(event)
+		})
+	}
+
 	// Special message handler
 	wsClient.OnSpecialMessage(func(message *models.SpecialMessage) {
+		emit("special", message)
 		handleSpecialMessage(message, filters, verbose)
 	})
 
 	// Unknown events (always enabled for debugging)
 	wsClient.OnUnknownEvent(func(event *models.WebSocketEvent) {
+		emit("unknown", event)
 		handleUnknownEvent(event, verbose)
 	})
 }
 
 // Event handlers
 func handleNowPlayingEvent(event *models.NowPlayingUpdatedEvent, verbose bool) {
-	fmt.Printf("\n🎵 Now Playing Update [%s]:\n", event.DeviceID)
+	output.Printf("\n🎵 Now Playing Update [%s]:\n", event.DeviceID)
 	np := &event.NowPlaying
 
 	if np.IsEmpty() {
-		fmt.Println("  ⏹️  Nothing playing")
+		output.Println("  ⏹️  Nothing playing")
 		return
 	}
 
-	fmt.Printf("  🎵 %s\n", np.GetDisplayTitle())
+	output.Printf("  🎵 %s\n", np.GetDisplayTitle())
 
 	if artist := np.GetDisplayArtist(); artist != "" {
-		fmt.Printf("  👤 %s\n", artist)
+		output.Printf("  👤 %s\n", artist)
 	}
 
 	if np.Album != "" {
-		fmt.Printf("  💿 %s\n", np.Album)
+		output.Printf("  💿 %s\n", np.Album)
 	}
 
-	fmt.Printf("  📻 Source: %s\n", np.Source)
-	fmt.Printf("  ▶️  Status: %s\n", np.PlayStatus.String())
+	output.Printf("  📻 Source: %s\n", np.Source)
+	output.Printf("  ▶️  Status: %s\n", np.PlayStatus.String())
 
 	if np.HasTimeInfo() {
-		fmt.Printf("  ⏱️  Duration: %s\n", np.FormatDuration())
+		output.Printf("  ⏱️  Duration: %s\n", np.FormatDuration())
 	}
 
 	if np.ShuffleSetting != "" {
-		fmt.Printf("  🔀 Shuffle: %s\n", np.ShuffleSetting.String())
+		output.Printf("  🔀 Shuffle: %s\n", np.ShuffleSetting.String())
 	}
 
 	if np.RepeatSetting != "" {
-		fmt.Printf("  🔁 Repeat: %s\n", np.RepeatSetting.String())
+		output.Printf("  🔁 Repeat: %s\n", np.RepeatSetting.String())
 	}
 
 	if verbose {
-		fmt.Printf("  📱 Raw Source: %s, Account: %s\n", np.Source, np.SourceAccount)
+		output.Printf("  📱 Raw Source: %s, Account: %s\n", np.Source, np.SourceAccount)
 
 		if np.Art != nil && np.Art.URL != "" {
-			fmt.Printf("  🖼️  Artwork: %s\n", np.Art.URL)
+			output.Printf("  🖼️  Artwork: %s\n", np.Art.URL)
 		}
 	}
 }
 
 func handleVolumeEvent(event *models.VolumeUpdatedEvent, verbose bool) {
 	vol := &event.Volume
-	fmt.Printf("\n🔊 Volume Update [%s]:\n", event.DeviceID)
+	output.Printf("\n🔊 Volume Update [%s]:\n", event.DeviceID)
 
 	if vol.IsMuted() {
-		fmt.Println("  🔇 Muted")
+		output.Println("  🔇 Muted")
 	} else {
-		fmt.Printf("  🔊 Level: %d\n", vol.ActualVolume)
+		output.Printf("  🔊 Level: %d\n", vol.ActualVolume)
 
 		if vol.TargetVolume != vol.ActualVolume {
-			fmt.Printf("  🎯 Target: %d\n", vol.TargetVolume)
+			output.Printf("  🎯 Target: %d\n", vol.TargetVolume)
 		}
 
-		fmt.Printf("  📊 %s\n", models.GetVolumeLevelName(vol.ActualVolume))
+		output.Printf("  📊 %s\n", models.GetVolumeLevelName(vol.ActualVolume))
 	}
 
 	if verbose {
-		fmt.Printf("  📱 Sync: %v\n", vol.IsVolumeSync())
+		output.Printf("  📱 Sync: %v\n", vol.IsVolumeSync())
 	}
 }
 
 func handleConnectionEvent(event *models.ConnectionStateUpdatedEvent) {
 	cs := &event.ConnectionState
-	fmt.Printf("\n🌐 Connection Update [%s]:\n", event.DeviceID)
+	output.Printf("\n🌐 Connection Update [%s]:\n", event.DeviceID)
 
 	if cs.IsConnected() {
-		fmt.Println("  ✅ Connected")
+		output.Println("  ✅ Connected")
 	} else {
-		fmt.Printf("  ❌ State: %s\n", cs.State)
+		output.Printf("  ❌ State: %s\n", cs.State)
 	}
 
 	if cs.Signal != "" {
-		fmt.Printf("  📶 Signal: %s\n", cs.GetSignalStrength())
+		output.Printf("  📶 Signal: %s\n", cs.GetSignalStrength())
 	}
 }
 
@@ -323,48 +368,48 @@ func handlePresetEvent(event *models.PresetUpdatedEvent, verbose bool) {
 		deviceHeader += fmt.Sprintf(" [%s]", event.DeviceID)
 	}
 
-	fmt.Printf("%s:\n", deviceHeader)
-	fmt.Printf("  📻 Total presets: %d\n", len(presets.Preset))
+	output.Printf("%s:\n", deviceHeader)
+	output.Printf("  📻 Total presets: %d\n", len(presets.Preset))
 
 	for _, preset := range presets.Preset {
-		fmt.Printf("  📻 Preset %d:", preset.ID)
+		output.Printf("  📻 Preset %d:", preset.ID)
 
 		if preset.ContentItem != nil {
-			fmt.Printf(" %s", preset.ContentItem.ItemName)
-			fmt.Printf(" (%s)", preset.ContentItem.Source)
+			output.Printf(" %s", preset.ContentItem.ItemName)
+			output.Printf(" (%s)", preset.ContentItem.Source)
 		}
 
-		fmt.Println()
+		output.Println()
 	}
 
 	if verbose {
-		fmt.Printf("  📱 Raw presets data: %d total presets\n", len(presets.Preset))
+		output.Printf("  📱 Raw presets data: %d total presets\n", len(presets.Preset))
 	}
 }
 
 func handleZoneEvent(event *models.ZoneUpdatedEvent) {
 	zone := &event.Zone
-	fmt.Printf("\n🏠 Zone Update [%s]:\n", event.DeviceID)
-	fmt.Printf("  👑 Master: %s\n", zone.Master)
+	output.Printf("\n🏠 Zone Update [%s]:\n", event.DeviceID)
+	output.Printf("  👑 Master: %s\n", zone.Master)
 
 	if len(zone.Members) > 0 {
-		fmt.Printf("  👥 Members (%d):\n", len(zone.Members))
+		output.Printf("  👥 Members (%d):\n", len(zone.Members))
 
 		for i, member := range zone.Members {
-			fmt.Printf("    %d. %s (%s)\n", i+1, member.DeviceID, member.IP)
+			output.Printf("    %d. %s (%s)\n", i+1, member.DeviceID, member.IP)
 		}
 	} else {
-		fmt.Println("  👤 Single device (no zone)")
+		output.Println("  👤 Single device (no zone)")
 	}
 }
 
 func handleBassEvent(event *models.BassUpdatedEvent) {
 	bass := &event.Bass
-	fmt.Printf("\n🎵 Bass Update [%s]:\n", event.DeviceID)
-	fmt.Printf("  🎚️  Level: %d\n", bass.ActualBass)
+	output.Printf("\n🎵 Bass Update [%s]:\n", event.DeviceID)
+	output.Printf("  🎚️  Level: %d\n", bass.ActualBass)
 
 	if bass.TargetBass != bass.ActualBass {
-		fmt.Printf("  🎯 Target: %d\n", bass.TargetBass)
+		output.Printf("  🎯 Target: %d\n", bass.TargetBass)
 	}
 
 	levelDesc := "Neutral"
@@ -374,7 +419,27 @@ func handleBassEvent(event *models.BassUpdatedEvent) {
 		levelDesc = "Reduced"
 	}
 
-	fmt.Printf("  📊 %s\n", levelDesc)
+	output.Printf("  📊 %s\n", levelDesc)
+}
+
+func handleNameEvent(event *models.NameUpdatedEvent) {
+	output.Printf("\n🏷️  Name Update [%s]:\n", event.DeviceID)
+	output.Printf("  📛 Name: %s\n", event.Name.GetName())
+}
+
+func handleRecentsEvent(event *models.RecentsUpdatedEvent, verbose bool) {
+	output.Printf("\n🕘 Recents Update [%s]:\n", event.DeviceID)
+	output.Printf("  📋 Items: %d\n", len(event.Recents.Items))
+
+	if verbose {
+		for i, item := range event.Recents.Items {
+			output.Printf("    %d. %s (%s)\n", i+1, item.ContentItem.ItemName, item.ContentItem.Source)
+		}
+	}
+}
+
+func handleSourcesEvent(event *models.SourcesUpdatedEvent) {
+	output.Printf("\n🔌 Sources Update [%s]\n", event.DeviceID)
 }
 
 func handleSpecialMessage(message *models.SpecialMessage, filters map[string]bool, verbose bool) {
@@ -395,37 +460,37 @@ func handleSpecialMessage(message *models.SpecialMessage, filters map[string]boo
 	switch message.Type {
 	case models.MessageTypeSdkInfo:
 		if sdkInfo := message.GetSdkInfo(); sdkInfo != nil {
-			fmt.Printf("\n📡 SDK Info:\n")
-			fmt.Printf("  📋 Server Version: %s\n", sdkInfo.ServerVersion)
-			fmt.Printf("  🔧 Server Build: %s\n", sdkInfo.ServerBuild)
+			output.Printf("\n📡 SDK Info:\n")
+			output.Printf("  📋 Server Version: %s\n", sdkInfo.ServerVersion)
+			output.Printf("  🔧 Server Build: %s\n", sdkInfo.ServerBuild)
 		}
 	case models.MessageTypeUserActivity:
-		fmt.Printf("\n👤 User Activity [%s]\n", message.DeviceID)
+		output.Printf("\n👤 User Activity [%s]\n", message.DeviceID)
 
 		if verbose {
-			fmt.Printf("  ⏰ Timestamp: %s\n", message.Timestamp.Format("15:04:05"))
+			output.Printf("  ⏰ Timestamp: %s\n", message.Timestamp.Format("15:04:05"))
 		}
 	default:
-		fmt.Printf("\n❓ Unknown Special Message: %s\n", message.String())
+		output.Printf("\n❓ Unknown Special Message: %s\n", message.String())
 
 		if verbose {
-			fmt.Printf("  📱 Raw data: %s\n", string(message.RawData))
+			output.Printf("  📱 Raw data: %s\n", string(message.RawData))
 		}
 	}
 }
 
 func handleUnknownEvent(event *models.WebSocketEvent, verbose bool) {
-	fmt.Printf("\n❓ Unknown Event [%s]:\n", event.DeviceID)
+	output.Printf("\n❓ Unknown Event [%s]:\n", event.DeviceID)
 	types := event.GetEventTypes()
 
 	for _, eventType := range types {
-		fmt.Printf("  📝 Type: %s\n", eventType)
+		output.Printf("  📝 Type: %s\n", eventType)
 	}
 
 	if verbose {
 		events := event.GetEvents()
-		fmt.Printf("  📱 Event count: %d\n", len(events))
-		fmt.Printf("  ⏰ Timestamp: %s\n", event.Timestamp.Format(time.RFC3339))
+		output.Printf("  📱 Event count: %d\n", len(events))
+		output.Printf("  ⏰ Timestamp: %s\n", event.Timestamp.Format(time.RFC3339))
 	}
 }
 
@@ -444,7 +509,7 @@ type VerboseLogger struct{}
 
 func (v *VerboseLogger) Printf(format string, args ...interface{}) {
 	timestamp := time.Now().Format("15:04:05")
-	fmt.Printf("[%s] [WebSocket] %s\n", timestamp, fmt.Sprintf(format, args...))
+	output.Printf("[%s] [WebSocket] %s\n", timestamp, fmt.Sprintf(format, args...))
 }
 
 type SilentLogger struct{}
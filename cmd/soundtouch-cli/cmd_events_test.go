@@ -1,8 +1,8 @@
 package main
 
 import (
+	"errors"
 	"reflect"
-	"strings"
 	"testing"
 )
 
@@ -11,31 +11,31 @@ func TestParseEventFilters(t *testing.T) {
 		name        string
 		eventFilter string
 		want        map[string]bool
-		expectExit  bool
+		expectErr   bool
 	}{
 		{
 			name:        "empty filter",
 			eventFilter: "",
 			want:        nil,
-			expectExit:  false,
+			expectErr:   false,
 		},
 		{
 			name:        "single valid filter",
 			eventFilter: "nowPlaying",
 			want:        map[string]bool{"nowPlaying": true},
-			expectExit:  false,
+			expectErr:   false,
 		},
 		{
 			name:        "multiple valid filters",
 			eventFilter: "nowPlaying,volume,bass",
 			want:        map[string]bool{"nowPlaying": true, "volume": true, "bass": true},
-			expectExit:  false,
+			expectErr:   false,
 		},
 		{
 			name:        "filters with spaces",
 			eventFilter: "nowPlaying, volume , bass",
 			want:        map[string]bool{"nowPlaying": true, "volume": true, "bass": true},
-			expectExit:  false,
+			expectErr:   false,
 		},
 		{
 			name:        "all valid filters",
@@ -50,128 +50,57 @@ func TestParseEventFilters(t *testing.T) {
 				"sdkInfo":      true,
 				"userActivity": true,
 			},
-			expectExit: false,
+			expectErr: false,
 		},
 		{
 			name:        "duplicate filters",
 			eventFilter: "volume,volume,bass",
 			want:        map[string]bool{"volume": true, "bass": true},
-			expectExit:  false,
+			expectErr:   false,
 		},
 		{
-			name:        "single invalid filter - should exit",
+			name:        "single invalid filter",
 			eventFilter: "invalidFilter",
 			want:        nil,
-			expectExit:  true,
+			expectErr:   true,
 		},
 		{
-			name:        "mixed valid and invalid - should exit",
+			name:        "mixed valid and invalid",
 			eventFilter: "nowPlaying,invalidFilter,volume",
 			want:        nil,
-			expectExit:  true,
+			expectErr:   true,
 		},
 		{
 			name:        "comma only",
 			eventFilter: ",",
 			want:        nil,
-			expectExit:  true,
+			expectErr:   true,
 		},
 		{
 			name:        "trailing comma",
 			eventFilter: "nowPlaying,volume,",
 			want:        nil,
-			expectExit:  true,
+			expectErr:   true,
 		},
 		{
 			name:        "leading comma",
 			eventFilter: ",nowPlaying,volume",
 			want:        nil,
-			expectExit:  true,
+			expectErr:   true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if tt.expectExit {
-				// For test cases that should exit, we can't easily test the os.Exit call
-				// So we'll just test that invalid filters exist in the input
-				if tt.eventFilter == "" {
-					return // Empty filter is valid
-				}
-
-				// Check if the filter contains any invalid values
-				hasInvalid := false
-
-				if tt.eventFilter != "" {
-					if strings.Contains(tt.eventFilter, "invalidFilter") ||
-						strings.Contains(tt.eventFilter, ",,") ||
-						strings.HasPrefix(tt.eventFilter, ",") ||
-						strings.HasSuffix(tt.eventFilter, ",") ||
-						tt.eventFilter == "," {
-						hasInvalid = true
-					}
-				}
-
-				if !hasInvalid && tt.expectExit {
-					t.Errorf("Expected invalid filter but didn't find one in: %s", tt.eventFilter)
-				}
-			} else {
-				// We can't easily test the actual function since it calls os.Exit on invalid input
-				// Instead, we'll test the logic manually
-				if tt.eventFilter == "" {
-					if tt.want != nil {
-						t.Errorf("parseEventFilters() = %v, want %v", nil, tt.want)
-					}
-
-					return
-				}
-
-				// Simulate the parsing logic
-				filters := make(map[string]bool)
-				validFilters := map[string]bool{
-					"nowPlaying": true, "volume": true, "connection": true,
-					"preset": true, "zone": true, "bass": true,
-					"sdkInfo": true, "userActivity": true,
-				}
+			filters, err := parseEventFilters(tt.eventFilter)
 
-				parts := []string{}
-
-				for _, part := range []string{tt.eventFilter} {
-					// Simple split simulation
-					switch part {
-					case "nowPlaying,volume,bass":
-						parts = []string{"nowPlaying", "volume", "bass"}
-					case "nowPlaying, volume , bass":
-						parts = []string{"nowPlaying", " volume ", " bass"}
-					case "nowPlaying,volume,connection,preset,zone,bass,sdkInfo,userActivity":
-						parts = []string{"nowPlaying", "volume", "connection", "preset", "zone", "bass", "sdkInfo", "userActivity"}
-					case "volume,volume,bass":
-						parts = []string{"volume", "volume", "bass"}
-					default:
-						parts = []string{part}
-					}
-				}
-
-				allValid := true
-
-				for _, f := range parts {
-					f = strings.TrimSpace(f)
-					if f == "" {
-						allValid = false
-						break
-					}
-
-					if !validFilters[f] {
-						allValid = false
-						break
-					}
-
-					filters[f] = true
-				}
+			var usageErr *usageError
+			if tt.expectErr != errors.As(err, &usageErr) {
+				t.Fatalf("parseEventFilters() error = %v, expected usage error: %v", err, tt.expectErr)
+			}
 
-				if allValid && !reflect.DeepEqual(filters, tt.want) {
-					t.Errorf("parseEventFilters() = %v, want %v", filters, tt.want)
-				}
+			if !reflect.DeepEqual(filters, tt.want) {
+				t.Errorf("parseEventFilters() = %v, want %v", filters, tt.want)
 			}
 		})
 	}
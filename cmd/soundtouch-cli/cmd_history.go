@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/gesellix/bose-soundtouch/pkg/service/datastore"
+	"github.com/urfave/cli/v2"
+)
+
+// historyDataDirFlag selects where the listening history is stored; it matches the service data directory
+var historyDataDirFlag = &cli.StringFlag{
+	Name:    "data-dir",
+	Usage:   "Directory where soundtouch-service records the listening history",
+	Value:   "data",
+	EnvVars: []string{"SOUNDTOUCH_DATA_DIR", "DATA_DIR"},
+}
+
+// historyFilterFlags returns the flags that select history entries
+func historyFilterFlags() []cli.Flag {
+	return []cli.Flag{
+		historyDataDirFlag,
+		&cli.StringFlag{
+			Name:    "query",
+			Aliases: []string{"q"},
+			Usage:   "Search names, tracks, artists, albums, stations and locations",
+		},
+		&cli.StringFlag{
+			Name:  "speaker",
+			Usage: "Only plays of this speaker (device ID or name)",
+		},
+		&cli.StringFlag{
+			Name:  "source",
+			Usage: "Only plays of this source, e.g. TUNEIN or SPOTIFY",
+		},
+		&cli.StringFlag{
+			Name:  "period",
+			Usage: "Only plays of the last period: today, week, month, year, days like 30d or a duration like 12h",
+		},
+	}
+}
+
+// historyQuery reads the filter flags
+func historyQuery(c *cli.Context, limit int) (models.HistoryQuery, error) {
+	from, err := models.ParseHistoryPeriod(c.String("period"), time.Now())
+	if err != nil {
+		return models.HistoryQuery{}, &usageError{err}
+	}
+
+	return models.HistoryQuery{
+		Text:    c.String("query"),
+		Speaker: c.String("speaker"),
+		Source:  c.String("source"),
+		From:    from,
+		Limit:   limit,
+	}, nil
+}
+
+// loadHistory returns the history entries selected by the query
+func loadHistory(c *cli.Context, query models.HistoryQuery) ([]models.HistoryEntry, error) {
+	entries, err := datastore.NewDataStore(c.String("data-dir")).ListHistory(query)
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to load history: %v", err))
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// listHistory prints the latest plays, optionally filtered or searched; the search text may also be given as arguments
+func listHistory(c *cli.Context) error {
+	query, err := historyQuery(c, c.Int("limit"))
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	if query.Text == "" && c.NArg() > 0 {
+		query.Text = strings.Join(c.Args().Slice(), " ")
+	}
+
+	entries, err := loadHistory(c, query)
+	if err != nil {
+		return err
+	}
+
+	emitResult(c, entries)
+
+	if len(entries) == 0 {
+		output.Println("No plays found")
+		output.Println("The history is recorded by soundtouch-service; use the same --data-dir")
+
+		return nil
+	}
+
+	output.Printf("Listening history (%d):\n", len(entries))
+
+	for i := range entries {
+		e := &entries[i]
+
+		speaker := e.Speaker
+		if speaker == "" {
+			speaker = e.DeviceID
+		}
+
+		output.Printf("  %s  %-16s %-12s %s\n", e.Time.Local().Format("2006-01-02 15:04"), speaker, e.Source, e.Title())
+	}
+
+	return nil
+}
+
+// historyStats prints the number of plays with the most played stations, artists and sources
+func historyStats(c *cli.Context) error {
+	query, err := historyQuery(c, 0)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	entries, err := loadHistory(c, query)
+	if err != nil {
+		return err
+	}
+
+	stats := models.NewHistoryStats(entries, c.Int("top"))
+	emitResult(c, stats)
+
+	if stats.Plays == 0 {
+		output.Println("No plays found")
+		return nil
+	}
+
+	output.Printf("Plays: %d (%s to %s)\n", stats.Plays, stats.First.Local().Format("2006-01-02"), stats.Last.Local().Format("2006-01-02"))
+	printHistoryCounts("Top stations", stats.Stations, "  ")
+	printHistoryCounts("Top artists", stats.Artists, "  ")
+	printHistoryCounts("Sources", stats.Sources, "  ")
+
+	if len(stats.Speakers) < 2 {
+		return nil
+	}
+
+	output.Println("\nSpeakers:")
+
+	for i := range stats.Speakers {
+		speaker := &stats.Speakers[i]
+
+		name := speaker.Speaker
+		if name == "" {
+			name = speaker.DeviceID
+		}
+
+		output.Printf("  %s: %d plays\n", name, speaker.Plays)
+		printHistoryCounts("Top stations", speaker.Stations, "    ")
+		printHistoryCounts("Top artists", speaker.Artists, "    ")
+	}
+
+	return nil
+}
+
+func printHistoryCounts(title string, counts []models.HistoryCount, indent string) {
+	if len(counts) == 0 {
+		return
+	}
+
+	output.Printf("%s%s:\n", indent, title)
+
+	for _, count := range counts {
+		output.Printf("%s  %4d  %s\n", indent, count.Plays, count.Name)
+	}
+}
+
+// exportHistory writes the selected plays as CSV or JSON to a file or stdout
+func exportHistory(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return &usageError{fmt.Errorf("usage: history export <file>, use - for stdout")}
+	}
+
+	format := strings.ToLower(c.String("format"))
+	if format != "csv" && format != "json" {
+		return &usageError{fmt.Errorf("invalid format %q: use csv or json", c.String("format"))}
+	}
+
+	path := c.Args().First()
+
+	query, err := historyQuery(c, 0)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	entries, err := loadHistory(c, query)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+
+	if format == "json" {
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			PrintError(fmt.Sprintf("Failed to encode history: %v", err))
+			return err
+		}
+
+		buf.Write(append(data, '\n'))
+	} else if err := models.WriteHistoryCSV(&buf, entries); err != nil {
+		PrintError(fmt.Sprintf("Failed to encode history: %v", err))
+		return err
+	}
+
+	if path == "-" {
+		// The history itself is the result; structured output wraps it instead of printing it twice
+		if structuredOutput() {
+			emitResult(c, entries)
+		} else {
+			_, _ = output.Writer().Write(buf.Bytes())
+		}
+
+		return nil
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		PrintError(fmt.Sprintf("Failed to write history: %v", err))
+		return err
+	}
+
+	emitResult(c, map[string]any{"file": path, "format": format, "entries": len(entries)})
+	PrintSuccess(fmt.Sprintf("Exported %d plays to %s", len(entries), path))
+
+	return nil
+}
+
+// pruneHistory removes plays beyond the given retention
+func pruneHistory(c *cli.Context) error {
+	maxAge, err := models.ParseHistoryAge(c.String("max-age"))
+	if err != nil {
+		return &usageError{err}
+	}
+
+	retention := models.HistoryRetention{MaxAge: maxAge, MaxEntries: c.Int("max-entries")}
+	if retention.MaxAge <= 0 && retention.MaxEntries <= 0 {
+		return &usageError{fmt.Errorf("usage: history prune --max-age <age> and/or --max-entries <n>")}
+	}
+
+	removed, err := datastore.NewDataStore(c.String("data-dir")).PruneHistory(retention)
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to prune history: %v", err))
+		return err
+	}
+
+	emitResult(c, map[string]int{"removed": removed})
+	PrintSuccess(fmt.Sprintf("Removed %d plays from the history", removed))
+
+	return nil
+}
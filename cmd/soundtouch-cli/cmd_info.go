@@ -24,38 +24,40 @@ func getDeviceInfo(c *cli.Context) error {
 		return fmt.Errorf("failed to get device info: %w", err)
 	}
 
+	emitResult(c, deviceInfo)
+
 	// Display basic device information
-	fmt.Printf("Device Information:\n")
-	fmt.Printf("  Name: %s\n", deviceInfo.Name)
-	fmt.Printf("  Type: %s\n", deviceInfo.Type)
-	fmt.Printf("  Device ID: %s\n", deviceInfo.DeviceID)
+	output.Printf("Device Information:\n")
+	output.Printf("  Name: %s\n", deviceInfo.Name)
+	output.Printf("  Type: %s\n", deviceInfo.Type)
+	output.Printf("  Device ID: %s\n", deviceInfo.DeviceID)
 
 	if deviceInfo.MargeAccountUUID != "" {
-		fmt.Printf("  Account UUID: %s\n", deviceInfo.MargeAccountUUID)
+		output.Printf("  Account UUID: %s\n", deviceInfo.MargeAccountUUID)
 	}
 
 	if len(deviceInfo.NetworkInfo) > 0 {
-		fmt.Printf("  Network Info:\n")
+		output.Printf("  Network Info:\n")
 
 		for _, net := range deviceInfo.NetworkInfo {
-			fmt.Printf("    - Type: %s\n", net.Type)
-			fmt.Printf("      MAC Address: %s\n", net.MacAddress)
-			fmt.Printf("      IP Address: %s\n", net.IPAddress)
+			output.Printf("    - Type: %s\n", net.Type)
+			output.Printf("      MAC Address: %s\n", net.MacAddress)
+			output.Printf("      IP Address: %s\n", net.IPAddress)
 		}
 	}
 
 	if len(deviceInfo.Components) > 0 {
-		fmt.Printf("  Components:\n")
+		output.Printf("  Components:\n")
 
 		for _, component := range deviceInfo.Components {
-			fmt.Printf("    - Category: %s\n", component.ComponentCategory)
+			output.Printf("    - Category: %s\n", component.ComponentCategory)
 
 			if component.SoftwareVersion != "" {
-				fmt.Printf("      Software Version: %s\n", component.SoftwareVersion)
+				output.Printf("      Software Version: %s\n", component.SoftwareVersion)
 			}
 
 			if component.SerialNumber != "" {
-				fmt.Printf("      Serial Number: %s\n", component.SerialNumber)
+				output.Printf("      Serial Number: %s\n", component.SerialNumber)
 			}
 		}
 	}
@@ -79,7 +81,9 @@ func getDeviceName(c *cli.Context) error {
 		return fmt.Errorf("failed to get device name: %w", err)
 	}
 
-	fmt.Printf("Device Name: %s\n", name)
+	emitResult(c, name)
+
+	output.Printf("Device Name: %s\n", name)
 
 	return nil
 }
@@ -126,33 +130,35 @@ func getCapabilities(c *cli.Context) error {
 		return fmt.Errorf("failed to get capabilities: %w", err)
 	}
 
-	fmt.Printf("Device Capabilities:\n")
-	fmt.Printf("  Device ID: %s\n", capabilities.DeviceID)
+	emitResult(c, capabilities)
+
+	output.Printf("Device Capabilities:\n")
+	output.Printf("  Device ID: %s\n", capabilities.DeviceID)
 
 	// Network capabilities
 	networkCaps := capabilities.GetNetworkCapabilities()
 	if len(networkCaps) > 0 {
-		fmt.Printf("  Network Capabilities:\n")
+		output.Printf("  Network Capabilities:\n")
 
 		for _, cap := range networkCaps {
-			fmt.Printf("    - %s\n", cap)
+			output.Printf("    - %s\n", cap)
 		}
 	}
 
 	// Extended capabilities
 	capNames := capabilities.GetCapabilityNames()
 	if len(capNames) > 0 {
-		fmt.Printf("  Extended Capabilities:\n")
+		output.Printf("  Extended Capabilities:\n")
 
 		for _, capName := range capNames {
 			capability := capabilities.GetCapabilityByName(capName)
-			fmt.Printf("    - %s", capName)
+			output.Printf("    - %s", capName)
 
 			if capability.URL != "" {
-				fmt.Printf(" (%s)", capability.URL)
+				output.Printf(" (%s)", capability.URL)
 			}
 
-			fmt.Println()
+			output.Println()
 		}
 	}
 
@@ -175,34 +181,36 @@ func getPresets(c *cli.Context) error {
 		return fmt.Errorf("failed to get presets: %w", err)
 	}
 
-	fmt.Printf("Device Presets:\n")
+	emitResult(c, presets)
+
+	output.Printf("Device Presets:\n")
 
 	if len(presets.Preset) == 0 {
-		fmt.Printf("  No presets configured\n")
+		output.Printf("  No presets configured\n")
 		return nil
 	}
 
-	fmt.Printf("  Configured Presets:\n")
+	output.Printf("  Configured Presets:\n")
 
 	for _, preset := range presets.Preset {
-		fmt.Printf("    %d. %s\n", preset.ID, preset.GetDisplayName())
-		fmt.Printf("       Source: %s\n", preset.ContentItem.Source)
+		output.Printf("    %d. %s\n", preset.ID, preset.GetDisplayName())
+		output.Printf("       Source: %s\n", preset.ContentItem.Source)
 
 		if preset.ContentItem.SourceAccount != "" && preset.ContentItem.SourceAccount != preset.ContentItem.Source {
-			fmt.Printf("       Account: %s\n", preset.ContentItem.SourceAccount)
+			output.Printf("       Account: %s\n", preset.ContentItem.SourceAccount)
 		}
 
 		if preset.ContentItem.Location != "" {
-			fmt.Printf("       Location: %s\n", preset.ContentItem.Location)
+			output.Printf("       Location: %s\n", preset.ContentItem.Location)
 		}
 
 		// Show preset creation time if available
 		if preset.CreatedOn != nil && *preset.CreatedOn != 0 {
 			createdTime := time.Unix(*preset.CreatedOn, 0)
-			fmt.Printf("       Created: %s\n", createdTime.Format("2006-01-02 15:04:05"))
+			output.Printf("       Created: %s\n", createdTime.Format("2006-01-02 15:04:05"))
 		}
 
-		fmt.Println()
+		output.Println()
 	}
 
 	return nil
@@ -225,6 +233,8 @@ func getSupportedURLs(c *cli.Context) error {
 		return err
 	}
 
+	emitResult(c, supportedURLs)
+
 	printSupportedURLs(supportedURLs, c)
 
 	return nil
@@ -235,13 +245,13 @@ func printSupportedURLs(supportedURLs *models.SupportedURLsResponse, c *cli.Cont
 	verbose := c.Bool("verbose")
 	showFeatures := c.Bool("features")
 
-	fmt.Printf("Device Supported URLs:\n")
-	fmt.Printf("  Device ID: %s\n", supportedURLs.DeviceID)
-	fmt.Printf("  Total Endpoints: %d\n", supportedURLs.GetURLCount())
+	output.Printf("Device Supported URLs:\n")
+	output.Printf("  Device ID: %s\n", supportedURLs.DeviceID)
+	output.Printf("  Total Endpoints: %d\n", supportedURLs.GetURLCount())
 
 	// Show feature completeness score
 	completeness, supported, total := supportedURLs.GetFeatureCompleteness()
-	fmt.Printf("  Feature Coverage: %d%% (%d/%d features)\n\n", completeness, supported, total)
+	output.Printf("  Feature Coverage: %d%% (%d/%d features)\n\n", completeness, supported, total)
 
 	if showFeatures || (!verbose && !showFeatures) {
 		// Show feature mapping (default view)
@@ -249,20 +259,20 @@ func printSupportedURLs(supportedURLs *models.SupportedURLsResponse, c *cli.Cont
 	}
 
 	if verbose {
-		fmt.Println()
+		output.Println()
 		printDetailedEndpoints(supportedURLs)
 	}
 
 	if !showFeatures && !verbose {
-		fmt.Printf("\n💡 Options:\n")
-		fmt.Printf("   --features  Show detailed feature mapping and CLI commands\n")
-		fmt.Printf("   --verbose   Show complete endpoint list\n")
+		output.Printf("\n💡 Options:\n")
+		output.Printf("   --features  Show detailed feature mapping and CLI commands\n")
+		output.Printf("   --verbose   Show complete endpoint list\n")
 	}
 }
 
 // printFeatureMapping displays the feature-to-endpoint mapping
 func printFeatureMapping(supportedURLs *models.SupportedURLsResponse, verbose bool) {
-	fmt.Printf("🎯 Device Feature Support:\n\n")
+	output.Printf("🎯 Device Feature Support:\n\n")
 
 	// Get features organized by category
 	featuresByCategory := supportedURLs.GetFeaturesByCategory()
@@ -293,13 +303,13 @@ func printFeatureCategories(featuresByCategory map[string][]models.EndpointFeatu
 		}
 
 		emoji := categoryInfo[category]
-		fmt.Printf("%s %s (%d features):\n", emoji, category, len(features))
+		output.Printf("%s %s (%d features):\n", emoji, category, len(features))
 
 		for _, feature := range features {
 			printFeatureStatus(feature, supportedURLs, verbose)
 		}
 
-		fmt.Println()
+		output.Println()
 	}
 }
 
@@ -311,13 +321,13 @@ func printFeatureStatus(feature models.EndpointFeature, supportedURLs *models.Su
 		status = "⚠️" // Partial support
 	}
 
-	fmt.Printf("    %s %s", status, feature.Name)
+	output.Printf("    %s %s", status, feature.Name)
 
 	if feature.Essential {
-		fmt.Printf(" ⭐")
+		output.Printf(" ⭐")
 	}
 
-	fmt.Printf("\n")
+	output.Printf("\n")
 
 	if verbose {
 		printVerboseFeatureDetails(feature, supportedEndpoints)
@@ -337,37 +347,37 @@ func countSupportedEndpoints(feature models.EndpointFeature, supportedURLs *mode
 }
 
 func printVerboseFeatureDetails(feature models.EndpointFeature, supportedEndpoints int) {
-	fmt.Printf("        %s\n", feature.Description)
-	fmt.Printf("        CLI: %s\n", feature.CLICommand)
-	fmt.Printf("        Endpoints: %d/%d supported", supportedEndpoints, len(feature.Endpoints))
+	output.Printf("        %s\n", feature.Description)
+	output.Printf("        CLI: %s\n", feature.CLICommand)
+	output.Printf("        Endpoints: %d/%d supported", supportedEndpoints, len(feature.Endpoints))
 
 	if supportedEndpoints < len(feature.Endpoints) {
-		fmt.Printf(" (partial)")
+		output.Printf(" (partial)")
 	}
 
-	fmt.Printf("\n")
+	output.Printf("\n")
 }
 
 func printMissingEssentialFeatures(supportedURLs *models.SupportedURLsResponse) {
 	missingEssential := supportedURLs.GetMissingEssentialFeatures()
 	if len(missingEssential) > 0 {
-		fmt.Printf("⚠️  Missing Essential Features:\n")
+		output.Printf("⚠️  Missing Essential Features:\n")
 
 		for _, feature := range missingEssential {
-			fmt.Printf("    ❌ %s - %s\n", feature.Name, feature.Description)
+			output.Printf("    ❌ %s - %s\n", feature.Name, feature.Description)
 		}
 
-		fmt.Println()
+		output.Println()
 	}
 }
 
 func printPartiallyImplementedFeatures(supportedURLs *models.SupportedURLsResponse, verbose bool) {
 	partial := supportedURLs.GetPartiallyImplementedFeatures()
 	if len(partial) > 0 && verbose {
-		fmt.Printf("⚠️  Partially Supported Features:\n")
+		output.Printf("⚠️  Partially Supported Features:\n")
 
 		for _, feature := range partial {
-			fmt.Printf("    🟡 %s\n", feature.Name)
+			output.Printf("    🟡 %s\n", feature.Name)
 
 			for _, endpoint := range feature.Endpoints {
 				status := "❌"
@@ -375,72 +385,72 @@ func printPartiallyImplementedFeatures(supportedURLs *models.SupportedURLsRespon
 					status = "✅"
 				}
 
-				fmt.Printf("        %s %s\n", status, endpoint)
+				output.Printf("        %s %s\n", status, endpoint)
 			}
 		}
 
-		fmt.Println()
+		output.Println()
 	}
 }
 
 // printDetailedEndpoints shows the traditional endpoint listing
 func printDetailedEndpoints(supportedURLs *models.SupportedURLsResponse) {
-	fmt.Printf("📋 Detailed Endpoint Analysis:\n\n")
+	output.Printf("📋 Detailed Endpoint Analysis:\n\n")
 
 	// Show core functionality
 	coreURLs := supportedURLs.GetCoreURLs()
 	if len(coreURLs) > 0 {
-		fmt.Printf("🎮 Core Functionality (%d endpoints):\n", len(coreURLs))
+		output.Printf("🎮 Core Functionality (%d endpoints):\n", len(coreURLs))
 
 		for _, url := range coreURLs {
-			fmt.Printf("    • %s\n", url)
+			output.Printf("    • %s\n", url)
 		}
 
-		fmt.Println()
+		output.Println()
 	}
 
 	// Show streaming functionality
 	streamingURLs := supportedURLs.GetStreamingURLs()
 	if len(streamingURLs) > 0 {
-		fmt.Printf("📻 Streaming Services (%d endpoints):\n", len(streamingURLs))
+		output.Printf("📻 Streaming Services (%d endpoints):\n", len(streamingURLs))
 
 		for _, url := range streamingURLs {
-			fmt.Printf("    • %s\n", url)
+			output.Printf("    • %s\n", url)
 		}
 
-		fmt.Println()
+		output.Println()
 	}
 
 	// Show advanced audio functionality
 	advancedURLs := supportedURLs.GetAdvancedURLs()
 	if len(advancedURLs) > 0 {
-		fmt.Printf("🔧 Advanced Audio (%d endpoints):\n", len(advancedURLs))
+		output.Printf("🔧 Advanced Audio (%d endpoints):\n", len(advancedURLs))
 
 		for _, url := range advancedURLs {
-			fmt.Printf("    • %s\n", url)
+			output.Printf("    • %s\n", url)
 		}
 
-		fmt.Println()
+		output.Println()
 	}
 
 	// Show network functionality
 	networkURLs := supportedURLs.GetNetworkURLs()
 	if len(networkURLs) > 0 {
-		fmt.Printf("🌐 Network & Connectivity (%d endpoints):\n", len(networkURLs))
+		output.Printf("🌐 Network & Connectivity (%d endpoints):\n", len(networkURLs))
 
 		for _, url := range networkURLs {
-			fmt.Printf("    • %s\n", url)
+			output.Printf("    • %s\n", url)
 		}
 
-		fmt.Println()
+		output.Println()
 	}
 
 	// Show all supported URLs
-	fmt.Printf("📝 Complete Endpoint List:\n")
+	output.Printf("📝 Complete Endpoint List:\n")
 
 	allURLs := supportedURLs.GetURLs()
 	for i, url := range allURLs {
-		fmt.Printf("    %3d. %s\n", i+1, url)
+		output.Printf("    %3d. %s\n", i+1, url)
 	}
 }
 
@@ -461,6 +471,7 @@ func getDeviceAnalysis(c *cli.Context) error {
 		return err
 	}
 
+	emitResult(c, newDeviceAnalysis(supportedURLs))
 	printDeviceAnalysis(supportedURLs)
 
 	return nil
@@ -468,35 +479,35 @@ func getDeviceAnalysis(c *cli.Context) error {
 
 // printDeviceAnalysis provides comprehensive device capability analysis
 func printDeviceAnalysis(supportedURLs *models.SupportedURLsResponse) {
-	fmt.Printf("🔍 Device Capability Analysis:\n")
-	fmt.Printf("  Device ID: %s\n", supportedURLs.DeviceID)
+	output.Printf("🔍 Device Capability Analysis:\n")
+	output.Printf("  Device ID: %s\n", supportedURLs.DeviceID)
 
 	// Overall score
 	completeness, supported, total := supportedURLs.GetFeatureCompleteness()
-	fmt.Printf("  Feature Coverage: %d%% (%d/%d features)\n", completeness, supported, total)
+	output.Printf("  Feature Coverage: %d%% (%d/%d features)\n", completeness, supported, total)
 
 	// Device classification
 	classification := classifyDevice(supportedURLs)
-	fmt.Printf("  Device Type: %s\n\n", classification)
+	output.Printf("  Device Type: %s\n\n", classification)
 
 	// Essential features check
 	missingEssential := supportedURLs.GetMissingEssentialFeatures()
 	if len(missingEssential) > 0 {
-		fmt.Printf("❌ Missing Essential Features:\n")
+		output.Printf("❌ Missing Essential Features:\n")
 
 		for _, feature := range missingEssential {
-			fmt.Printf("    • %s - %s\n", feature.Name, feature.Description)
-			fmt.Printf("      Impact: Device may not function properly without this\n")
+			output.Printf("    • %s - %s\n", feature.Name, feature.Description)
+			output.Printf("      Impact: Device may not function properly without this\n")
 		}
 
-		fmt.Println()
+		output.Println()
 	} else {
-		fmt.Printf("✅ All essential features are supported\n\n")
+		output.Printf("✅ All essential features are supported\n\n")
 	}
 
 	// Show what works
 	supportedFeatures := supportedURLs.GetSupportedFeatures()
-	fmt.Printf("✅ Available Features (%d):\n", len(supportedFeatures))
+	output.Printf("✅ Available Features (%d):\n", len(supportedFeatures))
 
 	categoryCount := make(map[string]int)
 	for _, feature := range supportedFeatures {
@@ -505,27 +516,27 @@ func printDeviceAnalysis(supportedURLs *models.SupportedURLsResponse) {
 
 	for category, count := range categoryCount {
 		emoji := getCategoryEmoji(category)
-		fmt.Printf("    %s %s: %d features\n", emoji, category, count)
+		output.Printf("    %s %s: %d features\n", emoji, category, count)
 	}
 
-	fmt.Println()
+	output.Println()
 
 	// Show what's missing
 	unsupportedFeatures := supportedURLs.GetUnsupportedFeatures()
 	if len(unsupportedFeatures) > 0 {
-		fmt.Printf("❌ Unsupported Features (%d):\n", len(unsupportedFeatures))
+		output.Printf("❌ Unsupported Features (%d):\n", len(unsupportedFeatures))
 
 		for _, feature := range unsupportedFeatures {
-			fmt.Printf("    • %s - %s\n", feature.Name, feature.Description)
+			output.Printf("    • %s - %s\n", feature.Name, feature.Description)
 		}
 
-		fmt.Println()
+		output.Println()
 	}
 
 	// Partial implementations
 	partial := supportedURLs.GetPartiallyImplementedFeatures()
 	if len(partial) > 0 {
-		fmt.Printf("⚠️ Partially Supported Features (%d):\n", len(partial))
+		output.Printf("⚠️ Partially Supported Features (%d):\n", len(partial))
 
 		for _, feature := range partial {
 			supportedCount := 0
@@ -536,10 +547,10 @@ func printDeviceAnalysis(supportedURLs *models.SupportedURLsResponse) {
 				}
 			}
 
-			fmt.Printf("    • %s (%d/%d endpoints)\n", feature.Name, supportedCount, len(feature.Endpoints))
+			output.Printf("    • %s (%d/%d endpoints)\n", feature.Name, supportedCount, len(feature.Endpoints))
 		}
 
-		fmt.Println()
+		output.Println()
 	}
 
 	// Recommendations
@@ -549,6 +560,36 @@ func printDeviceAnalysis(supportedURLs *models.SupportedURLsResponse) {
 	printCLIUsageSuggestions(supportedURLs)
 }
 
+// deviceAnalysis is the structured output of analyze
+type deviceAnalysis struct {
+	DeviceID                   string                   `json:"deviceId"`
+	Classification             string                   `json:"classification"`
+	Completeness               int                      `json:"completeness"`
+	SupportedFeatureCount      int                      `json:"supportedFeatureCount"`
+	TotalFeatureCount          int                      `json:"totalFeatureCount"`
+	MissingEssential           []models.EndpointFeature `json:"missingEssential"`
+	SupportedFeatures          []models.EndpointFeature `json:"supportedFeatures"`
+	UnsupportedFeatures        []models.EndpointFeature `json:"unsupportedFeatures"`
+	PartiallySupportedFeatures []models.EndpointFeature `json:"partiallySupportedFeatures"`
+}
+
+// newDeviceAnalysis collects what printDeviceAnalysis shows
+func newDeviceAnalysis(supportedURLs *models.SupportedURLsResponse) deviceAnalysis {
+	completeness, supported, total := supportedURLs.GetFeatureCompleteness()
+
+	return deviceAnalysis{
+		DeviceID:                   supportedURLs.DeviceID,
+		Classification:             classifyDevice(supportedURLs),
+		Completeness:               completeness,
+		SupportedFeatureCount:      supported,
+		TotalFeatureCount:          total,
+		MissingEssential:           supportedURLs.GetMissingEssentialFeatures(),
+		SupportedFeatures:          supportedURLs.GetSupportedFeatures(),
+		UnsupportedFeatures:        supportedURLs.GetUnsupportedFeatures(),
+		PartiallySupportedFeatures: supportedURLs.GetPartiallyImplementedFeatures(),
+	}
+}
+
 // classifyDevice determines the device type based on supported features
 func classifyDevice(supportedURLs *models.SupportedURLsResponse) string {
 	if supportedURLs.HasMultiroomSupport() && supportedURLs.HasAdvancedAudioSupport() {
@@ -572,72 +613,72 @@ func classifyDevice(supportedURLs *models.SupportedURLsResponse) string {
 
 // printRecommendations provides usage recommendations based on device capabilities
 func printRecommendations(supportedURLs *models.SupportedURLsResponse) {
-	fmt.Printf("💡 Recommendations:\n")
+	output.Printf("💡 Recommendations:\n")
 
 	if supportedURLs.HasMultiroomSupport() {
-		fmt.Printf("    🏠 This device supports multiroom - you can create speaker groups\n")
-		fmt.Printf("       Try: soundtouch-cli zone create --master <this-device> --members <other-devices>\n")
+		output.Printf("    🏠 This device supports multiroom - you can create speaker groups\n")
+		output.Printf("       Try: soundtouch-cli zone create --master <this-device> --members <other-devices>\n")
 	}
 
 	if supportedURLs.HasPresetSupport() {
-		fmt.Printf("    ⭐ Save your favorite content as presets for quick access\n")
-		fmt.Printf("       Try: soundtouch-cli preset store-current --slot 1\n")
+		output.Printf("    ⭐ Save your favorite content as presets for quick access\n")
+		output.Printf("       Try: soundtouch-cli preset store-current --slot 1\n")
 	}
 
 	if supportedURLs.HasStreamingSupport() {
-		fmt.Printf("    📻 Browse and discover new content from streaming services\n")
-		fmt.Printf("       Try: soundtouch-cli browse tunein, station search-tunein --query jazz\n")
+		output.Printf("    📻 Browse and discover new content from streaming services\n")
+		output.Printf("       Try: soundtouch-cli browse tunein, station search-tunein --query jazz\n")
 	}
 
 	if supportedURLs.HasAdvancedAudioSupport() {
-		fmt.Printf("    🔧 Fine-tune your audio with advanced controls\n")
-		fmt.Printf("       Try: soundtouch-cli audio dsp get, audio tone get\n")
+		output.Printf("    🔧 Fine-tune your audio with advanced controls\n")
+		output.Printf("       Try: soundtouch-cli audio dsp get, audio tone get\n")
 	}
 
 	if !supportedURLs.HasURL("/bassCapabilities") {
-		fmt.Printf("    ⚠️  Device may have limited bass control options\n")
+		output.Printf("    ⚠️  Device may have limited bass control options\n")
 	}
 
 	if !supportedURLs.HasURL("/balance") {
-		fmt.Printf("    ⚠️  No balance control available on this device\n")
+		output.Printf("    ⚠️  No balance control available on this device\n")
 	}
 
-	fmt.Println()
+	output.Println()
 }
 
 // printCLIUsageSuggestions shows common CLI commands for this device
 func printCLIUsageSuggestions(supportedURLs *models.SupportedURLsResponse) {
-	fmt.Printf("🚀 Common Commands for This Device:\n")
+	output.Printf("🚀 Common Commands for This Device:\n")
 
 	// Always available
-	fmt.Printf("    • Get device info: soundtouch-cli info get\n")
-	fmt.Printf("    • Control volume: soundtouch-cli volume set --level 50\n")
+	output.Printf("    • Get device info: soundtouch-cli info get\n")
+	output.Printf("    • Control volume: soundtouch-cli volume set --level 50\n")
 
 	if supportedURLs.HasURL("/nowPlaying") {
-		fmt.Printf("    • Check what's playing: soundtouch-cli play now\n")
+		output.Printf("    • Check what's playing: soundtouch-cli play now\n")
 	}
 
 	if supportedURLs.HasURL("/sources") {
-		fmt.Printf("    • List audio sources: soundtouch-cli source list\n")
+		output.Printf("    • List audio sources: soundtouch-cli source list\n")
 	}
 
 	if supportedURLs.HasURL("/presets") {
-		fmt.Printf("    • Manage presets: soundtouch-cli preset list\n")
+		output.Printf("    • Manage presets: soundtouch-cli preset list\n")
 	}
 
 	if supportedURLs.HasURL("/bass") {
-		fmt.Printf("    • Adjust bass: soundtouch-cli bass set --level 5\n")
+		output.Printf("    • Adjust bass: soundtouch-cli bass set --level 5\n")
 	}
 
 	if supportedURLs.HasURL("/setZone") {
-		fmt.Printf("    • Create speaker group: soundtouch-cli zone create\n")
+		output.Printf("    • Create speaker group: soundtouch-cli zone create\n")
 	}
 
 	if supportedURLs.HasURL("/search") {
-		fmt.Printf("    • Search content: soundtouch-cli station search-tunein --query \"classic rock\"\n")
+		output.Printf("    • Search content: soundtouch-cli station search-tunein --query \"classic rock\"\n")
 	}
 
-	fmt.Println()
+	output.Println()
 }
 
 // getCategoryEmoji returns emoji for feature categories
@@ -665,8 +706,8 @@ func getTrackInfo(c *cli.Context) error {
 	clientConfig := GetClientConfig(c)
 	PrintDeviceHeader("Getting track information", clientConfig.Host, clientConfig.Port)
 
-	fmt.Println("⚠️  WARNING: /trackInfo endpoint times out on real devices.")
-	fmt.Println("   Use 'soundtouch-cli now' (playback status) command instead for track information.")
+	output.Println("⚠️  WARNING: /trackInfo endpoint times out on real devices.")
+	output.Println("   Use 'soundtouch-cli now' (playback status) command instead for track information.")
 
 	client, err := CreateSoundTouchClient(clientConfig)
 	if err != nil {
@@ -680,26 +721,28 @@ func getTrackInfo(c *cli.Context) error {
 		return err
 	}
 
-	fmt.Println("Track Information:")
-	fmt.Printf("  Source: %s\n", trackInfo.Source)
+	emitResult(c, trackInfo)
+
+	output.Println("Track Information:")
+	output.Printf("  Source: %s\n", trackInfo.Source)
 
 	if trackInfo.Track != "" {
-		fmt.Printf("  Track: %s\n", trackInfo.Track)
+		output.Printf("  Track: %s\n", trackInfo.Track)
 	}
 
 	if trackInfo.Artist != "" {
-		fmt.Printf("  Artist: %s\n", trackInfo.Artist)
+		output.Printf("  Artist: %s\n", trackInfo.Artist)
 	}
 
 	if trackInfo.Album != "" {
-		fmt.Printf("  Album: %s\n", trackInfo.Album)
+		output.Printf("  Album: %s\n", trackInfo.Album)
 	}
 
 	if trackInfo.StationName != "" {
-		fmt.Printf("  Station: %s\n", trackInfo.StationName)
+		output.Printf("  Station: %s\n", trackInfo.StationName)
 	}
 
-	fmt.Printf("  Play Status: %s\n", trackInfo.PlayStatus)
+	output.Printf("  Play Status: %s\n", trackInfo.PlayStatus)
 
 	return nil
 }
@@ -30,37 +30,39 @@ func introspectService(c *cli.Context) error {
 	PrintDeviceHeader(fmt.Sprintf("Getting introspect data for %s", source), clientConfig.Host, clientConfig.Port)
 
 	if sourceAccount != "" {
-		fmt.Printf("Source Account: %s\n", sourceAccount)
+		output.Printf("Source Account: %s\n", sourceAccount)
 	}
 
-	fmt.Println()
+	output.Println()
 
 	response, err := client.Introspect(source, sourceAccount)
 	if err != nil {
 		return fmt.Errorf("failed to get introspect data: %w", err)
 	}
 
+	emitResult(c, response)
+
 	// Print basic information
-	fmt.Printf("=== %s Service Introspect Data ===\n", source)
+	output.Printf("=== %s Service Introspect Data ===\n", source)
 	printIntrospectBasicInfo(response)
 
 	// Print service state
-	fmt.Printf("\n=== Service State ===\n")
+	output.Printf("\n=== Service State ===\n")
 	printIntrospectServiceState(response)
 
 	// Print capabilities
-	fmt.Printf("\n=== Service Capabilities ===\n")
+	output.Printf("\n=== Service Capabilities ===\n")
 	printIntrospectCapabilities(response)
 
 	// Print history information
 	if response.GetMaxHistorySize() > 0 {
-		fmt.Printf("\n=== Content History ===\n")
+		output.Printf("\n=== Content History ===\n")
 		printIntrospectHistory(response)
 	}
 
 	// Print technical details
 	if response.TokenLastChangedTimeSeconds > 0 || response.PlayStatusState != "" {
-		fmt.Printf("\n=== Technical Details ===\n")
+		output.Printf("\n=== Technical Details ===\n")
 		printIntrospectTechnicalDetails(response)
 	}
 
@@ -87,49 +89,51 @@ func introspectSpotify(c *cli.Context) error {
 	PrintDeviceHeader("Getting Spotify introspect data", clientConfig.Host, clientConfig.Port)
 
 	if sourceAccount != "" {
-		fmt.Printf("Spotify Account: %s\n", sourceAccount)
+		output.Printf("Spotify Account: %s\n", sourceAccount)
 	}
 
-	fmt.Println()
+	output.Println()
 
 	response, err := client.IntrospectSpotify(sourceAccount)
 	if err != nil {
 		return fmt.Errorf("failed to get Spotify introspect data: %w", err)
 	}
 
+	emitResult(c, response)
+
 	// Print Spotify-specific information
-	fmt.Printf("=== Spotify Service Introspect Data ===\n")
+	output.Printf("=== Spotify Service Introspect Data ===\n")
 	printIntrospectBasicInfo(response)
 
 	// Print service state with Spotify context
-	fmt.Printf("\n=== Spotify Service State ===\n")
+	output.Printf("\n=== Spotify Service State ===\n")
 	printIntrospectServiceState(response)
 
 	// Print Spotify capabilities
-	fmt.Printf("\n=== Spotify Service Capabilities ===\n")
+	output.Printf("\n=== Spotify Service Capabilities ===\n")
 	printIntrospectCapabilities(response)
 
 	// Show Spotify-specific recommendations
 	if response.IsInactive() {
-		fmt.Printf("\n💡 Spotify Setup Recommendations:\n")
+		output.Printf("\n💡 Spotify Setup Recommendations:\n")
 
 		if !response.HasUser() {
-			fmt.Printf("   • Sign in to your Spotify account on the device\n")
+			output.Printf("   • Sign in to your Spotify account on the device\n")
 		}
 
-		fmt.Printf("   • Use 'soundtouch-cli source select --source SPOTIFY' to activate Spotify\n")
-		fmt.Printf("   • Ensure you have Spotify Premium for full functionality\n")
+		output.Printf("   • Use 'soundtouch-cli source select --source SPOTIFY' to activate Spotify\n")
+		output.Printf("   • Ensure you have Spotify Premium for full functionality\n")
 	}
 
 	// Print history information
 	if response.GetMaxHistorySize() > 0 {
-		fmt.Printf("\n=== Spotify Content History ===\n")
+		output.Printf("\n=== Spotify Content History ===\n")
 		printIntrospectHistory(response)
 	}
 
 	// Print technical details
 	if response.TokenLastChangedTimeSeconds > 0 || response.PlayStatusState != "" {
-		fmt.Printf("\n=== Technical Details ===\n")
+		output.Printf("\n=== Technical Details ===\n")
 		printIntrospectTechnicalDetails(response)
 	}
 
@@ -161,39 +165,39 @@ func introspectAllServices(c *cli.Context) error {
 
 	for i, source := range servicesToCheck {
 		if i > 0 {
-			fmt.Println("\n" + strings.Repeat("─", 50))
+			output.Println("\n" + strings.Repeat("─", 50))
 		}
 
 		// Check if service is available
 		serviceType := sourceToServiceType(source)
 		if serviceType != "" && !serviceAvailability.IsServiceAvailable(serviceType) {
-			fmt.Printf("\n❌ %s: Service not available on this device\n", source)
+			output.Printf("\n❌ %s: Service not available on this device\n", source)
 			continue
 		}
 
-		fmt.Printf("\n🔍 Getting introspect data for %s...\n", source)
+		output.Printf("\n🔍 Getting introspect data for %s...\n", source)
 
 		response, err := client.Introspect(source, "")
 		if err != nil {
-			fmt.Printf("❌ %s: Failed to get introspect data - %v\n", source, err)
+			output.Printf("❌ %s: Failed to get introspect data - %v\n", source, err)
 
 			failCount++
 
 			continue
 		}
 
-		fmt.Printf("✅ %s: Successfully retrieved introspect data\n", source)
+		output.Printf("✅ %s: Successfully retrieved introspect data\n", source)
 		printIntrospectSummary(source, response)
 
 		successCount++
 	}
 
 	// Print summary
-	fmt.Print("\n" + strings.Repeat("═", 50) + "\n")
-	fmt.Printf("📊 Introspect Summary:\n")
-	fmt.Printf("   ✅ Successful: %d services\n", successCount)
-	fmt.Printf("   ❌ Failed: %d services\n", failCount)
-	fmt.Printf("   📡 Total checked: %d services\n", len(servicesToCheck))
+	output.Print("\n" + strings.Repeat("═", 50) + "\n")
+	output.Printf("📊 Introspect Summary:\n")
+	output.Printf("   ✅ Successful: %d services\n", successCount)
+	output.Printf("   ❌ Failed: %d services\n", failCount)
+	output.Printf("   📡 Total checked: %d services\n", len(servicesToCheck))
 
 	if successCount > 0 {
 		PrintSuccess(fmt.Sprintf("Successfully retrieved introspect data for %d services", successCount))
@@ -204,50 +208,50 @@ func introspectAllServices(c *cli.Context) error {
 
 // printIntrospectBasicInfo prints basic introspect information
 func printIntrospectBasicInfo(response *models.IntrospectResponse) {
-	fmt.Printf("State: %s\n", response.State)
+	output.Printf("State: %s\n", response.State)
 
 	if response.HasUser() {
-		fmt.Printf("User: %s\n", response.User)
+		output.Printf("User: %s\n", response.User)
 	}
 
-	fmt.Printf("Currently Playing: %s\n", formatBooleanStatus(response.IsPlaying))
+	output.Printf("Currently Playing: %s\n", formatBooleanStatus(response.IsPlaying))
 
 	if response.HasCurrentContent() {
-		fmt.Printf("Current Content: %s\n", response.CurrentURI)
+		output.Printf("Current Content: %s\n", response.CurrentURI)
 	}
 
-	fmt.Printf("Shuffle Mode: %s\n", response.ShuffleMode)
+	output.Printf("Shuffle Mode: %s\n", response.ShuffleMode)
 
 	if response.HasSubscription() {
-		fmt.Printf("Subscription Type: %s\n", response.SubscriptionType)
+		output.Printf("Subscription Type: %s\n", response.SubscriptionType)
 	}
 }
 
 // printIntrospectServiceState prints service state information
 func printIntrospectServiceState(response *models.IntrospectResponse) {
 	if response.IsActive() {
-		fmt.Printf("✅ Service is ACTIVE\n")
+		output.Printf("✅ Service is ACTIVE\n")
 	} else if response.IsInactive() {
-		fmt.Printf("❌ Service is INACTIVE")
+		output.Printf("❌ Service is INACTIVE")
 
 		if response.GetState() == models.IntrospectStateInactiveUnselected {
-			fmt.Printf(" (Never been used)")
+			output.Printf(" (Never been used)")
 		}
 
-		fmt.Println()
+		output.Println()
 	}
 
 	// Additional state information
 	if response.IsPlaying {
-		fmt.Printf("🎵 Currently playing content\n")
+		output.Printf("🎵 Currently playing content\n")
 	} else {
-		fmt.Printf("⏸️  Not currently playing\n")
+		output.Printf("⏸️  Not currently playing\n")
 	}
 
 	if response.IsShuffleEnabled() {
-		fmt.Printf("🔀 Shuffle mode is ON\n")
+		output.Printf("🔀 Shuffle mode is ON\n")
 	} else {
-		fmt.Printf("➡️  Shuffle mode is OFF\n")
+		output.Printf("➡️  Shuffle mode is OFF\n")
 	}
 }
 
@@ -269,20 +273,20 @@ func printIntrospectCapabilities(response *models.IntrospectResponse) {
 			status = "✅"
 		}
 
-		fmt.Printf("%s %s %s\n", status, cap.icon, cap.feature)
+		output.Printf("%s %s %s\n", status, cap.icon, cap.feature)
 	}
 
 	// Data collection status
 	if response.CollectsData() {
-		fmt.Printf("📊 Data collection: ENABLED\n")
+		output.Printf("📊 Data collection: ENABLED\n")
 	} else {
-		fmt.Printf("🚫 Data collection: DISABLED\n")
+		output.Printf("🚫 Data collection: DISABLED\n")
 	}
 }
 
 // printIntrospectHistory prints content history information
 func printIntrospectHistory(response *models.IntrospectResponse) {
-	fmt.Printf("Max History Size: %d items\n", response.GetMaxHistorySize())
+	output.Printf("Max History Size: %d items\n", response.GetMaxHistorySize())
 }
 
 // printIntrospectTechnicalDetails prints technical details
@@ -290,42 +294,42 @@ func printIntrospectTechnicalDetails(response *models.IntrospectResponse) {
 	if response.TokenLastChangedTimeSeconds > 0 {
 		// Convert timestamp to readable format
 		tokenTime := time.Unix(response.TokenLastChangedTimeSeconds, 0)
-		fmt.Printf("Token Last Changed: %s\n", tokenTime.Format("2006-01-02 15:04:05 MST"))
-		fmt.Printf("Token Timestamp: %d seconds since Unix epoch\n", response.TokenLastChangedTimeSeconds)
+		output.Printf("Token Last Changed: %s\n", tokenTime.Format("2006-01-02 15:04:05 MST"))
+		output.Printf("Token Timestamp: %d seconds since Unix epoch\n", response.TokenLastChangedTimeSeconds)
 
 		if response.TokenLastChangedTimeMicroseconds > 0 {
-			fmt.Printf("Token Microseconds: %d\n", response.TokenLastChangedTimeMicroseconds)
+			output.Printf("Token Microseconds: %d\n", response.TokenLastChangedTimeMicroseconds)
 		}
 	}
 
 	if response.PlayStatusState != "" {
-		fmt.Printf("Play Status State: %s\n", response.PlayStatusState)
+		output.Printf("Play Status State: %s\n", response.PlayStatusState)
 	}
 
-	fmt.Printf("Received Playback Request: %s\n", formatBooleanStatus(response.ReceivedPlaybackRequest))
+	output.Printf("Received Playback Request: %s\n", formatBooleanStatus(response.ReceivedPlaybackRequest))
 }
 
 // printIntrospectSummary prints a brief summary for the "all" command
 func printIntrospectSummary(_ string, response *models.IntrospectResponse) {
-	fmt.Printf("   State: %s", response.State)
+	output.Printf("   State: %s", response.State)
 
 	if response.HasUser() {
-		fmt.Printf(" (User: %s)", response.User)
+		output.Printf(" (User: %s)", response.User)
 	}
 
-	fmt.Println()
+	output.Println()
 
-	fmt.Printf("   Playing: %s", formatBooleanStatus(response.IsPlaying))
+	output.Printf("   Playing: %s", formatBooleanStatus(response.IsPlaying))
 
 	if response.HasCurrentContent() {
-		fmt.Printf(" | Content: %.50s", response.CurrentURI)
+		output.Printf(" | Content: %.50s", response.CurrentURI)
 
 		if len(response.CurrentURI) > 50 {
-			fmt.Printf("...")
+			output.Printf("...")
 		}
 	}
 
-	fmt.Println()
+	output.Println()
 
 	var capabilities []string
 	if response.SupportsSkipPrevious() {
@@ -341,9 +345,9 @@ func printIntrospectSummary(_ string, response *models.IntrospectResponse) {
 	}
 
 	if len(capabilities) > 0 {
-		fmt.Printf("   Capabilities: %s\n", strings.Join(capabilities, ", "))
+		output.Printf("   Capabilities: %s\n", strings.Join(capabilities, ", "))
 	} else {
-		fmt.Printf("   Capabilities: None\n")
+		output.Printf("   Capabilities: None\n")
 	}
 }
 
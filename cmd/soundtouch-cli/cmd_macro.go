@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/gesellix/bose-soundtouch/pkg/client"
+	"github.com/urfave/cli/v2"
+)
+
+// holdKey presses a key and releases it after the given duration
+func holdKey(c *cli.Context) error {
+	key := strings.ToUpper(c.String("key"))
+	duration := c.Duration("duration")
+	clientConfig := GetClientConfig(c)
+	PrintDeviceHeader(fmt.Sprintf("Holding %s key for %v", key, duration), clientConfig.Host, clientConfig.Port)
+
+	soundTouchClient, err := CreateSoundTouchClient(clientConfig)
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to create client: %v", err))
+		return err
+	}
+
+	// Interrupting the hold still releases the key
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	err = soundTouchClient.HoldKeyContext(ctx, key, duration)
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to hold key: %v", err))
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("%s key held for %v", key, duration))
+
+	return nil
+}
+
+// runKeyMacro runs the key sequence from a macro file
+func runKeyMacro(c *cli.Context) error {
+	path := c.String("file")
+
+	file, err := os.Open(path)
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to open macro file: %v", err))
+		return err
+	}
+
+	defer func() { _ = file.Close() }()
+
+	macro, err := client.ParseMacro(file)
+	if err != nil {
+		PrintError(fmt.Sprintf("Invalid macro %s: %v", path, err))
+		return err
+	}
+
+	title := macro.Name
+	if title == "" {
+		title = path
+	}
+
+	clientConfig := GetClientConfig(c)
+	PrintDeviceHeader(fmt.Sprintf("Running macro '%s' (%d steps)", title, len(macro.Steps)), clientConfig.Host, clientConfig.Port)
+
+	if c.Bool("dry-run") {
+		for i, step := range macro.Steps {
+			output.Printf("  %2d. %s\n", i+1, step)
+		}
+
+		return nil
+	}
+
+	soundTouchClient, err := CreateSoundTouchClient(clientConfig)
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to create client: %v", err))
+		return err
+	}
+
+	runner := client.NewMacroRunner(soundTouchClient)
+	runner.OnStep(func(index int, step client.MacroStep) {
+		output.Printf("  %2d. %s\n", index+1, step)
+	})
+
+	if macroNeedsEvents(macro) {
+		wsClient := setupWebSocketClient(soundTouchClient, false, false)
+		if err := wsClient.Connect(); err != nil {
+			PrintError(fmt.Sprintf("Failed to connect to WebSocket: %v", err))
+			return err
+		}
+
+		defer func() { _ = wsClient.Disconnect() }()
+
+		runner.SetWebSocket(wsClient)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	if err := runner.Run(ctx, macro); err != nil {
+		PrintError(fmt.Sprintf("Macro failed: %v", err))
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Macro '%s' completed", title))
+
+	return nil
+}
+
+// macroNeedsEvents reports whether the macro waits for WebSocket events
+func macroNeedsEvents(macro *client.Macro) bool {
+	for _, step := range macro.Steps {
+		if step.Action == client.MacroWaitFor {
+			return true
+		}
+	}
+
+	return false
+}
@@ -29,6 +29,8 @@ func browseContent(c *cli.Context) error {
 		return err
 	}
 
+	emitResult(c, response)
+
 	printNavigationResults(response, "Content")
 
 	return nil
@@ -58,6 +60,8 @@ func browseWithMenu(c *cli.Context) error {
 		return err
 	}
 
+	emitResult(c, response)
+
 	printNavigationResults(response, "Menu Items")
 
 	return nil
@@ -94,6 +98,8 @@ func browseContainer(c *cli.Context) error {
 		return err
 	}
 
+	emitResult(c, response)
+
 	printNavigationResults(response, "Container Contents")
 
 	return nil
@@ -120,6 +126,8 @@ func browseTuneIn(c *cli.Context) error {
 		return err
 	}
 
+	emitResult(c, response)
+
 	// Apply pagination if different from defaults
 	if startItem != 1 || numItems != 100 {
 		response, err = client.Navigate("TUNEIN", sourceAccount, startItem, numItems)
@@ -158,6 +166,8 @@ func browsePandora(c *cli.Context) error {
 		return err
 	}
 
+	emitResult(c, response)
+
 	printNavigationResults(response, "Pandora Stations")
 
 	return nil
@@ -187,6 +197,8 @@ func browseStoredMusic(c *cli.Context) error {
 		return err
 	}
 
+	emitResult(c, response)
+
 	printNavigationResults(response, "Stored Music Library")
 
 	return nil
@@ -194,21 +206,21 @@ func browseStoredMusic(c *cli.Context) error {
 
 // printNavigationResults formats and displays navigation results
 func printNavigationResults(response *models.NavigateResponse, title string) {
-	fmt.Printf("%s:\n", title)
+	output.Printf("%s:\n", title)
 
 	if response.TotalItems == 0 {
-		fmt.Printf("  No items found\n")
+		output.Printf("  No items found\n")
 		return
 	}
 
-	fmt.Printf("  Total items: %d\n", response.TotalItems)
+	output.Printf("  Total items: %d\n", response.TotalItems)
 
 	if len(response.Items) == 0 {
-		fmt.Printf("  No items in current page\n")
+		output.Printf("  No items in current page\n")
 		return
 	}
 
-	fmt.Printf("  Items:\n")
+	output.Printf("  Items:\n")
 
 	for i, item := range response.Items {
 		printNavigationItem(item, i+1, response.Source)
@@ -219,13 +231,13 @@ func printNavigationResults(response *models.NavigateResponse, title string) {
 
 // printNavigationItem prints a single navigation item with its metadata
 func printNavigationItem(item models.NavigateItem, index int, responseSource string) {
-	fmt.Printf("    %d. %s\n", index, item.GetDisplayName())
+	output.Printf("    %d. %s\n", index, item.GetDisplayName())
 
 	printContentItemInfo(item, responseSource)
 	printItemMetadata(item)
 	printItemType(item)
 
-	fmt.Println()
+	output.Println()
 }
 
 // printContentItemInfo prints content item information (source, type, location)
@@ -235,35 +247,35 @@ func printContentItemInfo(item models.NavigateItem, responseSource string) {
 	}
 
 	if item.ContentItem.Source != "" && item.ContentItem.Source != responseSource {
-		fmt.Printf("       Source: %s\n", item.ContentItem.Source)
+		output.Printf("       Source: %s\n", item.ContentItem.Source)
 	}
 
 	if item.Type != "" {
-		fmt.Printf("       Type: %s\n", item.Type)
+		output.Printf("       Type: %s\n", item.Type)
 	}
 
 	if item.ContentItem.Location != "" && len(item.ContentItem.Location) < 100 {
-		fmt.Printf("       Location: %s\n", item.ContentItem.Location)
+		output.Printf("       Location: %s\n", item.ContentItem.Location)
 	}
 }
 
 // printItemMetadata prints additional metadata (artist, album)
 func printItemMetadata(item models.NavigateItem) {
 	if item.ArtistName != "" {
-		fmt.Printf("       Artist: %s\n", item.ArtistName)
+		output.Printf("       Artist: %s\n", item.ArtistName)
 	}
 
 	if item.AlbumName != "" {
-		fmt.Printf("       Album: %s\n", item.AlbumName)
+		output.Printf("       Album: %s\n", item.AlbumName)
 	}
 }
 
 // printItemType prints whether the item is a directory or playable
 func printItemType(item models.NavigateItem) {
 	if item.IsDirectory() {
-		fmt.Printf("       📁 Directory (can browse into)\n")
+		output.Printf("       📁 Directory (can browse into)\n")
 	} else if item.IsPlayable() {
-		fmt.Printf("       ▶️  Playable content\n")
+		output.Printf("       ▶️  Playable content\n")
 	}
 }
 
@@ -271,11 +283,11 @@ func printItemType(item models.NavigateItem) {
 func printNavigationHints(response *models.NavigateResponse) {
 	directories := response.GetDirectories()
 	if len(directories) > 0 {
-		fmt.Printf("  💡 To browse into a directory, use: browse container --location <location> --type <type>\n")
+		output.Printf("  💡 To browse into a directory, use: browse container --location <location> --type <type>\n")
 	}
 
 	playableItems := response.GetPlayableItems()
 	if len(playableItems) > 0 {
-		fmt.Printf("  💡 Found %d playable items\n", len(playableItems))
+		output.Printf("  💡 Found %d playable items\n", len(playableItems))
 	}
 }
@@ -8,38 +8,38 @@ import (
 )
 
 func printNetworkInterface(i int, iface *models.NetworkInterface) {
-	fmt.Printf("\n  Interface %d:\n", i+1)
-	fmt.Printf("    Type: %s\n", iface.GetType())
+	output.Printf("\n  Interface %d:\n", i+1)
+	output.Printf("    Type: %s\n", iface.GetType())
 
 	if iface.GetName() != "" {
-		fmt.Printf("    Name: %s\n", iface.GetName())
+		output.Printf("    Name: %s\n", iface.GetName())
 	}
 
 	if iface.GetIPAddress() != "" {
-		fmt.Printf("    IP Address: %s\n", iface.GetIPAddress())
+		output.Printf("    IP Address: %s\n", iface.GetIPAddress())
 	}
 
 	if iface.GetMacAddress() != "" {
-		fmt.Printf("    MAC Address: %s\n", iface.GetMacAddress())
+		output.Printf("    MAC Address: %s\n", iface.GetMacAddress())
 	}
 
-	fmt.Printf("    State: %s\n", iface.GetStateDescription())
+	output.Printf("    State: %s\n", iface.GetStateDescription())
 
 	if iface.IsWiFi() {
 		if iface.GetSSID() != "" {
-			fmt.Printf("    SSID: %s\n", iface.GetSSID())
+			output.Printf("    SSID: %s\n", iface.GetSSID())
 		}
 
 		if iface.GetSignal() != "" {
-			fmt.Printf("    Signal: %s (%d%%)\n", iface.GetSignalDescription(), iface.GetSignalQuality())
+			output.Printf("    Signal: %s (%d%%)\n", iface.GetSignalDescription(), iface.GetSignalQuality())
 		}
 
 		if iface.GetFrequencyKHz() > 0 {
-			fmt.Printf("    Frequency: %s (%s)\n", iface.FormatFrequency(), iface.GetFrequencyBand())
+			output.Printf("    Frequency: %s (%s)\n", iface.FormatFrequency(), iface.GetFrequencyBand())
 		}
 
 		if iface.GetMode() != "" {
-			fmt.Printf("    Mode: %s\n", iface.GetModeDescription())
+			output.Printf("    Mode: %s\n", iface.GetModeDescription())
 		}
 	}
 }
@@ -61,19 +61,21 @@ func getNetworkInfo(c *cli.Context) error {
 		return err
 	}
 
-	fmt.Println("Network Information:")
+	emitResult(c, networkInfo)
+
+	output.Println("Network Information:")
 
 	if networkInfo.GetWifiProfileCount() > 0 {
-		fmt.Printf("  WiFi Profiles: %d\n", networkInfo.GetWifiProfileCount())
+		output.Printf("  WiFi Profiles: %d\n", networkInfo.GetWifiProfileCount())
 	}
 
 	interfaces := networkInfo.GetInterfaces()
 	if len(interfaces) == 0 {
-		fmt.Println("  No network interfaces found")
+		output.Println("  No network interfaces found")
 		return nil
 	}
 
-	fmt.Printf("  Interfaces (%d):\n", len(interfaces))
+	output.Printf("  Interfaces (%d):\n", len(interfaces))
 
 	for i := range interfaces {
 		printNetworkInterface(i, &interfaces[i])
@@ -82,11 +84,11 @@ func getNetworkInfo(c *cli.Context) error {
 	// Show active connections summary
 	activeInterfaces := networkInfo.GetActiveInterfaces()
 	if len(activeInterfaces) > 0 {
-		fmt.Println("\n  Active Connections:")
+		output.Println("\n  Active Connections:")
 
 		for i := range activeInterfaces {
 			iface := &activeInterfaces[i]
-			fmt.Printf("    - %s: %s\n", iface.GetType(), iface.GetNetworkSummary())
+			output.Printf("    - %s: %s\n", iface.GetType(), iface.GetNetworkSummary())
 		}
 	}
 
@@ -126,7 +128,7 @@ func getDeviceURL(c *cli.Context) error {
 	}
 
 	baseURL := client.BaseURL()
-	fmt.Printf("Device URL: %s\n", baseURL)
+	output.Printf("Device URL: %s\n", baseURL)
 
 	return nil
 }
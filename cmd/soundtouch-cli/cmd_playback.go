@@ -1,9 +1,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
+	"github.com/gesellix/bose-soundtouch/pkg/client"
 	"github.com/gesellix/bose-soundtouch/pkg/models"
 	"github.com/urfave/cli/v2"
 )
@@ -24,11 +29,17 @@ func getNowPlaying(c *cli.Context) error {
 		return fmt.Errorf("failed to get now playing: %w", err)
 	}
 
-	fmt.Printf("Now Playing:\n")
-	fmt.Printf("  Device ID: %s\n", nowPlaying.DeviceID)
+	emitResult(c, nowPlaying)
+
+	if c.Bool("follow") {
+		return followNowPlaying(client, nowPlaying)
+	}
+
+	output.Printf("Now Playing:\n")
+	output.Printf("  Device ID: %s\n", nowPlaying.DeviceID)
 
 	if nowPlaying.IsEmpty() {
-		fmt.Printf("  Status: No content playing\n")
+		output.Printf("  Status: No content playing\n")
 		return nil
 	}
 
@@ -44,27 +55,27 @@ func getNowPlaying(c *cli.Context) error {
 
 // printBasicPlaybackInfo prints basic source and status information
 func printBasicPlaybackInfo(nowPlaying *models.NowPlaying) {
-	fmt.Printf("  Source: %s\n", nowPlaying.Source)
+	output.Printf("  Source: %s\n", nowPlaying.Source)
 
 	if nowPlaying.SourceAccount != "" {
-		fmt.Printf("  Source Account: %s\n", nowPlaying.SourceAccount)
+		output.Printf("  Source Account: %s\n", nowPlaying.SourceAccount)
 	}
 
-	fmt.Printf("  Status: %s\n", nowPlaying.PlayStatus.String())
+	output.Printf("  Status: %s\n", nowPlaying.PlayStatus.String())
 }
 
 // printTrackInfo prints track, artist, and album information
 func printTrackInfo(nowPlaying *models.NowPlaying) {
 	if nowPlaying.Track != "" {
-		fmt.Printf("  Track: %s\n", nowPlaying.Track)
+		output.Printf("  Track: %s\n", nowPlaying.Track)
 	}
 
 	if nowPlaying.Artist != "" {
-		fmt.Printf("  Artist: %s\n", nowPlaying.Artist)
+		output.Printf("  Artist: %s\n", nowPlaying.Artist)
 	}
 
 	if nowPlaying.Album != "" {
-		fmt.Printf("  Album: %s\n", nowPlaying.Album)
+		output.Printf("  Album: %s\n", nowPlaying.Album)
 	}
 }
 
@@ -74,17 +85,17 @@ func printTimeInfo(nowPlaying *models.NowPlaying) {
 		return
 	}
 
-	fmt.Printf("  Duration: %s\n", nowPlaying.FormatDuration())
+	output.Printf("  Duration: %s\n", nowPlaying.FormatDuration())
 
 	if nowPlaying.Position != nil {
-		fmt.Printf("  Position: %s\n", nowPlaying.FormatPosition())
+		output.Printf("  Position: %s\n", nowPlaying.FormatPosition())
 	}
 }
 
 // printStreamInfo prints stream type information
 func printStreamInfo(nowPlaying *models.NowPlaying) {
 	if nowPlaying.StreamType != "" {
-		fmt.Printf("  Stream Type: %s\n", nowPlaying.StreamType)
+		output.Printf("  Stream Type: %s\n", nowPlaying.StreamType)
 	}
 }
 
@@ -99,7 +110,7 @@ func printContentDetails(nowPlaying *models.NowPlaying, verbose bool) {
 		return
 	}
 
-	fmt.Printf("\nContent Details:\n")
+	output.Printf("\nContent Details:\n")
 	printContentLocation(nowPlaying.ContentItem)
 	printVerboseContentInfo(nowPlaying, verbose)
 
@@ -111,7 +122,7 @@ func printContentDetails(nowPlaying *models.NowPlaying, verbose bool) {
 // printContentLocation prints the content location
 func printContentLocation(contentItem *models.ContentItem) {
 	if contentItem.Location != "" {
-		fmt.Printf("  Location: %s\n", contentItem.Location)
+		output.Printf("  Location: %s\n", contentItem.Location)
 	}
 }
 
@@ -122,62 +133,147 @@ func printVerboseContentInfo(nowPlaying *models.NowPlaying, verbose bool) {
 	}
 
 	if nowPlaying.ContentItem.Type != "" {
-		fmt.Printf("  Content Type: %s\n", nowPlaying.ContentItem.Type)
+		output.Printf("  Content Type: %s\n", nowPlaying.ContentItem.Type)
 	}
 
 	if nowPlaying.ContentItem.ItemName != "" && nowPlaying.ContentItem.ItemName != nowPlaying.Track {
-		fmt.Printf("  Item Name: %s\n", nowPlaying.ContentItem.ItemName)
+		output.Printf("  Item Name: %s\n", nowPlaying.ContentItem.ItemName)
 	}
 
 	if nowPlaying.ContentItem.ContainerArt != "" {
-		fmt.Printf("  Container Art: %s\n", nowPlaying.ContentItem.ContainerArt)
+		output.Printf("  Container Art: %s\n", nowPlaying.ContentItem.ContainerArt)
 	}
 
-	fmt.Printf("  Presetable: %t\n", nowPlaying.ContentItem.IsPresetable)
+	output.Printf("  Presetable: %t\n", nowPlaying.ContentItem.IsPresetable)
 }
 
 // printVerbosePlaybackDetails prints detailed playback information in verbose mode
 func printVerbosePlaybackDetails(nowPlaying *models.NowPlaying) {
-	fmt.Printf("\nPlayback Details:\n")
+	output.Printf("\nPlayback Details:\n")
 
 	// Shuffle and repeat settings
 	if nowPlaying.ShuffleSetting != "" {
-		fmt.Printf("  Shuffle: %s\n", nowPlaying.ShuffleSetting.String())
+		output.Printf("  Shuffle: %s\n", nowPlaying.ShuffleSetting.String())
 	}
 
 	if nowPlaying.RepeatSetting != "" {
-		fmt.Printf("  Repeat: %s\n", nowPlaying.RepeatSetting.String())
+		output.Printf("  Repeat: %s\n", nowPlaying.RepeatSetting.String())
 	}
 
 	// Track ID
 	if nowPlaying.TrackID != "" {
-		fmt.Printf("  Track ID: %s\n", nowPlaying.TrackID)
+		output.Printf("  Track ID: %s\n", nowPlaying.TrackID)
 	}
 
 	// Art details
 	if nowPlaying.Art != nil {
-		fmt.Printf("  Art Image Status: %s\n", nowPlaying.Art.ArtImageStatus)
+		output.Printf("  Art Image Status: %s\n", nowPlaying.Art.ArtImageStatus)
 
 		if nowPlaying.Art.URL != "" {
-			fmt.Printf("  Art URL: %s\n", nowPlaying.Art.URL)
+			output.Printf("  Art URL: %s\n", nowPlaying.Art.URL)
 		}
 	}
 
 	// Capabilities
-	fmt.Printf("\nCapabilities:\n")
-	fmt.Printf("  Skip Enabled: %t\n", nowPlaying.CanSkip())
-	fmt.Printf("  Skip Previous Enabled: %t\n", nowPlaying.CanSkipPrevious())
-	fmt.Printf("  Favorite Enabled: %t\n", nowPlaying.CanFavorite())
-	fmt.Printf("  Seek Supported: %t\n", nowPlaying.IsSeekSupported())
+	output.Printf("\nCapabilities:\n")
+	output.Printf("  Skip Enabled: %t\n", nowPlaying.CanSkip())
+	output.Printf("  Skip Previous Enabled: %t\n", nowPlaying.CanSkipPrevious())
+	output.Printf("  Favorite Enabled: %t\n", nowPlaying.CanFavorite())
+	output.Printf("  Seek Supported: %t\n", nowPlaying.IsSeekSupported())
 }
 
 // printPlaybackStatus prints special status messages
 func printPlaybackStatus(nowPlaying *models.NowPlaying) {
 	if nowPlaying.PlayStatus == models.PlayStatusBuffering {
-		fmt.Printf("\nNote: Content is buffering\n")
+		output.Printf("\nNote: Content is buffering\n")
+	}
+}
+
+// followNowPlaying shows a live progress bar fed by now playing events until interrupted
+func followNowPlaying(soundTouchClient *client.Client, initial *models.NowPlaying) error {
+	tracker := client.NewProgressTracker()
+	tracker.OnProgressEvent(func(event client.ProgressEvent) {
+		switch event.Type {
+		case client.ProgressTrackChanged:
+			output.Printf("\n🎵 %s\n", formatProgressTitle(event.Progress))
+		case client.ProgressEndingSoon:
+			output.Printf("\n⏳ Track ending in %s\n", formatProgressTime(event.Progress.Remaining()))
+		case client.ProgressFinished:
+			output.Printf("\n✅ Track finished\n")
+		case client.ProgressSeeked:
+			output.Printf("\n⏩ Seeked to %s\n", formatProgressTime(event.Progress.Position))
+		}
+	})
+	tracker.Update(initial)
+
+	wsClient := setupWebSocketClient(soundTouchClient, true, false)
+	tracker.Attach(wsClient)
+
+	if err := wsClient.Connect(); err != nil {
+		return fmt.Errorf("failed to connect to WebSocket: %w", err)
+	}
+
+	defer func() { _ = wsClient.Disconnect() }()
+
+	output.Printf("🎵 %s\n", formatProgressTitle(tracker.Progress()))
+	output.Println("⏸️  Press Ctrl+C to stop")
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			output.Println()
+			return nil
+		case <-ticker.C:
+			tracker.Tick()
+			output.Printf("\r%s\033[K", renderProgressLine(tracker.Progress(), 30))
+		}
 	}
 }
 
+// formatProgressTitle returns "Track — Artist" for display
+func formatProgressTitle(progress client.Progress) string {
+	if progress.Artist == "" {
+		return progress.Track
+	}
+
+	return fmt.Sprintf("%s — %s", progress.Track, progress.Artist)
+}
+
+// renderProgressLine renders a status icon, a progress bar of the given width and the times
+func renderProgressLine(progress client.Progress, width int) string {
+	icon := "⏸️ "
+	if progress.Playing {
+		icon = "▶️ "
+	}
+
+	if !progress.HasTime || progress.Total <= 0 {
+		if progress.HasTime {
+			return fmt.Sprintf("%s %s", icon, formatProgressTime(progress.Position))
+		}
+
+		return fmt.Sprintf("%s (live)", icon)
+	}
+
+	filled := int(progress.Fraction() * float64(width))
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+
+	return fmt.Sprintf("%s [%s] %s / %s", icon, bar,
+		formatProgressTime(progress.Position), formatProgressTime(progress.Total))
+}
+
+// formatProgressTime formats a duration as M:SS
+func formatProgressTime(d time.Duration) string {
+	seconds := int(d.Seconds())
+
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
 // playCommand handles play command
 func playCommand(c *cli.Context) error {
 	clientConfig := GetClientConfig(c)
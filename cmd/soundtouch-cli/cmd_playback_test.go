@@ -2,7 +2,9 @@ package main
 
 import (
 	"testing"
+	"time"
 
+	"github.com/gesellix/bose-soundtouch/pkg/client"
 	"github.com/gesellix/bose-soundtouch/pkg/models"
 )
 
@@ -285,3 +287,40 @@ func TestRealWorldScenarios(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderProgressLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		progress client.Progress
+		expected string
+	}{
+		{
+			name:     "halfway",
+			progress: client.Progress{Position: 90 * time.Second, Total: 180 * time.Second, Playing: true, HasTime: true},
+			expected: "▶️  [█████░░░░░] 1:30 / 3:00",
+		},
+		{
+			name:     "paused_at_start",
+			progress: client.Progress{Position: 0, Total: 200 * time.Second, HasTime: true},
+			expected: "⏸️  [░░░░░░░░░░] 0:00 / 3:20",
+		},
+		{
+			name:     "no_total",
+			progress: client.Progress{Position: 65 * time.Second, Playing: true, HasTime: true},
+			expected: "▶️  1:05",
+		},
+		{
+			name:     "live_stream",
+			progress: client.Progress{Playing: true},
+			expected: "▶️  (live)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderProgressLine(tt.progress, 10); got != tt.expected {
+				t.Errorf("renderProgressLine() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
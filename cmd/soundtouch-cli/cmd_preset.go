@@ -40,28 +40,28 @@ func storeCurrentPreset(c *cli.Context) error {
 
 	if !nowPlaying.ContentItem.IsPresetable {
 		PrintError("Current content cannot be saved as preset")
-		fmt.Printf("  Content: %s\n", nowPlaying.Track)
-		fmt.Printf("  Source: %s\n", nowPlaying.Source)
+		output.Printf("  Content: %s\n", nowPlaying.Track)
+		output.Printf("  Source: %s\n", nowPlaying.Source)
 
 		return fmt.Errorf("current content cannot be preset")
 	}
 
 	// Show what we're about to store
-	fmt.Printf("Current Content:\n")
-	fmt.Printf("  Track: %s\n", nowPlaying.Track)
+	output.Printf("Current Content:\n")
+	output.Printf("  Track: %s\n", nowPlaying.Track)
 
 	if nowPlaying.Artist != "" {
-		fmt.Printf("  Artist: %s\n", nowPlaying.Artist)
+		output.Printf("  Artist: %s\n", nowPlaying.Artist)
 	}
 
 	if nowPlaying.Album != "" {
-		fmt.Printf("  Album: %s\n", nowPlaying.Album)
+		output.Printf("  Album: %s\n", nowPlaying.Album)
 	}
 
-	fmt.Printf("  Source: %s\n", nowPlaying.Source)
+	output.Printf("  Source: %s\n", nowPlaying.Source)
 
 	if nowPlaying.ContentItem.Location != "" {
-		fmt.Printf("  Location: %s\n", nowPlaying.ContentItem.Location)
+		output.Printf("  Location: %s\n", nowPlaying.ContentItem.Location)
 	}
 
 	// Store as preset
@@ -177,17 +177,17 @@ func createContentItem(params *presetParams) *models.ContentItem {
 
 // printPresetContent displays what content will be stored
 func printPresetContent(params *presetParams) {
-	fmt.Printf("Content to store:\n")
-	fmt.Printf("  Name: %s\n", params.name)
-	fmt.Printf("  Source: %s\n", params.source)
-	fmt.Printf("  Location: %s\n", params.location)
+	output.Printf("Content to store:\n")
+	output.Printf("  Name: %s\n", params.name)
+	output.Printf("  Source: %s\n", params.source)
+	output.Printf("  Location: %s\n", params.location)
 
 	if params.sourceAccount != "" {
-		fmt.Printf("  Source Account: %s\n", params.sourceAccount)
+		output.Printf("  Source Account: %s\n", params.sourceAccount)
 	}
 
 	if params.itemType != "" {
-		fmt.Printf("  Type: %s\n", params.itemType)
+		output.Printf("  Type: %s\n", params.itemType)
 	}
 }
 
@@ -257,9 +257,9 @@ func removePreset(c *cli.Context) error {
 	}
 
 	// Show what we're removing
-	fmt.Printf("Removing preset %d:\n", slot)
-	fmt.Printf("  Name: %s\n", preset.GetDisplayName())
-	fmt.Printf("  Source: %s\n", preset.GetSource())
+	output.Printf("Removing preset %d:\n", slot)
+	output.Printf("  Name: %s\n", preset.GetDisplayName())
+	output.Printf("  Source: %s\n", preset.GetSource())
 
 	// Remove preset
 	err = client.RemovePreset(slot)
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gesellix/bose-soundtouch/pkg/client"
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/gesellix/bose-soundtouch/pkg/service/datastore"
+	"github.com/gesellix/bose-soundtouch/pkg/service/presetbank"
+	"github.com/gesellix/bose-soundtouch/pkg/service/worker"
+	"github.com/urfave/cli/v2"
+)
+
+// presetBankDataDirFlag selects where preset banks are stored; it matches the service data directory
+var presetBankDataDirFlag = &cli.StringFlag{
+	Name:    "data-dir",
+	Usage:   "Directory where preset banks are stored (shared with soundtouch-service)",
+	Value:   "data",
+	EnvVars: []string{"SOUNDTOUCH_DATA_DIR", "DATA_DIR"},
+}
+
+// presetBankSpeaker connects to the device and identifies it for its preset banks
+func presetBankSpeaker(c *cli.Context, operation string) (*client.Client, worker.Speaker, error) {
+	clientConfig := GetClientConfig(c)
+	PrintDeviceHeader(operation, clientConfig.Host, clientConfig.Port)
+
+	soundTouchClient, err := CreateSoundTouchClient(clientConfig)
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to create client: %v", err))
+		return nil, worker.Speaker{}, err
+	}
+
+	info, err := soundTouchClient.GetDeviceInfo()
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to get device info: %v", err))
+		return nil, worker.Speaker{}, err
+	}
+
+	return soundTouchClient, worker.Speaker{
+		DeviceID:  info.DeviceID,
+		Name:      info.Name,
+		Host:      clientConfig.Host,
+		Port:      clientConfig.Port,
+		AccountID: info.MargeAccountUUID,
+	}, nil
+}
+
+// listPresetBanks prints the preset banks of the device
+func listPresetBanks(c *cli.Context) error {
+	_, speaker, err := presetBankSpeaker(c, "Getting preset banks")
+	if err != nil {
+		return err
+	}
+
+	banks, err := datastore.NewDataStore(c.String("data-dir")).ListPresetBanks(speaker.DeviceID)
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to load preset banks: %v", err))
+		return err
+	}
+
+	emitResult(c, banks)
+
+	if len(banks) == 0 {
+		output.Printf("No preset banks for %s\n", speaker.Name)
+		output.Println("Save the current presets with: soundtouch-cli preset bank save --name <name>")
+
+		return nil
+	}
+
+	output.Printf("Preset banks of %s (%d):\n", speaker.Name, len(banks))
+
+	for i := range banks {
+		marker := " "
+		if banks[i].Active {
+			marker = "*"
+		}
+
+		output.Printf("  %s %s\n", marker, banks[i].String())
+	}
+
+	return nil
+}
+
+// savePresetBank stores the current presets of the device, or those of a preset file, as a bank
+func savePresetBank(c *cli.Context) error {
+	soundTouchClient, speaker, err := presetBankSpeaker(c, "Saving preset bank")
+	if err != nil {
+		return err
+	}
+
+	var set *models.PresetSet
+
+	if path := c.String("file"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			PrintError(fmt.Sprintf("Failed to read presets: %v", err))
+			return err
+		}
+
+		if set, err = models.ParsePresetSet(data); err != nil {
+			PrintError(err.Error())
+			return err
+		}
+	} else if set, err = soundTouchClient.GetPresetSet(); err != nil {
+		PrintError(fmt.Sprintf("Failed to get presets: %v", err))
+		return err
+	}
+
+	ds := datastore.NewDataStore(c.String("data-dir"))
+
+	bank := models.PresetBank{
+		Name:     c.String("name"),
+		DeviceID: speaker.DeviceID,
+		Presets:  set.Presets,
+		Schedule: c.String("schedule"),
+	}
+
+	if existing, err := ds.GetPresetBank(speaker.DeviceID, bank.Name); err == nil && !c.IsSet("schedule") {
+		bank.Schedule = existing.Schedule
+	}
+
+	if err := bank.Validate(); err != nil {
+		PrintError(err.Error())
+		return &usageError{err}
+	}
+
+	if err := ds.SavePresetBank(bank); err != nil {
+		PrintError(fmt.Sprintf("Failed to save preset bank: %v", err))
+		return err
+	}
+
+	emitResult(c, bank)
+	PrintSuccess(fmt.Sprintf("Saved preset bank %s", bank.String()))
+
+	return nil
+}
+
+// schedulePresetBank sets or clears the time of day a bank is activated at
+func schedulePresetBank(c *cli.Context) error {
+	at, clearSchedule := c.String("at"), c.Bool("clear")
+	if (at != "") == clearSchedule {
+		err := &usageError{fmt.Errorf("either --at or --clear is required, but not both")}
+		PrintError(err.Error())
+
+		return err
+	}
+
+	_, speaker, err := presetBankSpeaker(c, "Scheduling preset bank")
+	if err != nil {
+		return err
+	}
+
+	ds := datastore.NewDataStore(c.String("data-dir"))
+
+	bank, err := ds.GetPresetBank(speaker.DeviceID, c.String("name"))
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	bank.Schedule = at
+	if err := bank.Validate(); err != nil {
+		PrintError(err.Error())
+		return &usageError{err}
+	}
+
+	if err := ds.SavePresetBank(*bank); err != nil {
+		PrintError(fmt.Sprintf("Failed to save preset bank: %v", err))
+		return err
+	}
+
+	emitResult(c, bank)
+
+	if clearSchedule {
+		PrintSuccess(fmt.Sprintf("Preset bank %s is activated manually only", bank.Name))
+	} else {
+		PrintSuccess(fmt.Sprintf("Preset bank %s is activated daily at %s by soundtouch-service", bank.Name, bank.Schedule))
+	}
+
+	return nil
+}
+
+// activatePresetBank writes a bank to the device and to the Marge presets of its account
+func activatePresetBank(c *cli.Context) error {
+	_, speaker, err := presetBankSpeaker(c, "Activating preset bank")
+	if err != nil {
+		return err
+	}
+
+	ds := datastore.NewDataStore(c.String("data-dir"))
+
+	bank, err := ds.GetPresetBank(speaker.DeviceID, c.String("name"))
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	activation, err := presetbank.Activate(ds, speaker, bank)
+	if activation != nil {
+		emitResult(c, activation)
+
+		for _, change := range activation.Changes {
+			output.Printf("  %s\n", change)
+		}
+	}
+
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to activate preset bank: %v", err))
+		return err
+	}
+
+	for _, skipped := range activation.StoreSkipped {
+		PrintWarning(fmt.Sprintf("Marge presets not updated: %s", skipped))
+	}
+
+	PrintSuccess(fmt.Sprintf("Activated preset bank %s on %s", bank.Name, speaker.Name))
+
+	return nil
+}
+
+// deletePresetBank removes a preset bank of the device
+func deletePresetBank(c *cli.Context) error {
+	_, speaker, err := presetBankSpeaker(c, "Deleting preset bank")
+	if err != nil {
+		return err
+	}
+
+	name := c.String("name")
+
+	if err := datastore.NewDataStore(c.String("data-dir")).DeletePresetBank(speaker.DeviceID, name); err != nil {
+		PrintError(fmt.Sprintf("Failed to delete preset bank: %v", err))
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Deleted preset bank %s", name))
+
+	return nil
+}
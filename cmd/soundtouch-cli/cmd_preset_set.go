@@ -0,0 +1,495 @@
+// Package main provides the soundtouch-cli commands that work on all presets of one or more devices.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/gesellix/bose-soundtouch/pkg/client"
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/urfave/cli/v2"
+)
+
+// parseSlots reads comma separated preset slots like "1,3" from one or more values
+func parseSlots(values []string) ([]int, error) {
+	var slots []int
+
+	for _, value := range values {
+		for _, field := range strings.Split(value, ",") {
+			if field = strings.TrimSpace(field); field == "" {
+				continue
+			}
+
+			slot, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid preset slot %q", field)
+			}
+
+			if err := models.ValidatePresetSlot(slot); err != nil {
+				return nil, err
+			}
+
+			slots = append(slots, slot)
+		}
+	}
+
+	return slots, nil
+}
+
+// slotsFlag reads the --slots flag
+func slotsFlag(c *cli.Context) ([]int, error) {
+	slots, err := parseSlots(c.StringSlice("slots"))
+	if err != nil {
+		return nil, &usageError{err}
+	}
+
+	return slots, nil
+}
+
+// presetDeviceClient creates a client for a device given by name, host or device ID
+func presetDeviceClient(c *cli.Context, ref string) (*client.Client, error) {
+	soundTouchClient, err := CreateSoundTouchClient(deviceClientConfig(c, "", ref))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client for %s: %w", ref, err)
+	}
+
+	return soundTouchClient, nil
+}
+
+// applyPresetSet makes the presets of the device match the set, or only shows the differences with --dry-run
+func applyPresetSet(c *cli.Context, soundTouchClient *client.Client, set *models.PresetSet, slots []int) error {
+	plan, err := soundTouchClient.PlanPresets(set, slots)
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to compare presets: %v", err))
+		return err
+	}
+
+	emitResult(c, plan)
+	printRestorePlan(plan.RestorePlan)
+
+	pending := len(plan.Pending())
+
+	switch {
+	case c.Bool("dry-run"):
+		output.Printf("Dry run: %d preset change(s) would be applied\n", pending)
+		return nil
+	case pending == 0:
+		PrintSuccess("Presets already match")
+		return nil
+	}
+
+	if err := plan.Apply(); err != nil {
+		PrintError(fmt.Sprintf("Failed to update presets: %v", err))
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Updated %d preset(s) on %s", pending, plan.Device.Name))
+
+	return nil
+}
+
+// exportPresets writes all presets of the device to a file
+func exportPresets(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return &usageError{fmt.Errorf("usage: preset export <file>, use - for stdout")}
+	}
+
+	toStdout := c.Args().First() == "-"
+
+	clientConfig := GetClientConfig(c)
+	if !toStdout {
+		PrintDeviceHeader("Exporting presets", clientConfig.Host, clientConfig.Port)
+	}
+
+	soundTouchClient, err := CreateSoundTouchClient(clientConfig)
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to create client: %v", err))
+		return err
+	}
+
+	set, err := soundTouchClient.GetPresetSet()
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to get presets: %v", err))
+		return err
+	}
+
+	data, err := json.MarshalIndent(set, "", "  ")
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to encode presets: %v", err))
+		return err
+	}
+
+	if toStdout {
+		if structuredOutput() {
+			emitResult(c, set)
+		} else {
+			output.Println(string(data))
+		}
+
+		return nil
+	}
+
+	path := backupPath(c.Args().First(), &models.DeviceBackup{Device: set.Device})
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		PrintError(fmt.Sprintf("Failed to write presets: %v", err))
+		return err
+	}
+
+	emitResult(c, map[string]any{"file": path, "presets": len(set.Presets)})
+	PrintSuccess(fmt.Sprintf("Exported %d preset(s) of %s to %s", len(set.Presets), set.Device.Name, path))
+
+	return nil
+}
+
+// importPresets stores the presets of a preset file or backup on the device
+func importPresets(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return &usageError{fmt.Errorf("usage: preset import <file>, use - for stdin")}
+	}
+
+	slots, err := slotsFlag(c)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	var data []byte
+
+	if path := c.Args().First(); path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to read presets: %v", err))
+		return err
+	}
+
+	set, err := models.ParsePresetSet(data)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	clientConfig := GetClientConfig(c)
+	PrintDeviceHeader("Importing presets", clientConfig.Host, clientConfig.Port)
+
+	soundTouchClient, err := CreateSoundTouchClient(clientConfig)
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to create client: %v", err))
+		return err
+	}
+
+	output.Printf("Presets of %s (%s) from %s\n", set.Device.Name, set.Device.Type, set.CreatedAt.Local().Format("2006-01-02 15:04"))
+
+	return applyPresetSet(c, soundTouchClient, set, slots)
+}
+
+// copyPresets copies the presets of the --from device to the device, or to every device of --group
+func copyPresets(c *cli.Context) error {
+	slots, err := slotsFlag(c)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	from := c.String("from")
+
+	sourceClient, err := presetDeviceClient(c, from)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	set, err := sourceClient.GetPresetSet()
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to get presets of %s: %v", from, err))
+		return err
+	}
+
+	clientConfig := GetClientConfig(c)
+	PrintDeviceHeader(fmt.Sprintf("Copying presets of %s", set.Device.Name), clientConfig.Host, clientConfig.Port)
+
+	soundTouchClient, err := CreateSoundTouchClient(clientConfig)
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to create client: %v", err))
+		return err
+	}
+
+	return applyPresetSet(c, soundTouchClient, set, slots)
+}
+
+// swapPresets exchanges the presets of two slots
+func swapPresets(c *cli.Context) error {
+	if c.NArg() != 2 {
+		return &usageError{fmt.Errorf("usage: preset swap <slot> <slot>")}
+	}
+
+	slots, err := parseSlots(c.Args().Slice())
+	if err != nil {
+		return &usageError{err}
+	}
+
+	return rearrangePresets(c, fmt.Sprintf("Swapping presets %d and %d", slots[0], slots[1]), func(set *models.PresetSet) error {
+		return set.Swap(slots[0], slots[1])
+	})
+}
+
+// reorderPresets moves presets to new slots, e.g. 3,1,2 puts preset 3 first
+func reorderPresets(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return &usageError{fmt.Errorf("usage: preset reorder <order>, e.g. 3,1,2")}
+	}
+
+	order, err := parseSlots([]string{c.Args().First()})
+	if err != nil {
+		return &usageError{err}
+	}
+
+	return rearrangePresets(c, "Reordering presets", func(set *models.PresetSet) error {
+		return set.Reorder(order)
+	})
+}
+
+// rearrangePresets changes the slots of the presets of the device
+func rearrangePresets(c *cli.Context, operation string, rearrange func(set *models.PresetSet) error) error {
+	clientConfig := GetClientConfig(c)
+	PrintDeviceHeader(operation, clientConfig.Host, clientConfig.Port)
+
+	soundTouchClient, err := CreateSoundTouchClient(clientConfig)
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to create client: %v", err))
+		return err
+	}
+
+	set, err := soundTouchClient.GetPresetSet()
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to get presets: %v", err))
+		return err
+	}
+
+	if err := rearrange(set); err != nil {
+		PrintError(err.Error())
+		return &usageError{err}
+	}
+
+	return applyPresetSet(c, soundTouchClient, set, nil)
+}
+
+// syncPresets makes the presets of every device of --group identical to the --from device, or to the
+// first device of the group. With --watch, a preset changed on any of them is copied to all the others.
+func syncPresets(c *cli.Context) error {
+	group := c.String("group")
+	if group == "" {
+		return &usageError{fmt.Errorf("preset sync needs the devices to keep in sync with --group")}
+	}
+
+	if c.Bool("watch") && c.Bool("dry-run") {
+		return &usageError{fmt.Errorf("--watch cannot be combined with --dry-run")}
+	}
+
+	slots, err := slotsFlag(c)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	profiles, err := loadProfiles(c)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	members, ok := profiles.Group(group)
+	if !ok {
+		err := fmt.Errorf("group %q is not configured in %s", group, profiles.Path())
+		PrintError(err.Error())
+
+		return err
+	}
+
+	reference := c.String("from")
+	if reference == "" {
+		reference = members[0]
+	}
+
+	devices := members
+	if !slices.Contains(devices, reference) {
+		devices = append([]string{reference}, devices...)
+	}
+
+	clients := make(map[string]*client.Client)
+
+	for _, device := range devices {
+		if clients[device], err = presetDeviceClient(c, device); err != nil {
+			PrintError(err.Error())
+			return err
+		}
+	}
+
+	s := &presetSync{c: c, devices: devices, clients: clients, slots: slots, known: make(map[string]*models.PresetSet)}
+
+	if err := s.propagate(reference, slots); err != nil && !c.Bool("watch") {
+		return err
+	}
+
+	if !c.Bool("watch") {
+		return nil
+	}
+
+	return s.watch()
+}
+
+// presetSync keeps the presets of several devices identical
+type presetSync struct {
+	c       *cli.Context
+	devices []string
+	clients map[string]*client.Client
+	slots   []int
+	// known holds the presets of every device after the last sync, to tell own changes from new ones
+	known map[string]*models.PresetSet
+}
+
+// propagate copies the presets of some slots from one device to all others
+func (s *presetSync) propagate(from string, slots []int) error {
+	set, err := s.clients[from].GetPresetSet()
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to get presets of %s: %v", from, err))
+		return err
+	}
+
+	output.Printf("Syncing presets of %s\n", from)
+
+	var failed []string
+
+	for _, device := range s.devices {
+		if device == from {
+			continue
+		}
+
+		output.Printf("\n=== %s ===\n", device)
+
+		if err := applyPresetSet(s.c, s.clients[device], set, slots); err != nil {
+			failed = append(failed, device)
+		}
+	}
+
+	s.known[from] = set
+	s.refresh(from)
+
+	if len(failed) > 0 {
+		return fmt.Errorf("presets not synced to %s", strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// refresh reads the presets of all devices except one
+func (s *presetSync) refresh(except string) {
+	for _, device := range s.devices {
+		if device == except {
+			continue
+		}
+
+		if set, err := s.clients[device].GetPresetSet(); err == nil {
+			s.known[device] = set
+		} else {
+			delete(s.known, device)
+		}
+	}
+}
+
+// changedSlots returns the synced slots whose presets changed on a device since the last sync
+func (s *presetSync) changedSlots(device string, set *models.PresetSet) []int {
+	slots := s.slots
+	if len(slots) == 0 {
+		slots = []int{1, 2, 3, 4, 5, 6}
+	}
+
+	known, ok := s.known[device]
+	if !ok {
+		return slots
+	}
+
+	var changed []int
+
+	for _, slot := range known.DiffSlots(set) {
+		if slices.Contains(slots, slot) {
+			changed = append(changed, slot)
+		}
+	}
+
+	return changed
+}
+
+// watch copies presets changed on one device to all others until interrupted
+func (s *presetSync) watch() error {
+	changed := make(chan string, 16)
+
+	for _, device := range s.devices {
+		ws := s.clients[device].NewWebSocketClient(newWebSocketConfig(true, false))
+
+		ws.OnPresetUpdated(func(_ *models.PresetUpdatedEvent) {
+			select {
+			case changed <- device:
+			default:
+			}
+		})
+
+		if err := ws.Connect(); err != nil {
+			PrintWarning(fmt.Sprintf("Not watching %s: %v", device, err))
+			continue
+		}
+
+		defer func() { _ = ws.Disconnect() }()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	output.Printf("\nWatching presets of %s, press Ctrl+C to stop\n", strings.Join(s.devices, ", "))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case device := <-changed:
+			set, err := s.clients[device].GetPresetSet()
+			if err != nil {
+				PrintWarning(fmt.Sprintf("Failed to get presets of %s: %v", device, err))
+				continue
+			}
+
+			// Events caused by the sync itself report presets that are already known
+			slots := s.changedSlots(device, set)
+			if len(slots) == 0 {
+				continue
+			}
+
+			output.Printf("\nPresets %s changed on %s\n", joinSlots(slots), device)
+
+			if err := s.propagate(device, slots); err != nil {
+				PrintWarning(err.Error())
+			}
+		}
+	}
+}
+
+// joinSlots formats slots like "1, 3"
+func joinSlots(slots []int) string {
+	names := make([]string, len(slots))
+	for i, slot := range slots {
+		names[i] = strconv.Itoa(slot)
+	}
+
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+func TestParseSlots(t *testing.T) {
+	slots, err := parseSlots([]string{"1, 3", "5"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(slots, []int{1, 3, 5}) {
+		t.Errorf("Unexpected slots %v", slots)
+	}
+
+	for _, value := range []string{"0", "7", "one"} {
+		if _, err := parseSlots([]string{value}); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestPresetSync_ChangedSlots(t *testing.T) {
+	known := &models.PresetSet{Presets: []models.BackupPreset{{Slot: 1, Name: "A"}, {Slot: 2, Name: "B"}}}
+	s := &presetSync{known: map[string]*models.PresetSet{"kitchen": known}}
+
+	changed := &models.PresetSet{Presets: []models.BackupPreset{{Slot: 1, Name: "A"}, {Slot: 2, Name: "C"}, {Slot: 4, Name: "D"}}}
+
+	if got := s.changedSlots("kitchen", changed); !reflect.DeepEqual(got, []int{2, 4}) {
+		t.Errorf("Expected slots 2 and 4, got %v", got)
+	}
+
+	if got := s.changedSlots("kitchen", known); len(got) != 0 {
+		t.Errorf("Expected no changes for known presets, got %v", got)
+	}
+
+	s.slots = []int{1, 2}
+	if got := s.changedSlots("kitchen", changed); !reflect.DeepEqual(got, []int{2}) {
+		t.Errorf("Expected only synced slot 2, got %v", got)
+	}
+
+	// Unknown presets are synced completely
+	if got := s.changedSlots("office", changed); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("Expected all synced slots for an unknown device, got %v", got)
+	}
+}
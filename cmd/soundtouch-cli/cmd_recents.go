@@ -25,16 +25,18 @@ func getRecents(c *cli.Context) error {
 		return fmt.Errorf("failed to get recent items: %w", err)
 	}
 
+	emitResult(c, limitRecentItems(response.Items, c.Int("limit")))
+
 	if response.IsEmpty() {
-		fmt.Printf("📭 No recent items found\n")
-		fmt.Printf("💡 Play some content to populate the recent items list\n")
+		output.Printf("📭 No recent items found\n")
+		output.Printf("💡 Play some content to populate the recent items list\n")
 
 		return nil
 	}
 
 	// Display summary
-	fmt.Printf("📊 Recent Items Summary:\n")
-	fmt.Printf("   Total Items: %d\n", response.GetItemCount())
+	output.Printf("📊 Recent Items Summary:\n")
+	output.Printf("   Total Items: %d\n", response.GetItemCount())
 
 	// Show source breakdown
 	sources := map[string]int{
@@ -45,11 +47,11 @@ func getRecents(c *cli.Context) error {
 		"Pandora":      len(response.GetPandoraItems()),
 	}
 
-	fmt.Printf("   By Source:\n")
+	output.Printf("   By Source:\n")
 
 	for source, count := range sources {
 		if count > 0 {
-			fmt.Printf("     • %s: %d items\n", source, count)
+			output.Printf("     • %s: %d items\n", source, count)
 		}
 	}
 
@@ -59,25 +61,25 @@ func getRecents(c *cli.Context) error {
 	playlists := len(response.GetPlaylistsAndAlbums())
 	presetable := len(response.GetPresetableItems())
 
-	fmt.Printf("   By Type:\n")
+	output.Printf("   By Type:\n")
 
 	if tracks > 0 {
-		fmt.Printf("     • 🎵 Tracks: %d\n", tracks)
+		output.Printf("     • 🎵 Tracks: %d\n", tracks)
 	}
 
 	if stations > 0 {
-		fmt.Printf("     • 📻 Stations: %d\n", stations)
+		output.Printf("     • 📻 Stations: %d\n", stations)
 	}
 
 	if playlists > 0 {
-		fmt.Printf("     • 📋 Playlists/Albums: %d\n", playlists)
+		output.Printf("     • 📋 Playlists/Albums: %d\n", playlists)
 	}
 
 	if presetable > 0 {
-		fmt.Printf("     • ⭐ Presetable: %d\n", presetable)
+		output.Printf("     • ⭐ Presetable: %d\n", presetable)
 	}
 
-	fmt.Printf("\n=== Recent Items ===\n")
+	output.Printf("\n=== Recent Items ===\n")
 
 	// Display items with details
 	maxItems := c.Int("limit")
@@ -90,7 +92,7 @@ func getRecents(c *cli.Context) error {
 	}
 
 	if len(response.Items) > maxItems {
-		fmt.Printf("\n... and %d more items (use --limit to show more)\n", len(response.Items)-maxItems)
+		output.Printf("\n... and %d more items (use --limit to show more)\n", len(response.Items)-maxItems)
 	}
 
 	return nil
@@ -150,18 +152,65 @@ func shouldIncludeItemByType(item models.RecentsResponseItem, contentType string
 
 // displayFilteredResults prints the filtered recent items
 func displayFilteredResults(filteredItems []models.RecentsResponseItem, c *cli.Context) {
-	maxItems := c.Int("limit")
-	if maxItems <= 0 || maxItems > len(filteredItems) {
-		maxItems = len(filteredItems)
-	}
+	shown := limitRecentItems(filteredItems, c.Int("limit"))
 
-	for i, item := range filteredItems[:maxItems] {
+	for i, item := range shown {
 		printRecentItem(i+1, &item, c.Bool("detailed"))
 	}
 
-	if len(filteredItems) > maxItems {
-		fmt.Printf("\n... and %d more items (use --limit to show more)\n", len(filteredItems)-maxItems)
+	if len(filteredItems) > len(shown) {
+		output.Printf("\n... and %d more items (use --limit to show more)\n", len(filteredItems)-len(shown))
+	}
+}
+
+// limitRecentItems returns the first limit items, or all of them for a limit of zero or less
+func limitRecentItems(items []models.RecentsResponseItem, limit int) []models.RecentsResponseItem {
+	if limit <= 0 || limit > len(items) {
+		limit = len(items)
+	}
+
+	return append([]models.RecentsResponseItem{}, items[:limit]...)
+}
+
+// recentsStatistics is the structured output of recents stats
+type recentsStatistics struct {
+	Total              int            `json:"total"`
+	BySource           map[string]int `json:"bySource"`
+	Tracks             int            `json:"tracks"`
+	Stations           int            `json:"stations"`
+	PlaylistsAndAlbums int            `json:"playlistsAndAlbums"`
+	Presetable         int            `json:"presetable"`
+	Streaming          int            `json:"streaming"`
+	Local              int            `json:"local"`
+	LastPlayed         int64          `json:"lastPlayed,omitempty"`
+}
+
+// newRecentsStatistics counts recent items by source, content type and origin
+func newRecentsStatistics(response *models.RecentsResponse) recentsStatistics {
+	stats := recentsStatistics{
+		Total:              response.GetItemCount(),
+		BySource:           make(map[string]int),
+		Tracks:             len(response.GetTracks()),
+		Stations:           len(response.GetStations()),
+		PlaylistsAndAlbums: len(response.GetPlaylistsAndAlbums()),
+		Presetable:         len(response.GetPresetableItems()),
+	}
+
+	for _, item := range response.Items {
+		stats.BySource[item.GetSource()]++
+
+		if item.IsStreamingContent() {
+			stats.Streaming++
+		} else if item.IsLocalContent() {
+			stats.Local++
+		}
 	}
+
+	if mostRecent := response.GetMostRecent(); mostRecent != nil {
+		stats.LastPlayed = mostRecent.GetUTCTime()
+	}
+
+	return stats
 }
 
 func getRecentsFiltered(c *cli.Context) error {
@@ -184,7 +233,9 @@ func getRecentsFiltered(c *cli.Context) error {
 	}
 
 	if response.IsEmpty() {
-		fmt.Printf("📭 No recent items found\n")
+		emitResult(c, []models.RecentsResponseItem{})
+		output.Printf("📭 No recent items found\n")
+
 		return nil
 	}
 
@@ -199,14 +250,16 @@ func getRecentsFiltered(c *cli.Context) error {
 	// Apply type filter
 	filteredItems = applyContentTypeFilter(filteredItems, contentType)
 
+	emitResult(c, limitRecentItems(filteredItems, c.Int("limit")))
+
 	if len(filteredItems) == 0 {
-		fmt.Printf("📭 No items match the specified filters\n")
-		fmt.Printf("💡 Try different filter criteria or check available content\n")
+		output.Printf("📭 No items match the specified filters\n")
+		output.Printf("💡 Try different filter criteria or check available content\n")
 
 		return nil
 	}
 
-	fmt.Printf("📊 Filtered Results: %d items\n\n", len(filteredItems))
+	output.Printf("📊 Filtered Results: %d items\n\n", len(filteredItems))
 	displayFilteredResults(filteredItems, c)
 
 	return nil
@@ -229,12 +282,14 @@ func getRecentsMostRecent(c *cli.Context) error {
 	}
 
 	mostRecent := response.GetMostRecent()
+	emitResult(c, mostRecent)
+
 	if mostRecent == nil {
-		fmt.Printf("📭 No recent items found\n")
+		output.Printf("📭 No recent items found\n")
 		return nil
 	}
 
-	fmt.Printf("🕒 Most Recent Item:\n\n")
+	output.Printf("🕒 Most Recent Item:\n\n")
 	printRecentItem(1, mostRecent, true)
 
 	return nil
@@ -253,43 +308,43 @@ func printRecentItem(index int, item *models.RecentsResponseItem, detailed bool)
 	// Content type icon
 	typeIcon := getContentTypeIcon(item)
 
-	fmt.Printf("%d. %s %s\n", index, typeIcon, displayName)
-	fmt.Printf("   Source: %s", sourceDisplay)
+	output.Printf("%d. %s %s\n", index, typeIcon, displayName)
+	output.Printf("   Source: %s", sourceDisplay)
 
 	if contentType != "" {
-		fmt.Printf(" | Type: %s", contentType)
+		output.Printf(" | Type: %s", contentType)
 	}
 
-	fmt.Printf("\n")
+	output.Printf("\n")
 
 	// Time information
 	if item.GetUTCTime() > 0 {
 		playTime := time.Unix(item.GetUTCTime(), 0)
-		fmt.Printf("   Played: %s\n", playTime.Format("2006-01-02 15:04:05"))
+		output.Printf("   Played: %s\n", playTime.Format("2006-01-02 15:04:05"))
 	}
 
 	// Additional details if requested
 	if detailed {
 		if item.HasID() {
-			fmt.Printf("   ID: %s\n", item.GetID())
+			output.Printf("   ID: %s\n", item.GetID())
 		}
 
 		if item.IsPresetable() {
-			fmt.Printf("   ⭐ Can be saved as preset\n")
+			output.Printf("   ⭐ Can be saved as preset\n")
 		}
 
 		if item.HasArtwork() {
-			fmt.Printf("   🎨 Has artwork: %s\n", truncateString(item.GetArtwork(), 50))
+			output.Printf("   🎨 Has artwork: %s\n", truncateString(item.GetArtwork(), 50))
 		}
 
 		location := item.GetLocation()
 		if location != "" {
-			fmt.Printf("   📍 Location: %s\n", truncateString(location, 50))
+			output.Printf("   📍 Location: %s\n", truncateString(location, 50))
 		}
 
 		sourceAccount := item.GetSourceAccount()
 		if sourceAccount != "" && sourceAccount != source {
-			fmt.Printf("   👤 Account: %s\n", truncateString(sourceAccount, 30))
+			output.Printf("   👤 Account: %s\n", truncateString(sourceAccount, 30))
 		}
 
 		// Content classification
@@ -303,11 +358,11 @@ func printRecentItem(index int, item *models.RecentsResponseItem, detailed bool)
 		}
 
 		if len(classifications) > 0 {
-			fmt.Printf("   🏷️  Classification: %s\n", strings.Join(classifications, ", "))
+			output.Printf("   🏷️  Classification: %s\n", strings.Join(classifications, ", "))
 		}
 	}
 
-	fmt.Println()
+	output.Println()
 }
 
 // getContentTypeIcon returns an emoji icon for the content type
@@ -373,21 +428,21 @@ func truncateString(s string, maxLength int) string {
 
 // printBasicStats prints overall statistics about recent items
 func printBasicStats(response *models.RecentsResponse) {
-	fmt.Printf("Overall Statistics:\n")
-	fmt.Printf("  Total Items: %d\n", response.GetItemCount())
+	output.Printf("Overall Statistics:\n")
+	output.Printf("  Total Items: %d\n", response.GetItemCount())
 
 	if !response.IsEmpty() {
 		mostRecent := response.GetMostRecent()
 		if mostRecent != nil {
 			lastPlayTime := time.Unix(mostRecent.GetUTCTime(), 0)
-			fmt.Printf("  Last Played: %s\n", lastPlayTime.Format("2006-01-02 15:04:05"))
+			output.Printf("  Last Played: %s\n", lastPlayTime.Format("2006-01-02 15:04:05"))
 		}
 	}
 }
 
 // printSourceStats prints statistics broken down by source
 func printSourceStats(response *models.RecentsResponse) {
-	fmt.Printf("\nBy Source:\n")
+	output.Printf("\nBy Source:\n")
 
 	sourceStats := map[string]int{
 		"Spotify":      len(response.GetSpotifyItems()),
@@ -425,14 +480,14 @@ func printSourceStats(response *models.RecentsResponse) {
 	for source, count := range sourceStats {
 		if count > 0 {
 			percentage := float64(count) / float64(response.GetItemCount()) * 100
-			fmt.Printf("  %-15s %3d items (%5.1f%%)\n", source+":", count, percentage)
+			output.Printf("  %-15s %3d items (%5.1f%%)\n", source+":", count, percentage)
 		}
 	}
 }
 
 // printContentTypeStats prints statistics broken down by content type
 func printContentTypeStats(response *models.RecentsResponse) {
-	fmt.Printf("\nBy Content Type:\n")
+	output.Printf("\nBy Content Type:\n")
 
 	tracks := len(response.GetTracks())
 	stations := len(response.GetStations())
@@ -440,17 +495,17 @@ func printContentTypeStats(response *models.RecentsResponse) {
 
 	if tracks > 0 {
 		percentage := float64(tracks) / float64(response.GetItemCount()) * 100
-		fmt.Printf("  %-15s %3d items (%5.1f%%)\n", "Tracks:", tracks, percentage)
+		output.Printf("  %-15s %3d items (%5.1f%%)\n", "Tracks:", tracks, percentage)
 	}
 
 	if stations > 0 {
 		percentage := float64(stations) / float64(response.GetItemCount()) * 100
-		fmt.Printf("  %-15s %3d items (%5.1f%%)\n", "Stations:", stations, percentage)
+		output.Printf("  %-15s %3d items (%5.1f%%)\n", "Stations:", stations, percentage)
 	}
 
 	if playlists > 0 {
 		percentage := float64(playlists) / float64(response.GetItemCount()) * 100
-		fmt.Printf("  %-15s %3d items (%5.1f%%)\n", "Playlists/Albums:", playlists, percentage)
+		output.Printf("  %-15s %3d items (%5.1f%%)\n", "Playlists/Albums:", playlists, percentage)
 	}
 }
 
@@ -458,10 +513,10 @@ func printContentTypeStats(response *models.RecentsResponse) {
 func printSpecialCategoryStats(response *models.RecentsResponse) {
 	presetable := len(response.GetPresetableItems())
 	if presetable > 0 {
-		fmt.Printf("\nSpecial Categories:\n")
+		output.Printf("\nSpecial Categories:\n")
 
 		percentage := float64(presetable) / float64(response.GetItemCount()) * 100
-		fmt.Printf("  %-15s %3d items (%5.1f%%)\n", "Presetable:", presetable, percentage)
+		output.Printf("  %-15s %3d items (%5.1f%%)\n", "Presetable:", presetable, percentage)
 	}
 }
 
@@ -478,16 +533,16 @@ func printSourceAnalysisStats(response *models.RecentsResponse) {
 		}
 	}
 
-	fmt.Printf("\nSource Analysis:\n")
+	output.Printf("\nSource Analysis:\n")
 
 	if streamingCount > 0 {
 		percentage := float64(streamingCount) / float64(response.GetItemCount()) * 100
-		fmt.Printf("  %-15s %3d items (%5.1f%%)\n", "Streaming:", streamingCount, percentage)
+		output.Printf("  %-15s %3d items (%5.1f%%)\n", "Streaming:", streamingCount, percentage)
 	}
 
 	if localCount > 0 {
 		percentage := float64(localCount) / float64(response.GetItemCount()) * 100
-		fmt.Printf("  %-15s %3d items (%5.1f%%)\n", "Local:", localCount, percentage)
+		output.Printf("  %-15s %3d items (%5.1f%%)\n", "Local:", localCount, percentage)
 	}
 }
 
@@ -507,12 +562,14 @@ func recentsStats(c *cli.Context) error {
 		return fmt.Errorf("failed to get recent items: %w", err)
 	}
 
+	emitResult(c, newRecentsStatistics(response))
+
 	if response.IsEmpty() {
-		fmt.Printf("📊 Statistics: No recent items found\n")
+		output.Printf("📊 Statistics: No recent items found\n")
 		return nil
 	}
 
-	fmt.Printf("📊 Recent Items Statistics\n\n")
+	output.Printf("📊 Recent Items Statistics\n\n")
 
 	printBasicStats(response)
 	printSourceStats(response)
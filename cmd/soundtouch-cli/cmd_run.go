@@ -0,0 +1,669 @@
+// Package main provides the soundtouch-cli run command for scripts of CLI commands.
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/client"
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/urfave/cli/v2"
+)
+
+// Directives of the script language; every other step is a soundtouch-cli command
+const (
+	scriptSet     = "set"
+	scriptEcho    = "echo"
+	scriptSleep   = "sleep"
+	scriptWaitFor = "wait-for"
+	scriptOnError = "on-error"
+	scriptCommand = "command"
+)
+
+// Error policies of script steps
+const (
+	policyAbort    = "abort"
+	policyContinue = "continue"
+	policyRetry    = "retry"
+)
+
+// defaultWaitTimeout is how long wait-for waits unless the step gives a timeout
+const defaultWaitTimeout = 30 * time.Second
+
+// scriptEvents maps the event names of wait-for to the WebSocket event types
+var scriptEvents = map[string]models.WebSocketEventType{
+	"nowPlaying":   models.EventTypeNowPlaying,
+	"volume":       models.EventTypeVolumeUpdated,
+	"connection":   models.EventTypeConnectionState,
+	"preset":       models.EventTypePresetUpdated,
+	"zone":         models.EventTypeZoneUpdated,
+	"bass":         models.EventTypeBassUpdated,
+	"clockTime":    models.EventTypeClockTimeUpdated,
+	"clockDisplay": models.EventTypeClockDisplayUpdated,
+	"name":         models.EventTypeNameUpdated,
+	"recents":      models.EventTypeRecentsUpdated,
+	"language":     models.EventTypeLanguageUpdated,
+	"sources":      models.EventTypeSourcesUpdated,
+}
+
+var scriptVariableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// scriptPolicy is what happens when a step fails
+type scriptPolicy struct {
+	mode     string
+	attempts int
+	delay    time.Duration
+}
+
+// scriptSegment is a part of a word; quoted in single quotes, it is taken literally
+type scriptSegment struct {
+	text    string
+	literal bool
+}
+
+// scriptWord is one argument of a step
+type scriptWord []scriptSegment
+
+// scriptStep is one line of a script
+type scriptStep struct {
+	line    int
+	kind    string
+	words   []scriptWord
+	event   string
+	timeout time.Duration
+	policy  scriptPolicy
+}
+
+// String returns the word as written, without quotes and before variables are expanded
+func (w scriptWord) String() string {
+	var b strings.Builder
+	for _, segment := range w {
+		b.WriteString(segment.text)
+	}
+
+	return b.String()
+}
+
+// expand replaces ${name} and $name with the values of variables; $$ is a literal $
+func (w scriptWord) expand(vars map[string]string) (string, error) {
+	var b strings.Builder
+
+	for _, segment := range w {
+		if segment.literal {
+			b.WriteString(segment.text)
+			continue
+		}
+
+		text := segment.text
+		for i := 0; i < len(text); i++ {
+			if text[i] != '$' || i+1 == len(text) {
+				b.WriteByte(text[i])
+				continue
+			}
+
+			var name string
+
+			switch {
+			case text[i+1] == '$':
+				b.WriteByte('$')
+				i++
+
+				continue
+			case text[i+1] == '{':
+				end := strings.IndexByte(text[i:], '}')
+				if end < 0 {
+					return "", fmt.Errorf("unterminated variable in %q", w.String())
+				}
+
+				name = text[i+2 : i+end]
+				i += end
+			default:
+				end := i + 1
+				for end < len(text) && (text[end] == '_' || isAlphaNumeric(text[end])) {
+					end++
+				}
+
+				if end == i+1 {
+					b.WriteByte('$')
+					continue
+				}
+
+				name = text[i+1 : end]
+				i = end - 1
+			}
+
+			value, ok := vars[name]
+			if !ok {
+				return "", fmt.Errorf("undefined variable %q", name)
+			}
+
+			b.WriteString(value)
+		}
+	}
+
+	return b.String(), nil
+}
+
+func isAlphaNumeric(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// splitScriptLine splits a line into words. Double quotes group words, single quotes also turn off
+// variable expansion, a backslash escapes the next character and # starts a comment.
+func splitScriptLine(line string) ([]scriptWord, error) {
+	var (
+		words   []scriptWord
+		word    scriptWord
+		current strings.Builder
+		inWord  bool
+		quote   byte
+	)
+
+	flush := func(literal bool) {
+		if current.Len() > 0 || literal {
+			word = append(word, scriptSegment{text: current.String(), literal: literal})
+			current.Reset()
+		}
+	}
+
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+
+		switch {
+		case quote == '\'':
+			if c == '\'' {
+				flush(true)
+
+				quote = 0
+			} else {
+				current.WriteByte(c)
+			}
+		case c == '\\' && i+1 < len(line):
+			i++
+			// An escaped $ must not start a variable
+			if line[i] == '$' {
+				current.WriteString("$$")
+			} else {
+				current.WriteByte(line[i])
+			}
+
+			inWord = true
+		case quote == '"':
+			if c == '"' {
+				quote = 0
+			} else {
+				current.WriteByte(c)
+			}
+		case c == '\'' || c == '"':
+			if c == '\'' {
+				flush(false)
+			}
+
+			quote = c
+			inWord = true
+		case c == ' ' || c == '\t':
+			if inWord {
+				flush(false)
+				words = append(words, word)
+				word = nil
+				inWord = false
+			}
+		case c == '#' && !inWord:
+			i = len(line)
+		default:
+			current.WriteByte(c)
+
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+
+	if inWord {
+		flush(false)
+		words = append(words, word)
+	}
+
+	return words, nil
+}
+
+// parseScript reads the steps of a script; isCommand reports whether a word names a CLI command
+func parseScript(content string, isCommand func(name string) bool) ([]scriptStep, error) {
+	var steps []scriptStep
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+
+	for number := 1; scanner.Scan(); number++ {
+		words, err := splitScriptLine(scanner.Text())
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number, err)
+		}
+
+		if len(words) == 0 {
+			continue
+		}
+
+		step, err := parseScriptStep(words, isCommand)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number, err)
+		}
+
+		step.line = number
+		steps = append(steps, step)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read script: %w", err)
+	}
+
+	return steps, nil
+}
+
+// parseScriptStep checks the words of one line and turns them into a step
+func parseScriptStep(words []scriptWord, isCommand func(name string) bool) (scriptStep, error) {
+	step := scriptStep{kind: words[0].String(), words: words[1:]}
+	args := make([]string, len(step.words))
+
+	for i, word := range step.words {
+		args[i] = word.String()
+	}
+
+	switch step.kind {
+	case scriptSet:
+		if len(args) < 1 || !scriptVariableName.MatchString(args[0]) {
+			return step, fmt.Errorf("usage: set <name> <value>")
+		}
+	case scriptEcho:
+	case scriptSleep:
+		if len(args) != 1 {
+			return step, fmt.Errorf("usage: sleep <duration>")
+		}
+
+		duration, err := parseScriptDuration(args[0])
+		if err != nil {
+			return step, err
+		}
+
+		step.timeout = duration
+	case scriptWaitFor:
+		if len(args) < 1 || len(args) > 2 {
+			return step, fmt.Errorf("usage: wait-for <event> [timeout]")
+		}
+
+		if _, ok := scriptEvents[args[0]]; !ok {
+			return step, fmt.Errorf("unknown event %q, valid events: %s", args[0], strings.Join(scriptEventNames(), ", "))
+		}
+
+		step.event = args[0]
+		step.timeout = defaultWaitTimeout
+
+		if len(args) == 2 {
+			timeout, err := parseScriptDuration(args[1])
+			if err != nil {
+				return step, err
+			}
+
+			step.timeout = timeout
+		}
+	case scriptOnError:
+		policy, err := parseScriptPolicy(args)
+		if err != nil {
+			return step, err
+		}
+
+		step.policy = policy
+	default:
+		if !isCommand(step.kind) {
+			return step, fmt.Errorf("unknown command %q", step.kind)
+		}
+
+		step.words = words
+		step.kind = scriptCommand
+	}
+
+	return step, nil
+}
+
+// parseScriptPolicy parses "abort", "continue" or "retry [attempts] [delay]"
+func parseScriptPolicy(args []string) (scriptPolicy, error) {
+	if len(args) == 0 {
+		return scriptPolicy{}, fmt.Errorf("usage: on-error abort|continue|retry [attempts] [delay]")
+	}
+
+	policy := scriptPolicy{mode: args[0], attempts: 1}
+
+	switch policy.mode {
+	case policyAbort, policyContinue:
+		if len(args) > 1 {
+			return policy, fmt.Errorf("on-error %s takes no arguments", policy.mode)
+		}
+	case policyRetry:
+		if len(args) > 3 {
+			return policy, fmt.Errorf("usage: on-error retry [attempts] [delay]")
+		}
+
+		policy.attempts = 3
+		policy.delay = time.Second
+
+		if len(args) > 1 {
+			attempts, err := strconv.Atoi(args[1])
+			if err != nil || attempts < 1 {
+				return policy, fmt.Errorf("invalid number of attempts %q", args[1])
+			}
+
+			policy.attempts = attempts
+		}
+
+		if len(args) > 2 {
+			delay, err := parseScriptDuration(args[2])
+			if err != nil {
+				return policy, err
+			}
+
+			policy.delay = delay
+		}
+	default:
+		return policy, fmt.Errorf("invalid error policy %q: use abort, continue or retry", policy.mode)
+	}
+
+	return policy, nil
+}
+
+// parseScriptDuration parses a duration like 500ms or 2s; plain numbers are seconds
+func parseScriptDuration(value string) (time.Duration, error) {
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds >= 0 {
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	return duration, nil
+}
+
+// scriptEventNames returns the sorted event names of wait-for
+func scriptEventNames() []string {
+	names := make([]string, 0, len(scriptEvents))
+	for name := range scriptEvents {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// eventRecorder keeps the events of a device so wait-for also sees events that arrived during the previous step
+type eventRecorder struct {
+	mu      sync.Mutex
+	events  []recordedEvent
+	changed chan struct{}
+}
+
+type recordedEvent struct {
+	eventType models.WebSocketEventType
+	at        time.Time
+}
+
+func newEventRecorder() *eventRecorder {
+	return &eventRecorder{changed: make(chan struct{})}
+}
+
+// record stores the types of an event and wakes up waiting steps
+func (r *eventRecorder) record(event *models.WebSocketEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, eventType := range event.GetEventTypes() {
+		r.events = append(r.events, recordedEvent{eventType: eventType, at: now})
+	}
+
+	close(r.changed)
+	r.changed = make(chan struct{})
+}
+
+// wait returns the time of the first event of the given type at or after since
+func (r *eventRecorder) wait(eventType models.WebSocketEventType, since time.Time, timeout time.Duration) (time.Time, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		r.mu.Lock()
+
+		for _, event := range r.events {
+			if event.eventType == eventType && !event.at.Before(since) {
+				r.mu.Unlock()
+				return event.at, nil
+			}
+		}
+
+		changed := r.changed
+		r.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-timer.C:
+			return time.Time{}, fmt.Errorf("no %s event within %v", eventType, timeout)
+		}
+	}
+}
+
+// scriptRunner runs the steps of a script against one device
+type scriptRunner struct {
+	c      *cli.Context
+	device string
+	vars   map[string]string
+	fixed  map[string]bool
+	policy scriptPolicy
+	dryRun bool
+	events *eventRecorder
+	since  time.Time
+}
+
+// runScript handles the run command
+func runScript(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return &usageError{fmt.Errorf("usage: soundtouch-cli run <script>")}
+	}
+
+	path := c.Args().First()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to read script: %v", err))
+		return err
+	}
+
+	steps, err := parseScript(string(content), func(name string) bool {
+		return name != "run" && name != "shell" && c.App.Command(name) != nil
+	})
+	if err != nil {
+		err = &usageError{fmt.Errorf("%s: %w", path, err)}
+		PrintError(err.Error())
+
+		return err
+	}
+
+	policy, err := parseScriptPolicy(strings.Fields(c.String("on-error")))
+	if err != nil {
+		return &usageError{err}
+	}
+
+	runner := &scriptRunner{
+		c:      c,
+		device: selectedDevice(c),
+		vars:   make(map[string]string),
+		fixed:  make(map[string]bool),
+		policy: policy,
+		dryRun: c.Bool("dry-run"),
+	}
+
+	runner.vars["device"] = runner.device
+
+	for _, definition := range c.StringSlice("var") {
+		name, value, ok := strings.Cut(definition, "=")
+		if !ok || !scriptVariableName.MatchString(name) {
+			return &usageError{fmt.Errorf("invalid variable %q: use name=value", definition)}
+		}
+
+		runner.vars[name] = value
+		runner.fixed[name] = true
+	}
+
+	output.Printf("Running %s (%d steps) on %s\n", path, len(steps), runner.device)
+
+	if !runner.dryRun && needsEvents(steps) {
+		ws, err := runner.subscribe()
+		if err != nil {
+			PrintError(err.Error())
+			return err
+		}
+
+		defer func() { _ = ws.Disconnect() }()
+	}
+
+	return runner.run(steps)
+}
+
+// needsEvents reports whether a script waits for events
+func needsEvents(steps []scriptStep) bool {
+	for _, step := range steps {
+		if step.kind == scriptWaitFor {
+			return true
+		}
+	}
+
+	return false
+}
+
+// subscribe connects to the WebSocket of the device and records its events for wait-for
+func (r *scriptRunner) subscribe() (*client.WebSocketClient, error) {
+	soundTouchClient, err := CreateSoundTouchClient(GetClientConfig(r.c))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	r.events = newEventRecorder()
+
+	ws := setupWebSocketClient(soundTouchClient, true, false)
+	ws.ObserveEvents(r.events.record)
+
+	if err := ws.Connect(); err != nil {
+		return nil, fmt.Errorf("failed to connect to WebSocket for wait-for: %w", err)
+	}
+
+	return ws, nil
+}
+
+// run executes the steps in order, applying the error policy in effect for each step
+func (r *scriptRunner) run(steps []scriptStep) error {
+	var failed []string
+
+	for _, step := range steps {
+		if step.kind == scriptOnError {
+			r.policy = step.policy
+			continue
+		}
+
+		err := r.runStep(step)
+		for attempt := 2; err != nil && r.policy.mode == policyRetry && attempt <= r.policy.attempts; attempt++ {
+			PrintWarning(fmt.Sprintf("line %d: %v, retrying in %v (attempt %d of %d)", step.line, err, r.policy.delay, attempt, r.policy.attempts))
+			time.Sleep(r.policy.delay)
+
+			err = r.runStep(step)
+		}
+
+		if err == nil {
+			continue
+		}
+
+		if r.policy.mode != policyContinue {
+			err = fmt.Errorf("line %d: %w", step.line, err)
+			PrintError(fmt.Sprintf("Script aborted at %v", err))
+
+			return err
+		}
+
+		PrintWarning(fmt.Sprintf("line %d failed, continuing: %v", step.line, err))
+
+		failed = append(failed, strconv.Itoa(step.line))
+	}
+
+	if len(failed) > 0 {
+		err := fmt.Errorf("script finished with failed steps on lines %s", strings.Join(failed, ", "))
+		PrintError(err.Error())
+
+		return err
+	}
+
+	PrintSuccess("Script finished")
+
+	return nil
+}
+
+// runStep executes one step
+func (r *scriptRunner) runStep(step scriptStep) error {
+	args := make([]string, len(step.words))
+
+	for i, word := range step.words {
+		value, err := word.expand(r.vars)
+		if err != nil {
+			return err
+		}
+
+		args[i] = value
+	}
+
+	switch step.kind {
+	case scriptSet:
+		if !r.fixed[args[0]] {
+			r.vars[args[0]] = strings.Join(args[1:], " ")
+		}
+	case scriptEcho:
+		output.Println(strings.Join(args, " "))
+	case scriptSleep:
+		output.Printf("⏳ sleep %v\n", step.timeout)
+
+		if !r.dryRun {
+			time.Sleep(step.timeout)
+		}
+	case scriptWaitFor:
+		output.Printf("⏳ wait-for %s (up to %v)\n", step.event, step.timeout)
+
+		if r.dryRun {
+			return nil
+		}
+
+		at, err := r.events.wait(scriptEvents[step.event], r.since, step.timeout)
+		if err != nil {
+			return err
+		}
+
+		// The next wait for the same event needs a newer one
+		r.since = at.Add(time.Nanosecond)
+	default:
+		output.Printf("▶️  %s\n", strings.Join(args, " "))
+
+		if r.dryRun {
+			return nil
+		}
+
+		r.since = time.Now()
+
+		return r.c.App.Run(deviceCommandArgs(r.c, r.device, args...))
+	}
+
+	return nil
+}
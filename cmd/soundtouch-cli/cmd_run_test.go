@@ -0,0 +1,238 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/config"
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/urfave/cli/v2"
+)
+
+func TestSplitScriptLine(t *testing.T) {
+	vars := map[string]string{"name": "Living Room", "level": "30"}
+
+	tests := []struct {
+		name     string
+		line     string
+		expected []string
+	}{
+		{"plain", "volume set --level 30", []string{"volume", "set", "--level", "30"}},
+		{"comment", "  # setup  ", nil},
+		{"trailing comment", "bass set --value -3 # warmer", []string{"bass", "set", "--value", "-3"}},
+		{"double quotes expand", `name set --value "${name}"`, []string{"name", "set", "--value", "Living Room"}},
+		{"unquoted variable stays one word", "name set --value $name", []string{"name", "set", "--value", "Living Room"}},
+		{"single quotes are literal", `echo '$name' "#1"`, []string{"echo", "$name", "#1"}},
+		{"escapes", `echo \$level $$ a\ b level=${level}%`, []string{"echo", "$level", "$", "a b", "level=30%"}},
+		{"empty quotes", `echo "" ''`, []string{"echo", "", ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			words, err := splitScriptLine(tt.line)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var got []string
+
+			for _, word := range words {
+				value, err := word.expand(vars)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+
+				got = append(got, value)
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("splitScriptLine(%q) = %q, want %q", tt.line, got, tt.expected)
+			}
+		})
+	}
+
+	if _, err := splitScriptLine(`echo "open`); err == nil {
+		t.Error("Expected an error for an unterminated quote")
+	}
+
+	words, _ := splitScriptLine("echo ${missing}")
+	if _, err := words[1].expand(vars); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("Expected an undefined variable error, got %v", err)
+	}
+}
+
+func TestParseScript(t *testing.T) {
+	isCommand := func(name string) bool { return name == "volume" || name == "preset" }
+
+	steps, err := parseScript("set level 20\nvolume set --level $level\n\nwait-for volume 5s\non-error retry 2 500ms\npreset select 1\nsleep 1.5\n", isCommand)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	kinds := make([]string, len(steps))
+	for i, step := range steps {
+		kinds[i] = step.kind
+	}
+
+	expectedKinds := []string{scriptSet, scriptCommand, scriptWaitFor, scriptOnError, scriptCommand, scriptSleep}
+	if !reflect.DeepEqual(kinds, expectedKinds) {
+		t.Fatalf("Expected steps %v, got %v", expectedKinds, kinds)
+	}
+
+	if steps[2].line != 4 || steps[2].event != "volume" || steps[2].timeout != 5*time.Second {
+		t.Errorf("Unexpected wait-for step: %+v", steps[2])
+	}
+
+	if policy := steps[3].policy; policy.mode != policyRetry || policy.attempts != 2 || policy.delay != 500*time.Millisecond {
+		t.Errorf("Unexpected policy: %+v", policy)
+	}
+
+	if steps[5].timeout != 1500*time.Millisecond {
+		t.Errorf("Expected sleep of 1.5s, got %v", steps[5].timeout)
+	}
+
+	for _, script := range []string{
+		"bogus command",
+		"wait-for party",
+		"sleep soon",
+		"on-error ignore",
+		"on-error retry 0",
+		"set 1x value",
+	} {
+		if _, err := parseScript("volume get\n"+script, isCommand); err == nil || !strings.HasPrefix(err.Error(), "line 2:") {
+			t.Errorf("Expected a line 2 error for %q, got %v", script, err)
+		}
+	}
+}
+
+func TestEventRecorder(t *testing.T) {
+	recorder := newEventRecorder()
+	recorder.record(&models.WebSocketEvent{VolumeUpdated: &models.VolumeUpdatedEvent{}})
+
+	since := time.Now().Add(time.Millisecond)
+
+	if _, err := recorder.wait(models.EventTypeVolumeUpdated, since, 20*time.Millisecond); err == nil {
+		t.Error("Expected events before the step to be ignored")
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		recorder.record(&models.WebSocketEvent{NowPlayingUpdated: &models.NowPlayingUpdatedEvent{}})
+		recorder.record(&models.WebSocketEvent{VolumeUpdated: &models.VolumeUpdatedEvent{}})
+	}()
+
+	at, err := recorder.wait(models.EventTypeVolumeUpdated, since, time.Second)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if at.Before(since) {
+		t.Errorf("Expected an event after %v, got %v", since, at)
+	}
+}
+
+func TestRunScript(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DEVICE_REGISTRY", filepath.Join(dir, "devices.json"))
+	t.Setenv("SOUNDTOUCH_HOST", "")
+	t.Setenv("SOUNDTOUCH_DEVICE", "")
+	t.Setenv("SOUNDTOUCH_GROUP", "")
+
+	profiles, err := config.LoadProfiles(filepath.Join(dir, "config.json"))
+	if err != nil {
+		t.Fatalf("Failed to load profiles: %v", err)
+	}
+
+	profiles.SetDevice("Kitchen", &config.DeviceProfile{Host: "192.168.1.10"})
+	profiles.SetDevice("Office", &config.DeviceProfile{Host: "192.168.1.11"})
+	profiles.SetGroup("all", []string{"Kitchen", "Office"})
+
+	if err := profiles.Save(); err != nil {
+		t.Fatalf("Failed to save profiles: %v", err)
+	}
+
+	script := filepath.Join(dir, "setup.st")
+	content := `# provision a speaker
+set room "Living Room"
+probe name "${room}" '$room'
+on-error continue
+probe fail
+on-error retry 3 0
+probe flaky
+`
+	if err := os.WriteFile(script, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write script: %v", err)
+	}
+
+	var (
+		calls []string
+		flaky int
+	)
+
+	app := &cli.App{
+		Name:  "soundtouch-cli",
+		Flags: CommonFlags,
+		Commands: []*cli.Command{
+			{
+				Name:   "run",
+				Action: runScript,
+				Before: RequireHost,
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{Name: "var"},
+					&cli.StringFlag{Name: "on-error", Value: policyAbort},
+					&cli.BoolFlag{Name: "dry-run"},
+				},
+			},
+			{
+				Name:   "probe",
+				Before: RequireHost,
+				Action: func(c *cli.Context) error {
+					calls = append(calls, GetClientConfig(c).Host+" "+strings.Join(c.Args().Slice(), "|"))
+
+					switch c.Args().First() {
+					case "fail":
+						return os.ErrInvalid
+					case "flaky":
+						flaky++
+						if flaky%2 == 1 {
+							return os.ErrDeadlineExceeded
+						}
+					}
+
+					return nil
+				},
+			},
+		},
+	}
+	applyGroupSelector(app.Commands)
+
+	err = app.Run([]string{"soundtouch-cli", "--config", profiles.Path(), "--group", "all", "run", "--var", "room=Den", script})
+	if err == nil || !strings.Contains(err.Error(), "2 devices") {
+		t.Fatalf("Expected the failed step to fail the group run, got %v", err)
+	}
+
+	expected := []string{
+		"192.168.1.10 name|Den|$room",
+		"192.168.1.10 fail",
+		"192.168.1.10 flaky",
+		"192.168.1.10 flaky",
+		"192.168.1.11 name|Den|$room",
+		"192.168.1.11 fail",
+		"192.168.1.11 flaky",
+		"192.168.1.11 flaky",
+	}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("Unexpected calls:\n%s\nwant:\n%s", strings.Join(calls, "\n"), strings.Join(expected, "\n"))
+	}
+
+	calls = nil
+
+	err = app.Run([]string{"soundtouch-cli", "--config", profiles.Path(), "--device", "office", "run", "--dry-run", script})
+	if err != nil || len(calls) != 0 {
+		t.Errorf("Expected a dry run without calls, got %v and %v", err, calls)
+	}
+}
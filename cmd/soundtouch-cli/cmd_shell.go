@@ -0,0 +1,789 @@
+// Package main provides the soundtouch-cli shell command, an interactive session with a live status line.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gesellix/bose-soundtouch/pkg/client"
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
+)
+
+// shellVolumeStep is the volume change of the vol +/- commands and the arrow keys in remote mode
+const shellVolumeStep = 2
+
+// Kinds of arguments the shell completes for its built-in commands
+const (
+	shellArgPreset = "preset"
+	shellArgSource = "source"
+	shellArgDevice = "device"
+)
+
+// shellCommand is a built-in command of the interactive shell
+type shellCommand struct {
+	name  string
+	args  string
+	usage string
+	arg   string
+	run   func(s *shellSession, args []string) error
+}
+
+// shellCommands lists the built-in commands; every other line is run as a regular soundtouch-cli command.
+// A built-in sharing its name with a regular command gives way when followed by one of its subcommands.
+var shellCommands = []shellCommand{
+	{name: "play", usage: "Start playback", run: (*shellSession).play},
+	{name: "pause", usage: "Pause playback", run: (*shellSession).pause},
+	{name: "toggle", usage: "Toggle between play and pause", run: (*shellSession).toggle},
+	{name: "stop", usage: "Stop playback", run: (*shellSession).stop},
+	{name: "next", usage: "Skip to the next track", run: (*shellSession).next},
+	{name: "prev", usage: "Go back to the previous track", run: (*shellSession).prev},
+	{name: "vol", args: "[level|+|-]", usage: "Show, set, raise or lower the volume", run: (*shellSession).volume},
+	{name: "mute", usage: "Toggle mute", run: (*shellSession).mute},
+	{name: "preset", args: "<1-6>", usage: "Play a preset", arg: shellArgPreset, run: (*shellSession).preset},
+	{name: "source", args: "<source>", usage: "Select a source", arg: shellArgSource, run: (*shellSession).source},
+	{name: "status", usage: "Show the status line", run: (*shellSession).showStatus},
+	{name: "device", args: "<name|host>", usage: "Switch to another device", arg: shellArgDevice, run: (*shellSession).switchDevice},
+	{name: "remote", usage: "Single-key remote control (space, arrows, 1-6, m, q)", run: (*shellSession).remote},
+}
+
+// errShellExit ends the shell
+var errShellExit = errors.New("exit")
+
+// shellStatus is what the status line shows
+type shellStatus struct {
+	nowPlaying *models.NowPlaying
+	volume     *models.Volume
+	zone       *models.ZoneInfo
+}
+
+// shellSession holds the client, the mirrored device state and the terminal of an interactive shell
+type shellSession struct {
+	c      *cli.Context
+	device string
+	config *ClientConfig
+	client *client.Client
+	state  *client.DeviceState
+
+	out         io.Writer
+	terminal    *term.Terminal
+	fd          int
+	interactive bool
+
+	mu         sync.Mutex
+	lastStatus string
+}
+
+// runShell handles the shell command
+func runShell(c *cli.Context) error {
+	if structuredOutput() {
+		return &usageError{fmt.Errorf("the shell only supports text output")}
+	}
+
+	if c.String("group") != "" {
+		return &usageError{fmt.Errorf("the shell controls one device at a time; use --device instead of --group")}
+	}
+
+	s := &shellSession{c: c, out: output.Writer(), fd: int(os.Stdin.Fd())}
+	s.interactive = term.IsTerminal(s.fd)
+
+	if s.interactive {
+		s.terminal = term.NewTerminal(struct {
+			io.Reader
+			io.Writer
+		}{os.Stdin, os.Stdout}, "> ")
+		s.terminal.AutoCompleteCallback = s.complete
+		s.out = s.terminal
+	}
+
+	if err := s.connect(c.String("device")); err != nil {
+		PrintError(err.Error())
+		return err
+	}
+	defer s.disconnect()
+
+	output.Println("Type 'help' for the commands, 'remote' for single-key control and 'exit' to leave")
+	s.printStatus(true)
+
+	return s.loop(os.Stdin)
+}
+
+// loop reads and runs commands until exit or end of input
+func (s *shellSession) loop(in io.Reader) error {
+	var scanner *bufio.Scanner
+	if !s.interactive {
+		scanner = bufio.NewScanner(in)
+	}
+
+	for {
+		var (
+			line string
+			err  error
+		)
+
+		if s.interactive {
+			line, err = s.readLine()
+		} else if scanner.Scan() {
+			line = scanner.Text()
+		} else {
+			err = scanner.Err()
+			if err == nil {
+				err = io.EOF
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to read command: %w", err)
+		}
+
+		if err := s.execute(line); err != nil {
+			if errors.Is(err, errShellExit) {
+				return nil
+			}
+
+			PrintError(err.Error())
+		}
+	}
+}
+
+// readLine reads one line with editing, history and completion; the terminal is only raw while reading
+func (s *shellSession) readLine() (string, error) {
+	state, err := term.MakeRaw(s.fd)
+	if err != nil {
+		return "", fmt.Errorf("failed to switch terminal to raw mode: %w", err)
+	}
+
+	defer func() { _ = term.Restore(s.fd, state) }()
+
+	return s.terminal.ReadLine()
+}
+
+// execute runs one line of input
+func (s *shellSession) execute(line string) error {
+	args := strings.Fields(line)
+	if len(args) == 0 {
+		return nil
+	}
+
+	name := strings.ToLower(args[0])
+
+	switch name {
+	case "exit", "quit":
+		return errShellExit
+	case "help", "?":
+		s.printHelp()
+		return nil
+	case "shell":
+		return fmt.Errorf("already in the shell")
+	}
+
+	if cmd := s.c.App.Command(name); cmd != nil && len(args) > 1 && findSubcommand(cmd, args[1]) != nil {
+		return s.runCommand(args)
+	}
+
+	for _, cmd := range shellCommands {
+		if cmd.name == name {
+			return cmd.run(s, args[1:])
+		}
+	}
+
+	return s.runCommand(args)
+}
+
+// runCommand runs any other soundtouch-cli command against the current device
+func (s *shellSession) runCommand(args []string) error {
+	app := s.c.App
+
+	if app.Command(args[0]) == nil {
+		return fmt.Errorf("unknown command %q, type 'help' for the commands", args[0])
+	}
+
+	return app.Run(deviceCommandArgs(s.c, s.device, args...))
+}
+
+// connect creates the client for a device, reads its state and subscribes to its events
+func (s *shellSession) connect(device string) error {
+	if device != "" && device != s.c.String("device") {
+		if err := s.c.Set("device", device); err != nil {
+			return fmt.Errorf("failed to select device %s: %w", device, err)
+		}
+	}
+
+	clientConfig := GetClientConfig(s.c)
+
+	soundTouchClient, err := CreateSoundTouchClient(clientConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	state := client.NewDeviceState(soundTouchClient)
+	stateErr := state.Start(newWebSocketConfig(true, false))
+
+	if state.DeviceInfo() == nil {
+		return fmt.Errorf("failed to connect to device: %w", stateErr)
+	}
+
+	if device == "" {
+		device = selectedDevice(s.c)
+	}
+
+	s.device = device
+	s.config = clientConfig
+	s.client = soundTouchClient
+	s.state = state
+
+	s.mu.Lock()
+	s.lastStatus = ""
+	s.mu.Unlock()
+
+	if s.terminal != nil {
+		s.terminal.SetPrompt(state.Name() + "> ")
+	}
+
+	if !state.IsLive() {
+		PrintWarning(fmt.Sprintf("Live status unavailable: %v", stateErr))
+	}
+
+	// Events update the status line; without them, commands print it themselves
+	state.OnChange(func(field client.StateField, state *client.DeviceState) {
+		switch field {
+		case client.StateFieldNowPlaying, client.StateFieldVolume, client.StateFieldZone:
+			if state.IsLive() {
+				s.printStatus(false)
+			}
+		}
+	})
+
+	return nil
+}
+
+// disconnect closes the WebSocket connection of the current device
+func (s *shellSession) disconnect() {
+	if s.state == nil {
+		return
+	}
+
+	if s.state.IsLive() {
+		if err := s.state.Stop(); err != nil {
+			PrintWarning(fmt.Sprintf("Error during disconnect: %v", err))
+		}
+	}
+
+	s.state = nil
+}
+
+// printStatus prints the status line; unless forced, only when it changed
+func (s *shellSession) printStatus(force bool) {
+	status := shellStatus{nowPlaying: s.state.NowPlaying(), volume: s.state.Volume(), zone: s.state.Zone()}
+
+	s.mu.Lock()
+	line := formatShellStatus(status, s.state.DeviceInfo().DeviceID)
+	changed := line != s.lastStatus
+	s.lastStatus = line
+	s.mu.Unlock()
+
+	if changed || force {
+		_, _ = fmt.Fprintln(s.out, line)
+	}
+}
+
+// formatShellStatus renders now playing, volume and zone as one line
+func formatShellStatus(status shellStatus, deviceID string) string {
+	var parts []string
+
+	switch np := status.nowPlaying; {
+	case np == nil:
+		parts = append(parts, "❔ Unknown")
+	case np.PlayStatus == models.PlayStatusStandby || np.Source == "STANDBY":
+		parts = append(parts, "⏻  Standby")
+	default:
+		icon := "⏹️ "
+
+		switch np.PlayStatus {
+		case models.PlayStatusPlaying:
+			icon = "▶️ "
+		case models.PlayStatusPaused:
+			icon = "⏸️ "
+		case models.PlayStatusBuffering:
+			icon = "⏳"
+		}
+
+		title := np.GetDisplayTitle()
+		if artist := np.GetDisplayArtist(); artist != "" {
+			title = fmt.Sprintf("%s — %s", title, artist)
+		}
+
+		if np.Source != "" {
+			title = fmt.Sprintf("%s [%s]", title, np.Source)
+		}
+
+		parts = append(parts, fmt.Sprintf("%s %s", icon, title))
+	}
+
+	if status.volume != nil {
+		if status.volume.IsMuted() {
+			parts = append(parts, fmt.Sprintf("🔇 %d (muted)", status.volume.GetLevel()))
+		} else {
+			parts = append(parts, fmt.Sprintf("🔊 %d", status.volume.GetLevel()))
+		}
+	}
+
+	switch zone := status.zone; {
+	case zone == nil || zone.Master == "":
+	case zone.Master == deviceID:
+		parts = append(parts, fmt.Sprintf("🔗 zone master, %d members", len(zone.Members)))
+	default:
+		parts = append(parts, fmt.Sprintf("🔗 zone member of %s", zone.Master))
+	}
+
+	return strings.Join(parts, " | ")
+}
+
+// printHelp lists the built-in commands
+func (s *shellSession) printHelp() {
+	_, _ = fmt.Fprintln(s.out, "Commands:")
+
+	for _, cmd := range shellCommands {
+		_, _ = fmt.Fprintf(s.out, "  %-22s %s\n", strings.TrimSpace(cmd.name+" "+cmd.args), cmd.usage)
+	}
+
+	_, _ = fmt.Fprintf(s.out, "  %-22s %s\n", "help", "Show this help")
+	_, _ = fmt.Fprintf(s.out, "  %-22s %s\n", "exit", "Leave the shell")
+	_, _ = fmt.Fprintln(s.out, "Every other soundtouch-cli command works as well, e.g. 'bass get' or 'zone status'.")
+	_, _ = fmt.Fprintln(s.out, "Press Tab to complete commands, presets, sources and device names.")
+}
+
+func (s *shellSession) play(_ []string) error {
+	return s.client.Play()
+}
+
+func (s *shellSession) pause(_ []string) error {
+	return s.client.Pause()
+}
+
+func (s *shellSession) stop(_ []string) error {
+	return s.client.Stop()
+}
+
+func (s *shellSession) next(_ []string) error {
+	return s.client.NextTrack()
+}
+
+func (s *shellSession) prev(_ []string) error {
+	return s.client.PrevTrack()
+}
+
+func (s *shellSession) mute(_ []string) error {
+	return s.client.SendKey(models.KeyMute)
+}
+
+// toggle pauses while playing and plays otherwise
+func (s *shellSession) toggle(_ []string) error {
+	if np := s.state.NowPlaying(); np != nil && np.PlayStatus == models.PlayStatusPlaying {
+		return s.client.Pause()
+	}
+
+	return s.client.Play()
+}
+
+// volume shows, sets, raises or lowers the volume
+func (s *shellSession) volume(args []string) error {
+	var err error
+
+	switch {
+	case len(args) == 0:
+	case args[0] == "+" || args[0] == "up":
+		_, err = s.client.IncreaseVolume(shellVolumeStep)
+	case args[0] == "-" || args[0] == "down":
+		_, err = s.client.DecreaseVolume(shellVolumeStep)
+	default:
+		level, convErr := strconv.Atoi(args[0])
+		if convErr != nil || !models.ValidateVolumeLevel(level) {
+			return fmt.Errorf("invalid volume %q: use 0-100, + or -", args[0])
+		}
+
+		err = s.client.SetVolume(level)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// While live, the volume event prints the change
+	if len(args) == 0 || !s.state.IsLive() {
+		if err := s.state.RefreshField(client.StateFieldVolume); err != nil {
+			return err
+		}
+
+		s.printStatus(len(args) == 0)
+	}
+
+	return nil
+}
+
+// preset plays one of the presets 1-6
+func (s *shellSession) preset(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: preset <1-6>")
+	}
+
+	number, err := strconv.Atoi(args[0])
+	if err != nil || number < 1 || number > 6 {
+		return fmt.Errorf("invalid preset %q: use 1-6", args[0])
+	}
+
+	return s.client.SelectPreset(number)
+}
+
+// source selects a source by its name, optionally followed by the account
+func (s *shellSession) source(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: source <source> [account]")
+	}
+
+	account := ""
+	if len(args) > 1 {
+		account = args[1]
+	}
+
+	sources, err := s.loadSources()
+	if err != nil {
+		return err
+	}
+
+	for _, item := range sources {
+		if strings.EqualFold(item.Source, args[0]) && (account == "" || item.SourceAccount == account) {
+			return s.client.SelectSource(item.Source, item.SourceAccount)
+		}
+	}
+
+	return s.client.SelectSource(strings.ToUpper(args[0]), account)
+}
+
+// showStatus prints the status line, reading the state again unless events keep it current
+func (s *shellSession) showStatus(_ []string) error {
+	if !s.state.IsLive() {
+		_ = s.state.Refresh()
+	}
+
+	s.printStatus(true)
+
+	return nil
+}
+
+// switchDevice connects the shell to another device
+func (s *shellSession) switchDevice(args []string) error {
+	if len(args) != 1 {
+		_, _ = fmt.Fprintf(s.out, "Device: %s (%s:%d)\n", s.state.Name(), s.config.Host, s.config.Port)
+		return nil
+	}
+
+	previous := s.device
+
+	s.disconnect()
+
+	if err := s.connect(args[0]); err != nil {
+		if reconnectErr := s.connect(previous); reconnectErr != nil {
+			PrintWarning(fmt.Sprintf("Failed to reconnect to %s: %v", previous, reconnectErr))
+		}
+
+		return err
+	}
+
+	s.printStatus(true)
+
+	return nil
+}
+
+// loadSources returns the available sources, read again after source events
+func (s *shellSession) loadSources() ([]models.SourceItem, error) {
+	if s.state.Sources() == nil {
+		if err := s.state.RefreshField(client.StateFieldSources); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.state.Sources().GetAvailableSources(), nil
+}
+
+// complete is the tab completion callback of the terminal
+func (s *shellSession) complete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' {
+		return "", 0, false
+	}
+
+	newLine, newPos, options := completeShellLine(line, pos, s.candidates)
+	if len(options) > 1 {
+		_, _ = fmt.Fprintln(s.out, strings.Join(options, "  "))
+	}
+
+	return newLine, newPos, true
+}
+
+// candidates returns the words that can follow the given words of a line
+func (s *shellSession) candidates(words []string) []string {
+	if len(words) == 0 {
+		names := []string{"help", "exit"}
+		for _, cmd := range shellCommands {
+			names = append(names, cmd.name)
+		}
+
+		for _, cmd := range s.c.App.VisibleCommands() {
+			if cmd.Name != "shell" {
+				names = append(names, cmd.Name)
+			}
+		}
+
+		return names
+	}
+
+	var names []string
+
+	if len(words) == 1 {
+		names = s.argumentCandidates(strings.ToLower(words[0]))
+	}
+
+	// Subcommands of regular commands
+	cmd := s.c.App.Command(words[0])
+	for _, word := range words[1:] {
+		if cmd == nil {
+			break
+		}
+
+		cmd = findSubcommand(cmd, word)
+	}
+
+	if cmd != nil {
+		for _, sub := range cmd.VisibleCommands() {
+			names = append(names, sub.Name)
+		}
+	}
+
+	return names
+}
+
+// argumentCandidates returns the presets, sources or devices the built-in command of that name takes
+func (s *shellSession) argumentCandidates(name string) []string {
+	for _, cmd := range shellCommands {
+		if cmd.name != name {
+			continue
+		}
+
+		switch cmd.arg {
+		case shellArgPreset:
+			return []string{"1", "2", "3", "4", "5", "6"}
+		case shellArgSource:
+			sources, _ := s.loadSources()
+
+			var names []string
+			for _, item := range sources {
+				names = append(names, item.Source)
+			}
+
+			return names
+		case shellArgDevice:
+			return s.deviceNames()
+		}
+	}
+
+	return nil
+}
+
+// findSubcommand returns the subcommand of cmd with the given name or alias
+func findSubcommand(cmd *cli.Command, name string) *cli.Command {
+	for _, sub := range cmd.Subcommands {
+		if sub.HasName(name) {
+			return sub
+		}
+	}
+
+	return nil
+}
+
+// deviceNames returns the devices of the config file and the device registry
+func (s *shellSession) deviceNames() []string {
+	var names []string
+
+	if profiles := openProfiles(s.c); profiles != nil {
+		names = append(names, profiles.DeviceNames()...)
+	}
+
+	if registry := openDeviceRegistry(); registry != nil {
+		for _, entry := range registry.Entries() {
+			if entry.Name != "" {
+				names = append(names, entry.Name)
+			}
+		}
+	}
+
+	return names
+}
+
+// completeShellLine completes the word before pos. It returns the new line and cursor position and,
+// if the word is ambiguous, the matching candidates.
+func completeShellLine(line string, pos int, candidates func(words []string) []string) (string, int, []string) {
+	head := line[:pos]
+
+	start := strings.LastIndexAny(head, " \t") + 1
+	prefix := head[start:]
+
+	var matches []string
+
+	seen := make(map[string]bool)
+
+	for _, candidate := range candidates(strings.Fields(head[:start])) {
+		if seen[candidate] || strings.ContainsAny(candidate, " \t") {
+			continue
+		}
+
+		if strings.HasPrefix(strings.ToLower(candidate), strings.ToLower(prefix)) {
+			seen[candidate] = true
+			matches = append(matches, candidate)
+		}
+	}
+
+	sort.Strings(matches)
+
+	switch len(matches) {
+	case 0:
+		return line, pos, nil
+	case 1:
+		completed := head[:start] + matches[0] + " "
+		return completed + line[pos:], len(completed), nil
+	}
+
+	common := matches[0]
+	for _, match := range matches[1:] {
+		for !strings.HasPrefix(strings.ToLower(match), strings.ToLower(common)) {
+			common = common[:len(common)-1]
+		}
+	}
+
+	if len(common) > len(prefix) {
+		completed := head[:start] + common
+		return completed + line[pos:], len(completed), nil
+	}
+
+	return line, pos, matches
+}
+
+// Actions of the single-key remote mode
+const (
+	remoteToggle     = "toggle"
+	remoteVolumeUp   = "volume-up"
+	remoteVolumeDown = "volume-down"
+	remoteNext       = "next"
+	remotePrev       = "prev"
+	remoteMute       = "mute"
+	remoteQuit       = "quit"
+)
+
+// parseRemoteKeys translates the bytes read from a raw terminal into remote actions.
+// Presets are returned as their number "1" to "6"; unknown keys are ignored.
+func parseRemoteKeys(input []byte) []string {
+	var actions []string
+
+	for i := 0; i < len(input); i++ {
+		switch b := input[i]; {
+		case b == 0x1b && i+2 < len(input) && input[i+1] == '[':
+			switch input[i+2] {
+			case 'A':
+				actions = append(actions, remoteVolumeUp)
+			case 'B':
+				actions = append(actions, remoteVolumeDown)
+			case 'C':
+				actions = append(actions, remoteNext)
+			case 'D':
+				actions = append(actions, remotePrev)
+			}
+
+			i += 2
+		case b == 0x1b, b == 'q', b == 'Q', b == 0x03, b == 0x04:
+			actions = append(actions, remoteQuit)
+		case b == ' ':
+			actions = append(actions, remoteToggle)
+		case b >= '1' && b <= '6':
+			actions = append(actions, string(b))
+		case b == '+', b == '=':
+			actions = append(actions, remoteVolumeUp)
+		case b == '-':
+			actions = append(actions, remoteVolumeDown)
+		case b == 'n', b == '>':
+			actions = append(actions, remoteNext)
+		case b == 'p', b == '<':
+			actions = append(actions, remotePrev)
+		case b == 'm', b == 'M':
+			actions = append(actions, remoteMute)
+		}
+	}
+
+	return actions
+}
+
+// remote turns the terminal into a remote control until q or Esc is pressed
+func (s *shellSession) remote(_ []string) error {
+	if !s.interactive {
+		return fmt.Errorf("remote mode needs a terminal")
+	}
+
+	_, _ = fmt.Fprintln(s.out, "Remote: space play/pause, ↑/↓ volume, ←/→ previous/next, 1-6 presets, m mute, q or Esc to leave")
+	s.printStatus(true)
+
+	state, err := term.MakeRaw(s.fd)
+	if err != nil {
+		return fmt.Errorf("failed to switch terminal to raw mode: %w", err)
+	}
+
+	defer func() { _ = term.Restore(s.fd, state) }()
+
+	buf := make([]byte, 64)
+
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return nil
+		}
+
+		for _, action := range parseRemoteKeys(buf[:n]) {
+			if action == remoteQuit {
+				return nil
+			}
+
+			if err := s.remoteAction(action); err != nil {
+				_, _ = fmt.Fprintf(s.out, "❌ %v\n", err)
+			}
+		}
+	}
+}
+
+// remoteAction runs one action of the remote mode
+func (s *shellSession) remoteAction(action string) error {
+	switch action {
+	case remoteToggle:
+		return s.toggle(nil)
+	case remoteVolumeUp:
+		return s.volume([]string{"+"})
+	case remoteVolumeDown:
+		return s.volume([]string{"-"})
+	case remoteNext:
+		return s.next(nil)
+	case remotePrev:
+		return s.prev(nil)
+	case remoteMute:
+		return s.mute(nil)
+	default:
+		return s.preset([]string{action})
+	}
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/urfave/cli/v2"
+)
+
+func TestCompleteShellLine(t *testing.T) {
+	candidates := func(words []string) []string {
+		switch {
+		case len(words) == 0:
+			return []string{"play", "pause", "preset", "presets", "volume", "vol"}
+		case words[0] == "source":
+			return []string{"SPOTIFY", "TUNEIN", "AUX", "BLUETOOTH"}
+		}
+
+		return nil
+	}
+
+	tests := []struct {
+		name            string
+		line            string
+		pos             int
+		expectedLine    string
+		expectedPos     int
+		expectedOptions []string
+	}{
+		{"unique command", "pau", 3, "pause ", 6, nil},
+		{"common prefix", "pr", 2, "preset", 6, nil},
+		{"ambiguous", "preset", 6, "preset", 6, []string{"preset", "presets"}},
+		{"argument ignores case", "source tu", 9, "source TUNEIN ", 14, nil},
+		{"keeps rest of line", "vo 30", 2, "vol 30", 3, nil},
+		{"no match", "xyz", 3, "xyz", 3, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line, pos, options := completeShellLine(tt.line, tt.pos, candidates)
+			if line != tt.expectedLine || pos != tt.expectedPos || !reflect.DeepEqual(options, tt.expectedOptions) {
+				t.Errorf("completeShellLine(%q, %d) = %q, %d, %v; want %q, %d, %v",
+					tt.line, tt.pos, line, pos, options, tt.expectedLine, tt.expectedPos, tt.expectedOptions)
+			}
+		})
+	}
+}
+
+func TestParseRemoteKeys(t *testing.T) {
+	input := []byte(" \x1b[A\x1b[B\x1b[C\x1b[D3m7xq")
+	expected := []string{remoteToggle, remoteVolumeUp, remoteVolumeDown, remoteNext, remotePrev, "3", remoteMute, remoteQuit}
+
+	if actions := parseRemoteKeys(input); !reflect.DeepEqual(actions, expected) {
+		t.Errorf("parseRemoteKeys() = %v, want %v", actions, expected)
+	}
+
+	if actions := parseRemoteKeys([]byte{0x1b}); !reflect.DeepEqual(actions, []string{remoteQuit}) {
+		t.Errorf("Expected a lone Esc to quit, got %v", actions)
+	}
+}
+
+func TestFormatShellStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   shellStatus
+		expected string
+	}{
+		{"unknown", shellStatus{}, "❔ Unknown"},
+		{
+			name: "playing",
+			status: shellStatus{
+				nowPlaying: &models.NowPlaying{Source: "SPOTIFY", Track: "So What", Artist: "Miles Davis", PlayStatus: models.PlayStatusPlaying},
+				volume:     &models.Volume{ActualVolume: 25},
+			},
+			expected: "▶️  So What — Miles Davis [SPOTIFY] | 🔊 25",
+		},
+		{
+			name: "muted zone master",
+			status: shellStatus{
+				nowPlaying: &models.NowPlaying{Source: "TUNEIN", StationName: "Radio", PlayStatus: models.PlayStatusPaused},
+				volume:     &models.Volume{ActualVolume: 10, MuteEnabled: true},
+				zone:       &models.ZoneInfo{Master: "AABBCC", Members: []models.Member{{DeviceID: "DDEEFF"}, {DeviceID: "112233"}}},
+			},
+			expected: "⏸️  Radio [TUNEIN] | 🔇 10 (muted) | 🔗 zone master, 2 members",
+		},
+		{
+			name:     "standby zone member",
+			status:   shellStatus{nowPlaying: &models.NowPlaying{Source: "STANDBY"}, zone: &models.ZoneInfo{Master: "DDEEFF"}},
+			expected: "⏻  Standby | 🔗 zone member of DDEEFF",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatShellStatus(tt.status, "AABBCC"); got != tt.expected {
+				t.Errorf("formatShellStatus() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestShell_PipedCommands(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mu.Lock()
+		requests = append(requests, strings.TrimSpace(r.Method+" "+r.URL.Path+" "+string(body)))
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/info":
+			_, _ = w.Write([]byte(`<info deviceID="AABBCC"><name>Kitchen</name><type>SoundTouch 10</type></info>`))
+		case "/now_playing":
+			_, _ = w.Write([]byte(`<nowPlaying deviceID="AABBCC" source="TUNEIN"><stationName>Radio</stationName><playStatus>PLAY_STATE</playStatus></nowPlaying>`))
+		case "/volume":
+			_, _ = w.Write([]byte(`<volume deviceID="AABBCC"><targetvolume>30</targetvolume><actualvolume>30</actualvolume><muteenabled>false</muteenabled></volume>`))
+		case "/getZone":
+			_, _ = w.Write([]byte(`<zone />`))
+		case "/sources":
+			_, _ = w.Write([]byte(`<sources deviceID="AABBCC"><sourceItem source="AUX" sourceAccount="AUX" status="READY">AUX IN</sourceItem></sources>`))
+		default:
+			_, _ = w.Write([]byte(`<status>/ok</status>`))
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	t.Setenv("DEVICE_REGISTRY", filepath.Join(dir, "devices.json"))
+	t.Setenv("SOUNDTOUCH_CONFIG", filepath.Join(dir, "config.json"))
+
+	stdin, input, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+
+	previous := os.Stdin
+	os.Stdin = stdin
+
+	defer func() { os.Stdin = previous }()
+
+	_, _ = input.WriteString("vol 20\npreset 3\nsource aux\ntoggle\nvolume get\nexit\nnext\n")
+	_ = input.Close()
+
+	app := &cli.App{
+		Name:  "soundtouch-cli",
+		Flags: CommonFlags,
+		Commands: []*cli.Command{
+			{Name: "shell", Action: runShell, Before: RequireHost},
+			{
+				Name: "volume",
+				Subcommands: []*cli.Command{
+					{
+						Name:   "get",
+						Before: RequireHost,
+						Action: func(c *cli.Context) error {
+							soundTouchClient, err := CreateSoundTouchClient(GetClientConfig(c))
+							if err != nil {
+								return err
+							}
+
+							_, err = soundTouchClient.GetVolume()
+
+							return err
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if err := app.Run([]string{"soundtouch-cli", "--host", strings.TrimPrefix(server.URL, "http://"), "shell"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	joined := strings.Join(requests, "\n")
+	for _, expected := range []string{
+		`POST /volume <volume>20</volume>`,
+		`<key state="press" sender="Gabbo">PRESET_3</key>`,
+		`POST /select <ContentItem source="AUX"`,
+		`<key state="press" sender="Gabbo">PAUSE</key>`,
+	} {
+		if !strings.Contains(joined, expected) {
+			t.Errorf("Expected a request containing %q, got:\n%s", expected, joined)
+		}
+	}
+
+	if !strings.HasSuffix(joined, "GET /volume") {
+		t.Errorf("Expected 'volume get' to run as a regular command, got:\n%s", joined)
+	}
+
+	if strings.Contains(joined, "NEXT_TRACK") {
+		t.Error("Expected commands after exit to be ignored")
+	}
+}
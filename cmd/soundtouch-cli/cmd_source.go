@@ -9,10 +9,10 @@ import (
 )
 
 func printSource(source models.SourceItem) {
-	fmt.Printf("    • %s", source.GetDisplayName())
+	output.Printf("    • %s", source.GetDisplayName())
 
 	if source.SourceAccount != "" && source.SourceAccount != source.Source {
-		fmt.Printf(" (%s)", source.SourceAccount)
+		output.Printf(" (%s)", source.SourceAccount)
 	}
 
 	var attributes []string
@@ -22,10 +22,10 @@ func printSource(source models.SourceItem) {
 	}
 
 	if len(attributes) > 0 {
-		fmt.Printf(" [%s]", strings.Join(attributes, ", "))
+		output.Printf(" [%s]", strings.Join(attributes, ", "))
 	}
 
-	fmt.Println()
+	output.Println()
 }
 
 // listSources handles listing available audio sources
@@ -44,13 +44,15 @@ func listSources(c *cli.Context) error {
 		return fmt.Errorf("failed to get sources: %w", err)
 	}
 
-	fmt.Printf("Available Audio Sources:\n")
-	fmt.Printf("  Device ID: %s\n", sources.DeviceID)
+	emitResult(c, sources)
+
+	output.Printf("Available Audio Sources:\n")
+	output.Printf("  Device ID: %s\n", sources.DeviceID)
 
 	// Show ready sources first
 	availableSources := sources.GetAvailableSources()
 	if len(availableSources) > 0 {
-		fmt.Printf("  Ready Sources:\n")
+		output.Printf("  Ready Sources:\n")
 
 		for _, source := range availableSources {
 			printSource(source)
@@ -58,7 +60,7 @@ func listSources(c *cli.Context) error {
 	}
 
 	// Show all configured sources
-	fmt.Printf("  All Sources:\n")
+	output.Printf("  All Sources:\n")
 
 	for _, source := range sources.SourceItem {
 		status := "Available"
@@ -66,31 +68,31 @@ func listSources(c *cli.Context) error {
 			status = "Remote"
 		}
 
-		fmt.Printf("    • %s (%s)\n", source.GetDisplayName(), status)
+		output.Printf("    • %s (%s)\n", source.GetDisplayName(), status)
 
 		if source.SourceAccount != "" && source.SourceAccount != source.Source {
-			fmt.Printf("      Account: %s\n", source.SourceAccount)
+			output.Printf("      Account: %s\n", source.SourceAccount)
 		}
 	}
 
 	// Show streaming sources
 	streamingSources := sources.GetStreamingSources()
 	if len(streamingSources) > 0 {
-		fmt.Printf("  Streaming Services:\n")
+		output.Printf("  Streaming Services:\n")
 
 		for _, source := range streamingSources {
-			fmt.Printf("    • %s", source.GetDisplayName())
+			output.Printf("    • %s", source.GetDisplayName())
 
 			if source.SourceAccount != "" {
-				fmt.Printf(" (%s)", source.SourceAccount)
+				output.Printf(" (%s)", source.SourceAccount)
 			}
 
-			fmt.Println()
+			output.Println()
 		}
 	}
 
 	// Show service availability summary
-	fmt.Println()
+	output.Println()
 
 	checker := NewServiceAvailabilityChecker(client)
 	checker.PrintServiceAvailabilitySummary()
@@ -236,10 +238,10 @@ func selectLocalInternetRadio(c *cli.Context) error {
 	PrintDeviceHeader("Selecting internet radio stream", clientConfig.Host, clientConfig.Port)
 
 	if itemName != "" {
-		fmt.Printf("  Station: %s\n", itemName)
+		output.Printf("  Station: %s\n", itemName)
 	}
 
-	fmt.Printf("  Location: %s\n", location)
+	output.Printf("  Location: %s\n", location)
 
 	err = client.SelectLocalInternetRadio(location, sourceAccount, itemName, containerArt)
 	if err != nil {
@@ -282,11 +284,11 @@ func selectLocalMusic(c *cli.Context) error {
 	PrintDeviceHeader("Selecting local music content", clientConfig.Host, clientConfig.Port)
 
 	if itemName != "" {
-		fmt.Printf("  Content: %s\n", itemName)
+		output.Printf("  Content: %s\n", itemName)
 	}
 
-	fmt.Printf("  Location: %s\n", location)
-	fmt.Printf("  Account: %s\n", sourceAccount)
+	output.Printf("  Location: %s\n", location)
+	output.Printf("  Account: %s\n", sourceAccount)
 
 	err = client.SelectLocalMusic(location, sourceAccount, itemName, containerArt)
 	if err != nil {
@@ -329,11 +331,11 @@ func selectStoredMusic(c *cli.Context) error {
 	PrintDeviceHeader("Selecting stored music content", clientConfig.Host, clientConfig.Port)
 
 	if itemName != "" {
-		fmt.Printf("  Content: %s\n", itemName)
+		output.Printf("  Content: %s\n", itemName)
 	}
 
-	fmt.Printf("  Location: %s\n", location)
-	fmt.Printf("  Account: %s\n", sourceAccount)
+	output.Printf("  Location: %s\n", location)
+	output.Printf("  Account: %s\n", sourceAccount)
 
 	err = client.SelectStoredMusic(location, sourceAccount, itemName, containerArt)
 	if err != nil {
@@ -402,19 +404,19 @@ func selectContent(c *cli.Context) error {
 
 	PrintDeviceHeader("Selecting content", clientConfig.Host, clientConfig.Port)
 
-	fmt.Printf("  Source: %s\n", source)
-	fmt.Printf("  Location: %s\n", location)
+	output.Printf("  Source: %s\n", source)
+	output.Printf("  Location: %s\n", location)
 
 	if sourceAccount != "" {
-		fmt.Printf("  Account: %s\n", sourceAccount)
+		output.Printf("  Account: %s\n", sourceAccount)
 	}
 
 	if itemName != "" {
-		fmt.Printf("  Name: %s\n", itemName)
+		output.Printf("  Name: %s\n", itemName)
 	}
 
 	if itemType != "" {
-		fmt.Printf("  Type: %s\n", itemType)
+		output.Printf("  Type: %s\n", itemType)
 	}
 
 	err = client.SelectContentItem(contentItem)
@@ -443,29 +445,31 @@ func getServiceAvailability(c *cli.Context) error {
 		return fmt.Errorf("failed to get service availability: %w", err)
 	}
 
-	fmt.Printf("Service Availability Report:\n")
-	fmt.Printf("  Total Services: %d\n", serviceAvailability.GetServiceCount())
-	fmt.Printf("  Available Services: %d\n", serviceAvailability.GetAvailableServiceCount())
-	fmt.Printf("  Unavailable Services: %d\n", serviceAvailability.GetUnavailableServiceCount())
+	emitResult(c, serviceAvailability)
+
+	output.Printf("Service Availability Report:\n")
+	output.Printf("  Total Services: %d\n", serviceAvailability.GetServiceCount())
+	output.Printf("  Available Services: %d\n", serviceAvailability.GetAvailableServiceCount())
+	output.Printf("  Unavailable Services: %d\n", serviceAvailability.GetUnavailableServiceCount())
 
 	// Show available services
-	fmt.Printf("\n✅ Available Services:\n")
+	output.Printf("\n✅ Available Services:\n")
 
 	availableServices := serviceAvailability.GetAvailableServices()
 	if len(availableServices) == 0 {
-		fmt.Printf("    None\n")
+		output.Printf("    None\n")
 	} else {
 		for _, service := range availableServices {
-			fmt.Printf("    • %s\n", formatServiceTypeForDisplay(models.ServiceType(service.Type)))
+			output.Printf("    • %s\n", formatServiceTypeForDisplay(models.ServiceType(service.Type)))
 		}
 	}
 
 	// Show unavailable services with reasons
-	fmt.Printf("\n❌ Unavailable Services:\n")
+	output.Printf("\n❌ Unavailable Services:\n")
 
 	unavailableServices := serviceAvailability.GetUnavailableServices()
 	if len(unavailableServices) == 0 {
-		fmt.Printf("    None\n")
+		output.Printf("    None\n")
 	} else {
 		for _, service := range unavailableServices {
 			reason := ""
@@ -473,12 +477,12 @@ func getServiceAvailability(c *cli.Context) error {
 				reason = fmt.Sprintf(" (%s)", service.Reason)
 			}
 
-			fmt.Printf("    • %s%s\n", formatServiceTypeForDisplay(models.ServiceType(service.Type)), reason)
+			output.Printf("    • %s%s\n", formatServiceTypeForDisplay(models.ServiceType(service.Type)), reason)
 		}
 	}
 
 	// Show service categories
-	fmt.Printf("\n🎵 Streaming Services:\n")
+	output.Printf("\n🎵 Streaming Services:\n")
 
 	streamingServices := serviceAvailability.GetStreamingServices()
 	availableCount := 0
@@ -490,12 +494,12 @@ func getServiceAvailability(c *cli.Context) error {
 			availableCount++
 		}
 
-		fmt.Printf("    %s %s\n", status, formatServiceTypeForDisplay(models.ServiceType(service.Type)))
+		output.Printf("    %s %s\n", status, formatServiceTypeForDisplay(models.ServiceType(service.Type)))
 	}
 
-	fmt.Printf("    Summary: %d/%d streaming services available\n", availableCount, len(streamingServices))
+	output.Printf("    Summary: %d/%d streaming services available\n", availableCount, len(streamingServices))
 
-	fmt.Printf("\n🔗 Local Input Services:\n")
+	output.Printf("\n🔗 Local Input Services:\n")
 
 	localServices := serviceAvailability.GetLocalServices()
 	localAvailableCount := 0
@@ -507,10 +511,10 @@ func getServiceAvailability(c *cli.Context) error {
 			localAvailableCount++
 		}
 
-		fmt.Printf("    %s %s\n", status, formatServiceTypeForDisplay(models.ServiceType(service.Type)))
+		output.Printf("    %s %s\n", status, formatServiceTypeForDisplay(models.ServiceType(service.Type)))
 	}
 
-	fmt.Printf("    Summary: %d/%d local services available\n", localAvailableCount, len(localServices))
+	output.Printf("    Summary: %d/%d local services available\n", localAvailableCount, len(localServices))
 
 	return nil
 }
@@ -537,7 +541,7 @@ func compareSourcesAndAvailability(c *cli.Context) error {
 		return fmt.Errorf("failed to get service availability: %w", err)
 	}
 
-	fmt.Printf("Source vs Availability Comparison:\n\n")
+	output.Printf("Source vs Availability Comparison:\n\n")
 
 	performSourceComparisons(sources, serviceAvailability)
 	printSourceSummary(sources, serviceAvailability)
@@ -575,23 +579,23 @@ func performSourceComparisons(sources *models.Sources, serviceAvailability *mode
 
 // compareServiceStatus compares a single service's configuration vs availability
 func compareServiceStatus(serviceName string, configured, available bool, serviceAvailability *models.ServiceAvailability) {
-	fmt.Printf("🔍 %s:\n", serviceName)
-	fmt.Printf("    Configured: %s\n", boolToStatus(configured))
-	fmt.Printf("    Available: %s\n", boolToStatus(available))
+	output.Printf("🔍 %s:\n", serviceName)
+	output.Printf("    Configured: %s\n", boolToStatus(configured))
+	output.Printf("    Available: %s\n", boolToStatus(available))
 
 	switch {
 	case available && !configured:
-		fmt.Printf("    💡 %s is available but not configured - consider setting it up\n", serviceName)
+		output.Printf("    💡 %s is available but not configured - consider setting it up\n", serviceName)
 	case configured && !available:
-		fmt.Printf("    ⚠️  %s is configured but not available - check device status\n", serviceName)
+		output.Printf("    ⚠️  %s is configured but not available - check device status\n", serviceName)
 		printServiceUnavailableReason(serviceName, serviceAvailability)
 	case configured && available:
-		fmt.Printf("    ✅ %s is properly configured and available\n", serviceName)
+		output.Printf("    ✅ %s is properly configured and available\n", serviceName)
 	default:
-		fmt.Printf("    ➖ %s is neither configured nor available\n", serviceName)
+		output.Printf("    ➖ %s is neither configured nor available\n", serviceName)
 	}
 
-	fmt.Println()
+	output.Println()
 }
 
 // printServiceUnavailableReason prints the reason why a service is unavailable
@@ -606,18 +610,18 @@ func printServiceUnavailableReason(serviceName string, serviceAvailability *mode
 	}
 
 	if service != nil && service.Reason != "" {
-		fmt.Printf("    📝 Reason: %s\n", service.Reason)
+		output.Printf("    📝 Reason: %s\n", service.Reason)
 	}
 }
 
 // printSourceSummary prints a summary of sources and services
 func printSourceSummary(sources *models.Sources, serviceAvailability *models.ServiceAvailability) {
 	// Summary
-	fmt.Printf("📊 Summary:\n")
-	fmt.Printf("    Total configured sources: %d\n", sources.GetSourceCount())
-	fmt.Printf("    Ready configured sources: %d\n", sources.GetReadySourceCount())
-	fmt.Printf("    Total available services: %d\n", serviceAvailability.GetAvailableServiceCount())
-	fmt.Printf("    Total possible services: %d\n", serviceAvailability.GetServiceCount())
+	output.Printf("📊 Summary:\n")
+	output.Printf("    Total configured sources: %d\n", sources.GetSourceCount())
+	output.Printf("    Ready configured sources: %d\n", sources.GetReadySourceCount())
+	output.Printf("    Total available services: %d\n", serviceAvailability.GetAvailableServiceCount())
+	output.Printf("    Total possible services: %d\n", serviceAvailability.GetServiceCount())
 }
 
 // boolToStatus converts boolean to user-friendly status
@@ -48,16 +48,16 @@ func playTTS(c *cli.Context) error {
 		return err
 	}
 
-	fmt.Printf("✅ TTS message sent successfully\n")
+	output.Printf("✅ TTS message sent successfully\n")
 
 	if volume > 0 {
-		fmt.Printf("   Volume: %d\n", volume)
+		output.Printf("   Volume: %d\n", volume)
 	} else {
-		fmt.Printf("   Volume: current level\n")
+		output.Printf("   Volume: current level\n")
 	}
 
-	fmt.Printf("   Language: %s\n", strings.ToUpper(language))
-	fmt.Printf("   Message: \"%s\"\n", text)
+	output.Printf("   Language: %s\n", strings.ToUpper(language))
+	output.Printf("   Message: \"%s\"\n", text)
 
 	return nil
 }
@@ -122,15 +122,15 @@ func playURL(c *cli.Context) error {
 		return err
 	}
 
-	fmt.Printf("✅ URL playback started successfully\n")
-	fmt.Printf("   URL: %s\n", urlStr)
-	fmt.Printf("   Service: %s\n", service)
-	fmt.Printf("   Message: %s\n", message)
+	output.Printf("✅ URL playback started successfully\n")
+	output.Printf("   URL: %s\n", urlStr)
+	output.Printf("   Service: %s\n", service)
+	output.Printf("   Message: %s\n", message)
 
 	if volume > 0 {
-		fmt.Printf("   Volume: %d\n", volume)
+		output.Printf("   Volume: %d\n", volume)
 	} else {
-		fmt.Printf("   Volume: current level\n")
+		output.Printf("   Volume: current level\n")
 	}
 
 	return nil
@@ -165,9 +165,9 @@ func playNotification(c *cli.Context) error {
 	}
 
 	if path != "" {
-		fmt.Printf("✅ Notification file sent successfully: %s\n", path)
+		output.Printf("✅ Notification file sent successfully: %s\n", path)
 	} else {
-		fmt.Printf("✅ Notification beep played successfully\n")
+		output.Printf("✅ Notification beep played successfully\n")
 	}
 
 	return nil
@@ -180,38 +180,38 @@ func playNotificationBeep(c *cli.Context) error {
 
 // showSpeakerHelp displays help information about speaker functionality
 func showSpeakerHelp(_ *cli.Context) error {
-	fmt.Println("SoundTouch Speaker Playback Commands")
-	fmt.Println("=====================================")
-	fmt.Println()
-	fmt.Println("The /speaker endpoint supports playing notifications and URL content:")
-	fmt.Println()
-	fmt.Println("• Text-to-Speech (TTS) Messages:")
-	fmt.Println("  Play spoken messages using Google TTS")
-	fmt.Println("  Example: soundtouch-cli speaker tts --text \"Hello World\" --app-key YOUR_KEY")
-	fmt.Println()
-	fmt.Println("• URL Content Playback:")
-	fmt.Println("  Play audio files from HTTP/HTTPS URLs")
-	fmt.Println("  Example: soundtouch-cli speaker url --url \"https://example.com/audio.mp3\" --app-key YOUR_KEY")
-	fmt.Println()
-	fmt.Println("• Notification Beep:")
-	fmt.Println("  Play a simple notification sound")
-	fmt.Println("  Example: soundtouch-cli speaker beep")
-	fmt.Println()
-	fmt.Println("• Custom Notification:")
-	fmt.Println("  Play a device-local PCM file as notification")
-	fmt.Println("  Example: soundtouch-cli speaker notify --path \"/opt/Bose/chimes/grouped.pcm\"")
-	fmt.Println()
-	fmt.Println("Notes:")
-	fmt.Println("• Only ST-10 (Series III) speakers support the /speaker endpoint")
-	fmt.Println("• ST-300 and other models may not support this functionality")
-	fmt.Println("• You need to provide your own app_key for TTS and URL playback")
-	fmt.Println("• Currently playing content is paused during playback and resumed after")
-	fmt.Println("• If device is a zone master, content plays on all zone members")
-	fmt.Println("• Volume is automatically restored after playback completes")
-	fmt.Println()
-	fmt.Println("Supported Languages for TTS:")
-	fmt.Println("EN (English), DE (German), ES (Spanish), FR (French), IT (Italian),")
-	fmt.Println("NL (Dutch), PT (Portuguese), RU (Russian), ZH (Chinese), JA (Japanese)")
+	output.Println("SoundTouch Speaker Playback Commands")
+	output.Println("=====================================")
+	output.Println()
+	output.Println("The /speaker endpoint supports playing notifications and URL content:")
+	output.Println()
+	output.Println("• Text-to-Speech (TTS) Messages:")
+	output.Println("  Play spoken messages using Google TTS")
+	output.Println("  Example: soundtouch-cli speaker tts --text \"Hello World\" --app-key YOUR_KEY")
+	output.Println()
+	output.Println("• URL Content Playback:")
+	output.Println("  Play audio files from HTTP/HTTPS URLs")
+	output.Println("  Example: soundtouch-cli speaker url --url \"https://example.com/audio.mp3\" --app-key YOUR_KEY")
+	output.Println()
+	output.Println("• Notification Beep:")
+	output.Println("  Play a simple notification sound")
+	output.Println("  Example: soundtouch-cli speaker beep")
+	output.Println()
+	output.Println("• Custom Notification:")
+	output.Println("  Play a device-local PCM file as notification")
+	output.Println("  Example: soundtouch-cli speaker notify --path \"/opt/Bose/chimes/grouped.pcm\"")
+	output.Println()
+	output.Println("Notes:")
+	output.Println("• Only ST-10 (Series III) speakers support the /speaker endpoint")
+	output.Println("• ST-300 and other models may not support this functionality")
+	output.Println("• You need to provide your own app_key for TTS and URL playback")
+	output.Println("• Currently playing content is paused during playback and resumed after")
+	output.Println("• If device is a zone master, content plays on all zone members")
+	output.Println("• Volume is automatically restored after playback completes")
+	output.Println()
+	output.Println("Supported Languages for TTS:")
+	output.Println("EN (English), DE (German), ES (Spanish), FR (French), IT (Italian),")
+	output.Println("NL (Dutch), PT (Portuguese), RU (Russian), ZH (Chinese), JA (Japanese)")
 
 	return nil
 }
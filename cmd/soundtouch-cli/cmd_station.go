@@ -42,6 +42,8 @@ func searchStations(c *cli.Context) error {
 		return err
 	}
 
+	emitResult(c, response)
+
 	printSearchResults(response, searchTerm)
 
 	return nil
@@ -77,6 +79,8 @@ func searchTuneIn(c *cli.Context) error {
 		return err
 	}
 
+	emitResult(c, response)
+
 	printSearchResults(response, searchTerm)
 
 	return nil
@@ -118,6 +122,8 @@ func searchPandora(c *cli.Context) error {
 		return err
 	}
 
+	emitResult(c, response)
+
 	printSearchResults(response, searchTerm)
 
 	return nil
@@ -159,6 +165,8 @@ func searchSpotify(c *cli.Context) error {
 		return err
 	}
 
+	emitResult(c, response)
+
 	printSearchResults(response, searchTerm)
 
 	return nil
@@ -261,14 +269,14 @@ func removeStation(c *cli.Context) error {
 
 // printSearchResults formats and displays search results
 func printSearchResults(response *models.SearchStationResponse, searchTerm string) {
-	fmt.Printf("Search Results for '%s':\n", searchTerm)
+	output.Printf("Search Results for '%s':\n", searchTerm)
 
 	if response.IsEmpty() {
-		fmt.Printf("  No results found\n")
+		output.Printf("  No results found\n")
 		return
 	}
 
-	fmt.Printf("  Total results: %d\n", response.GetResultCount())
+	output.Printf("  Total results: %d\n", response.GetResultCount())
 
 	// Group results by type for better display
 	songs := response.GetSongs()
@@ -287,26 +295,26 @@ func printSongs(songs []models.SearchResult) {
 		return
 	}
 
-	fmt.Printf("\n  🎵 Songs (%d):\n", len(songs))
+	output.Printf("\n  🎵 Songs (%d):\n", len(songs))
 
 	for i := range songs {
 		song := &songs[i]
-		fmt.Printf("    %d. %s\n", i+1, song.GetDisplayName())
+		output.Printf("    %d. %s\n", i+1, song.GetDisplayName())
 
 		if song.Artist != "" {
-			fmt.Printf("       Artist: %s\n", song.Artist)
+			output.Printf("       Artist: %s\n", song.Artist)
 		}
 
 		if song.Album != "" {
-			fmt.Printf("       Album: %s\n", song.Album)
+			output.Printf("       Album: %s\n", song.Album)
 		}
 
 		if song.SourceAccount != "" {
-			fmt.Printf("       Account: %s\n", song.SourceAccount)
+			output.Printf("       Account: %s\n", song.SourceAccount)
 		}
 
-		fmt.Printf("       Token: %s\n", song.Token)
-		fmt.Println()
+		output.Printf("       Token: %s\n", song.Token)
+		output.Println()
 	}
 }
 
@@ -316,18 +324,18 @@ func printArtists(artists []models.SearchResult) {
 		return
 	}
 
-	fmt.Printf("  🎤 Artists (%d):\n", len(artists))
+	output.Printf("  🎤 Artists (%d):\n", len(artists))
 
 	for i := range artists {
 		artist := &artists[i]
-		fmt.Printf("    %d. %s\n", i+1, artist.GetDisplayName())
+		output.Printf("    %d. %s\n", i+1, artist.GetDisplayName())
 
 		if artist.SourceAccount != "" {
-			fmt.Printf("       Account: %s\n", artist.SourceAccount)
+			output.Printf("       Account: %s\n", artist.SourceAccount)
 		}
 
-		fmt.Printf("       Token: %s\n", artist.Token)
-		fmt.Println()
+		output.Printf("       Token: %s\n", artist.Token)
+		output.Println()
 	}
 }
 
@@ -337,37 +345,37 @@ func printStations(stations []models.SearchResult) {
 		return
 	}
 
-	fmt.Printf("  📻 Stations (%d):\n", len(stations))
+	output.Printf("  📻 Stations (%d):\n", len(stations))
 
 	for i := range stations {
 		station := &stations[i]
-		fmt.Printf("    %d. %s\n", i+1, station.GetDisplayName())
+		output.Printf("    %d. %s\n", i+1, station.GetDisplayName())
 
 		if station.SourceAccount != "" {
-			fmt.Printf("       Account: %s\n", station.SourceAccount)
+			output.Printf("       Account: %s\n", station.SourceAccount)
 		}
 
-		fmt.Printf("       Token: %s\n", station.Token)
+		output.Printf("       Token: %s\n", station.Token)
 
 		if station.Description != "" {
-			fmt.Printf("       Description: %s\n", station.Description)
+			output.Printf("       Description: %s\n", station.Description)
 		}
 
-		fmt.Println()
+		output.Println()
 	}
 }
 
 // printSearchHints prints usage hints for search results
 func printSearchHints(response *models.SearchStationResponse, songs, artists, stations []models.SearchResult) {
-	fmt.Printf("💡 Usage hints:\n")
-	fmt.Printf("   • To add a station and play it: station add --source %s --token <token> --name <name>\n", response.Source)
+	output.Printf("💡 Usage hints:\n")
+	output.Printf("   • To add a station and play it: station add --source %s --token <token> --name <name>\n", response.Source)
 
 	if hasAccountResults(response) {
-		fmt.Printf("   • Include --source-account <account> when adding stations that require it\n")
+		output.Printf("   • Include --source-account <account> when adding stations that require it\n")
 	}
 
 	if len(songs) > 0 || len(artists) > 0 || len(stations) > 0 {
-		fmt.Printf("   • Copy the token from results above to use with 'station add'\n")
+		output.Printf("   • Copy the token from results above to use with 'station add'\n")
 	}
 }
 
@@ -426,6 +434,7 @@ func listStations(c *cli.Context) error {
 		return err
 	}
 
+	emitResult(c, response)
 	printStationList(response, source)
 
 	return nil
@@ -433,43 +442,43 @@ func listStations(c *cli.Context) error {
 
 // printStationList formats and displays saved station results
 func printStationList(response *models.NavigateResponse, source string) {
-	fmt.Printf("Saved %s Stations:\n", source)
+	output.Printf("Saved %s Stations:\n", source)
 
 	if response.TotalItems == 0 {
-		fmt.Printf("  No stations found\n")
+		output.Printf("  No stations found\n")
 		return
 	}
 
 	stations := response.GetStations()
-	fmt.Printf("  Total stations: %d\n", response.TotalItems)
-	fmt.Printf("  Showing: %d\n\n", len(stations))
+	output.Printf("  Total stations: %d\n", response.TotalItems)
+	output.Printf("  Showing: %d\n\n", len(stations))
 
 	for i, station := range stations {
-		fmt.Printf("  %d. %s\n", i+1, station.Name)
+		output.Printf("  %d. %s\n", i+1, station.Name)
 
 		if station.ContentItem != nil {
 			if station.ContentItem.Location != "" {
-				fmt.Printf("     Location: %s\n", station.ContentItem.Location)
+				output.Printf("     Location: %s\n", station.ContentItem.Location)
 			}
 
 			if station.ContentItem.SourceAccount != "" {
-				fmt.Printf("     Account: %s\n", station.ContentItem.SourceAccount)
+				output.Printf("     Account: %s\n", station.ContentItem.SourceAccount)
 			}
 
 			if station.ContentItem.IsPresetable {
-				fmt.Printf("     Can be saved as preset: Yes\n")
+				output.Printf("     Can be saved as preset: Yes\n")
 			}
 		}
 
 		if station.Type != "" {
-			fmt.Printf("     Type: %s\n", station.Type)
+			output.Printf("     Type: %s\n", station.Type)
 		}
 
-		fmt.Println()
+		output.Println()
 	}
 
 	// Show usage hints
-	fmt.Printf("💡 Usage hints:\n")
-	fmt.Printf("   • To play a station: Use the location value with 'play content' command\n")
-	fmt.Printf("   • To save as preset: Use 'preset set' command with the location\n")
+	output.Printf("💡 Usage hints:\n")
+	output.Printf("   • To play a station: Use the location value with 'play content' command\n")
+	output.Printf("   • To save as preset: Use 'preset set' command with the location\n")
 }
@@ -23,32 +23,32 @@ func requestToken(c *cli.Context) error {
 		return err
 	}
 
-	fmt.Println("Bearer Token Information:")
+	output.Println("Bearer Token Information:")
 
 	if token.IsValid() {
-		fmt.Printf("  Status: Valid\n")
-		fmt.Printf("  Token: %s\n", token.String())
-		fmt.Printf("  Full value: %s\n", token.GetToken())
-		fmt.Printf("  Authorization header: %s\n", token.GetAuthHeader())
+		output.Printf("  Status: Valid\n")
+		output.Printf("  Token: %s\n", token.String())
+		output.Printf("  Full value: %s\n", token.GetToken())
+		output.Printf("  Authorization header: %s\n", token.GetAuthHeader())
 
 		// Display token without Bearer prefix for API usage
-		fmt.Println("\nFor API Usage:")
-		fmt.Printf("  Raw token: %s\n", token.GetTokenWithoutPrefix())
+		output.Println("\nFor API Usage:")
+		output.Printf("  Raw token: %s\n", token.GetTokenWithoutPrefix())
 
 		// Usage instructions
-		fmt.Println("\nUsage Instructions:")
-		fmt.Println("  • Use the 'Authorization header' value in HTTP Authorization headers")
-		fmt.Println("  • Use the 'Raw token' value when an API requires token without 'Bearer ' prefix")
-		fmt.Println("  • Tokens are generated per request and may have expiration times")
+		output.Println("\nUsage Instructions:")
+		output.Println("  • Use the 'Authorization header' value in HTTP Authorization headers")
+		output.Println("  • Use the 'Raw token' value when an API requires token without 'Bearer ' prefix")
+		output.Println("  • Tokens are generated per request and may have expiration times")
 
 		// Security notice
-		fmt.Println("\nSecurity Notice:")
-		fmt.Println("  • Store tokens securely and avoid logging them in plain text")
-		fmt.Println("  • Tokens provide authentication - treat them as passwords")
-		fmt.Println("  • Request new tokens when needed rather than reusing old ones")
+		output.Println("\nSecurity Notice:")
+		output.Println("  • Store tokens securely and avoid logging them in plain text")
+		output.Println("  • Tokens provide authentication - treat them as passwords")
+		output.Println("  • Request new tokens when needed rather than reusing old ones")
 	} else {
-		fmt.Printf("  Status: Invalid\n")
-		fmt.Printf("  Raw response: %s\n", token.GetToken())
+		output.Printf("  Status: Invalid\n")
+		output.Printf("  Raw response: %s\n", token.GetToken())
 		PrintError("Received invalid bearer token from device")
 	}
 
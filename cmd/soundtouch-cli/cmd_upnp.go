@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gesellix/bose-soundtouch/pkg/client"
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/urfave/cli/v2"
+)
+
+// newMediaRenderer connects to the UPnP MediaRenderer given by --location or, by default, of --host
+func newMediaRenderer(c *cli.Context) (*client.MediaRenderer, error) {
+	clientConfig := GetClientConfig(c)
+
+	if location := c.String("location"); location != "" {
+		return client.NewMediaRendererFromDescription(location, clientConfig.Timeout)
+	}
+
+	if clientConfig.Host == "" {
+		return nil, fmt.Errorf("host is required. Use --host flag, set SOUNDTOUCH_HOST or pass --location")
+	}
+
+	soundtouchClient, err := CreateSoundTouchClient(clientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	return soundtouchClient.MediaRenderer()
+}
+
+// upnpPlay plays an HTTP audio URL through the UPnP AVTransport service
+func upnpPlay(c *cli.Context) error {
+	url := c.Args().First()
+	if url == "" {
+		PrintError("URL is required")
+		return fmt.Errorf("usage: upnp play <url>")
+	}
+
+	renderer, err := newMediaRenderer(c)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	if c.IsSet("volume") {
+		if err := renderer.SetVolume(c.Int("volume")); err != nil {
+			PrintError(fmt.Sprintf("Failed to set volume: %v", err))
+			return err
+		}
+	}
+
+	if err := renderer.PlayURL(url, c.String("title")); err != nil {
+		PrintError(fmt.Sprintf("Failed to play URL: %v", err))
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Playing %s via UPnP", url))
+
+	return nil
+}
+
+// upnpPause pauses UPnP playback
+func upnpPause(c *cli.Context) error {
+	return upnpTransport(c, "Paused", (*client.MediaRenderer).Pause)
+}
+
+// upnpResume resumes UPnP playback
+func upnpResume(c *cli.Context) error {
+	return upnpTransport(c, "Resumed", (*client.MediaRenderer).Play)
+}
+
+// upnpStop stops UPnP playback
+func upnpStop(c *cli.Context) error {
+	return upnpTransport(c, "Stopped", (*client.MediaRenderer).Stop)
+}
+
+func upnpTransport(c *cli.Context, done string, action func(*client.MediaRenderer) error) error {
+	renderer, err := newMediaRenderer(c)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	if err := action(renderer); err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	PrintSuccess(done)
+
+	return nil
+}
+
+// upnpStatus prints the transport state and position of the UPnP renderer
+func upnpStatus(c *cli.Context) error {
+	renderer, err := newMediaRenderer(c)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	transport, err := renderer.GetTransportInfo()
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to get transport info: %v", err))
+		return err
+	}
+
+	output.Printf("State:    %s (%s)\n", transport.State, transport.Status)
+
+	status := upnpStatusResult{Transport: transport}
+
+	if position, err := renderer.GetPositionInfo(); err == nil {
+		status.Position = position
+
+		if position.TrackURI != "" {
+			output.Printf("URL:      %s\n", position.TrackURI)
+		}
+
+		output.Printf("Position: %s / %s\n", position.RelTime, position.TrackDuration)
+	}
+
+	if volume, err := renderer.GetVolume(); err == nil {
+		status.Volume = &volume
+
+		output.Printf("Volume:   %d\n", volume)
+	}
+
+	emitResult(c, status)
+
+	return nil
+}
+
+// upnpStatusResult is the structured output of upnp status
+type upnpStatusResult struct {
+	Transport *models.UPnPTransportInfo `json:"transport"`
+	Position  *models.UPnPPositionInfo  `json:"position,omitempty"`
+	Volume    *int                      `json:"volume,omitempty"`
+}
+
+// upnpDescribe prints the UPnP device description of a speaker
+func upnpDescribe(c *cli.Context) error {
+	clientConfig := GetClientConfig(c)
+
+	location := c.String("location")
+	if location == "" {
+		if clientConfig.Host == "" {
+			err := fmt.Errorf("host is required. Use --host flag, set SOUNDTOUCH_HOST or pass --location")
+			PrintError(err.Error())
+
+			return err
+		}
+
+		soundtouchClient, err := CreateSoundTouchClient(clientConfig)
+		if err != nil {
+			PrintError(fmt.Sprintf("Failed to create client: %v", err))
+			return err
+		}
+
+		info, err := soundtouchClient.GetDeviceInfo()
+		if err != nil {
+			PrintError(err.Error())
+			return err
+		}
+
+		location = client.SoundTouchDescriptionURL(clientConfig.Host, info.DeviceID)
+	}
+
+	description, err := client.GetUPnPDescription(location, clientConfig.Timeout)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	emitResult(c, description)
+
+	device := description.Device
+	output.Printf("Location:      %s\n", location)
+	output.Printf("Friendly name: %s\n", device.FriendlyName)
+	output.Printf("Device type:   %s\n", device.DeviceType)
+	output.Printf("Manufacturer:  %s\n", device.Manufacturer)
+	output.Printf("Model:         %s %s\n", device.ModelName, device.ModelNumber)
+	output.Printf("Serial number: %s\n", device.SerialNumber)
+	output.Printf("UDN:           %s\n", device.UDN)
+	output.Println("Services:")
+
+	for _, service := range description.Services(location) {
+		output.Printf("  %s\n", service.ServiceType)
+		output.Printf("    Control: %s\n", service.ControlURL)
+	}
+
+	return nil
+}
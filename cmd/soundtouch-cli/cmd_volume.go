@@ -24,14 +24,16 @@ func getVolume(c *cli.Context) error {
 		return fmt.Errorf("failed to get volume: %w", err)
 	}
 
-	fmt.Printf("Current Volume:\n")
-	fmt.Printf("  Device ID: %s\n", volume.DeviceID)
-	fmt.Printf("  Current Level: %d (%s)\n", volume.GetLevel(), models.GetVolumeLevelName(volume.GetLevel()))
-	fmt.Printf("  Target Level: %d\n", volume.GetTargetLevel())
-	fmt.Printf("  Muted: %v\n", volume.IsMuted())
+	emitResult(c, volume)
+
+	output.Printf("Current Volume:\n")
+	output.Printf("  Device ID: %s\n", volume.DeviceID)
+	output.Printf("  Current Level: %d (%s)\n", volume.GetLevel(), models.GetVolumeLevelName(volume.GetLevel()))
+	output.Printf("  Target Level: %d\n", volume.GetTargetLevel())
+	output.Printf("  Muted: %v\n", volume.IsMuted())
 
 	if !volume.IsVolumeSync() {
-		fmt.Printf("  Note: Volume is adjusting (target: %d, actual: %d)\n", volume.GetTargetLevel(), volume.GetLevel())
+		output.Printf("  Note: Volume is adjusting (target: %d, actual: %d)\n", volume.GetTargetLevel(), volume.GetLevel())
 	}
 
 	return nil
@@ -54,7 +56,7 @@ func setVolume(c *cli.Context) error {
 	// Safety warning for loud volumes
 	if level > 30 {
 		PrintWarning(fmt.Sprintf("Setting volume to %d (this is quite loud!)", level))
-		fmt.Printf("Proceeding in 2 seconds... Press Ctrl+C to cancel\n")
+		output.Printf("Proceeding in 2 seconds... Press Ctrl+C to cancel\n")
 		time.Sleep(2 * time.Second)
 	}
 
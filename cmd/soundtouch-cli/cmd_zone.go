@@ -26,28 +26,30 @@ func getZone(c *cli.Context) error {
 		return err
 	}
 
+	emitResult(c, zone)
+
 	if zone.Master == "" {
-		fmt.Println("Device is not in a zone")
+		output.Println("Device is not in a zone")
 		return nil
 	}
 
-	fmt.Println("Zone Configuration:")
-	fmt.Printf("  Master: %s\n", zone.Master)
+	output.Println("Zone Configuration:")
+	output.Printf("  Master: %s\n", zone.Master)
 
 	if len(zone.Members) > 0 {
-		fmt.Printf("  Members (%d):\n", len(zone.Members))
+		output.Printf("  Members (%d):\n", len(zone.Members))
 
 		for _, member := range zone.Members {
-			fmt.Printf("    - %s", member.DeviceID)
+			output.Printf("    - %s", member.DeviceID)
 
 			if member.IP != "" {
-				fmt.Printf(" (IP: %s)", member.IP)
+				output.Printf(" (IP: %s)", member.IP)
 			}
 
-			fmt.Println()
+			output.Println()
 		}
 	} else {
-		fmt.Println("  Members: none (standalone device)")
+		output.Println("  Members: none (standalone device)")
 	}
 
 	return nil
@@ -70,13 +72,15 @@ func getZoneStatus(c *cli.Context) error {
 		return err
 	}
 
-	fmt.Printf("Zone Status: %s\n", status)
+	emitResult(c, status)
+
+	output.Printf("Zone Status: %s\n", status)
 
 	inZone, err := client.IsInZone()
 	if err != nil {
 		PrintWarning(fmt.Sprintf("Could not determine zone membership: %v", err))
 	} else {
-		fmt.Printf("In Zone: %t\n", inZone)
+		output.Printf("In Zone: %t\n", inZone)
 	}
 
 	return nil
@@ -99,21 +103,23 @@ func getZoneMembers(c *cli.Context) error {
 		return err
 	}
 
+	emitResult(c, members)
+
 	if len(members) == 0 {
-		fmt.Println("No zone members found")
+		output.Println("No zone members found")
 		return nil
 	}
 
-	fmt.Printf("Zone Members (%d):\n", len(members))
+	output.Printf("Zone Members (%d):\n", len(members))
 
 	for i, member := range members {
-		fmt.Printf("  %d. %s", i+1, member)
+		output.Printf("  %d. %s", i+1, member)
 
 		if member == clientConfig.Host {
-			fmt.Print(" (this device)")
+			output.Print(" (this device)")
 		}
 
-		fmt.Println()
+		output.Println()
 	}
 
 	return nil
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/client"
+	"github.com/gesellix/bose-soundtouch/pkg/config"
+	"github.com/gesellix/bose-soundtouch/pkg/discovery"
+	"github.com/urfave/cli/v2"
+)
+
+// newZoneManager discovers speakers and creates a zone manager addressing them by name
+func newZoneManager(c *cli.Context) (*client.ZoneManager, error) {
+	cfg, err := config.LoadFromEnv()
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+
+	updateConfigFromCLI(c, cfg)
+
+	discoveryService := discovery.NewUnifiedDiscoveryService(cfg)
+
+	registry := openDeviceRegistry()
+	if registry != nil {
+		discoveryService.SetRegistry(registry)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.DiscoveryTimeout+5*time.Second)
+	defer cancel()
+
+	devices, err := discoveryService.DiscoverDevices(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("discovery failed: %w", err)
+	}
+
+	manager := client.NewZoneManager(devices)
+	manager.SetEventsEnabled(true)
+
+	if registry != nil {
+		manager.SetResolver(registry)
+	}
+
+	// A speaker given with --host is always known, even if discovery missed it
+	if clientConfig := GetClientConfig(c); clientConfig.Host != "" {
+		manager.AddSpeaker(client.ZoneSpeaker{Host: clientConfig.Host, Port: clientConfig.Port})
+	}
+
+	if len(manager.Speakers()) == 0 {
+		return nil, fmt.Errorf("no speakers found")
+	}
+
+	return manager, nil
+}
+
+// groupSpeakers creates a zone from speaker names
+func groupSpeakers(c *cli.Context) error {
+	master := c.String("master")
+	members := c.StringSlice("members")
+	output.Printf("Grouping %v under %s...\n", members, master)
+
+	manager, err := newZoneManager(c)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	zone, err := manager.CreateZone(master, members...)
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to create zone: %v", err))
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Zone: %s", manager.ZoneSummary(zone)))
+
+	return nil
+}
+
+// moveMusic moves the music playing on one speaker to another, which becomes the new master
+func moveMusic(c *cli.Context) error {
+	from := c.String("from")
+	to := c.String("to")
+	output.Printf("Moving music from %s to %s...\n", from, to)
+
+	manager, err := newZoneManager(c)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	zone, err := manager.MoveMusic(from, to)
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to move music: %v", err))
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Zone: %s", manager.ZoneSummary(zone)))
+
+	return nil
+}
+
+// joinSpeaker adds a speaker to whatever another speaker is playing
+func joinSpeaker(c *cli.Context) error {
+	speaker := c.String("speaker")
+	with := c.String("with")
+	output.Printf("Joining %s to %s...\n", speaker, with)
+
+	manager, err := newZoneManager(c)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	zone, err := manager.Join(speaker, with)
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to join: %v", err))
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Zone: %s", manager.ZoneSummary(zone)))
+
+	return nil
+}
+
+// splitSpeaker removes a speaker from its zone while the rest keeps playing
+func splitSpeaker(c *cli.Context) error {
+	speaker := c.String("speaker")
+	output.Printf("Splitting %s off its zone...\n", speaker)
+
+	manager, err := newZoneManager(c)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	if err := manager.SplitOff(speaker); err != nil {
+		PrintError(fmt.Sprintf("Failed to split off: %v", err))
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("%s is now standalone", speaker))
+
+	return nil
+}
+
+// zoneGroupVolume shows or changes the volume of a whole zone
+func zoneGroupVolume(c *cli.Context) error {
+	group, err := groupVolumeFor(c)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	var level *client.GroupVolumeLevel
+
+	switch {
+	case c.Bool("mute"):
+		level, err = group.SetMuted(true)
+	case c.Bool("unmute"):
+		level, err = group.SetMuted(false)
+	case c.IsSet("set"):
+		level, err = group.Set(c.Int("set"))
+	case c.IsSet("change"):
+		level, err = group.Change(c.Int("change"))
+	default:
+		level, err = group.Get()
+	}
+
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to control group volume: %v", err))
+		return err
+	}
+
+	muted := ""
+	if level.Muted {
+		muted = " (muted)"
+	}
+
+	output.Printf("Group volume: %d%s\n", level.Level, muted)
+
+	for _, member := range level.Members {
+		name := member.Name
+		if name == "" {
+			name = member.DeviceID
+		}
+
+		state := ""
+		if member.Muted {
+			state = " (muted)"
+		}
+
+		output.Printf("  %-20s %3d%s\n", name, member.Level, state)
+	}
+
+	return nil
+}
+
+// groupVolumeFor builds the group volume from --speaker via discovery, or from --host
+func groupVolumeFor(c *cli.Context) (*client.GroupVolume, error) {
+	if speaker := c.String("speaker"); speaker != "" {
+		output.Printf("Zone volume of %s:\n", speaker)
+
+		manager, err := newZoneManager(c)
+		if err != nil {
+			return nil, err
+		}
+
+		return manager.GroupVolume(speaker)
+	}
+
+	if err := RequireHost(c); err != nil {
+		return nil, fmt.Errorf("either --speaker or --host is required")
+	}
+
+	clientConfig := GetClientConfig(c)
+	PrintDeviceHeader("Zone volume", clientConfig.Host, clientConfig.Port)
+
+	soundTouchClient, err := CreateSoundTouchClient(clientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	return soundTouchClient.GroupVolume()
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gesellix/bose-soundtouch/pkg/client"
+	"github.com/gesellix/bose-soundtouch/pkg/config"
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/gesellix/bose-soundtouch/pkg/service/datastore"
+	"github.com/urfave/cli/v2"
+)
+
+// zoneDataDirFlag selects where named zones are stored; it matches the service data directory
+var zoneDataDirFlag = &cli.StringFlag{
+	Name:    "data-dir",
+	Usage:   "Directory where named zones are stored (shared with soundtouch-service)",
+	Value:   "data",
+	EnvVars: []string{"SOUNDTOUCH_DATA_DIR", "DATA_DIR"},
+}
+
+// saveNamedZone stores a named zone, either from flags or from the live zone of a speaker
+func saveNamedZone(c *cli.Context) error {
+	zone := models.ZoneDefinition{
+		Name:    c.String("name"),
+		Master:  c.String("master"),
+		Members: c.StringSlice("members"),
+	}
+
+	if current := c.String("current"); current != "" {
+		output.Printf("Reading current zone of %s...\n", current)
+
+		manager, err := newZoneManager(c)
+		if err != nil {
+			PrintError(err.Error())
+			return err
+		}
+
+		live, master, err := manager.ZoneOf(current)
+		if err != nil {
+			PrintError(fmt.Sprintf("Failed to get zone: %v", err))
+			return err
+		}
+
+		zone.Master = master.String()
+		zone.Members = nil
+
+		for _, member := range live.Members {
+			if strings.EqualFold(member.DeviceID, master.DeviceID) {
+				continue
+			}
+
+			speaker, err := manager.Resolve(member.DeviceID)
+			if err != nil {
+				zone.Members = append(zone.Members, member.DeviceID)
+				continue
+			}
+
+			zone.Members = append(zone.Members, speaker.String())
+		}
+	}
+
+	if err := zone.Validate(); err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	ds := datastore.NewDataStore(c.String("data-dir"))
+	if err := ds.SaveZone(zone); err != nil {
+		PrintError(fmt.Sprintf("Failed to save zone: %v", err))
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Saved zone %s", zone.String()))
+
+	return nil
+}
+
+// listNamedZones prints the saved zones and those configured via ZONES
+func listNamedZones(c *cli.Context) error {
+	zones, configured, err := loadNamedZones(c)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	emitResult(c, append([]models.ZoneDefinition{}, zones...))
+
+	if len(zones) == 0 {
+		output.Println("No named zones defined")
+		output.Println("Save one with: soundtouch-cli zone save --name <name> --master <speaker> --members <speaker>")
+
+		return nil
+	}
+
+	output.Printf("Named zones (%d):\n", len(zones))
+
+	for _, zone := range zones {
+		origin := ""
+		if configured[strings.ToLower(zone.Name)] {
+			origin = " [config]"
+		}
+
+		output.Printf("  %s%s\n", zone.String(), origin)
+	}
+
+	return nil
+}
+
+// applyNamedZone restores a named zone and reports the outcome for every member
+func applyNamedZone(c *cli.Context) error {
+	name := c.String("name")
+
+	zone, err := findNamedZone(c, name)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	output.Printf("Applying zone %s...\n", zone.String())
+
+	manager, err := newZoneManager(c)
+	if err != nil {
+		PrintError(err.Error())
+		return err
+	}
+
+	result, err := manager.ApplyDefinition(zone)
+	if err != nil {
+		PrintError(fmt.Sprintf("Failed to apply zone: %v", err))
+		return err
+	}
+
+	for _, outcome := range result.Outcomes {
+		marker := "✓"
+		if outcome.Status == client.ZoneMemberFailed {
+			marker = "✗"
+		}
+
+		output.Printf("  %s %s\n", marker, outcome)
+	}
+
+	if result.Failed() {
+		PrintWarning(fmt.Sprintf("Zone %s applied partially: %s", zone.Name, manager.ZoneSummary(result.Zone)))
+		return fmt.Errorf("not all members of zone %s could be added", zone.Name)
+	}
+
+	PrintSuccess(fmt.Sprintf("Zone %s applied: %s", zone.Name, manager.ZoneSummary(result.Zone)))
+
+	return nil
+}
+
+// deleteNamedZone removes a saved zone
+func deleteNamedZone(c *cli.Context) error {
+	name := c.String("name")
+
+	ds := datastore.NewDataStore(c.String("data-dir"))
+	if err := ds.DeleteZone(name); err != nil {
+		PrintError(fmt.Sprintf("Failed to delete zone: %v", err))
+		return err
+	}
+
+	PrintSuccess(fmt.Sprintf("Deleted zone %s", name))
+
+	return nil
+}
+
+// loadNamedZones merges saved zones with those from the configuration; saved zones win on name clashes
+func loadNamedZones(c *cli.Context) ([]models.ZoneDefinition, map[string]bool, error) {
+	ds := datastore.NewDataStore(c.String("data-dir"))
+
+	zones, err := ds.ListZones()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load zones: %w", err)
+	}
+
+	saved := make(map[string]bool)
+	for _, zone := range zones {
+		saved[strings.ToLower(zone.Name)] = true
+	}
+
+	configured := make(map[string]bool)
+
+	cfg, err := config.LoadFromEnv()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	for _, zone := range cfg.Zones {
+		if saved[strings.ToLower(zone.Name)] {
+			continue
+		}
+
+		configured[strings.ToLower(zone.Name)] = true
+		zones = append(zones, zone)
+	}
+
+	return zones, configured, nil
+}
+
+// findNamedZone looks up a zone by name among saved and configured zones
+func findNamedZone(c *cli.Context, name string) (*models.ZoneDefinition, error) {
+	zones, _, err := loadNamedZones(c)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range zones {
+		if strings.EqualFold(zones[i].Name, name) {
+			return &zones[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("zone %s not found", name)
+}
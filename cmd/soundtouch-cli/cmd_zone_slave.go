@@ -40,10 +40,10 @@ func addZoneSlave(c *cli.Context) error {
 		return err
 	}
 
-	fmt.Printf("✅ Successfully added device '%s' to zone master '%s'\n", slaveID, masterID)
+	output.Printf("✅ Successfully added device '%s' to zone master '%s'\n", slaveID, masterID)
 
 	if slaveIP != "" {
-		fmt.Printf("   Slave IP: %s\n", slaveIP)
+		output.Printf("   Slave IP: %s\n", slaveIP)
 	}
 
 	return nil
@@ -83,10 +83,10 @@ func removeZoneSlave(c *cli.Context) error {
 		return err
 	}
 
-	fmt.Printf("✅ Successfully removed device '%s' from zone master '%s'\n", slaveID, masterID)
+	output.Printf("✅ Successfully removed device '%s' from zone master '%s'\n", slaveID, masterID)
 
 	if slaveIP != "" {
-		fmt.Printf("   Slave IP: %s\n", slaveIP)
+		output.Printf("   Slave IP: %s\n", slaveIP)
 	}
 
 	return nil
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"html"
@@ -16,6 +17,7 @@ import (
 
 	"github.com/gesellix/bose-soundtouch/pkg/client"
 	"github.com/gesellix/bose-soundtouch/pkg/config"
+	"github.com/gesellix/bose-soundtouch/pkg/discovery"
 	"github.com/urfave/cli/v2"
 )
 
@@ -39,6 +41,33 @@ var CommonFlags = []cli.Flag{
 		Usage:   "Request timeout",
 		Value:   10 * time.Second,
 	},
+	&cli.StringFlag{
+		Name:    "service",
+		Usage:   "Send requests through the proxy of a soundtouch-service: its URL, or \"auto\" to find it via mDNS",
+		EnvVars: []string{"SOUNDTOUCH_SERVICE"},
+	},
+	&cli.StringFlag{
+		Name:    "device",
+		Usage:   "Named device or alias from the config file; takes precedence over --host",
+		EnvVars: []string{"SOUNDTOUCH_DEVICE"},
+	},
+	&cli.StringFlag{
+		Name:    "group",
+		Usage:   "Run the command on every device of a group from the config file",
+		EnvVars: []string{"SOUNDTOUCH_GROUP"},
+	},
+	&cli.StringFlag{
+		Name:    "config",
+		Usage:   "Path of the config file with named devices and groups (default: <user config dir>/soundtouch/config.json)",
+		EnvVars: []string{"SOUNDTOUCH_CONFIG"},
+	},
+	&cli.StringFlag{
+		Name:    "output",
+		Aliases: []string{"o"},
+		Usage:   "Output format: text, json or yaml",
+		Value:   outputText,
+		EnvVars: []string{"SOUNDTOUCH_OUTPUT"},
+	},
 }
 
 // ClientConfig holds configuration for creating a SoundTouch client
@@ -46,13 +75,49 @@ type ClientConfig struct {
 	Host    string
 	Port    int
 	Timeout time.Duration
+	Service string
 }
 
-// GetClientConfig extracts client configuration from CLI context
+// GetClientConfig extracts client configuration from CLI context. A device selected with
+// --device, or the default device of the config file if no host is given, sets host, port
+// and timeout; the service of the config file is used unless --service is given.
 func GetClientConfig(c *cli.Context) *ClientConfig {
-	host := c.String("host")
+	return deviceClientConfig(c, c.String("host"), c.String("device"))
+}
+
+// deviceClientConfig resolves a host or a device reference like the global flags do,
+// e.g. for commands that talk to a second device given with --from
+func deviceClientConfig(c *cli.Context, host, ref string) *ClientConfig {
 	port := c.Int("port")
 	timeout := c.Duration("timeout")
+	service := c.String("service")
+
+	if profiles := openProfiles(c); profiles != nil {
+		if ref == "" && host == "" {
+			ref = profiles.DefaultDevice
+		}
+
+		if _, device, ok := profiles.Device(ref); ok {
+			host = device.Host
+
+			if device.Port > 0 {
+				port = device.Port
+			}
+
+			if deviceTimeout := device.TimeoutDuration(); deviceTimeout > 0 && !c.IsSet("timeout") {
+				timeout = deviceTimeout
+			}
+		} else if ref != "" {
+			// Not configured: names known to the device registry and plain hosts work as well
+			host = ref
+		}
+
+		if service == "" {
+			service = profiles.Service
+		}
+	} else if ref != "" {
+		host = ref
+	}
 
 	// Parse host:port if host contains a port
 	if host != "" {
@@ -62,20 +127,37 @@ func GetClientConfig(c *cli.Context) *ClientConfig {
 		}
 	}
 
+	// Names and device IDs resolve through the device registry; an address is only replaced
+	// when the speaker registered with it moved away
+	if host != "" {
+		if registry := openDeviceRegistry(); registry != nil {
+			if registeredHost, registeredPort, ok := registry.ResolveHost(net.JoinHostPort(host, strconv.Itoa(port))); ok {
+				host = registeredHost
+				port = registeredPort
+			}
+		}
+	}
+
 	return &ClientConfig{
 		Host:    host,
 		Port:    port,
 		Timeout: timeout,
+		Service: service,
 	}
 }
 
-// RequireHost validates that a host is provided for commands that need it
+// RequireHost validates that a host is provided for commands that need it. A device or group
+// selected from the config file, or its default device, count as well.
 func RequireHost(c *cli.Context) error {
-	if c.String("host") == "" {
-		return fmt.Errorf("host is required. Use --host flag or set SOUNDTOUCH_HOST environment variable")
+	if c.String("host") != "" || c.String("device") != "" || c.String("group") != "" {
+		return nil
 	}
 
-	return nil
+	if profiles := openProfiles(c); profiles != nil && profiles.DefaultDevice != "" {
+		return nil
+	}
+
+	return &usageError{fmt.Errorf("host is required. Use --host or --device flag, set SOUNDTOUCH_HOST environment variable or configure a default device with 'config default'")}
 }
 
 // CreateSoundTouchClient creates a configured SoundTouch client
@@ -92,9 +174,86 @@ func CreateSoundTouchClient(config *ClientConfig) (*client.Client, error) {
 		UserAgent: cfg.UserAgent,
 	}
 
+	if config.Service != "" {
+		proxyURL, err := resolveServiceProxy(config.Service)
+		if err != nil {
+			return nil, err
+		}
+
+		clientConfig.ProxyURL = proxyURL
+	}
+
 	return client.NewClient(clientConfig), nil
 }
 
+// resolveServiceProxy returns the proxy URL of a soundtouch-service given by URL, or found via mDNS for "auto"
+func resolveServiceProxy(service string) (string, error) {
+	if service != "auto" {
+		return strings.TrimSuffix(service, "/") + discovery.DefaultServiceProxyPath, nil
+	}
+
+	instance, err := findService()
+	if err != nil {
+		return "", err
+	}
+
+	return instance.ProxyURL(), nil
+}
+
+// resolveServiceURL returns the base URL of a soundtouch-service given by URL, or found via mDNS for "auto"
+func resolveServiceURL(service string) (string, error) {
+	if service != "auto" {
+		return strings.TrimSuffix(service, "/"), nil
+	}
+
+	instance, err := findService()
+	if err != nil {
+		return "", err
+	}
+
+	return instance.URL(), nil
+}
+
+// findService looks up a soundtouch-service via mDNS
+func findService() (*discovery.ServiceInstance, error) {
+	instance, err := discovery.ResolveService(context.Background(), 2*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find soundtouch-service: %w", err)
+	}
+
+	return instance, nil
+}
+
+// configuredService returns the soundtouch-service set with --service or in the profiles, or an empty string
+func configuredService(c *cli.Context) string {
+	if service := c.String("service"); service != "" {
+		return service
+	}
+
+	if profiles := openProfiles(c); profiles != nil {
+		return profiles.Service
+	}
+
+	return ""
+}
+
+// openDeviceRegistry opens the device registry set with DEVICE_REGISTRY or the default one.
+// It returns nil if the registry cannot be read.
+func openDeviceRegistry() *discovery.DeviceRegistry {
+	path := discovery.DefaultRegistryPath()
+	if cfg, err := config.LoadFromEnv(); err == nil && cfg.DeviceRegistry != "" {
+		path = cfg.DeviceRegistry
+	}
+
+	registry, err := discovery.OpenDeviceRegistry(path)
+	if err != nil {
+		PrintWarning(err.Error())
+		return nil
+	}
+
+	return registry
+}
+
 // loadConfig loads the application configuration with optional timeout override
 func loadConfig(timeout time.Duration) (*config.Config, error) {
 	cfg, err := config.LoadFromEnv()
@@ -111,8 +270,14 @@ func loadConfig(timeout time.Duration) (*config.Config, error) {
 }
 
 // parseHostPort splits a host:port string into separate host and port components
-// If no port is specified, returns the original host and the provided default port
+// If no port is specified, returns the original host and the provided default port.
+// IPv6 addresses are accepted bare, in brackets or as [address]:port.
 func parseHostPort(hostPort string, defaultPort int) (string, int) {
+	// Handles bare and bracketed IPv6 addresses as well
+	if host, port, err := config.SplitHostPort(hostPort, defaultPort); err == nil {
+		return host, port
+	}
+
 	// Check if host contains a port (has a colon)
 	if strings.Contains(hostPort, ":") {
 		host, portStr, err := net.SplitHostPort(hostPort)
@@ -134,9 +299,34 @@ func parseHostPort(hostPort string, defaultPort int) (string, int) {
 	return hostPort, defaultPort
 }
 
+// selectedDevice returns the device given with --device, or host:port of the device the command talks to
+func selectedDevice(c *cli.Context) string {
+	if device := c.String("device"); device != "" {
+		return device
+	}
+
+	clientConfig := GetClientConfig(c)
+
+	return net.JoinHostPort(clientConfig.Host, strconv.Itoa(clientConfig.Port))
+}
+
+// deviceCommandArgs returns the arguments to run another command of the app against one device.
+// Global flags other than the device selection are passed on; --group is cleared so the command runs once.
+func deviceCommandArgs(c *cli.Context, device string, args ...string) []string {
+	run := []string{c.App.Name, "--device", device, "--group="}
+
+	for _, flag := range []string{"config", "service", "timeout", "output"} {
+		if c.IsSet(flag) {
+			run = append(run, "--"+flag, fmt.Sprint(c.Value(flag)))
+		}
+	}
+
+	return append(run, args...)
+}
+
 // PrintDeviceHeader prints a standard header for device commands
 func PrintDeviceHeader(operation, host string, port int) {
-	fmt.Printf("%s from %s:%d...\n", operation, host, port)
+	output.Printf("%s from %s:%d...\n", operation, host, port)
 }
 
 // resolveLocation converts potential URLs to SoundTouch locations
@@ -317,26 +507,48 @@ func fetchSpotifyMetadata(url string) (*Metadata, error) {
 
 // PrintSuccess prints a standard success message
 func PrintSuccess(message string) {
-	fmt.Printf("✓ %s\n", message)
+	output.Printf("✓ %s\n", message)
 }
 
 // PrintError prints a standard error message
 func PrintError(message string) {
-	fmt.Printf("✗ %s\n", message)
+	output.Printf("✗ %s\n", message)
 }
 
-// PrintWarning prints a standard warning message
+// PrintWarning prints a standard warning message; with structured output it goes to stderr
 func PrintWarning(message string) {
-	fmt.Printf("⚠️  %s\n", message)
+	if structuredOutput() {
+		fmt.Fprintf(os.Stderr, "⚠️  %s\n", message)
+		return
+	}
+
+	output.Printf("⚠️  %s\n", message)
+}
+
+// versionInfo is the structured output of version
+type versionInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Date      string `json:"date"`
+	GoVersion string `json:"goVersion"`
+	Platform  string `json:"platform"`
 }
 
 // showVersionInfo displays detailed version information including build details
-func showVersionInfo(_ *cli.Context) error {
-	fmt.Printf("%s version %s\n", os.Args[0], version)
-	fmt.Printf("Build commit: %s\n", commit)
-	fmt.Printf("Build date: %s\n", date)
-	fmt.Printf("Go version: %s\n", runtime.Version())
-	fmt.Printf("Platform: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+func showVersionInfo(c *cli.Context) error {
+	emitResult(c, versionInfo{
+		Version:   version,
+		Commit:    commit,
+		Date:      date,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	})
+
+	output.Printf("%s version %s\n", os.Args[0], version)
+	output.Printf("Build commit: %s\n", commit)
+	output.Printf("Build date: %s\n", date)
+	output.Printf("Go version: %s\n", runtime.Version())
+	output.Printf("Platform: %s/%s\n", runtime.GOOS, runtime.GOARCH)
 
 	return nil
 }
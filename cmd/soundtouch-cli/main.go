@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"log"
 	"os"
 	"runtime/debug"
 	"sort"
@@ -107,7 +106,8 @@ func main() {
 				Name: "Tobias Gesellchen, and the Bose-SoundTouch Contributors",
 			},
 		},
-		Flags: CommonFlags,
+		Flags:  CommonFlags,
+		Before: setupOutput,
 		Commands: []*cli.Command{
 			// Version commands
 			{
@@ -116,6 +116,130 @@ func main() {
 				Usage:   "Show detailed version information",
 				Action:  showVersionInfo,
 			},
+			// Config file commands
+			{
+				Name:   "config",
+				Usage:  "Show and edit named devices, groups and defaults in the config file",
+				Action: showConfig,
+				Subcommands: []*cli.Command{
+					{
+						Name:   "show",
+						Usage:  "Show the config file",
+						Action: showConfig,
+					},
+					{
+						Name:  "device",
+						Usage: "Manage named devices",
+						Subcommands: []*cli.Command{
+							{
+								Name:   "set",
+								Usage:  "Add a named device or update its settings",
+								Action: setConfigDevice,
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:     "name",
+										Aliases:  []string{"n"},
+										Usage:    "Device name (e.g. kitchen)",
+										Required: true,
+									},
+									&cli.StringFlag{
+										Name:  "host",
+										Usage: "Device host/IP address, a device ID or a name from the device registry",
+									},
+									&cli.IntFlag{
+										Name:  "port",
+										Usage: "Device port (default 8090)",
+									},
+									&cli.StringSliceFlag{
+										Name:    "alias",
+										Aliases: []string{"a"},
+										Usage:   "Alternative names of the device (replaces existing aliases)",
+									},
+									&cli.DurationFlag{
+										Name:  "timeout",
+										Usage: "Request timeout for this device (0 removes it)",
+									},
+								},
+							},
+							{
+								Name:   "remove",
+								Usage:  "Remove a named device, including its group memberships",
+								Action: removeConfigDevice,
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:     "name",
+										Aliases:  []string{"n"},
+										Usage:    "Device name or alias",
+										Required: true,
+									},
+								},
+							},
+						},
+					},
+					{
+						Name:  "group",
+						Usage: "Manage device groups",
+						Subcommands: []*cli.Command{
+							{
+								Name:   "set",
+								Usage:  "Add a group of named devices or replace its devices",
+								Action: setConfigGroup,
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:     "name",
+										Aliases:  []string{"n"},
+										Usage:    "Group name (e.g. downstairs)",
+										Required: true,
+									},
+									&cli.StringSliceFlag{
+										Name:     "devices",
+										Aliases:  []string{"d"},
+										Usage:    "Named devices of the group",
+										Required: true,
+									},
+								},
+							},
+							{
+								Name:   "remove",
+								Usage:  "Remove a group",
+								Action: removeConfigGroup,
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:     "name",
+										Aliases:  []string{"n"},
+										Usage:    "Group name",
+										Required: true,
+									},
+								},
+							},
+						},
+					},
+					{
+						Name:      "default",
+						Usage:     "Show or set the device used when neither --host nor --device is given",
+						ArgsUsage: "[device]",
+						Action:    setConfigDefault,
+						Flags: []cli.Flag{
+							&cli.BoolFlag{
+								Name:  "clear",
+								Usage: "Remove the default device",
+							},
+						},
+					},
+					{
+						Name:      "service",
+						Usage:     "Show or set the soundtouch-service URL (or \"auto\") requests are sent through",
+						ArgsUsage: "[url]",
+						Action:    setConfigService,
+						Flags: []cli.Flag{
+							&cli.BoolFlag{
+								Name:  "clear",
+								Usage: "Stop sending requests through a service",
+							},
+						},
+					},
+				},
+			},
 			// Discovery commands
 			{
 				Name:    "discover",
@@ -134,6 +258,53 @@ func main() {
 							},
 						},
 					},
+					{
+						Name:   "scan",
+						Usage:  "Find devices by probing :8090/info across subnets, for networks that block multicast",
+						Action: scanSubnets,
+						Flags: []cli.Flag{
+							&cli.StringSliceFlag{
+								Name:    "subnet",
+								Aliases: []string{"s"},
+								Usage:   "CIDR range to scan (default: SCAN_SUBNETS or the subnets of the local interfaces)",
+							},
+							&cli.IntFlag{
+								Name:  "concurrency",
+								Usage: "Maximum number of parallel probes (default: SCAN_CONCURRENCY or 64)",
+							},
+							&cli.DurationFlag{
+								Name:  "probe-timeout",
+								Usage: "Timeout of a single probe (default: SCAN_TIMEOUT or 750ms)",
+							},
+							&cli.BoolFlag{
+								Name:    "all",
+								Aliases: []string{"a"},
+								Usage:   "Show detailed information for all devices",
+							},
+						},
+					},
+					{
+						Name:   "watch",
+						Usage:  "Report devices appearing, disappearing and changing until interrupted",
+						Action: watchDevices,
+						Flags: []cli.Flag{
+							&cli.DurationFlag{
+								Name:  "interval",
+								Usage: "Interval between active probes",
+								Value: time.Minute,
+							},
+						},
+					},
+					{
+						Name:   "known",
+						Usage:  "List devices remembered in the device registry, with their address history",
+						Action: listKnownDevices,
+					},
+					{
+						Name:   "service",
+						Usage:  "Find soundtouch-service instances announced via mDNS",
+						Action: discoverServices,
+					},
 				},
 			},
 			// Device information commands
@@ -292,6 +463,11 @@ func main() {
 								Aliases: []string{"v"},
 								Usage:   "Show detailed content information (including Spotify URIs)",
 							},
+							&cli.BoolFlag{
+								Name:    "follow",
+								Aliases: []string{"f"},
+								Usage:   "Keep running and show a live progress bar",
+							},
 						},
 					},
 					{
@@ -415,6 +591,187 @@ func main() {
 						Action: listPresets,
 						Before: RequireHost,
 					},
+					{
+						Name:      "export",
+						Usage:     "Export all presets to a file",
+						ArgsUsage: "<file>",
+						Action:    exportPresets,
+						Before:    RequireHost,
+					},
+					{
+						Name:      "import",
+						Usage:     "Store the presets of a preset file or backup",
+						ArgsUsage: "<file>",
+						Action:    importPresets,
+						Flags: []cli.Flag{
+							&cli.StringSliceFlag{
+								Name:  "slots",
+								Usage: "Only change these preset slots (comma separated)",
+							},
+							&cli.BoolFlag{
+								Name:  "dry-run",
+								Usage: "Only show the changes",
+							},
+						},
+						Before: RequireHost,
+					},
+					{
+						Name:   "copy",
+						Usage:  "Copy the presets of another device, e.g. to every device of --group",
+						Action: copyPresets,
+						Flags: []cli.Flag{
+							&cli.StringSliceFlag{
+								Name:  "slots",
+								Usage: "Only change these preset slots (comma separated)",
+							},
+							&cli.BoolFlag{
+								Name:  "dry-run",
+								Usage: "Only show the changes",
+							},
+							&cli.StringFlag{
+								Name:     "from",
+								Usage:    "Device to copy the presets from (name, host or device ID)",
+								Required: true,
+							},
+						},
+						Before: RequireHost,
+					},
+					{
+						Name:      "swap",
+						Usage:     "Swap the presets of two slots",
+						ArgsUsage: "<slot> <slot>",
+						Action:    swapPresets,
+						Flags: []cli.Flag{
+							&cli.BoolFlag{
+								Name:  "dry-run",
+								Usage: "Only show the changes",
+							},
+						},
+						Before: RequireHost,
+					},
+					{
+						Name:      "reorder",
+						Usage:     "Move presets to new slots, e.g. 3,1,2 makes preset 3 the first",
+						ArgsUsage: "<order>",
+						Action:    reorderPresets,
+						Flags: []cli.Flag{
+							&cli.BoolFlag{
+								Name:  "dry-run",
+								Usage: "Only show the changes",
+							},
+						},
+						Before: RequireHost,
+					},
+					{
+						Name:   "sync",
+						Usage:  "Make the presets of every device of --group identical, and keep them so with --watch",
+						Action: syncPresets,
+						Flags: []cli.Flag{
+							&cli.StringSliceFlag{
+								Name:  "slots",
+								Usage: "Only change these preset slots (comma separated)",
+							},
+							&cli.BoolFlag{
+								Name:  "dry-run",
+								Usage: "Only show the changes",
+							},
+							&cli.StringFlag{
+								Name:  "from",
+								Usage: "Device whose presets are copied (default: first device of the group)",
+							},
+							&cli.BoolFlag{
+								Name:  "watch",
+								Usage: "Keep running and copy presets changed on any device to all others",
+							},
+						},
+					},
+					{
+						Name:  "bank",
+						Usage: "Named sets of presets per device, switched manually or by soundtouch-service at a time of day",
+						Subcommands: []*cli.Command{
+							{
+								Name:   "list",
+								Usage:  "List the preset banks of the device",
+								Action: listPresetBanks,
+								Flags: []cli.Flag{
+									presetBankDataDirFlag,
+								},
+								Before: RequireHost,
+							},
+							{
+								Name:   "save",
+								Usage:  "Save the current presets, or those of --file, as a preset bank",
+								Action: savePresetBank,
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:     "name",
+										Usage:    "Preset bank name",
+										Required: true,
+									},
+									&cli.StringFlag{
+										Name:  "file",
+										Usage: "Preset file or backup to take the presets from",
+									},
+									&cli.StringFlag{
+										Name:  "schedule",
+										Usage: "Activate the bank daily at this time (HH:MM)",
+									},
+									presetBankDataDirFlag,
+								},
+								Before: RequireHost,
+							},
+							{
+								Name:   "schedule",
+								Usage:  "Set or clear the time of day a preset bank is activated at",
+								Action: schedulePresetBank,
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:     "name",
+										Usage:    "Preset bank name",
+										Required: true,
+									},
+									&cli.StringFlag{
+										Name:  "at",
+										Usage: "Activate the bank daily at this time (HH:MM)",
+									},
+									&cli.BoolFlag{
+										Name:  "clear",
+										Usage: "Only activate the bank manually",
+									},
+									presetBankDataDirFlag,
+								},
+								Before: RequireHost,
+							},
+							{
+								Name:   "activate",
+								Usage:  "Write a preset bank to the device and to the Marge presets of its account",
+								Action: activatePresetBank,
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:     "name",
+										Usage:    "Preset bank name",
+										Required: true,
+									},
+									presetBankDataDirFlag,
+								},
+								Before: RequireHost,
+							},
+							{
+								Name:   "delete",
+								Usage:  "Delete a preset bank",
+								Action: deletePresetBank,
+								Flags: []cli.Flag{
+									&cli.StringFlag{
+										Name:     "name",
+										Usage:    "Preset bank name",
+										Required: true,
+									},
+									presetBankDataDirFlag,
+								},
+								Before: RequireHost,
+							},
+						},
+					},
 				},
 			},
 			// Browse/Navigation commands
@@ -746,6 +1103,44 @@ func main() {
 						},
 						Before: RequireHost,
 					},
+					{
+						Name:   "hold",
+						Usage:  "Press a key and release it after a duration (hold PRESET_n to store a preset)",
+						Action: holdKey,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "key",
+								Aliases:  []string{"k"},
+								Usage:    "Key name (PRESET_1, POWER, etc.)",
+								Required: true,
+							},
+							&cli.DurationFlag{
+								Name:    "duration",
+								Aliases: []string{"d"},
+								Usage:   "How long to hold the key",
+								Value:   2 * time.Second,
+							},
+						},
+						Before: RequireHost,
+					},
+					{
+						Name:   "macro",
+						Usage:  "Run a timed key sequence from a macro file",
+						Action: runKeyMacro,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "file",
+								Aliases:  []string{"f"},
+								Usage:    "Macro file (one step per line: key, hold, wait, wait-for)",
+								Required: true,
+							},
+							&cli.BoolFlag{
+								Name:  "dry-run",
+								Usage: "Only print the parsed steps",
+							},
+						},
+						Before: RequireHost,
+					},
 					{
 						Name:   "power",
 						Usage:  "Send POWER key command",
@@ -1234,6 +1629,33 @@ func main() {
 						Action: setClockTimeNow,
 						Before: RequireHost,
 					},
+					{
+						Name:   "sync",
+						Usage:  "Correct clock drift and wrong UTC offsets, e.g. after power cuts or DST transitions",
+						Action: syncClock,
+						Flags: []cli.Flag{
+							&cli.BoolFlag{
+								Name:    "all",
+								Aliases: []string{"a"},
+								Usage:   "Sync the clocks of all discovered speakers",
+							},
+							&cli.StringFlag{
+								Name:  "zone",
+								Usage: "IANA time zone of the speakers, e.g. Europe/Berlin",
+								Value: "local",
+							},
+							&cli.DurationFlag{
+								Name:  "threshold",
+								Usage: "Drift that is tolerated before a clock is corrected",
+								Value: 10 * time.Second,
+							},
+							&cli.BoolFlag{
+								Name:  "dry-run",
+								Usage: "Only report drift, do not correct it",
+							},
+						},
+						Before: requireHostUnlessAll,
+					},
 					{
 						Name:  "display",
 						Usage: "Clock display commands",
@@ -1448,6 +1870,160 @@ func main() {
 						},
 						Before: RequireHost,
 					},
+					{
+						Name:   "group",
+						Usage:  "Group speakers by name, alias or device ID (uses discovery)",
+						Action: groupSpeakers,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "master",
+								Usage:    "Speaker that becomes the zone master",
+								Required: true,
+							},
+							&cli.StringSliceFlag{
+								Name:     "members",
+								Aliases:  []string{"m"},
+								Usage:    "Speakers joining the zone",
+								Required: true,
+							},
+						},
+					},
+					{
+						Name:   "move",
+						Usage:  "Move the music playing on one speaker to another, which becomes the new master",
+						Action: moveMusic,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "from",
+								Usage:    "Speaker currently playing",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:     "to",
+								Usage:    "Speaker to move the music to",
+								Required: true,
+							},
+						},
+					},
+					{
+						Name:   "join",
+						Usage:  "Join a speaker to whatever another speaker is playing",
+						Action: joinSpeaker,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "speaker",
+								Usage:    "Speaker to add",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:     "with",
+								Usage:    "Speaker whose zone to join",
+								Required: true,
+							},
+						},
+					},
+					{
+						Name:   "split",
+						Usage:  "Remove a speaker from its zone without stopping the others",
+						Action: splitSpeaker,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "speaker",
+								Usage:    "Speaker to split off",
+								Required: true,
+							},
+						},
+					},
+					{
+						Name:   "volume",
+						Usage:  "Show or change the volume of a whole zone, keeping the balance between speakers",
+						Action: zoneGroupVolume,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "speaker",
+								Usage: "Any speaker of the zone by name, alias or device ID (uses discovery; defaults to --host)",
+							},
+							&cli.IntFlag{
+								Name:  "set",
+								Usage: "Set the group volume (0-100), scaling every speaker proportionally",
+							},
+							&cli.IntFlag{
+								Name:  "change",
+								Usage: "Change every speaker by the same amount (e.g. 5 or -5)",
+							},
+							&cli.BoolFlag{
+								Name:  "mute",
+								Usage: "Mute all speakers of the zone",
+							},
+							&cli.BoolFlag{
+								Name:  "unmute",
+								Usage: "Unmute all speakers of the zone",
+							},
+						},
+					},
+					{
+						Name:   "save",
+						Usage:  "Save a named zone from speaker names or from a speaker's current zone",
+						Action: saveNamedZone,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "name",
+								Aliases:  []string{"n"},
+								Usage:    "Zone name (e.g. downstairs)",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "master",
+								Usage: "Speaker that becomes the zone master",
+							},
+							&cli.StringSliceFlag{
+								Name:    "members",
+								Aliases: []string{"m"},
+								Usage:   "Speakers joining the zone",
+							},
+							&cli.StringFlag{
+								Name:  "current",
+								Usage: "Save the zone this speaker currently belongs to (uses discovery)",
+							},
+							zoneDataDirFlag,
+						},
+					},
+					{
+						Name:   "apply",
+						Usage:  "Restore a named zone: dissolve conflicting zones, power on and add missing members",
+						Action: applyNamedZone,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "name",
+								Aliases:  []string{"n"},
+								Usage:    "Zone name",
+								Required: true,
+							},
+							zoneDataDirFlag,
+						},
+					},
+					{
+						Name:   "list",
+						Usage:  "List named zones",
+						Action: listNamedZones,
+						Flags: []cli.Flag{
+							zoneDataDirFlag,
+						},
+					},
+					{
+						Name:   "delete",
+						Usage:  "Delete a saved named zone",
+						Action: deleteNamedZone,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "name",
+								Aliases:  []string{"n"},
+								Usage:    "Zone name",
+								Required: true,
+							},
+							zoneDataDirFlag,
+						},
+					},
 				},
 			},
 			// Advanced Audio commands
@@ -1626,6 +2202,61 @@ func main() {
 					},
 				},
 			},
+			// UPnP MediaRenderer commands
+			{
+				Name:  "upnp",
+				Usage: "Play HTTP audio URLs through the UPnP MediaRenderer (AVTransport) of a speaker",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "location",
+						Usage: "UPnP device description URL (default: derived from the device ID of --host)",
+					},
+				},
+				Subcommands: []*cli.Command{
+					{
+						Name:      "play",
+						Usage:     "Play an HTTP audio URL",
+						ArgsUsage: "<url>",
+						Action:    upnpPlay,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "title",
+								Usage: "Track title shown on the speaker (default: the URL)",
+							},
+							&cli.IntFlag{
+								Name:    "volume",
+								Aliases: []string{"v"},
+								Usage:   "Volume level (0-100) to set before playing",
+							},
+						},
+					},
+					{
+						Name:   "pause",
+						Usage:  "Pause playback",
+						Action: upnpPause,
+					},
+					{
+						Name:   "resume",
+						Usage:  "Resume playback",
+						Action: upnpResume,
+					},
+					{
+						Name:   "stop",
+						Usage:  "Stop playback",
+						Action: upnpStop,
+					},
+					{
+						Name:   "status",
+						Usage:  "Show transport state, position and volume",
+						Action: upnpStatus,
+					},
+					{
+						Name:   "describe",
+						Usage:  "Show the UPnP device description and its services",
+						Action: upnpDescribe,
+					},
+				},
+			},
 			// Speaker commands (TTS and URL playback)
 			{
 				Name:    "speaker",
@@ -2026,6 +2657,152 @@ func main() {
 					},
 				},
 			},
+			// Diagnose commands
+			{
+				Name:   "diagnose",
+				Usage:  "Check ports, API, WebSocket, cloud redirection, clock and Wi-Fi of a speaker",
+				Action: diagnoseDevice,
+				Before: RequireHost,
+				Flags: []cli.Flag{
+					&cli.DurationFlag{
+						Name:  "probe-timeout",
+						Usage: "Timeout for each port and DNS probe",
+						Value: 2 * time.Second,
+					},
+				},
+			},
+			// Backup commands
+			{
+				Name:      "backup",
+				Usage:     "Save the device configuration (name, presets, audio, clock, accounts, zone, language) to a file",
+				ArgsUsage: "<file>",
+				Description: "The file may contain {name} and {id}, which are replaced by the device name and ID,\n" +
+					"so that a group backup writes one file per device. Use - to write to stdout.",
+				Action: backupDevice,
+				Before: RequireHost,
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:  "sections",
+						Usage: "Only back up these sections (comma separated): name, language, accounts, presets, clock, bass, balance, tone, levels, dsp, zone",
+					},
+				},
+			},
+			{
+				Name:      "restore",
+				Usage:     "Restore a configuration backup, also to a speaker of another model",
+				ArgsUsage: "<file>",
+				Action:    restoreDevice,
+				Before:    RequireHost,
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Only show the differences between the backup and the device",
+					},
+					&cli.StringSliceFlag{
+						Name:  "only",
+						Usage: "Only restore these sections (comma separated)",
+					},
+					&cli.StringSliceFlag{
+						Name:  "skip",
+						Usage: "Do not restore these sections (comma separated)",
+					},
+				},
+			},
+			// History commands
+			{
+				Name:  "history",
+				Usage: "Listening history recorded by soundtouch-service: search, statistics, export and retention",
+				Subcommands: []*cli.Command{
+					{
+						Name:      "list",
+						Aliases:   []string{"search"},
+						Usage:     "List the latest plays, optionally searched and filtered",
+						ArgsUsage: "[search text]",
+						Action:    listHistory,
+						Flags: append(historyFilterFlags(),
+							&cli.IntFlag{
+								Name:    "limit",
+								Aliases: []string{"n"},
+								Usage:   "Number of plays to list (0 = all)",
+								Value:   20,
+							},
+						),
+					},
+					{
+						Name:   "stats",
+						Usage:  "Show the top stations, artists and sources, overall and per speaker",
+						Action: historyStats,
+						Flags: append(historyFilterFlags(),
+							&cli.IntFlag{
+								Name:  "top",
+								Usage: "Number of stations, artists and sources to show (0 = all)",
+								Value: 10,
+							},
+						),
+					},
+					{
+						Name:      "export",
+						Usage:     "Export the plays as CSV or JSON",
+						ArgsUsage: "<file>",
+						Description: "Use - to write to stdout. The filter flags select the plays to export;\n" +
+							"without them the whole history is exported.",
+						Action: exportHistory,
+						Flags: append(historyFilterFlags(),
+							&cli.StringFlag{
+								Name:  "format",
+								Usage: "Export format: csv or json",
+								Value: "csv",
+							},
+						),
+					},
+					{
+						Name:   "prune",
+						Usage:  "Remove old plays from the history",
+						Action: pruneHistory,
+						Flags: []cli.Flag{
+							historyDataDirFlag,
+							&cli.StringFlag{
+								Name:  "max-age",
+								Usage: "Remove plays older than this: days like 365d or a duration like 720h",
+							},
+							&cli.IntFlag{
+								Name:  "max-entries",
+								Usage: "Keep only this many of the latest plays",
+							},
+						},
+					},
+				},
+			},
+			// Script commands
+			{
+				Name:      "run",
+				Usage:     "Run a script of soundtouch-cli commands with variables, waits and error policies",
+				ArgsUsage: "<script>",
+				Action:    runScript,
+				Before:    RequireHost,
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:  "var",
+						Usage: "Set a script variable as name=value (repeatable); takes precedence over set in the script",
+					},
+					&cli.StringFlag{
+						Name:  "on-error",
+						Usage: "Error policy until the script sets one: abort, continue or \"retry [attempts] [delay]\"",
+						Value: policyAbort,
+					},
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Print the steps with variables expanded without running them",
+					},
+				},
+			},
+			// Interactive shell
+			{
+				Name:   "shell",
+				Usage:  "Interactive shell with tab completion, a live status line and a single-key remote mode",
+				Action: runShell,
+				Before: RequireHost,
+			},
 			// Events commands
 			{
 				Name:    "events",
@@ -2041,7 +2818,7 @@ func main() {
 							&cli.StringFlag{
 								Name:    "filter",
 								Aliases: []string{"f"},
-								Usage:   "Filter events by type (comma-separated): nowPlaying,volume,connection,preset,zone,bass,sdkInfo,userActivity",
+								Usage:   "Filter events by type (comma-separated): nowPlaying,volume,connection,preset,zone,bass,name,recents,sources,sdkInfo,userActivity",
 							},
 							&cli.DurationFlag{
 								Name:    "duration",
@@ -2065,6 +2842,10 @@ func main() {
 		},
 	}
 
+	// Record structured results, then let device commands run on all devices of --group
+	applyOutput(app.Commands)
+	applyGroupSelector(app.Commands)
+
 	// Sort commands alphabetically (including subcommands and flags recursively)
 	sortCommands(app.Commands)
 
@@ -2073,7 +2854,7 @@ func main() {
 		sortFlags(app.Flags)
 	}
 
-	if err := app.Run(os.Args); err != nil {
-		log.Fatal(err)
+	if code := finishOutput(app.Run(os.Args)); code != 0 {
+		os.Exit(code)
 	}
 }
@@ -138,6 +138,27 @@ func TestParseHostPort(t *testing.T) {
 			wantHost:    "192.168.1.10",
 			wantPort:    8090,
 		},
+		{
+			name:        "bare IPv6 address",
+			input:       "fe80::1%eth0",
+			defaultPort: 8090,
+			wantHost:    "fe80::1%eth0",
+			wantPort:    8090,
+		},
+		{
+			name:        "bracketed IPv6 address with port",
+			input:       "[2001:db8::10]:8091",
+			defaultPort: 8090,
+			wantHost:    "2001:db8::10",
+			wantPort:    8091,
+		},
+		{
+			name:        "bracketed IPv6 address without port",
+			input:       "[2001:db8::10]",
+			defaultPort: 8090,
+			wantHost:    "2001:db8::10",
+			wantPort:    8090,
+		},
 		{
 			name:        "hostname only fallback",
 			input:       "bose-soundtouch-20",
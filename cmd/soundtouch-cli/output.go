@@ -0,0 +1,536 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/gesellix/bose-soundtouch/pkg/client"
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats of the --output flag
+const (
+	outputText = "text"
+	outputJSON = "json"
+	outputYAML = "yaml"
+)
+
+// Error classes of structured errors, each with its own exit code
+const (
+	errorClassGeneral    = "general"
+	errorClassUsage      = "usage"
+	errorClassConnection = "connection"
+	errorClassTimeout    = "timeout"
+	errorClassDevice     = "device"
+)
+
+// exitCodes maps error classes to process exit codes
+var exitCodes = map[string]int{
+	errorClassGeneral:    1,
+	errorClassUsage:      2,
+	errorClassConnection: 3,
+	errorClassTimeout:    4,
+	errorClassDevice:     5,
+}
+
+// commandResult is the structured output of one command run on one device
+type commandResult struct {
+	Command string       `json:"command,omitempty"`
+	Device  string       `json:"device,omitempty"`
+	Status  string       `json:"status"`
+	Data    any          `json:"data,omitempty"`
+	Error   *resultError `json:"error,omitempty"`
+}
+
+// resultError is a structured error with its class and exit code
+type resultError struct {
+	Class    string `json:"class"`
+	Message  string `json:"message"`
+	ExitCode int    `json:"exitCode"`
+}
+
+// outputState owns the output of the CLI. Commands print their text through it and it collects
+// structured results until the CLI exits. In structured mode, the hand-formatted text of the
+// commands is discarded and only results are written to stdout.
+type outputState struct {
+	mu       sync.Mutex
+	format   string
+	out      io.Writer // structured results, stdout if nil
+	results  []commandResult
+	streamed bool
+}
+
+var output = &outputState{format: outputText}
+
+// Writer returns the writer for the text output of commands
+func (o *outputState) Writer() io.Writer {
+	if o.format != outputText {
+		return io.Discard
+	}
+
+	return os.Stdout
+}
+
+// Printf writes formatted text output
+func (o *outputState) Printf(format string, a ...any) {
+	_, _ = fmt.Fprintf(o.Writer(), format, a...)
+}
+
+// Println writes a line of text output
+func (o *outputState) Println(a ...any) {
+	_, _ = fmt.Fprintln(o.Writer(), a...)
+}
+
+// Print writes text output
+func (o *outputState) Print(a ...any) {
+	_, _ = fmt.Fprint(o.Writer(), a...)
+}
+
+// documents returns the writer for structured results
+func (o *outputState) documents() io.Writer {
+	if o.out != nil {
+		return o.out
+	}
+
+	return os.Stdout
+}
+
+// usageError marks errors caused by invalid command-line input
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
+
+func (e *usageError) Unwrap() error {
+	return e.err
+}
+
+// structuredOutput reports whether results are printed as JSON or YAML
+func structuredOutput() bool {
+	return output.format != outputText
+}
+
+// setupOutput validates --output and, for JSON and YAML, moves help texts out of stdout
+func setupOutput(c *cli.Context) error {
+	format := strings.ToLower(c.String("output"))
+
+	switch format {
+	case outputText, outputJSON, outputYAML:
+	default:
+		return &usageError{fmt.Errorf("invalid output format %q: use text, json or yaml", c.String("output"))}
+	}
+
+	output.format = format
+
+	if format != outputText {
+		c.App.Writer = os.Stderr
+	}
+
+	return nil
+}
+
+// emitResult records data as the result of the running command; it is only printed with --output json or yaml
+func emitResult(c *cli.Context, data any) {
+	if !structuredOutput() {
+		return
+	}
+
+	output.mu.Lock()
+	defer output.mu.Unlock()
+
+	output.results = append(output.results, commandResult{
+		Command: commandName(c),
+		Status:  "ok",
+		Data:    data,
+	})
+}
+
+// emitEvent writes a structured event of device right away, one JSON line or YAML document per event
+func emitEvent(c *cli.Context, device, kind string, event any) {
+	if !structuredOutput() {
+		return
+	}
+
+	output.mu.Lock()
+	defer output.mu.Unlock()
+
+	output.streamed = true
+
+	document := struct {
+		Command string `json:"command"`
+		Device  string `json:"device,omitempty"`
+		Event   string `json:"event"`
+		Data    any    `json:"data"`
+	}{commandName(c), device, kind, event}
+
+	if err := writeDocument(output.documents(), output.format, document, true); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write event: %v\n", err)
+	}
+}
+
+// commandName returns the command path without the program name, e.g. "volume get"
+func commandName(c *cli.Context) string {
+	var names []string
+
+	for _, ctx := range c.Lineage() {
+		if ctx.Command != nil && ctx.Command.Name != "" && ctx.Command.Name != c.App.Name {
+			names = append([]string{ctx.Command.Name}, names...)
+		}
+	}
+
+	return strings.Join(names, " ")
+}
+
+// resultDevice returns host:port of the device a command talks to
+func resultDevice(c *cli.Context) string {
+	if c.String("host") == "" && c.String("device") == "" && c.String("group") == "" {
+		if profiles := openProfiles(c); profiles == nil || profiles.DefaultDevice == "" {
+			return ""
+		}
+	}
+
+	clientConfig := GetClientConfig(c)
+
+	return net.JoinHostPort(clientConfig.Host, fmt.Sprint(clientConfig.Port))
+}
+
+// applyOutput records a result for every command run: the data it emitted, a plain
+// success status, or its error. Device commands, the ones with a Before hook, name their device.
+func applyOutput(commands []*cli.Command) {
+	for _, cmd := range commands {
+		if cmd.Action != nil {
+			cmd.Action = recordResult(cmd.Action, cmd.Before != nil)
+		}
+
+		applyOutput(cmd.Subcommands)
+	}
+}
+
+// recordResult wraps action so that its outcome is recorded as a structured result
+func recordResult(action cli.ActionFunc, deviceCommand bool) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		if !structuredOutput() {
+			return action(c)
+		}
+
+		output.mu.Lock()
+		before := len(output.results)
+		output.mu.Unlock()
+
+		err := action(c)
+
+		device := ""
+		if deviceCommand {
+			device = resultDevice(c)
+		}
+
+		output.mu.Lock()
+		defer output.mu.Unlock()
+
+		if err != nil {
+			output.results = append(output.results[:before], commandResult{
+				Command: commandName(c),
+				Device:  device,
+				Status:  "error",
+				Error:   newResultError(err),
+			})
+
+			return err
+		}
+
+		if len(output.results) == before && !output.streamed {
+			output.results = append(output.results, commandResult{Command: commandName(c), Status: "ok"})
+		}
+
+		for i := before; i < len(output.results); i++ {
+			output.results[i].Device = device
+		}
+
+		return nil
+	}
+}
+
+// finishOutput writes the collected results and returns the exit code for err. A single
+// result is written as one document, results of a --group run as a list.
+func finishOutput(err error) int {
+	code := 0
+	if err != nil {
+		code = newResultError(err).ExitCode
+	}
+
+	if !structuredOutput() {
+		if err != nil {
+			log.Print(err)
+		}
+
+		return code
+	}
+
+	output.mu.Lock()
+	defer output.mu.Unlock()
+
+	if err != nil && !hasErrorResult(output.results) {
+		output.results = append(output.results, commandResult{Status: "error", Error: newResultError(err)})
+	}
+
+	if len(output.results) == 0 || (output.streamed && err == nil) {
+		return code
+	}
+
+	var document any = output.results
+	if len(output.results) == 1 {
+		document = output.results[0]
+	}
+
+	if writeErr := writeDocument(output.documents(), output.format, document, false); writeErr != nil {
+		fmt.Fprintf(os.Stderr, "failed to write output: %v\n", writeErr)
+
+		if code == 0 {
+			code = exitCodes[errorClassGeneral]
+		}
+	}
+
+	return code
+}
+
+// hasErrorResult reports whether an error has already been recorded
+func hasErrorResult(results []commandResult) bool {
+	for _, result := range results {
+		if result.Error != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newResultError classifies err
+func newResultError(err error) *resultError {
+	class := classifyError(err)
+
+	return &resultError{Class: class, Message: err.Error(), ExitCode: exitCodes[class]}
+}
+
+// classifyError maps an error to its class: invalid input, unreachable device, timeout or an error reported by the device
+func classifyError(err error) string {
+	var (
+		usageErr  *usageError
+		apiErr    *models.APIError
+		statusErr *client.StatusError
+		netErr    net.Error
+		urlErr    *url.Error
+		opErr     *net.OpError
+	)
+
+	switch {
+	case errors.As(err, &usageErr):
+		return errorClassUsage
+	case errors.As(err, &apiErr), errors.As(err, &statusErr):
+		return errorClassDevice
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return errorClassTimeout
+	case errors.As(err, &opErr), errors.As(err, &urlErr):
+		return errorClassConnection
+	case strings.HasPrefix(err.Error(), "Required flag"), strings.HasPrefix(err.Error(), "flag provided but not defined"):
+		// urfave/cli does not export its usage errors
+		return errorClassUsage
+	default:
+		return errorClassGeneral
+	}
+}
+
+// writeDocument encodes v as JSON or YAML. Compact documents are single JSON lines or
+// YAML documents starting with "---", for streams of events.
+func writeDocument(w io.Writer, format string, v any, compact bool) error {
+	value, err := toOutputValue(v)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+
+	switch format {
+	case outputYAML:
+		if compact {
+			buf.WriteString("---\n")
+		}
+
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+
+		if err := encoder.Encode(toYAMLNode(value)); err != nil {
+			return fmt.Errorf("failed to encode output: %w", err)
+		}
+
+		if err := encoder.Close(); err != nil {
+			return fmt.Errorf("failed to encode output: %w", err)
+		}
+	default:
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+
+		if !compact {
+			encoder.SetIndent("", "  ")
+		}
+
+		if err := encoder.Encode(value); err != nil {
+			return fmt.Errorf("failed to encode output: %w", err)
+		}
+	}
+
+	_, err = w.Write(buf.Bytes())
+
+	return err
+}
+
+// outputField is a key and value of an outputObject
+type outputField struct {
+	key   string
+	value any
+}
+
+// outputObject is a JSON object that keeps the order of its keys
+type outputObject []outputField
+
+// MarshalJSON encodes the object with its keys in order
+func (o outputObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+
+	for i, field := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, _ := json.Marshal(field.key)
+		buf.Write(key)
+		buf.WriteByte(':')
+
+		value, err := json.Marshal(field.value)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(value)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// toOutputValue converts v into objects, lists and scalars following its JSON encoding.
+// The XMLName fields of the models are dropped, as they only describe the device API.
+func toOutputValue(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode output: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	return decodeOutputValue(decoder)
+}
+
+func decodeOutputValue(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+
+	switch delim {
+	case '{':
+		object := outputObject{}
+
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := decodeOutputValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+
+			if key := keyToken.(string); key != "XMLName" {
+				object = append(object, outputField{key, value})
+			}
+		}
+
+		_, err := decoder.Token()
+
+		return object, err
+	default:
+		list := []any{}
+
+		for decoder.More() {
+			value, err := decodeOutputValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+
+			list = append(list, value)
+		}
+
+		_, err := decoder.Token()
+
+		return list, err
+	}
+}
+
+// toYAMLNode converts a value from toOutputValue into a YAML node, keeping the order of keys and JSON numbers
+func toYAMLNode(value any) *yaml.Node {
+	switch v := value.(type) {
+	case outputObject:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+
+		for _, field := range v {
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: field.key}, toYAMLNode(field.value))
+		}
+
+		return node
+	case []any:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+
+		for _, item := range v {
+			node.Content = append(node.Content, toYAMLNode(item))
+		}
+
+		return node
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(v.String(), ".eE") {
+			tag = "!!float"
+		}
+
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: v.String()}
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprint(v)}
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: fmt.Sprint(v)}
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gesellix/bose-soundtouch/pkg/client"
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/urfave/cli/v2"
+)
+
+func TestWriteDocument(t *testing.T) {
+	volume := &models.Volume{DeviceID: "AABBCC", TargetVolume: 30, ActualVolume: 25, MuteEnabled: false}
+	result := commandResult{Command: "volume get", Device: "192.168.1.10:8090", Status: "ok", Data: volume}
+
+	var jsonOut bytes.Buffer
+	if err := writeDocument(&jsonOut, outputJSON, result, true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedJSON := `{"command":"volume get","device":"192.168.1.10:8090","status":"ok","data":{"DeviceID":"AABBCC","TargetVolume":30,"ActualVolume":25,"MuteEnabled":false}}` + "\n"
+	if jsonOut.String() != expectedJSON {
+		t.Errorf("Unexpected JSON:\n%s\nwant:\n%s", jsonOut.String(), expectedJSON)
+	}
+
+	var yamlOut bytes.Buffer
+	if err := writeDocument(&yamlOut, outputYAML, result, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedYAML := `command: volume get
+device: 192.168.1.10:8090
+status: ok
+data:
+  DeviceID: AABBCC
+  TargetVolume: 30
+  ActualVolume: 25
+  MuteEnabled: false
+`
+	if yamlOut.String() != expectedYAML {
+		t.Errorf("Unexpected YAML:\n%s\nwant:\n%s", yamlOut.String(), expectedYAML)
+	}
+}
+
+func TestWriteDocument_NestedYAML(t *testing.T) {
+	value := map[string]any{
+		"items": []any{
+			map[string]any{"name": "Jazz: live", "tags": []string{"a", "b"}},
+			"plain",
+			[]int{},
+		},
+		"empty":  map[string]any{},
+		"quoted": []string{"", "true", "-1", "# comment", "line\nbreak"},
+	}
+
+	var buf bytes.Buffer
+	if err := writeDocument(&buf, outputYAML, value, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `empty: {}
+items:
+  - name: 'Jazz: live'
+    tags:
+      - a
+      - b
+  - plain
+  - []
+quoted:
+  - ""
+  - "true"
+  - "-1"
+  - '# comment'
+  - |-
+    line
+    break
+`
+	if buf.String() != expected {
+		t.Errorf("Unexpected YAML:\n%s\nwant:\n%s", buf.String(), expected)
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	host, port, _ := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+
+	var portNumber int
+	if _, err := fmt.Sscan(port, &portNumber); err != nil {
+		t.Fatalf("Invalid port: %v", err)
+	}
+
+	_, statusErr := client.NewClient(&client.Config{Host: host, Port: portNumber}).GetVolume()
+
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"usage", fmt.Errorf("failed: %w", &usageError{errors.New("host is required")}), errorClassUsage},
+		{"required flag", errors.New(`Required flag "name" not set`), errorClassUsage},
+		{"status", statusErr, errorClassDevice},
+		{"api error", fmt.Errorf("failed: %w", &models.APIError{Code: 1, Message: "bad"}), errorClassDevice},
+		{"connection", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, errorClassConnection},
+		{"timeout", fmt.Errorf("failed: %w", &timeoutError{}), errorClassTimeout},
+		{"group", &groupError{message: "failed", errs: []error{errors.New("x"), &net.OpError{Op: "dial", Err: errors.New("refused")}}}, errorClassConnection},
+		{"general", errors.New("something else"), errorClassGeneral},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyError(tt.err); got != tt.expected {
+				t.Errorf("classifyError(%v) = %s, want %s", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
+
+type timeoutError struct{}
+
+func (e *timeoutError) Error() string   { return "i/o timeout" }
+func (e *timeoutError) Timeout() bool   { return true }
+func (e *timeoutError) Temporary() bool { return true }
+
+func TestRecordResult(t *testing.T) {
+	var out bytes.Buffer
+
+	previous := output
+	output = &outputState{format: outputJSON, out: &out}
+
+	defer func() { output = previous }()
+
+	app := &cli.App{
+		Name: "soundtouch-cli",
+		Commands: []*cli.Command{
+			{
+				Name: "volume",
+				Subcommands: []*cli.Command{
+					{
+						Name: "get",
+						Action: func(c *cli.Context) error {
+							emitResult(c, map[string]int{"level": 20})
+							return nil
+						},
+					},
+					{
+						Name: "set",
+						Action: func(_ *cli.Context) error {
+							return nil
+						},
+					},
+				},
+			},
+		},
+	}
+	applyOutput(app.Commands)
+
+	if err := app.Run([]string{"soundtouch-cli", "volume", "get"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := app.Run([]string{"soundtouch-cli", "volume", "set"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if code := finishOutput(nil); code != 0 {
+		t.Errorf("Expected exit code 0, got %d", code)
+	}
+
+	expected := `[
+  {
+    "command": "volume get",
+    "status": "ok",
+    "data": {
+      "level": 20
+    }
+  },
+  {
+    "command": "volume set",
+    "status": "ok"
+  }
+]
+`
+	if out.String() != expected {
+		t.Errorf("Unexpected output:\n%s\nwant:\n%s", out.String(), expected)
+	}
+}
@@ -358,13 +358,13 @@ func (sac *ServiceAvailabilityChecker) PrintServiceAvailabilitySummary() {
 		return
 	}
 
-	fmt.Printf("📊 Service Availability Summary:\n")
-	fmt.Printf("   Total services: %d\n", sac.serviceAvailability.GetServiceCount())
-	fmt.Printf("   Available: %d\n", sac.serviceAvailability.GetAvailableServiceCount())
-	fmt.Printf("   Unavailable: %d\n", sac.serviceAvailability.GetUnavailableServiceCount())
+	output.Printf("📊 Service Availability Summary:\n")
+	output.Printf("   Total services: %d\n", sac.serviceAvailability.GetServiceCount())
+	output.Printf("   Available: %d\n", sac.serviceAvailability.GetAvailableServiceCount())
+	output.Printf("   Unavailable: %d\n", sac.serviceAvailability.GetUnavailableServiceCount())
 
 	// Show quick status for popular services
-	fmt.Printf("   Popular services:\n")
+	output.Printf("   Popular services:\n")
 
 	popularChecks := []struct {
 		check func() bool
@@ -383,8 +383,8 @@ func (sac *ServiceAvailabilityChecker) PrintServiceAvailabilitySummary() {
 			status = "✅"
 		}
 
-		fmt.Printf("     %s %s\n", status, check.name)
+		output.Printf("     %s %s\n", status, check.name)
 	}
 
-	fmt.Printf("💡 Use 'soundtouch-cli sources list' to see configured sources\n")
+	output.Printf("💡 Use 'soundtouch-cli sources list' to see configured sources\n")
 }
@@ -8,22 +8,32 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
 	"runtime/debug"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/gesellix/bose-soundtouch/pkg/config"
 	"github.com/gesellix/bose-soundtouch/pkg/discovery"
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/gesellix/bose-soundtouch/pkg/service/artwork"
 	"github.com/gesellix/bose-soundtouch/pkg/service/certmanager"
+	"github.com/gesellix/bose-soundtouch/pkg/service/clocksync"
 	"github.com/gesellix/bose-soundtouch/pkg/service/datastore"
 	"github.com/gesellix/bose-soundtouch/pkg/service/handlers"
+	"github.com/gesellix/bose-soundtouch/pkg/service/history"
+	"github.com/gesellix/bose-soundtouch/pkg/service/presetbank"
 	"github.com/gesellix/bose-soundtouch/pkg/service/proxy"
 	"github.com/gesellix/bose-soundtouch/pkg/service/setup"
 	"github.com/gesellix/bose-soundtouch/pkg/service/spotify"
+	"github.com/gesellix/bose-soundtouch/pkg/service/worker"
+	"github.com/gesellix/bose-soundtouch/pkg/service/zonesupervisor"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/urfave/cli/v2"
@@ -195,6 +205,95 @@ func main() {
 				Usage:   "External base URL for OAuth callbacks behind reverse proxy",
 				EnvVars: []string{"BASE_URL"},
 			},
+			&cli.BoolFlag{
+				Name:    "speaker-mirror",
+				Usage:   "Answer /api/speakers reads from live state mirrors kept in sync over WebSocket",
+				Value:   false,
+				EnvVars: []string{"SPEAKER_MIRROR"},
+			},
+			&cli.BoolFlag{
+				Name:    "artwork-cache",
+				Usage:   "Cache cover art in the data directory and serve it via /media/art/{hash}",
+				Value:   true,
+				EnvVars: []string{"ARTWORK_CACHE"},
+			},
+			&cli.BoolFlag{
+				Name:    "zone-supervisor",
+				Usage:   "Watch multiroom zones and heal them after the master or a member dropped out",
+				Value:   false,
+				EnvVars: []string{"ZONE_SUPERVISOR"},
+			},
+			&cli.StringFlag{
+				Name:    "zone-supervisor-policy",
+				Usage:   "How broken zones are healed: rejoin, promote or release",
+				Value:   "rejoin",
+				EnvVars: []string{"ZONE_SUPERVISOR_POLICY"},
+			},
+			&cli.StringFlag{
+				Name:    "zone-supervisor-grace",
+				Usage:   "How long a zone master may be unreachable before members are promoted or released",
+				Value:   "30s",
+				EnvVars: []string{"ZONE_SUPERVISOR_GRACE"},
+			},
+			&cli.BoolFlag{
+				Name:    "preset-bank-schedules",
+				Usage:   "Activate preset banks at their scheduled time of day",
+				Value:   false,
+				EnvVars: []string{"PRESET_BANK_SCHEDULES"},
+			},
+			&cli.BoolFlag{
+				Name:    "history",
+				Usage:   "Record the listening history of all speakers from recents and now playing events",
+				Value:   false,
+				EnvVars: []string{"HISTORY"},
+			},
+			&cli.StringFlag{
+				Name:    "history-max-age",
+				Usage:   "Remove history entries older than this, as days (365d) or duration (720h); 0 keeps them forever",
+				Value:   "365d",
+				EnvVars: []string{"HISTORY_MAX_AGE"},
+			},
+			&cli.IntFlag{
+				Name:    "history-max-entries",
+				Usage:   "Keep at most this many history entries; 0 for no limit",
+				Value:   0,
+				EnvVars: []string{"HISTORY_MAX_ENTRIES"},
+			},
+			&cli.BoolFlag{
+				Name:    "clock-sync",
+				Usage:   "Check the clocks of all speakers periodically and after power on, and correct drift",
+				Value:   false,
+				EnvVars: []string{"CLOCK_SYNC"},
+			},
+			&cli.StringFlag{
+				Name:    "clock-sync-interval",
+				Usage:   "How often the clocks of all speakers are checked",
+				Value:   "1h",
+				EnvVars: []string{"CLOCK_SYNC_INTERVAL"},
+			},
+			&cli.StringFlag{
+				Name:    "clock-sync-threshold",
+				Usage:   "How far a speaker clock may drift before it is corrected",
+				Value:   "10s",
+				EnvVars: []string{"CLOCK_SYNC_THRESHOLD"},
+			},
+			&cli.StringFlag{
+				Name:    "clock-sync-zone",
+				Usage:   "IANA time zone of speakers without their own zone, e.g. Europe/Berlin; empty for the local time zone",
+				Value:   "",
+				EnvVars: []string{"CLOCK_SYNC_ZONE"},
+			},
+			&cli.BoolFlag{
+				Name:    "mdns-advertise",
+				Usage:   "Advertise this service via mDNS as " + discovery.ServiceType + " so clients can find it",
+				Value:   true,
+				EnvVars: []string{"MDNS_ADVERTISE"},
+			},
+			&cli.StringFlag{
+				Name:    "mdns-instance",
+				Usage:   "Instance name of the mDNS announcement (default: soundtouch-service on <hostname>)",
+				EnvVars: []string{"MDNS_INSTANCE"},
+			},
 		},
 		Action: func(c *cli.Context) error {
 			config := loadConfig(c)
@@ -228,6 +327,18 @@ func main() {
 			server.SetMgmtConfig(config.mgmtUsername, config.mgmtPassword)
 			server.SetZeroconfEnabled(config.zeroconfEnabled)
 			server.SetBaseURL(config.baseURL)
+			server.SetSpeakerMirrorEnabled(config.speakerMirror)
+			server.SetSpeakerConfig(loadSpeakerConfig())
+
+			if registry, err := discovery.OpenDeviceRegistry(discovery.RegistryPathInDataDir(config.dataDir)); err != nil {
+				log.Printf("Warning: Failed to open device registry: %v", err)
+			} else {
+				server.SetDeviceRegistry(registry)
+			}
+
+			if config.artworkCache {
+				server.SetArtworkCache(artwork.NewCache(filepath.Join(config.dataDir, "artwork")))
+			}
 
 			var spotifyService *spotify.SpotifyService
 			if config.spotifyClientID != "" {
@@ -253,6 +364,43 @@ func main() {
 				log.Printf("ZeroConf Spotify primer enabled (45-minute refresh)")
 			}
 
+			speakers := worker.KnownSpeakers(ds)
+
+			if config.zoneSupervisor {
+				supervisor := zonesupervisor.New(speakers, ds.AddDeviceEvent, config.zoneSupervisorPolicy)
+				supervisor.SetGracePeriod(config.zoneSupervisorGrace)
+				supervisor.SetEventsEnabled(true)
+				supervisor.Start()
+				defer supervisor.Stop()
+			}
+
+			if config.presetBankSchedules {
+				scheduler := presetbank.NewScheduler(ds, speakers, ds.AddDeviceEvent)
+				scheduler.Start()
+				defer scheduler.Stop()
+			}
+
+			if config.history {
+				ds.SetHistoryEnabled(true)
+
+				recorder := history.NewRecorder(ds, speakers)
+				recorder.SetRetention(config.historyRetention)
+				recorder.SetStateSource(server.SpeakerState)
+				recorder.Start()
+				defer recorder.Stop()
+			}
+
+			clockSyncer := clocksync.New(ds, speakers, ds.AddDeviceEvent)
+			clockSyncer.SetInterval(config.clockSyncInterval)
+			clockSyncer.SetThreshold(config.clockSyncThreshold)
+			clockSyncer.SetDefaultZone(config.clockSyncZone)
+			server.SetClockSyncer(clockSyncer)
+
+			if config.clockSync {
+				clockSyncer.Start()
+				defer clockSyncer.Stop()
+			}
+
 			// Load and set initial DNS discoveries
 			dnsDiscoveries, err := ds.LoadDNSDiscoveries()
 			if err == nil && len(dnsDiscoveries) > 0 {
@@ -321,6 +469,14 @@ func main() {
 				startHTTPSServer(config.httpsAddr, r, tlsConfig, config.httpsServerURL)
 			}
 
+			if config.mdnsAdvertise {
+				if advertiser := startServiceAdvertiser(config, tlsConfig != nil); advertiser != nil {
+					defer func() {
+						_ = advertiser.Shutdown()
+					}()
+				}
+			}
+
 			return http.ListenAndServe(config.addr, r)
 		},
 		Commands: []*cli.Command{
@@ -357,6 +513,7 @@ type serviceConfig struct {
 	serverURL            string
 	httpsServerURL       string
 	httpsAddr            string
+	httpsPort            string
 	redact               bool
 	logBody              bool
 	record               bool
@@ -373,6 +530,20 @@ type serviceConfig struct {
 	mgmtPassword         string
 	zeroconfEnabled      bool
 	baseURL              string
+	speakerMirror        bool
+	artworkCache         bool
+	zoneSupervisor       bool
+	zoneSupervisorPolicy zonesupervisor.Policy
+	zoneSupervisorGrace  time.Duration
+	presetBankSchedules  bool
+	history              bool
+	historyRetention     models.HistoryRetention
+	clockSync            bool
+	clockSyncInterval    time.Duration
+	clockSyncThreshold   time.Duration
+	clockSyncZone        *time.Location
+	mdnsAdvertise        bool
+	mdnsInstance         string
 }
 
 func loadConfig(c *cli.Context) serviceConfig {
@@ -438,6 +609,62 @@ func loadConfig(c *cli.Context) serviceConfig {
 	mgmtPassword := c.String("mgmt-password")
 	zeroconfEnabled := c.Bool("zeroconf-primer-enabled")
 	baseURL := c.String("base-url")
+	speakerMirror := c.Bool("speaker-mirror")
+	artworkCache := c.Bool("artwork-cache")
+	zoneSupervisor := c.Bool("zone-supervisor")
+
+	zoneSupervisorPolicy, err := zonesupervisor.ParsePolicy(c.String("zone-supervisor-policy"))
+	if err != nil {
+		log.Printf("Warning: %v, using default rejoin", err)
+
+		zoneSupervisorPolicy = zonesupervisor.PolicyRejoin
+	}
+
+	zoneSupervisorGraceStr := c.String("zone-supervisor-grace")
+
+	zoneSupervisorGrace, err := time.ParseDuration(zoneSupervisorGraceStr)
+	if err != nil {
+		log.Printf("Warning: Failed to parse zone supervisor grace %s, using default 30s: %v", zoneSupervisorGraceStr, err)
+
+		zoneSupervisorGrace = 30 * time.Second
+	}
+
+	historyMaxAge, err := models.ParseHistoryAge(c.String("history-max-age"))
+	if err != nil {
+		log.Printf("Warning: %v, using default 365d", err)
+
+		historyMaxAge = 365 * 24 * time.Hour
+	}
+
+	clockSyncIntervalStr := c.String("clock-sync-interval")
+
+	clockSyncInterval, err := time.ParseDuration(clockSyncIntervalStr)
+	if err != nil || clockSyncInterval <= 0 {
+		log.Printf("Warning: Invalid clock sync interval %s, using default 1h", clockSyncIntervalStr)
+
+		clockSyncInterval = time.Hour
+	}
+
+	clockSyncThresholdStr := c.String("clock-sync-threshold")
+
+	clockSyncThreshold, err := time.ParseDuration(clockSyncThresholdStr)
+	if err != nil || clockSyncThreshold < 0 {
+		log.Printf("Warning: Invalid clock sync threshold %s, using default 10s", clockSyncThresholdStr)
+
+		clockSyncThreshold = 10 * time.Second
+	}
+
+	clockSyncZone, err := models.ParseClockZone(c.String("clock-sync-zone"))
+	if err != nil {
+		log.Printf("Warning: %v, using the local time zone", err)
+
+		clockSyncZone = time.Local
+	}
+
+	mdnsInstance := c.String("mdns-instance")
+	if mdnsInstance == "" {
+		mdnsInstance = "soundtouch-service on " + hostname
+	}
 
 	return serviceConfig{
 		port:                 port,
@@ -448,6 +675,7 @@ func loadConfig(c *cli.Context) serviceConfig {
 		serverURL:            serverURL,
 		httpsServerURL:       httpsServerURL,
 		httpsAddr:            httpsAddr,
+		httpsPort:            httpsPort,
 		redact:               redact,
 		logBody:              logBody,
 		record:               record,
@@ -464,9 +692,54 @@ func loadConfig(c *cli.Context) serviceConfig {
 		mgmtPassword:         mgmtPassword,
 		zeroconfEnabled:      zeroconfEnabled,
 		baseURL:              baseURL,
+		speakerMirror:        speakerMirror,
+		artworkCache:         artworkCache,
+		zoneSupervisor:       zoneSupervisor,
+		zoneSupervisorPolicy: zoneSupervisorPolicy,
+		zoneSupervisorGrace:  zoneSupervisorGrace,
+		presetBankSchedules:  c.Bool("preset-bank-schedules"),
+		history:              c.Bool("history"),
+		historyRetention:     models.HistoryRetention{MaxAge: historyMaxAge, MaxEntries: c.Int("history-max-entries")},
+		clockSync:            c.Bool("clock-sync"),
+		clockSyncInterval:    clockSyncInterval,
+		clockSyncThreshold:   clockSyncThreshold,
+		clockSyncZone:        clockSyncZone,
+		mdnsAdvertise:        c.Bool("mdns-advertise"),
+		mdnsInstance:         mdnsInstance,
 	}
 }
 
+// startServiceAdvertiser announces the service via mDNS; it returns nil if the announcement failed
+func startServiceAdvertiser(config serviceConfig, httpsEnabled bool) *discovery.ServiceAdvertiser {
+	httpPort, err := strconv.Atoi(config.port)
+	if err != nil {
+		log.Printf("Warning: Cannot advertise service via mDNS, invalid port %s", config.port)
+		return nil
+	}
+
+	announcement := discovery.ServiceAnnouncement{
+		Instance: config.mdnsInstance,
+		Version:  version,
+		HTTPPort: httpPort,
+	}
+
+	if httpsEnabled {
+		announcement.HTTPSPort, _ = strconv.Atoi(config.httpsPort)
+	}
+
+	if ip := net.ParseIP(config.bindAddr); ip != nil && !ip.IsUnspecified() {
+		announcement.IPs = []net.IP{ip}
+	}
+
+	advertiser, err := discovery.AdvertiseService(announcement)
+	if err != nil {
+		log.Printf("Warning: Failed to advertise service via mDNS: %v", err)
+		return nil
+	}
+
+	return advertiser
+}
+
 func getDomains(serverURL, httpsServerURL, hostname string) []string {
 	domainsMap := map[string]bool{
 		"streaming.bose.com":  true,
@@ -570,6 +843,17 @@ func initDataStore(dataDir string) *datastore.DataStore {
 	return ds
 }
 
+// loadSpeakerConfig loads the configuration shared with the CLI from the environment, e.g. ZONES
+func loadSpeakerConfig() *config.Config {
+	cfg, err := config.LoadFromEnv()
+	if err != nil {
+		log.Printf("Warning: Failed to load configuration from environment, using defaults: %v", err)
+		return config.DefaultConfig()
+	}
+
+	return cfg
+}
+
 func initCertificateManager(dataDir string) *certmanager.CertificateManager {
 	cm := certmanager.NewCertificateManager(filepath.Join(dataDir, "certs"))
 	if err := cm.EnsureCA(); err != nil {
@@ -580,16 +864,7 @@ func initCertificateManager(dataDir string) *certmanager.CertificateManager {
 }
 
 func startDeviceDiscovery(server *handlers.Server) {
-	go func() {
-		for {
-			currentInterval, enabled := server.GetDiscoverySettings()
-			if enabled {
-				server.DiscoverDevices(context.Background())
-			}
-
-			time.Sleep(currentInterval)
-		}
-	}()
+	go server.WatchDevices(context.Background())
 }
 
 func setupRouter(server *handlers.Server) *chi.Mux {
@@ -606,6 +881,7 @@ func setupRouter(server *handlers.Server) *chi.Mux {
 		server.HandleMedia()(w, r)
 	})
 
+	r.Get("/media/art/{hash}", server.HandleArtwork)
 	r.Get("/media/*", server.HandleMedia())
 	r.Get("/web/*", server.HandleWeb())
 	r.Get("/docs/*", server.HandleDocs)
@@ -688,6 +964,7 @@ func setupRouter(server *handlers.Server) *chi.Mux {
 	})
 
 	r.Get("/proxy/*", server.HandleProxyRequest)
+	r.Post("/proxy/*", server.HandleSpeakerProxyRequest)
 
 	r.Route("/setup", func(r chi.Router) {
 		r.Get("/devices", server.HandleListDiscoveredDevices)
@@ -743,6 +1020,37 @@ func setupRouter(server *handlers.Server) *chi.Mux {
 		r.Get("/{id}/standby", server.HandleAPISpeakerStandby)
 		r.Post("/{id}/name", server.HandleAPISpeakerSetName)
 		r.Get("/{id}/zones", server.HandleAPISpeakerZones)
+		r.Get("/{id}/preset-banks", server.HandleAPIPresetBanksList)
+		r.Post("/{id}/preset-banks", server.HandleAPIPresetBankCreate)
+		r.Get("/{id}/preset-banks/{name}", server.HandleAPIPresetBankGet)
+		r.Put("/{id}/preset-banks/{name}", server.HandleAPIPresetBankUpdate)
+		r.Delete("/{id}/preset-banks/{name}", server.HandleAPIPresetBankDelete)
+		r.Post("/{id}/preset-banks/{name}/activate", server.HandleAPIPresetBankActivate)
+	})
+
+	r.Route("/api/history", func(r chi.Router) {
+		r.Get("/", server.HandleAPIHistory)
+		r.Get("/stats", server.HandleAPIHistoryStats)
+		r.Get("/export", server.HandleAPIHistoryExport)
+	})
+
+	r.Route("/api/clock", func(r chi.Router) {
+		r.Get("/", server.HandleAPIClock)
+		r.Post("/sync", server.HandleAPIClockSync)
+		r.Get("/zones", server.HandleAPIClockZones)
+		r.Put("/zones/{id}", server.HandleAPIClockZoneSet)
+		r.Delete("/zones/{id}", server.HandleAPIClockZoneDelete)
+	})
+
+	r.Route("/api/zones", func(r chi.Router) {
+		r.Get("/", server.HandleAPIZonesList)
+		r.Post("/", server.HandleAPIZoneCreate)
+		r.Get("/{name}", server.HandleAPIZoneGet)
+		r.Put("/{name}", server.HandleAPIZoneUpdate)
+		r.Delete("/{name}", server.HandleAPIZoneDelete)
+		r.Post("/{name}/apply", server.HandleAPIZoneApply)
+		r.Get("/{name}/volume", server.HandleAPIZoneVolume)
+		r.Post("/{name}/volume", server.HandleAPIZoneSetVolume)
 	})
 
 	r.NotFound(server.HandleNotFound)
@@ -0,0 +1,844 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+// backupEndpoints maps each section to the endpoint a device must support to capture or restore it
+var backupEndpoints = map[string]string{
+	models.BackupSectionName:     "/name",
+	models.BackupSectionLanguage: "/language",
+	models.BackupSectionAccounts: "/sources",
+	models.BackupSectionPresets:  "/presets",
+	models.BackupSectionClock:    "/clockDisplay",
+	models.BackupSectionBass:     "/bass",
+	models.BackupSectionBalance:  "/balance",
+	models.BackupSectionTone:     "/audioproducttonecontrols",
+	models.BackupSectionLevels:   "/audioproductlevelcontrols",
+	models.BackupSectionDSP:      "/audiodspcontrols",
+	models.BackupSectionZone:     "/getZone",
+}
+
+// backupCapabilities maps sections to the capability a device must also list, like the advanced audio controls
+var backupCapabilities = map[string]string{
+	models.BackupSectionTone:   "audioproducttonecontrols",
+	models.BackupSectionLevels: "audioproductlevelcontrols",
+	models.BackupSectionDSP:    "audiodspcontrols",
+}
+
+// accountSources are the music services whose accounts are part of a backup
+var accountSources = map[string]bool{
+	"SPOTIFY":      true,
+	"PANDORA":      true,
+	"STORED_MUSIC": true,
+	"AMAZON":       true,
+	"DEEZER":       true,
+	"IHEART":       true,
+}
+
+// supportedEndpoints returns a check for endpoints of the device.
+// Devices without /supportedURLs are assumed to support everything.
+func (c *Client) supportedEndpoints() func(endpoint string) bool {
+	urls, err := c.GetSupportedURLs()
+	if err != nil || urls.GetURLCount() == 0 {
+		return func(string) bool { return true }
+	}
+
+	return urls.HasURL
+}
+
+// supportedSections returns a check for backup sections of the device, based on its endpoints and capabilities
+func (c *Client) supportedSections(supported func(endpoint string) bool) func(section string) bool {
+	capabilities, err := c.GetCapabilities()
+
+	return func(section string) bool {
+		if !supported(backupEndpoints[section]) {
+			return false
+		}
+
+		capability, ok := backupCapabilities[section]
+
+		return !ok || err == nil && c.hasCapability(capabilities, capability)
+	}
+}
+
+// Backup captures the user-facing configuration of the device.
+// Without sections, all sections are captured. Sections the device does not support are left out,
+// sections that fail to load are left out and their errors returned joined with the partial backup.
+func (c *Client) Backup(sections []string) (*models.DeviceBackup, error) {
+	if err := models.ValidateBackupSections(sections); err != nil {
+		return nil, err
+	}
+
+	if len(sections) == 0 {
+		sections = models.BackupSections
+	}
+
+	info, err := c.GetDeviceInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device info: %w", err)
+	}
+
+	backup := &models.DeviceBackup{
+		Version:   models.DeviceBackupVersion,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		Device:    models.BackupDevice{DeviceID: info.DeviceID, Name: info.Name, Type: info.Type, Host: c.Host()},
+	}
+
+	supported := c.supportedSections(c.supportedEndpoints())
+
+	var errs []error
+
+	for _, section := range models.BackupSections {
+		if !slices.Contains(sections, section) || !supported(section) {
+			continue
+		}
+
+		captured, err := c.captureSection(backup, section, info)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to back up %s: %w", section, err))
+			continue
+		}
+
+		if captured {
+			backup.Sections = append(backup.Sections, section)
+		}
+	}
+
+	return backup, errors.Join(errs...)
+}
+
+// captureSection reads one section into the backup; false means the device has no such setting
+func (c *Client) captureSection(backup *models.DeviceBackup, section string, info *models.DeviceInfo) (bool, error) {
+	switch section {
+	case models.BackupSectionName:
+		backup.Name = info.Name
+	case models.BackupSectionLanguage:
+		language, err := c.GetLanguage()
+		if err != nil {
+			return false, err
+		}
+
+		backup.Language = language.Value
+	case models.BackupSectionAccounts:
+		sources, err := c.GetSources()
+		if err != nil {
+			return false, err
+		}
+
+		backup.Accounts = backupAccounts(sources)
+	case models.BackupSectionPresets:
+		presets, err := c.GetPresets()
+		if err != nil {
+			return false, err
+		}
+
+		for i := range presets.Preset {
+			if !presets.Preset[i].IsEmpty() {
+				backup.Presets = append(backup.Presets, models.NewBackupPreset(&presets.Preset[i]))
+			}
+		}
+	case models.BackupSectionClock:
+		clock, err := c.GetClockDisplay()
+		if err != nil {
+			return false, err
+		}
+
+		backup.Clock = &models.BackupClockDisplay{
+			Enabled:    clock.Enabled,
+			Format:     clock.Format,
+			Brightness: clock.Brightness,
+			AutoDim:    clock.AutoDim,
+			TimeZone:   clock.TimeZone,
+		}
+	case models.BackupSectionBass:
+		if capabilities, err := c.GetBassCapabilities(); err == nil && !capabilities.IsBassSupported() {
+			return false, nil
+		}
+
+		bass, err := c.GetBass()
+		if err != nil {
+			return false, err
+		}
+
+		backup.Bass = &bass.TargetBass
+	case models.BackupSectionBalance:
+		balance, err := c.GetBalance()
+		if err != nil {
+			return false, err
+		}
+
+		backup.Balance = &balance.TargetBalance
+	case models.BackupSectionTone:
+		tone, err := c.GetAudioProductToneControls()
+		if err != nil {
+			return false, err
+		}
+
+		backup.Tone = &models.BackupTone{Bass: tone.Bass.Value, Treble: tone.Treble.Value}
+	case models.BackupSectionLevels:
+		levels, err := c.GetAudioProductLevelControls()
+		if err != nil {
+			return false, err
+		}
+
+		backup.Levels = &models.BackupLevels{
+			FrontCenter:  levels.FrontCenterSpeakerLevel.Value,
+			RearSurround: levels.RearSurroundSpeakersLevel.Value,
+		}
+	case models.BackupSectionDSP:
+		dsp, err := c.GetAudioDSPControls()
+		if err != nil {
+			return false, err
+		}
+
+		backup.DSP = &models.BackupDSP{AudioMode: dsp.AudioMode, VideoSyncAudioDelay: dsp.VideoSyncAudioDelay}
+	case models.BackupSectionZone:
+		zone, err := c.GetZone()
+		if err != nil {
+			return false, err
+		}
+
+		if zone.Master != "" {
+			backup.Zone = &models.BackupZone{Master: zone.Master}
+
+			for _, member := range zone.Members {
+				backup.Zone.Members = append(backup.Zone.Members, models.BackupZoneMember{DeviceID: member.DeviceID, IP: member.IP})
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// backupAccounts returns the configured music service accounts
+func backupAccounts(sources *models.Sources) []models.BackupAccount {
+	var accounts []models.BackupAccount
+
+	for _, item := range sources.SourceItem {
+		if accountSources[item.Source] && item.SourceAccount != "" {
+			accounts = append(accounts, models.BackupAccount{Source: item.Source, Account: item.SourceAccount, DisplayName: item.DisplayName})
+		}
+	}
+
+	return accounts
+}
+
+// RestoreChange is a single setting a restore changes
+type RestoreChange struct {
+	Section string `json:"section"`
+	Setting string `json:"setting"`
+	Current string `json:"current"`
+	Desired string `json:"desired"`
+	// Skipped is why the change cannot be applied to this device; empty if it can
+	Skipped string `json:"skipped,omitempty"`
+
+	apply func() error
+}
+
+// String returns a one-line description of the change
+func (rc *RestoreChange) String() string {
+	line := rc.Setting
+	if rc.Current != "" || rc.Desired != "" {
+		line = fmt.Sprintf("%s: %s → %s", rc.Setting, rc.Current, rc.Desired)
+	}
+
+	if rc.Skipped != "" {
+		line += fmt.Sprintf(" (skipped: %s)", rc.Skipped)
+	}
+
+	return line
+}
+
+// RestorePlan is the difference between a backup and the current configuration of a device
+type RestorePlan struct {
+	Device    models.BackupDevice `json:"device"`
+	Changes   []RestoreChange     `json:"changes"`
+	Unchanged []string            `json:"unchanged,omitempty"`
+}
+
+// Pending returns the changes that will be applied
+func (p *RestorePlan) Pending() []RestoreChange {
+	var pending []RestoreChange
+
+	for _, change := range p.Changes {
+		if change.Skipped == "" {
+			pending = append(pending, change)
+		}
+	}
+
+	return pending
+}
+
+// Apply makes the pending changes in order.
+// Failed changes do not stop the restore; their errors are returned joined.
+func (p *RestorePlan) Apply() error {
+	var errs []error
+
+	for _, change := range p.Pending() {
+		if err := change.apply(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", change.Setting, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// restorePlanner compares a backup with the device it is restored to
+type restorePlanner struct {
+	c         *Client
+	backup    *models.DeviceBackup
+	info      *models.DeviceInfo
+	supported func(endpoint string) bool
+	plan      *RestorePlan
+	sources   *models.Sources
+	accounts  map[string]bool
+	// slots limits the planned preset slots; all slots if empty
+	slots []int
+	// expected records the preset each planned slot ends up with, nil for an empty slot
+	expected map[int]*models.BackupPreset
+}
+
+// newRestorePlanner prepares the comparison of a backup with the device
+func (c *Client) newRestorePlanner(backup *models.DeviceBackup, supported func(endpoint string) bool) (*restorePlanner, error) {
+	info, err := c.GetDeviceInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device info: %w", err)
+	}
+
+	return &restorePlanner{
+		c:         c,
+		backup:    backup,
+		info:      info,
+		supported: supported,
+		plan:      &RestorePlan{Device: models.BackupDevice{DeviceID: info.DeviceID, Name: info.Name, Type: info.Type, Host: c.Host()}},
+		accounts:  make(map[string]bool),
+		expected:  make(map[int]*models.BackupPreset),
+	}, nil
+}
+
+// PlanRestore compares a backup with the current configuration of the device.
+// Without sections, every section of the backup is planned. Settings the device cannot take,
+// like a DSP mode it does not support or a bass level out of its range, are planned as skipped.
+func (c *Client) PlanRestore(backup *models.DeviceBackup, sections []string) (*RestorePlan, error) {
+	if err := backup.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := models.ValidateBackupSections(sections); err != nil {
+		return nil, err
+	}
+
+	supported := c.supportedEndpoints()
+
+	p, err := c.newRestorePlanner(backup, supported)
+	if err != nil {
+		return nil, err
+	}
+
+	sectionSupported := c.supportedSections(supported)
+
+	for _, section := range models.BackupSections {
+		if len(sections) > 0 && !slices.Contains(sections, section) || !backup.HasSection(section) {
+			continue
+		}
+
+		if !sectionSupported(section) {
+			p.skip(section, section, "", "", fmt.Sprintf("not supported by %s", p.info.Type))
+			continue
+		}
+
+		if err := p.planSection(section); err != nil {
+			return nil, fmt.Errorf("failed to plan %s: %w", section, err)
+		}
+	}
+
+	return p.plan, nil
+}
+
+// add plans a change
+func (p *restorePlanner) add(section, setting, current, desired string, apply func() error) {
+	p.plan.Changes = append(p.plan.Changes, RestoreChange{Section: section, Setting: setting, Current: current, Desired: desired, apply: apply})
+}
+
+// skip plans a change the device cannot take
+func (p *restorePlanner) skip(section, setting, current, desired, reason string) {
+	p.plan.Changes = append(p.plan.Changes, RestoreChange{Section: section, Setting: setting, Current: current, Desired: desired, Skipped: reason})
+}
+
+// unchanged records a setting that already matches the backup
+func (p *restorePlanner) unchanged(setting string) {
+	p.plan.Unchanged = append(p.plan.Unchanged, setting)
+}
+
+// planSection compares one section of the backup with the device
+func (p *restorePlanner) planSection(section string) error {
+	switch section {
+	case models.BackupSectionName:
+		return p.planName()
+	case models.BackupSectionLanguage:
+		return p.planLanguage()
+	case models.BackupSectionAccounts:
+		return p.planAccounts()
+	case models.BackupSectionPresets:
+		return p.planPresets()
+	case models.BackupSectionClock:
+		return p.planClock()
+	case models.BackupSectionBass:
+		return p.planBass()
+	case models.BackupSectionBalance:
+		return p.planBalance()
+	case models.BackupSectionTone:
+		return p.planTone()
+	case models.BackupSectionLevels:
+		return p.planLevels()
+	case models.BackupSectionDSP:
+		return p.planDSP()
+	case models.BackupSectionZone:
+		return p.planZone()
+	}
+
+	return nil
+}
+
+func (p *restorePlanner) planName() error {
+	name := p.backup.Name
+
+	switch {
+	case name == "":
+	case name == p.info.Name:
+		p.unchanged("name")
+	default:
+		p.add(models.BackupSectionName, "name", p.info.Name, name, func() error { return p.c.SetName(name) })
+	}
+
+	return nil
+}
+
+func (p *restorePlanner) planLanguage() error {
+	language, err := p.c.GetLanguage()
+	if err != nil {
+		return err
+	}
+
+	code := p.backup.Language
+	current := models.GetLanguageName(language.Value)
+	desired := models.GetLanguageName(code)
+
+	switch {
+	case code == language.Value:
+		p.unchanged("language")
+	case !models.IsValidLanguageCode(code):
+		p.skip(models.BackupSectionLanguage, "language", current, desired, "unknown language code")
+	default:
+		p.add(models.BackupSectionLanguage, "language", current, desired, func() error { return p.c.SetLanguage(code) })
+	}
+
+	return nil
+}
+
+// loadSources reads the sources of the device once
+func (p *restorePlanner) loadSources() (*models.Sources, error) {
+	if p.sources != nil {
+		return p.sources, nil
+	}
+
+	sources, err := p.c.GetSources()
+	if err != nil {
+		return nil, err
+	}
+
+	p.sources = sources
+
+	for _, account := range backupAccounts(sources) {
+		p.accounts[account.Source+"/"+account.Account] = true
+	}
+
+	return sources, nil
+}
+
+func (p *restorePlanner) planAccounts() error {
+	if _, err := p.loadSources(); err != nil {
+		return err
+	}
+
+	for _, account := range p.backup.Accounts {
+		key := account.Source + "/" + account.Account
+		setting := fmt.Sprintf("account %s %s", account.Source, account.Account)
+
+		switch {
+		case p.accounts[key]:
+			p.unchanged(setting)
+		case account.Source != "STORED_MUSIC":
+			p.skip(models.BackupSectionAccounts, setting, "missing", "configured", "needs the account password, which is not part of a backup")
+		case !p.supported("/setMusicServiceAccount"):
+			p.skip(models.BackupSectionAccounts, setting, "missing", "configured", fmt.Sprintf("not supported by %s", p.info.Type))
+		default:
+			p.accounts[key] = true
+			p.add(models.BackupSectionAccounts, setting, "missing", "configured", func() error {
+				return p.c.AddStoredMusicAccount(account.Account, account.DisplayName)
+			})
+		}
+	}
+
+	return nil
+}
+
+// presetPlayable returns an empty string if the device can play the preset, or why it cannot
+func (p *restorePlanner) presetPlayable(preset *models.BackupPreset) (string, error) {
+	sources, err := p.loadSources()
+	if err != nil {
+		return "", err
+	}
+
+	if accountSources[preset.Source] && preset.Account != "" {
+		if p.accounts[preset.Source+"/"+preset.Account] {
+			return "", nil
+		}
+
+		return fmt.Sprintf("account %s of %s is not configured", preset.Account, preset.Source), nil
+	}
+
+	if len(sources.GetSourcesByType(preset.Source)) == 0 {
+		return fmt.Sprintf("source %s is not available on %s", preset.Source, p.info.Type), nil
+	}
+
+	return "", nil
+}
+
+func (p *restorePlanner) planPresets() error {
+	presets, err := p.c.GetPresets()
+	if err != nil {
+		return err
+	}
+
+	current := make(map[int]models.BackupPreset)
+
+	for i := range presets.Preset {
+		if !presets.Preset[i].IsEmpty() {
+			current[presets.Preset[i].ID] = models.NewBackupPreset(&presets.Preset[i])
+		}
+	}
+
+	desired := make(map[int]models.BackupPreset)
+	for _, preset := range p.backup.Presets {
+		desired[preset.Slot] = preset
+	}
+
+	for slot := 1; slot <= models.PresetSlots; slot++ {
+		if len(p.slots) > 0 && !slices.Contains(p.slots, slot) {
+			continue
+		}
+
+		setting := fmt.Sprintf("preset %d", slot)
+		have, hasCurrent := current[slot]
+		want, hasDesired := desired[slot]
+
+		switch {
+		case !hasCurrent && !hasDesired, hasCurrent && hasDesired && have == want:
+			p.unchanged(setting)
+		case !hasDesired:
+			p.expected[slot] = nil
+			p.add(models.BackupSectionPresets, setting, have.String(), "empty", func() error { return p.c.RemovePreset(slot) })
+		default:
+			currentLabel := "empty"
+			if hasCurrent {
+				currentLabel = have.String()
+			}
+
+			reason, err := p.presetPlayable(&want)
+			if err != nil {
+				return err
+			}
+
+			if reason != "" {
+				p.skip(models.BackupSectionPresets, setting, currentLabel, want.String(), reason)
+				continue
+			}
+
+			p.expected[slot] = &want
+			p.add(models.BackupSectionPresets, setting, currentLabel, want.String(), func() error {
+				return p.c.StorePreset(slot, want.ContentItem())
+			})
+		}
+	}
+
+	return nil
+}
+
+func (p *restorePlanner) planClock() error {
+	want := p.backup.Clock
+	if want == nil {
+		return nil
+	}
+
+	clock, err := p.c.GetClockDisplay()
+	if err != nil {
+		return err
+	}
+
+	have := &models.BackupClockDisplay{
+		Enabled:    clock.Enabled,
+		Format:     clock.Format,
+		Brightness: clock.Brightness,
+		AutoDim:    clock.AutoDim,
+		TimeZone:   clock.TimeZone,
+	}
+
+	if *have == *want {
+		p.unchanged("clock display")
+		return nil
+	}
+
+	request := models.NewClockDisplayRequest().
+		SetEnabled(want.Enabled).
+		SetBrightness(want.Brightness).
+		SetAutoDim(want.AutoDim).
+		SetTimeZone(want.TimeZone)
+	request.Format = want.Format
+
+	p.add(models.BackupSectionClock, "clock display", have.String(), want.String(), func() error { return p.c.SetClockDisplay(request) })
+
+	return nil
+}
+
+func (p *restorePlanner) planBass() error {
+	if p.backup.Bass == nil {
+		return nil
+	}
+
+	level := *p.backup.Bass
+	minLevel, maxLevel := models.BassLevelMin, models.BassLevelMax
+
+	if p.supported("/bassCapabilities") {
+		capabilities, err := p.c.GetBassCapabilities()
+		if err != nil {
+			return err
+		}
+
+		if !capabilities.IsBassSupported() {
+			p.skip(models.BackupSectionBass, "bass", "", fmt.Sprint(level), fmt.Sprintf("bass is not adjustable on %s", p.info.Type))
+			return nil
+		}
+
+		minLevel, maxLevel = capabilities.GetMinLevel(), capabilities.GetMaxLevel()
+	}
+
+	bass, err := p.c.GetBass()
+	if err != nil {
+		return err
+	}
+
+	current := fmt.Sprint(bass.TargetBass)
+
+	switch {
+	case level == bass.TargetBass:
+		p.unchanged("bass")
+	case level < minLevel || level > maxLevel:
+		p.skip(models.BackupSectionBass, "bass", current, fmt.Sprint(level), fmt.Sprintf("outside the range %d..%d of %s", minLevel, maxLevel, p.info.Type))
+	default:
+		p.add(models.BackupSectionBass, "bass", current, fmt.Sprint(level), func() error { return p.c.SetBass(level) })
+	}
+
+	return nil
+}
+
+func (p *restorePlanner) planBalance() error {
+	if p.backup.Balance == nil {
+		return nil
+	}
+
+	level := *p.backup.Balance
+
+	balance, err := p.c.GetBalance()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case level == balance.TargetBalance:
+		p.unchanged("balance")
+	case !models.ValidateBalanceLevel(level):
+		p.skip(models.BackupSectionBalance, "balance", fmt.Sprint(balance.TargetBalance), fmt.Sprint(level), "invalid balance level")
+	default:
+		p.add(models.BackupSectionBalance, "balance", fmt.Sprint(balance.TargetBalance), fmt.Sprint(level), func() error { return p.c.SetBalance(level) })
+	}
+
+	return nil
+}
+
+// planRange plans a setting with the value range reported by the device
+func (p *restorePlanner) planRange(section, setting string, current, desired, minValue, maxValue int, apply func() error) {
+	switch {
+	case current == desired:
+		p.unchanged(setting)
+	case desired < minValue || desired > maxValue:
+		p.skip(section, setting, fmt.Sprint(current), fmt.Sprint(desired), fmt.Sprintf("outside the range %d..%d of %s", minValue, maxValue, p.info.Type))
+	default:
+		p.add(section, setting, fmt.Sprint(current), fmt.Sprint(desired), apply)
+	}
+}
+
+func (p *restorePlanner) planTone() error {
+	want := p.backup.Tone
+	if want == nil {
+		return nil
+	}
+
+	tone, err := p.c.GetAudioProductToneControls()
+	if err != nil {
+		return err
+	}
+
+	p.planRange(models.BackupSectionTone, "treble", tone.Treble.Value, want.Treble, tone.Treble.MinValue, tone.Treble.MaxValue,
+		func() error { return p.c.SetAdvancedTreble(want.Treble) })
+	p.planRange(models.BackupSectionTone, "advanced bass", tone.Bass.Value, want.Bass, tone.Bass.MinValue, tone.Bass.MaxValue,
+		func() error { return p.c.SetAdvancedBass(want.Bass) })
+
+	return nil
+}
+
+func (p *restorePlanner) planLevels() error {
+	want := p.backup.Levels
+	if want == nil {
+		return nil
+	}
+
+	levels, err := p.c.GetAudioProductLevelControls()
+	if err != nil {
+		return err
+	}
+
+	frontCenter, rearSurround := levels.FrontCenterSpeakerLevel, levels.RearSurroundSpeakersLevel
+
+	p.planRange(models.BackupSectionLevels, "front center level", frontCenter.Value, want.FrontCenter, frontCenter.MinValue, frontCenter.MaxValue,
+		func() error { return p.c.SetFrontCenterSpeakerLevel(want.FrontCenter) })
+	p.planRange(models.BackupSectionLevels, "rear surround level", rearSurround.Value, want.RearSurround, rearSurround.MinValue, rearSurround.MaxValue,
+		func() error { return p.c.SetRearSurroundSpeakersLevel(want.RearSurround) })
+
+	return nil
+}
+
+func (p *restorePlanner) planDSP() error {
+	want := p.backup.DSP
+	if want == nil {
+		return nil
+	}
+
+	dsp, err := p.c.GetAudioDSPControls()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case want.AudioMode == dsp.AudioMode:
+		p.unchanged("audio mode")
+	case !dsp.IsAudioModeSupported(want.AudioMode):
+		p.skip(models.BackupSectionDSP, "audio mode", dsp.AudioMode, want.AudioMode, fmt.Sprintf("not supported by %s", p.info.Type))
+	default:
+		p.add(models.BackupSectionDSP, "audio mode", dsp.AudioMode, want.AudioMode, func() error { return p.c.SetAudioMode(want.AudioMode) })
+	}
+
+	if want.VideoSyncAudioDelay == dsp.VideoSyncAudioDelay {
+		p.unchanged("video sync audio delay")
+	} else {
+		p.add(models.BackupSectionDSP, "video sync audio delay", fmt.Sprint(dsp.VideoSyncAudioDelay), fmt.Sprint(want.VideoSyncAudioDelay),
+			func() error { return p.c.SetVideoSyncAudioDelay(want.VideoSyncAudioDelay) })
+	}
+
+	return nil
+}
+
+func (p *restorePlanner) planZone() error {
+	zone, err := p.c.GetZone()
+	if err != nil {
+		return err
+	}
+
+	deviceID := p.info.DeviceID
+	current := describeZone(zone.Master, zoneMemberIDs(zone.Master, zone.Members), deviceID)
+	want := p.backup.Zone
+
+	if want == nil {
+		switch {
+		case zone.Master == "":
+			p.unchanged("zone")
+		case zone.Master == deviceID:
+			p.add(models.BackupSectionZone, "zone", current, "standalone", p.c.DissolveZone)
+		default:
+			p.skip(models.BackupSectionZone, "zone", current, "standalone", "the device is a zone member; change the zone on its master")
+		}
+
+		return nil
+	}
+
+	if want.Master != p.backup.Device.DeviceID {
+		p.skip(models.BackupSectionZone, "zone", current, fmt.Sprintf("member of %s", want.Master),
+			"the device was a zone member; restore the backup of the zone master to rebuild the zone")
+
+		return nil
+	}
+
+	// The restored device takes the place of the backed up master, which the zone request lists implicitly
+	members := make(map[string]string)
+
+	for _, member := range want.Members {
+		if member.DeviceID != want.Master && member.DeviceID != deviceID {
+			members[member.DeviceID] = member.IP
+		}
+	}
+
+	wantIDs := make([]string, 0, len(members))
+	for id := range members {
+		wantIDs = append(wantIDs, id)
+	}
+
+	sort.Strings(wantIDs)
+
+	desired := describeZone(deviceID, wantIDs, deviceID)
+
+	switch {
+	case desired == current:
+		p.unchanged("zone")
+	case !p.supported("/setZone"):
+		p.skip(models.BackupSectionZone, "zone", current, desired, fmt.Sprintf("not supported by %s", p.info.Type))
+	default:
+		p.add(models.BackupSectionZone, "zone", current, desired, func() error { return p.c.CreateZoneWithIPs(deviceID, members) })
+	}
+
+	return nil
+}
+
+// zoneMemberIDs returns the sorted device IDs of the members other than the master
+func zoneMemberIDs(master string, members []models.Member) []string {
+	var ids []string
+
+	for _, member := range members {
+		if member.DeviceID != master {
+			ids = append(ids, member.DeviceID)
+		}
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
+
+// describeZone returns a short description of a zone from the point of view of the device
+func describeZone(master string, members []string, deviceID string) string {
+	switch {
+	case master == "", master == deviceID && len(members) == 0:
+		return "standalone"
+	case master != deviceID:
+		return fmt.Sprintf("member of %s", master)
+	default:
+		return fmt.Sprintf("master of %s", strings.Join(members, ", "))
+	}
+}
@@ -0,0 +1,250 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+// backupTestDevice serves fixed GET responses and records POST requests
+type backupTestDevice struct {
+	mu        sync.Mutex
+	responses map[string]string
+	posts     []string
+}
+
+func (d *backupTestDevice) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		body, _ := io.ReadAll(r.Body)
+
+		d.mu.Lock()
+		d.posts = append(d.posts, r.URL.Path+" "+string(body))
+		d.mu.Unlock()
+
+		_, _ = w.Write([]byte("<status>" + r.URL.Path + "</status>"))
+
+		return
+	}
+
+	response, ok := d.responses[r.URL.Path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+func supportedURLsXML(paths ...string) string {
+	var b strings.Builder
+
+	b.WriteString(`<supportedURLs deviceID="X">`)
+
+	for _, path := range paths {
+		b.WriteString(`<URL location="` + path + `"/>`)
+	}
+
+	b.WriteString(`</supportedURLs>`)
+
+	return b.String()
+}
+
+func TestClient_BackupAndPlanRestore(t *testing.T) {
+	source := &backupTestDevice{responses: map[string]string{
+		"/info": `<info deviceID="AAA"><name>Living Room</name><type>SoundTouch 300</type></info>`,
+		"/supportedURLs": supportedURLsXML("/info", "/name", "/language", "/sources", "/presets", "/clockDisplay", "/bass", "/bassCapabilities",
+			"/balance", "/audioproducttonecontrols", "/audioproductlevelcontrols", "/audiodspcontrols", "/getZone"),
+		"/capabilities": `<capabilities deviceID="AAA"><capability name="audiodspcontrols" url="/audiodspcontrols" />` +
+			`<capability name="audioproducttonecontrols" url="/audioproducttonecontrols" />` +
+			`<capability name="audioproductlevelcontrols" url="/audioproductlevelcontrols" /></capabilities>`,
+		"/language": `<sysLanguage>3</sysLanguage>`,
+		"/sources": `<sources deviceID="AAA">` +
+			`<sourceItem source="SPOTIFY" sourceAccount="user1" status="READY">user1</sourceItem>` +
+			`<sourceItem source="STORED_MUSIC" sourceAccount="nas-uuid/0" status="READY">My NAS</sourceItem>` +
+			`<sourceItem source="TUNEIN" status="READY" />` +
+			`<sourceItem source="AUX" sourceAccount="AUX" status="READY">AUX IN</sourceItem></sources>`,
+		"/presets": `<presets>` +
+			`<preset id="1"><ContentItem source="TUNEIN" type="stationurl" location="/v1/playback/station/s1" isPresetable="true"><itemName>Radio 1</itemName><containerArt>http://art/1.png</containerArt></ContentItem></preset>` +
+			`<preset id="2"><ContentItem source="SPOTIFY" type="tracklisturl" location="/playback/container/abc" sourceAccount="user1" isPresetable="true"><itemName>Jazz</itemName></ContentItem></preset>` +
+			`<preset id="3"><ContentItem source="STORED_MUSIC" location="1$4" sourceAccount="nas-uuid/0" isPresetable="true"><itemName>Album</itemName></ContentItem></preset>` +
+			`</presets>`,
+		"/clockDisplay":     `<clockDisplay deviceID="AAA" enabled="true" format="24" brightness="70" autoDim="true" />`,
+		"/bassCapabilities": `<bassCapabilities deviceID="AAA"><bassAvailable>true</bassAvailable><bassMin>-9</bassMin><bassMax>0</bassMax><bassDefault>0</bassDefault></bassCapabilities>`,
+		"/bass":             `<bass deviceID="AAA"><targetbass>-3</targetbass><actualbass>-3</actualbass></bass>`,
+		"/balance":          `<balance deviceID="AAA"><targetbalance>5</targetbalance><actualbalance>5</actualbalance></balance>`,
+		"/audioproducttonecontrols": `<audioproducttonecontrols><bass value="2" minValue="-10" maxValue="10" step="1" />` +
+			`<treble value="-1" minValue="-10" maxValue="10" step="1" /></audioproducttonecontrols>`,
+		"/audioproductlevelcontrols": `<audioproductlevelcontrols><frontCenterSpeakerLevel value="1" minValue="-5" maxValue="5" step="1" />` +
+			`<rearSurroundSpeakersLevel value="-2" minValue="-5" maxValue="5" step="1" /></audioproductlevelcontrols>`,
+		"/audiodspcontrols": `<audiodspcontrols audiomode="MUSIC" videosyncaudiodelay="40" supportedaudiomodes="NORMAL|DIALOG|MUSIC" />`,
+		"/getZone":          `<zone master="AAA"><member ipaddress="10.0.0.1">AAA</member><member ipaddress="10.0.0.3">CCC</member></zone>`,
+	}}
+
+	sourceServer := httptest.NewServer(source)
+	defer sourceServer.Close()
+
+	backup, err := createTestClient(sourceServer.URL).Backup(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(backup.Sections) != len(models.BackupSections) {
+		t.Errorf("Expected all sections, got %v", backup.Sections)
+	}
+
+	if backup.Device.Type != "SoundTouch 300" || backup.Name != "Living Room" || backup.Language != 3 {
+		t.Errorf("Unexpected device data: %+v, name %q, language %d", backup.Device, backup.Name, backup.Language)
+	}
+
+	if len(backup.Accounts) != 2 || len(backup.Presets) != 3 || backup.Presets[0].Artwork != "http://art/1.png" {
+		t.Errorf("Unexpected accounts %+v or presets %+v", backup.Accounts, backup.Presets)
+	}
+
+	if *backup.Bass != -3 || backup.DSP.AudioMode != "MUSIC" || backup.Tone.Treble != -1 || backup.Zone.Master != "AAA" {
+		t.Errorf("Unexpected audio or zone settings: %+v", backup)
+	}
+
+	// A smaller speaker without balance, tone, level and DSP controls
+	target := &backupTestDevice{responses: map[string]string{
+		"/info": `<info deviceID="BBB"><name>Kitchen</name><type>SoundTouch 10</type></info>`,
+		"/supportedURLs": supportedURLsXML("/info", "/name", "/language", "/sources", "/presets", "/storePreset", "/removePreset",
+			"/clockDisplay", "/bass", "/bassCapabilities", "/getZone", "/setZone", "/setMusicServiceAccount"),
+		"/language": `<sysLanguage>1</sysLanguage>`,
+		"/sources": `<sources deviceID="BBB"><sourceItem source="TUNEIN" status="READY" />` +
+			`<sourceItem source="AUX" sourceAccount="AUX" status="READY">AUX IN</sourceItem></sources>`,
+		"/presets": `<presets>` +
+			`<preset id="1"><ContentItem source="TUNEIN" type="stationurl" location="/v1/playback/station/s1" isPresetable="true"><itemName>Radio 1</itemName><containerArt>http://art/1.png</containerArt></ContentItem></preset>` +
+			`<preset id="4"><ContentItem source="AUX" sourceAccount="AUX" isPresetable="true"><itemName>AUX IN</itemName></ContentItem></preset>` +
+			`</presets>`,
+		"/clockDisplay":     `<clockDisplay deviceID="BBB" format="24" brightness="70" autoDim="true" />`,
+		"/bassCapabilities": `<bassCapabilities deviceID="BBB"><bassAvailable>true</bassAvailable><bassMin>-9</bassMin><bassMax>0</bassMax><bassDefault>0</bassDefault></bassCapabilities>`,
+		"/bass":             `<bass deviceID="BBB"><targetbass>0</targetbass><actualbass>0</actualbass></bass>`,
+		"/getZone":          `<zone />`,
+	}}
+
+	targetServer := httptest.NewServer(target)
+	defer targetServer.Close()
+
+	targetClient := createTestClient(targetServer.URL)
+
+	plan, err := targetClient.PlanRestore(backup, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var changes []string
+	for _, change := range plan.Changes {
+		changes = append(changes, change.String())
+	}
+
+	expected := []string{
+		"name: Kitchen → Living Room",
+		"language: Danish → English",
+		"account SPOTIFY user1: missing → configured (skipped: needs the account password, which is not part of a backup)",
+		"account STORED_MUSIC nas-uuid/0: missing → configured",
+		"preset 2: empty → Jazz (SPOTIFY) (skipped: account user1 of SPOTIFY is not configured)",
+		"preset 3: empty → Album (STORED_MUSIC)",
+		"preset 4: AUX IN (AUX) → empty",
+		"clock display: off, format 24, brightness 70, auto-dim true → on, format 24, brightness 70, auto-dim true",
+		"bass: 0 → -3",
+		"balance (skipped: not supported by SoundTouch 10)",
+		"tone (skipped: not supported by SoundTouch 10)",
+		"levels (skipped: not supported by SoundTouch 10)",
+		"dsp (skipped: not supported by SoundTouch 10)",
+		"zone: standalone → master of CCC",
+	}
+
+	if strings.Join(changes, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("Unexpected plan:\n%s\nwant:\n%s", strings.Join(changes, "\n"), strings.Join(expected, "\n"))
+	}
+
+	if len(plan.Pending()) != 8 {
+		t.Errorf("Expected 8 pending changes, got %d", len(plan.Pending()))
+	}
+
+	if err := plan.Apply(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	posts := strings.Join(target.posts, "\n")
+	for _, want := range []string{
+		"/name <name>Living Room</name>",
+		"/language <sysLanguage>3</sysLanguage>",
+		`/setMusicServiceAccount <credentials source="STORED_MUSIC" displayName="My NAS"><user>nas-uuid/0</user>`,
+		`/storePreset <preset id="3"`,
+		`/removePreset <preset id="4"`,
+		`/clockDisplay <clockDisplay enabled="true" format="24" brightness="70" autoDim="true">`,
+		"/bass <bass>-3</bass>",
+		`/setZone <zone master="BBB"`,
+		`<member ipaddress="10.0.0.3">CCC</member>`,
+	} {
+		if !strings.Contains(posts, want) {
+			t.Errorf("Expected a request containing %q, got:\n%s", want, posts)
+		}
+	}
+
+	if len(target.posts) != 8 {
+		t.Errorf("Expected 8 requests, got %d:\n%s", len(target.posts), posts)
+	}
+}
+
+func TestClient_PlanRestore_Ranges(t *testing.T) {
+	bass, balance := -7, 10
+	backup := &models.DeviceBackup{
+		Version:  models.DeviceBackupVersion,
+		Device:   models.BackupDevice{DeviceID: "AAA"},
+		Sections: []string{models.BackupSectionBass, models.BackupSectionBalance, models.BackupSectionDSP, models.BackupSectionZone},
+		Bass:     &bass,
+		Balance:  &balance,
+		DSP:      &models.BackupDSP{AudioMode: "MOVIE", VideoSyncAudioDelay: 0},
+		Zone:     &models.BackupZone{Master: "CCC", Members: []models.BackupZoneMember{{DeviceID: "CCC"}, {DeviceID: "AAA"}}},
+	}
+
+	device := &backupTestDevice{responses: map[string]string{
+		"/info":             `<info deviceID="AAA"><name>Den</name><type>Wave SoundTouch</type></info>`,
+		"/capabilities":     `<capabilities deviceID="AAA"><capability name="audiodspcontrols" url="/audiodspcontrols" /></capabilities>`,
+		"/bassCapabilities": `<bassCapabilities deviceID="AAA"><bassAvailable>true</bassAvailable><bassMin>-5</bassMin><bassMax>0</bassMax></bassCapabilities>`,
+		"/bass":             `<bass deviceID="AAA"><targetbass>0</targetbass><actualbass>0</actualbass></bass>`,
+		"/balance":          `<balance deviceID="AAA"><targetbalance>10</targetbalance><actualbalance>10</actualbalance></balance>`,
+		"/audiodspcontrols": `<audiodspcontrols audiomode="NORMAL" videosyncaudiodelay="0" supportedaudiomodes="NORMAL|DIALOG" />`,
+		"/getZone":          `<zone />`,
+	}}
+
+	server := httptest.NewServer(device)
+	defer server.Close()
+
+	plan, err := createTestClient(server.URL).PlanRestore(backup, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	skipped := map[string]string{}
+	for _, change := range plan.Changes {
+		skipped[change.Setting] = change.Skipped
+	}
+
+	if !strings.Contains(skipped["bass"], "outside the range -5..0") {
+		t.Errorf("Expected bass out of range to be skipped, got %q", skipped["bass"])
+	}
+
+	if !strings.Contains(skipped["audio mode"], "not supported") {
+		t.Errorf("Expected unsupported audio mode to be skipped, got %q", skipped["audio mode"])
+	}
+
+	if !strings.Contains(skipped["zone"], "zone member") {
+		t.Errorf("Expected zone of a member to be skipped, got %q", skipped["zone"])
+	}
+
+	if len(plan.Pending()) != 0 || len(plan.Unchanged) != 2 {
+		t.Errorf("Expected no pending and two unchanged settings, got %v and %v", plan.Pending(), plan.Unchanged)
+	}
+
+	if _, err := createTestClient(server.URL).PlanRestore(backup, []string{"volume"}); err == nil {
+		t.Error("Expected an error for an unknown section")
+	}
+}
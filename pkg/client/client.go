@@ -143,6 +143,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"fmt"
 	"io"
@@ -150,6 +151,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gesellix/bose-soundtouch/pkg/config"
 	"github.com/gesellix/bose-soundtouch/pkg/models"
 )
 
@@ -159,6 +161,7 @@ const defaultSoundTouchPort = 8090
 // Client represents a SoundTouch API client
 type Client struct {
 	baseURL    string
+	proxyURL   string
 	httpClient *http.Client
 	timeout    time.Duration
 	userAgent  string
@@ -170,6 +173,20 @@ type Config struct {
 	Port      int
 	Timeout   time.Duration
 	UserAgent string
+	// ProxyURL routes API requests through the /proxy endpoint of a soundtouch-service,
+	// e.g. http://service:8000/proxy
+	ProxyURL string
+}
+
+// StatusError is returned when the device answers a request with an HTTP status other than 200 OK
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+// Error implements the error interface
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Body)
 }
 
 // DefaultConfig returns a default client configuration
@@ -183,26 +200,27 @@ func DefaultConfig() *Config {
 }
 
 // NewClient creates a new SoundTouch API client
-func NewClient(config *Config) *Client {
-	if config.Timeout == 0 {
-		config.Timeout = 10 * time.Second
+func NewClient(cfg *Config) *Client {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
 	}
 
-	if config.UserAgent == "" {
-		config.UserAgent = "Bose-SoundTouch-Go-Client/1.0"
+	if cfg.UserAgent == "" {
+		cfg.UserAgent = "Bose-SoundTouch-Go-Client/1.0"
 	}
 
-	if config.Port == 0 {
-		config.Port = 8090
+	if cfg.Port == 0 {
+		cfg.Port = 8090
 	}
 
 	return &Client{
-		baseURL: fmt.Sprintf("http://%s:%d", config.Host, config.Port),
+		baseURL:  config.HostURL(cfg.Host, cfg.Port),
+		proxyURL: strings.TrimSuffix(cfg.ProxyURL, "/"),
 		httpClient: &http.Client{
-			Timeout: config.Timeout,
+			Timeout: cfg.Timeout,
 		},
-		timeout:   config.Timeout,
-		userAgent: config.UserAgent,
+		timeout:   cfg.Timeout,
+		userAgent: cfg.UserAgent,
 	}
 }
 
@@ -461,6 +479,37 @@ func (c *Client) SendKeyReleaseOnly(keyValue string) error {
 	return c.SendKeyRelease(keyValue)
 }
 
+// HoldKey presses a key, keeps it pressed for the given duration and releases it.
+// Holding PRESET_1 to PRESET_6 for about two seconds stores the current content in that preset.
+func (c *Client) HoldKey(keyValue string, duration time.Duration) error {
+	return c.HoldKeyContext(context.Background(), keyValue, duration)
+}
+
+// HoldKeyContext is like HoldKey, but releases the key early when ctx is cancelled
+// and then returns the context error.
+func (c *Client) HoldKeyContext(ctx context.Context, keyValue string, duration time.Duration) error {
+	if !models.IsValidKey(keyValue) {
+		return fmt.Errorf("invalid key value: %s", keyValue)
+	}
+
+	if duration < 0 {
+		return fmt.Errorf("invalid hold duration: %v", duration)
+	}
+
+	if err := c.post("/key", models.NewKey(keyValue)); err != nil {
+		return fmt.Errorf("failed to send key press: %w", err)
+	}
+
+	// The key is released in any case, so it does not stay pressed on the device
+	held := sleepContext(ctx, duration)
+
+	if err := c.post("/key", models.NewKeyRelease(keyValue)); err != nil {
+		return fmt.Errorf("failed to send key release: %w", err)
+	}
+
+	return held
+}
+
 // Play sends a PLAY key command
 func (c *Client) Play() error {
 	return c.SendKey(models.KeyPlay)
@@ -1002,6 +1051,32 @@ func (c *Client) SetClockDisplayFormat(format models.ClockFormat) error {
 	return c.SetClockDisplay(request)
 }
 
+// GetLanguage retrieves the language of the device from the /language endpoint
+func (c *Client) GetLanguage() (*models.SystemLanguage, error) {
+	var language models.SystemLanguage
+
+	err := c.get("/language", &language)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get language: %w", err)
+	}
+
+	return &language, nil
+}
+
+// SetLanguage sets the language of the device by its language code
+func (c *Client) SetLanguage(code int) error {
+	if !models.IsValidLanguageCode(code) {
+		return fmt.Errorf("invalid language code: %d", code)
+	}
+
+	err := c.post("/language", models.NewSystemLanguage(code))
+	if err != nil {
+		return fmt.Errorf("failed to set language: %w", err)
+	}
+
+	return nil
+}
+
 // GetNetworkInfo retrieves network information from the /networkInfo endpoint
 func (c *Client) GetNetworkInfo() (*models.NetworkInformation, error) {
 	var networkInfo models.NetworkInformation
@@ -1025,6 +1100,20 @@ func (c *Client) BaseURL() string {
 	return c.baseURL
 }
 
+// ProxyURL returns the soundtouch-service proxy used for API requests, or an empty string
+func (c *Client) ProxyURL() string {
+	return c.proxyURL
+}
+
+// endpointURL returns the URL of an API endpoint, routed through the proxy if one is set
+func (c *Client) endpointURL(endpoint string) string {
+	if c.proxyURL != "" {
+		return c.proxyURL + "/" + c.baseURL + endpoint
+	}
+
+	return c.baseURL + endpoint
+}
+
 // Host returns the host for this client
 func (c *Client) Host() string {
 	return c.baseURL
@@ -1032,7 +1121,7 @@ func (c *Client) Host() string {
 
 // get performs a GET request and unmarshals the XML response
 func (c *Client) get(endpoint string, result interface{}) error {
-	url := c.baseURL + endpoint
+	url := c.endpointURL(endpoint)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -1056,7 +1145,7 @@ func (c *Client) get(endpoint string, result interface{}) error {
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -1080,7 +1169,7 @@ func (c *Client) get(endpoint string, result interface{}) error {
 
 // post performs a POST request with XML body
 func (c *Client) post(endpoint string, payload interface{}) error {
-	url := c.baseURL + endpoint
+	url := c.endpointURL(endpoint)
 
 	var body io.Reader
 
@@ -1116,7 +1205,7 @@ func (c *Client) post(endpoint string, payload interface{}) error {
 
 	if resp.StatusCode != http.StatusOK {
 		responseBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(responseBody))
+		return &StatusError{StatusCode: resp.StatusCode, Body: string(responseBody)}
 	}
 
 	return nil
@@ -1124,7 +1213,7 @@ func (c *Client) post(endpoint string, payload interface{}) error {
 
 // postWithResponse performs a POST request with XML body and parses the response
 func (c *Client) postWithResponse(endpoint string, payload, result interface{}) error {
-	url := c.baseURL + endpoint
+	url := c.endpointURL(endpoint)
 
 	var body io.Reader
 
@@ -1160,7 +1249,7 @@ func (c *Client) postWithResponse(endpoint string, payload, result interface{})
 
 	if resp.StatusCode != http.StatusOK {
 		responseBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(responseBody))
+		return &StatusError{StatusCode: resp.StatusCode, Body: string(responseBody)}
 	}
 
 	if result != nil {
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// ClientPool hands out one cached API client per speaker address
+type ClientPool struct {
+	mu      sync.Mutex
+	timeout time.Duration
+	clients map[string]*Client
+}
+
+// NewClientPool creates a pool whose clients use the given request timeout
+func NewClientPool(timeout time.Duration) *ClientPool {
+	return &ClientPool{
+		timeout: timeout,
+		clients: make(map[string]*Client),
+	}
+}
+
+// Get returns the client for a speaker; port 0 selects the default API port 8090
+func (p *ClientPool) Get(host string, port int) *Client {
+	if port == 0 {
+		port = 8090
+	}
+
+	key := net.JoinHostPort(host, fmt.Sprint(port))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if c, ok := p.clients[key]; ok {
+		return c
+	}
+
+	config := DefaultConfig()
+	config.Host = host
+	config.Port = port
+	config.Timeout = p.timeout
+	c := NewClient(config)
+	p.clients[key] = c
+
+	return c
+}
+
+// ZoneMemberIP returns the host if it is an IP address, as zone requests only accept IPs
+func ZoneMemberIP(host string) string {
+	if net.ParseIP(host) != nil {
+		return host
+	}
+
+	return ""
+}
@@ -0,0 +1,31 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClientPool(t *testing.T) {
+	pool := NewClientPool(5 * time.Second)
+
+	first := pool.Get("192.168.1.10", 0)
+	if first != pool.Get("192.168.1.10", 8090) {
+		t.Error("Expected port 0 to share the client of the default port")
+	}
+
+	if first == pool.Get("192.168.1.11", 8090) {
+		t.Error("Expected a separate client per host")
+	}
+
+	if first.Host() != "http://192.168.1.10:8090" {
+		t.Errorf("Unexpected client address %s", first.Host())
+	}
+}
+
+func TestZoneMemberIP(t *testing.T) {
+	for host, want := range map[string]string{"192.168.1.10": "192.168.1.10", "fe80::1": "fe80::1", "kitchen.local": ""} {
+		if got := ZoneMemberIP(host); got != want {
+			t.Errorf("ZoneMemberIP(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
@@ -267,6 +267,60 @@ func TestBaseURL(t *testing.T) {
 	}
 }
 
+func TestBaseURL_IPv6(t *testing.T) {
+	tests := []struct {
+		host     string
+		port     int
+		expected string
+	}{
+		{"2001:db8::10", 8090, "http://[2001:db8::10]:8090"},
+		{"[2001:db8::10]", 8090, "http://[2001:db8::10]:8090"},
+		{"[2001:db8::10]:8091", 8090, "http://[2001:db8::10]:8091"},
+		{"fe80::1%eth0", 8090, "http://[fe80::1%25eth0]:8090"},
+	}
+
+	for _, tt := range tests {
+		client := NewClient(&Config{Host: tt.host, Port: tt.port})
+		if client.BaseURL() != tt.expected {
+			t.Errorf("Host %q: expected BaseURL '%s', got '%s'", tt.host, tt.expected, client.BaseURL())
+		}
+
+		if _, err := url.Parse(client.BaseURL()); err != nil {
+			t.Errorf("Host %q: BaseURL does not parse: %v", tt.host, err)
+		}
+	}
+}
+
+func TestProxyURL(t *testing.T) {
+	var paths []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+
+		_, _ = w.Write([]byte(`<volume deviceID="test"><targetvolume>20</targetvolume><actualvolume>20</actualvolume></volume>`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{Host: "192.168.1.100", ProxyURL: server.URL + "/proxy/"})
+
+	if _, err := client.GetVolume(); err != nil {
+		t.Fatalf("GetVolume() through proxy failed: %v", err)
+	}
+
+	if err := client.SetVolume(25); err != nil {
+		t.Fatalf("SetVolume() through proxy failed: %v", err)
+	}
+
+	expected := "GET /proxy/http://192.168.1.100:8090/volume,POST /proxy/http://192.168.1.100:8090/volume"
+	if got := strings.Join(paths, ","); got != expected {
+		t.Errorf("Expected requests %s, got %s", expected, got)
+	}
+
+	if client.BaseURL() != "http://192.168.1.100:8090" {
+		t.Errorf("Expected BaseURL to keep the device address, got %s", client.BaseURL())
+	}
+}
+
 func TestClientTimeout(t *testing.T) {
 	// Create a server that delays response
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
@@ -0,0 +1,45 @@
+package client
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+// CheckClock compares the clock of the device with the current time in loc
+func (c *Client) CheckClock(loc *time.Location) (*models.ClockCheck, error) {
+	clockTime, err := c.GetClockTime()
+	if err != nil {
+		return nil, err
+	}
+
+	check, err := models.NewClockCheck(clockTime, time.Now(), loc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read clock time: %w", err)
+	}
+
+	return check, nil
+}
+
+// SyncClock sets the clock of the device to the current time in loc if it drifted more than threshold
+// or applies a wrong UTC offset, e.g. after a DST transition. The returned check describes the clock
+// before the correction.
+func (c *Client) SyncClock(loc *time.Location, threshold time.Duration) (*models.ClockCheck, error) {
+	check, err := c.CheckClock(loc)
+	if err != nil {
+		return nil, err
+	}
+
+	if !check.NeedsCorrection(threshold) {
+		return check, nil
+	}
+
+	if err := c.SetClockTime(models.NewClockTimeRequest(time.Now().In(loc))); err != nil {
+		return check, err
+	}
+
+	check.Corrected = true
+
+	return check, nil
+}
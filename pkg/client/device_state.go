@@ -0,0 +1,425 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+// StateField identifies a part of the device state tracked by DeviceState
+type StateField string
+
+const (
+	// StateFieldInfo is the device information from /info
+	StateFieldInfo StateField = "info"
+	// StateFieldNowPlaying is the current playback status from /now_playing
+	StateFieldNowPlaying StateField = "nowPlaying"
+	// StateFieldVolume is the volume level from /volume
+	StateFieldVolume StateField = "volume"
+	// StateFieldPresets is the preset list from /presets
+	StateFieldPresets StateField = "presets"
+	// StateFieldZone is the multiroom zone from /getZone
+	StateFieldZone StateField = "zone"
+	// StateFieldBass is the bass level from /bass
+	StateFieldBass StateField = "bass"
+	// StateFieldName is the device name from /name
+	StateFieldName StateField = "name"
+	// StateFieldRecents is the recently played list from /recents
+	StateFieldRecents StateField = "recents"
+	// StateFieldSources is the source list from /sources
+	StateFieldSources StateField = "sources"
+)
+
+// AllStateFields lists every field tracked by DeviceState
+var AllStateFields = []StateField{
+	StateFieldInfo,
+	StateFieldNowPlaying,
+	StateFieldVolume,
+	StateFieldPresets,
+	StateFieldZone,
+	StateFieldBass,
+	StateFieldName,
+	StateFieldRecents,
+	StateFieldSources,
+}
+
+// StateChangeHandler is called after a field of the device state has been updated
+type StateChangeHandler func(field StateField, state *DeviceState)
+
+// DeviceState mirrors the state of a single device.
+// It loads everything once over REST and then applies WebSocket events incrementally,
+// so readers never have to poll the device. Values returned by the getters are shared
+// snapshots and must not be modified.
+type DeviceState struct {
+	client *Client
+	ws     *WebSocketClient
+
+	mu         sync.RWMutex
+	info       *models.DeviceInfo
+	nowPlaying *models.NowPlaying
+	volume     *models.Volume
+	presets    *models.Presets
+	zone       *models.ZoneInfo
+	bass       *models.Bass
+	name       string
+	recents    *models.RecentsResponse
+	sources    *models.Sources
+	updated    map[StateField]time.Time
+
+	handlersMu sync.RWMutex
+	handlers   []StateChangeHandler
+}
+
+// NewDeviceState creates an empty state mirror for the device behind the given client
+func NewDeviceState(c *Client) *DeviceState {
+	return &DeviceState{
+		client:  c,
+		updated: make(map[StateField]time.Time),
+	}
+}
+
+// Client returns the REST client used by this mirror
+func (s *DeviceState) Client() *Client {
+	return s.client
+}
+
+// OnChange registers a handler that is called whenever a field changes
+func (s *DeviceState) OnChange(handler StateChangeHandler) {
+	s.handlersMu.Lock()
+	defer s.handlersMu.Unlock()
+
+	s.handlers = append(s.handlers, handler)
+}
+
+// Start loads the full state and keeps it in sync over a new WebSocket connection.
+// Only the device info is required; fields the device does not support stay empty.
+func (s *DeviceState) Start(config *WebSocketConfig) error {
+	if err := s.Refresh(); err != nil && s.LastUpdated(StateFieldInfo).IsZero() {
+		return err
+	}
+
+	if config == nil {
+		config = DefaultWebSocketConfig()
+	}
+
+	ws := s.client.NewWebSocketClient(config)
+	s.Attach(ws)
+
+	if err := ws.ConnectWithConfig(config); err != nil {
+		return fmt.Errorf("failed to connect state mirror: %w", err)
+	}
+
+	s.mu.Lock()
+	s.ws = ws
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Stop closes the WebSocket connection opened by Start
+func (s *DeviceState) Stop() error {
+	s.mu.Lock()
+	ws := s.ws
+	s.ws = nil
+	s.mu.Unlock()
+
+	if ws == nil {
+		return nil
+	}
+
+	return ws.Disconnect()
+}
+
+// IsLive returns true if the state has been loaded and the WebSocket connection is up
+func (s *DeviceState) IsLive() bool {
+	s.mu.RLock()
+	ws := s.ws
+	loaded := len(s.updated) > 0
+	s.mu.RUnlock()
+
+	return loaded && ws != nil && ws.IsConnected()
+}
+
+// Attach installs event handlers on an existing WebSocket client.
+// This replaces handlers previously set for the same event types. After a reconnect
+// the full state is reloaded, since events sent while disconnected are lost.
+func (s *DeviceState) Attach(ws *WebSocketClient) {
+	ws.OnNowPlaying(func(event *models.NowPlayingUpdatedEvent) {
+		np := event.NowPlaying
+		s.set(StateFieldNowPlaying, func() { s.nowPlaying = &np })
+	})
+	ws.OnVolumeUpdated(func(event *models.VolumeUpdatedEvent) {
+		volume := event.Volume
+		s.set(StateFieldVolume, func() { s.volume = &volume })
+	})
+	ws.OnPresetUpdated(func(event *models.PresetUpdatedEvent) {
+		presets := event.Presets
+		s.set(StateFieldPresets, func() { s.presets = &presets })
+	})
+	ws.OnZoneUpdated(func(event *models.ZoneUpdatedEvent) {
+		zone := zoneInfoFromEvent(&event.Zone)
+		s.set(StateFieldZone, func() { s.zone = zone })
+	})
+	ws.OnBassUpdated(func(event *models.BassUpdatedEvent) {
+		bass := event.Bass
+		s.set(StateFieldBass, func() { s.bass = &bass })
+	})
+	ws.OnNameUpdated(func(event *models.NameUpdatedEvent) {
+		s.setName(event.Name.GetName())
+	})
+	ws.OnRecentsUpdated(func(event *models.RecentsUpdatedEvent) {
+		recents := recentsFromEvent(&event.Recents)
+		s.set(StateFieldRecents, func() { s.recents = recents })
+	})
+	ws.OnSourcesUpdated(func(_ *models.SourcesUpdatedEvent) {
+		// The event carries no payload, so the list has to be fetched again
+		_ = s.RefreshField(StateFieldSources)
+	})
+	ws.OnReconnect(func() {
+		_ = s.Refresh()
+	})
+}
+
+// Refresh reloads every field over REST.
+// Fields that fail to load keep their previous value; all errors are returned joined.
+func (s *DeviceState) Refresh() error {
+	var errs []error
+
+	for _, field := range AllStateFields {
+		if err := s.RefreshField(field); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// RefreshField reloads a single field over REST
+func (s *DeviceState) RefreshField(field StateField) error {
+	var (
+		apply func()
+		err   error
+	)
+
+	switch field {
+	case StateFieldInfo:
+		var info *models.DeviceInfo
+		if info, err = s.client.GetDeviceInfo(); err == nil {
+			apply = func() { s.info = info }
+		}
+	case StateFieldNowPlaying:
+		var np *models.NowPlaying
+		if np, err = s.client.GetNowPlaying(); err == nil {
+			apply = func() { s.nowPlaying = np }
+		}
+	case StateFieldVolume:
+		var volume *models.Volume
+		if volume, err = s.client.GetVolume(); err == nil {
+			apply = func() { s.volume = volume }
+		}
+	case StateFieldPresets:
+		var presets *models.Presets
+		if presets, err = s.client.GetPresets(); err == nil {
+			apply = func() { s.presets = presets }
+		}
+	case StateFieldZone:
+		var zone *models.ZoneInfo
+		if zone, err = s.client.GetZone(); err == nil {
+			apply = func() { s.zone = zone }
+		}
+	case StateFieldBass:
+		var bass *models.Bass
+		if bass, err = s.client.GetBass(); err == nil {
+			apply = func() { s.bass = bass }
+		}
+	case StateFieldName:
+		var name *models.Name
+		if name, err = s.client.GetName(); err == nil {
+			apply = func() { s.name = name.GetName() }
+		}
+	case StateFieldRecents:
+		var recents *models.RecentsResponse
+		if recents, err = s.client.GetRecents(); err == nil {
+			apply = func() { s.recents = recents }
+		}
+	case StateFieldSources:
+		var sources *models.Sources
+		if sources, err = s.client.GetSources(); err == nil {
+			apply = func() { s.sources = sources }
+		}
+	default:
+		return fmt.Errorf("unknown state field: %s", field)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to refresh %s: %w", field, err)
+	}
+
+	s.set(field, apply)
+
+	return nil
+}
+
+// set applies an update under the lock, records its time and notifies handlers
+func (s *DeviceState) set(field StateField, apply func()) {
+	s.mu.Lock()
+	apply()
+	s.updated[field] = time.Now()
+	s.mu.Unlock()
+
+	s.notify(field)
+}
+
+// setName updates the name, keeping the cached device info consistent
+func (s *DeviceState) setName(name string) {
+	s.set(StateFieldName, func() {
+		s.name = name
+
+		if s.info != nil {
+			info := *s.info
+			info.Name = name
+			s.info = &info
+		}
+	})
+}
+
+func (s *DeviceState) notify(field StateField) {
+	s.handlersMu.RLock()
+	handlers := make([]StateChangeHandler, len(s.handlers))
+	copy(handlers, s.handlers)
+	s.handlersMu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(field, s)
+	}
+}
+
+// DeviceInfo returns the cached device information
+func (s *DeviceState) DeviceInfo() *models.DeviceInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.info
+}
+
+// NowPlaying returns the cached playback status
+func (s *DeviceState) NowPlaying() *models.NowPlaying {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.nowPlaying
+}
+
+// Volume returns the cached volume
+func (s *DeviceState) Volume() *models.Volume {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.volume
+}
+
+// Presets returns the cached presets
+func (s *DeviceState) Presets() *models.Presets {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.presets
+}
+
+// Zone returns the cached zone configuration
+func (s *DeviceState) Zone() *models.ZoneInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.zone
+}
+
+// Bass returns the cached bass level
+func (s *DeviceState) Bass() *models.Bass {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.bass
+}
+
+// Name returns the cached device name
+func (s *DeviceState) Name() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.name
+}
+
+// Recents returns the cached recently played items
+func (s *DeviceState) Recents() *models.RecentsResponse {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.recents
+}
+
+// Sources returns the cached source list
+func (s *DeviceState) Sources() *models.Sources {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.sources
+}
+
+// LastUpdated returns when a field was last updated, or the zero time if it was never loaded
+func (s *DeviceState) LastUpdated(field StateField) time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.updated[field]
+}
+
+// Staleness reports how long ago each loaded field was last updated.
+// Fields that have never been loaded are not included.
+func (s *DeviceState) Staleness() map[StateField]time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	result := make(map[StateField]time.Duration, len(s.updated))
+
+	for field, at := range s.updated {
+		result[field] = now.Sub(at)
+	}
+
+	return result
+}
+
+// zoneInfoFromEvent converts a zoneUpdated payload into the /getZone model
+func zoneInfoFromEvent(zone *models.Zone) *models.ZoneInfo {
+	info := &models.ZoneInfo{Master: zone.Master}
+
+	for _, member := range zone.Members {
+		info.Members = append(info.Members, models.Member{
+			DeviceID: member.DeviceID,
+			IP:       member.IP,
+		})
+	}
+
+	return info
+}
+
+// recentsFromEvent converts a recentsUpdated payload into the /recents model
+func recentsFromEvent(recents *models.Recents) *models.RecentsResponse {
+	response := &models.RecentsResponse{}
+
+	for _, item := range recents.Items {
+		contentItem := item.ContentItem
+		response.Items = append(response.Items, models.RecentsResponseItem{
+			DeviceID:    item.DeviceID,
+			UTCTime:     item.CreatedOn,
+			ID:          item.ID,
+			ContentItem: &contentItem,
+		})
+	}
+
+	return response
+}
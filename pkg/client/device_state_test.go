@@ -0,0 +1,216 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func newDeviceStateTestServer(t *testing.T, sourcesHits *int32) *httptest.Server {
+	t.Helper()
+
+	responses := map[string]string{
+		"/info": `<info deviceID="ABCD1234EF56"><name>Living Room</name><type>SoundTouch 10</type></info>`,
+		"/now_playing": `<nowPlaying deviceID="ABCD1234EF56" source="TUNEIN">
+			<ContentItem source="TUNEIN" location="/v1/playback/station/s33828"><itemName>Radio</itemName></ContentItem>
+			<track>First Track</track><playStatus>PLAY_STATE</playStatus></nowPlaying>`,
+		"/volume":  `<volume deviceID="ABCD1234EF56"><targetvolume>20</targetvolume><actualvolume>20</actualvolume><muteenabled>false</muteenabled></volume>`,
+		"/presets": `<presets><preset id="1"><ContentItem source="TUNEIN" location="/v1/playback/station/s1"><itemName>One</itemName></ContentItem></preset></presets>`,
+		"/getZone": `<zone />`,
+		"/bass":    `<bass deviceID="ABCD1234EF56"><targetbass>0</targetbass><actualbass>0</actualbass></bass>`,
+		"/name":    `<name>Living Room</name>`,
+		"/recents": `<recents></recents>`,
+		"/sources": `<sources deviceID="ABCD1234EF56"><sourceItem source="AUX" status="READY">AUX IN</sourceItem></sources>`,
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/sources" {
+			atomic.AddInt32(sourcesHits, 1)
+		}
+
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+func TestDeviceState_Refresh(t *testing.T) {
+	var sourcesHits int32
+
+	server := newDeviceStateTestServer(t, &sourcesHits)
+	defer server.Close()
+
+	state := NewDeviceState(createTestClient(server.URL))
+
+	if err := state.Refresh(); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	if state.Name() != "Living Room" {
+		t.Errorf("Expected name 'Living Room', got '%s'", state.Name())
+	}
+
+	if state.DeviceInfo() == nil || state.DeviceInfo().DeviceID != "ABCD1234EF56" {
+		t.Errorf("Expected device info to be loaded, got %+v", state.DeviceInfo())
+	}
+
+	if state.Volume() == nil || state.Volume().ActualVolume != 20 {
+		t.Errorf("Expected volume 20, got %+v", state.Volume())
+	}
+
+	if state.NowPlaying() == nil || state.NowPlaying().Track != "First Track" {
+		t.Errorf("Expected track 'First Track', got %+v", state.NowPlaying())
+	}
+
+	if state.Presets() == nil || len(state.Presets().Preset) != 1 {
+		t.Errorf("Expected 1 preset, got %+v", state.Presets())
+	}
+
+	if state.Zone() == nil || !state.Zone().IsStandalone() {
+		t.Errorf("Expected standalone zone, got %+v", state.Zone())
+	}
+
+	if state.Sources() == nil || len(state.Sources().SourceItem) != 1 {
+		t.Errorf("Expected 1 source, got %+v", state.Sources())
+	}
+
+	staleness := state.Staleness()
+	if len(staleness) != len(AllStateFields) {
+		t.Errorf("Expected staleness for %d fields, got %d", len(AllStateFields), len(staleness))
+	}
+
+	if state.IsLive() {
+		t.Error("Expected state without WebSocket not to be live")
+	}
+}
+
+func TestDeviceState_RefreshPartialFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/name" {
+			_, _ = w.Write([]byte(`<name>Kitchen</name>`))
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	state := NewDeviceState(createTestClient(server.URL))
+
+	err := state.Refresh()
+	if err == nil {
+		t.Fatal("Expected error for failing endpoints")
+	}
+
+	if !strings.Contains(err.Error(), "failed to refresh volume") {
+		t.Errorf("Expected joined error to mention volume, got: %v", err)
+	}
+
+	if state.Name() != "Kitchen" {
+		t.Errorf("Expected name 'Kitchen', got '%s'", state.Name())
+	}
+
+	if !state.LastUpdated(StateFieldVolume).IsZero() {
+		t.Error("Expected volume to remain unloaded")
+	}
+
+	if _, ok := state.Staleness()[StateFieldVolume]; ok {
+		t.Error("Expected staleness report to omit unloaded fields")
+	}
+}
+
+func TestDeviceState_AppliesWebSocketEvents(t *testing.T) {
+	var sourcesHits int32
+
+	server := newDeviceStateTestServer(t, &sourcesHits)
+	defer server.Close()
+
+	c := createTestClient(server.URL)
+	state := NewDeviceState(c)
+
+	if err := state.Refresh(); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	var (
+		mu      sync.Mutex
+		changes []StateField
+	)
+
+	state.OnChange(func(field StateField, _ *DeviceState) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		changes = append(changes, field)
+	})
+
+	ws := c.NewWebSocketClient(&WebSocketConfig{Logger: &mockLogger{}})
+	state.Attach(ws)
+
+	ws.handleMessage([]byte(`<updates deviceID="ABCD1234EF56"><volumeUpdated><volume><targetvolume>35</targetvolume><actualvolume>35</actualvolume><muteenabled>true</muteenabled></volume></volumeUpdated></updates>`))
+
+	if state.Volume().ActualVolume != 35 || !state.Volume().MuteEnabled {
+		t.Errorf("Expected muted volume 35, got %+v", state.Volume())
+	}
+
+	ws.handleMessage([]byte(`<updates deviceID="ABCD1234EF56"><nameUpdated><name>Kitchen</name></nameUpdated></updates>`))
+
+	if state.Name() != "Kitchen" {
+		t.Errorf("Expected name 'Kitchen', got '%s'", state.Name())
+	}
+
+	if state.DeviceInfo().Name != "Kitchen" {
+		t.Errorf("Expected device info name to follow name update, got '%s'", state.DeviceInfo().Name)
+	}
+
+	ws.handleMessage([]byte(`<updates deviceID="ABCD1234EF56"><zoneUpdated><zone master="ABCD1234EF56"><member ipaddress="192.168.1.11">112233445566</member></zone></zoneUpdated></updates>`))
+
+	if !state.Zone().IsMaster("ABCD1234EF56") || len(state.Zone().Members) != 1 {
+		t.Errorf("Expected zone with master and one member, got %+v", state.Zone())
+	}
+
+	ws.handleMessage([]byte(`<updates deviceID="ABCD1234EF56"><recentsUpdated><recents><recent deviceID="ABCD1234EF56" createdOn="1700000000" id="42"><ContentItem source="TUNEIN" location="/v1/playback/station/s1"><itemName>One</itemName></ContentItem></recent></recents></recentsUpdated></updates>`))
+
+	recents := state.Recents()
+	if len(recents.Items) != 1 || recents.Items[0].ContentItem.ItemName != "One" || recents.Items[0].UTCTime != 1700000000 {
+		t.Errorf("Expected one converted recent item, got %+v", recents.Items)
+	}
+
+	hitsBefore := atomic.LoadInt32(&sourcesHits)
+
+	ws.handleMessage([]byte(`<updates deviceID="ABCD1234EF56"><sourcesUpdated /></updates>`))
+
+	if atomic.LoadInt32(&sourcesHits) != hitsBefore+1 {
+		t.Errorf("Expected sourcesUpdated to refetch /sources")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	expected := []StateField{StateFieldVolume, StateFieldName, StateFieldZone, StateFieldRecents, StateFieldSources}
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %d change notifications, got %v", len(expected), changes)
+	}
+
+	for i, field := range expected {
+		if changes[i] != field {
+			t.Errorf("Expected change %d to be %s, got %s", i, field, changes[i])
+		}
+	}
+}
+
+func TestDeviceState_RefreshFieldUnknown(t *testing.T) {
+	state := NewDeviceState(createTestClient("http://localhost"))
+
+	if err := state.RefreshField(StateField("bogus")); err == nil {
+		t.Error("Expected error for unknown field")
+	}
+}
@@ -0,0 +1,325 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+// GroupVolumeMember is a speaker taking part in a group volume.
+// A zero weight counts as 1.
+type GroupVolumeMember struct {
+	DeviceID string
+	Name     string
+	Client   *Client
+	Weight   float64
+}
+
+// MemberVolume is the volume of a single group member
+type MemberVolume struct {
+	DeviceID string `json:"deviceId"`
+	Name     string `json:"name,omitempty"`
+	Level    int    `json:"level"`
+	Muted    bool   `json:"muted"`
+}
+
+// GroupVolumeLevel is a snapshot of the group volume and its members
+type GroupVolumeLevel struct {
+	Level   int            `json:"level"`
+	Muted   bool           `json:"muted"`
+	Members []MemberVolume `json:"members"`
+}
+
+// GroupVolume controls the volumes of all speakers of a zone as one, like the group slider of the Bose app.
+// Each member keeps its own level; the group level is the weighted average of the member levels.
+type GroupVolume struct {
+	members []*GroupVolumeMember
+}
+
+// NewGroupVolume creates a group volume over the given members
+func NewGroupVolume(members ...GroupVolumeMember) *GroupVolume {
+	g := &GroupVolume{}
+
+	for _, member := range members {
+		if member.Weight <= 0 {
+			member.Weight = 1
+		}
+
+		g.members = append(g.members, &member)
+	}
+
+	return g
+}
+
+// GroupVolume returns a group volume for the zone this device belongs to.
+// A standalone device forms a group of its own.
+func (c *Client) GroupVolume() (*GroupVolume, error) {
+	zone, err := c.GetZone()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get zone: %w", err)
+	}
+
+	if zone.IsStandalone() {
+		return NewGroupVolume(GroupVolumeMember{Client: c}), nil
+	}
+
+	ips := make(map[string]string)
+	for _, member := range zone.Members {
+		ips[strings.ToUpper(member.DeviceID)] = member.IP
+	}
+
+	var members []GroupVolumeMember
+
+	for _, deviceID := range uniqueDeviceIDs(zone) {
+		ip := ips[strings.ToUpper(deviceID)]
+		if ip == "" {
+			return nil, fmt.Errorf("no IP address known for zone member %s", deviceID)
+		}
+
+		members = append(members, GroupVolumeMember{DeviceID: deviceID, Client: c.peer(ip)})
+	}
+
+	return NewGroupVolume(members...), nil
+}
+
+// GroupVolume returns a group volume for the zone the referenced speaker belongs to
+func (m *ZoneManager) GroupVolume(ref string) (*GroupVolume, error) {
+	zone, master, err := m.ZoneOf(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if zone.IsStandalone() {
+		return NewGroupVolume(GroupVolumeMember{DeviceID: master.DeviceID, Name: master.Name, Client: m.client(master)}), nil
+	}
+
+	var members []GroupVolumeMember
+
+	for _, deviceID := range uniqueDeviceIDs(zone) {
+		speaker := m.byDeviceID(deviceID)
+
+		if speaker == nil {
+			member, ok := zone.GetMemberByDeviceID(deviceID)
+			if !ok || member.IP == "" {
+				return nil, models.NewZoneError(models.ZoneOpModify, deviceID, models.ZoneErrorDeviceNotFound)
+			}
+
+			speaker = &ZoneSpeaker{DeviceID: deviceID, Host: member.IP, Port: 8090}
+		}
+
+		members = append(members, GroupVolumeMember{DeviceID: deviceID, Name: speaker.Name, Client: m.client(speaker)})
+	}
+
+	return NewGroupVolume(members...), nil
+}
+
+// Members returns the group members
+func (g *GroupVolume) Members() []GroupVolumeMember {
+	members := make([]GroupVolumeMember, 0, len(g.members))
+	for _, member := range g.members {
+		members = append(members, *member)
+	}
+
+	return members
+}
+
+// SetWeight sets how much a member counts towards the group level
+func (g *GroupVolume) SetWeight(deviceID string, weight float64) error {
+	if weight <= 0 {
+		return fmt.Errorf("weight must be positive, got %v", weight)
+	}
+
+	for _, member := range g.members {
+		if strings.EqualFold(member.DeviceID, deviceID) {
+			member.Weight = weight
+			return nil
+		}
+	}
+
+	return fmt.Errorf("device %s is not a group member", deviceID)
+}
+
+// Get reads the volume of all members and returns the weighted average as group level.
+// The group counts as muted when every member is muted.
+func (g *GroupVolume) Get() (*GroupVolumeLevel, error) {
+	if len(g.members) == 0 {
+		return nil, fmt.Errorf("group has no members")
+	}
+
+	level := &GroupVolumeLevel{Muted: true}
+
+	var sum, weights float64
+
+	for _, member := range g.members {
+		volume, err := member.Client.GetVolume()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get volume of %s: %w", member.label(), err)
+		}
+
+		if member.DeviceID == "" {
+			member.DeviceID = volume.DeviceID
+		}
+
+		level.Members = append(level.Members, MemberVolume{
+			DeviceID: member.DeviceID,
+			Name:     member.Name,
+			Level:    volume.ActualVolume,
+			Muted:    volume.MuteEnabled,
+		})
+
+		level.Muted = level.Muted && volume.MuteEnabled
+		sum += member.Weight * float64(volume.ActualVolume)
+		weights += member.Weight
+	}
+
+	level.Level = int(math.Round(sum / weights))
+
+	return level, nil
+}
+
+// Set changes the group level by scaling every member proportionally.
+// If all members are at zero, every member is set to the requested level.
+func (g *GroupVolume) Set(level int) (*GroupVolumeLevel, error) {
+	if !models.ValidateVolumeLevel(level) {
+		return nil, fmt.Errorf("invalid volume level: %d (must be 0-100)", level)
+	}
+
+	current, err := g.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]int, len(g.members))
+
+	for i, member := range current.Members {
+		if current.Level == 0 {
+			targets[i] = level
+			continue
+		}
+
+		scaled := float64(member.Level) * float64(level) / float64(current.Level)
+		targets[i] = models.ClampVolumeLevel(int(math.Round(scaled)))
+	}
+
+	return g.apply(current, targets)
+}
+
+// Change moves every member by the same amount, keeping the offsets between members.
+// The amount is limited so that no member would have to leave the 0-100 range.
+func (g *GroupVolume) Change(delta int) (*GroupVolumeLevel, error) {
+	current, err := g.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	lowest, highest := 100, 0
+
+	for _, member := range current.Members {
+		lowest = min(lowest, member.Level)
+		highest = max(highest, member.Level)
+	}
+
+	delta = max(-lowest, min(delta, 100-highest))
+
+	targets := make([]int, len(g.members))
+	for i, member := range current.Members {
+		targets[i] = member.Level + delta
+	}
+
+	return g.apply(current, targets)
+}
+
+// SetMuted mutes or unmutes every member of the group
+func (g *GroupVolume) SetMuted(muted bool) (*GroupVolumeLevel, error) {
+	current, err := g.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+
+	for i, member := range g.members {
+		if current.Members[i].Muted == muted {
+			continue
+		}
+
+		if err := member.Client.SendKey(models.KeyMute); err != nil {
+			errs = append(errs, fmt.Errorf("failed to toggle mute on %s: %w", member.label(), err))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return g.Get()
+}
+
+// apply sets the target level on every member whose level differs
+func (g *GroupVolume) apply(current *GroupVolumeLevel, targets []int) (*GroupVolumeLevel, error) {
+	var errs []error
+
+	for i, member := range g.members {
+		if current.Members[i].Level == targets[i] {
+			continue
+		}
+
+		if err := member.Client.SetVolume(targets[i]); err != nil {
+			errs = append(errs, fmt.Errorf("failed to set volume of %s: %w", member.label(), err))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return g.Get()
+}
+
+// label names a member in error messages
+func (m *GroupVolumeMember) label() string {
+	if m.Name != "" {
+		return m.Name
+	}
+
+	if m.DeviceID != "" {
+		return m.DeviceID
+	}
+
+	return m.Client.baseURL
+}
+
+// peer returns a client for another device using the same port and settings as this client
+func (c *Client) peer(host string) *Client {
+	config := &Config{Host: host, Timeout: c.timeout, UserAgent: c.userAgent, ProxyURL: c.proxyURL}
+
+	if u, err := url.Parse(c.baseURL); err == nil {
+		config.Port, _ = strconv.Atoi(u.Port())
+	}
+
+	return NewClient(config)
+}
+
+// uniqueDeviceIDs returns the master followed by the other zone devices, without duplicates
+func uniqueDeviceIDs(zone *models.ZoneInfo) []string {
+	seen := make(map[string]bool)
+
+	var devices []string
+
+	for _, deviceID := range zone.GetAllDeviceIDs() {
+		key := strings.ToUpper(deviceID)
+		if deviceID == "" || seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		devices = append(devices, deviceID)
+	}
+
+	return devices
+}
@@ -0,0 +1,164 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+func newGroupVolumeFixture(t *testing.T, levels ...int) (*fakeZoneNetwork, *GroupVolume) {
+	t.Helper()
+
+	names := []string{"Kitchen", "Living Room", "Office"}[:len(levels)]
+
+	network, manager := newFakeZoneNetwork(t, names...)
+	network.byName("Kitchen").content = &models.ContentItem{Source: "AUX", ItemName: "AUX IN"}
+
+	for i, name := range names {
+		network.byName(name).volume = levels[i]
+	}
+
+	if len(names) > 1 {
+		if _, err := manager.CreateZone(names[0], names[1:]...); err != nil {
+			t.Fatalf("CreateZone failed: %v", err)
+		}
+	}
+
+	// Any member, not only the master, gives access to the whole group
+	group, err := manager.GroupVolume(names[len(names)-1])
+	if err != nil {
+		t.Fatalf("GroupVolume failed: %v", err)
+	}
+
+	return network, group
+}
+
+func memberLevels(level *GroupVolumeLevel) map[string]int {
+	levels := make(map[string]int)
+	for _, member := range level.Members {
+		levels[member.Name] = member.Level
+	}
+
+	return levels
+}
+
+func TestGroupVolume_GetWeightedAverage(t *testing.T) {
+	_, group := newGroupVolumeFixture(t, 20, 40, 60)
+
+	if len(group.Members()) != 3 {
+		t.Fatalf("Expected 3 members, got %d", len(group.Members()))
+	}
+
+	level, err := group.Get()
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+
+	if level.Level != 40 {
+		t.Errorf("Expected average 40, got %d", level.Level)
+	}
+
+	if err := group.SetWeight("DEVICE3", 2); err != nil {
+		t.Fatalf("SetWeight failed: %v", err)
+	}
+
+	level, _ = group.Get()
+	if level.Level != 45 {
+		t.Errorf("Expected weighted average 45, got %d", level.Level)
+	}
+
+	if err := group.SetWeight("UNKNOWN", 2); err == nil {
+		t.Error("Expected error for unknown member")
+	}
+}
+
+func TestGroupVolume_SetScalesProportionally(t *testing.T) {
+	_, group := newGroupVolumeFixture(t, 20, 40, 60)
+
+	level, err := group.Set(20)
+	if err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	levels := memberLevels(level)
+	if levels["Kitchen"] != 10 || levels["Living Room"] != 20 || levels["Office"] != 30 || level.Level != 20 {
+		t.Errorf("Unexpected levels after scaling: %v (group %d)", levels, level.Level)
+	}
+
+	if _, err := group.Set(101); err == nil {
+		t.Error("Expected error for invalid level")
+	}
+}
+
+func TestGroupVolume_SetFromSilence(t *testing.T) {
+	_, group := newGroupVolumeFixture(t, 0, 0)
+
+	level, err := group.Set(25)
+	if err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	for _, member := range level.Members {
+		if member.Level != 25 {
+			t.Errorf("Expected %s at 25, got %d", member.Name, member.Level)
+		}
+	}
+}
+
+func TestGroupVolume_ChangeKeepsOffsets(t *testing.T) {
+	_, group := newGroupVolumeFixture(t, 20, 40, 90)
+
+	level, err := group.Change(5)
+	if err != nil {
+		t.Fatalf("Change failed: %v", err)
+	}
+
+	levels := memberLevels(level)
+	if levels["Kitchen"] != 25 || levels["Living Room"] != 45 || levels["Office"] != 95 {
+		t.Errorf("Unexpected levels after change: %v", levels)
+	}
+
+	// The loudest member limits the increase so offsets are kept
+	level, _ = group.Change(20)
+
+	levels = memberLevels(level)
+	if levels["Kitchen"] != 30 || levels["Office"] != 100 {
+		t.Errorf("Expected change to be limited to 5, got %v", levels)
+	}
+}
+
+func TestGroupVolume_Mute(t *testing.T) {
+	network, group := newGroupVolumeFixture(t, 20, 40)
+	network.byName("Living Room").muted = true
+
+	level, err := group.SetMuted(true)
+	if err != nil {
+		t.Fatalf("SetMuted failed: %v", err)
+	}
+
+	if !level.Muted || !network.byName("Kitchen").muted || !network.byName("Living Room").muted {
+		t.Errorf("Expected all members muted, got %+v", level)
+	}
+
+	level, _ = group.SetMuted(false)
+	if level.Muted || network.byName("Kitchen").muted || network.byName("Living Room").muted {
+		t.Errorf("Expected all members unmuted, got %+v", level)
+	}
+}
+
+func TestClient_GroupVolumeStandalone(t *testing.T) {
+	network, manager := newFakeZoneNetwork(t, "Kitchen")
+	network.byName("Kitchen").volume = 30
+
+	speaker, _ := manager.Resolve("Kitchen")
+
+	group, err := manager.client(speaker).GroupVolume()
+	if err != nil {
+		t.Fatalf("GroupVolume failed: %v", err)
+	}
+
+	level, err := group.Get()
+	if err != nil || level.Level != 30 || len(level.Members) != 1 || level.Members[0].DeviceID != "DEVICE1" {
+		t.Errorf("Unexpected standalone group volume: %+v, %v", level, err)
+	}
+}
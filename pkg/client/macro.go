@@ -0,0 +1,397 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+// MacroAction identifies what a macro step does
+type MacroAction string
+
+const (
+	// MacroKey sends a key press and release, optionally repeated
+	MacroKey MacroAction = "key"
+	// MacroHold presses a key and releases it after a duration
+	MacroHold MacroAction = "hold"
+	// MacroWait pauses for a duration
+	MacroWait MacroAction = "wait"
+	// MacroWaitFor waits for a WebSocket event sent after the previous key step
+	MacroWaitFor MacroAction = "wait-for"
+)
+
+const (
+	defaultMacroWaitTimeout = 10 * time.Second
+	defaultMacroRepeatDelay = 200 * time.Millisecond
+)
+
+// macroEventAliases maps short event names to WebSocket event types
+var macroEventAliases = map[string]models.WebSocketEventType{
+	"source":     models.EventTypeNowPlaying,
+	"nowplaying": models.EventTypeNowPlaying,
+	"volume":     models.EventTypeVolumeUpdated,
+	"presets":    models.EventTypePresetUpdated,
+	"zone":       models.EventTypeZoneUpdated,
+	"bass":       models.EventTypeBassUpdated,
+	"name":       models.EventTypeNameUpdated,
+	"recents":    models.EventTypeRecentsUpdated,
+	"sources":    models.EventTypeSourcesUpdated,
+}
+
+// MacroStep is a single instruction of a key macro
+type MacroStep struct {
+	Action   MacroAction
+	Key      string
+	Repeat   int
+	Duration time.Duration
+	Event    models.WebSocketEventType
+	Line     int
+}
+
+// String returns the step in macro file syntax
+func (s MacroStep) String() string {
+	switch s.Action {
+	case MacroKey:
+		if s.Repeat > 1 {
+			return fmt.Sprintf("key %s x%d", s.Key, s.Repeat)
+		}
+
+		return fmt.Sprintf("key %s", s.Key)
+	case MacroHold:
+		return fmt.Sprintf("hold %s %v", s.Key, s.Duration)
+	case MacroWait:
+		return fmt.Sprintf("wait %v", s.Duration)
+	case MacroWaitFor:
+		return fmt.Sprintf("wait-for %s %v", string(s.Event), s.Duration)
+	default:
+		return string(s.Action)
+	}
+}
+
+// Macro is a named sequence of timed key steps
+type Macro struct {
+	Name  string
+	Steps []MacroStep
+}
+
+// ParseMacro reads a macro file. Each line holds one step; empty lines and lines starting with # are ignored.
+//
+//	name Morning radio
+//	key POWER
+//	wait-for source 10s
+//	key PRESET_3
+//	key VOLUME_UP x5
+//	hold PRESET_1 2s
+//	wait 500ms
+func ParseMacro(r io.Reader) (*Macro, error) {
+	macro := &Macro{}
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+
+		if strings.EqualFold(fields[0], "name") {
+			macro.Name = strings.TrimSpace(line[len(fields[0]):])
+			continue
+		}
+
+		step, err := parseMacroStep(fields)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		step.Line = lineNumber
+		macro.Steps = append(macro.Steps, step)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read macro: %w", err)
+	}
+
+	if len(macro.Steps) == 0 {
+		return nil, fmt.Errorf("macro has no steps")
+	}
+
+	return macro, nil
+}
+
+// parseMacroStep parses the fields of a single step line
+func parseMacroStep(fields []string) (MacroStep, error) {
+	action := MacroAction(strings.ToLower(fields[0]))
+	args := fields[1:]
+
+	switch action {
+	case MacroKey:
+		if len(args) < 1 || len(args) > 2 {
+			return MacroStep{}, fmt.Errorf("usage: key <KEY> [xN]")
+		}
+
+		step := MacroStep{Action: MacroKey, Key: strings.ToUpper(args[0]), Repeat: 1}
+
+		if len(args) == 2 {
+			repeat, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(args[1]), "x"))
+			if err != nil || repeat < 1 {
+				return MacroStep{}, fmt.Errorf("invalid repeat count: %s", args[1])
+			}
+
+			step.Repeat = repeat
+		}
+
+		if !models.IsValidKey(step.Key) {
+			return MacroStep{}, fmt.Errorf("invalid key value: %s", step.Key)
+		}
+
+		return step, nil
+	case MacroHold:
+		if len(args) != 2 {
+			return MacroStep{}, fmt.Errorf("usage: hold <KEY> <duration>")
+		}
+
+		key := strings.ToUpper(args[0])
+		if !models.IsValidKey(key) {
+			return MacroStep{}, fmt.Errorf("invalid key value: %s", key)
+		}
+
+		duration, err := parseMacroDuration(args[1])
+		if err != nil {
+			return MacroStep{}, err
+		}
+
+		return MacroStep{Action: MacroHold, Key: key, Duration: duration}, nil
+	case MacroWait:
+		if len(args) != 1 {
+			return MacroStep{}, fmt.Errorf("usage: wait <duration>")
+		}
+
+		duration, err := parseMacroDuration(args[0])
+		if err != nil {
+			return MacroStep{}, err
+		}
+
+		return MacroStep{Action: MacroWait, Duration: duration}, nil
+	case MacroWaitFor:
+		if len(args) < 1 || len(args) > 2 {
+			return MacroStep{}, fmt.Errorf("usage: wait-for <event> [timeout]")
+		}
+
+		eventType, err := parseMacroEvent(args[0])
+		if err != nil {
+			return MacroStep{}, err
+		}
+
+		step := MacroStep{Action: MacroWaitFor, Event: eventType, Duration: defaultMacroWaitTimeout}
+
+		if len(args) == 2 {
+			if step.Duration, err = parseMacroDuration(args[1]); err != nil {
+				return MacroStep{}, err
+			}
+		}
+
+		return step, nil
+	default:
+		return MacroStep{}, fmt.Errorf("unknown step: %s", fields[0])
+	}
+}
+
+func parseMacroDuration(value string) (time.Duration, error) {
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid duration: %s", value)
+	}
+
+	return duration, nil
+}
+
+func parseMacroEvent(name string) (models.WebSocketEventType, error) {
+	if eventType, ok := macroEventAliases[strings.ToLower(name)]; ok {
+		return eventType, nil
+	}
+
+	for _, eventType := range macroEventAliases {
+		if string(eventType) == name {
+			return eventType, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown event: %s", name)
+}
+
+// MacroRunner executes macros against a device
+type MacroRunner struct {
+	client      *Client
+	ws          *WebSocketClient
+	repeatDelay time.Duration
+	onStep      func(index int, step MacroStep)
+
+	mu       sync.Mutex
+	received []*models.WebSocketEvent
+	notify   chan struct{}
+}
+
+// NewMacroRunner creates a runner sending keys through the given client
+func NewMacroRunner(c *Client) *MacroRunner {
+	return &MacroRunner{
+		client:      c,
+		repeatDelay: defaultMacroRepeatDelay,
+		notify:      make(chan struct{}, 1),
+	}
+}
+
+// SetWebSocket provides the connection used by wait-for steps
+func (r *MacroRunner) SetWebSocket(ws *WebSocketClient) {
+	r.ws = ws
+}
+
+// SetRepeatDelay sets the pause between repeated key presses
+func (r *MacroRunner) SetRepeatDelay(delay time.Duration) {
+	r.repeatDelay = delay
+}
+
+// OnStep registers a handler called before each step is executed
+func (r *MacroRunner) OnStep(handler func(index int, step MacroStep)) {
+	r.onStep = handler
+}
+
+// Run executes all steps of the macro in order and stops at the first failing step
+func (r *MacroRunner) Run(ctx context.Context, macro *Macro) error {
+	if macro == nil {
+		return fmt.Errorf("macro is nil")
+	}
+
+	for _, step := range macro.Steps {
+		if step.Action == MacroWaitFor && r.ws == nil {
+			return fmt.Errorf("line %d: wait-for requires a WebSocket connection", step.Line)
+		}
+	}
+
+	if r.ws != nil {
+		stop := r.ws.ObserveEvents(r.recordEvent)
+		defer stop()
+	}
+
+	for i, step := range macro.Steps {
+		if r.onStep != nil {
+			r.onStep(i, step)
+		}
+
+		if err := r.runStep(ctx, step); err != nil {
+			return fmt.Errorf("step %d (%s): %w", i+1, step, err)
+		}
+	}
+
+	return nil
+}
+
+// runStep executes a single step
+func (r *MacroRunner) runStep(ctx context.Context, step MacroStep) error {
+	switch step.Action {
+	case MacroKey:
+		r.clearEvents()
+
+		for i := 0; i < step.Repeat; i++ {
+			if i > 0 {
+				if err := sleepContext(ctx, r.repeatDelay); err != nil {
+					return err
+				}
+			}
+
+			if err := r.client.SendKey(step.Key); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case MacroHold:
+		r.clearEvents()
+
+		return r.client.HoldKeyContext(ctx, step.Key, step.Duration)
+	case MacroWait:
+		return sleepContext(ctx, step.Duration)
+	case MacroWaitFor:
+		return r.waitForEvent(ctx, step.Event, step.Duration)
+	default:
+		return fmt.Errorf("unknown step: %s", step.Action)
+	}
+}
+
+// recordEvent keeps events so that wait-for also sees events that arrived before it started
+func (r *MacroRunner) recordEvent(event *models.WebSocketEvent) {
+	r.mu.Lock()
+	r.received = append(r.received, event)
+	r.mu.Unlock()
+
+	select {
+	case r.notify <- struct{}{}:
+	default:
+	}
+}
+
+// clearEvents forgets events received before a key is sent
+func (r *MacroRunner) clearEvents() {
+	r.mu.Lock()
+	r.received = nil
+	r.mu.Unlock()
+}
+
+// takeEvent removes and reports the first recorded event of the given type
+func (r *MacroRunner) takeEvent(eventType models.WebSocketEventType) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, event := range r.received {
+		if event.HasEventType(eventType) {
+			r.received = r.received[i+1:]
+			return true
+		}
+	}
+
+	return false
+}
+
+// waitForEvent blocks until an event of the given type was received after the last key step
+func (r *MacroRunner) waitForEvent(ctx context.Context, eventType models.WebSocketEventType, timeout time.Duration) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		if r.takeEvent(eventType) {
+			return nil
+		}
+
+		select {
+		case <-r.notify:
+		case <-timer.C:
+			return fmt.Errorf("timeout waiting for %s after %v", eventType, timeout)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// sleepContext sleeps for the duration or until the context is cancelled
+func sleepContext(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,234 @@
+package client
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+// keyRecorder is a fake device recording the key requests it receives
+type keyRecorder struct {
+	mu    sync.Mutex
+	keys  []string
+	times []time.Time
+}
+
+func (k *keyRecorder) handler(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	var key models.Key
+	_ = xml.Unmarshal(body, &key)
+
+	k.mu.Lock()
+	k.keys = append(k.keys, key.State+":"+key.Value)
+	k.times = append(k.times, time.Now())
+	k.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/xml")
+	_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8" ?><status>/key</status>`))
+}
+
+func (k *keyRecorder) recorded() []string {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	return append([]string(nil), k.keys...)
+}
+
+const testNowPlayingEvent = `<updates deviceID="689E19B8BB8A"><nowPlayingUpdated deviceID="689E19B8BB8A"><nowPlaying deviceID="689E19B8BB8A" source="TUNEIN"><playStatus>PLAY_STATE</playStatus></nowPlaying></nowPlayingUpdated></updates>`
+
+func TestClient_HoldKey(t *testing.T) {
+	recorder := &keyRecorder{}
+
+	server := httptest.NewServer(http.HandlerFunc(recorder.handler))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+
+	if err := client.HoldKey(models.KeyPreset1, 50*time.Millisecond); err != nil {
+		t.Fatalf("HoldKey failed: %v", err)
+	}
+
+	keys := recorder.recorded()
+	if strings.Join(keys, ",") != "press:PRESET_1,release:PRESET_1" {
+		t.Fatalf("Unexpected key sequence: %v", keys)
+	}
+
+	if held := recorder.times[1].Sub(recorder.times[0]); held < 50*time.Millisecond {
+		t.Errorf("Expected key to be held for at least 50ms, got %v", held)
+	}
+
+	if err := client.HoldKey("NOT_A_KEY", time.Millisecond); err == nil {
+		t.Error("Expected error for invalid key")
+	}
+}
+
+func TestClient_HoldKeyContext_Cancelled(t *testing.T) {
+	recorder := &keyRecorder{}
+
+	server := httptest.NewServer(http.HandlerFunc(recorder.handler))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+
+	if err := client.HoldKeyContext(ctx, models.KeyPreset1, time.Minute); err != context.DeadlineExceeded {
+		t.Fatalf("Expected the hold to end with the context, got %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the hold to end early, took %v", elapsed)
+	}
+
+	if keys := recorder.recorded(); strings.Join(keys, ",") != "press:PRESET_1,release:PRESET_1" {
+		t.Errorf("Expected the key to be released when cancelled, got %v", keys)
+	}
+}
+
+func TestParseMacro(t *testing.T) {
+	macro, err := ParseMacro(strings.NewReader(`
+# Morning radio
+name Morning radio
+key power
+wait-for source 5s
+key PRESET_3
+key VOLUME_UP x5
+hold PRESET_1 2s
+wait 500ms
+`))
+	if err != nil {
+		t.Fatalf("ParseMacro failed: %v", err)
+	}
+
+	if macro.Name != "Morning radio" {
+		t.Errorf("Expected name 'Morning radio', got '%s'", macro.Name)
+	}
+
+	expected := []string{"key POWER", "wait-for nowPlayingUpdated 5s", "key PRESET_3", "key VOLUME_UP x5", "hold PRESET_1 2s", "wait 500ms"}
+	if len(macro.Steps) != len(expected) {
+		t.Fatalf("Expected %d steps, got %d", len(expected), len(macro.Steps))
+	}
+
+	for i, step := range macro.Steps {
+		if step.String() != expected[i] {
+			t.Errorf("Step %d: expected '%s', got '%s'", i, expected[i], step.String())
+		}
+	}
+
+	if macro.Steps[1].Line != 5 {
+		t.Errorf("Expected line number 5, got %d", macro.Steps[1].Line)
+	}
+}
+
+func TestParseMacro_Errors(t *testing.T) {
+	tests := map[string]string{
+		"empty":         "# nothing\n",
+		"unknown step":  "jump POWER",
+		"invalid key":   "key LAUNCH",
+		"bad repeat":    "key VOLUME_UP x0",
+		"bad duration":  "hold PRESET_1 soon",
+		"unknown event": "wait-for sunrise",
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseMacro(strings.NewReader(input)); err == nil {
+				t.Errorf("Expected error for %q", input)
+			}
+		})
+	}
+}
+
+func TestMacroRunner_Run(t *testing.T) {
+	recorder := &keyRecorder{}
+
+	var ws *WebSocketClient
+
+	// The device reports the source change shortly after POWER
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder.handler(w, r)
+
+		if keys := recorder.recorded(); keys[len(keys)-1] == "release:POWER" {
+			go ws.handleMessage([]byte(testNowPlayingEvent))
+		}
+	}))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ws = client.NewWebSocketClient(&WebSocketConfig{Logger: &mockLogger{}})
+
+	macro, err := ParseMacro(strings.NewReader("key POWER\nwait-for source 2s\nkey VOLUME_UP x3\n"))
+	if err != nil {
+		t.Fatalf("ParseMacro failed: %v", err)
+	}
+
+	runner := NewMacroRunner(client)
+	runner.SetWebSocket(ws)
+	runner.SetRepeatDelay(time.Millisecond)
+
+	var steps []int
+
+	runner.OnStep(func(index int, _ MacroStep) {
+		steps = append(steps, index)
+	})
+
+	if err := runner.Run(context.Background(), macro); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	expected := "press:POWER,release:POWER,press:VOLUME_UP,release:VOLUME_UP,press:VOLUME_UP,release:VOLUME_UP,press:VOLUME_UP,release:VOLUME_UP"
+	if got := strings.Join(recorder.recorded(), ","); got != expected {
+		t.Errorf("Unexpected key sequence:\n got: %s\nwant: %s", got, expected)
+	}
+
+	if len(steps) != 3 {
+		t.Errorf("Expected 3 step callbacks, got %v", steps)
+	}
+}
+
+func TestMacroRunner_WaitForTimeout(t *testing.T) {
+	recorder := &keyRecorder{}
+
+	server := httptest.NewServer(http.HandlerFunc(recorder.handler))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+	ws := client.NewWebSocketClient(&WebSocketConfig{Logger: &mockLogger{}})
+
+	macro, _ := ParseMacro(strings.NewReader("key POWER\nwait-for volume 50ms\nkey PRESET_1\n"))
+
+	runner := NewMacroRunner(client)
+	runner.SetWebSocket(ws)
+
+	// An event from before the key step must not satisfy the wait
+	ws.handleMessage([]byte(`<updates deviceID="X"><volumeUpdated><volume><targetvolume>10</targetvolume><actualvolume>10</actualvolume><muteenabled>false</muteenabled></volume></volumeUpdated></updates>`))
+
+	err := runner.Run(context.Background(), macro)
+	if err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Fatalf("Expected timeout error, got %v", err)
+	}
+
+	if got := strings.Join(recorder.recorded(), ","); got != "press:POWER,release:POWER" {
+		t.Errorf("Expected the macro to stop after the failed wait, got %s", got)
+	}
+}
+
+func TestMacroRunner_WaitForRequiresWebSocket(t *testing.T) {
+	macro, _ := ParseMacro(strings.NewReader("wait-for source\n"))
+
+	if err := NewMacroRunner(createTestClient("http://localhost")).Run(context.Background(), macro); err == nil {
+		t.Error("Expected error without WebSocket connection")
+	}
+}
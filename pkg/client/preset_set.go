@@ -0,0 +1,156 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+// Limits of waiting for a device to confirm changed presets
+var (
+	presetVerifyTimeout = 10 * time.Second
+	presetPollInterval  = time.Second
+)
+
+// PresetPlan is the difference between a preset set and the presets of a device
+type PresetPlan struct {
+	*RestorePlan
+
+	c        *Client
+	expected map[int]*models.BackupPreset
+}
+
+// GetPresetSet copies the presets of the device into a preset set
+func (c *Client) GetPresetSet() (*models.PresetSet, error) {
+	info, err := c.GetDeviceInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device info: %w", err)
+	}
+
+	presets, err := c.GetPresets()
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewPresetSet(models.BackupDevice{DeviceID: info.DeviceID, Name: info.Name, Type: info.Type, Host: c.Host()}, presets), nil
+}
+
+// PlanPresets compares a preset set with the presets of the device.
+// Without slots, all slots are planned; slots that are empty in the set are cleared.
+// Presets whose source or account is not configured on the device are planned as skipped.
+func (c *Client) PlanPresets(set *models.PresetSet, slots []int) (*PresetPlan, error) {
+	if err := set.Validate(); err != nil {
+		return nil, err
+	}
+
+	for _, slot := range slots {
+		if err := models.ValidatePresetSlot(slot); err != nil {
+			return nil, err
+		}
+	}
+
+	backup := &models.DeviceBackup{Version: models.DeviceBackupVersion, Sections: []string{models.BackupSectionPresets}, Presets: set.Presets}
+
+	p, err := c.newRestorePlanner(backup, func(string) bool { return true })
+	if err != nil {
+		return nil, err
+	}
+
+	p.slots = slots
+
+	if err := p.planPresets(); err != nil {
+		return nil, fmt.Errorf("failed to plan presets: %w", err)
+	}
+
+	return &PresetPlan{RestorePlan: p.plan, c: c, expected: p.expected}, nil
+}
+
+// Apply stores and removes the pending presets, then waits until the device confirms them
+// with a presetsUpdated event, or by polling /presets where events are not available.
+func (p *PresetPlan) Apply() error {
+	if len(p.Pending()) == 0 {
+		return nil
+	}
+
+	events := make(chan *models.Presets, 8)
+
+	ws := p.c.NewWebSocketClient(&WebSocketConfig{Logger: DiscardLogger{}})
+	ws.reconnect = false
+
+	ws.OnPresetUpdated(func(event *models.PresetUpdatedEvent) {
+		select {
+		case events <- &event.Presets:
+		default:
+		}
+	})
+
+	if err := ws.Connect(); err == nil {
+		defer func() { _ = ws.Disconnect() }()
+	}
+
+	if err := p.RestorePlan.Apply(); err != nil {
+		return err
+	}
+
+	return p.confirm(events)
+}
+
+// confirm waits until the device reports the expected presets
+func (p *PresetPlan) confirm(events <-chan *models.Presets) error {
+	deadline := time.NewTimer(presetVerifyTimeout)
+	defer deadline.Stop()
+
+	ticker := time.NewTicker(presetPollInterval)
+	defer ticker.Stop()
+
+	mismatch := "no presets reported"
+
+	for {
+		if presets, err := p.c.GetPresets(); err == nil {
+			if mismatch = p.mismatch(presets); mismatch == "" {
+				return nil
+			}
+		}
+
+		select {
+		case presets := <-events:
+			if p.mismatch(presets) == "" {
+				return nil
+			}
+		case <-ticker.C:
+		case <-deadline.C:
+			return fmt.Errorf("preset change not confirmed, device reports: %s", mismatch)
+		}
+	}
+}
+
+// mismatch describes the slots whose presets differ from the plan, or returns an empty string
+func (p *PresetPlan) mismatch(presets *models.Presets) string {
+	var differences []string
+
+	for slot := 1; slot <= models.PresetSlots; slot++ {
+		want, planned := p.expected[slot]
+		if !planned {
+			continue
+		}
+
+		have := presets.GetPresetByID(slot)
+
+		switch {
+		case have == nil || have.IsEmpty():
+			if want != nil {
+				differences = append(differences, fmt.Sprintf("preset %d empty", slot))
+			}
+		case want == nil:
+			differences = append(differences, fmt.Sprintf("preset %d not removed", slot))
+		default:
+			if got := models.NewBackupPreset(have); !got.SameContent(want) {
+				differences = append(differences, fmt.Sprintf("preset %d is %s", slot, got.String()))
+			}
+		}
+	}
+
+	return strings.Join(differences, ", ")
+}
@@ -0,0 +1,195 @@
+package client
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+// presetTestDevice keeps presets stored and removed through the API; a frozen device ignores changes,
+// a renaming one stores presets under its own name and artwork
+type presetTestDevice struct {
+	mu       sync.Mutex
+	presets  map[int]*models.ContentItem
+	sources  string
+	frozen   bool
+	renaming bool
+}
+
+func (d *presetTestDevice) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/info":
+		_, _ = w.Write([]byte(`<info deviceID="BBB"><name>Kitchen</name><type>SoundTouch 10</type></info>`))
+	case "/sources":
+		_, _ = w.Write([]byte(d.sources))
+	case "/presets":
+		presets := models.Presets{}
+
+		for slot := 1; slot <= models.PresetSlots; slot++ {
+			if item, ok := d.presets[slot]; ok {
+				presets.Preset = append(presets.Preset, models.Preset{ID: slot, ContentItem: item})
+			}
+		}
+
+		data, _ := xml.Marshal(presets)
+		_, _ = w.Write(data)
+	case "/storePreset", "/removePreset":
+		var preset models.Preset
+		if err := xml.NewDecoder(r.Body).Decode(&preset); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if !d.frozen {
+			if r.URL.Path == "/storePreset" {
+				if d.renaming {
+					preset.ContentItem.ItemName = strings.ToUpper(preset.ContentItem.ItemName)
+					preset.ContentItem.ContainerArt = "http://device/art.png"
+				}
+
+				d.presets[preset.ID] = preset.ContentItem
+			} else {
+				delete(d.presets, preset.ID)
+			}
+		}
+
+		_, _ = w.Write([]byte(`<status>ok</status>`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func presetTestSet(presets ...models.BackupPreset) *models.PresetSet {
+	return &models.PresetSet{Version: models.PresetSetVersion, Presets: presets}
+}
+
+func TestClient_PlanAndApplyPresets(t *testing.T) {
+	device := &presetTestDevice{
+		presets: map[int]*models.ContentItem{
+			1: {Source: "TUNEIN", Type: "stationurl", Location: "/v1/playback/station/s1", IsPresetable: true, ItemName: "Radio 1"},
+			4: {Source: "TUNEIN", Type: "stationurl", Location: "/v1/playback/station/s4", IsPresetable: true, ItemName: "Radio 4"},
+		},
+		sources: `<sources deviceID="BBB"><sourceItem source="TUNEIN" status="READY" /></sources>`,
+	}
+
+	server := httptest.NewServer(device)
+	defer server.Close()
+
+	c := createTestClient(server.URL)
+
+	set := presetTestSet(
+		models.BackupPreset{Slot: 1, Source: "TUNEIN", Type: "stationurl", Location: "/v1/playback/station/s1", IsPresetable: true, Name: "Radio 1"},
+		models.BackupPreset{Slot: 2, Source: "TUNEIN", Type: "stationurl", Location: "/v1/playback/station/s2", IsPresetable: true, Name: "Radio 2", Artwork: "http://art/2.png"},
+		models.BackupPreset{Slot: 3, Source: "SPOTIFY", Type: "tracklisturl", Location: "/playback/container/abc", Account: "user1", IsPresetable: true, Name: "Jazz"},
+	)
+
+	plan, err := c.PlanPresets(set, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var changes []string
+	for _, change := range plan.Changes {
+		changes = append(changes, change.String())
+	}
+
+	expected := []string{
+		"preset 2: empty → Radio 2 (TUNEIN)",
+		"preset 3: empty → Jazz (SPOTIFY) (skipped: account user1 of SPOTIFY is not configured)",
+		"preset 4: Radio 4 (TUNEIN) → empty",
+	}
+	if strings.Join(changes, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected changes:\n%s", strings.Join(changes, "\n"))
+	}
+
+	if err := plan.Apply(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	result, err := c.GetPresetSet()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(result.Presets) != 2 || result.Preset(2) == nil || result.Preset(2).Artwork != "http://art/2.png" || result.Preset(4) != nil {
+		t.Errorf("Unexpected presets after apply: %+v", result.Presets)
+	}
+
+	// Limited to slot 1, which already matches
+	plan, err = c.PlanPresets(set, []int{1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(plan.Changes) != 0 || len(plan.Unchanged) != 1 {
+		t.Errorf("Expected slot 1 to be unchanged, got %+v", plan.RestorePlan)
+	}
+
+	if _, err := c.PlanPresets(set, []int{7}); err == nil {
+		t.Error("Expected an error for slot 7")
+	}
+}
+
+func TestPresetPlan_ApplyNotConfirmed(t *testing.T) {
+	timeout, interval := presetVerifyTimeout, presetPollInterval
+	presetVerifyTimeout, presetPollInterval = 200*time.Millisecond, 20*time.Millisecond
+
+	defer func() { presetVerifyTimeout, presetPollInterval = timeout, interval }()
+
+	device := &presetTestDevice{
+		presets: map[int]*models.ContentItem{},
+		sources: `<sources deviceID="BBB"><sourceItem source="TUNEIN" status="READY" /></sources>`,
+		frozen:  true,
+	}
+
+	server := httptest.NewServer(device)
+	defer server.Close()
+
+	plan, err := createTestClient(server.URL).PlanPresets(presetTestSet(
+		models.BackupPreset{Slot: 5, Source: "TUNEIN", Location: "/v1/playback/station/s5", IsPresetable: true, Name: "Radio 5"},
+	), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	err = plan.Apply()
+	if err == nil || !strings.Contains(err.Error(), "preset 5 empty") {
+		t.Errorf("Expected an unconfirmed preset 5, got %v", err)
+	}
+}
+
+func TestPresetPlan_ApplyConfirmsRenamedPreset(t *testing.T) {
+	timeout := presetVerifyTimeout
+	presetVerifyTimeout = 200 * time.Millisecond
+
+	defer func() { presetVerifyTimeout = timeout }()
+
+	device := &presetTestDevice{
+		presets:  map[int]*models.ContentItem{},
+		sources:  `<sources deviceID="BBB"><sourceItem source="TUNEIN" status="READY" /></sources>`,
+		renaming: true,
+	}
+
+	server := httptest.NewServer(device)
+	defer server.Close()
+
+	plan, err := createTestClient(server.URL).PlanPresets(presetTestSet(
+		models.BackupPreset{Slot: 2, Source: "TUNEIN", Type: "stationurl", Location: "/v1/playback/station/s2", IsPresetable: true, Name: "Radio 2"},
+	), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := plan.Apply(); err != nil {
+		t.Errorf("Expected the stored preset to be confirmed despite its new name, got %v", err)
+	}
+}
@@ -0,0 +1,281 @@
+package client
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+// ProgressEventType identifies a playback change detected by ProgressTracker
+type ProgressEventType string
+
+const (
+	// ProgressTrackChanged is fired when a different track starts
+	ProgressTrackChanged ProgressEventType = "trackChanged"
+	// ProgressSeeked is fired when the reported position jumps away from the interpolated one
+	ProgressSeeked ProgressEventType = "seeked"
+	// ProgressPaused is fired when playback stops or pauses
+	ProgressPaused ProgressEventType = "paused"
+	// ProgressResumed is fired when playback continues
+	ProgressResumed ProgressEventType = "resumed"
+	// ProgressEndingSoon is fired once when the remaining time drops below the threshold
+	ProgressEndingSoon ProgressEventType = "endingSoon"
+	// ProgressFinished is fired once when the interpolated position reaches the end of the track
+	ProgressFinished ProgressEventType = "finished"
+)
+
+const (
+	defaultEndingSoonThreshold = 10 * time.Second
+	defaultSeekTolerance       = 3 * time.Second
+)
+
+// Progress is an interpolated snapshot of the playback position
+type Progress struct {
+	Track    string
+	Artist   string
+	Album    string
+	Source   string
+	Position time.Duration
+	Total    time.Duration
+	Playing  bool
+	HasTime  bool
+}
+
+// Remaining returns the time left in the track, or 0 if the duration is unknown
+func (p Progress) Remaining() time.Duration {
+	if p.Total <= 0 || p.Position >= p.Total {
+		return 0
+	}
+
+	return p.Total - p.Position
+}
+
+// Fraction returns the played fraction between 0 and 1, or 0 if the duration is unknown
+func (p Progress) Fraction() float64 {
+	if p.Total <= 0 {
+		return 0
+	}
+
+	fraction := float64(p.Position) / float64(p.Total)
+	if fraction > 1 {
+		return 1
+	}
+
+	return fraction
+}
+
+// ProgressEvent describes a detected playback change
+type ProgressEvent struct {
+	Type     ProgressEventType
+	Progress Progress
+}
+
+// ProgressHandler is called for every detected playback change
+type ProgressHandler func(event ProgressEvent)
+
+// ProgressTracker interpolates the playback position between now playing updates.
+// The device only reports time when the source provides it, and only on changes,
+// so the tracker advances the last reported position with a local clock.
+type ProgressTracker struct {
+	mu                  sync.Mutex
+	now                 func() time.Time
+	endingSoonThreshold time.Duration
+	seekTolerance       time.Duration
+
+	trackKey     string
+	snapshot     Progress
+	snapshotAt   time.Time
+	initialized  bool
+	endingFired  bool
+	finishedSent bool
+
+	handlers []ProgressHandler
+}
+
+// NewProgressTracker creates a tracker with a 10 second "ending soon" threshold
+func NewProgressTracker() *ProgressTracker {
+	return &ProgressTracker{
+		now:                 time.Now,
+		endingSoonThreshold: defaultEndingSoonThreshold,
+		seekTolerance:       defaultSeekTolerance,
+	}
+}
+
+// SetEndingSoonThreshold sets how much remaining time triggers ProgressEndingSoon
+func (t *ProgressTracker) SetEndingSoonThreshold(threshold time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.endingSoonThreshold = threshold
+}
+
+// OnProgressEvent registers a handler for detected playback changes
+func (t *ProgressTracker) OnProgressEvent(handler ProgressHandler) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.handlers = append(t.handlers, handler)
+}
+
+// Attach feeds now playing events from a WebSocket client into the tracker.
+// This replaces a now playing handler previously set on the client.
+func (t *ProgressTracker) Attach(ws *WebSocketClient) {
+	ws.OnNowPlaying(func(event *models.NowPlayingUpdatedEvent) {
+		t.Update(&event.NowPlaying)
+	})
+}
+
+// Update applies a now playing snapshot from REST or a WebSocket event
+func (t *ProgressTracker) Update(np *models.NowPlaying) {
+	if np == nil {
+		return
+	}
+
+	t.mu.Lock()
+
+	now := t.now()
+	key := trackKey(np)
+	next := Progress{
+		Track:    np.GetDisplayTitle(),
+		Artist:   np.GetDisplayArtist(),
+		Album:    np.Album,
+		Source:   np.Source,
+		Position: np.GetPositionDuration(),
+		Total:    np.GetTotalDuration(),
+		Playing:  np.PlayStatus.IsPlaying() || np.PlayStatus == models.PlayStatusBuffering,
+		HasTime:  np.HasTimeInfo(),
+	}
+
+	var events []ProgressEvent
+
+	switch {
+	case !t.initialized || key != t.trackKey:
+		if t.initialized {
+			events = append(events, ProgressEvent{Type: ProgressTrackChanged, Progress: next})
+		}
+
+		t.endingFired = false
+		t.finishedSent = false
+	default:
+		previous := t.interpolate(now)
+
+		if next.HasTime && previous.HasTime && absDuration(next.Position-previous.Position) > t.seekTolerance {
+			events = append(events, ProgressEvent{Type: ProgressSeeked, Progress: next})
+
+			if next.Total <= 0 || next.Total-next.Position > t.endingSoonThreshold {
+				t.endingFired = false
+			}
+
+			t.finishedSent = false
+		}
+
+		if previous.Playing && !next.Playing {
+			events = append(events, ProgressEvent{Type: ProgressPaused, Progress: next})
+		} else if !previous.Playing && next.Playing {
+			events = append(events, ProgressEvent{Type: ProgressResumed, Progress: next})
+		}
+	}
+
+	t.initialized = true
+	t.trackKey = key
+	t.snapshot = next
+	t.snapshotAt = now
+
+	events = append(events, t.thresholdEvents(now)...)
+	handlers := t.handlers
+	t.mu.Unlock()
+
+	dispatchProgressEvents(handlers, events)
+}
+
+// Tick checks the interpolated position against the thresholds.
+// Call it periodically to get "ending soon" and "finished" notifications between updates.
+func (t *ProgressTracker) Tick() {
+	t.mu.Lock()
+	events := t.thresholdEvents(t.now())
+	handlers := t.handlers
+	t.mu.Unlock()
+
+	dispatchProgressEvents(handlers, events)
+}
+
+// Progress returns the interpolated progress at the current time
+func (t *ProgressTracker) Progress() Progress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.interpolate(t.now())
+}
+
+// interpolate advances the last snapshot to the given time; the caller must hold the lock
+func (t *ProgressTracker) interpolate(now time.Time) Progress {
+	progress := t.snapshot
+	if !progress.Playing || !progress.HasTime {
+		return progress
+	}
+
+	progress.Position += now.Sub(t.snapshotAt)
+	if progress.Total > 0 && progress.Position > progress.Total {
+		progress.Position = progress.Total
+	}
+
+	return progress
+}
+
+// thresholdEvents returns pending "ending soon" and "finished" events; the caller must hold the lock
+func (t *ProgressTracker) thresholdEvents(now time.Time) []ProgressEvent {
+	if !t.initialized {
+		return nil
+	}
+
+	progress := t.interpolate(now)
+	if !progress.Playing || !progress.HasTime || progress.Total <= 0 {
+		return nil
+	}
+
+	var events []ProgressEvent
+
+	if !t.endingFired && progress.Remaining() <= t.endingSoonThreshold {
+		t.endingFired = true
+		events = append(events, ProgressEvent{Type: ProgressEndingSoon, Progress: progress})
+	}
+
+	if !t.finishedSent && progress.Position >= progress.Total {
+		t.finishedSent = true
+		events = append(events, ProgressEvent{Type: ProgressFinished, Progress: progress})
+	}
+
+	return events
+}
+
+func dispatchProgressEvents(handlers []ProgressHandler, events []ProgressEvent) {
+	for _, event := range events {
+		for _, handler := range handlers {
+			handler(event)
+		}
+	}
+}
+
+// trackKey identifies a track across now playing updates
+func trackKey(np *models.NowPlaying) string {
+	key := np.Source + "|" + np.Track + "|" + np.Artist + "|" + np.Album
+
+	if np.ContentItem != nil {
+		key += "|" + np.ContentItem.Location
+	}
+
+	if np.TrackID != "" {
+		key += "|" + np.TrackID
+	}
+
+	return key
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+
+	return d
+}
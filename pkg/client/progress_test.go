@@ -0,0 +1,172 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+type fakeClock struct {
+	current time.Time
+}
+
+func (f *fakeClock) now() time.Time {
+	return f.current
+}
+
+func (f *fakeClock) advance(d time.Duration) {
+	f.current = f.current.Add(d)
+}
+
+func newTestProgressTracker() (*ProgressTracker, *fakeClock, *[]ProgressEventType) {
+	clock := &fakeClock{current: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	tracker := NewProgressTracker()
+	tracker.now = clock.now
+
+	var events []ProgressEventType
+
+	tracker.OnProgressEvent(func(event ProgressEvent) {
+		events = append(events, event.Type)
+	})
+
+	return tracker, clock, &events
+}
+
+func testNowPlaying(track string, position, total int, status models.PlayStatus) *models.NowPlaying {
+	return &models.NowPlaying{
+		Source:     "SPOTIFY",
+		Track:      track,
+		Artist:     "Artist",
+		Album:      "Album",
+		Time:       &models.Time{Position: position, Total: total},
+		PlayStatus: status,
+	}
+}
+
+func TestProgressTracker_Interpolation(t *testing.T) {
+	tracker, clock, events := newTestProgressTracker()
+
+	tracker.Update(testNowPlaying("Song", 30, 200, models.PlayStatusPlaying))
+	clock.advance(15 * time.Second)
+
+	progress := tracker.Progress()
+	if progress.Position != 45*time.Second {
+		t.Errorf("Expected interpolated position 45s, got %v", progress.Position)
+	}
+
+	if progress.Remaining() != 155*time.Second {
+		t.Errorf("Expected remaining 155s, got %v", progress.Remaining())
+	}
+
+	if len(*events) != 0 {
+		t.Errorf("Expected no events for the first snapshot, got %v", *events)
+	}
+}
+
+func TestProgressTracker_PausedDoesNotAdvance(t *testing.T) {
+	tracker, clock, events := newTestProgressTracker()
+
+	tracker.Update(testNowPlaying("Song", 30, 200, models.PlayStatusPlaying))
+	clock.advance(10 * time.Second)
+	tracker.Update(testNowPlaying("Song", 40, 200, models.PlayStatusPaused))
+	clock.advance(60 * time.Second)
+
+	if got := tracker.Progress().Position; got != 40*time.Second {
+		t.Errorf("Expected paused position to stay at 40s, got %v", got)
+	}
+
+	tracker.Update(testNowPlaying("Song", 40, 200, models.PlayStatusPlaying))
+
+	expected := []ProgressEventType{ProgressPaused, ProgressResumed}
+	assertProgressEvents(t, *events, expected)
+}
+
+func TestProgressTracker_SeekAndTrackChange(t *testing.T) {
+	tracker, clock, events := newTestProgressTracker()
+
+	tracker.Update(testNowPlaying("Song", 30, 200, models.PlayStatusPlaying))
+	clock.advance(5 * time.Second)
+
+	// Within tolerance: no seek
+	tracker.Update(testNowPlaying("Song", 36, 200, models.PlayStatusPlaying))
+
+	// Jump far ahead
+	tracker.Update(testNowPlaying("Song", 120, 200, models.PlayStatusPlaying))
+
+	tracker.Update(testNowPlaying("Other Song", 0, 180, models.PlayStatusPlaying))
+
+	assertProgressEvents(t, *events, []ProgressEventType{ProgressSeeked, ProgressTrackChanged})
+}
+
+func TestProgressTracker_EndingSoonAndFinished(t *testing.T) {
+	tracker, clock, events := newTestProgressTracker()
+	tracker.SetEndingSoonThreshold(10 * time.Second)
+
+	tracker.Update(testNowPlaying("Song", 180, 200, models.PlayStatusPlaying))
+
+	clock.advance(5 * time.Second)
+	tracker.Tick()
+
+	if len(*events) != 0 {
+		t.Fatalf("Expected no events yet, got %v", *events)
+	}
+
+	clock.advance(6 * time.Second)
+	tracker.Tick()
+	tracker.Tick()
+
+	clock.advance(20 * time.Second)
+	tracker.Tick()
+
+	assertProgressEvents(t, *events, []ProgressEventType{ProgressEndingSoon, ProgressFinished})
+
+	if got := tracker.Progress().Position; got != 200*time.Second {
+		t.Errorf("Expected position to be clamped at total, got %v", got)
+	}
+}
+
+func TestProgressTracker_SeekBackRearmsThresholds(t *testing.T) {
+	tracker, clock, events := newTestProgressTracker()
+
+	tracker.Update(testNowPlaying("Song", 195, 200, models.PlayStatusPlaying))
+	clock.advance(time.Second)
+	tracker.Update(testNowPlaying("Song", 10, 200, models.PlayStatusPlaying))
+
+	clock.advance(185 * time.Second)
+	tracker.Tick()
+
+	assertProgressEvents(t, *events, []ProgressEventType{ProgressEndingSoon, ProgressSeeked, ProgressEndingSoon})
+}
+
+func TestProgressTracker_NoTimeInfo(t *testing.T) {
+	tracker, clock, events := newTestProgressTracker()
+
+	np := &models.NowPlaying{Source: "TUNEIN", StationName: "Radio", PlayStatus: models.PlayStatusPlaying}
+	tracker.Update(np)
+	clock.advance(time.Hour)
+	tracker.Tick()
+
+	progress := tracker.Progress()
+	if progress.HasTime || progress.Position != 0 || progress.Fraction() != 0 {
+		t.Errorf("Expected no progress for a stream without time info, got %+v", progress)
+	}
+
+	if len(*events) != 0 {
+		t.Errorf("Expected no events, got %v", *events)
+	}
+}
+
+func assertProgressEvents(t *testing.T, got, expected []ProgressEventType) {
+	t.Helper()
+
+	if len(got) != len(expected) {
+		t.Fatalf("Expected events %v, got %v", expected, got)
+	}
+
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("Expected event %d to be %s, got %s", i, expected[i], got[i])
+		}
+	}
+}
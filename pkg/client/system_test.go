@@ -454,6 +454,90 @@ func TestClient_SetClockDisplayFormat(t *testing.T) {
 	}
 }
 
+func TestClient_GetLanguage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/language" {
+			t.Errorf("Expected path '/language', got '%s'", r.URL.Path)
+		}
+
+		if r.Method != "GET" {
+			t.Errorf("Expected GET method, got '%s'", r.Method)
+		}
+
+		_, _ = w.Write([]byte(`<sysLanguage>2</sysLanguage>`))
+	}))
+	defer server.Close()
+
+	client := createTestClient(server.URL)
+
+	language, err := client.GetLanguage()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if language.Value != 2 || language.String() != "German" {
+		t.Errorf("Expected German (2), got %s (%d)", language.String(), language.Value)
+	}
+}
+
+func TestClient_SetLanguage(t *testing.T) {
+	tests := []struct {
+		name        string
+		code        int
+		statusCode  int
+		expectError bool
+	}{
+		{
+			name:       "Successful language set",
+			code:       3,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:        "Unknown language code",
+			code:        14,
+			expectError: true,
+		},
+		{
+			name:        "Server error",
+			code:        5,
+			statusCode:  http.StatusInternalServerError,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/language" {
+					t.Errorf("Expected path '/language', got '%s'", r.URL.Path)
+				}
+
+				if r.Method != "POST" {
+					t.Errorf("Expected POST method, got '%s'", r.Method)
+				}
+
+				w.WriteHeader(tt.statusCode)
+			}))
+			defer server.Close()
+
+			client := createTestClient(server.URL)
+			err := client.SetLanguage(tt.code)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error, got none")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
 func TestClient_GetNetworkInfo(t *testing.T) {
 	tests := []struct {
 		name                   string
@@ -0,0 +1,355 @@
+package client
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+// soundTouchUPnPPort is the port of the UPnP MediaRenderer of SoundTouch speakers
+const soundTouchUPnPPort = 8091
+
+// MediaRenderer controls the UPnP MediaRenderer of a speaker through the AVTransport and
+// RenderingControl SOAP services. It plays arbitrary HTTP audio URLs without going
+// through /speaker or /select.
+type MediaRenderer struct {
+	avTransportURL      string
+	renderingControlURL string
+	httpClient          *http.Client
+	userAgent           string
+}
+
+// soapArg is an action argument; SOAP arguments are ordered
+type soapArg struct {
+	name  string
+	value string
+}
+
+// SoundTouchDescriptionURL returns the UPnP description location of a SoundTouch speaker,
+// which is derived from its device ID
+func SoundTouchDescriptionURL(host, deviceID string) string {
+	address := net.JoinHostPort(strings.Replace(host, "%", "%25", 1), strconv.Itoa(soundTouchUPnPPort))
+
+	return fmt.Sprintf("http://%s/XD/BO5EBO5E-F00D-F00D-FEED-%s.xml", address, strings.ToUpper(deviceID))
+}
+
+// NewMediaRenderer creates a MediaRenderer from the services of a UPnP device description.
+// The AVTransport service is required, RenderingControl is optional.
+func NewMediaRenderer(services []models.UPnPService, timeout time.Duration) (*MediaRenderer, error) {
+	avTransport, ok := models.FindUPnPService(services, models.UPnPServiceAVTransport)
+	if !ok || avTransport.ControlURL == "" {
+		return nil, fmt.Errorf("device has no AVTransport service")
+	}
+
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+
+	renderer := &MediaRenderer{
+		avTransportURL: avTransport.ControlURL,
+		httpClient:     &http.Client{Timeout: timeout},
+		userAgent:      "Bose-SoundTouch-Go-Client/1.0",
+	}
+
+	if renderingControl, ok := models.FindUPnPService(services, models.UPnPServiceRenderingControl); ok {
+		renderer.renderingControlURL = renderingControl.ControlURL
+	}
+
+	return renderer, nil
+}
+
+// NewMediaRendererFromDescription fetches the UPnP device description at location and creates a MediaRenderer
+func NewMediaRendererFromDescription(location string, timeout time.Duration) (*MediaRenderer, error) {
+	description, err := GetUPnPDescription(location, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMediaRenderer(description.Services(location), timeout)
+}
+
+// GetUPnPDescription fetches and parses the UPnP device description at location
+func GetUPnPDescription(location string, timeout time.Duration) (*models.UPnPDescription, error) {
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+
+	resp, err := (&http.Client{Timeout: timeout}).Get(location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch device description: %w", err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch device description: HTTP %d", resp.StatusCode)
+	}
+
+	var description models.UPnPDescription
+	if err := xml.NewDecoder(resp.Body).Decode(&description); err != nil {
+		return nil, fmt.Errorf("failed to parse device description: %w", err)
+	}
+
+	return &description, nil
+}
+
+// MediaRenderer returns the UPnP MediaRenderer of the speaker, located through its device ID
+func (c *Client) MediaRenderer() (*MediaRenderer, error) {
+	info, err := c.GetDeviceInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := url.Parse(c.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse base URL: %w", err)
+	}
+
+	renderer, err := NewMediaRendererFromDescription(SoundTouchDescriptionURL(base.Hostname(), info.DeviceID), c.timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	renderer.userAgent = c.userAgent
+
+	return renderer, nil
+}
+
+// PlayURL plays an HTTP audio URL; title is shown as track name where supported
+func (r *MediaRenderer) PlayURL(uri, title string) error {
+	if err := r.SetAVTransportURI(uri, models.NewUPnPTrackMetadata(uri, title)); err != nil {
+		return err
+	}
+
+	return r.Play()
+}
+
+// SetAVTransportURI sets the URL to play together with its DIDL-Lite metadata
+func (r *MediaRenderer) SetAVTransportURI(uri, metadata string) error {
+	if uri == "" {
+		return fmt.Errorf("URL cannot be empty")
+	}
+
+	_, err := r.avTransport("SetAVTransportURI", soapArg{"CurrentURI", uri}, soapArg{"CurrentURIMetaData", metadata})
+
+	return err
+}
+
+// Play starts or resumes playback of the current URL
+func (r *MediaRenderer) Play() error {
+	_, err := r.avTransport("Play", soapArg{"Speed", "1"})
+	return err
+}
+
+// Pause pauses playback
+func (r *MediaRenderer) Pause() error {
+	_, err := r.avTransport("Pause")
+	return err
+}
+
+// Stop stops playback
+func (r *MediaRenderer) Stop() error {
+	_, err := r.avTransport("Stop")
+	return err
+}
+
+// GetTransportInfo returns the transport state, e.g. PLAYING or STOPPED
+func (r *MediaRenderer) GetTransportInfo() (*models.UPnPTransportInfo, error) {
+	values, err := r.avTransport("GetTransportInfo")
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.UPnPTransportInfo{
+		State:  values["CurrentTransportState"],
+		Status: values["CurrentTransportStatus"],
+		Speed:  values["CurrentSpeed"],
+	}, nil
+}
+
+// GetPositionInfo returns the current track and the playback position
+func (r *MediaRenderer) GetPositionInfo() (*models.UPnPPositionInfo, error) {
+	values, err := r.avTransport("GetPositionInfo")
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.UPnPPositionInfo{
+		Track:         values["Track"],
+		TrackDuration: values["TrackDuration"],
+		TrackURI:      values["TrackURI"],
+		RelTime:       values["RelTime"],
+	}, nil
+}
+
+// GetVolume returns the master volume (0-100) through RenderingControl
+func (r *MediaRenderer) GetVolume() (int, error) {
+	values, err := r.renderingControl("GetVolume", soapArg{"Channel", "Master"})
+	if err != nil {
+		return 0, err
+	}
+
+	volume, err := strconv.Atoi(values["CurrentVolume"])
+	if err != nil {
+		return 0, fmt.Errorf("invalid volume %q: %w", values["CurrentVolume"], err)
+	}
+
+	return volume, nil
+}
+
+// SetVolume sets the master volume (0-100) through RenderingControl
+func (r *MediaRenderer) SetVolume(volume int) error {
+	if volume < 0 || volume > 100 {
+		return fmt.Errorf("volume must be between 0 and 100, got %d", volume)
+	}
+
+	_, err := r.renderingControl("SetVolume", soapArg{"Channel", "Master"}, soapArg{"DesiredVolume", strconv.Itoa(volume)})
+
+	return err
+}
+
+// SetMute mutes or unmutes through RenderingControl
+func (r *MediaRenderer) SetMute(muted bool) error {
+	desired := "0"
+	if muted {
+		desired = "1"
+	}
+
+	_, err := r.renderingControl("SetMute", soapArg{"Channel", "Master"}, soapArg{"DesiredMute", desired})
+
+	return err
+}
+
+func (r *MediaRenderer) avTransport(action string, args ...soapArg) (map[string]string, error) {
+	args = append([]soapArg{{"InstanceID", "0"}}, args...)
+
+	return r.call(r.avTransportURL, models.UPnPServiceAVTransport, action, args)
+}
+
+func (r *MediaRenderer) renderingControl(action string, args ...soapArg) (map[string]string, error) {
+	if r.renderingControlURL == "" {
+		return nil, fmt.Errorf("device has no RenderingControl service")
+	}
+
+	args = append([]soapArg{{"InstanceID", "0"}}, args...)
+
+	return r.call(r.renderingControlURL, models.UPnPServiceRenderingControl, action, args)
+}
+
+// call invokes a SOAP action and returns the output arguments of the response
+func (r *MediaRenderer) call(controlURL, serviceType, action string, args []soapArg) (map[string]string, error) {
+	var body bytes.Buffer
+
+	body.WriteString(`<?xml version="1.0" encoding="utf-8"?>`)
+	body.WriteString(`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"><s:Body>`)
+	fmt.Fprintf(&body, `<u:%s xmlns:u="%s">`, action, serviceType)
+
+	for _, arg := range args {
+		fmt.Fprintf(&body, "<%s>", arg.name)
+		_ = xml.EscapeText(&body, []byte(arg.value))
+		fmt.Fprintf(&body, "</%s>", arg.name)
+	}
+
+	fmt.Fprintf(&body, `</u:%s></s:Body></s:Envelope>`, action)
+
+	req, err := http.NewRequest(http.MethodPost, controlURL, &body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", `text/xml; charset="utf-8"`)
+	req.Header.Set("SOAPAction", fmt.Sprintf(`"%s#%s"`, serviceType, action))
+	req.Header.Set("User-Agent", r.userAgent)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send %s: %w", action, err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s response: %w", action, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if fault := parseSOAPFault(action, data); fault != nil {
+			return nil, fault
+		}
+
+		return nil, fmt.Errorf("%s failed: HTTP %d", action, resp.StatusCode)
+	}
+
+	return parseSOAPResponse(action, data)
+}
+
+// parseSOAPResponse collects the output arguments of an action response
+func parseSOAPResponse(action string, data []byte) (map[string]string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	values := make(map[string]string)
+	inResponse := false
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s response: %w", action, err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		if start.Name.Local == action+"Response" {
+			inResponse = true
+			continue
+		}
+
+		if inResponse {
+			var value string
+			if err := decoder.DecodeElement(&value, &start); err != nil {
+				return nil, fmt.Errorf("failed to parse %s response: %w", action, err)
+			}
+
+			values[start.Name.Local] = value
+		}
+	}
+
+	if !inResponse {
+		return nil, fmt.Errorf("%s response is missing", action)
+	}
+
+	return values, nil
+}
+
+// parseSOAPFault returns the UPnP error of a SOAP fault, or nil if data is no fault
+func parseSOAPFault(action string, data []byte) *models.UPnPError {
+	var fault struct {
+		Code        int    `xml:"Body>Fault>detail>UPnPError>errorCode"`
+		Description string `xml:"Body>Fault>detail>UPnPError>errorDescription"`
+	}
+
+	if err := xml.Unmarshal(data, &fault); err != nil || fault.Code == 0 {
+		return nil
+	}
+
+	return &models.UPnPError{Action: action, Code: fault.Code, Description: fault.Description}
+}
@@ -0,0 +1,157 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+// fakeRenderer records SOAP actions and answers them like a SoundTouch MediaRenderer
+type fakeRenderer struct {
+	mu      sync.Mutex
+	actions []string
+	bodies  []string
+	uri     string
+	state   string
+	volume  int
+}
+
+func newFakeRenderer(t *testing.T) (*fakeRenderer, string) {
+	t.Helper()
+
+	f := &fakeRenderer{state: models.UPnPStateStopped, volume: 30}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/XD/description.xml" {
+			_, _ = fmt.Fprint(w, `<root><device><friendlyName>Kitchen</friendlyName><serviceList>`+
+				`<service><serviceType>urn:schemas-upnp-org:service:AVTransport:1</serviceType><controlURL>/AVTransport/Control</controlURL></service>`+
+				`<service><serviceType>urn:schemas-upnp-org:service:RenderingControl:1</serviceType><controlURL>/RenderingControl/Control</controlURL></service>`+
+				`</serviceList></device></root>`)
+
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		action := r.Header.Get("SOAPAction")
+		action = strings.Trim(action[strings.Index(action, "#")+1:], `"`)
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		f.actions = append(f.actions, action)
+		f.bodies = append(f.bodies, string(body))
+
+		var result string
+
+		switch action {
+		case "SetAVTransportURI":
+			f.uri = "set"
+		case "Play":
+			if f.uri == "" {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = fmt.Fprint(w, `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body><s:Fault><faultcode>s:Client</faultcode><faultstring>UPnPError</faultstring>`+
+					`<detail><UPnPError xmlns="urn:schemas-upnp-org:control-1-0"><errorCode>701</errorCode><errorDescription>Transition not available</errorDescription></UPnPError></detail></s:Fault></s:Body></s:Envelope>`)
+
+				return
+			}
+
+			f.state = models.UPnPStatePlaying
+		case "GetTransportInfo":
+			result = fmt.Sprintf(`<CurrentTransportState>%s</CurrentTransportState><CurrentTransportStatus>OK</CurrentTransportStatus><CurrentSpeed>1</CurrentSpeed>`, f.state)
+		case "GetVolume":
+			result = fmt.Sprintf(`<CurrentVolume>%d</CurrentVolume>`, f.volume)
+		case "SetVolume":
+			if _, value, ok := strings.Cut(string(body), "<DesiredVolume>"); ok {
+				_, _ = fmt.Sscanf(value, "%d", &f.volume)
+			}
+		}
+
+		_, _ = fmt.Fprintf(w, `<?xml version="1.0"?><s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body><u:%sResponse xmlns:u="urn:schemas-upnp-org:service:AVTransport:1">%s</u:%sResponse></s:Body></s:Envelope>`,
+			action, result, action)
+	}))
+	t.Cleanup(server.Close)
+
+	return f, server.URL + "/XD/description.xml"
+}
+
+func TestMediaRenderer_PlayURL(t *testing.T) {
+	f, location := newFakeRenderer(t)
+
+	renderer, err := NewMediaRendererFromDescription(location, 0)
+	if err != nil {
+		t.Fatalf("Failed to create renderer: %v", err)
+	}
+
+	if err := renderer.PlayURL("http://example.com/stream.mp3?a=1&b=2", "Morning <Show>"); err != nil {
+		t.Fatalf("Failed to play URL: %v", err)
+	}
+
+	if got := strings.Join(f.actions, ","); got != "SetAVTransportURI,Play" {
+		t.Fatalf("Unexpected actions: %s", got)
+	}
+
+	setURI := f.bodies[0]
+	if !strings.Contains(setURI, "<InstanceID>0</InstanceID><CurrentURI>http://example.com/stream.mp3?a=1&amp;b=2</CurrentURI>") {
+		t.Errorf("Expected escaped URI in request, got %s", setURI)
+	}
+
+	if !strings.Contains(setURI, "Morning &amp;lt;Show&amp;gt;") || !strings.Contains(setURI, "http-get:*:audio/mpeg:*") {
+		t.Errorf("Expected escaped DIDL-Lite metadata in request, got %s", setURI)
+	}
+
+	info, err := renderer.GetTransportInfo()
+	if err != nil || info.State != models.UPnPStatePlaying || info.Status != "OK" {
+		t.Errorf("Unexpected transport info: %+v, %v", info, err)
+	}
+}
+
+func TestMediaRenderer_Volume(t *testing.T) {
+	_, location := newFakeRenderer(t)
+	renderer, _ := NewMediaRendererFromDescription(location, 0)
+
+	if err := renderer.SetVolume(42); err != nil {
+		t.Fatalf("Failed to set volume: %v", err)
+	}
+
+	if volume, err := renderer.GetVolume(); err != nil || volume != 42 {
+		t.Errorf("Expected volume 42, got %d, %v", volume, err)
+	}
+
+	if err := renderer.SetVolume(101); err == nil {
+		t.Error("Expected error for volume out of range")
+	}
+}
+
+func TestMediaRenderer_Fault(t *testing.T) {
+	_, location := newFakeRenderer(t)
+	renderer, _ := NewMediaRendererFromDescription(location, 0)
+
+	err := renderer.Play()
+
+	var upnpErr *models.UPnPError
+	if !errors.As(err, &upnpErr) || upnpErr.Code != 701 || upnpErr.Action != "Play" {
+		t.Fatalf("Expected UPnP error 701, got %v", err)
+	}
+}
+
+func TestNewMediaRenderer_RequiresAVTransport(t *testing.T) {
+	services := []models.UPnPService{{ServiceType: models.UPnPServiceRenderingControl, ControlURL: "http://host/rc"}}
+
+	if _, err := NewMediaRenderer(services, 0); err == nil {
+		t.Error("Expected error without AVTransport service")
+	}
+}
+
+func TestSoundTouchDescriptionURL(t *testing.T) {
+	expected := "http://192.168.1.10:8091/XD/BO5EBO5E-F00D-F00D-FEED-AABBCC000001.xml"
+	if got := SoundTouchDescriptionURL("192.168.1.10", "aabbcc000001"); got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
 	"net/url"
 	"strings"
 	"sync"
@@ -25,6 +26,18 @@ type WebSocketClient struct {
 	cancel     context.CancelFunc
 	logger     Logger
 	bufferSize int
+
+	onReconnect func()
+
+	observers      map[int]models.EventHandler
+	nextObserverID int
+
+	deviceID       string
+	requestTimeout time.Duration
+	nextRequestID  uint64
+	pendingMu      sync.Mutex
+	writeMu        sync.Mutex
+	pending        map[string]chan *models.WebSocketMessage
 }
 
 // Logger interface for WebSocket logging
@@ -40,6 +53,12 @@ func (d DefaultLogger) Printf(format string, v ...interface{}) {
 	log.Printf("[WebSocket] "+format, v...)
 }
 
+// DiscardLogger drops all messages, e.g. for short-lived or background connections
+type DiscardLogger struct{}
+
+// Printf implements the Logger interface by discarding the message.
+func (DiscardLogger) Printf(string, ...interface{}) {}
+
 // WebSocketConfig holds configuration for WebSocket client
 type WebSocketConfig struct {
 	// ReconnectInterval defines how long to wait between reconnection attempts
@@ -54,6 +73,8 @@ type WebSocketConfig struct {
 	ReadBufferSize int
 	// WriteBufferSize defines the WebSocket write buffer size
 	WriteBufferSize int
+	// RequestTimeout defines how long to wait for the reply to a request sent over the WebSocket
+	RequestTimeout time.Duration
 	// Logger for WebSocket events (nil = default logger)
 	Logger Logger
 }
@@ -67,6 +88,7 @@ func DefaultWebSocketConfig() *WebSocketConfig {
 		PongTimeout:          10 * time.Second,
 		ReadBufferSize:       1024,
 		WriteBufferSize:      1024,
+		RequestTimeout:       5 * time.Second,
 		Logger:               DefaultLogger{},
 	}
 }
@@ -79,14 +101,21 @@ func (c *Client) NewWebSocketClient(config *WebSocketConfig) *WebSocketClient {
 
 	ctx, cancel := context.WithCancel(context.Background())
 
+	requestTimeout := config.RequestTimeout
+	if requestTimeout <= 0 {
+		requestTimeout = 5 * time.Second
+	}
+
 	return &WebSocketClient{
-		client:     c,
-		handlers:   &models.WebSocketEventHandlers{},
-		reconnect:  true,
-		ctx:        ctx,
-		cancel:     cancel,
-		logger:     config.Logger,
-		bufferSize: config.ReadBufferSize,
+		client:         c,
+		handlers:       &models.WebSocketEventHandlers{},
+		reconnect:      true,
+		ctx:            ctx,
+		cancel:         cancel,
+		logger:         config.Logger,
+		bufferSize:     config.ReadBufferSize,
+		requestTimeout: requestTimeout,
+		pending:        make(map[string]chan *models.WebSocketMessage),
 	}
 }
 
@@ -146,6 +175,61 @@ func (ws *WebSocketClient) OnBassUpdated(handler models.TypedEventHandler[*model
 	ws.handlers.OnBassUpdated = handler
 }
 
+// OnNameUpdated sets a handler for device name update events
+func (ws *WebSocketClient) OnNameUpdated(handler models.TypedEventHandler[*models.NameUpdatedEvent]) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	ws.handlers.OnNameUpdated = handler
+}
+
+// OnRecentsUpdated sets a handler for recents update events
+func (ws *WebSocketClient) OnRecentsUpdated(handler models.TypedEventHandler[*models.RecentsUpdatedEvent]) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	ws.handlers.OnRecentsUpdated = handler
+}
+
+// OnSourcesUpdated sets a handler for sources update events
+func (ws *WebSocketClient) OnSourcesUpdated(handler models.TypedEventHandler[*models.SourcesUpdatedEvent]) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	ws.handlers.OnSourcesUpdated = handler
+}
+
+// OnReconnect sets a handler that is called after a lost connection has been re-established.
+// Events sent by the device while disconnected are lost, so this is the place to resync state.
+func (ws *WebSocketClient) OnReconnect(handler func()) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	ws.onReconnect = handler
+}
+
+// ObserveEvents registers a handler that sees every parsed event in addition to the typed handlers.
+// Unlike the On* setters it does not replace other handlers. The returned function removes it again.
+func (ws *WebSocketClient) ObserveEvents(handler models.EventHandler) func() {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if ws.observers == nil {
+		ws.observers = make(map[int]models.EventHandler)
+	}
+
+	id := ws.nextObserverID
+	ws.nextObserverID++
+	ws.observers[id] = handler
+
+	return func() {
+		ws.mu.Lock()
+		defer ws.mu.Unlock()
+
+		delete(ws.observers, id)
+	}
+}
+
 // OnUnknownEvent sets a handler for unknown events
 func (ws *WebSocketClient) OnUnknownEvent(handler models.EventHandler) {
 	ws.mu.Lock()
@@ -189,7 +273,7 @@ func (ws *WebSocketClient) connectWithConfig(config *WebSocketConfig) error {
 
 	wsURL := url.URL{
 		Scheme: "ws",
-		Host:   fmt.Sprintf("%s:8080", baseURL.Hostname()), // SoundTouch WebSocket port is typically 8080
+		Host:   net.JoinHostPort(baseURL.Hostname(), "8080"), // SoundTouch WebSocket port is typically 8080
 		Path:   "/",
 	}
 
@@ -272,6 +356,8 @@ func (ws *WebSocketClient) readLoop(config *WebSocketConfig) {
 
 		ws.mu.Unlock()
 
+		ws.failPendingRequests()
+
 		// Attempt reconnection if enabled
 		if ws.reconnect {
 			go ws.attemptReconnect(config)
@@ -336,8 +422,12 @@ func (ws *WebSocketClient) pingLoop(config *WebSocketConfig) {
 			}
 
 			// Set write deadline for ping
+			ws.writeMu.Lock()
 			_ = conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+			err := conn.WriteMessage(websocket.PingMessage, nil)
+			ws.writeMu.Unlock()
+
+			if err != nil {
 				ws.logger.Printf("Failed to send ping: %v", err)
 				return
 			}
@@ -365,6 +455,14 @@ func (ws *WebSocketClient) attemptReconnect(config *WebSocketConfig) {
 
 		ws.logger.Printf("Reconnected successfully")
 
+		ws.mu.RLock()
+		handler := ws.onReconnect
+		ws.mu.RUnlock()
+
+		if handler != nil {
+			handler()
+		}
+
 		return
 	}
 
@@ -373,6 +471,12 @@ func (ws *WebSocketClient) attemptReconnect(config *WebSocketConfig) {
 
 // handleMessage processes incoming WebSocket messages
 func (ws *WebSocketClient) handleMessage(data []byte) {
+	// Replies to requests sent over the WebSocket
+	if ws.isReplyMessage(data) {
+		ws.handleReply(data)
+		return
+	}
+
 	// Check if this is a SoundTouchSdkInfo or other non-updates message
 	if !ws.isUpdatesMessage(data) {
 		ws.handleSpecialMessage(data)
@@ -469,7 +573,25 @@ func (ws *WebSocketClient) dispatchTypedEventContinued(handlers *models.WebSocke
 
 		return true
 
+	case models.EventTypeNameUpdated:
+		if handlers.OnNameUpdated != nil && event.NameUpdated != nil {
+			handlers.OnNameUpdated(event.NameUpdated)
+		}
+
+		return true
+
 	case models.EventTypeRecentsUpdated:
+		if handlers.OnRecentsUpdated != nil && event.RecentsUpdated != nil {
+			handlers.OnRecentsUpdated(event.RecentsUpdated)
+		}
+
+		return true
+
+	case models.EventTypeSourcesUpdated:
+		if handlers.OnSourcesUpdated != nil && event.SourcesUpdated != nil {
+			handlers.OnSourcesUpdated(event.SourcesUpdated)
+		}
+
 		return true
 
 	case models.EventTypeLanguageUpdated:
@@ -482,9 +604,23 @@ func (ws *WebSocketClient) dispatchTypedEventContinued(handlers *models.WebSocke
 
 // handleEvent dispatches events to appropriate handlers
 func (ws *WebSocketClient) handleEvent(event *models.WebSocketEvent) {
-	ws.mu.RLock()
+	ws.mu.Lock()
 	handlers := ws.handlers
-	ws.mu.RUnlock()
+
+	if ws.deviceID == "" {
+		ws.deviceID = event.DeviceID
+	}
+
+	observers := make([]models.EventHandler, 0, len(ws.observers))
+	for _, observer := range ws.observers {
+		observers = append(observers, observer)
+	}
+
+	ws.mu.Unlock()
+
+	for _, observer := range observers {
+		observer(event)
+	}
 
 	eventTypes := event.GetEventTypes()
 	hasKnownEvent := false
@@ -503,7 +639,8 @@ func (ws *WebSocketClient) handleEvent(event *models.WebSocketEvent) {
 	}
 }
 
-// SendMessage sends a message to the WebSocket (if needed for future functionality)
+// SendMessage sends a raw message to the WebSocket.
+// Use Request for API calls that expect a reply.
 func (ws *WebSocketClient) SendMessage(message []byte) error {
 	ws.mu.RLock()
 	conn := ws.conn
@@ -514,6 +651,9 @@ func (ws *WebSocketClient) SendMessage(message []byte) error {
 		return fmt.Errorf("not connected")
 	}
 
+	ws.writeMu.Lock()
+	defer ws.writeMu.Unlock()
+
 	_ = conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
 
 	return conn.WriteMessage(websocket.TextMessage, message)
@@ -0,0 +1,270 @@
+package client
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+// SetDeviceID sets the device ID used in request headers.
+// It is learned automatically from the first event, so setting it is only needed
+// when requests are sent before any event has arrived.
+func (ws *WebSocketClient) SetDeviceID(deviceID string) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	ws.deviceID = deviceID
+}
+
+// Request sends an API request over the WebSocket and waits for the matching reply.
+// The payload is marshalled to XML; the reply body is unmarshalled into result if it is not nil.
+func (ws *WebSocketClient) Request(method, path string, payload, result interface{}) error {
+	var body []byte
+
+	if payload != nil {
+		data, err := xml.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal XML request: %w", err)
+		}
+
+		body = data
+	}
+
+	reply, err := ws.roundTrip(method, path, body)
+	if err != nil {
+		return err
+	}
+
+	if reply.Body == nil || len(bytes.TrimSpace(reply.Body.Content)) == 0 {
+		return nil
+	}
+
+	content := reply.Body.Content
+
+	if bytes.HasPrefix(bytes.TrimSpace(content), []byte("<errors")) {
+		return parseWebSocketErrors(content)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if err := xml.Unmarshal(content, result); err != nil {
+		return fmt.Errorf("failed to unmarshal XML response: %w", err)
+	}
+
+	return nil
+}
+
+// roundTrip sends a request envelope and blocks until the reply or the timeout
+func (ws *WebSocketClient) roundTrip(method, path string, body []byte) (*models.WebSocketMessage, error) {
+	requestID := strconv.FormatUint(atomic.AddUint64(&ws.nextRequestID, 1), 10)
+
+	ws.mu.RLock()
+	deviceID := ws.deviceID
+	timeout := ws.requestTimeout
+	ws.mu.RUnlock()
+
+	data, err := xml.Marshal(models.NewWebSocketRequest(deviceID, method, path, requestID, body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request envelope: %w", err)
+	}
+
+	replyChan := make(chan *models.WebSocketMessage, 1)
+
+	ws.pendingMu.Lock()
+	ws.pending[requestID] = replyChan
+	ws.pendingMu.Unlock()
+
+	defer func() {
+		ws.pendingMu.Lock()
+		delete(ws.pending, requestID)
+		ws.pendingMu.Unlock()
+	}()
+
+	if err := ws.SendMessage(data); err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case reply, ok := <-replyChan:
+		if !ok {
+			return nil, fmt.Errorf("connection closed while waiting for reply to %s %s", method, path)
+		}
+
+		return reply, nil
+	case <-timer.C:
+		return nil, fmt.Errorf("timeout waiting for reply to %s %s after %v", method, path, timeout)
+	case <-ws.ctx.Done():
+		return nil, fmt.Errorf("websocket client closed")
+	}
+}
+
+// isReplyMessage checks if the message is a reply envelope, i.e. its root element is msg.
+// An XML declaration or comments before the root element are skipped.
+func (ws *WebSocketClient) isReplyMessage(data []byte) bool {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return false
+		}
+
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local == "msg"
+		}
+	}
+}
+
+// handleReply routes a reply envelope to the request waiting for it
+func (ws *WebSocketClient) handleReply(data []byte) {
+	reply, err := models.ParseWebSocketMessage(data)
+	if err != nil {
+		ws.logger.Printf("Failed to parse reply: %v", err)
+		return
+	}
+
+	requestID := reply.Header.Request.RequestID
+
+	ws.pendingMu.Lock()
+	replyChan, ok := ws.pending[requestID]
+	delete(ws.pending, requestID)
+	ws.pendingMu.Unlock()
+
+	if !ok {
+		ws.logger.Printf("Received reply for unknown request %q (%s %s)", requestID, reply.Header.Method, reply.Header.URL)
+		return
+	}
+
+	replyChan <- reply
+}
+
+// failPendingRequests releases all waiting requests after the connection was lost
+func (ws *WebSocketClient) failPendingRequests() {
+	ws.pendingMu.Lock()
+	defer ws.pendingMu.Unlock()
+
+	for requestID, replyChan := range ws.pending {
+		close(replyChan)
+		delete(ws.pending, requestID)
+	}
+}
+
+// parseWebSocketErrors converts an <errors> reply into an API error
+func parseWebSocketErrors(content []byte) error {
+	var wsErrors models.WebSocketErrors
+	if err := xml.Unmarshal(content, &wsErrors); err != nil || len(wsErrors.Errors) == 0 {
+		return &models.APIError{Message: strings.TrimSpace(string(content))}
+	}
+
+	first := wsErrors.Errors[0]
+	code, _ := strconv.Atoi(first.Value)
+
+	message := first.Name
+	if text := strings.TrimSpace(first.Text); text != "" {
+		message = fmt.Sprintf("%s: %s", first.Name, text)
+	}
+
+	return &models.APIError{Code: code, Message: message}
+}
+
+// GetVolume retrieves the current volume over the WebSocket
+func (ws *WebSocketClient) GetVolume() (*models.Volume, error) {
+	var volume models.Volume
+
+	if err := ws.Request("GET", "/volume", nil, &volume); err != nil {
+		return nil, fmt.Errorf("failed to get volume: %w", err)
+	}
+
+	return &volume, nil
+}
+
+// SetVolume sets the volume level over the WebSocket
+func (ws *WebSocketClient) SetVolume(level int) error {
+	if !models.ValidateVolumeLevel(level) {
+		return fmt.Errorf("invalid volume level: %d (must be 0-100)", level)
+	}
+
+	return ws.Request("POST", "/volume", models.NewVolumeRequest(level), nil)
+}
+
+// GetBass retrieves the current bass level over the WebSocket
+func (ws *WebSocketClient) GetBass() (*models.Bass, error) {
+	var bass models.Bass
+
+	if err := ws.Request("GET", "/bass", nil, &bass); err != nil {
+		return nil, fmt.Errorf("failed to get bass: %w", err)
+	}
+
+	return &bass, nil
+}
+
+// SetBass sets the bass level over the WebSocket
+func (ws *WebSocketClient) SetBass(level int) error {
+	bassReq, err := models.NewBassRequest(level)
+	if err != nil {
+		return err
+	}
+
+	return ws.Request("POST", "/bass", bassReq, nil)
+}
+
+// GetNowPlaying retrieves the current playback status over the WebSocket
+func (ws *WebSocketClient) GetNowPlaying() (*models.NowPlaying, error) {
+	var nowPlaying models.NowPlaying
+
+	if err := ws.Request("GET", "/now_playing", nil, &nowPlaying); err != nil {
+		return nil, fmt.Errorf("failed to get now playing: %w", err)
+	}
+
+	return &nowPlaying, nil
+}
+
+// GetPresets retrieves the configured presets over the WebSocket
+func (ws *WebSocketClient) GetPresets() (*models.Presets, error) {
+	var presets models.Presets
+
+	if err := ws.Request("GET", "/presets", nil, &presets); err != nil {
+		return nil, fmt.Errorf("failed to get presets: %w", err)
+	}
+
+	return &presets, nil
+}
+
+// GetZone retrieves the multiroom zone over the WebSocket
+func (ws *WebSocketClient) GetZone() (*models.ZoneInfo, error) {
+	var zone models.ZoneInfo
+
+	if err := ws.Request("GET", "/getZone", nil, &zone); err != nil {
+		return nil, fmt.Errorf("failed to get zone: %w", err)
+	}
+
+	return &zone, nil
+}
+
+// SendKey sends a key press followed by a release over the WebSocket
+func (ws *WebSocketClient) SendKey(keyValue string) error {
+	if !models.IsValidKey(keyValue) {
+		return fmt.Errorf("invalid key value: %s", keyValue)
+	}
+
+	if err := ws.Request("POST", "/key", models.NewKey(keyValue), nil); err != nil {
+		return fmt.Errorf("failed to send key press: %w", err)
+	}
+
+	if err := ws.Request("POST", "/key", models.NewKeyRelease(keyValue), nil); err != nil {
+		return fmt.Errorf("failed to send key release: %w", err)
+	}
+
+	return nil
+}
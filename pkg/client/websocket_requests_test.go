@@ -0,0 +1,258 @@
+package client
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/gorilla/websocket"
+)
+
+// setupRequestReplyServer starts a WebSocket server that answers request envelopes using reply
+func setupRequestReplyServer(t *testing.T, reply func(msg *models.WebSocketMessage) string) *httptest.Server {
+	t.Helper()
+
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(_ *http.Request) bool {
+			return true
+		},
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Failed to upgrade connection: %v", err)
+			return
+		}
+
+		defer func() {
+			_ = conn.Close()
+		}()
+
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			msg, err := models.ParseWebSocketMessage(data)
+			if err != nil {
+				t.Errorf("Server failed to parse request: %v", err)
+				return
+			}
+
+			if response := reply(msg); response != "" {
+				_ = conn.WriteMessage(websocket.TextMessage, []byte(response))
+			}
+		}
+	}))
+}
+
+// connectTestWebSocket attaches a WebSocket client to the given test server
+func connectTestWebSocket(t *testing.T, server *httptest.Server, timeout time.Duration) *WebSocketClient {
+	t.Helper()
+
+	config := DefaultWebSocketConfig()
+	config.Logger = &mockLogger{}
+	config.RequestTimeout = timeout
+
+	ws := createTestClient(server.URL).NewWebSocketClient(config)
+	ws.reconnect = false
+
+	conn, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if resp != nil && resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+
+	if err != nil {
+		t.Fatalf("Failed to dial test server: %v", err)
+	}
+
+	ws.conn = conn
+	ws.connected = true
+
+	go ws.readLoop(config)
+
+	t.Cleanup(func() {
+		_ = ws.Disconnect()
+	})
+
+	return ws
+}
+
+func replyEnvelope(msg *models.WebSocketMessage, body string) string {
+	return fmt.Sprintf(`<msg><header deviceID="%s" url="%s" method="%s"><request requestID="%s"><info responseType="OK" type="new" /></request></header><body>%s</body></msg>`,
+		msg.Header.DeviceID, msg.Header.URL, msg.Header.Method, msg.Header.Request.RequestID, body)
+}
+
+func TestWebSocketRequest_GetVolume(t *testing.T) {
+	server := setupRequestReplyServer(t, func(msg *models.WebSocketMessage) string {
+		if msg.Header.URL != "volume" || msg.Header.Method != "GET" {
+			t.Errorf("Unexpected request: %s %s", msg.Header.Method, msg.Header.URL)
+		}
+
+		if msg.Header.DeviceID != "ABCD1234EF56" {
+			t.Errorf("Expected device ID in header, got '%s'", msg.Header.DeviceID)
+		}
+
+		return replyEnvelope(msg, `<volume deviceID="ABCD1234EF56"><targetvolume>42</targetvolume><actualvolume>42</actualvolume><muteenabled>false</muteenabled></volume>`)
+	})
+	defer server.Close()
+
+	ws := connectTestWebSocket(t, server, time.Second)
+	ws.SetDeviceID("ABCD1234EF56")
+
+	volume, err := ws.GetVolume()
+	if err != nil {
+		t.Fatalf("GetVolume failed: %v", err)
+	}
+
+	if volume.ActualVolume != 42 {
+		t.Errorf("Expected volume 42, got %d", volume.ActualVolume)
+	}
+}
+
+func TestWebSocketRequest_SetVolumeSendsBody(t *testing.T) {
+	bodies := make(chan string, 1)
+
+	server := setupRequestReplyServer(t, func(msg *models.WebSocketMessage) string {
+		if msg.Body != nil {
+			bodies <- string(msg.Body.Content)
+		}
+
+		return replyEnvelope(msg, `<status>/volume</status>`)
+	})
+	defer server.Close()
+
+	ws := connectTestWebSocket(t, server, time.Second)
+
+	if err := ws.SetVolume(25); err != nil {
+		t.Fatalf("SetVolume failed: %v", err)
+	}
+
+	select {
+	case body := <-bodies:
+		if body != "<volume>25</volume>" {
+			t.Errorf("Unexpected request body: %s", body)
+		}
+	default:
+		t.Error("Expected request body to be sent")
+	}
+
+	if err := ws.SetVolume(101); err == nil {
+		t.Error("Expected validation error for volume 101")
+	}
+}
+
+func TestWebSocketRequest_ErrorReply(t *testing.T) {
+	server := setupRequestReplyServer(t, func(msg *models.WebSocketMessage) string {
+		return replyEnvelope(msg, `<errors deviceID="ABCD1234EF56"><error value="1019" name="CLIENT_XML_ERROR" severity="Unknown">invalid bass level</error></errors>`)
+	})
+	defer server.Close()
+
+	ws := connectTestWebSocket(t, server, time.Second)
+
+	err := ws.SetBass(0)
+	if err == nil {
+		t.Fatal("Expected error reply to be returned")
+	}
+
+	var apiErr *models.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected APIError, got %T: %v", err, err)
+	}
+
+	if apiErr.Code != 1019 || !strings.Contains(apiErr.Message, "CLIENT_XML_ERROR") {
+		t.Errorf("Unexpected error contents: %+v", apiErr)
+	}
+}
+
+func TestWebSocketRequest_Timeout(t *testing.T) {
+	server := setupRequestReplyServer(t, func(_ *models.WebSocketMessage) string {
+		return ""
+	})
+	defer server.Close()
+
+	ws := connectTestWebSocket(t, server, 50*time.Millisecond)
+
+	_, err := ws.GetNowPlaying()
+	if err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Fatalf("Expected timeout error, got %v", err)
+	}
+
+	ws.pendingMu.Lock()
+	pending := len(ws.pending)
+	ws.pendingMu.Unlock()
+
+	if pending != 0 {
+		t.Errorf("Expected pending requests to be cleaned up, got %d", pending)
+	}
+}
+
+func TestWebSocketRequest_RepliesDoNotReachEventHandlers(t *testing.T) {
+	ws := createTestClient("http://localhost").NewWebSocketClient(&WebSocketConfig{Logger: &mockLogger{}})
+
+	called := false
+
+	ws.OnUnknownEvent(func(_ *models.WebSocketEvent) {
+		called = true
+	})
+	ws.OnSpecialMessage(func(_ *models.SpecialMessage) {
+		called = true
+	})
+
+	ws.handleMessage([]byte(`<msg><header deviceID="X" url="volume" method="GET"><request requestID="99"><info type="new" /></request></header><body><volume /></body></msg>`))
+
+	if called {
+		t.Error("Expected unmatched reply not to be dispatched as an event")
+	}
+}
+
+func TestWebSocketClient_IsReplyMessage(t *testing.T) {
+	ws := createTestClient("http://localhost").NewWebSocketClient(&WebSocketConfig{Logger: &mockLogger{}})
+
+	tests := map[string]bool{
+		`<msg><header /></msg>`:                                                          true,
+		"\n  <msg><header /></msg>":                                                      true,
+		`<?xml version="1.0" encoding="UTF-8" ?><msg><header /></msg>`:                   true,
+		"<?xml version=\"1.0\" encoding=\"UTF-8\" ?>\n<!-- reply --><msg></msg>":         true,
+		`<updates deviceID="X"><volumeUpdated /></updates>`:                              false,
+		`<?xml version="1.0" encoding="UTF-8" ?><SoundTouchSdkInfo serverVersion="4" />`: false,
+		`<msgs />`: false,
+		`not xml`:  false,
+	}
+
+	for data, expected := range tests {
+		if got := ws.isReplyMessage([]byte(data)); got != expected {
+			t.Errorf("isReplyMessage(%q) = %v, expected %v", data, got, expected)
+		}
+	}
+}
+
+func TestWebSocketRequest_NotConnected(t *testing.T) {
+	ws := createTestClient("http://localhost").NewWebSocketClient(&WebSocketConfig{Logger: &mockLogger{}})
+
+	if _, err := ws.GetVolume(); err == nil {
+		t.Error("Expected error when not connected")
+	}
+}
+
+func TestNewWebSocketRequest(t *testing.T) {
+	msg := models.NewWebSocketRequest("DEV", "POST", "/key", "7", []byte(`<key state="press" sender="Gabbo">PLAY</key>`))
+
+	data, err := xml.Marshal(msg)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+
+	expected := `<msg><header deviceID="DEV" url="key" method="POST"><request requestID="7"><info type="new"></info></request></header><body><key state="press" sender="Gabbo">PLAY</key></body></msg>`
+	if string(data) != expected {
+		t.Errorf("Unexpected envelope:\n got: %s\nwant: %s", data, expected)
+	}
+}
@@ -0,0 +1,306 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+// ZoneMemberStatus describes what happened to a speaker while a zone definition was applied
+type ZoneMemberStatus string
+
+const (
+	// ZoneMemberMaster marks the speaker acting as master of the applied zone
+	ZoneMemberMaster ZoneMemberStatus = "master"
+	// ZoneMemberPresent marks a speaker that already was part of the zone
+	ZoneMemberPresent ZoneMemberStatus = "already in zone"
+	// ZoneMemberAdded marks a speaker that was added to the zone
+	ZoneMemberAdded ZoneMemberStatus = "added"
+	// ZoneMemberFailed marks a speaker that could not be added to the zone
+	ZoneMemberFailed ZoneMemberStatus = "failed"
+)
+
+// ZoneMemberOutcome is the result of applying a zone definition to a single speaker
+type ZoneMemberOutcome struct {
+	Ref       string
+	Speaker   *ZoneSpeaker
+	Status    ZoneMemberStatus
+	PoweredOn bool
+	LeftZone  string
+	Err       error
+}
+
+// String returns a one-line description of the outcome
+func (o *ZoneMemberOutcome) String() string {
+	name := o.Ref
+	if o.Speaker != nil {
+		name = o.Speaker.String()
+	}
+
+	var notes []string
+
+	if o.LeftZone != "" {
+		notes = append(notes, fmt.Sprintf("left zone %s", o.LeftZone))
+	}
+
+	if o.PoweredOn {
+		notes = append(notes, "powered on")
+	}
+
+	if o.Err != nil {
+		notes = append(notes, o.Err.Error())
+	}
+
+	if len(notes) == 0 {
+		return fmt.Sprintf("%s: %s", name, o.Status)
+	}
+
+	return fmt.Sprintf("%s: %s (%s)", name, o.Status, strings.Join(notes, ", "))
+}
+
+// ZoneApplyResult holds the resulting zone and the outcome for every speaker of a zone definition
+type ZoneApplyResult struct {
+	Zone     *models.ZoneInfo
+	Outcomes []*ZoneMemberOutcome
+}
+
+// Failed reports whether any speaker could not be added to the zone
+func (r *ZoneApplyResult) Failed() bool {
+	for _, outcome := range r.Outcomes {
+		if outcome.Status == ZoneMemberFailed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ApplyDefinition reconciles the live topology with a zone definition.
+// Zones conflicting with the definition are dissolved, speakers in standby are powered on
+// and missing members are added. An error is only returned if the master cannot be set up;
+// members that fail are reported in the result.
+func (m *ZoneManager) ApplyDefinition(definition *models.ZoneDefinition) (*ZoneApplyResult, error) {
+	if err := definition.Validate(); err != nil {
+		return nil, err
+	}
+
+	master, err := m.resolveIdentified(definition.Master)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ZoneApplyResult{}
+	masterOutcome := &ZoneMemberOutcome{Ref: definition.Master, Speaker: master, Status: ZoneMemberMaster}
+	result.Outcomes = append(result.Outcomes, masterOutcome)
+
+	wanted := map[string]bool{strings.ToUpper(master.DeviceID): true}
+	byDevice := map[string]*ZoneMemberOutcome{strings.ToUpper(master.DeviceID): masterOutcome}
+
+	for _, ref := range definition.Members {
+		outcome := &ZoneMemberOutcome{Ref: ref}
+		result.Outcomes = append(result.Outcomes, outcome)
+
+		speaker, err := m.resolveIdentified(ref)
+		if err != nil {
+			outcome.Status = ZoneMemberFailed
+			outcome.Err = err
+
+			continue
+		}
+
+		outcome.Speaker = speaker
+		wanted[strings.ToUpper(speaker.DeviceID)] = true
+		byDevice[strings.ToUpper(speaker.DeviceID)] = outcome
+	}
+
+	// Dissolve every zone that mixes wanted speakers with others or is led by another master
+	for _, outcome := range result.Outcomes {
+		if outcome.Speaker == nil {
+			continue
+		}
+
+		if err := m.releaseConflictingZone(outcome.Speaker, master, wanted, byDevice); err != nil {
+			if outcome == masterOutcome {
+				return nil, err
+			}
+
+			outcome.Status = ZoneMemberFailed
+			outcome.Err = err
+		}
+	}
+
+	for _, outcome := range result.Outcomes {
+		if outcome.Speaker == nil || outcome.Status == ZoneMemberFailed {
+			continue
+		}
+
+		poweredOn, err := m.ensurePoweredOn(outcome.Speaker)
+		outcome.PoweredOn = poweredOn
+
+		if err != nil {
+			if outcome == masterOutcome {
+				return nil, err
+			}
+
+			outcome.Status = ZoneMemberFailed
+			outcome.Err = err
+		}
+	}
+
+	zone, err := m.client(master).GetZone()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get zone of %s: %w", master, err)
+	}
+
+	var missing []*ZoneMemberOutcome
+
+	for _, outcome := range result.Outcomes[1:] {
+		if outcome.Speaker == nil || outcome.Status == ZoneMemberFailed {
+			continue
+		}
+
+		if !zone.IsStandalone() && zone.IsInZone(outcome.Speaker.DeviceID) {
+			outcome.Status = ZoneMemberPresent
+			continue
+		}
+
+		missing = append(missing, outcome)
+	}
+
+	result.Zone = zone
+
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	if zone.IsStandalone() {
+		members := make([]*ZoneSpeaker, 0, len(missing))
+		for _, outcome := range missing {
+			members = append(members, outcome.Speaker)
+		}
+
+		zone, err := m.setZone(models.ZoneOpCreate, master, members)
+		for _, outcome := range missing {
+			outcome.Status = ZoneMemberAdded
+			if err != nil {
+				outcome.Status = ZoneMemberFailed
+				outcome.Err = err
+			}
+		}
+
+		if zone != nil {
+			result.Zone = zone
+		}
+
+		return result, nil
+	}
+
+	expected := make(map[string]bool)
+	for _, deviceID := range zone.GetAllDeviceIDs() {
+		expected[strings.ToUpper(deviceID)] = true
+	}
+
+	expected[strings.ToUpper(master.DeviceID)] = true
+
+	for _, outcome := range missing {
+		speaker := outcome.Speaker
+		deviceID := strings.ToUpper(speaker.DeviceID)
+		expected[deviceID] = true
+
+		confirmed, err := m.applyAndConfirm(models.ZoneOpAddMember, master, expected, func() error {
+			return m.client(master).AddZoneSlave(master.DeviceID, speaker.DeviceID, ZoneMemberIP(speaker.Host))
+		})
+		if err != nil {
+			delete(expected, deviceID)
+
+			outcome.Status = ZoneMemberFailed
+			outcome.Err = err
+
+			continue
+		}
+
+		outcome.Status = ZoneMemberAdded
+		result.Zone = confirmed
+	}
+
+	return result, nil
+}
+
+// releaseConflictingZone dissolves the zone of a speaker unless it is led by the master and only contains wanted speakers
+func (m *ZoneManager) releaseConflictingZone(speaker, master *ZoneSpeaker, wanted map[string]bool, byDevice map[string]*ZoneMemberOutcome) error {
+	zone, zoneMaster, err := m.ZoneOf(speaker.DeviceID)
+	if err != nil {
+		return err
+	}
+
+	if zone.IsStandalone() {
+		return nil
+	}
+
+	if strings.EqualFold(zoneMaster.DeviceID, master.DeviceID) && zoneWithin(zone, wanted) {
+		return nil
+	}
+
+	summary := m.ZoneSummary(zone)
+
+	if _, err := m.setZone(models.ZoneOpDissolve, zoneMaster, nil); err != nil {
+		return err
+	}
+
+	for _, deviceID := range zone.GetAllDeviceIDs() {
+		if outcome, ok := byDevice[strings.ToUpper(deviceID)]; ok {
+			outcome.LeftZone = summary
+		}
+	}
+
+	return nil
+}
+
+// ensurePoweredOn wakes a speaker from standby and reports whether it had to be powered on
+func (m *ZoneManager) ensurePoweredOn(speaker *ZoneSpeaker) (bool, error) {
+	c := m.client(speaker)
+
+	nowPlaying, err := c.GetNowPlaying()
+	if err != nil {
+		return false, fmt.Errorf("failed to get now playing on %s: %w", speaker, err)
+	}
+
+	if nowPlaying.Source != "STANDBY" {
+		return false, nil
+	}
+
+	if err := c.SendKey(models.KeyPower); err != nil {
+		return false, fmt.Errorf("failed to power on %s: %w", speaker, err)
+	}
+
+	m.mu.Lock()
+	timeout := m.verifyTimeout
+	interval := m.pollInterval
+	m.mu.Unlock()
+
+	deadline := time.Now().Add(timeout)
+
+	for time.Now().Before(deadline) {
+		nowPlaying, err := c.GetNowPlaying()
+		if err == nil && nowPlaying.Source != "STANDBY" {
+			return true, nil
+		}
+
+		time.Sleep(interval)
+	}
+
+	return true, fmt.Errorf("%s did not leave standby", speaker)
+}
+
+// zoneWithin reports whether every device of the zone is wanted
+func zoneWithin(zone *models.ZoneInfo, wanted map[string]bool) bool {
+	for _, deviceID := range zone.GetAllDeviceIDs() {
+		if !wanted[strings.ToUpper(deviceID)] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+func TestZoneManager_ApplyDefinition(t *testing.T) {
+	network, manager := newFakeZoneNetwork(t, "Kitchen", "Living Room", "Office", "Bedroom")
+	network.byName("Kitchen").content = &models.ContentItem{Source: "TUNEIN", Location: "/v1/playback/station/s1", ItemName: "Radio"}
+	network.byName("Bedroom").content = &models.ContentItem{Source: "AUX", ItemName: "AUX IN"}
+
+	// The office is currently grouped with the bedroom, which conflicts with the definition
+	if _, err := manager.CreateZone("Bedroom", "Office"); err != nil {
+		t.Fatalf("CreateZone failed: %v", err)
+	}
+
+	if _, err := manager.CreateZone("Kitchen", "Living Room"); err != nil {
+		t.Fatalf("CreateZone failed: %v", err)
+	}
+
+	definition := &models.ZoneDefinition{Name: "downstairs", Master: "Kitchen", Members: []string{"Living Room", "Office", "Garage"}}
+
+	result, err := manager.ApplyDefinition(definition)
+	if err != nil {
+		t.Fatalf("ApplyDefinition failed: %v", err)
+	}
+
+	if summary := manager.ZoneSummary(result.Zone); summary != "Kitchen + Living Room, Office" {
+		t.Errorf("Unexpected zone: %s", summary)
+	}
+
+	expected := map[string]ZoneMemberStatus{
+		"Kitchen":     ZoneMemberMaster,
+		"Living Room": ZoneMemberPresent,
+		"Office":      ZoneMemberAdded,
+		"Garage":      ZoneMemberFailed,
+	}
+
+	for _, outcome := range result.Outcomes {
+		if outcome.Status != expected[outcome.Ref] {
+			t.Errorf("Expected %s to be %q, got %s", outcome.Ref, expected[outcome.Ref], outcome)
+		}
+
+		if outcome.Ref == "Office" && !strings.Contains(outcome.String(), "left zone Bedroom + Office") {
+			t.Errorf("Expected office to report leaving the bedroom zone, got %s", outcome)
+		}
+	}
+
+	if !result.Failed() {
+		t.Error("Expected result to report the unknown speaker as failure")
+	}
+
+	zone, _, _ := manager.ZoneOf("Bedroom")
+	if !zone.IsStandalone() {
+		t.Errorf("Expected conflicting bedroom zone to be dissolved, got %s", zone)
+	}
+}
+
+func TestZoneManager_ApplyDefinitionPowersOnMaster(t *testing.T) {
+	network, manager := newFakeZoneNetwork(t, "Kitchen", "Office")
+
+	result, err := manager.ApplyDefinition(&models.ZoneDefinition{Name: "small", Master: "Kitchen", Members: []string{"Office"}})
+	if err != nil {
+		t.Fatalf("ApplyDefinition failed: %v", err)
+	}
+
+	if !result.Outcomes[0].PoweredOn {
+		t.Errorf("Expected master to be powered on, got %s", result.Outcomes[0])
+	}
+
+	if result.Failed() || result.Outcomes[1].Status != ZoneMemberAdded {
+		t.Errorf("Expected office to be added, got %s", result.Outcomes[1])
+	}
+
+	if content := network.byName("Office").content; content == nil || content.Source != "AUX" {
+		t.Errorf("Expected office to play the master's content, got %+v", content)
+	}
+
+	// Applying again is a no-op
+	result, err = manager.ApplyDefinition(&models.ZoneDefinition{Name: "small", Master: "Kitchen", Members: []string{"Office"}})
+	if err != nil {
+		t.Fatalf("ApplyDefinition failed: %v", err)
+	}
+
+	if result.Outcomes[0].PoweredOn || result.Outcomes[1].Status != ZoneMemberPresent {
+		t.Errorf("Expected unchanged zone, got %s / %s", result.Outcomes[0], result.Outcomes[1])
+	}
+}
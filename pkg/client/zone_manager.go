@@ -0,0 +1,735 @@
+package client
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+const (
+	defaultZoneVerifyTimeout = 10 * time.Second
+	defaultZonePollInterval  = 500 * time.Millisecond
+)
+
+// ZoneSpeaker is a speaker the zone manager can address by name, alias, host or device ID
+type ZoneSpeaker struct {
+	Name     string
+	DeviceID string
+	Host     string
+	Port     int
+}
+
+// String returns the speaker name, falling back to its host
+func (s *ZoneSpeaker) String() string {
+	if s.Name != "" {
+		return s.Name
+	}
+
+	return s.Host
+}
+
+// SpeakerResolver maps speaker references that match no known speaker, such as an IP address
+// the speaker had before a DHCP lease change, to the device ID and current address of a speaker
+type SpeakerResolver interface {
+	ResolveSpeaker(ref string) (deviceID, host string, port int, ok bool)
+}
+
+// ZoneManager builds and changes multiroom zones using speaker names instead of device IDs and IPs.
+// Every change is confirmed against the resulting zone reported by the master.
+type ZoneManager struct {
+	mu       sync.Mutex
+	speakers []*ZoneSpeaker
+	aliases  map[string]string
+	clients  *ClientPool
+	resolver SpeakerResolver
+
+	verifyTimeout time.Duration
+	pollInterval  time.Duration
+	useEvents     bool
+}
+
+// NewZoneManager creates a zone manager for the given discovered devices
+func NewZoneManager(devices []*models.DiscoveredDevice) *ZoneManager {
+	m := &ZoneManager{
+		aliases:       make(map[string]string),
+		clients:       NewClientPool(DefaultConfig().Timeout),
+		verifyTimeout: defaultZoneVerifyTimeout,
+		pollInterval:  defaultZonePollInterval,
+	}
+
+	for _, device := range devices {
+		m.AddSpeaker(ZoneSpeaker{
+			Name: device.Name,
+			Host: device.Host,
+			Port: device.Port,
+		})
+	}
+
+	return m
+}
+
+// AddSpeaker adds a speaker; a speaker with the same host is replaced
+func (m *ZoneManager) AddSpeaker(speaker ZoneSpeaker) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if speaker.Port == 0 {
+		speaker.Port = 8090
+	}
+
+	for i, existing := range m.speakers {
+		if existing.Host == speaker.Host && existing.Port == speaker.Port {
+			m.speakers[i] = &speaker
+			return
+		}
+	}
+
+	m.speakers = append(m.speakers, &speaker)
+}
+
+// SetAlias makes alias refer to the speaker matched by target
+func (m *ZoneManager) SetAlias(alias, target string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.aliases[strings.ToLower(alias)] = target
+}
+
+// SetResolver sets a resolver that is asked for references matching no known speaker
+func (m *ZoneManager) SetResolver(resolver SpeakerResolver) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.resolver = resolver
+}
+
+// SetVerifyTimeout sets how long to wait for a zone change to be confirmed
+func (m *ZoneManager) SetVerifyTimeout(timeout time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.verifyTimeout = timeout
+}
+
+// SetPollInterval sets how often the zone is read while waiting for confirmation
+func (m *ZoneManager) SetPollInterval(interval time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.pollInterval = interval
+}
+
+// SetEventsEnabled makes the manager also listen for zoneUpdated events on the master while confirming changes
+func (m *ZoneManager) SetEventsEnabled(enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.useEvents = enabled
+}
+
+// Speakers returns the known speakers
+func (m *ZoneManager) Speakers() []ZoneSpeaker {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	speakers := make([]ZoneSpeaker, 0, len(m.speakers))
+	for _, speaker := range m.speakers {
+		speakers = append(speakers, *speaker)
+	}
+
+	return speakers
+}
+
+// Resolve finds a speaker by device ID, host, name or alias (case-insensitive)
+func (m *ZoneManager) Resolve(ref string) (*ZoneSpeaker, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil, fmt.Errorf("speaker reference cannot be empty")
+	}
+
+	m.mu.Lock()
+	target, isAlias := m.aliases[strings.ToLower(ref)]
+	m.mu.Unlock()
+
+	if isAlias {
+		ref = target
+	}
+
+	if speaker := m.match(ref); speaker != nil {
+		return speaker, nil
+	}
+
+	// Device IDs are only known after asking the speakers
+	m.identifyAll()
+
+	if speaker := m.match(ref); speaker != nil {
+		return speaker, nil
+	}
+
+	if speaker := m.resolveElsewhere(ref); speaker != nil {
+		return speaker, nil
+	}
+
+	return nil, models.NewZoneError(models.ZoneOpModify, ref, models.ZoneErrorDeviceNotFound)
+}
+
+// resolveElsewhere asks the resolver for a reference and adds the speaker it points to if needed
+func (m *ZoneManager) resolveElsewhere(ref string) *ZoneSpeaker {
+	m.mu.Lock()
+	resolver := m.resolver
+	m.mu.Unlock()
+
+	if resolver == nil {
+		return nil
+	}
+
+	deviceID, host, port, ok := resolver.ResolveSpeaker(ref)
+	if !ok || deviceID == "" {
+		return nil
+	}
+
+	if speaker := m.match(deviceID); speaker != nil && strings.EqualFold(speaker.DeviceID, deviceID) {
+		return speaker
+	}
+
+	m.AddSpeaker(ZoneSpeaker{DeviceID: deviceID, Host: host, Port: port})
+
+	return m.match(deviceID)
+}
+
+// match looks up a speaker among the known ones without contacting devices
+func (m *ZoneManager) match(ref string) *ZoneSpeaker {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, speaker := range m.speakers {
+		if speaker.DeviceID != "" && strings.EqualFold(speaker.DeviceID, ref) {
+			return speaker
+		}
+	}
+
+	for _, speaker := range m.speakers {
+		if speaker.Host == ref || net.JoinHostPort(speaker.Host, fmt.Sprint(speaker.Port)) == ref {
+			return speaker
+		}
+	}
+
+	for _, speaker := range m.speakers {
+		if strings.EqualFold(speaker.Name, ref) {
+			return speaker
+		}
+	}
+
+	return nil
+}
+
+// identifyAll fills in missing device IDs and names from /info
+func (m *ZoneManager) identifyAll() {
+	for _, speaker := range m.snapshot() {
+		if speaker.DeviceID != "" && speaker.Name != "" {
+			continue
+		}
+
+		_ = m.identify(speaker)
+	}
+}
+
+// identify fills in the device ID and name of a speaker from /info
+func (m *ZoneManager) identify(speaker *ZoneSpeaker) error {
+	info, err := m.client(speaker).GetDeviceInfo()
+	if err != nil {
+		return fmt.Errorf("failed to identify %s: %w", speaker, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	speaker.DeviceID = info.DeviceID
+
+	if speaker.Name == "" {
+		speaker.Name = info.Name
+	}
+
+	return nil
+}
+
+// resolveIdentified resolves a speaker and makes sure its device ID is known
+func (m *ZoneManager) resolveIdentified(ref string) (*ZoneSpeaker, error) {
+	speaker, err := m.Resolve(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if speaker.DeviceID == "" {
+		if err := m.identify(speaker); err != nil {
+			return nil, err
+		}
+	}
+
+	return speaker, nil
+}
+
+func (m *ZoneManager) snapshot() []*ZoneSpeaker {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]*ZoneSpeaker(nil), m.speakers...)
+}
+
+// byDeviceID returns the known speaker with the given device ID
+func (m *ZoneManager) byDeviceID(deviceID string) *ZoneSpeaker {
+	if deviceID == "" {
+		return nil
+	}
+
+	if speaker := m.match(deviceID); speaker != nil && strings.EqualFold(speaker.DeviceID, deviceID) {
+		return speaker
+	}
+
+	m.identifyAll()
+
+	if speaker := m.match(deviceID); speaker != nil && strings.EqualFold(speaker.DeviceID, deviceID) {
+		return speaker
+	}
+
+	return nil
+}
+
+// client returns a cached API client for a speaker
+func (m *ZoneManager) client(speaker *ZoneSpeaker) *Client {
+	return m.clients.Get(speaker.Host, speaker.Port)
+}
+
+// ZoneOf returns the zone a speaker belongs to and the speaker acting as its master.
+// For a standalone speaker the speaker itself is returned as master.
+func (m *ZoneManager) ZoneOf(ref string) (*models.ZoneInfo, *ZoneSpeaker, error) {
+	speaker, err := m.resolveIdentified(ref)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	zone, err := m.client(speaker).GetZone()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get zone of %s: %w", speaker, err)
+	}
+
+	if zone.Master == "" || strings.EqualFold(zone.Master, speaker.DeviceID) {
+		return zone, speaker, nil
+	}
+
+	master := m.byDeviceID(zone.Master)
+	if master == nil {
+		return nil, nil, models.NewZoneError(models.ZoneOpModify, zone.Master, models.ZoneErrorDeviceNotFound)
+	}
+
+	// Slaves may only report a partial zone; the master has the full picture
+	masterZone, err := m.client(master).GetZone()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get zone of master %s: %w", master, err)
+	}
+
+	return masterZone, master, nil
+}
+
+// CreateZone groups the members under the master, replacing the master's current zone
+func (m *ZoneManager) CreateZone(masterRef string, memberRefs ...string) (*models.ZoneInfo, error) {
+	master, err := m.resolveIdentified(masterRef)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]*ZoneSpeaker, 0, len(memberRefs))
+
+	for _, ref := range memberRefs {
+		member, err := m.resolveIdentified(ref)
+		if err != nil {
+			return nil, err
+		}
+
+		if member.DeviceID == master.DeviceID {
+			continue
+		}
+
+		members = append(members, member)
+	}
+
+	if len(members) == 0 {
+		return nil, models.NewZoneError(models.ZoneOpCreate, master.DeviceID, "at least one member besides the master is required")
+	}
+
+	return m.setZone(models.ZoneOpCreate, master, members)
+}
+
+// MoveMusic makes the target speaker the new master of the zone playing on the source speaker.
+// The current content is started on the target and all speakers of the old zone join it.
+func (m *ZoneManager) MoveMusic(fromRef, toRef string) (*models.ZoneInfo, error) {
+	zone, oldMaster, err := m.ZoneOf(fromRef)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := m.resolveIdentified(toRef)
+	if err != nil {
+		return nil, err
+	}
+
+	if oldMaster.DeviceID == target.DeviceID {
+		return zone, nil
+	}
+
+	content, err := m.currentContent(oldMaster)
+	if err != nil {
+		return nil, err
+	}
+
+	members := []*ZoneSpeaker{oldMaster}
+
+	for _, deviceID := range zone.GetAllDeviceIDs() {
+		if strings.EqualFold(deviceID, oldMaster.DeviceID) || strings.EqualFold(deviceID, target.DeviceID) {
+			continue
+		}
+
+		member := m.byDeviceID(deviceID)
+		if member == nil {
+			return nil, models.NewZoneError(models.ZoneOpModify, deviceID, models.ZoneErrorDeviceNotFound)
+		}
+
+		members = append(members, member)
+	}
+
+	if !zone.IsInZone(target.DeviceID) {
+		if err := m.SplitOff(target.DeviceID); err != nil {
+			return nil, err
+		}
+	}
+
+	if !zone.IsStandalone() {
+		if _, err := m.setZone(models.ZoneOpDissolve, oldMaster, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := m.client(target).SelectContentItem(content); err != nil {
+		return nil, fmt.Errorf("failed to start playback on %s: %w", target, err)
+	}
+
+	return m.setZone(models.ZoneOpCreate, target, members)
+}
+
+// Join adds a speaker to the zone playing on another speaker.
+// If the speaker is currently part of a different zone, it is split off first.
+func (m *ZoneManager) Join(speakerRef, playingRef string) (*models.ZoneInfo, error) {
+	speaker, err := m.resolveIdentified(speakerRef)
+	if err != nil {
+		return nil, err
+	}
+
+	zone, master, err := m.ZoneOf(playingRef)
+	if err != nil {
+		return nil, err
+	}
+
+	if zone.IsInZone(speaker.DeviceID) && !zone.IsStandalone() {
+		return zone, nil
+	}
+
+	if master.DeviceID == speaker.DeviceID {
+		return nil, models.NewZoneError(models.ZoneOpAddMember, speaker.DeviceID, "cannot join its own zone")
+	}
+
+	if err := m.SplitOff(speaker.DeviceID); err != nil {
+		return nil, err
+	}
+
+	members := []*ZoneSpeaker{speaker}
+
+	for _, member := range zone.Members {
+		if strings.EqualFold(member.DeviceID, master.DeviceID) {
+			continue
+		}
+
+		existing := m.byDeviceID(member.DeviceID)
+		if existing == nil {
+			existing = &ZoneSpeaker{DeviceID: member.DeviceID, Host: member.IP, Port: 8090}
+		}
+
+		members = append(members, existing)
+	}
+
+	if zone.IsStandalone() {
+		return m.setZone(models.ZoneOpCreate, master, members)
+	}
+
+	expected := zoneDeviceSet(master, members)
+
+	return m.applyAndConfirm(models.ZoneOpAddMember, master, expected, func() error {
+		return m.client(master).AddZoneSlave(master.DeviceID, speaker.DeviceID, ZoneMemberIP(speaker.Host))
+	})
+}
+
+// SplitOff removes a speaker from its zone without stopping playback on the remaining speakers.
+// If the speaker is the master, the first remaining member takes over the current content.
+func (m *ZoneManager) SplitOff(speakerRef string) error {
+	zone, master, err := m.ZoneOf(speakerRef)
+	if err != nil {
+		return err
+	}
+
+	if zone.IsStandalone() {
+		return nil
+	}
+
+	speaker, err := m.resolveIdentified(speakerRef)
+	if err != nil {
+		return err
+	}
+
+	if master.DeviceID != speaker.DeviceID {
+		remaining := make(map[string]bool)
+
+		for _, deviceID := range zone.GetAllDeviceIDs() {
+			if !strings.EqualFold(deviceID, speaker.DeviceID) {
+				remaining[strings.ToUpper(deviceID)] = true
+			}
+		}
+
+		_, err := m.applyAndConfirm(models.ZoneOpRemove, master, remaining, func() error {
+			return m.client(master).RemoveZoneSlave(master.DeviceID, speaker.DeviceID, ZoneMemberIP(speaker.Host))
+		})
+
+		return err
+	}
+
+	// The master leaves: hand the content over to the first remaining member
+	var rest []*ZoneSpeaker
+
+	for _, member := range zone.Members {
+		if strings.EqualFold(member.DeviceID, master.DeviceID) {
+			continue
+		}
+
+		existing := m.byDeviceID(member.DeviceID)
+		if existing == nil {
+			return models.NewZoneError(models.ZoneOpRemove, member.DeviceID, models.ZoneErrorDeviceNotFound)
+		}
+
+		rest = append(rest, existing)
+	}
+
+	content, err := m.currentContent(master)
+	if err != nil {
+		return err
+	}
+
+	if _, err := m.setZone(models.ZoneOpDissolve, master, nil); err != nil {
+		return err
+	}
+
+	if len(rest) == 0 {
+		return nil
+	}
+
+	newMaster := rest[0]
+	if err := m.client(newMaster).SelectContentItem(content); err != nil {
+		return fmt.Errorf("failed to continue playback on %s: %w", newMaster, err)
+	}
+
+	if len(rest) > 1 {
+		if _, err := m.setZone(models.ZoneOpCreate, newMaster, rest[1:]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Dissolve ends the zone the speaker belongs to
+func (m *ZoneManager) Dissolve(speakerRef string) error {
+	zone, master, err := m.ZoneOf(speakerRef)
+	if err != nil {
+		return err
+	}
+
+	if zone.IsStandalone() {
+		return nil
+	}
+
+	_, err = m.setZone(models.ZoneOpDissolve, master, nil)
+
+	return err
+}
+
+// currentContent returns the content item playing on a speaker
+func (m *ZoneManager) currentContent(speaker *ZoneSpeaker) (*models.ContentItem, error) {
+	nowPlaying, err := m.client(speaker).GetNowPlaying()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get now playing on %s: %w", speaker, err)
+	}
+
+	if nowPlaying.ContentItem == nil || nowPlaying.ContentItem.Source == "" || nowPlaying.Source == "STANDBY" {
+		return nil, fmt.Errorf("nothing is playing on %s", speaker)
+	}
+
+	return nowPlaying.ContentItem, nil
+}
+
+// setZone sends a complete zone configuration to the master and waits for it to be confirmed
+func (m *ZoneManager) setZone(op models.ZoneOperation, master *ZoneSpeaker, members []*ZoneSpeaker) (*models.ZoneInfo, error) {
+	request := models.NewZoneRequest(master.DeviceID)
+	for _, member := range members {
+		request.AddMember(member.DeviceID, ZoneMemberIP(member.Host))
+	}
+
+	return m.applyAndConfirm(op, master, zoneDeviceSet(master, members), func() error {
+		return m.client(master).SetZone(request)
+	})
+}
+
+// applyAndConfirm runs a zone change on the master and waits until the master reports the expected devices
+func (m *ZoneManager) applyAndConfirm(op models.ZoneOperation, master *ZoneSpeaker, expected map[string]bool, apply func() error) (*models.ZoneInfo, error) {
+	m.mu.Lock()
+	timeout := m.verifyTimeout
+	interval := m.pollInterval
+	useEvents := m.useEvents
+	m.mu.Unlock()
+
+	events := make(chan *models.ZoneInfo, 8)
+
+	if useEvents {
+		ws := m.client(master).NewWebSocketClient(&WebSocketConfig{Logger: DiscardLogger{}})
+		ws.reconnect = false
+
+		ws.OnZoneUpdated(func(event *models.ZoneUpdatedEvent) {
+			select {
+			case events <- zoneInfoFromEvent(&event.Zone):
+			default:
+			}
+		})
+
+		if err := ws.Connect(); err == nil {
+			defer func() { _ = ws.Disconnect() }()
+		}
+	}
+
+	if err := apply(); err != nil {
+		return nil, models.NewZoneError(op, master.DeviceID, err.Error())
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last *models.ZoneInfo
+
+	for {
+		zone, err := m.client(master).GetZone()
+		if err == nil {
+			last = zone
+			if zoneMatches(zone, master.DeviceID, expected) {
+				return zone, nil
+			}
+		}
+
+		select {
+		case zone := <-events:
+			if zoneMatches(zone, master.DeviceID, expected) {
+				return zone, nil
+			}
+		case <-ticker.C:
+		case <-deadline.C:
+			reason := "zone change not confirmed"
+			if last != nil {
+				reason = fmt.Sprintf("zone change not confirmed, device reports: %s", last)
+			}
+
+			return last, models.NewZoneError(op, master.DeviceID, reason)
+		}
+	}
+}
+
+// zoneDeviceSet returns the upper-cased device IDs a zone is expected to contain
+func zoneDeviceSet(master *ZoneSpeaker, members []*ZoneSpeaker) map[string]bool {
+	devices := map[string]bool{strings.ToUpper(master.DeviceID): true}
+	for _, member := range members {
+		devices[strings.ToUpper(member.DeviceID)] = true
+	}
+
+	return devices
+}
+
+// zoneMatches checks a reported zone against the expected devices.
+// A single expected device means the speaker must be standalone.
+func zoneMatches(zone *models.ZoneInfo, masterID string, expected map[string]bool) bool {
+	if len(expected) <= 1 {
+		return zone.Master == "" || (strings.EqualFold(zone.Master, masterID) && zoneDeviceCount(zone) <= 1)
+	}
+
+	if !strings.EqualFold(zone.Master, masterID) {
+		return false
+	}
+
+	actual := make(map[string]bool)
+	for _, deviceID := range zone.GetAllDeviceIDs() {
+		actual[strings.ToUpper(deviceID)] = true
+	}
+
+	if len(actual) != len(expected) {
+		return false
+	}
+
+	for deviceID := range expected {
+		if !actual[deviceID] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// zoneDeviceCount counts distinct devices, as masters may also list themselves as member
+func zoneDeviceCount(zone *models.ZoneInfo) int {
+	devices := make(map[string]bool)
+	for _, deviceID := range zone.GetAllDeviceIDs() {
+		devices[strings.ToUpper(deviceID)] = true
+	}
+
+	return len(devices)
+}
+
+// ZoneSummary describes a zone using speaker names where known
+func (m *ZoneManager) ZoneSummary(zone *models.ZoneInfo) string {
+	if zone == nil || zone.Master == "" {
+		return "standalone"
+	}
+
+	name := func(deviceID string) string {
+		if speaker := m.match(deviceID); speaker != nil && speaker.Name != "" {
+			return speaker.Name
+		}
+
+		return deviceID
+	}
+
+	var members []string
+
+	for _, member := range zone.Members {
+		if !strings.EqualFold(member.DeviceID, zone.Master) {
+			members = append(members, name(member.DeviceID))
+		}
+	}
+
+	sort.Strings(members)
+
+	if len(members) == 0 {
+		return fmt.Sprintf("%s (standalone)", name(zone.Master))
+	}
+
+	return fmt.Sprintf("%s + %s", name(zone.Master), strings.Join(members, ", "))
+}
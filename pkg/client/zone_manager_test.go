@@ -0,0 +1,376 @@
+package client
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+// fakeZoneNetwork simulates a set of speakers that apply zone changes like real devices
+type fakeZoneNetwork struct {
+	mu       sync.Mutex
+	speakers map[string]*fakeZoneSpeaker
+	servers  []*httptest.Server
+}
+
+type fakeZoneSpeaker struct {
+	deviceID string
+	name     string
+	master   string
+	members  []string
+	content  *models.ContentItem
+	port     int
+	volume   int
+	muted    bool
+}
+
+func newFakeZoneNetwork(t *testing.T, names ...string) (*fakeZoneNetwork, *ZoneManager) {
+	t.Helper()
+
+	network := &fakeZoneNetwork{speakers: make(map[string]*fakeZoneSpeaker)}
+	manager := NewZoneManager(nil)
+	manager.SetPollInterval(5 * time.Millisecond)
+	manager.SetVerifyTimeout(500 * time.Millisecond)
+
+	for i, name := range names {
+		speaker := &fakeZoneSpeaker{deviceID: fmt.Sprintf("DEVICE%d", i+1), name: name}
+		server := httptest.NewServer(network.handler(speaker))
+		t.Cleanup(server.Close)
+
+		u, _ := url.Parse(server.URL)
+		speaker.port, _ = strconv.Atoi(u.Port())
+
+		network.speakers[speaker.deviceID] = speaker
+		network.servers = append(network.servers, server)
+		manager.AddSpeaker(ZoneSpeaker{Host: u.Hostname(), Port: speaker.port})
+	}
+
+	return network, manager
+}
+
+func (n *fakeZoneNetwork) handler(speaker *fakeZoneSpeaker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		n.mu.Lock()
+		defer n.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/xml")
+
+		switch r.URL.Path {
+		case "/info":
+			_, _ = fmt.Fprintf(w, `<info deviceID="%s"><name>%s</name><type>SoundTouch 10</type></info>`, speaker.deviceID, speaker.name)
+		case "/getZone":
+			_, _ = w.Write([]byte(n.zoneXML(speaker)))
+		case "/now_playing", "/nowPlaying":
+			if speaker.content == nil {
+				_, _ = fmt.Fprintf(w, `<nowPlaying deviceID="%s" source="STANDBY"><ContentItem source="STANDBY" isPresetable="false" /></nowPlaying>`, speaker.deviceID)
+				return
+			}
+
+			_, _ = fmt.Fprintf(w, `<nowPlaying deviceID="%s" source="%s"><ContentItem source="%s" location="%s"><itemName>%s</itemName></ContentItem><playStatus>PLAY_STATE</playStatus></nowPlaying>`,
+				speaker.deviceID, speaker.content.Source, speaker.content.Source, speaker.content.Location, speaker.content.ItemName)
+		case "/key":
+			var key models.Key
+			_ = xml.Unmarshal(body, &key)
+
+			if key.State == "press" && key.Value == models.KeyMute {
+				speaker.muted = !speaker.muted
+			}
+
+			if key.State == "press" && key.Value == models.KeyPower {
+				if speaker.content == nil {
+					speaker.content = &models.ContentItem{Source: "AUX", ItemName: "AUX IN"}
+				} else {
+					speaker.content = nil
+				}
+			}
+
+			_, _ = w.Write([]byte(`<status>/key</status>`))
+		case "/volume":
+			if r.Method == http.MethodPost {
+				var request models.VolumeRequest
+				_ = xml.Unmarshal(body, &request)
+				speaker.volume = request.Value
+				_, _ = w.Write([]byte(`<status>/volume</status>`))
+
+				return
+			}
+
+			_, _ = fmt.Fprintf(w, `<volume deviceID="%s"><targetvolume>%d</targetvolume><actualvolume>%d</actualvolume><muteenabled>%t</muteenabled></volume>`,
+				speaker.deviceID, speaker.volume, speaker.volume, speaker.muted)
+		case "/select":
+			var item models.ContentItem
+			_ = xml.Unmarshal(body, &item)
+			speaker.content = &item
+			_, _ = w.Write([]byte(`<status>/select</status>`))
+		case "/setZone":
+			var request models.ZoneRequest
+			_ = xml.Unmarshal(body, &request)
+			n.applyZone(speaker, request.Members)
+			_, _ = w.Write([]byte(`<status>/setZone</status>`))
+		case "/addZoneSlave", "/removeZoneSlave":
+			var request models.ZoneSlaveRequest
+			_ = xml.Unmarshal(body, &request)
+
+			members := append([]string(nil), speaker.members...)
+
+			for _, entry := range request.Members {
+				if r.URL.Path == "/addZoneSlave" {
+					members = append(members, entry.DeviceID)
+				} else {
+					members = removeString(members, entry.DeviceID)
+					n.speakers[entry.DeviceID].master = ""
+					n.speakers[entry.DeviceID].content = nil
+				}
+			}
+
+			entries := make([]models.MemberEntry, 0, len(members))
+			for _, id := range members {
+				entries = append(entries, models.MemberEntry{DeviceID: id})
+			}
+
+			n.applyZone(speaker, entries)
+			_, _ = w.Write([]byte(`<status>` + r.URL.Path + `</status>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+// applyZone makes speaker the master of the given members; an empty list dissolves the zone
+func (n *fakeZoneNetwork) applyZone(master *fakeZoneSpeaker, members []models.MemberEntry) {
+	for _, id := range master.members {
+		n.speakers[id].master = ""
+		n.speakers[id].content = nil
+	}
+
+	master.members = nil
+
+	if len(members) == 0 {
+		master.master = ""
+		return
+	}
+
+	master.master = master.deviceID
+
+	for _, member := range members {
+		slave := n.speakers[member.DeviceID]
+		slave.master = master.deviceID
+		slave.content = master.content
+		master.members = append(master.members, member.DeviceID)
+	}
+}
+
+func (n *fakeZoneNetwork) zoneXML(speaker *fakeZoneSpeaker) string {
+	if speaker.master == "" {
+		return `<zone />`
+	}
+
+	master := n.speakers[speaker.master]
+
+	var sb strings.Builder
+
+	_, _ = fmt.Fprintf(&sb, `<zone master="%s">`, master.deviceID)
+	for _, id := range master.members {
+		_, _ = fmt.Fprintf(&sb, `<member ipaddress="127.0.0.1">%s</member>`, id)
+	}
+
+	sb.WriteString(`</zone>`)
+
+	return sb.String()
+}
+
+func (n *fakeZoneNetwork) byName(name string) *fakeZoneSpeaker {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for _, speaker := range n.speakers {
+		if speaker.name == name {
+			return speaker
+		}
+	}
+
+	return nil
+}
+
+func removeString(values []string, value string) []string {
+	result := values[:0]
+
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+func TestZoneManager_Resolve(t *testing.T) {
+	_, manager := newFakeZoneNetwork(t, "Kitchen", "Living Room")
+	manager.SetAlias("lr", "living room")
+
+	for _, ref := range []string{"Kitchen", "kitchen", "DEVICE1"} {
+		speaker, err := manager.Resolve(ref)
+		if err != nil || speaker.DeviceID != "DEVICE1" {
+			t.Errorf("Resolve(%q) = %v, %v; expected DEVICE1", ref, speaker, err)
+		}
+	}
+
+	speaker, err := manager.Resolve("lr")
+	if err != nil || speaker.Name != "Living Room" {
+		t.Errorf("Expected alias to resolve to Living Room, got %v, %v", speaker, err)
+	}
+
+	if _, err := manager.Resolve("Garage"); err == nil {
+		t.Error("Expected error for unknown speaker")
+	}
+}
+
+// fakeSpeakerResolver resolves references to the speakers of a fake zone network
+type fakeSpeakerResolver map[string]ZoneSpeaker
+
+func (r fakeSpeakerResolver) ResolveSpeaker(ref string) (string, string, int, bool) {
+	speaker, ok := r[ref]
+
+	return speaker.DeviceID, speaker.Host, speaker.Port, ok
+}
+
+func TestZoneManager_ResolveThroughResolver(t *testing.T) {
+	_, manager := newFakeZoneNetwork(t, "Kitchen", "Living Room")
+	livingRoom := manager.Speakers()[1]
+
+	// Living Room is not known to the manager anymore, only the resolver knows where it went
+	manager.speakers = manager.speakers[:1]
+	manager.SetResolver(fakeSpeakerResolver{"192.168.1.10": {DeviceID: "DEVICE2", Host: livingRoom.Host, Port: livingRoom.Port}})
+
+	speaker, err := manager.Resolve("192.168.1.10")
+	if err != nil || speaker.DeviceID != "DEVICE2" || speaker.Port != livingRoom.Port {
+		t.Fatalf("Expected the resolver to find DEVICE2, got %v, %v", speaker, err)
+	}
+
+	if speaker, err := manager.Resolve("Living Room"); err != nil || speaker.DeviceID != "DEVICE2" {
+		t.Errorf("Expected the resolved speaker to be known afterwards, got %v, %v", speaker, err)
+	}
+
+	if _, err := manager.Resolve("192.168.1.99"); err == nil {
+		t.Error("Expected error for a reference the resolver does not know")
+	}
+}
+
+func TestZoneManager_CreateAndJoin(t *testing.T) {
+	network, manager := newFakeZoneNetwork(t, "Kitchen", "Living Room", "Office")
+	network.byName("Kitchen").content = &models.ContentItem{Source: "TUNEIN", Location: "/v1/playback/station/s1", ItemName: "Radio"}
+
+	zone, err := manager.CreateZone("Kitchen", "Living Room")
+	if err != nil {
+		t.Fatalf("CreateZone failed: %v", err)
+	}
+
+	if summary := manager.ZoneSummary(zone); summary != "Kitchen + Living Room" {
+		t.Errorf("Unexpected zone: %s", summary)
+	}
+
+	// Join via a slave: the office joins the zone mastered by the kitchen
+	zone, err = manager.Join("Office", "Living Room")
+	if err != nil {
+		t.Fatalf("Join failed: %v", err)
+	}
+
+	if summary := manager.ZoneSummary(zone); summary != "Kitchen + Living Room, Office" {
+		t.Errorf("Unexpected zone after join: %s", summary)
+	}
+}
+
+func TestZoneManager_MoveMusic(t *testing.T) {
+	network, manager := newFakeZoneNetwork(t, "Kitchen", "Living Room", "Office")
+	network.byName("Kitchen").content = &models.ContentItem{Source: "TUNEIN", Location: "/v1/playback/station/s1", ItemName: "Radio"}
+
+	if _, err := manager.CreateZone("Kitchen", "Living Room"); err != nil {
+		t.Fatalf("CreateZone failed: %v", err)
+	}
+
+	zone, err := manager.MoveMusic("Living Room", "Office")
+	if err != nil {
+		t.Fatalf("MoveMusic failed: %v", err)
+	}
+
+	if summary := manager.ZoneSummary(zone); summary != "Office + Kitchen, Living Room" {
+		t.Errorf("Unexpected zone after move: %s", summary)
+	}
+
+	office := network.byName("Office")
+	if office.content == nil || office.content.Location != "/v1/playback/station/s1" {
+		t.Errorf("Expected content to move to the office, got %+v", office.content)
+	}
+}
+
+func TestZoneManager_SplitOff(t *testing.T) {
+	network, manager := newFakeZoneNetwork(t, "Kitchen", "Living Room", "Office")
+	network.byName("Kitchen").content = &models.ContentItem{Source: "TUNEIN", Location: "/v1/playback/station/s1", ItemName: "Radio"}
+
+	if _, err := manager.CreateZone("Kitchen", "Living Room", "Office"); err != nil {
+		t.Fatalf("CreateZone failed: %v", err)
+	}
+
+	// Splitting off the master hands the music to the remaining speakers
+	if err := manager.SplitOff("Kitchen"); err != nil {
+		t.Fatalf("SplitOff failed: %v", err)
+	}
+
+	zone, master, err := manager.ZoneOf("Office")
+	if err != nil {
+		t.Fatalf("ZoneOf failed: %v", err)
+	}
+
+	if master.Name != "Living Room" || manager.ZoneSummary(zone) != "Living Room + Office" {
+		t.Errorf("Unexpected remaining zone: %s", manager.ZoneSummary(zone))
+	}
+
+	if content := network.byName("Living Room").content; content == nil || content.Source != "TUNEIN" {
+		t.Errorf("Expected playback to continue on the new master, got %+v", content)
+	}
+
+	if err := manager.SplitOff("Office"); err != nil {
+		t.Fatalf("SplitOff of slave failed: %v", err)
+	}
+
+	zone, _, _ = manager.ZoneOf("Living Room")
+	if !zone.IsStandalone() {
+		t.Errorf("Expected living room to be standalone, got %s", zone)
+	}
+}
+
+func TestZoneManager_MoveMusicRequiresPlayback(t *testing.T) {
+	_, manager := newFakeZoneNetwork(t, "Kitchen", "Office")
+
+	if _, err := manager.MoveMusic("Kitchen", "Office"); err == nil || !strings.Contains(err.Error(), "nothing is playing") {
+		t.Errorf("Expected error when nothing is playing, got %v", err)
+	}
+}
+
+func TestZoneMatches(t *testing.T) {
+	zone := &models.ZoneInfo{Master: "A", Members: []models.Member{{DeviceID: "A"}, {DeviceID: "B"}}}
+
+	if !zoneMatches(zone, "A", map[string]bool{"A": true, "B": true}) {
+		t.Error("Expected zone listing the master as member to match")
+	}
+
+	if zoneMatches(zone, "A", map[string]bool{"A": true, "B": true, "C": true}) {
+		t.Error("Expected missing member not to match")
+	}
+
+	if !zoneMatches(&models.ZoneInfo{}, "A", map[string]bool{"A": true}) {
+		t.Error("Expected empty zone to match a standalone expectation")
+	}
+}
@@ -20,9 +20,26 @@ type Config struct {
 	UPnPEnabled      bool          `env:"UPNP_ENABLED" default:"true"`
 	MDNSEnabled      bool          `env:"MDNS_ENABLED" default:"true"`
 
+	// Network interfaces for SSDP and mDNS (default: all up, multicast-capable interfaces
+	// except loopback, point-to-point links and container bridges)
+	DiscoveryInterfaces []string `env:"DISCOVERY_INTERFACES"`
+	DiscoveryIPv6       bool     `env:"DISCOVERY_IPV6" default:"true"`
+
+	// Unicast subnet sweep for networks where multicast does not reach the speakers
+	ScanSubnets     []string      `env:"SCAN_SUBNETS"`
+	ScanConcurrency int           `env:"SCAN_CONCURRENCY" default:"64"`
+	ScanTimeout     time.Duration `env:"SCAN_TIMEOUT" default:"750ms"`
+
+	// Device registry that keeps device identities across IP address changes
+	// (default: soundtouch/devices.json in the user configuration directory)
+	DeviceRegistry string `env:"DEVICE_REGISTRY"`
+
 	// Preferred devices from .env file
 	PreferredDevices []DeviceConfig `env:"PREFERRED_DEVICES"`
 
+	// Named zones from .env file
+	Zones []models.ZoneDefinition `env:"ZONES"`
+
 	// HTTP Client settings
 	HTTPTimeout time.Duration `env:"HTTP_TIMEOUT" default:"10s"`
 	UserAgent   string        `env:"USER_AGENT" default:"Bose-SoundTouch-Go-Client/1.0"`
@@ -45,7 +62,12 @@ func DefaultConfig() *Config {
 		DiscoveryTimeout: 5 * time.Second,
 		UPnPEnabled:      true,
 		MDNSEnabled:      true,
+		DiscoveryIPv6:    true,
+		ScanSubnets:      []string{},
+		ScanConcurrency:  64,
+		ScanTimeout:      750 * time.Millisecond,
 		PreferredDevices: []DeviceConfig{},
+		Zones:            []models.ZoneDefinition{},
 		HTTPTimeout:      10 * time.Second,
 		UserAgent:        "Bose-SoundTouch-Go-Client/1.0",
 		CacheEnabled:     true,
@@ -75,6 +97,30 @@ func LoadFromEnv() (*Config, error) {
 		config.MDNSEnabled = mdns == "true" || mdns == "1"
 	}
 
+	if interfaces := os.Getenv("DISCOVERY_INTERFACES"); interfaces != "" {
+		config.DiscoveryInterfaces = splitList(interfaces)
+	}
+
+	if ipv6 := os.Getenv("DISCOVERY_IPV6"); ipv6 != "" {
+		config.DiscoveryIPv6 = ipv6 == "true" || ipv6 == "1"
+	}
+
+	if concurrency := os.Getenv("SCAN_CONCURRENCY"); concurrency != "" {
+		if n, err := strconv.Atoi(concurrency); err == nil {
+			config.ScanConcurrency = n
+		}
+	}
+
+	if timeout := os.Getenv("SCAN_TIMEOUT"); timeout != "" {
+		if d, err := time.ParseDuration(timeout); err == nil {
+			config.ScanTimeout = d
+		}
+	}
+
+	if registry := os.Getenv("DEVICE_REGISTRY"); registry != "" {
+		config.DeviceRegistry = registry
+	}
+
 	if timeout := os.Getenv("HTTP_TIMEOUT"); timeout != "" {
 		if d, err := time.ParseDuration(timeout); err == nil {
 			config.HTTPTimeout = d
@@ -103,9 +149,34 @@ func LoadFromEnv() (*Config, error) {
 
 	config.PreferredDevices = devices
 
+	zones, err := parseZones()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse zones: %w", err)
+	}
+
+	config.Zones = zones
+
+	subnets, err := parseScanSubnets()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse scan subnets: %w", err)
+	}
+
+	config.ScanSubnets = subnets
+
 	return config, nil
 }
 
+// GetZone returns the named zone definition (case-insensitive)
+func (c *Config) GetZone(name string) (*models.ZoneDefinition, bool) {
+	for i := range c.Zones {
+		if strings.EqualFold(c.Zones[i].Name, name) {
+			return &c.Zones[i], true
+		}
+	}
+
+	return nil, false
+}
+
 // GetPreferredDevicesAsDiscovered converts configured devices to DiscoveredDevice format
 func (c *Config) GetPreferredDevicesAsDiscovered() []*models.DiscoveredDevice {
 	devices := make([]*models.DiscoveredDevice, 0, len(c.PreferredDevices))
@@ -117,8 +188,8 @@ func (c *Config) GetPreferredDevicesAsDiscovered() []*models.DiscoveredDevice {
 			Port:            device.Port,
 			LastSeen:        time.Now(),
 			DiscoveryMethod: "Configuration",
-			APIBaseURL:      fmt.Sprintf("http://%s:%d/", device.Host, device.Port),
-			InfoURL:         fmt.Sprintf("http://%s:%d/info", device.Host, device.Port),
+			APIBaseURL:      HostURL(device.Host, device.Port) + "/",
+			InfoURL:         HostURL(device.Host, device.Port) + "/info",
 			ConfigName:      device.Name,
 		}
 		devices = append(devices, discovered)
@@ -212,24 +283,15 @@ func parseDeviceString(deviceStr string) (DeviceConfig, error) {
 		deviceStr = strings.TrimSpace(parts[1])
 	}
 
-	// Parse host:port or just host
-	if strings.Contains(deviceStr, ":") {
-		host, portStr, err := net.SplitHostPort(deviceStr)
-		if err != nil {
-			return device, fmt.Errorf("invalid host:port format: %w", err)
-		}
-
-		port, err := strconv.Atoi(portStr)
-		if err != nil || port <= 0 || port > 65535 {
-			return device, fmt.Errorf("invalid port number: %s", portStr)
-		}
-
-		device.Host = host
-		device.Port = port
-	} else {
-		device.Host = deviceStr
+	// Parse host:port, [ipv6]:port or just host
+	host, port, err := SplitHostPort(deviceStr, device.Port)
+	if err != nil {
+		return device, err
 	}
 
+	device.Host = host
+	device.Port = port
+
 	// Validate host
 	if device.Host == "" {
 		return device, fmt.Errorf("host cannot be empty")
@@ -243,6 +305,152 @@ func parseDeviceString(deviceStr string) (DeviceConfig, error) {
 	return device, nil
 }
 
+// SplitHostPort splits "host", "host:port", "[ipv6]", "[ipv6]:port" or a bare IPv6 address
+// into host and port, using defaultPort if none is given. IPv6 hosts are returned without brackets.
+func SplitHostPort(hostPort string, defaultPort int) (string, int, error) {
+	hostPort = strings.TrimSpace(hostPort)
+
+	switch {
+	case strings.HasPrefix(hostPort, "[") && strings.HasSuffix(hostPort, "]"):
+		return hostPort[1 : len(hostPort)-1], defaultPort, nil
+	case !strings.Contains(hostPort, ":") || isIPv6Literal(hostPort):
+		return hostPort, defaultPort, nil
+	}
+
+	host, portStr, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return "", defaultPort, fmt.Errorf("invalid host:port format: %w", err)
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port <= 0 || port > 65535 {
+		return "", defaultPort, fmt.Errorf("invalid port number: %s", portStr)
+	}
+
+	return host, port, nil
+}
+
+// HostURL returns the http:// base URL of a host and port, with IPv6 hosts in brackets.
+// IPv6 hosts may be given with or without brackets; "[ipv6]:port" overrides port.
+func HostURL(host string, port int) string {
+	if strings.HasPrefix(host, "[") {
+		if h, p, err := net.SplitHostPort(host); err == nil {
+			host = h
+
+			if n, err := strconv.Atoi(p); err == nil {
+				port = n
+			}
+		} else {
+			host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+		}
+	}
+
+	// The zone of a link-local address must be escaped in URLs
+	host = strings.Replace(host, "%", "%25", 1)
+
+	return "http://" + net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// isIPv6Literal reports whether s is an IPv6 address, optionally with a zone
+func isIPv6Literal(s string) bool {
+	address, _, _ := strings.Cut(s, "%")
+	ip := net.ParseIP(address)
+
+	return ip != nil && ip.To4() == nil
+}
+
+// splitList splits a comma or semicolon separated list and drops empty entries
+func splitList(value string) []string {
+	var items []string
+
+	for _, item := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' }) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// parseZones parses ZONES from environment.
+// Format: "name=master,member,member;name=master,member", the first speaker becomes the master.
+func parseZones() ([]models.ZoneDefinition, error) {
+	zonesEnv := os.Getenv("ZONES")
+	if zonesEnv == "" {
+		return []models.ZoneDefinition{}, nil
+	}
+
+	var zones []models.ZoneDefinition
+
+	for _, zoneStr := range strings.Split(zonesEnv, ";") {
+		zoneStr = strings.TrimSpace(zoneStr)
+		if zoneStr == "" {
+			continue
+		}
+
+		name, speakers, found := strings.Cut(zoneStr, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid zone configuration '%s': expected name=master,member", zoneStr)
+		}
+
+		zone := models.ZoneDefinition{Name: strings.TrimSpace(name)}
+
+		for i, speaker := range strings.Split(speakers, ",") {
+			speaker = strings.TrimSpace(speaker)
+			if i == 0 {
+				zone.Master = speaker
+				continue
+			}
+
+			zone.Members = append(zone.Members, speaker)
+		}
+
+		if err := zone.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid zone configuration '%s': %w", zoneStr, err)
+		}
+
+		zones = append(zones, zone)
+	}
+
+	return zones, nil
+}
+
+// parseScanSubnets parses SCAN_SUBNETS from environment.
+// Format: "192.168.1.0/24,10.0.20.0/24", separated by commas or semicolons.
+func parseScanSubnets() ([]string, error) {
+	subnetsEnv := os.Getenv("SCAN_SUBNETS")
+	if subnetsEnv == "" {
+		return []string{}, nil
+	}
+
+	var subnets []string
+
+	for _, subnet := range splitList(subnetsEnv) {
+		if err := ValidateScanSubnet(subnet); err != nil {
+			return nil, err
+		}
+
+		subnets = append(subnets, subnet)
+	}
+
+	return subnets, nil
+}
+
+// ValidateScanSubnet checks that a subnet is a CIDR range small enough to sweep (at most 65536 addresses)
+func ValidateScanSubnet(subnet string) error {
+	_, ipNet, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return fmt.Errorf("invalid subnet '%s': %w", subnet, err)
+	}
+
+	ones, bits := ipNet.Mask.Size()
+	if bits-ones > 16 {
+		return fmt.Errorf("subnet '%s' is too large to scan, use /%d or smaller", subnet, bits-16)
+	}
+
+	return nil
+}
+
 // Validate checks if the configuration is valid
 func (c *Config) Validate() error {
 	if c.DiscoveryTimeout <= 0 {
@@ -267,5 +475,21 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	for i := range c.Zones {
+		if err := c.Zones[i].Validate(); err != nil {
+			return fmt.Errorf("zone %d: %w", i, err)
+		}
+	}
+
+	for _, subnet := range c.ScanSubnets {
+		if err := ValidateScanSubnet(subnet); err != nil {
+			return err
+		}
+	}
+
+	if len(c.ScanSubnets) > 0 && (c.ScanConcurrency <= 0 || c.ScanTimeout <= 0) {
+		return fmt.Errorf("scan concurrency and timeout must be positive")
+	}
+
 	return nil
 }
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strings"
 	"testing"
 	"time"
 )
@@ -202,6 +203,70 @@ func TestParseDeviceString_InvalidPort(t *testing.T) {
 	}
 }
 
+func TestParseDeviceString_IPv6(t *testing.T) {
+	tests := []struct {
+		input string
+		host  string
+		port  int
+	}{
+		{"fe80::1", "fe80::1", 8090},
+		{"fe80::1%eth0", "fe80::1%eth0", 8090},
+		{"[2001:db8::10]", "2001:db8::10", 8090},
+		{"[2001:db8::10]:8091", "2001:db8::10", 8091},
+		{"Office@[fe80::1%eth0]:8090", "fe80::1%eth0", 8090},
+	}
+
+	for _, tt := range tests {
+		device, err := parseDeviceString(tt.input)
+		if err != nil {
+			t.Errorf("parseDeviceString(%q) failed: %v", tt.input, err)
+			continue
+		}
+
+		if device.Host != tt.host || device.Port != tt.port {
+			t.Errorf("parseDeviceString(%q) = %s, %d; expected %s, %d", tt.input, device.Host, device.Port, tt.host, tt.port)
+		}
+	}
+
+	if _, err := parseDeviceString("[2001:db8::10]:invalid"); err == nil {
+		t.Error("Expected error for invalid port after IPv6 address, got nil")
+	}
+}
+
+func TestHostURL(t *testing.T) {
+	tests := map[string]string{
+		"192.168.1.100":       "http://192.168.1.100:8090",
+		"2001:db8::10":        "http://[2001:db8::10]:8090",
+		"fe80::1%eth0":        "http://[fe80::1%25eth0]:8090",
+		"[2001:db8::10]":      "http://[2001:db8::10]:8090",
+		"[fe80::1%eth0]:8091": "http://[fe80::1%25eth0]:8091",
+	}
+
+	for host, expected := range tests {
+		if got := HostURL(host, 8090); got != expected {
+			t.Errorf("HostURL(%q) = %s, expected %s", host, got, expected)
+		}
+	}
+}
+
+func TestLoadFromEnv_DiscoveryInterfaces(t *testing.T) {
+	clearTestEnvVars()
+
+	_ = os.Setenv("DISCOVERY_INTERFACES", "eth0, wlan0")
+	_ = os.Setenv("DISCOVERY_IPV6", "false")
+
+	defer clearTestEnvVars()
+
+	config, err := LoadFromEnv()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if strings.Join(config.DiscoveryInterfaces, ",") != "eth0,wlan0" || config.DiscoveryIPv6 {
+		t.Errorf("Unexpected interface settings: %v, IPv6 %v", config.DiscoveryInterfaces, config.DiscoveryIPv6)
+	}
+}
+
 func TestParseDeviceString_EmptyHost(t *testing.T) {
 	_, err := parseDeviceString("")
 	if err == nil {
@@ -321,6 +386,81 @@ func TestParsePreferredDevices_InvalidDevice(t *testing.T) {
 	}
 }
 
+func TestParseZones(t *testing.T) {
+	clearTestEnvVars()
+
+	_ = os.Setenv("ZONES", "downstairs=Kitchen, Living Room;party=Kitchen,Living Room,Office")
+
+	defer clearTestEnvVars()
+
+	zones, err := parseZones()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(zones) != 2 {
+		t.Fatalf("Expected 2 zones, got %d", len(zones))
+	}
+
+	if zones[0].Name != "downstairs" || zones[0].Master != "Kitchen" || len(zones[0].Members) != 1 || zones[0].Members[0] != "Living Room" {
+		t.Errorf("Unexpected first zone: %+v", zones[0])
+	}
+
+	if len(zones[1].Members) != 2 {
+		t.Errorf("Expected 2 members in party zone, got %d", len(zones[1].Members))
+	}
+
+	config := &Config{Zones: zones}
+	if zone, ok := config.GetZone("PARTY"); !ok || zone.Master != "Kitchen" {
+		t.Errorf("Expected to find party zone, got %+v", zone)
+	}
+}
+
+func TestParseScanSubnets(t *testing.T) {
+	clearTestEnvVars()
+
+	defer clearTestEnvVars()
+
+	_ = os.Setenv("SCAN_SUBNETS", "192.168.1.0/24, 10.0.20.0/28;")
+	_ = os.Setenv("SCAN_CONCURRENCY", "16")
+	_ = os.Setenv("SCAN_TIMEOUT", "500ms")
+
+	config, err := LoadFromEnv()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(config.ScanSubnets) != 2 || config.ScanSubnets[0] != "192.168.1.0/24" || config.ScanSubnets[1] != "10.0.20.0/28" {
+		t.Errorf("Unexpected scan subnets: %v", config.ScanSubnets)
+	}
+
+	if config.ScanConcurrency != 16 || config.ScanTimeout != 500*time.Millisecond {
+		t.Errorf("Unexpected scan settings: concurrency=%d timeout=%v", config.ScanConcurrency, config.ScanTimeout)
+	}
+
+	for _, value := range []string{"192.168.1.1", "10.0.0.0/8", "not-a-subnet/24"} {
+		_ = os.Setenv("SCAN_SUBNETS", value)
+
+		if _, err := parseScanSubnets(); err == nil {
+			t.Errorf("Expected error for SCAN_SUBNETS=%q", value)
+		}
+	}
+}
+
+func TestParseZones_Invalid(t *testing.T) {
+	clearTestEnvVars()
+
+	defer clearTestEnvVars()
+
+	for _, value := range []string{"downstairs", "downstairs=Kitchen", "=Kitchen,Office"} {
+		_ = os.Setenv("ZONES", value)
+
+		if _, err := parseZones(); err == nil {
+			t.Errorf("Expected error for ZONES=%q", value)
+		}
+	}
+}
+
 func TestGetPreferredDevicesAsDiscovered(t *testing.T) {
 	config := &Config{
 		PreferredDevices: []DeviceConfig{
@@ -441,6 +581,13 @@ func clearTestEnvVars() {
 		"CACHE_ENABLED",
 		"CACHE_TTL",
 		"PREFERRED_DEVICES",
+		"ZONES",
+		"SCAN_SUBNETS",
+		"SCAN_CONCURRENCY",
+		"SCAN_TIMEOUT",
+		"DEVICE_REGISTRY",
+		"DISCOVERY_INTERFACES",
+		"DISCOVERY_IPV6",
 	}
 
 	for _, env := range envVars {
@@ -0,0 +1,290 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// profilesFileName is the file name of the CLI configuration file in the user configuration directory
+const profilesFileName = "config.json"
+
+// DeviceProfile is a named device of the CLI configuration file
+type DeviceProfile struct {
+	Host    string   `json:"host"`
+	Port    int      `json:"port,omitempty"`
+	Aliases []string `json:"aliases,omitempty"`
+	Timeout string   `json:"timeout,omitempty"`
+}
+
+// TimeoutDuration returns the request timeout of the device, or zero if none is set
+func (d *DeviceProfile) TimeoutDuration() time.Duration {
+	timeout, err := time.ParseDuration(d.Timeout)
+	if err != nil {
+		return 0
+	}
+
+	return timeout
+}
+
+// Profiles is the CLI configuration file. It holds named devices with aliases, the device
+// used when none is selected, groups of devices and the soundtouch-service to send requests through.
+type Profiles struct {
+	DefaultDevice string                    `json:"defaultDevice,omitempty"`
+	Service       string                    `json:"service,omitempty"`
+	Devices       map[string]*DeviceProfile `json:"devices,omitempty"`
+	Groups        map[string][]string       `json:"groups,omitempty"`
+
+	path string
+}
+
+// DefaultProfilesPath returns the location of the CLI configuration file in the user configuration directory
+func DefaultProfilesPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return profilesFileName
+	}
+
+	return filepath.Join(dir, "soundtouch", profilesFileName)
+}
+
+// LoadProfiles reads the CLI configuration file at path; a missing file yields an empty configuration
+func LoadProfiles(path string) (*Profiles, error) {
+	p := &Profiles{path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return p, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	return p, nil
+}
+
+// Path returns the file the configuration is stored in
+func (p *Profiles) Path() string {
+	return p.path
+}
+
+// Save validates the configuration and writes it to its file
+func (p *Profiles) Save() error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode config file: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p.path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	if err := os.WriteFile(p.path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	return nil
+}
+
+// Validate checks that names and aliases are unique and that groups and the default device refer to known devices
+func (p *Profiles) Validate() error {
+	names := make(map[string]string)
+
+	for _, name := range p.DeviceNames() {
+		device := p.Devices[name]
+		if device == nil || device.Host == "" {
+			return fmt.Errorf("device %q has no host", name)
+		}
+
+		if device.Port < 0 || device.Port > 65535 {
+			return fmt.Errorf("device %q has invalid port %d", name, device.Port)
+		}
+
+		if device.Timeout != "" {
+			if timeout, err := time.ParseDuration(device.Timeout); err != nil || timeout <= 0 {
+				return fmt.Errorf("device %q has invalid timeout %q", name, device.Timeout)
+			}
+		}
+
+		for _, key := range append([]string{name}, device.Aliases...) {
+			if other, exists := names[strings.ToLower(key)]; exists {
+				return fmt.Errorf("name %q of device %q is already used by device %q", key, name, other)
+			}
+
+			names[strings.ToLower(key)] = name
+		}
+	}
+
+	if p.DefaultDevice != "" {
+		if _, _, ok := p.Device(p.DefaultDevice); !ok {
+			return fmt.Errorf("default device %q is not configured", p.DefaultDevice)
+		}
+	}
+
+	for group, members := range p.Groups {
+		if len(members) == 0 {
+			return fmt.Errorf("group %q has no devices", group)
+		}
+
+		for _, member := range members {
+			if _, _, ok := p.Device(member); !ok {
+				return fmt.Errorf("group %q refers to unknown device %q", group, member)
+			}
+		}
+	}
+
+	return nil
+}
+
+// DeviceNames returns the names of all configured devices, sorted
+func (p *Profiles) DeviceNames() []string {
+	names := make([]string, 0, len(p.Devices))
+	for name := range p.Devices {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// GroupNames returns the names of all configured groups, sorted
+func (p *Profiles) GroupNames() []string {
+	names := make([]string, 0, len(p.Groups))
+	for name := range p.Groups {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Device looks up a device by name or alias, ignoring case. It returns the device name and profile.
+func (p *Profiles) Device(ref string) (string, *DeviceProfile, bool) {
+	if device, ok := p.Devices[ref]; ok {
+		return ref, device, true
+	}
+
+	for _, name := range p.DeviceNames() {
+		device := p.Devices[name]
+		if strings.EqualFold(name, ref) {
+			return name, device, true
+		}
+
+		for _, alias := range device.Aliases {
+			if strings.EqualFold(alias, ref) {
+				return name, device, true
+			}
+		}
+	}
+
+	return "", nil, false
+}
+
+// Group returns the device names of a group, ignoring case of the group name
+func (p *Profiles) Group(name string) ([]string, bool) {
+	if members, ok := p.Groups[name]; ok {
+		return members, true
+	}
+
+	for group, members := range p.Groups {
+		if strings.EqualFold(group, name) {
+			return members, true
+		}
+	}
+
+	return nil, false
+}
+
+// SetDevice adds or replaces a named device
+func (p *Profiles) SetDevice(name string, device *DeviceProfile) {
+	if p.Devices == nil {
+		p.Devices = make(map[string]*DeviceProfile)
+	}
+
+	p.Devices[name] = device
+}
+
+// RemoveDevice removes a device, its group memberships and its use as default device.
+// Groups left without devices are removed as well.
+func (p *Profiles) RemoveDevice(ref string) bool {
+	name, device, ok := p.Device(ref)
+	if !ok {
+		return false
+	}
+
+	refs := append([]string{name}, device.Aliases...)
+	isDevice := func(value string) bool {
+		for _, r := range refs {
+			if strings.EqualFold(r, value) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	delete(p.Devices, name)
+
+	if isDevice(p.DefaultDevice) {
+		p.DefaultDevice = ""
+	}
+
+	for group, members := range p.Groups {
+		kept := members[:0]
+
+		for _, member := range members {
+			if !isDevice(member) {
+				kept = append(kept, member)
+			}
+		}
+
+		if len(kept) == 0 {
+			delete(p.Groups, group)
+		} else {
+			p.Groups[group] = kept
+		}
+	}
+
+	return true
+}
+
+// SetGroup adds or replaces a group of devices
+func (p *Profiles) SetGroup(name string, members []string) {
+	if p.Groups == nil {
+		p.Groups = make(map[string][]string)
+	}
+
+	p.Groups[name] = members
+}
+
+// RemoveGroup removes a group
+func (p *Profiles) RemoveGroup(name string) bool {
+	for group := range p.Groups {
+		if strings.EqualFold(group, name) {
+			delete(p.Groups, group)
+			return true
+		}
+	}
+
+	return false
+}
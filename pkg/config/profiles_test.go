@@ -0,0 +1,136 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProfiles_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "soundtouch", "config.json")
+
+	profiles, err := LoadProfiles(path)
+	if err != nil {
+		t.Fatalf("Unexpected error for missing file: %v", err)
+	}
+
+	if len(profiles.Devices) != 0 || profiles.Path() != path {
+		t.Fatalf("Expected empty configuration at %s, got %+v", path, profiles)
+	}
+
+	profiles.SetDevice("Kitchen", &DeviceProfile{Host: "192.168.1.10", Aliases: []string{"k"}, Timeout: "3s"})
+	profiles.SetDevice("Living Room", &DeviceProfile{Host: "192.168.1.11", Port: 8091})
+	profiles.SetGroup("downstairs", []string{"kitchen", "Living Room"})
+	profiles.DefaultDevice = "k"
+	profiles.Service = "http://nas:8000"
+
+	if err := profiles.Save(); err != nil {
+		t.Fatalf("Failed to save: %v", err)
+	}
+
+	loaded, err := LoadProfiles(path)
+	if err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+
+	name, device, ok := loaded.Device("K")
+	if !ok || name != "Kitchen" || device.Host != "192.168.1.10" {
+		t.Errorf("Expected alias to resolve to Kitchen, got %q %+v", name, device)
+	}
+
+	if device.TimeoutDuration() != 3*time.Second {
+		t.Errorf("Expected timeout 3s, got %v", device.TimeoutDuration())
+	}
+
+	if _, device, _ := loaded.Device("living room"); device == nil || device.Port != 8091 || device.TimeoutDuration() != 0 {
+		t.Errorf("Unexpected living room device: %+v", device)
+	}
+
+	if members, ok := loaded.Group("Downstairs"); !ok || len(members) != 2 {
+		t.Errorf("Expected group with two devices, got %v", members)
+	}
+
+	if loaded.DefaultDevice != "k" || loaded.Service != "http://nas:8000" {
+		t.Errorf("Unexpected default device %q or service %q", loaded.DefaultDevice, loaded.Service)
+	}
+}
+
+func TestProfiles_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		profiles Profiles
+		errMsg   string
+	}{
+		{
+			name:     "missing host",
+			profiles: Profiles{Devices: map[string]*DeviceProfile{"a": {}}},
+			errMsg:   "has no host",
+		},
+		{
+			name:     "invalid timeout",
+			profiles: Profiles{Devices: map[string]*DeviceProfile{"a": {Host: "h", Timeout: "soon"}}},
+			errMsg:   "invalid timeout",
+		},
+		{
+			name: "duplicate alias",
+			profiles: Profiles{Devices: map[string]*DeviceProfile{
+				"a": {Host: "h1", Aliases: []string{"x"}},
+				"b": {Host: "h2", Aliases: []string{"X"}},
+			}},
+			errMsg: "already used",
+		},
+		{
+			name:     "unknown default",
+			profiles: Profiles{DefaultDevice: "b", Devices: map[string]*DeviceProfile{"a": {Host: "h"}}},
+			errMsg:   "default device",
+		},
+		{
+			name:     "unknown group member",
+			profiles: Profiles{Devices: map[string]*DeviceProfile{"a": {Host: "h"}}, Groups: map[string][]string{"g": {"a", "b"}}},
+			errMsg:   "unknown device",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.profiles.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestProfiles_RemoveDevice(t *testing.T) {
+	profiles := &Profiles{}
+	profiles.SetDevice("Kitchen", &DeviceProfile{Host: "h1", Aliases: []string{"k"}})
+	profiles.SetDevice("Office", &DeviceProfile{Host: "h2"})
+	profiles.SetGroup("all", []string{"k", "Office"})
+	profiles.SetGroup("cooking", []string{"Kitchen"})
+	profiles.DefaultDevice = "k"
+
+	if !profiles.RemoveDevice("KITCHEN") {
+		t.Fatal("Expected Kitchen to be removed")
+	}
+
+	if profiles.DefaultDevice != "" {
+		t.Errorf("Expected default device to be cleared, got %q", profiles.DefaultDevice)
+	}
+
+	if members, _ := profiles.Group("all"); len(members) != 1 || members[0] != "Office" {
+		t.Errorf("Expected only Office in group all, got %v", members)
+	}
+
+	if _, ok := profiles.Group("cooking"); ok {
+		t.Error("Expected empty group to be removed")
+	}
+
+	if err := profiles.Validate(); err != nil {
+		t.Errorf("Expected valid configuration after removal: %v", err)
+	}
+
+	if profiles.RemoveDevice("Kitchen") {
+		t.Error("Expected removing an unknown device to fail")
+	}
+}
@@ -0,0 +1,284 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/mdns"
+)
+
+// ServiceType is the mDNS service type under which soundtouch-service announces itself
+const ServiceType = "_soundtouch-service._tcp"
+
+// Default paths of the soundtouch-service HTTP API
+const (
+	DefaultServiceAPIPath   = "/api"
+	DefaultServiceProxyPath = "/proxy"
+)
+
+// ServiceAnnouncement describes a soundtouch-service instance advertised over mDNS
+type ServiceAnnouncement struct {
+	Instance  string
+	Version   string
+	HTTPPort  int
+	HTTPSPort int
+	APIPath   string
+	ProxyPath string
+	// IPs are the addresses announced for the host; the addresses of all
+	// non-loopback interfaces are used if empty.
+	IPs []net.IP
+}
+
+// TXT returns the TXT records of the announcement
+func (a ServiceAnnouncement) TXT() []string {
+	txt := []string{
+		"version=" + a.Version,
+		"http_port=" + strconv.Itoa(a.HTTPPort),
+	}
+
+	if a.HTTPSPort != 0 {
+		txt = append(txt, "https_port="+strconv.Itoa(a.HTTPSPort))
+	}
+
+	apiPath := a.APIPath
+	if apiPath == "" {
+		apiPath = DefaultServiceAPIPath
+	}
+
+	proxyPath := a.ProxyPath
+	if proxyPath == "" {
+		proxyPath = DefaultServiceProxyPath
+	}
+
+	return append(txt, "api_path="+apiPath, "proxy_path="+proxyPath)
+}
+
+// ServiceAdvertiser answers mDNS queries for a soundtouch-service instance
+type ServiceAdvertiser struct {
+	server *mdns.Server
+}
+
+// AdvertiseService starts answering mDNS queries for the announced service until Shutdown is called
+func AdvertiseService(announcement ServiceAnnouncement) (*ServiceAdvertiser, error) {
+	if announcement.HTTPPort == 0 {
+		return nil, fmt.Errorf("HTTP port is required")
+	}
+
+	instance := announcement.Instance
+	if instance == "" {
+		instance = "soundtouch-service"
+	}
+
+	ips := announcement.IPs
+	if len(ips) == 0 {
+		ips = localUnicastIPs()
+	}
+
+	service, err := mdns.NewMDNSService(instance, ServiceType, soundTouchDomain, "", announcement.HTTPPort, ips, announcement.TXT())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mDNS service: %w", err)
+	}
+
+	server, err := mdns.NewServer(&mdns.Config{Zone: service})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start mDNS server: %w", err)
+	}
+
+	log.Printf("mDNS: Advertising '%s.%s.%s' on port %d", instance, ServiceType, soundTouchDomain, announcement.HTTPPort)
+
+	return &ServiceAdvertiser{server: server}, nil
+}
+
+// Shutdown stops answering mDNS queries
+func (a *ServiceAdvertiser) Shutdown() error {
+	return a.server.Shutdown()
+}
+
+// ServiceInstance is a soundtouch-service found on the network
+type ServiceInstance struct {
+	Name      string `json:"name"`
+	Host      string `json:"host"`
+	HTTPPort  int    `json:"http_port"`
+	HTTPSPort int    `json:"https_port,omitempty"`
+	Version   string `json:"version,omitempty"`
+	APIPath   string `json:"api_path"`
+	ProxyPath string `json:"proxy_path"`
+}
+
+// URL returns the HTTP base URL of the service
+func (s *ServiceInstance) URL() string {
+	return "http://" + net.JoinHostPort(s.Host, strconv.Itoa(s.HTTPPort))
+}
+
+// ProxyURL returns the URL under which the service proxies requests to other hosts
+func (s *ServiceInstance) ProxyURL() string {
+	return s.URL() + s.ProxyPath
+}
+
+// ResolveServices looks up soundtouch-service instances via mDNS, sorted by name
+func ResolveServices(ctx context.Context, timeout time.Duration) ([]*ServiceInstance, error) {
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	entries := make(chan *mdns.ServiceEntry, 16)
+	done := make(chan error, 1)
+
+	go func() {
+		defer close(entries)
+
+		done <- mdns.Query(&mdns.QueryParam{
+			Service:     ServiceType,
+			Domain:      soundTouchDomain,
+			Timeout:     timeout,
+			Entries:     entries,
+			DisableIPv6: true,
+		})
+	}()
+
+	seen := make(map[string]bool)
+
+	var instances []*ServiceInstance
+
+	for {
+		select {
+		case <-ctx.Done():
+			return instances, ctx.Err()
+		case entry, ok := <-entries:
+			if !ok {
+				if err := <-done; err != nil && len(instances) == 0 {
+					return nil, fmt.Errorf("failed to query %s: %w", ServiceType, err)
+				}
+
+				sort.Slice(instances, func(i, j int) bool { return instances[i].Name < instances[j].Name })
+
+				return instances, nil
+			}
+
+			instance := serviceEntryToInstance(entry)
+			if instance == nil || seen[instance.URL()] {
+				continue
+			}
+
+			seen[instance.URL()] = true
+			instances = append(instances, instance)
+		}
+	}
+}
+
+// ResolveService returns the first soundtouch-service instance found via mDNS
+func ResolveService(ctx context.Context, timeout time.Duration) (*ServiceInstance, error) {
+	instances, err := ResolveServices(ctx, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(instances) == 0 {
+		return nil, fmt.Errorf("no soundtouch-service found via mDNS")
+	}
+
+	return instances[0], nil
+}
+
+// serviceEntryToInstance converts an mDNS answer for ServiceType; it returns nil for other services
+func serviceEntryToInstance(entry *mdns.ServiceEntry) *ServiceInstance {
+	if entry == nil || !strings.Contains(entry.Name, ServiceType) {
+		return nil
+	}
+
+	var host string
+
+	switch {
+	case entry.AddrV4 != nil:
+		host = entry.AddrV4.String()
+	case entry.AddrV6 != nil:
+		host = entry.AddrV6.String()
+	default:
+		host = strings.TrimSuffix(entry.Host, ".")
+	}
+
+	if host == "" {
+		return nil
+	}
+
+	instance := &ServiceInstance{
+		Name:      unescapeInstanceName(strings.TrimSuffix(entry.Name, "."+ServiceType+"."+soundTouchDomain)),
+		Host:      host,
+		HTTPPort:  entry.Port,
+		APIPath:   DefaultServiceAPIPath,
+		ProxyPath: DefaultServiceProxyPath,
+	}
+
+	for _, field := range entry.InfoFields {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "version":
+			instance.Version = value
+		case "http_port":
+			if port, err := strconv.Atoi(value); err == nil && port > 0 {
+				instance.HTTPPort = port
+			}
+		case "https_port":
+			if port, err := strconv.Atoi(value); err == nil {
+				instance.HTTPSPort = port
+			}
+		case "api_path":
+			instance.APIPath = value
+		case "proxy_path":
+			instance.ProxyPath = value
+		}
+	}
+
+	if instance.HTTPPort == 0 {
+		return nil
+	}
+
+	return instance
+}
+
+// unescapeInstanceName removes the DNS escaping of an mDNS instance name
+func unescapeInstanceName(name string) string {
+	name = strings.ReplaceAll(name, `\ `, " ")
+	name = strings.ReplaceAll(name, `\.`, ".")
+
+	return strings.ReplaceAll(name, `\\`, `\`)
+}
+
+// localUnicastIPs returns the IPv4 addresses of all up, non-loopback interfaces
+func localUnicastIPs() []net.IP {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil
+	}
+
+	var ips []net.IP
+
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.To4() != nil && !ipNet.IP.IsLoopback() {
+				ips = append(ips, ipNet.IP)
+			}
+		}
+	}
+
+	return ips
+}
@@ -0,0 +1,44 @@
+package discovery
+
+import (
+	"net"
+	"testing"
+
+	"github.com/hashicorp/mdns"
+)
+
+func TestServiceAnnouncement_TXT(t *testing.T) {
+	announcement := ServiceAnnouncement{Instance: "Living Room", Version: "1.2.3", HTTPPort: 8000, HTTPSPort: 8443}
+
+	entry := &mdns.ServiceEntry{
+		Name:       `Living\ Room._soundtouch-service._tcp.local.`,
+		AddrV4:     net.ParseIP("192.168.1.5"),
+		Port:       8000,
+		InfoFields: announcement.TXT(),
+	}
+
+	instance := serviceEntryToInstance(entry)
+	if instance == nil {
+		t.Fatal("Expected the entry to convert to a service instance")
+	}
+
+	if instance.Name != "Living Room" || instance.Version != "1.2.3" || instance.HTTPSPort != 8443 || instance.APIPath != "/api" {
+		t.Errorf("Unexpected instance: %+v", instance)
+	}
+
+	if got := instance.ProxyURL(); got != "http://192.168.1.5:8000/proxy" {
+		t.Errorf("Unexpected proxy URL: %s", got)
+	}
+}
+
+func TestServiceEntryToInstance_IgnoresOtherServices(t *testing.T) {
+	entry := &mdns.ServiceEntry{
+		Name:   "Kitchen._soundtouch._tcp.local.",
+		AddrV4: net.ParseIP("192.168.1.10"),
+		Port:   8090,
+	}
+
+	if instance := serviceEntryToInstance(entry); instance != nil {
+		t.Errorf("Expected speakers to be ignored, got %+v", instance)
+	}
+}
@@ -7,6 +7,9 @@ const (
 	// SSDP multicast address and port
 	ssdpAddr = "239.255.255.250:1900"
 
+	// Link-local IPv6 SSDP multicast address and port
+	ssdpIPv6Addr = "[ff02::c]:1900"
+
 	// SoundTouch device URN for UPnP discovery
 	soundTouchURN = "urn:schemas-upnp-org:device:MediaRenderer:1"
 
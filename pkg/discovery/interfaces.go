@@ -0,0 +1,224 @@
+package discovery
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gesellix/bose-soundtouch/pkg/config"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+// virtualInterfacePrefixes are name prefixes of container bridges, virtual Ethernet pairs and
+// VPN tunnels. Speakers are never found behind them, so they are skipped unless listed explicitly.
+var virtualInterfacePrefixes = []string{
+	"docker", "br-", "veth", "virbr", "cni", "flannel", "cali", "vxlan", "tun", "tap", "tailscale", "wg", "zt",
+}
+
+// DiscoveryInterface is a network interface used for multicast discovery
+type DiscoveryInterface struct {
+	Interface net.Interface
+	IPv4      []net.IP
+	IPv6      []net.IP
+}
+
+// Name returns the name of the interface
+func (i DiscoveryInterface) Name() string {
+	return i.Interface.Name
+}
+
+// SelectInterfaces returns the interfaces to run multicast discovery on. If names are given,
+// exactly these interfaces are used; otherwise all up, multicast-capable interfaces except
+// loopback, point-to-point links and container bridges. IPv6 addresses are only collected
+// with ipv6 set.
+func SelectInterfaces(names []string, ipv6 bool) ([]DiscoveryInterface, error) {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list network interfaces: %w", err)
+	}
+
+	return selectInterfaces(interfaces, func(iface net.Interface) ([]net.Addr, error) {
+		return iface.Addrs()
+	}, names, ipv6)
+}
+
+func selectInterfaces(interfaces []net.Interface, addrs func(net.Interface) ([]net.Addr, error), names []string, ipv6 bool) ([]DiscoveryInterface, error) {
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	var selected []DiscoveryInterface
+
+	for _, iface := range interfaces {
+		if len(wanted) > 0 {
+			if !wanted[iface.Name] {
+				continue
+			}
+
+			delete(wanted, iface.Name)
+		} else if !eligibleInterface(iface) {
+			continue
+		}
+
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagMulticast == 0 {
+			continue
+		}
+
+		ifaceAddrs, err := addrs(iface)
+		if err != nil {
+			continue
+		}
+
+		candidate := DiscoveryInterface{Interface: iface}
+
+		for _, addr := range ifaceAddrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || ipNet.IP.IsLoopback() {
+				continue
+			}
+
+			switch {
+			case ipNet.IP.To4() != nil:
+				candidate.IPv4 = append(candidate.IPv4, ipNet.IP)
+			case ipv6:
+				candidate.IPv6 = append(candidate.IPv6, ipNet.IP)
+			}
+		}
+
+		if len(candidate.IPv4) > 0 || len(candidate.IPv6) > 0 {
+			selected = append(selected, candidate)
+		}
+	}
+
+	if len(wanted) > 0 {
+		missing := make([]string, 0, len(wanted))
+		for _, name := range names {
+			if wanted[name] {
+				missing = append(missing, name)
+			}
+		}
+
+		return selected, fmt.Errorf("network interface not found: %s", strings.Join(missing, ", "))
+	}
+
+	return selected, nil
+}
+
+// eligibleInterface reports whether an interface is used for discovery when none are configured
+func eligibleInterface(iface net.Interface) bool {
+	if iface.Flags&net.FlagLoopback != 0 || iface.Flags&net.FlagPointToPoint != 0 {
+		return false
+	}
+
+	for _, prefix := range virtualInterfacePrefixes {
+		if strings.HasPrefix(iface.Name, prefix) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// listenMulticast joins the IPv4 group and, with IPv6 enabled, the link-local IPv6 group on all
+// discovery interfaces. It returns one connection per address family; interfaces that cannot
+// join are reported in the error.
+func listenMulticast(cfg *config.Config, ipv4Group, ipv6Group string) ([]*net.UDPConn, error) {
+	interfaces, err := SelectInterfaces(cfg.DiscoveryInterfaces, cfg.DiscoveryIPv6)
+
+	errs := []error{err}
+
+	var ipv4Interfaces, ipv6Interfaces []*net.Interface
+
+	for i := range interfaces {
+		if len(interfaces[i].IPv4) > 0 {
+			ipv4Interfaces = append(ipv4Interfaces, &interfaces[i].Interface)
+		}
+
+		if len(interfaces[i].IPv6) > 0 {
+			ipv6Interfaces = append(ipv6Interfaces, &interfaces[i].Interface)
+		}
+	}
+
+	var conns []*net.UDPConn
+
+	// Without usable interfaces, fall back to the system default multicast interface
+	if len(ipv4Interfaces) > 0 || len(ipv6Interfaces) == 0 {
+		conn, err := joinMulticast("udp4", ipv4Group, ipv4Interfaces)
+		if conn != nil {
+			conns = append(conns, conn)
+		}
+
+		errs = append(errs, err)
+	}
+
+	if len(ipv6Interfaces) > 0 {
+		conn, err := joinMulticast("udp6", ipv6Group, ipv6Interfaces)
+		if conn != nil {
+			conns = append(conns, conn)
+		}
+
+		errs = append(errs, err)
+	}
+
+	return conns, errors.Join(errs...)
+}
+
+// joinMulticast listens on the port of group and joins it on every interface
+func joinMulticast(network, group string, interfaces []*net.Interface) (*net.UDPConn, error) {
+	addr, err := net.ResolveUDPAddr(network, group)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", group, err)
+	}
+
+	var first *net.Interface
+	if len(interfaces) > 0 {
+		first = interfaces[0]
+	}
+
+	conn, err := net.ListenMulticastUDP(network, first, addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to join %s: %w", group, err)
+	}
+
+	var errs []error
+
+	for _, iface := range interfaces[min(1, len(interfaces)):] {
+		if network == "udp6" {
+			err = ipv6.NewPacketConn(conn).JoinGroup(iface, addr)
+		} else {
+			err = ipv4.NewPacketConn(conn).JoinGroup(iface, addr)
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to join %s on %s: %w", group, iface.Name, err))
+		}
+	}
+
+	return conn, errors.Join(errs...)
+}
+
+// setMulticastInterface sends multicast packets of conn out of iface
+func setMulticastInterface(conn *net.UDPConn, network string, iface *net.Interface) error {
+	if network == "udp6" {
+		return ipv6.NewPacketConn(conn).SetMulticastInterface(iface)
+	}
+
+	return ipv4.NewPacketConn(conn).SetMulticastInterface(iface)
+}
+
+// withZone adds the zone of the sender to a link-local IPv6 host; SSDP and mDNS answers
+// carry link-local addresses without the interface they were received on
+func withZone(host string, from *net.UDPAddr) string {
+	if from == nil || from.Zone == "" || strings.Contains(host, "%") {
+		return host
+	}
+
+	if ip := net.ParseIP(host); ip != nil && ip.To4() == nil && ip.IsLinkLocalUnicast() {
+		return host + "%" + from.Zone
+	}
+
+	return host
+}
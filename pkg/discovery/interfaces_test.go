@@ -0,0 +1,91 @@
+package discovery
+
+import (
+	"net"
+	"testing"
+)
+
+func testInterfaces() ([]net.Interface, func(net.Interface) ([]net.Addr, error)) {
+	up := net.FlagUp | net.FlagMulticast
+
+	interfaces := []net.Interface{
+		{Index: 1, Name: "lo", Flags: up | net.FlagLoopback},
+		{Index: 2, Name: "eth0", Flags: up},
+		{Index: 3, Name: "wlan0", Flags: up},
+		{Index: 4, Name: "docker0", Flags: up},
+		{Index: 5, Name: "eth1", Flags: net.FlagMulticast},
+	}
+
+	addrs := map[string][]string{
+		"lo":      {"127.0.0.1/8", "::1/128"},
+		"eth0":    {"192.168.1.5/24", "fe80::1/64"},
+		"wlan0":   {"fe80::2/64"},
+		"docker0": {"172.17.0.1/16"},
+		"eth1":    {"10.0.0.5/24"},
+	}
+
+	return interfaces, func(iface net.Interface) ([]net.Addr, error) {
+		var result []net.Addr
+
+		for _, cidr := range addrs[iface.Name] {
+			ip, ipNet, _ := net.ParseCIDR(cidr)
+			ipNet.IP = ip
+			result = append(result, ipNet)
+		}
+
+		return result, nil
+	}
+}
+
+func TestSelectInterfaces_Default(t *testing.T) {
+	interfaces, addrs := testInterfaces()
+
+	selected, err := selectInterfaces(interfaces, addrs, nil, true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(selected) != 2 || selected[0].Name() != "eth0" || selected[1].Name() != "wlan0" {
+		t.Fatalf("Expected eth0 and wlan0, got %+v", selected)
+	}
+
+	if len(selected[0].IPv4) != 1 || len(selected[0].IPv6) != 1 || len(selected[1].IPv4) != 0 {
+		t.Errorf("Unexpected addresses: %+v", selected)
+	}
+
+	selected, _ = selectInterfaces(interfaces, addrs, nil, false)
+	if len(selected) != 1 || selected[0].Name() != "eth0" || len(selected[0].IPv6) != 0 {
+		t.Errorf("Expected only eth0 over IPv4, got %+v", selected)
+	}
+}
+
+func TestSelectInterfaces_Configured(t *testing.T) {
+	interfaces, addrs := testInterfaces()
+
+	selected, err := selectInterfaces(interfaces, addrs, []string{"docker0"}, true)
+	if err != nil || len(selected) != 1 || selected[0].Name() != "docker0" {
+		t.Errorf("Expected an explicitly listed bridge to be used, got %+v, %v", selected, err)
+	}
+
+	selected, err = selectInterfaces(interfaces, addrs, []string{"eth0", "eth9"}, true)
+	if err == nil || len(selected) != 1 {
+		t.Errorf("Expected eth0 and an error for eth9, got %+v, %v", selected, err)
+	}
+}
+
+func TestWithZone(t *testing.T) {
+	from := &net.UDPAddr{IP: net.ParseIP("fe80::10"), Zone: "eth0"}
+
+	tests := map[string]string{
+		"fe80::10":      "fe80::10%eth0",
+		"fe80::10%eth1": "fe80::10%eth1",
+		"2001:db8::10":  "2001:db8::10",
+		"192.168.1.10":  "192.168.1.10",
+	}
+
+	for host, expected := range tests {
+		if got := withZone(host, from); got != expected {
+			t.Errorf("withZone(%q) = %s, expected %s", host, got, expected)
+		}
+	}
+}
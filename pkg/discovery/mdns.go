@@ -6,15 +6,19 @@ import (
 	"log"
 	"net"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/gesellix/bose-soundtouch/pkg/config"
 	"github.com/gesellix/bose-soundtouch/pkg/models"
 	"github.com/hashicorp/mdns"
 )
 
 // MDNSDiscoveryService handles mDNS/Bonjour discovery of SoundTouch devices
 type MDNSDiscoveryService struct {
-	timeout time.Duration
+	timeout    time.Duration
+	interfaces []string
+	ipv6       bool
 }
 
 // NewMDNSDiscoveryService creates a new mDNS discovery service
@@ -25,9 +29,20 @@ func NewMDNSDiscoveryService(timeout time.Duration) *MDNSDiscoveryService {
 
 	return &MDNSDiscoveryService{
 		timeout: timeout,
+		ipv6:    true,
 	}
 }
 
+// NewMDNSDiscoveryServiceWithConfig creates a new mDNS discovery service that queries the
+// configured interfaces
+func NewMDNSDiscoveryServiceWithConfig(cfg *config.Config) *MDNSDiscoveryService {
+	service := NewMDNSDiscoveryService(cfg.DiscoveryTimeout)
+	service.interfaces = cfg.DiscoveryInterfaces
+	service.ipv6 = cfg.DiscoveryIPv6
+
+	return service
+}
+
 // DiscoverDevices discovers SoundTouch devices using mDNS
 func (m *MDNSDiscoveryService) DiscoverDevices(ctx context.Context) ([]*models.DiscoveredDevice, error) {
 	// Initialize devices slice to ensure it's never nil
@@ -40,48 +55,40 @@ func (m *MDNSDiscoveryService) DiscoverDevices(ctx context.Context) ([]*models.D
 	timeoutCtx, cancel := context.WithTimeout(ctx, m.timeout)
 	defer cancel()
 
-	// Start mDNS query in a goroutine
+	// Query all discovery interfaces concurrently
 	go func() {
 		defer close(entries)
 
 		log.Printf("mDNS: Starting discovery for service '%s.%s' with timeout %v",
 			soundTouchServiceType, soundTouchDomain, m.timeout)
 
-		// IPv4-only query to fix "no route to host" errors on IPv6
-		// This addresses the issue where hashicorp/mdns fails with:
-		// "write udp6 [::]:port->[ff02::fb]:5353: sendto: no route to host"
-		// The trailing dot in service names is handled correctly by separating
-		// service and domain parameters as expected by the library.
-		err := mdns.Query(&mdns.QueryParam{
-			Service:     "_soundtouch._tcp",
-			Domain:      "local.",
-			Timeout:     m.timeout,
-			Entries:     entries,
-			DisableIPv6: true,                 // Force IPv4 only to avoid routing issues
-			Interface:   m.getIPv4Interface(), // Use specific interface if available
-		})
+		interfaces, err := SelectInterfaces(m.interfaces, m.ipv6)
 		if err != nil {
-			log.Printf("mDNS IPv4 query failed: %v", err)
-
-			// Fallback to standard query (both IPv4 and IPv6)
-			log.Printf("mDNS: Falling back to standard query...")
-
-			err = mdns.Query(&mdns.QueryParam{
-				Service: "_soundtouch._tcp",
-				Domain:  "local.",
-				Timeout: m.timeout,
-				Entries: entries,
-			})
-			if err != nil {
-				log.Printf("mDNS query completed with error: %v", err)
-			} else {
-				log.Printf("mDNS query completed successfully")
-			}
-		} else {
-			log.Printf("mDNS IPv4 query completed successfully")
+			log.Printf("mDNS: %v", err)
+		}
+
+		if len(interfaces) == 0 {
+			m.query(nil, false, entries)
+			return
 		}
+
+		var wg sync.WaitGroup
+
+		for i := range interfaces {
+			wg.Add(1)
+
+			go func(iface DiscoveryInterface) {
+				defer wg.Done()
+
+				m.query(&iface.Interface, len(iface.IPv6) > 0, entries)
+			}(interfaces[i])
+		}
+
+		wg.Wait()
 	}()
 
+	seen := make(map[string]bool)
+
 	// Collect discovered devices
 	for {
 		select {
@@ -105,7 +112,13 @@ func (m *MDNSDiscoveryService) DiscoverDevices(ctx context.Context) ([]*models.D
 			}
 
 			device := m.serviceEntryToDevice(entry)
+			if device != nil && seen[device.Host] {
+				continue
+			}
+
 			if device != nil {
+				seen[device.Host] = true
+
 				log.Printf("mDNS: Successfully converted to device: %s at %s:%d", device.Name, device.Host, device.Port)
 				devices = append(devices, device)
 			} else {
@@ -115,6 +128,41 @@ func (m *MDNSDiscoveryService) DiscoverDevices(ctx context.Context) ([]*models.D
 	}
 }
 
+// query sends an mDNS query on one interface, or the default interface if iface is nil.
+// IPv6 is queried as well if ipv6 is set; if that fails, the query is repeated over IPv4 only.
+func (m *MDNSDiscoveryService) query(iface *net.Interface, ipv6 bool, entries chan<- *mdns.ServiceEntry) {
+	name := "default interface"
+	if iface != nil {
+		name = iface.Name
+	}
+
+	params := &mdns.QueryParam{
+		Service:     soundTouchServiceType,
+		Domain:      soundTouchDomain,
+		Timeout:     m.timeout,
+		Entries:     entries,
+		Interface:   iface,
+		DisableIPv6: !ipv6,
+	}
+
+	err := mdns.Query(params)
+	if err != nil && ipv6 {
+		// hashicorp/mdns fails the whole query with "no route to host" if IPv6
+		// multicast is not routable on the interface
+		log.Printf("mDNS: Query on %s failed, retrying over IPv4 only: %v", name, err)
+
+		params.DisableIPv6 = true
+		err = mdns.Query(params)
+	}
+
+	if err != nil {
+		log.Printf("mDNS: Query on %s failed: %v", name, err)
+		return
+	}
+
+	log.Printf("mDNS: Query on %s completed successfully", name)
+}
+
 // serviceEntryToDevice converts an mdns ServiceEntry to a DiscoveredDevice
 func (m *MDNSDiscoveryService) serviceEntryToDevice(entry *mdns.ServiceEntry) *models.DiscoveredDevice {
 	if entry == nil {
@@ -134,6 +182,12 @@ func (m *MDNSDiscoveryService) serviceEntryToDevice(entry *mdns.ServiceEntry) *m
 		ipSource = "IPv4"
 
 		log.Printf("mDNS: Using IPv4 address: %s", host)
+	case entry.AddrV6IPAddr != nil:
+		// Keeps the zone of link-local addresses
+		host = entry.AddrV6IPAddr.String()
+		ipSource = "IPv6"
+
+		log.Printf("mDNS: Using IPv6 address: %s", host)
 	case entry.AddrV6 != nil:
 		host = entry.AddrV6.String()
 		ipSource = "IPv6"
@@ -207,8 +261,8 @@ func (m *MDNSDiscoveryService) serviceEntryToDevice(entry *mdns.ServiceEntry) *m
 		Name:            name,
 		LastSeen:        time.Now(),
 		DiscoveryMethod: "mDNS/Bonjour",
-		APIBaseURL:      fmt.Sprintf("http://%s:%d/", host, port),
-		InfoURL:         fmt.Sprintf("http://%s:%d/info", host, port),
+		APIBaseURL:      config.HostURL(host, port) + "/",
+		InfoURL:         config.HostURL(host, port) + "/info",
 		MDNSHostname:    entry.Host,
 		MDNSService:     entry.Name,
 	}
@@ -217,47 +271,3 @@ func (m *MDNSDiscoveryService) serviceEntryToDevice(entry *mdns.ServiceEntry) *m
 
 	return device
 }
-
-// getIPv4Interface returns the first suitable IPv4 network interface
-func (m *MDNSDiscoveryService) getIPv4Interface() *net.Interface {
-	interfaces, err := net.Interfaces()
-	if err != nil {
-		log.Printf("mDNS: Failed to get network interfaces: %v", err)
-		return nil
-	}
-
-	for _, iface := range interfaces {
-		// Skip loopback, down interfaces, and point-to-point interfaces
-		if iface.Flags&net.FlagLoopback != 0 ||
-			iface.Flags&net.FlagUp == 0 ||
-			iface.Flags&net.FlagPointToPoint != 0 {
-			continue
-		}
-
-		// Check if this interface has IPv4 addresses
-		addrs, err := iface.Addrs()
-		if err != nil {
-			continue
-		}
-
-		hasIPv4 := false
-
-		for _, addr := range addrs {
-			if ipNet, ok := addr.(*net.IPNet); ok {
-				if ipNet.IP.To4() != nil && !ipNet.IP.IsLoopback() {
-					hasIPv4 = true
-					break
-				}
-			}
-		}
-
-		if hasIPv4 {
-			log.Printf("mDNS: Using IPv4 interface: %s", iface.Name)
-			return &iface
-		}
-	}
-
-	log.Printf("mDNS: No suitable IPv4 interface found")
-
-	return nil
-}
@@ -0,0 +1,351 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+// registryFileName is the file name of the device registry inside a data directory
+const registryFileName = "devices.json"
+
+// registryProbeTimeout bounds the /info request checking whether a previous address still reaches its device
+const registryProbeTimeout = 2 * time.Second
+
+// RegistryAddress is an address a device was seen at
+type RegistryAddress struct {
+	Host      string    `json:"host"`
+	Port      int       `json:"port"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// RegistryEntry is the stable identity of a device, keyed by the device ID from /info
+type RegistryEntry struct {
+	DeviceID         string            `json:"deviceId"`
+	MAC              string            `json:"mac,omitempty"`
+	Name             string            `json:"name"`
+	ModelID          string            `json:"modelId,omitempty"`
+	SerialNo         string            `json:"serialNo,omitempty"`
+	Host             string            `json:"host"`
+	Port             int               `json:"port"`
+	Addresses        []RegistryAddress `json:"addresses"`
+	FirstSeen        time.Time         `json:"firstSeen"`
+	LastSeen         time.Time         `json:"lastSeen"`
+	DiscoveryMethods []string          `json:"discoveryMethods"`
+}
+
+// DeviceRegistry remembers devices across IP address changes. Discovery records every
+// identified device, and host references such as names, device IDs, MAC addresses or
+// outdated IP addresses resolve to the address the device was last seen at.
+type DeviceRegistry struct {
+	path    string
+	mu      sync.Mutex
+	entries map[string]*RegistryEntry
+
+	// probeInfo reads /info of an address, replaced in tests
+	probeInfo func(ctx context.Context, host string, port int) (*models.DeviceInfo, error)
+}
+
+// DefaultRegistryPath returns the registry location in the user configuration directory
+func DefaultRegistryPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return registryFileName
+	}
+
+	return filepath.Join(dir, "soundtouch", registryFileName)
+}
+
+// RegistryPathInDataDir returns the registry location inside a data directory
+func RegistryPathInDataDir(dataDir string) string {
+	return filepath.Join(dataDir, registryFileName)
+}
+
+// OpenDeviceRegistry loads the registry stored at path; a missing file yields an empty registry
+func OpenDeviceRegistry(path string) (*DeviceRegistry, error) {
+	r := &DeviceRegistry{path: path, entries: make(map[string]*RegistryEntry), probeInfo: requestDeviceInfo}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read device registry: %w", err)
+	}
+
+	var entries []*RegistryEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse device registry: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.DeviceID != "" {
+			r.entries[strings.ToUpper(entry.DeviceID)] = entry
+		}
+	}
+
+	return r, nil
+}
+
+// Path returns the file the registry is stored in
+func (r *DeviceRegistry) Path() string {
+	return r.path
+}
+
+// Record updates the registry with an identified device. Devices without a device ID
+// from /info are ignored. It returns the updated entry and whether the device moved
+// to a new address.
+func (r *DeviceRegistry) Record(device *models.DiscoveredDevice) (RegistryEntry, bool) {
+	deviceID := strings.ToUpper(device.Metadata["deviceID"])
+	if deviceID == "" {
+		return RegistryEntry{}, false
+	}
+
+	seen := device.LastSeen
+	if seen.IsZero() {
+		seen = time.Now()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, known := r.entries[deviceID]
+	if !known {
+		entry = &RegistryEntry{DeviceID: deviceID, FirstSeen: seen}
+		r.entries[deviceID] = entry
+	}
+
+	moved := known && (entry.Host != device.Host || entry.Port != device.Port)
+
+	entry.Host = device.Host
+	entry.Port = device.Port
+	entry.LastSeen = seen
+
+	if device.Name != "" {
+		entry.Name = device.Name
+	}
+
+	if device.ModelID != "" {
+		entry.ModelID = device.ModelID
+	}
+
+	if device.SerialNo != "" {
+		entry.SerialNo = device.SerialNo
+	}
+
+	if mac := device.Metadata["mac"]; mac != "" {
+		entry.MAC = strings.ToUpper(mac)
+	}
+
+	entry.recordAddress(device.Host, device.Port, seen)
+
+	for _, method := range strings.Split(device.DiscoveryMethod, "+") {
+		if method != "" && !containsString(entry.DiscoveryMethods, method) {
+			entry.DiscoveryMethods = append(entry.DiscoveryMethods, method)
+		}
+	}
+
+	return entry.clone(), moved
+}
+
+// recordAddress adds an address to the history or refreshes its last seen time
+func (e *RegistryEntry) recordAddress(host string, port int, seen time.Time) {
+	for i := range e.Addresses {
+		if e.Addresses[i].Host == host && e.Addresses[i].Port == port {
+			e.Addresses[i].LastSeen = seen
+			return
+		}
+	}
+
+	e.Addresses = append(e.Addresses, RegistryAddress{Host: host, Port: port, FirstSeen: seen, LastSeen: seen})
+}
+
+func (e *RegistryEntry) clone() RegistryEntry {
+	clone := *e
+	clone.Addresses = append([]RegistryAddress(nil), e.Addresses...)
+	clone.DiscoveryMethods = append([]string(nil), e.DiscoveryMethods...)
+
+	return clone
+}
+
+// Save writes the registry to its file
+func (r *DeviceRegistry) Save() error {
+	entries := r.Entries()
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode device registry: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return fmt.Errorf("failed to create registry directory: %w", err)
+	}
+
+	if err := os.WriteFile(r.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write device registry: %w", err)
+	}
+
+	return nil
+}
+
+// Entries returns all known devices sorted by name
+func (r *DeviceRegistry) Entries() []RegistryEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := make([]RegistryEntry, 0, len(r.entries))
+	for _, entry := range r.entries {
+		entries = append(entries, entry.clone())
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if !strings.EqualFold(entries[i].Name, entries[j].Name) {
+			return strings.ToLower(entries[i].Name) < strings.ToLower(entries[j].Name)
+		}
+
+		return entries[i].DeviceID < entries[j].DeviceID
+	})
+
+	return entries
+}
+
+// Lookup finds a device by device ID, MAC address, current address, name or a previous address,
+// each optionally followed by a port.
+// Current addresses win over previous ones, so an IP reused by another device resolves to that device.
+func (r *DeviceRegistry) Lookup(ref string) (RegistryEntry, bool) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return RegistryEntry{}, false
+	}
+
+	host, port := splitRegistryRef(ref)
+	normalized := normalizeHardwareAddress(host)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	matchers := []func(entry *RegistryEntry) bool{
+		func(entry *RegistryEntry) bool {
+			return entry.DeviceID == normalized || (entry.MAC != "" && normalizeHardwareAddress(entry.MAC) == normalized)
+		},
+		func(entry *RegistryEntry) bool {
+			return entry.Host == host && (port == 0 || entry.Port == port)
+		},
+		func(entry *RegistryEntry) bool {
+			return strings.EqualFold(entry.Name, host)
+		},
+		func(entry *RegistryEntry) bool {
+			for _, address := range entry.Addresses {
+				if address.Host == host && (port == 0 || address.Port == port) {
+					return true
+				}
+			}
+
+			return false
+		},
+	}
+
+	for _, matches := range matchers {
+		var found *RegistryEntry
+
+		for _, entry := range r.entries {
+			// Prefer the most recently seen device if several match
+			if matches(entry) && (found == nil || entry.LastSeen.After(found.LastSeen)) {
+				found = entry
+			}
+		}
+
+		if found != nil {
+			return found.clone(), true
+		}
+	}
+
+	return RegistryEntry{}, false
+}
+
+// ResolveHost returns the current address of the device a reference points to.
+// Names and device IDs resolve to the registered address. An explicit address is kept unless
+// the device registered with it moved away: it does not answer anymore, or /info at the address
+// reports another device.
+func (r *DeviceRegistry) ResolveHost(ref string) (string, int, bool) {
+	entry, ok := r.Lookup(ref)
+	if !ok {
+		return "", 0, false
+	}
+
+	host, port := splitRegistryRef(strings.TrimSpace(ref))
+
+	if ip, _, _ := strings.Cut(host, "%"); net.ParseIP(ip) == nil {
+		return entry.Host, entry.Port, true
+	}
+
+	if host == entry.Host && (port == 0 || port == entry.Port) {
+		return entry.Host, entry.Port, true
+	}
+
+	if port == 0 {
+		port = 8090
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), registryProbeTimeout)
+	defer cancel()
+
+	if info, err := r.probeInfo(ctx, host, port); err == nil && normalizeHardwareAddress(info.DeviceID) == normalizeHardwareAddress(entry.DeviceID) {
+		return host, port, true
+	}
+
+	return entry.Host, entry.Port, true
+}
+
+// ResolveSpeaker implements client.SpeakerResolver
+func (r *DeviceRegistry) ResolveSpeaker(ref string) (deviceID, host string, port int, ok bool) {
+	entry, ok := r.Lookup(ref)
+	if !ok {
+		return "", "", 0, false
+	}
+
+	return entry.DeviceID, entry.Host, entry.Port, true
+}
+
+// splitRegistryRef splits an optional port off a reference
+func splitRegistryRef(ref string) (string, int) {
+	host, portStr, err := net.SplitHostPort(ref)
+	if err != nil {
+		return ref, 0
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return ref, 0
+	}
+
+	return host, port
+}
+
+// normalizeHardwareAddress upper-cases device IDs and MAC addresses and drops separators
+func normalizeHardwareAddress(value string) string {
+	return strings.ToUpper(strings.NewReplacer(":", "", "-", "").Replace(value))
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,204 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/config"
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+func registryDevice(deviceID, name, host, method string, seen time.Time) *models.DiscoveredDevice {
+	return &models.DiscoveredDevice{
+		Name:            name,
+		Host:            host,
+		Port:            8090,
+		LastSeen:        seen,
+		DiscoveryMethod: method,
+		Metadata:        map[string]string{"deviceID": deviceID, "mac": deviceID},
+	}
+}
+
+func TestDeviceRegistry_RecordAndLookup(t *testing.T) {
+	registry, err := OpenDeviceRegistry(filepath.Join(t.TempDir(), "devices.json"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if _, moved := registry.Record(registryDevice("AABBCC000001", "Kitchen", "192.168.1.10", "SSDP/UPnP", start)); moved {
+		t.Error("Expected a new device not to count as moved")
+	}
+
+	entry, moved := registry.Record(registryDevice("aabbcc000001", "Kitchen", "192.168.1.23", "Configuration+Subnet scan", start.Add(time.Hour)))
+	if !moved {
+		t.Error("Expected the device to have moved")
+	}
+
+	if entry.Host != "192.168.1.23" || len(entry.Addresses) != 2 || !entry.FirstSeen.Equal(start) || !entry.LastSeen.Equal(start.Add(time.Hour)) {
+		t.Errorf("Unexpected entry after move: %+v", entry)
+	}
+
+	if got := strings.Join(entry.DiscoveryMethods, ","); got != "SSDP/UPnP,Configuration,Subnet scan" {
+		t.Errorf("Unexpected discovery methods: %s", got)
+	}
+
+	// Another speaker took over the old address
+	registry.Record(registryDevice("AABBCC000002", "Office", "192.168.1.10", "mDNS", start.Add(2*time.Hour)))
+
+	tests := []struct {
+		ref      string
+		expected string
+	}{
+		{"AABBCC000001", "Kitchen"},
+		{"aa:bb:cc:00:00:01", "Kitchen"},
+		{"kitchen", "Kitchen"},
+		{"192.168.1.23:8090", "Kitchen"},
+		{"192.168.1.10", "Office"},
+	}
+
+	for _, tt := range tests {
+		if entry, ok := registry.Lookup(tt.ref); !ok || entry.Name != tt.expected {
+			t.Errorf("Lookup(%q) = %+v, %v; expected %s", tt.ref, entry, ok, tt.expected)
+		}
+	}
+
+	if _, ok := registry.Lookup("192.168.1.99"); ok {
+		t.Error("Expected unknown address not to resolve")
+	}
+
+	if _, moved := registry.Record(&models.DiscoveredDevice{Host: "192.168.1.50"}); moved || len(registry.Entries()) != 2 {
+		t.Error("Expected devices without a device ID to be ignored")
+	}
+}
+
+func TestDeviceRegistry_FollowsMovedDevice(t *testing.T) {
+	registry, _ := OpenDeviceRegistry(filepath.Join(t.TempDir(), "devices.json"))
+	registry.probeInfo = func(context.Context, string, int) (*models.DeviceInfo, error) {
+		return nil, errors.New("connection refused")
+	}
+
+	registry.Record(registryDevice("AABBCC000001", "Kitchen", "192.168.1.10", "SSDP/UPnP", time.Now()))
+	registry.Record(registryDevice("AABBCC000001", "Kitchen", "192.168.1.23", "SSDP/UPnP", time.Now()))
+
+	host, port, ok := registry.ResolveHost("192.168.1.10:8090")
+	if !ok || host != "192.168.1.23" || port != 8090 {
+		t.Errorf("Expected old address to resolve to 192.168.1.23:8090, got %s:%d (%v)", host, port, ok)
+	}
+
+	if host, _, ok := registry.ResolveHost("kitchen"); !ok || host != "192.168.1.23" {
+		t.Errorf("Expected the name to resolve to 192.168.1.23, got %s (%v)", host, ok)
+	}
+
+	deviceID, host, _, ok := registry.ResolveSpeaker("192.168.1.10")
+	if !ok || deviceID != "AABBCC000001" || host != "192.168.1.23" {
+		t.Errorf("Unexpected speaker resolution: %s at %s (%v)", deviceID, host, ok)
+	}
+}
+
+func TestDeviceRegistry_KeepsAnsweringAddress(t *testing.T) {
+	registry, _ := OpenDeviceRegistry(filepath.Join(t.TempDir(), "devices.json"))
+
+	answering := map[string]string{"192.168.1.10": "AABBCC000001", "192.168.1.11": "AABBCC000003"}
+	registry.probeInfo = func(_ context.Context, host string, _ int) (*models.DeviceInfo, error) {
+		if deviceID, ok := answering[host]; ok {
+			return &models.DeviceInfo{DeviceID: deviceID}, nil
+		}
+
+		return nil, errors.New("connection refused")
+	}
+
+	registry.Record(registryDevice("AABBCC000001", "Kitchen", "192.168.1.10", "SSDP/UPnP", time.Now()))
+	registry.Record(registryDevice("AABBCC000001", "Kitchen", "192.168.1.23", "SSDP/UPnP", time.Now()))
+	registry.Record(registryDevice("AABBCC000002", "Office", "192.168.1.11", "SSDP/UPnP", time.Now()))
+	registry.Record(registryDevice("AABBCC000002", "Office", "192.168.1.24", "SSDP/UPnP", time.Now()))
+
+	tests := []struct {
+		ref      string
+		expected string
+	}{
+		// The device still answers at the address it was asked for
+		{"192.168.1.10:8090", "192.168.1.10"},
+		// Another device answers at the previous address
+		{"192.168.1.11:8090", "192.168.1.24"},
+		{"192.168.1.23", "192.168.1.23"},
+	}
+
+	for _, tt := range tests {
+		if host, _, ok := registry.ResolveHost(tt.ref); !ok || host != tt.expected {
+			t.Errorf("ResolveHost(%q) = %s (%v); expected %s", tt.ref, host, ok, tt.expected)
+		}
+	}
+}
+
+func TestDeviceRegistry_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "devices.json")
+
+	registry, err := OpenDeviceRegistry(path)
+	if err != nil {
+		t.Fatalf("Expected a missing file to open as empty registry, got %v", err)
+	}
+
+	registry.Record(registryDevice("AABBCC000001", "Kitchen", "192.168.1.10", "mDNS", time.Now()))
+	registry.Record(registryDevice("AABBCC000001", "Kitchen", "192.168.1.23", "mDNS", time.Now()))
+
+	if err := registry.Save(); err != nil {
+		t.Fatalf("Failed to save registry: %v", err)
+	}
+
+	reopened, err := OpenDeviceRegistry(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen registry: %v", err)
+	}
+
+	entries := reopened.Entries()
+	if len(entries) != 1 || entries[0].Host != "192.168.1.23" || len(entries[0].Addresses) != 2 || entries[0].MAC != "AABBCC000001" {
+		t.Errorf("Unexpected entries after reload: %+v", entries)
+	}
+}
+
+func TestUnifiedDiscovery_Registry(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.UPnPEnabled = false
+	cfg.MDNSEnabled = false
+	cfg.CacheEnabled = false
+	cfg.PreferredDevices = []config.DeviceConfig{{Name: "Configured", Host: "127.0.0.1", Port: newInfoServer(t)}}
+
+	registry, _ := OpenDeviceRegistry(filepath.Join(t.TempDir(), "devices.json"))
+
+	service := NewUnifiedDiscoveryService(cfg)
+	service.SetRegistry(registry)
+
+	if _, err := service.DiscoverDevices(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	entry, ok := registry.Lookup("AABBCC000001")
+	if !ok || entry.SerialNo != "SCM-SERIAL" || entry.Port != cfg.PreferredDevices[0].Port {
+		t.Fatalf("Expected the configured device to be identified and recorded, got %+v (%v)", entry, ok)
+	}
+
+	// The speaker got a new address after the configuration was written
+	moved := registryDevice("AABBCC000001", "Kitchen", "192.168.1.23", "SSDP/UPnP", time.Now())
+	moved.Port = entry.Port
+	registry.Record(moved)
+
+	devices := service.getConfiguredDevices()
+	if len(devices) != 1 || devices[0].Host != "127.0.0.1" {
+		t.Fatalf("Expected the configured address to be kept while the device answers there, got %+v", devices[0])
+	}
+
+	registry.probeInfo = func(context.Context, string, int) (*models.DeviceInfo, error) {
+		return nil, errors.New("connection refused")
+	}
+
+	devices = service.getConfiguredDevices()
+	if len(devices) != 1 || devices[0].Host != "192.168.1.23" || !strings.Contains(devices[0].InfoURL, "192.168.1.23") {
+		t.Errorf("Expected the configured device to follow the move, got %+v", devices[0])
+	}
+}
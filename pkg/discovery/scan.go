@@ -0,0 +1,313 @@
+package discovery
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/config"
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+const (
+	// Discovery method name of devices found by the subnet sweep
+	scanDiscoveryMethod = "Subnet scan"
+
+	// Default settings of the subnet sweep
+	defaultScanConcurrency = 64
+	defaultScanTimeout     = 750 * time.Millisecond
+)
+
+// SubnetScanner discovers SoundTouch devices by probing :8090/info on every address of the
+// configured subnets. It works on networks where SSDP and mDNS never reach the speakers.
+type SubnetScanner struct {
+	subnets     []*net.IPNet
+	concurrency int
+	timeout     time.Duration
+	port        int
+}
+
+// NewSubnetScanner creates a scanner for the given CIDR ranges
+func NewSubnetScanner(subnets []string, concurrency int, timeout time.Duration) (*SubnetScanner, error) {
+	if concurrency <= 0 {
+		concurrency = defaultScanConcurrency
+	}
+
+	if timeout <= 0 {
+		timeout = defaultScanTimeout
+	}
+
+	scanner := &SubnetScanner{concurrency: concurrency, timeout: timeout, port: 8090}
+
+	for _, subnet := range subnets {
+		if err := config.ValidateScanSubnet(subnet); err != nil {
+			return nil, err
+		}
+
+		_, ipNet, _ := net.ParseCIDR(subnet)
+		scanner.subnets = append(scanner.subnets, ipNet)
+	}
+
+	return scanner, nil
+}
+
+// NewSubnetScannerWithConfig creates a scanner from the scan settings of the configuration
+func NewSubnetScannerWithConfig(cfg *config.Config) (*SubnetScanner, error) {
+	return NewSubnetScanner(cfg.ScanSubnets, cfg.ScanConcurrency, cfg.ScanTimeout)
+}
+
+// Hosts returns the number of addresses the scanner probes
+func (s *SubnetScanner) Hosts() int {
+	return len(s.hosts())
+}
+
+// DiscoverDevices probes every address of the subnets and returns the devices answering /info
+func (s *SubnetScanner) DiscoverDevices(ctx context.Context) ([]*models.DiscoveredDevice, error) {
+	hosts := s.hosts()
+	log.Printf("Scan: Probing %d addresses with %d workers (timeout %v)", len(hosts), s.concurrency, s.timeout)
+
+	jobs := make(chan string)
+	results := make(chan *models.DiscoveredDevice)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < min(s.concurrency, len(hosts)); i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for host := range jobs {
+				if device, err := s.ProbeHost(ctx, host); err == nil {
+					results <- device
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+
+		for _, host := range hosts {
+			select {
+			case jobs <- host:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	devices := make([]*models.DiscoveredDevice, 0)
+	for device := range results {
+		log.Printf("Scan: Found %s at %s:%d", device.Name, device.Host, device.Port)
+		devices = append(devices, device)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return devices, fmt.Errorf("scan interrupted: %w", err)
+	}
+
+	return devices, nil
+}
+
+// ProbeHost reads /info of a single host and returns it as discovered device
+func (s *SubnetScanner) ProbeHost(ctx context.Context, host string) (*models.DiscoveredDevice, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	info, err := requestDeviceInfo(ctx, host, s.port)
+	if err != nil {
+		return nil, err
+	}
+
+	address := net.JoinHostPort(host, fmt.Sprint(s.port))
+	device := &models.DiscoveredDevice{
+		Host:            host,
+		Port:            s.port,
+		Name:            fmt.Sprintf("SoundTouch-%s", host),
+		LastSeen:        time.Now(),
+		DiscoveryMethod: scanDiscoveryMethod,
+		APIBaseURL:      fmt.Sprintf("http://%s/", address),
+		InfoURL:         fmt.Sprintf("http://%s/info", address),
+	}
+
+	applyDeviceInfo(device, info)
+
+	return device, nil
+}
+
+// hosts lists the unique addresses of all subnets, without network and broadcast addresses
+func (s *SubnetScanner) hosts() []string {
+	seen := make(map[string]bool)
+
+	var hosts []string
+
+	for _, ipNet := range s.subnets {
+		ones, bits := ipNet.Mask.Size()
+		skipEdges := bits == 32 && bits-ones > 1
+
+		first := ipNet.IP.Mask(ipNet.Mask)
+		for ip := cloneIP(first); ipNet.Contains(ip); incrementIP(ip) {
+			if skipEdges && (ip.Equal(first) || isBroadcast(ip, ipNet)) {
+				continue
+			}
+
+			if host := ip.String(); !seen[host] {
+				seen[host] = true
+				hosts = append(hosts, host)
+			}
+		}
+	}
+
+	return hosts
+}
+
+// LocalSubnets returns the IPv4 subnets of the active, non-loopback network interfaces
+// that are small enough to scan
+func LocalSubnets() []string {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil
+	}
+
+	var subnets []string
+
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagLoopback != 0 || iface.Flags&net.FlagUp == 0 {
+			continue
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || ipNet.IP.To4() == nil {
+				continue
+			}
+
+			network := &net.IPNet{IP: ipNet.IP.Mask(ipNet.Mask), Mask: ipNet.Mask}
+			if config.ValidateScanSubnet(network.String()) == nil {
+				subnets = append(subnets, network.String())
+			}
+		}
+	}
+
+	return subnets
+}
+
+// requestDeviceInfo reads /info of a device; the context bounds the request time
+func requestDeviceInfo(ctx context.Context, host string, port int) (*models.DeviceInfo, error) {
+	if port == 0 {
+		port = 8090
+	}
+
+	url := fmt.Sprintf("http://%s/info", net.JoinHostPort(host, fmt.Sprint(port)))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch info: %w", err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var info models.DeviceInfo
+	if err := xml.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, fmt.Errorf("failed to decode info: %w", err)
+	}
+
+	if info.DeviceID == "" {
+		return nil, fmt.Errorf("info has no device ID")
+	}
+
+	return &info, nil
+}
+
+// applyDeviceInfo copies identity, name, model, serial number and firmware from /info to a device
+func applyDeviceInfo(device *models.DiscoveredDevice, info *models.DeviceInfo) {
+	if device.Metadata == nil {
+		device.Metadata = make(map[string]string)
+	}
+
+	device.Metadata["deviceID"] = info.DeviceID
+
+	if info.Name != "" {
+		device.Name = info.Name
+	}
+
+	if device.ModelID == "" {
+		device.ModelID = info.Type
+	}
+
+	for _, component := range info.Components {
+		switch component.ComponentCategory {
+		case "SCM":
+			device.Metadata["firmware"] = component.SoftwareVersion
+
+			if component.SerialNumber != "" {
+				device.SerialNo = component.SerialNumber
+			}
+		case "PackagedProduct":
+			if device.SerialNo == "" {
+				device.SerialNo = component.SerialNumber
+			}
+		}
+	}
+
+	for _, network := range info.NetworkInfo {
+		if network.MacAddress != "" && strings.EqualFold(network.IPAddress, device.Host) {
+			device.Metadata["mac"] = network.MacAddress
+		}
+	}
+}
+
+func cloneIP(ip net.IP) net.IP {
+	clone := make(net.IP, len(ip))
+	copy(clone, ip)
+
+	return clone
+}
+
+func incrementIP(ip net.IP) {
+	for i := len(ip) - 1; i >= 0; i-- {
+		ip[i]++
+		if ip[i] != 0 {
+			return
+		}
+	}
+}
+
+func isBroadcast(ip net.IP, ipNet *net.IPNet) bool {
+	ip4 := ip.To4()
+	for i := range ip4 {
+		if ip4[i] != ipNet.IP.To4()[i]|^ipNet.Mask[len(ipNet.Mask)-4+i] {
+			return false
+		}
+	}
+
+	return true
+}
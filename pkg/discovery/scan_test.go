@@ -0,0 +1,151 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/config"
+)
+
+func newInfoServer(t *testing.T) int {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/info" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = fmt.Fprint(w, `<info deviceID="AABBCC000001"><name>Kitchen</name><type>SoundTouch 10</type>`+
+			`<components><component><componentCategory>SCM</componentCategory><softwareVersion>27.0.6</softwareVersion><serialNumber>SCM-SERIAL</serialNumber></component>`+
+			`<component><componentCategory>PackagedProduct</componentCategory><serialNumber>PRODUCT-SERIAL</serialNumber></component></components>`+
+			`<networkInfo type="SCM"><macAddress>AABBCC000001</macAddress><ipAddress>127.0.0.1</ipAddress></networkInfo></info>`)
+	}))
+	t.Cleanup(server.Close)
+
+	u, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(u.Port())
+
+	return port
+}
+
+func TestNewSubnetScanner(t *testing.T) {
+	scanner, err := NewSubnetScanner([]string{"192.168.1.0/24"}, 0, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if scanner.concurrency != defaultScanConcurrency || scanner.timeout != defaultScanTimeout || scanner.port != 8090 {
+		t.Errorf("Expected defaults, got concurrency=%d timeout=%v port=%d", scanner.concurrency, scanner.timeout, scanner.port)
+	}
+
+	for _, subnet := range []string{"192.168.1.10", "10.0.0.0/8"} {
+		if _, err := NewSubnetScanner([]string{subnet}, 1, time.Second); err == nil {
+			t.Errorf("Expected error for subnet %s", subnet)
+		}
+	}
+}
+
+func TestSubnetScannerHosts(t *testing.T) {
+	tests := []struct {
+		subnets  []string
+		expected string
+	}{
+		{[]string{"192.168.1.0/30"}, "192.168.1.1,192.168.1.2"},
+		{[]string{"192.168.1.4/31"}, "192.168.1.4,192.168.1.5"},
+		{[]string{"192.168.1.7/32"}, "192.168.1.7"},
+		{[]string{"192.168.1.0/30", "192.168.1.2/31"}, "192.168.1.1,192.168.1.2,192.168.1.3"},
+		{[]string{"192.168.1.13/30"}, "192.168.1.13,192.168.1.14"},
+	}
+
+	for _, tt := range tests {
+		scanner, err := NewSubnetScanner(tt.subnets, 1, time.Second)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if got := strings.Join(scanner.hosts(), ","); got != tt.expected {
+			t.Errorf("hosts(%v) = %s, expected %s", tt.subnets, got, tt.expected)
+		}
+	}
+
+	scanner, _ := NewSubnetScanner([]string{"10.0.0.0/24"}, 1, time.Second)
+	if scanner.Hosts() != 254 {
+		t.Errorf("Expected 254 hosts in a /24, got %d", scanner.Hosts())
+	}
+}
+
+func TestSubnetScanner_DiscoverDevices(t *testing.T) {
+	scanner, err := NewSubnetScanner([]string{"127.0.0.0/29"}, 4, 500*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	scanner.port = newInfoServer(t)
+
+	devices, err := scanner.DiscoverDevices(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(devices) != 1 {
+		t.Fatalf("Expected 1 device, got %d", len(devices))
+	}
+
+	device := devices[0]
+	if device.Host != "127.0.0.1" || device.Name != "Kitchen" || device.ModelID != "SoundTouch 10" || device.DiscoveryMethod != scanDiscoveryMethod {
+		t.Errorf("Unexpected device: %+v", device)
+	}
+
+	if device.SerialNo != "SCM-SERIAL" || device.Metadata["deviceID"] != "AABBCC000001" || device.Metadata["firmware"] != "27.0.6" || device.Metadata["mac"] != "AABBCC000001" {
+		t.Errorf("Expected details from /info, got %+v", device)
+	}
+}
+
+func TestSubnetScanner_Cancelled(t *testing.T) {
+	scanner, _ := NewSubnetScanner([]string{"127.0.0.0/24"}, 2, 500*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := scanner.DiscoverDevices(ctx); err == nil {
+		t.Error("Expected an error for a cancelled scan")
+	}
+}
+
+func TestUnifiedDiscovery_SubnetScan(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.UPnPEnabled = false
+	cfg.MDNSEnabled = false
+	cfg.CacheEnabled = false
+	cfg.ScanSubnets = []string{"127.0.0.1/32"}
+	cfg.PreferredDevices = []config.DeviceConfig{{Name: "Configured", Host: "127.0.0.1", Port: 8090}}
+
+	service := NewUnifiedDiscoveryService(cfg)
+	if service.scanner == nil {
+		t.Fatal("Expected the subnet scanner to be set up")
+	}
+
+	service.scanner.port = newInfoServer(t)
+
+	devices, err := service.DiscoverDevices(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(devices) != 1 {
+		t.Fatalf("Expected the scanned device to be merged with the configured one, got %d devices", len(devices))
+	}
+
+	device := devices[0]
+	if device.Name != "Configured" || device.SerialNo != "SCM-SERIAL" || device.DiscoveryMethod != "Configuration+"+scanDiscoveryMethod {
+		t.Errorf("Unexpected merged device: %+v", device)
+	}
+}
@@ -110,6 +110,9 @@ package discovery
 import (
 	"context"
 	"fmt"
+	"log"
+	"net"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -118,31 +121,42 @@ import (
 	"github.com/gesellix/bose-soundtouch/pkg/models"
 )
 
-// UnifiedDiscoveryService combines SSDP and mDNS discovery methods
+// UnifiedDiscoveryService combines SSDP, mDNS and subnet scan discovery methods
 type UnifiedDiscoveryService struct {
 	ssdpService *Service
 	mdnsService *MDNSDiscoveryService
+	scanner     *SubnetScanner
 	config      *config.Config
 	cache       map[string]*models.DiscoveredDevice
 	cacheTTL    time.Duration
 	mutex       sync.RWMutex
+
+	watchInterval time.Duration
+	registry      *DeviceRegistry
 }
 
 // NewUnifiedDiscoveryService creates a new unified discovery service
 func NewUnifiedDiscoveryService(cfg *config.Config) *UnifiedDiscoveryService {
-	timeout := cfg.DiscoveryTimeout
-	if timeout == 0 {
-		timeout = defaultTimeout
-	}
-
 	cacheTTL := cfg.CacheTTL
 	if cacheTTL == 0 {
 		cacheTTL = defaultCacheTTL
 	}
 
+	var scanner *SubnetScanner
+
+	if len(cfg.ScanSubnets) > 0 {
+		var err error
+
+		scanner, err = NewSubnetScannerWithConfig(cfg)
+		if err != nil {
+			log.Printf("Scan: Subnet scan disabled: %v", err)
+		}
+	}
+
 	return &UnifiedDiscoveryService{
 		ssdpService: NewServiceWithConfig(cfg),
-		mdnsService: NewMDNSDiscoveryService(timeout),
+		mdnsService: NewMDNSDiscoveryServiceWithConfig(cfg),
+		scanner:     scanner,
 		config:      cfg,
 		cache:       make(map[string]*models.DiscoveredDevice),
 		cacheTTL:    cacheTTL,
@@ -150,7 +164,7 @@ func NewUnifiedDiscoveryService(cfg *config.Config) *UnifiedDiscoveryService {
 	}
 }
 
-// DiscoverDevices discovers SoundTouch devices using both SSDP and mDNS
+// DiscoverDevices discovers SoundTouch devices using SSDP, mDNS and, if subnets are configured, a subnet scan
 func (u *UnifiedDiscoveryService) DiscoverDevices(ctx context.Context) ([]*models.DiscoveredDevice, error) {
 	// Check cache first
 	u.cleanupCache()
@@ -160,6 +174,69 @@ func (u *UnifiedDiscoveryService) DiscoverDevices(ctx context.Context) ([]*model
 		return cached, nil
 	}
 
+	devices := u.scan(ctx)
+	u.recordDevices(ctx, devices)
+
+	return devices, nil
+}
+
+// SetRegistry attaches a device registry. Discovered devices are identified through /info
+// and recorded in it, and configured devices follow their registered address changes.
+func (u *UnifiedDiscoveryService) SetRegistry(registry *DeviceRegistry) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	u.registry = registry
+}
+
+// getRegistry returns the attached registry, if any
+func (u *UnifiedDiscoveryService) getRegistry() *DeviceRegistry {
+	u.mutex.RLock()
+	defer u.mutex.RUnlock()
+
+	return u.registry
+}
+
+// recordDevices identifies devices that lack a device ID and records them in the registry
+func (u *UnifiedDiscoveryService) recordDevices(ctx context.Context, devices []*models.DiscoveredDevice) {
+	registry := u.getRegistry()
+	if registry == nil || len(devices) == 0 {
+		return
+	}
+
+	var wg sync.WaitGroup
+
+	for _, device := range devices {
+		if device.Metadata["deviceID"] != "" {
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(device *models.DiscoveredDevice) {
+			defer wg.Done()
+
+			if info, err := fetchDeviceInfo(ctx, device); err == nil {
+				applyDeviceInfo(device, info)
+			}
+		}(device)
+	}
+
+	wg.Wait()
+
+	for _, device := range devices {
+		if entry, moved := registry.Record(device); moved {
+			log.Printf("Registry: %s (%s) moved to %s:%d", entry.Name, entry.DeviceID, entry.Host, entry.Port)
+		}
+	}
+
+	if err := registry.Save(); err != nil {
+		log.Printf("Registry: %v", err)
+	}
+}
+
+// scan runs all enabled discovery methods, bypassing the cache
+func (u *UnifiedDiscoveryService) scan(ctx context.Context) []*models.DiscoveredDevice {
 	// Initialize devices slice to ensure it's never nil
 	allDevices := make([]*models.DiscoveredDevice, 0)
 
@@ -170,6 +247,7 @@ func (u *UnifiedDiscoveryService) DiscoverDevices(ctx context.Context) ([]*model
 	// Use channels to collect results from both discovery methods
 	ssdpChan := make(chan []*models.DiscoveredDevice, 1)
 	mdnsChan := make(chan []*models.DiscoveredDevice, 1)
+	scanChan := make(chan []*models.DiscoveredDevice, 1)
 
 	var wg sync.WaitGroup
 
@@ -210,7 +288,22 @@ func (u *UnifiedDiscoveryService) DiscoverDevices(ctx context.Context) ([]*model
 		mdnsChan <- nil
 	}
 
-	// Wait for both discovery methods to complete
+	// Start the subnet scan if subnets are configured
+	if u.scanner != nil {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			// Keep what was found before an interruption
+			devices, _ := u.scanner.DiscoverDevices(ctx)
+			scanChan <- devices
+		}()
+	} else {
+		scanChan <- nil
+	}
+
+	// Wait for all discovery methods to complete
 	wg.Wait()
 
 	// Collect results from both methods
@@ -222,6 +315,10 @@ func (u *UnifiedDiscoveryService) DiscoverDevices(ctx context.Context) ([]*model
 		allDevices = u.mergeDevices(allDevices, mdnsDevices)
 	}
 
+	if scanDevices := <-scanChan; scanDevices != nil {
+		allDevices = u.mergeDevices(allDevices, scanDevices)
+	}
+
 	// Update cache
 	u.updateCache(allDevices)
 
@@ -230,7 +327,7 @@ func (u *UnifiedDiscoveryService) DiscoverDevices(ctx context.Context) ([]*model
 		allDevices = make([]*models.DiscoveredDevice, 0)
 	}
 
-	return allDevices, nil
+	return allDevices
 }
 
 // DiscoverDevice discovers a specific SoundTouch device by host
@@ -316,9 +413,23 @@ func (u *UnifiedDiscoveryService) cleanupCache() {
 	}
 }
 
-// getConfiguredDevices returns devices from configuration
+// getConfiguredDevices returns devices from configuration, at their registered address if they moved
 func (u *UnifiedDiscoveryService) getConfiguredDevices() []*models.DiscoveredDevice {
-	return u.config.GetPreferredDevicesAsDiscovered()
+	devices := u.config.GetPreferredDevicesAsDiscovered()
+
+	if registry := u.getRegistry(); registry != nil {
+		for _, device := range devices {
+			host, port, ok := registry.ResolveHost(net.JoinHostPort(device.Host, strconv.Itoa(device.Port)))
+			if ok && (host != device.Host || port != device.Port) {
+				log.Printf("Registry: Configured device %s moved from %s to %s:%d", device.Name, device.Host, host, port)
+				device.Host, device.Port = host, port
+				device.APIBaseURL = config.HostURL(host, port) + "/"
+				device.InfoURL = config.HostURL(host, port) + "/info"
+			}
+		}
+	}
+
+	return devices
 }
 
 // mergeDevices merges two device lists, combining protocol-specific data when same device found via multiple methods
@@ -391,6 +502,9 @@ func (u *UnifiedDiscoveryService) pickBestName(existing, newDevice *models.Disco
 		return existing.Name
 	case newDevice.DiscoveryMethod == "Configuration":
 		return newDevice.Name
+	case newDevice.DiscoveryMethod == scanDiscoveryMethod:
+		// The subnet scan reads the name from /info
+		return newDevice.Name
 	default:
 		return existing.Name
 	}
@@ -405,6 +519,10 @@ func (u *UnifiedDiscoveryService) mergeProtocolData(merged, newDevice *models.Di
 		merged.UPnPUSN = newDevice.UPnPUSN
 	}
 
+	if len(newDevice.UPnPServices) > 0 {
+		merged.UPnPServices = newDevice.UPnPServices
+	}
+
 	if newDevice.MDNSHostname != "" {
 		merged.MDNSHostname = newDevice.MDNSHostname
 	}
@@ -2,12 +2,14 @@ package discovery
 
 import (
 	"context"
+	"encoding/xml"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"net/http"
-	"regexp"
+	"net/url"
 	"strings"
 	"sync"
 	"time"
@@ -137,35 +139,72 @@ func (d *Service) ClearCache() {
 	d.cache = make(map[string]*models.DiscoveredDevice)
 }
 
-// PerformDiscovery performs the actual UPnP SSDP discovery
+// ssdpTarget is an address family on a network interface to send M-SEARCH requests on
+type ssdpTarget struct {
+	network string
+	iface   *net.Interface
+	localIP net.IP
+}
+
+func (t ssdpTarget) String() string {
+	family := "IPv4"
+	if t.network == "udp6" {
+		family = "IPv6"
+	}
+
+	if t.iface == nil {
+		return family + " (default interface)"
+	}
+
+	return family + " on " + t.iface.Name
+}
+
+// PerformDiscovery performs the actual UPnP SSDP discovery, concurrently on all discovery
+// interfaces and, if enabled, over IPv6
 func (d *Service) PerformDiscovery(ctx context.Context) ([]*models.DiscoveredDevice, error) {
 	log.Printf("UPnP: Starting SSDP discovery for '%s' with timeout %v", soundTouchURN, d.timeout)
 
-	listener, err := d.setupUDPListener()
-	if err != nil {
-		return nil, err
-	}
+	targets := d.searchTargets()
 
-	defer func() {
-		_ = listener.Close()
-	}()
+	var (
+		mu            sync.Mutex
+		wg            sync.WaitGroup
+		responseCount int
+		errs          []error
+	)
 
-	multicastAddr, err := net.ResolveUDPAddr("udp4", ssdpAddr)
-	if err != nil {
-		log.Printf("UPnP: Failed to resolve multicast address %s: %v", ssdpAddr, err)
-		return nil, fmt.Errorf("failed to resolve multicast address: %w", err)
-	}
+	devices := make(map[string]*models.DiscoveredDevice)
 
-	if err = d.sendMSearch(listener, multicastAddr); err != nil {
-		return nil, err
+	for _, target := range targets {
+		wg.Add(1)
+
+		go func(target ssdpTarget) {
+			defer wg.Done()
+
+			found, count, err := d.search(ctx, target)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			responseCount += count
+
+			if err != nil {
+				log.Printf("UPnP: Search via %s failed: %v", target, err)
+				errs = append(errs, err)
+
+				return
+			}
+
+			for _, device := range found {
+				addDiscoveredDevice(devices, device)
+			}
+		}(target)
 	}
 
-	// Listen for responses
-	devices := make(map[string]*models.DiscoveredDevice)
+	wg.Wait()
 
-	responseCount, err := d.listenForResponses(ctx, listener, devices)
-	if err != nil {
-		return nil, err
+	if len(errs) == len(targets) {
+		return nil, errs[0]
 	}
 
 	// Convert map to slice
@@ -183,38 +222,135 @@ func (d *Service) PerformDiscovery(ctx context.Context) ([]*models.DiscoveredDev
 	return result, nil
 }
 
-func (d *Service) setupUDPListener() (*net.UDPConn, error) {
-	listenAddr, err := net.ResolveUDPAddr("udp4", ":0")
+// searchTargets returns the interfaces and address families to search on
+func (d *Service) searchTargets() []ssdpTarget {
+	interfaces, err := SelectInterfaces(d.config.DiscoveryInterfaces, d.config.DiscoveryIPv6)
 	if err != nil {
-		log.Printf("UPnP: Failed to resolve listen address: %v", err)
-		return nil, fmt.Errorf("failed to resolve listen address: %w", err)
+		log.Printf("UPnP: %v", err)
 	}
 
-	listener, err := net.ListenUDP("udp4", listenAddr)
-	if err != nil {
-		log.Printf("UPnP: Failed to create UDP listener: %v", err)
-		return nil, fmt.Errorf("failed to create UDP listener: %w", err)
+	var targets []ssdpTarget
+
+	for i := range interfaces {
+		iface := &interfaces[i].Interface
+
+		if len(interfaces[i].IPv4) > 0 {
+			targets = append(targets, ssdpTarget{network: "udp4", iface: iface, localIP: interfaces[i].IPv4[0]})
+		}
+
+		if len(interfaces[i].IPv6) > 0 {
+			targets = append(targets, ssdpTarget{network: "udp6", iface: iface})
+		}
+	}
+
+	if len(targets) == 0 {
+		targets = append(targets, ssdpTarget{network: "udp4"})
 	}
 
-	addr := listener.LocalAddr()
+	return targets
+}
+
+// search sends an M-SEARCH request via one target and collects the answers
+func (d *Service) search(ctx context.Context, target ssdpTarget) (map[string]*models.DiscoveredDevice, int, error) {
+	listener, err := d.setupUDPListener(target)
+	if err != nil {
+		return nil, 0, err
+	}
 
-	localAddr, ok := addr.(*net.UDPAddr)
-	if !ok {
+	defer func() {
 		_ = listener.Close()
+	}()
 
-		log.Printf("UPnP: Failed to cast local address to UDPAddr: %v", addr)
+	multicastAddr, err := target.multicastAddr()
+	if err != nil {
+		log.Printf("UPnP: Failed to resolve multicast address for %s: %v", target, err)
+		return nil, 0, fmt.Errorf("failed to resolve multicast address: %w", err)
+	}
 
-		return nil, fmt.Errorf("failed to cast local address to UDPAddr: %v", addr)
+	if err = d.sendMSearch(listener, multicastAddr); err != nil {
+		return nil, 0, err
 	}
 
-	log.Printf("UPnP: Created UDP listener on %s", localAddr.String())
+	devices := make(map[string]*models.DiscoveredDevice)
+
+	responseCount, err := d.listenForResponses(ctx, listener, devices)
+	if err != nil {
+		return nil, responseCount, err
+	}
+
+	return devices, responseCount, nil
+}
+
+// multicastAddr returns the SSDP group of the target; the IPv6 group is link-local and scoped to the interface
+func (t ssdpTarget) multicastAddr() (*net.UDPAddr, error) {
+	if t.network == "udp6" {
+		addr, err := net.ResolveUDPAddr("udp6", ssdpIPv6Addr)
+		if err != nil {
+			return nil, err
+		}
+
+		addr.Zone = t.iface.Name
+
+		return addr, nil
+	}
+
+	return net.ResolveUDPAddr("udp4", ssdpAddr)
+}
+
+// addDiscoveredDevice adds a device found by SSDP; a device answering over IPv4 and IPv6 is
+// recognized by its USN and kept with its IPv4 address
+func addDiscoveredDevice(devices map[string]*models.DiscoveredDevice, device *models.DiscoveredDevice) {
+	for host, existing := range devices {
+		if device.UPnPUSN == "" || existing.UPnPUSN != device.UPnPUSN {
+			continue
+		}
+
+		if isIPv4Host(existing.Host) || !isIPv4Host(device.Host) {
+			return
+		}
+
+		delete(devices, host)
+	}
+
+	devices[device.Host] = device
+}
+
+func isIPv4Host(host string) bool {
+	ip := net.ParseIP(host)
+	return ip != nil && ip.To4() != nil
+}
+
+func (d *Service) setupUDPListener(target ssdpTarget) (*net.UDPConn, error) {
+	listenAddr := &net.UDPAddr{IP: target.localIP}
+	if target.network == "udp6" {
+		listenAddr = &net.UDPAddr{IP: net.IPv6unspecified, Zone: target.iface.Name}
+	}
+
+	listener, err := net.ListenUDP(target.network, listenAddr)
+	if err != nil {
+		log.Printf("UPnP: Failed to create UDP listener for %s: %v", target, err)
+		return nil, fmt.Errorf("failed to create UDP listener: %w", err)
+	}
+
+	if target.iface != nil {
+		if err := setMulticastInterface(listener, target.network, target.iface); err != nil {
+			log.Printf("UPnP: Failed to select multicast interface %s: %v", target.iface.Name, err)
+		}
+	}
+
+	log.Printf("UPnP: Created UDP listener on %s for %s", listener.LocalAddr().String(), target)
 
 	return listener, nil
 }
 
 func (d *Service) sendMSearch(listener *net.UDPConn, multicastAddr *net.UDPAddr) error {
-	msearchRequest := d.buildMSearchRequest()
-	log.Printf("UPnP: Sending M-SEARCH request to %s:\n%s", ssdpAddr, strings.TrimSpace(msearchRequest))
+	host := ssdpAddr
+	if multicastAddr.IP.To4() == nil {
+		host = ssdpIPv6Addr
+	}
+
+	msearchRequest := d.msearchRequest(host)
+	log.Printf("UPnP: Sending M-SEARCH request to %s:\n%s", multicastAddr, strings.TrimSpace(msearchRequest))
 
 	bytesWritten, err := listener.WriteToUDP([]byte(msearchRequest), multicastAddr)
 	if err != nil {
@@ -264,7 +400,7 @@ func (d *Service) listenForResponses(ctx context.Context, listener *net.UDPConn,
 			responseText := string(buffer[:n])
 			log.Printf("UPnP: Received response #%d (%d bytes) from %s:\n%s", responseCount, n, remoteAddr.String(), strings.TrimSpace(responseText))
 
-			device, err := d.parseResponse(responseText)
+			device, err := d.parseResponseFrom(responseText, remoteAddr)
 			if err != nil {
 				log.Printf("UPnP: Failed to parse response #%d from %s: %v", responseCount, remoteAddr.String(), err)
 				continue // Skip invalid responses
@@ -272,7 +408,7 @@ func (d *Service) listenForResponses(ctx context.Context, listener *net.UDPConn,
 
 			if device != nil {
 				log.Printf("UPnP: Successfully parsed device from response #%d: %s at %s:%d", responseCount, device.Name, device.Host, device.Port)
-				devices[device.Host] = device
+				addDiscoveredDevice(devices, device)
 			} else {
 				log.Printf("UPnP: Response #%d from %s did not contain a valid SoundTouch device", responseCount, remoteAddr.String())
 			}
@@ -282,8 +418,13 @@ func (d *Service) listenForResponses(ctx context.Context, listener *net.UDPConn,
 	return responseCount, nil
 }
 
-// buildMSearchRequest builds the M-SEARCH request for SoundTouch devices
+// buildMSearchRequest builds the IPv4 M-SEARCH request for SoundTouch devices
 func (d *Service) buildMSearchRequest() string {
+	return d.msearchRequest(ssdpAddr)
+}
+
+// msearchRequest builds the M-SEARCH request for SoundTouch devices sent to the given multicast host
+func (d *Service) msearchRequest(host string) string {
 	return fmt.Sprintf(
 		"M-SEARCH * HTTP/1.1\r\n"+
 			"HOST: %s\r\n"+
@@ -291,7 +432,7 @@ func (d *Service) buildMSearchRequest() string {
 			"ST: %s\r\n"+
 			"MX: %d\r\n"+
 			"\r\n",
-		ssdpAddr,
+		host,
 		soundTouchURN,
 		int(d.timeout.Seconds()),
 	)
@@ -299,6 +440,11 @@ func (d *Service) buildMSearchRequest() string {
 
 // parseResponse parses UPnP SSDP response and extracts device information
 func (d *Service) parseResponse(response string) (*models.DiscoveredDevice, error) {
+	return d.parseResponseFrom(response, nil)
+}
+
+// parseResponseFrom parses an SSDP response received from the given address
+func (d *Service) parseResponseFrom(response string, from *net.UDPAddr) (*models.DiscoveredDevice, error) {
 	log.Printf("UPnP: Parsing response (%d chars): %.100s...", len(response), strings.ReplaceAll(response, "\r\n", "\\r\\n"))
 
 	// Try both \r\n and \n line endings
@@ -371,6 +517,9 @@ func (d *Service) parseResponse(response string) (*models.DiscoveredDevice, erro
 		return nil, fmt.Errorf("failed to parse location URL: %w", err)
 	}
 
+	scopeDevice(device, from)
+	location = device.UPnPLocation
+
 	log.Printf("UPnP: Successfully parsed device from location: %s at %s:%d", device.Name, device.Host, device.Port)
 
 	// Try to get more device info from the location URL
@@ -390,15 +539,13 @@ func (d *Service) parseLocationURL(location, usn string) (*models.DiscoveredDevi
 	log.Printf("UPnP: Parsing location URL: %s", location)
 
 	// Parse the URL to extract host and port
-	re := regexp.MustCompile(`http://([^:]+):(\d+)`)
-	matches := re.FindStringSubmatch(location)
-
-	if len(matches) < 2 {
+	u, err := url.Parse(location)
+	if err != nil || u.Scheme != "http" || u.Hostname() == "" || u.Port() == "" {
 		log.Printf("UPnP: Location URL '%s' does not match expected format http://host:port", location)
 		return nil, fmt.Errorf("invalid location URL format")
 	}
 
-	host := matches[1]
+	host := u.Hostname()
 	port := 8090 // Default SoundTouch port
 	log.Printf("UPnP: Extracted host='%s', using default port=%d", host, port)
 
@@ -408,8 +555,8 @@ func (d *Service) parseLocationURL(location, usn string) (*models.DiscoveredDevi
 		LastSeen:        time.Now(),
 		Name:            fmt.Sprintf("SoundTouch-%s", host), // Default name
 		DiscoveryMethod: "SSDP/UPnP",
-		APIBaseURL:      fmt.Sprintf("http://%s:%d/", host, port),
-		InfoURL:         fmt.Sprintf("http://%s:%d/info", host, port),
+		APIBaseURL:      config.HostURL(host, port) + "/",
+		InfoURL:         config.HostURL(host, port) + "/info",
 		UPnPLocation:    location,
 		UPnPUSN:         usn,
 	}
@@ -417,8 +564,30 @@ func (d *Service) parseLocationURL(location, usn string) (*models.DiscoveredDevi
 	return device, nil
 }
 
-// enrichDeviceInfo tries to get additional device information from the device description
-func (d *Service) enrichDeviceInfo(_ *models.DiscoveredDevice, location string) error {
+// scopeDevice adds the zone of the sender to a device with a link-local IPv6 address, so that
+// its URLs can be reached
+func scopeDevice(device *models.DiscoveredDevice, from *net.UDPAddr) {
+	host := withZone(device.Host, from)
+	if host == device.Host {
+		return
+	}
+
+	if device.Name == fmt.Sprintf("SoundTouch-%s", device.Host) {
+		device.Name = fmt.Sprintf("SoundTouch-%s", host)
+	}
+
+	device.Host = host
+	device.APIBaseURL = config.HostURL(host, device.Port) + "/"
+	device.InfoURL = config.HostURL(host, device.Port) + "/info"
+
+	if u, err := url.Parse(device.UPnPLocation); err == nil && device.UPnPLocation != "" {
+		u.Host = net.JoinHostPort(host, u.Port())
+		device.UPnPLocation = u.String()
+	}
+}
+
+// enrichDeviceInfo reads the UPnP device description and adds its details to the device
+func (d *Service) enrichDeviceInfo(device *models.DiscoveredDevice, location string) error {
 	log.Printf("UPnP: Attempting to enrich device info by fetching %s", location)
 
 	client := &http.Client{
@@ -437,8 +606,54 @@ func (d *Service) enrichDeviceInfo(_ *models.DiscoveredDevice, location string)
 
 	log.Printf("UPnP: Successfully fetched device description from %s (Status: %s)", location, resp.Status)
 
-	// For now, we'll keep it simple and not parse the full UPnP device description
-	// This can be enhanced later to extract more detailed device information
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return applyDeviceDescription(device, resp.Body, location)
+}
+
+// applyDeviceDescription parses a UPnP device description into the device: friendly name,
+// model, serial number and the services with absolute URLs
+func applyDeviceDescription(device *models.DiscoveredDevice, body io.Reader, location string) error {
+	var description models.UPnPDescription
+	if err := xml.NewDecoder(body).Decode(&description); err != nil {
+		return fmt.Errorf("failed to decode device description: %w", err)
+	}
+
+	desc := description.Device
+
+	if name := strings.TrimSpace(desc.FriendlyName); name != "" {
+		device.Name = name
+	}
+
+	if device.ModelID == "" {
+		device.ModelID = strings.TrimSpace(desc.ModelName)
+	}
+
+	if device.SerialNo == "" {
+		device.SerialNo = strings.TrimSpace(desc.SerialNumber)
+	}
+
+	device.UPnPServices = description.Services(location)
+
+	if device.Metadata == nil {
+		device.Metadata = make(map[string]string)
+	}
+
+	for key, value := range map[string]string{
+		"manufacturer":   desc.Manufacturer,
+		"modelNumber":    desc.ModelNumber,
+		"upnpDeviceType": desc.DeviceType,
+		"udn":            desc.UDN,
+	} {
+		if value = strings.TrimSpace(value); value != "" {
+			device.Metadata[key] = value
+		}
+	}
+
+	log.Printf("UPnP: Description of %s lists %d services", device.Name, len(device.UPnPServices))
+
 	return nil
 }
 
@@ -2,6 +2,7 @@ package discovery
 
 import (
 	"context"
+	"net"
 	"strings"
 	"testing"
 	"time"
@@ -90,6 +91,38 @@ func TestParseLocationURL_Valid(t *testing.T) {
 	}
 }
 
+func TestParseLocationURL_IPv6(t *testing.T) {
+	service := NewService(1 * time.Second)
+
+	device, err := service.parseLocationURL("http://[fe80::10]:8091/XD/description.xml", "uuid:kitchen")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	scopeDevice(device, &net.UDPAddr{IP: net.ParseIP("fe80::10"), Zone: "eth0"})
+
+	if device.Host != "fe80::10%eth0" || device.InfoURL != "http://[fe80::10%25eth0]:8090/info" {
+		t.Errorf("Unexpected scoped device: %s, %s", device.Host, device.InfoURL)
+	}
+
+	if device.UPnPLocation != "http://[fe80::10%25eth0]:8091/XD/description.xml" {
+		t.Errorf("Unexpected location: %s", device.UPnPLocation)
+	}
+}
+
+func TestAddDiscoveredDevice_PrefersIPv4(t *testing.T) {
+	devices := make(map[string]*models.DiscoveredDevice)
+
+	addDiscoveredDevice(devices, &models.DiscoveredDevice{Host: "fe80::10%eth0", UPnPUSN: "uuid:kitchen"})
+	addDiscoveredDevice(devices, &models.DiscoveredDevice{Host: "192.168.1.10", UPnPUSN: "uuid:kitchen"})
+	addDiscoveredDevice(devices, &models.DiscoveredDevice{Host: "fe80::10%eth1", UPnPUSN: "uuid:kitchen"})
+	addDiscoveredDevice(devices, &models.DiscoveredDevice{Host: "192.168.1.11", UPnPUSN: "uuid:office"})
+
+	if len(devices) != 2 || devices["192.168.1.10"] == nil || devices["192.168.1.11"] == nil {
+		t.Errorf("Expected one IPv4 entry per device, got %v", devices)
+	}
+}
+
 func TestParseLocationURL_Invalid(t *testing.T) {
 	service := NewService(1 * time.Second)
 
@@ -500,3 +533,65 @@ func TestDiscoverDevices_ConfiguredOnly(t *testing.T) {
 func contains(s, substr string) bool {
 	return strings.Contains(s, substr)
 }
+
+func TestApplyDeviceDescription(t *testing.T) {
+	description := `<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+  <device>
+    <deviceType>urn:schemas-upnp-org:device:MediaRenderer:1</deviceType>
+    <friendlyName>Kitchen</friendlyName>
+    <manufacturer>Bose Corporation</manufacturer>
+    <modelName>SoundTouch 10</modelName>
+    <modelNumber>416776</modelNumber>
+    <serialNumber>I6332527703739342000020</serialNumber>
+    <UDN>uuid:BO5EBO5E-F00D-F00D-FEED-AABBCC000001</UDN>
+    <serviceList>
+      <service>
+        <serviceType>urn:schemas-upnp-org:service:AVTransport:1</serviceType>
+        <serviceId>urn:upnp-org:serviceId:AVTransport</serviceId>
+        <SCPDURL>/XD/AVTransport.xml</SCPDURL>
+        <controlURL>/AVTransport/Control</controlURL>
+        <eventSubURL>/AVTransport/Event</eventSubURL>
+      </service>
+      <service>
+        <serviceType>urn:schemas-upnp-org:service:RenderingControl:1</serviceType>
+        <serviceId>urn:upnp-org:serviceId:RenderingControl</serviceId>
+        <SCPDURL>RenderingControl.xml</SCPDURL>
+        <controlURL>http://192.168.1.10:8091/RenderingControl/Control</controlURL>
+      </service>
+    </serviceList>
+  </device>
+</root>`
+
+	location := "http://192.168.1.10:8091/XD/BO5EBO5E-F00D-F00D-FEED-AABBCC000001.xml"
+	device := &models.DiscoveredDevice{Name: "SoundTouch-192.168.1.10", Host: "192.168.1.10"}
+
+	if err := applyDeviceDescription(device, strings.NewReader(description), location); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if device.Name != "Kitchen" || device.ModelID != "SoundTouch 10" || device.SerialNo != "I6332527703739342000020" {
+		t.Errorf("Unexpected device details: %+v", device)
+	}
+
+	if device.Metadata["manufacturer"] != "Bose Corporation" || device.Metadata["udn"] != "uuid:BO5EBO5E-F00D-F00D-FEED-AABBCC000001" {
+		t.Errorf("Unexpected metadata: %v", device.Metadata)
+	}
+
+	if len(device.UPnPServices) != 2 {
+		t.Fatalf("Expected 2 services, got %d", len(device.UPnPServices))
+	}
+
+	avTransport, ok := models.FindUPnPService(device.UPnPServices, models.UPnPServiceAVTransport)
+	if !ok || avTransport.ControlURL != "http://192.168.1.10:8091/AVTransport/Control" || avTransport.SCPDURL != "http://192.168.1.10:8091/XD/AVTransport.xml" {
+		t.Errorf("Expected absolute AVTransport URLs, got %+v", avTransport)
+	}
+
+	if rendering := device.UPnPServices[1]; rendering.SCPDURL != "http://192.168.1.10:8091/XD/RenderingControl.xml" {
+		t.Errorf("Expected SCPD URL relative to the description, got %s", rendering.SCPDURL)
+	}
+
+	if err := applyDeviceDescription(device, strings.NewReader("not xml"), location); err == nil {
+		t.Error("Expected error for an invalid description")
+	}
+}
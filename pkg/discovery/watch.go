@@ -0,0 +1,565 @@
+package discovery
+
+import (
+	"context"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/hashicorp/mdns"
+	"github.com/miekg/dns"
+)
+
+const (
+	// Default interval between active probes while watching
+	defaultWatchInterval = time.Minute
+
+	// mDNS multicast address and port
+	mdnsAddr = "224.0.0.251:5353"
+
+	// Link-local IPv6 mDNS multicast address and port
+	mdnsIPv6Addr = "[ff02::fb]:5353"
+)
+
+// WatchEventType describes what happened to a watched device
+type WatchEventType string
+
+const (
+	// DeviceAppeared is sent when a device is seen for the first time
+	DeviceAppeared WatchEventType = "appeared"
+	// DeviceDisappeared is sent when a device said goodbye or was not seen for a while
+	DeviceDisappeared WatchEventType = "disappeared"
+	// DeviceChanged is sent when the IP address, name or firmware of a known device changed
+	DeviceChanged WatchEventType = "changed"
+)
+
+// WatchEvent is a change in the set of devices on the network
+type WatchEvent struct {
+	Type     WatchEventType
+	Device   *models.DiscoveredDevice
+	Previous *models.DiscoveredDevice // Device before a DeviceChanged event
+	Changes  []string                 // Changed fields of a DeviceChanged event: host, name, firmware
+}
+
+// watchObservation is a device seen by one of the watch sources
+type watchObservation struct {
+	device   *models.DiscoveredDevice
+	gone     bool
+	verified bool
+}
+
+// watcher keeps the state of a running Watch; all state is owned by the run loop
+type watcher struct {
+	service      *UnifiedDiscoveryService
+	interval     time.Duration
+	expireAfter  time.Duration
+	probe        func(ctx context.Context) []*models.DiscoveredDevice
+	info         func(ctx context.Context, device *models.DiscoveredDevice) (*models.DeviceInfo, error)
+	devices      map[string]*models.DiscoveredDevice
+	pending      map[string]bool
+	observations chan watchObservation
+	probes       chan []watchObservation
+	events       chan WatchEvent
+}
+
+// SetWatchInterval sets how often Watch actively probes the network.
+// Devices not seen for three intervals are reported as disappeared.
+// A running Watch picks up the new interval after its current probe.
+func (u *UnifiedDiscoveryService) SetWatchInterval(interval time.Duration) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	u.watchInterval = interval
+}
+
+// getWatchInterval returns the watch interval, falling back to the default
+func (u *UnifiedDiscoveryService) getWatchInterval() time.Duration {
+	u.mutex.RLock()
+	defer u.mutex.RUnlock()
+
+	if u.watchInterval <= 0 {
+		return defaultWatchInterval
+	}
+
+	return u.watchInterval
+}
+
+// Watch reports devices appearing, disappearing and changing until ctx is done.
+// It listens to SSDP NOTIFY and mDNS announcements and actively probes the network
+// in the configured watch interval. Every device is verified through its /info endpoint,
+// which also provides its name and firmware version. The channel is closed when ctx is done.
+func (u *UnifiedDiscoveryService) Watch(ctx context.Context) <-chan WatchEvent {
+	w := u.newWatcher()
+
+	if u.config.UPnPEnabled {
+		go w.listenSSDP(ctx)
+	}
+
+	if u.config.MDNSEnabled {
+		go w.listenMDNS(ctx)
+	}
+
+	go w.run(ctx)
+
+	return w.events
+}
+
+func (u *UnifiedDiscoveryService) newWatcher() *watcher {
+	interval := u.getWatchInterval()
+
+	w := &watcher{
+		service:      u,
+		interval:     interval,
+		expireAfter:  3 * interval,
+		devices:      make(map[string]*models.DiscoveredDevice),
+		pending:      make(map[string]bool),
+		observations: make(chan watchObservation, 16),
+		probes:       make(chan []watchObservation, 1),
+		events:       make(chan WatchEvent, 16),
+	}
+	w.probe = u.scan
+	w.info = fetchDeviceInfo
+
+	return w
+}
+
+// run is the watch loop; it applies observations and probe results and expires devices
+func (w *watcher) run(ctx context.Context) {
+	defer close(w.events)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	probing := true
+
+	go w.runProbe(ctx, w.known())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case observation := <-w.observations:
+			w.handle(ctx, observation)
+		case results := <-w.probes:
+			probing = false
+
+			for _, observation := range results {
+				w.handle(ctx, observation)
+			}
+
+			w.expire(ctx)
+
+			if interval := w.service.getWatchInterval(); interval != w.interval {
+				w.interval = interval
+				w.expireAfter = 3 * interval
+				ticker.Reset(interval)
+			}
+		case <-ticker.C:
+			if !probing {
+				probing = true
+
+				go w.runProbe(ctx, w.known())
+			}
+		}
+	}
+}
+
+// runProbe scans the network and re-checks the known devices directly, as multicast answers get lost
+func (w *watcher) runProbe(ctx context.Context, known []*models.DiscoveredDevice) {
+	devices := w.probe(ctx)
+
+	seen := make(map[string]bool)
+	for _, device := range devices {
+		seen[device.Host] = true
+	}
+
+	for _, device := range known {
+		if !seen[device.Host] {
+			devices = append(devices, device)
+		}
+	}
+
+	results := make([]watchObservation, 0, len(devices))
+
+	for _, device := range devices {
+		if verified := w.verify(ctx, device); verified != nil {
+			results = append(results, watchObservation{device: verified, verified: true})
+		}
+	}
+
+	select {
+	case w.probes <- results:
+	case <-ctx.Done():
+	}
+}
+
+// observe hands a passively seen device to the run loop
+func (w *watcher) observe(ctx context.Context, observation watchObservation) {
+	select {
+	case w.observations <- observation:
+	case <-ctx.Done():
+	}
+}
+
+// handle applies a single observation
+func (w *watcher) handle(ctx context.Context, observation watchObservation) {
+	device := observation.device
+	key, known := w.match(device)
+
+	switch {
+	case observation.gone:
+		if known {
+			previous := w.devices[key]
+			delete(w.devices, key)
+			w.emit(ctx, WatchEvent{Type: DeviceDisappeared, Device: previous})
+		}
+	case !observation.verified:
+		// Announcements of known devices only count as sign of life; changes are picked up by the next probe
+		if known && w.devices[key].Host == device.Host {
+			w.devices[key].LastSeen = time.Now()
+			return
+		}
+
+		if w.pending[device.Host] {
+			return
+		}
+
+		w.pending[device.Host] = true
+
+		go func() {
+			if verified := w.verify(ctx, device); verified != nil {
+				w.observe(ctx, watchObservation{device: verified, verified: true})
+				return
+			}
+
+			w.observe(ctx, watchObservation{device: device, verified: true})
+		}()
+	default:
+		delete(w.pending, device.Host)
+
+		if device.Metadata["deviceID"] == "" {
+			// The device did not answer /info, so it is not a SoundTouch speaker we can use
+			return
+		}
+
+		w.remember(device)
+
+		if !known {
+			w.devices[deviceKey(device)] = device
+			w.emit(ctx, WatchEvent{Type: DeviceAppeared, Device: device})
+
+			return
+		}
+
+		previous := w.devices[key]
+		w.devices[key] = device
+
+		if changes := deviceChanges(previous, device); len(changes) > 0 {
+			w.emit(ctx, WatchEvent{Type: DeviceChanged, Device: device, Previous: previous, Changes: changes})
+		}
+	}
+}
+
+// remember records a verified device in the registry of the service, if one is attached
+func (w *watcher) remember(device *models.DiscoveredDevice) {
+	registry := w.service.getRegistry()
+	if registry == nil {
+		return
+	}
+
+	registry.Record(device)
+
+	if err := registry.Save(); err != nil {
+		log.Printf("Watch: %v", err)
+	}
+}
+
+// expire reports devices that were not seen for a while as disappeared
+func (w *watcher) expire(ctx context.Context) {
+	for key, device := range w.devices {
+		if time.Since(device.LastSeen) >= w.expireAfter {
+			delete(w.devices, key)
+			w.emit(ctx, WatchEvent{Type: DeviceDisappeared, Device: device})
+		}
+	}
+}
+
+func (w *watcher) emit(ctx context.Context, event WatchEvent) {
+	select {
+	case w.events <- event:
+	case <-ctx.Done():
+	}
+}
+
+// known returns copies of the known devices for a probe
+func (w *watcher) known() []*models.DiscoveredDevice {
+	devices := make([]*models.DiscoveredDevice, 0, len(w.devices))
+	for _, device := range w.devices {
+		known := *device
+		devices = append(devices, &known)
+	}
+
+	return devices
+}
+
+// match finds the key of a known device. Verified devices match by device ID,
+// announcements by UPnP UUID, mDNS service or host.
+func (w *watcher) match(device *models.DiscoveredDevice) (string, bool) {
+	if key := deviceKey(device); key != "" {
+		_, ok := w.devices[key]
+
+		return key, ok
+	}
+
+	for key, known := range w.devices {
+		switch {
+		case upnpUUID(device.UPnPUSN) != "" && upnpUUID(device.UPnPUSN) == upnpUUID(known.UPnPUSN):
+			return key, true
+		case device.MDNSService != "" && device.MDNSService == known.MDNSService:
+			return key, true
+		case device.Host != "" && device.Host == known.Host:
+			return key, true
+		}
+	}
+
+	return "", false
+}
+
+// verify fetches /info of a device and returns a copy carrying its identity, name and firmware
+func (w *watcher) verify(ctx context.Context, device *models.DiscoveredDevice) *models.DiscoveredDevice {
+	info, err := w.info(ctx, device)
+	if err != nil {
+		log.Printf("Watch: %s did not answer /info: %v", device.Host, err)
+		return nil
+	}
+
+	verified := *device
+	verified.LastSeen = time.Now()
+	verified.Metadata = make(map[string]string)
+
+	for k, v := range device.Metadata {
+		verified.Metadata[k] = v
+	}
+
+	applyDeviceInfo(&verified, info)
+
+	return &verified
+}
+
+// listenSSDP turns SSDP NOTIFY messages into observations
+func (w *watcher) listenSSDP(ctx context.Context) {
+	conns, err := listenMulticast(w.service.config, ssdpAddr, ssdpIPv6Addr)
+	if len(conns) == 0 {
+		log.Printf("Watch: SSDP listener unavailable, relying on active probes: %v", err)
+		return
+	}
+
+	if err != nil {
+		log.Printf("Watch: SSDP listener incomplete: %v", err)
+	}
+
+	for _, conn := range conns {
+		go w.readMulticast(ctx, conn, 4096, func(data []byte, from *net.UDPAddr) {
+			if device, alive, ok := w.service.ssdpService.parseNotify(string(data)); ok {
+				if alive {
+					scopeDevice(device, from)
+				}
+
+				w.observe(ctx, watchObservation{device: device, gone: !alive})
+			}
+		})
+	}
+}
+
+// listenMDNS turns mDNS announcements of SoundTouch services into observations
+func (w *watcher) listenMDNS(ctx context.Context) {
+	conns, err := listenMulticast(w.service.config, mdnsAddr, mdnsIPv6Addr)
+	if len(conns) == 0 {
+		log.Printf("Watch: mDNS listener unavailable, relying on active probes: %v", err)
+		return
+	}
+
+	if err != nil {
+		log.Printf("Watch: mDNS listener incomplete: %v", err)
+	}
+
+	for _, conn := range conns {
+		go w.readMulticast(ctx, conn, 9000, func(data []byte, from *net.UDPAddr) {
+			var msg dns.Msg
+			if err := msg.Unpack(data); err != nil || !msg.Response {
+				return
+			}
+
+			for _, observation := range w.service.mdnsService.parseAnnouncement(&msg) {
+				if !observation.gone {
+					scopeDevice(observation.device, from)
+				}
+
+				w.observe(ctx, observation)
+			}
+		})
+	}
+}
+
+// readMulticast hands the packets of conn to handle until the context is done
+func (w *watcher) readMulticast(ctx context.Context, conn *net.UDPConn, size int, handle func(data []byte, from *net.UDPAddr)) {
+	go func() {
+		<-ctx.Done()
+
+		_ = conn.Close()
+	}()
+
+	buffer := make([]byte, size)
+
+	for {
+		n, from, err := conn.ReadFromUDP(buffer)
+		if err != nil {
+			return
+		}
+
+		handle(buffer[:n], from)
+	}
+}
+
+// parseNotify parses an SSDP NOTIFY message of a media renderer.
+// alive is false for ssdp:byebye.
+func (d *Service) parseNotify(message string) (device *models.DiscoveredDevice, alive, ok bool) {
+	lines := strings.Split(strings.ReplaceAll(message, "\r\n", "\n"), "\n")
+	if !strings.HasPrefix(lines[0], "NOTIFY * HTTP/1.1") {
+		return nil, false, false
+	}
+
+	headers := make(map[string]string)
+
+	for _, line := range lines[1:] {
+		parts := strings.SplitN(strings.TrimSpace(line), ":", 2)
+		if len(parts) == 2 {
+			headers[strings.ToLower(strings.TrimSpace(parts[0]))] = strings.TrimSpace(parts[1])
+		}
+	}
+
+	if !strings.Contains(strings.ToLower(headers["nt"]), "mediarenderer") {
+		return nil, false, false
+	}
+
+	alive = headers["nts"] != "ssdp:byebye"
+
+	if !alive {
+		// A byebye has no location; the USN identifies the device
+		return &models.DiscoveredDevice{UPnPUSN: headers["usn"], DiscoveryMethod: "SSDP/UPnP"}, false, headers["usn"] != ""
+	}
+
+	device, err := d.parseLocationURL(headers["location"], headers["usn"])
+	if err != nil {
+		return nil, false, false
+	}
+
+	return device, true, true
+}
+
+// parseAnnouncement extracts SoundTouch services from an mDNS response.
+// Services announced with a TTL of zero are gone.
+func (m *MDNSDiscoveryService) parseAnnouncement(msg *dns.Msg) []watchObservation {
+	records := append(append([]dns.RR{}, msg.Answer...), msg.Extra...)
+	entries := make(map[string]*mdns.ServiceEntry)
+	gone := make(map[string]bool)
+	hostsV4 := make(map[string]net.IP)
+	hostsV6 := make(map[string]net.IP)
+
+	entry := func(name string) *mdns.ServiceEntry {
+		if entries[name] == nil {
+			entries[name] = &mdns.ServiceEntry{Name: name}
+		}
+
+		return entries[name]
+	}
+
+	suffix := "." + soundTouchServiceType + "." + soundTouchDomain
+
+	for _, record := range records {
+		switch rr := record.(type) {
+		case *dns.PTR:
+			if strings.HasSuffix(rr.Ptr, suffix) {
+				entry(rr.Ptr)
+
+				if rr.Hdr.Ttl == 0 {
+					gone[rr.Ptr] = true
+				}
+			}
+		case *dns.SRV:
+			if strings.HasSuffix(rr.Hdr.Name, suffix) {
+				e := entry(rr.Hdr.Name)
+				e.Host = rr.Target
+				e.Port = int(rr.Port)
+			}
+		case *dns.A:
+			hostsV4[rr.Hdr.Name] = rr.A
+		case *dns.AAAA:
+			hostsV6[rr.Hdr.Name] = rr.AAAA
+		}
+	}
+
+	var observations []watchObservation
+
+	for name, e := range entries {
+		if gone[name] {
+			observations = append(observations, watchObservation{device: &models.DiscoveredDevice{MDNSService: name}, gone: true})
+			continue
+		}
+
+		e.AddrV4 = hostsV4[e.Host]
+		e.AddrV6 = hostsV6[e.Host]
+
+		if e.AddrV4 == nil && e.AddrV6 == nil {
+			// Without an address record the announcement is only a hint; the next probe resolves it
+			continue
+		}
+
+		if device := m.serviceEntryToDevice(e); device != nil {
+			observations = append(observations, watchObservation{device: device})
+		}
+	}
+
+	return observations
+}
+
+// fetchDeviceInfo reads /info of a device with a short timeout
+func fetchDeviceInfo(ctx context.Context, device *models.DiscoveredDevice) (*models.DeviceInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	return requestDeviceInfo(ctx, device.Host, device.Port)
+}
+
+// deviceKey identifies a verified device across IP changes
+func deviceKey(device *models.DiscoveredDevice) string {
+	return strings.ToUpper(device.Metadata["deviceID"])
+}
+
+// deviceChanges lists the fields a device event reports as changed
+func deviceChanges(previous, current *models.DiscoveredDevice) []string {
+	var changes []string
+
+	if previous.Host != current.Host || previous.Port != current.Port {
+		changes = append(changes, "host")
+	}
+
+	if previous.Name != current.Name {
+		changes = append(changes, "name")
+	}
+
+	if previous.Metadata["firmware"] != current.Metadata["firmware"] {
+		changes = append(changes, "firmware")
+	}
+
+	return changes
+}
+
+// upnpUUID returns the uuid part of a USN like "uuid:xyz::urn:schemas-upnp-org:device:MediaRenderer:1"
+func upnpUUID(usn string) string {
+	uuid, _, _ := strings.Cut(usn, "::")
+
+	return strings.ToLower(strings.TrimPrefix(uuid, "uuid:"))
+}
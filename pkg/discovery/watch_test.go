@@ -0,0 +1,292 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/config"
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/miekg/dns"
+)
+
+// fakeWatchNetwork serves /info for a set of speakers and answers probes with the speakers that are up
+type fakeWatchNetwork struct {
+	mu       sync.Mutex
+	speakers []*fakeWatchSpeaker
+}
+
+type fakeWatchSpeaker struct {
+	deviceID string
+	name     string
+	firmware string
+	host     string
+	port     int
+	up       bool
+}
+
+func (n *fakeWatchNetwork) add(t *testing.T, deviceID, name string) *fakeWatchSpeaker {
+	t.Helper()
+
+	speaker := &fakeWatchSpeaker{deviceID: deviceID, name: name, firmware: "27.0.6", up: true}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+
+		if !speaker.up {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		_, _ = fmt.Fprintf(w, `<info deviceID="%s"><name>%s</name><type>SoundTouch 10</type><components><component><componentCategory>SCM</componentCategory><softwareVersion>%s</softwareVersion><serialNumber>SERIAL-%s</serialNumber></component></components></info>`,
+			speaker.deviceID, speaker.name, speaker.firmware, speaker.deviceID)
+	}))
+	t.Cleanup(server.Close)
+
+	u, _ := url.Parse(server.URL)
+	speaker.host = u.Hostname()
+	speaker.port, _ = strconv.Atoi(u.Port())
+
+	n.mu.Lock()
+	n.speakers = append(n.speakers, speaker)
+	n.mu.Unlock()
+
+	return speaker
+}
+
+func (n *fakeWatchNetwork) probe(_ context.Context) []*models.DiscoveredDevice {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var devices []*models.DiscoveredDevice
+
+	for _, speaker := range n.speakers {
+		if speaker.up {
+			devices = append(devices, &models.DiscoveredDevice{
+				Host:            speaker.host,
+				Port:            speaker.port,
+				Name:            "SoundTouch-" + speaker.host,
+				LastSeen:        time.Now(),
+				DiscoveryMethod: "SSDP/UPnP",
+			})
+		}
+	}
+
+	return devices
+}
+
+func (n *fakeWatchNetwork) update(fn func()) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	fn()
+}
+
+func startTestWatch(t *testing.T, network *fakeWatchNetwork) (*watcher, <-chan WatchEvent) {
+	t.Helper()
+
+	cfg := config.DefaultConfig()
+	cfg.UPnPEnabled = false
+	cfg.MDNSEnabled = false
+
+	service := NewUnifiedDiscoveryService(cfg)
+	service.SetWatchInterval(20 * time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	w := service.newWatcher()
+	w.probe = network.probe
+
+	go w.run(ctx)
+
+	return w, w.events
+}
+
+func nextEvent(t *testing.T, events <-chan WatchEvent) WatchEvent {
+	t.Helper()
+
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for a watch event")
+		return WatchEvent{}
+	}
+}
+
+func TestWatch_AppearedChangedDisappeared(t *testing.T) {
+	network := &fakeWatchNetwork{}
+	kitchen := network.add(t, "AABBCC000001", "Kitchen")
+
+	_, events := startTestWatch(t, network)
+
+	event := nextEvent(t, events)
+	if event.Type != DeviceAppeared || event.Device.Name != "Kitchen" {
+		t.Fatalf("Expected Kitchen to appear, got %s %+v", event.Type, event.Device)
+	}
+
+	if event.Device.Metadata["firmware"] != "27.0.6" || event.Device.SerialNo != "SERIAL-AABBCC000001" {
+		t.Errorf("Expected device to be enriched from /info, got %+v", event.Device)
+	}
+
+	network.update(func() {
+		kitchen.name = "Kitchen Counter"
+		kitchen.firmware = "27.0.7"
+	})
+
+	event = nextEvent(t, events)
+	if event.Type != DeviceChanged || event.Device.Name != "Kitchen Counter" || event.Previous.Name != "Kitchen" {
+		t.Fatalf("Expected Kitchen to change, got %s %+v", event.Type, event.Device)
+	}
+
+	if len(event.Changes) != 2 || event.Changes[0] != "name" || event.Changes[1] != "firmware" {
+		t.Errorf("Expected name and firmware changes, got %v", event.Changes)
+	}
+
+	network.update(func() { kitchen.up = false })
+
+	event = nextEvent(t, events)
+	if event.Type != DeviceDisappeared || event.Device.Name != "Kitchen Counter" {
+		t.Fatalf("Expected Kitchen to disappear, got %s %+v", event.Type, event.Device)
+	}
+}
+
+func TestWatch_HostChange(t *testing.T) {
+	network := &fakeWatchNetwork{}
+	network.add(t, "AABBCC000001", "Kitchen")
+
+	_, events := startTestWatch(t, network)
+
+	event := nextEvent(t, events)
+	if event.Type != DeviceAppeared {
+		t.Fatalf("Expected device to appear, got %s", event.Type)
+	}
+
+	oldHost := fmt.Sprintf("%s:%d", event.Device.Host, event.Device.Port)
+
+	// The same speaker answers on a new address, the old one is gone
+	network.update(func() { network.speakers[0].up = false })
+	moved := network.add(t, "AABBCC000001", "Kitchen")
+
+	event = nextEvent(t, events)
+	if event.Type != DeviceChanged || len(event.Changes) != 1 || event.Changes[0] != "host" {
+		t.Fatalf("Expected a host change, got %s %v", event.Type, event.Changes)
+	}
+
+	if event.Device.Port != moved.port || fmt.Sprintf("%s:%d", event.Previous.Host, event.Previous.Port) != oldHost {
+		t.Errorf("Expected move from %s to port %d, got %+v", oldHost, moved.port, event.Device)
+	}
+}
+
+func TestWatch_IgnoresDevicesWithoutInfo(t *testing.T) {
+	network := &fakeWatchNetwork{}
+	network.add(t, "AABBCC000001", "Kitchen")
+	network.update(func() { network.speakers[0].up = false })
+
+	w, events := startTestWatch(t, network)
+
+	// Announced but not answering /info
+	w.observations <- watchObservation{device: &models.DiscoveredDevice{Host: "127.0.0.1", Port: 1}}
+
+	select {
+	case event := <-events:
+		t.Fatalf("Expected no event, got %s %+v", event.Type, event.Device)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestWatch_ClosesOnCancel(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.UPnPEnabled = false
+	cfg.MDNSEnabled = false
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := NewUnifiedDiscoveryService(cfg).Watch(ctx)
+
+	cancel()
+
+	select {
+	case _, ok := <-events:
+		for ok {
+			_, ok = <-events
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the event channel to be closed")
+	}
+}
+
+func TestParseNotify(t *testing.T) {
+	service := NewService(time.Second)
+
+	alive := "NOTIFY * HTTP/1.1\r\n" +
+		"HOST: 239.255.255.250:1900\r\n" +
+		"LOCATION: http://192.168.1.10:8091/XD/BO5EBO5E-F00D-F00D-FEED-AABBCC000001.xml\r\n" +
+		"NT: urn:schemas-upnp-org:device:MediaRenderer:1\r\n" +
+		"NTS: ssdp:alive\r\n" +
+		"USN: uuid:BO5EBO5E-F00D-F00D-FEED-AABBCC000001::urn:schemas-upnp-org:device:MediaRenderer:1\r\n\r\n"
+
+	device, isAlive, ok := service.parseNotify(alive)
+	if !ok || !isAlive || device.Host != "192.168.1.10" || device.Port != 8090 {
+		t.Fatalf("Unexpected alive result: ok=%v alive=%v device=%+v", ok, isAlive, device)
+	}
+
+	byebye := "NOTIFY * HTTP/1.1\r\n" +
+		"NT: urn:schemas-upnp-org:device:MediaRenderer:1\r\n" +
+		"NTS: ssdp:byebye\r\n" +
+		"USN: uuid:BO5EBO5E-F00D-F00D-FEED-AABBCC000001::urn:schemas-upnp-org:device:MediaRenderer:1\r\n\r\n"
+
+	device, isAlive, ok = service.parseNotify(byebye)
+	if !ok || isAlive || upnpUUID(device.UPnPUSN) != "bo5ebo5e-f00d-f00d-feed-aabbcc000001" {
+		t.Fatalf("Unexpected byebye result: ok=%v alive=%v device=%+v", ok, isAlive, device)
+	}
+
+	if _, _, ok := service.parseNotify("M-SEARCH * HTTP/1.1\r\n\r\n"); ok {
+		t.Error("Expected non-NOTIFY messages to be ignored")
+	}
+
+	if _, _, ok := service.parseNotify("NOTIFY * HTTP/1.1\r\nNT: upnp:rootdevice\r\nNTS: ssdp:alive\r\n\r\n"); ok {
+		t.Error("Expected non-MediaRenderer notifications to be ignored")
+	}
+}
+
+func TestParseAnnouncement(t *testing.T) {
+	service := NewMDNSDiscoveryService(time.Second)
+	instance := "Kitchen." + soundTouchServiceType + "." + soundTouchDomain
+	target := "kitchen.local."
+
+	msg := &dns.Msg{}
+	msg.Response = true
+	msg.Answer = []dns.RR{
+		&dns.PTR{Hdr: dns.RR_Header{Name: soundTouchServiceType + "." + soundTouchDomain, Rrtype: dns.TypePTR, Class: dns.ClassINET, Ttl: 4500}, Ptr: instance},
+	}
+	msg.Extra = []dns.RR{
+		&dns.SRV{Hdr: dns.RR_Header{Name: instance, Rrtype: dns.TypeSRV, Class: dns.ClassINET, Ttl: 120}, Target: target, Port: 8090},
+		&dns.A{Hdr: dns.RR_Header{Name: target, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 120}, A: net.ParseIP("192.168.1.10")},
+	}
+
+	observations := service.parseAnnouncement(msg)
+	if len(observations) != 1 || observations[0].gone {
+		t.Fatalf("Expected one live observation, got %+v", observations)
+	}
+
+	if device := observations[0].device; device.Host != "192.168.1.10" || device.Port != 8090 || device.Name != "Kitchen" {
+		t.Errorf("Unexpected device: %+v", device)
+	}
+
+	msg.Answer[0].Header().Ttl = 0
+	msg.Extra = nil
+
+	observations = service.parseAnnouncement(msg)
+	if len(observations) != 1 || !observations[0].gone || observations[0].device.MDNSService != instance {
+		t.Fatalf("Expected a goodbye observation, got %+v", observations)
+	}
+}
@@ -0,0 +1,241 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DeviceBackupVersion is the version of the backup file format
+const DeviceBackupVersion = 1
+
+// Sections of a device backup; each one can be captured and restored on its own
+const (
+	BackupSectionName     = "name"
+	BackupSectionLanguage = "language"
+	BackupSectionAccounts = "accounts"
+	BackupSectionPresets  = "presets"
+	BackupSectionClock    = "clock"
+	BackupSectionBass     = "bass"
+	BackupSectionBalance  = "balance"
+	BackupSectionTone     = "tone"
+	BackupSectionLevels   = "levels"
+	BackupSectionDSP      = "dsp"
+	BackupSectionZone     = "zone"
+)
+
+// BackupSections lists all sections in the order they are restored
+var BackupSections = []string{
+	BackupSectionName,
+	BackupSectionLanguage,
+	BackupSectionAccounts,
+	BackupSectionPresets,
+	BackupSectionClock,
+	BackupSectionBass,
+	BackupSectionBalance,
+	BackupSectionTone,
+	BackupSectionLevels,
+	BackupSectionDSP,
+	BackupSectionZone,
+}
+
+// DeviceBackup is the user-facing configuration of a speaker.
+// Sections lists what was captured; a captured section may still be empty, e.g. no presets or no zone.
+type DeviceBackup struct {
+	Version   int                 `json:"version"`
+	CreatedAt time.Time           `json:"createdAt"`
+	Device    BackupDevice        `json:"device"`
+	Sections  []string            `json:"sections"`
+	Name      string              `json:"name,omitempty"`
+	Language  int                 `json:"language,omitempty"`
+	Accounts  []BackupAccount     `json:"accounts,omitempty"`
+	Presets   []BackupPreset      `json:"presets,omitempty"`
+	Clock     *BackupClockDisplay `json:"clock,omitempty"`
+	Bass      *int                `json:"bass,omitempty"`
+	Balance   *int                `json:"balance,omitempty"`
+	Tone      *BackupTone         `json:"tone,omitempty"`
+	Levels    *BackupLevels       `json:"levels,omitempty"`
+	DSP       *BackupDSP          `json:"dsp,omitempty"`
+	Zone      *BackupZone         `json:"zone,omitempty"`
+}
+
+// BackupDevice identifies the speaker a backup was taken from
+type BackupDevice struct {
+	DeviceID string `json:"deviceID"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Host     string `json:"host,omitempty"`
+}
+
+// BackupAccount is a configured music service account; passwords are never stored
+type BackupAccount struct {
+	Source      string `json:"source"`
+	Account     string `json:"account"`
+	DisplayName string `json:"displayName,omitempty"`
+}
+
+// BackupPreset is a stored preset with its full content item
+type BackupPreset struct {
+	Slot         int    `json:"slot"`
+	Source       string `json:"source"`
+	Type         string `json:"type,omitempty"`
+	Location     string `json:"location,omitempty"`
+	Account      string `json:"account,omitempty"`
+	IsPresetable bool   `json:"isPresetable"`
+	Name         string `json:"name,omitempty"`
+	Artwork      string `json:"artwork,omitempty"`
+}
+
+// NewBackupPreset copies a preset of the device
+func NewBackupPreset(preset *Preset) BackupPreset {
+	item := preset.ContentItem
+
+	return BackupPreset{
+		Slot:         preset.ID,
+		Source:       item.Source,
+		Type:         item.Type,
+		Location:     item.Location,
+		Account:      item.SourceAccount,
+		IsPresetable: item.IsPresetable,
+		Name:         item.ItemName,
+		Artwork:      item.ContainerArt,
+	}
+}
+
+// ContentItem returns the content item to store the preset with
+func (p *BackupPreset) ContentItem() *ContentItem {
+	return &ContentItem{
+		Source:        p.Source,
+		Type:          p.Type,
+		Location:      p.Location,
+		SourceAccount: p.Account,
+		IsPresetable:  p.IsPresetable,
+		ItemName:      p.Name,
+		ContainerArt:  p.Artwork,
+	}
+}
+
+// SameContent reports whether both presets play the same content. Name and artwork are
+// left out, as the device may rewrite them when storing a preset.
+func (p *BackupPreset) SameContent(other *BackupPreset) bool {
+	return p.Source == other.Source && p.Type == other.Type && p.Location == other.Location && p.Account == other.Account
+}
+
+// String returns the preset name and source
+func (p *BackupPreset) String() string {
+	if p.Name == "" {
+		return fmt.Sprintf("%s %s", p.Source, p.Location)
+	}
+
+	return fmt.Sprintf("%s (%s)", p.Name, p.Source)
+}
+
+// BackupClockDisplay holds the clock display settings
+type BackupClockDisplay struct {
+	Enabled    bool   `json:"enabled"`
+	Format     string `json:"format,omitempty"`
+	Brightness int    `json:"brightness"`
+	AutoDim    bool   `json:"autoDim"`
+	TimeZone   string `json:"timeZone,omitempty"`
+}
+
+// String returns a one-line description of the settings
+func (c *BackupClockDisplay) String() string {
+	state := "off"
+	if c.Enabled {
+		state = "on"
+	}
+
+	return fmt.Sprintf("%s, format %s, brightness %d, auto-dim %t", state, c.Format, c.Brightness, c.AutoDim)
+}
+
+// BackupTone holds the advanced bass and treble settings
+type BackupTone struct {
+	Bass   int `json:"bass"`
+	Treble int `json:"treble"`
+}
+
+// BackupLevels holds the speaker level settings of home theater systems
+type BackupLevels struct {
+	FrontCenter  int `json:"frontCenter"`
+	RearSurround int `json:"rearSurround"`
+}
+
+// BackupDSP holds the audio DSP settings
+type BackupDSP struct {
+	AudioMode           string `json:"audioMode"`
+	VideoSyncAudioDelay int    `json:"videoSyncAudioDelay"`
+}
+
+// BackupZone is the multiroom zone the device belonged to
+type BackupZone struct {
+	Master  string             `json:"master"`
+	Members []BackupZoneMember `json:"members"`
+}
+
+// BackupZoneMember is a speaker of a backed up zone
+type BackupZoneMember struct {
+	DeviceID string `json:"deviceID"`
+	IP       string `json:"ip,omitempty"`
+}
+
+// HasSection returns true if the section was captured
+func (b *DeviceBackup) HasSection(section string) bool {
+	for _, captured := range b.Sections {
+		if captured == section {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate checks the format version and the section names
+func (b *DeviceBackup) Validate() error {
+	switch {
+	case b.Version == 0:
+		return fmt.Errorf("not a device backup: version is missing")
+	case b.Version > DeviceBackupVersion:
+		return fmt.Errorf("backup format version %d is newer than the supported version %d", b.Version, DeviceBackupVersion)
+	}
+
+	return ValidateBackupSections(b.Sections)
+}
+
+// ParseDeviceBackup reads a backup file and validates it
+func ParseDeviceBackup(data []byte) (*DeviceBackup, error) {
+	var backup DeviceBackup
+
+	if err := json.Unmarshal(data, &backup); err != nil {
+		return nil, fmt.Errorf("failed to parse backup: %w", err)
+	}
+
+	if err := backup.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &backup, nil
+}
+
+// ValidateBackupSections returns an error for unknown section names
+func ValidateBackupSections(sections []string) error {
+	for _, section := range sections {
+		if !IsValidBackupSection(section) {
+			return fmt.Errorf("unknown backup section %q, valid sections are: %s", section, strings.Join(BackupSections, ", "))
+		}
+	}
+
+	return nil
+}
+
+// IsValidBackupSection checks if the name is a known backup section
+func IsValidBackupSection(section string) bool {
+	for _, known := range BackupSections {
+		if section == known {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseDeviceBackup(t *testing.T) {
+	bass := -3
+	backup := &DeviceBackup{
+		Version:  DeviceBackupVersion,
+		Device:   BackupDevice{DeviceID: "AAA", Name: "Kitchen", Type: "SoundTouch 10"},
+		Sections: []string{BackupSectionName, BackupSectionPresets, BackupSectionBass},
+		Name:     "Kitchen",
+		Presets:  []BackupPreset{{Slot: 1, Source: "TUNEIN", Location: "/v1/playback/station/s1", Name: "Radio", Artwork: "http://art/1.png"}},
+		Bass:     &bass,
+	}
+
+	data, err := json.Marshal(backup)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	parsed, err := ParseDeviceBackup(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !parsed.HasSection(BackupSectionPresets) || parsed.HasSection(BackupSectionZone) {
+		t.Errorf("Unexpected sections: %v", parsed.Sections)
+	}
+
+	item := parsed.Presets[0].ContentItem()
+	if item.Source != "TUNEIN" || item.ItemName != "Radio" || item.ContainerArt != "http://art/1.png" || *parsed.Bass != -3 {
+		t.Errorf("Unexpected round trip: %+v, %+v", parsed, item)
+	}
+
+	tests := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{"not json", `<info />`, "failed to parse backup"},
+		{"no version", `{"sections": []}`, "version is missing"},
+		{"newer version", `{"version": 99}`, "newer than the supported version"},
+		{"unknown section", `{"version": 1, "sections": ["volume"]}`, `unknown backup section "volume"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseDeviceBackup([]byte(tt.data)); err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ClockCheck is the result of comparing the clock of a speaker with the reference time in its time zone
+type ClockCheck struct {
+	DeviceID  string    `json:"deviceId,omitempty"`
+	Speaker   string    `json:"speaker,omitempty"`
+	Zone      string    `json:"zone"`
+	CheckedAt time.Time `json:"checkedAt"`
+	// DeviceTime is the wall clock time the speaker showed, in Zone
+	DeviceTime time.Time `json:"deviceTime"`
+	// DriftSeconds is how far the wall clock of the speaker is ahead (positive) or behind (negative)
+	DriftSeconds int64 `json:"driftSeconds"`
+	// OffsetErrorSeconds is how far the UTC offset the speaker applies is off, e.g. after a missed DST transition
+	OffsetErrorSeconds int64  `json:"offsetErrorSeconds,omitempty"`
+	Corrected          bool   `json:"corrected"`
+	Error              string `json:"error,omitempty"`
+}
+
+// NewClockCheck compares the clock time reported by a speaker with now in loc.
+// The wall clock of the speaker is compared if it reports one, otherwise its UTC time.
+func NewClockCheck(ct *ClockTime, now time.Time, loc *time.Location) (*ClockCheck, error) {
+	if ct == nil || ct.IsEmpty() {
+		return nil, fmt.Errorf("no time data available")
+	}
+
+	expected := now.In(loc)
+	check := &ClockCheck{Zone: loc.String(), CheckedAt: now.UTC()}
+	utc := ct.GetUTC()
+
+	if lt := ct.LocalTime; lt != nil {
+		// Wall clock times are compared as if they were UTC, so the UTC offsets do not interfere
+		wall := time.Date(lt.Year, time.Month(lt.Month+1), lt.DayOfMonth, lt.Hour, lt.Minute, lt.Second, 0, time.UTC)
+		expectedWall := time.Date(expected.Year(), expected.Month(), expected.Day(), expected.Hour(), expected.Minute(), expected.Second(), 0, time.UTC)
+
+		check.DriftSeconds = int64(wall.Sub(expectedWall) / time.Second)
+
+		if utc > 0 {
+			_, offset := time.Unix(utc, 0).In(loc).Zone()
+			offsetError := time.Duration(wall.Unix()-utc-int64(offset)) * time.Second
+			check.OffsetErrorSeconds = int64(offsetError.Round(time.Minute) / time.Second)
+		}
+	} else if utc > 0 {
+		check.DriftSeconds = utc - now.Unix()
+	} else {
+		deviceTime, err := ct.GetTime()
+		if err != nil {
+			return nil, err
+		}
+
+		check.DriftSeconds = int64(deviceTime.Sub(now) / time.Second)
+	}
+
+	check.DeviceTime = expected.Add(check.Drift()).Truncate(time.Second)
+
+	return check, nil
+}
+
+// Drift returns how far the wall clock of the speaker is ahead (positive) or behind (negative)
+func (c *ClockCheck) Drift() time.Duration {
+	return time.Duration(c.DriftSeconds) * time.Second
+}
+
+// OffsetError returns how far the UTC offset the speaker applies is off
+func (c *ClockCheck) OffsetError() time.Duration {
+	return time.Duration(c.OffsetErrorSeconds) * time.Second
+}
+
+// NeedsCorrection reports whether the clock drifted more than threshold or applies a wrong UTC offset
+func (c *ClockCheck) NeedsCorrection(threshold time.Duration) bool {
+	drift := c.Drift()
+	if drift < 0 {
+		drift = -drift
+	}
+
+	return drift > threshold || c.OffsetErrorSeconds != 0
+}
+
+// String returns a short description of the check, e.g. "2m5s ahead (Europe/Berlin), corrected"
+func (c *ClockCheck) String() string {
+	if c.Error != "" {
+		return "failed: " + c.Error
+	}
+
+	var parts []string
+
+	switch drift := c.Drift(); {
+	case drift > 0:
+		parts = append(parts, fmt.Sprintf("%v ahead", drift))
+	case drift < 0:
+		parts = append(parts, fmt.Sprintf("%v behind", -drift))
+	default:
+		parts = append(parts, "in sync")
+	}
+
+	if offset := c.OffsetError(); offset > 0 {
+		parts = append(parts, fmt.Sprintf("UTC offset %v ahead", offset))
+	} else if offset < 0 {
+		parts = append(parts, fmt.Sprintf("UTC offset %v behind", -offset))
+	}
+
+	result := strings.Join(parts, ", ") + " (" + c.Zone + ")"
+	if c.Corrected {
+		result += ", corrected"
+	}
+
+	return result
+}
+
+// ParseClockZone loads a time zone by its IANA name, e.g. Europe/Berlin. Empty and Local select the local time zone.
+func ParseClockZone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.EqualFold(name, "local") {
+		return time.Local, nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q (expected an IANA name like Europe/Berlin): %w", name, err)
+	}
+
+	return loc, nil
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewClockCheck(t *testing.T) {
+	berlin, err := ParseClockZone("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+
+	// 14:00 CEST, the day DST started
+	now := time.Date(2026, 3, 29, 12, 0, 0, 0, time.UTC)
+
+	localTime := func(hour, minute, second int) *LocalTime {
+		return &LocalTime{Year: 2026, Month: 2, DayOfMonth: 29, Hour: hour, Minute: minute, Second: second}
+	}
+
+	tests := []struct {
+		name        string
+		clockTime   *ClockTime
+		drift       int64
+		offsetError int64
+		correct     bool
+		description string
+	}{
+		{
+			name:        "in sync",
+			clockTime:   &ClockTime{UTC: now.Unix() + 3, LocalTime: localTime(14, 0, 3)},
+			drift:       3,
+			description: "3s ahead (Europe/Berlin)",
+		},
+		{
+			name:        "missed DST transition",
+			clockTime:   &ClockTime{UTC: now.Unix(), LocalTime: localTime(13, 0, 0)},
+			drift:       -3600,
+			offsetError: -3600,
+			correct:     true,
+			description: "1h0m0s behind, UTC offset 1h0m0s behind (Europe/Berlin)",
+		},
+		{
+			name:        "lost time after power cut",
+			clockTime:   &ClockTime{UTC: now.Unix() - 300, LocalTime: localTime(13, 55, 0)},
+			drift:       -300,
+			correct:     true,
+			description: "5m0s behind (Europe/Berlin)",
+		},
+		{
+			name:        "UTC only",
+			clockTime:   &ClockTime{UTC: now.Unix() + 120},
+			drift:       120,
+			correct:     true,
+			description: "2m0s ahead (Europe/Berlin)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check, err := NewClockCheck(tt.clockTime, now, berlin)
+			if err != nil {
+				t.Fatalf("NewClockCheck() error = %v", err)
+			}
+
+			if check.DriftSeconds != tt.drift || check.OffsetErrorSeconds != tt.offsetError {
+				t.Errorf("Expected drift %d and offset error %d, got %d and %d", tt.drift, tt.offsetError, check.DriftSeconds, check.OffsetErrorSeconds)
+			}
+
+			if check.NeedsCorrection(10*time.Second) != tt.correct {
+				t.Errorf("NeedsCorrection() = %v, want %v", !tt.correct, tt.correct)
+			}
+
+			if check.String() != tt.description {
+				t.Errorf("String() = %q, want %q", check.String(), tt.description)
+			}
+		})
+	}
+
+	if _, err := NewClockCheck(&ClockTime{}, now, berlin); err == nil {
+		t.Error("Expected an error for an empty clock time")
+	}
+}
+
+func TestParseClockZone(t *testing.T) {
+	for _, name := range []string{"", "local", "Local"} {
+		if loc, err := ParseClockZone(name); err != nil || loc != time.Local {
+			t.Errorf("ParseClockZone(%q) = %v, %v; want the local time zone", name, loc, err)
+		}
+	}
+
+	if _, err := ParseClockZone("Mars/Olympus_Mons"); err == nil {
+		t.Error("Expected an error for an unknown time zone")
+	}
+}
@@ -68,11 +68,12 @@ type DiscoveredDevice struct {
 	InfoURL    string `json:"info_url"`     // http://host:port/info
 
 	// Protocol-specific details
-	UPnPLocation string `json:"upnp_location,omitempty"` // UPnP device description XML URL
-	UPnPUSN      string `json:"upnp_usn,omitempty"`      // UPnP Unique Service Name
-	MDNSHostname string `json:"mdns_hostname,omitempty"` // mDNS hostname (e.g., "device.local.")
-	MDNSService  string `json:"mdns_service,omitempty"`  // mDNS service name
-	ConfigName   string `json:"config_name,omitempty"`   // Original name from config
+	UPnPLocation string        `json:"upnp_location,omitempty"` // UPnP device description XML URL
+	UPnPUSN      string        `json:"upnp_usn,omitempty"`      // UPnP Unique Service Name
+	UPnPServices []UPnPService `json:"upnp_services,omitempty"` // Services from the UPnP device description
+	MDNSHostname string        `json:"mdns_hostname,omitempty"` // mDNS hostname (e.g., "device.local.")
+	MDNSService  string        `json:"mdns_service,omitempty"`  // mDNS service name
+	ConfigName   string        `json:"config_name,omitempty"`   // Original name from config
 
 	// Additional metadata
 	Metadata map[string]string `json:"metadata,omitempty"`
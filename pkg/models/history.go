@@ -0,0 +1,493 @@
+package models
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// History entry origins
+const (
+	// HistoryOriginRecent is an entry recorded from a recent item the speaker reported to Marge
+	HistoryOriginRecent = "recent"
+	// HistoryOriginNowPlaying is an entry recorded from a nowPlayingUpdated event of the speaker
+	HistoryOriginNowPlaying = "nowPlaying"
+)
+
+// historyPlayGap is how long a speaker may stay silent about a play before the same content counts as a new play
+const historyPlayGap = 10 * time.Minute
+
+// HistoryEntry is one play in the listening history kept by soundtouch-service
+type HistoryEntry struct {
+	Time          time.Time `json:"time"`
+	DeviceID      string    `json:"deviceId"`
+	Speaker       string    `json:"speaker,omitempty"`
+	Origin        string    `json:"origin"`
+	Source        string    `json:"source"`
+	SourceAccount string    `json:"sourceAccount,omitempty"`
+	Type          string    `json:"type,omitempty"`
+	Location      string    `json:"location,omitempty"`
+	Name          string    `json:"name,omitempty"`
+	Track         string    `json:"track,omitempty"`
+	Artist        string    `json:"artist,omitempty"`
+	Album         string    `json:"album,omitempty"`
+	StationName   string    `json:"stationName,omitempty"`
+}
+
+// NewNowPlayingHistoryEntry creates a history entry for what a speaker plays.
+// It returns nil if nothing is playing, e.g. in standby or while paused.
+func NewNowPlayingHistoryEntry(deviceID, speaker string, np *NowPlaying, at time.Time) *HistoryEntry {
+	if np == nil || np.Source == "" || np.Source == "STANDBY" || np.Source == "INVALID_SOURCE" {
+		return nil
+	}
+
+	if np.PlayStatus != PlayStatusPlaying && np.PlayStatus != PlayStatusBuffering {
+		return nil
+	}
+
+	entry := &HistoryEntry{
+		Time:          at.UTC(),
+		DeviceID:      deviceID,
+		Speaker:       speaker,
+		Origin:        HistoryOriginNowPlaying,
+		Source:        np.Source,
+		SourceAccount: np.SourceAccount,
+		Track:         np.Track,
+		Artist:        np.Artist,
+		Album:         np.Album,
+		StationName:   np.StationName,
+	}
+
+	if item := np.ContentItem; item != nil {
+		entry.Type = item.Type
+		entry.Location = item.Location
+		entry.Name = item.ItemName
+	}
+
+	return entry
+}
+
+// Station returns the radio station of the entry, or an empty string if it is no radio play
+func (e *HistoryEntry) Station() string {
+	if e.StationName != "" {
+		return e.StationName
+	}
+
+	if strings.Contains(strings.ToLower(e.Type), "station") {
+		return e.Name
+	}
+
+	return ""
+}
+
+// Title returns the best description of what was played
+func (e *HistoryEntry) Title() string {
+	switch {
+	case e.Track != "" && e.Artist != "":
+		return e.Artist + " - " + e.Track
+	case e.Track != "":
+		return e.Track
+	case e.Station() != "":
+		return e.Station()
+	case e.Name != "":
+		return e.Name
+	default:
+		return e.Source
+	}
+}
+
+// Continues reports whether the entry repeats the previous entry of the same speaker and origin, last seen at lastSeen.
+// Speakers send now playing events on every pause or volume change, which must not count as new plays.
+func (e *HistoryEntry) Continues(prev *HistoryEntry, lastSeen time.Time) bool {
+	if prev == nil || !strings.EqualFold(e.DeviceID, prev.DeviceID) || e.Origin != prev.Origin {
+		return false
+	}
+
+	if e.Time.Sub(lastSeen) > historyPlayGap {
+		return false
+	}
+
+	return e.Source == prev.Source && e.Location == prev.Location && e.Track == prev.Track && e.Artist == prev.Artist
+}
+
+// CollapseHistory drops recent entries that are covered by a now playing entry of the same speaker and content,
+// so plays recorded both ways count once. The order of the entries is kept.
+func CollapseHistory(entries []HistoryEntry) []HistoryEntry {
+	key := func(e *HistoryEntry) string {
+		return strings.ToLower(e.DeviceID) + "|" + e.Source + "|" + e.Location
+	}
+
+	playing := make(map[string][]time.Time)
+
+	for i := range entries {
+		if entries[i].Origin == HistoryOriginNowPlaying {
+			k := key(&entries[i])
+			playing[k] = append(playing[k], entries[i].Time)
+		}
+	}
+
+	result := make([]HistoryEntry, 0, len(entries))
+
+	for i := range entries {
+		if entries[i].Origin == HistoryOriginRecent && coveredPlay(playing[key(&entries[i])], entries[i].Time) {
+			continue
+		}
+
+		result = append(result, entries[i])
+	}
+
+	return result
+}
+
+// coveredPlay reports whether one of the times lies within the play gap around at
+func coveredPlay(times []time.Time, at time.Time) bool {
+	for _, t := range times {
+		if d := t.Sub(at); d > -historyPlayGap && d < historyPlayGap {
+			return true
+		}
+	}
+
+	return false
+}
+
+// historyCSVHeader names the columns of CSVRecord
+var historyCSVHeader = []string{"time", "deviceId", "speaker", "origin", "source", "sourceAccount", "type", "location", "name", "track", "artist", "album", "stationName"}
+
+// CSVRecord returns the entry as CSV columns
+func (e *HistoryEntry) CSVRecord() []string {
+	return []string{
+		e.Time.UTC().Format(time.RFC3339), e.DeviceID, e.Speaker, e.Origin, e.Source, e.SourceAccount,
+		e.Type, e.Location, e.Name, e.Track, e.Artist, e.Album, e.StationName,
+	}
+}
+
+// WriteHistoryCSV writes the entries as CSV with a header row
+func WriteHistoryCSV(w io.Writer, entries []HistoryEntry) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(historyCSVHeader); err != nil {
+		return err
+	}
+
+	for i := range entries {
+		if err := cw.Write(entries[i].CSVRecord()); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// HistoryQuery selects history entries. Empty fields match everything.
+type HistoryQuery struct {
+	// Text is searched case-insensitively in names, tracks, artists, albums, stations and locations
+	Text string
+	// Speaker matches the device ID or the speaker name
+	Speaker string
+	Source  string
+	From    time.Time
+	To      time.Time
+	// Limit returns only the newest entries; 0 returns all
+	Limit int
+}
+
+// Matches reports whether the query selects the entry; the limit is not considered
+func (q *HistoryQuery) Matches(e *HistoryEntry) bool {
+	if !q.From.IsZero() && e.Time.Before(q.From) || !q.To.IsZero() && !e.Time.Before(q.To) {
+		return false
+	}
+
+	if q.Speaker != "" && !strings.EqualFold(q.Speaker, e.DeviceID) && !strings.EqualFold(q.Speaker, e.Speaker) {
+		return false
+	}
+
+	if q.Source != "" && !strings.EqualFold(q.Source, e.Source) {
+		return false
+	}
+
+	if q.Text == "" {
+		return true
+	}
+
+	text := strings.ToLower(q.Text)
+
+	for _, field := range []string{e.Name, e.Track, e.Artist, e.Album, e.StationName, e.Location} {
+		if strings.Contains(strings.ToLower(field), text) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Filter returns the selected entries, newest first, limited to Limit
+func (q *HistoryQuery) Filter(entries []HistoryEntry) []HistoryEntry {
+	result := []HistoryEntry{}
+
+	for i := range entries {
+		if q.Matches(&entries[i]) {
+			result = append(result, entries[i])
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Time.After(result[j].Time) })
+
+	if q.Limit > 0 && len(result) > q.Limit {
+		result = result[:q.Limit]
+	}
+
+	return result
+}
+
+// ParseHistoryPeriod returns the start of a period ending at now.
+// The period is today, week, month or year, a number of days like 7d, or a duration like 12h.
+func ParseHistoryPeriod(period string, now time.Time) (time.Time, error) {
+	period = strings.ToLower(strings.TrimSpace(period))
+
+	switch period {
+	case "":
+		return time.Time{}, nil
+	case "today":
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()), nil
+	case "week":
+		return now.AddDate(0, 0, -7), nil
+	case "month":
+		return now.AddDate(0, -1, 0), nil
+	case "year":
+		return now.AddDate(-1, 0, 0), nil
+	}
+
+	if days, ok := strings.CutSuffix(period, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n > 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+
+	if d, err := time.ParseDuration(period); err == nil && d > 0 {
+		return now.Add(-d), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid period %q (expected today, week, month, year, a number of days like 7d or a duration like 12h)", period)
+}
+
+// HistoryCount is how often something was played
+type HistoryCount struct {
+	Name  string `json:"name"`
+	Plays int    `json:"plays"`
+}
+
+// HistorySpeakerStats is the breakdown of the plays of one speaker
+type HistorySpeakerStats struct {
+	DeviceID string         `json:"deviceId"`
+	Speaker  string         `json:"speaker,omitempty"`
+	Plays    int            `json:"plays"`
+	Stations []HistoryCount `json:"stations"`
+	Artists  []HistoryCount `json:"artists"`
+	Sources  []HistoryCount `json:"sources"`
+}
+
+// HistoryStats summarizes history entries
+type HistoryStats struct {
+	Plays    int                   `json:"plays"`
+	First    *time.Time            `json:"first,omitempty"`
+	Last     *time.Time            `json:"last,omitempty"`
+	Stations []HistoryCount        `json:"stations"`
+	Artists  []HistoryCount        `json:"artists"`
+	Sources  []HistoryCount        `json:"sources"`
+	Speakers []HistorySpeakerStats `json:"speakers"`
+}
+
+// historyCounter counts plays by name and keeps the first spelling of every name
+type historyCounter struct {
+	counts map[string]int
+	names  map[string]string
+}
+
+func newHistoryCounter() *historyCounter {
+	return &historyCounter{counts: make(map[string]int), names: make(map[string]string)}
+}
+
+func (hc *historyCounter) add(name string) {
+	if name == "" {
+		return
+	}
+
+	key := strings.ToLower(name)
+	if _, ok := hc.names[key]; !ok {
+		hc.names[key] = name
+	}
+
+	hc.counts[key]++
+}
+
+// top returns the most played names, at most limit unless limit is 0
+func (hc *historyCounter) top(limit int) []HistoryCount {
+	counts := make([]HistoryCount, 0, len(hc.counts))
+	for key, plays := range hc.counts {
+		counts = append(counts, HistoryCount{Name: hc.names[key], Plays: plays})
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Plays != counts[j].Plays {
+			return counts[i].Plays > counts[j].Plays
+		}
+
+		return strings.ToLower(counts[i].Name) < strings.ToLower(counts[j].Name)
+	})
+
+	if limit > 0 && len(counts) > limit {
+		counts = counts[:limit]
+	}
+
+	return counts
+}
+
+// historyBreakdown counts stations, artists and sources
+type historyBreakdown struct {
+	plays                      int
+	stations, artists, sources *historyCounter
+}
+
+func newHistoryBreakdown() *historyBreakdown {
+	return &historyBreakdown{stations: newHistoryCounter(), artists: newHistoryCounter(), sources: newHistoryCounter()}
+}
+
+func (hb *historyBreakdown) add(e *HistoryEntry) {
+	hb.plays++
+	hb.stations.add(e.Station())
+	hb.artists.add(e.Artist)
+	hb.sources.add(e.Source)
+}
+
+// NewHistoryStats counts the plays of the entries, with the top stations, artists and sources
+// overall and per speaker. Only the top most played are listed, unless top is 0.
+func NewHistoryStats(entries []HistoryEntry, top int) *HistoryStats {
+	overall := newHistoryBreakdown()
+	speakers := make(map[string]*historyBreakdown)
+	names := make(map[string]string)
+	stats := &HistoryStats{}
+
+	var ids []string
+
+	for i := range entries {
+		e := &entries[i]
+		overall.add(e)
+
+		id := strings.ToLower(e.DeviceID)
+		if _, ok := speakers[id]; !ok {
+			speakers[id] = newHistoryBreakdown()
+			ids = append(ids, e.DeviceID)
+		}
+
+		speakers[id].add(e)
+
+		if e.Speaker != "" {
+			names[id] = e.Speaker
+		}
+
+		if stats.First == nil || e.Time.Before(*stats.First) {
+			stats.First = &entries[i].Time
+		}
+
+		if stats.Last == nil || e.Time.After(*stats.Last) {
+			stats.Last = &entries[i].Time
+		}
+	}
+
+	stats.Plays = overall.plays
+	stats.Stations = overall.stations.top(top)
+	stats.Artists = overall.artists.top(top)
+	stats.Sources = overall.sources.top(top)
+	stats.Speakers = []HistorySpeakerStats{}
+
+	for _, deviceID := range ids {
+		id := strings.ToLower(deviceID)
+		breakdown := speakers[id]
+
+		stats.Speakers = append(stats.Speakers, HistorySpeakerStats{
+			DeviceID: deviceID,
+			Speaker:  names[id],
+			Plays:    breakdown.plays,
+			Stations: breakdown.stations.top(top),
+			Artists:  breakdown.artists.top(top),
+			Sources:  breakdown.sources.top(top),
+		})
+	}
+
+	sort.SliceStable(stats.Speakers, func(i, j int) bool { return stats.Speakers[i].Plays > stats.Speakers[j].Plays })
+
+	return stats
+}
+
+// HistoryRetention limits how much history is kept. Zero values keep everything.
+type HistoryRetention struct {
+	MaxAge     time.Duration
+	MaxEntries int
+}
+
+// ParseHistoryAge parses a maximum history age as a number of days like 365d or a duration like 720h.
+// Empty and 0 keep the history forever.
+func ParseHistoryAge(value string) (time.Duration, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" || value == "0" {
+		return 0, nil
+	}
+
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	}
+
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return d, nil
+	}
+
+	return 0, fmt.Errorf("invalid history age %q (expected a number of days like 365d or a duration like 720h)", value)
+}
+
+// Apply returns the entries to keep at now, in their original order
+func (r HistoryRetention) Apply(entries []HistoryEntry, now time.Time) []HistoryEntry {
+	kept := make([]HistoryEntry, 0, len(entries))
+
+	for i := range entries {
+		if r.MaxAge > 0 && now.Sub(entries[i].Time) > r.MaxAge {
+			continue
+		}
+
+		kept = append(kept, entries[i])
+	}
+
+	if r.MaxEntries <= 0 || len(kept) <= r.MaxEntries {
+		return kept
+	}
+
+	// Drop the oldest entries beyond the limit
+	order := make([]int, len(kept))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return kept[order[i]].Time.After(kept[order[j]].Time) })
+
+	keep := make(map[int]bool, r.MaxEntries)
+	for _, i := range order[:r.MaxEntries] {
+		keep[i] = true
+	}
+
+	limited := make([]HistoryEntry, 0, r.MaxEntries)
+
+	for i := range kept {
+		if keep[i] {
+			limited = append(limited, kept[i])
+		}
+	}
+
+	return limited
+}
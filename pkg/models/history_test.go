@@ -0,0 +1,224 @@
+package models
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+var historyBase = time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+
+func TestNewNowPlayingHistoryEntry(t *testing.T) {
+	np := &NowPlaying{
+		Source:      "TUNEIN",
+		PlayStatus:  PlayStatusPlaying,
+		StationName: "Radio Paradise",
+		Track:       "Song",
+		Artist:      "Band",
+		ContentItem: &ContentItem{Source: "TUNEIN", Type: "stationurl", Location: "/v1/playback/station/s1", ItemName: "Radio Paradise"},
+	}
+
+	entry := NewNowPlayingHistoryEntry("AAA", "Kitchen", np, historyBase)
+	if entry == nil {
+		t.Fatal("Expected an entry for a playing speaker")
+	}
+
+	if entry.Origin != HistoryOriginNowPlaying || entry.Location != "/v1/playback/station/s1" || entry.Station() != "Radio Paradise" {
+		t.Errorf("Unexpected entry: %+v", entry)
+	}
+
+	if entry.Title() != "Band - Song" {
+		t.Errorf("Expected artist and track as title, got %q", entry.Title())
+	}
+
+	paused := *np
+	paused.PlayStatus = PlayStatusPaused
+
+	if NewNowPlayingHistoryEntry("AAA", "Kitchen", &paused, historyBase) != nil {
+		t.Error("Expected no entry for a paused speaker")
+	}
+
+	if NewNowPlayingHistoryEntry("AAA", "Kitchen", &NowPlaying{Source: "STANDBY", PlayStatus: PlayStatusPlaying}, historyBase) != nil {
+		t.Error("Expected no entry in standby")
+	}
+}
+
+func TestHistoryEntry_Continues(t *testing.T) {
+	prev := HistoryEntry{Time: historyBase, DeviceID: "AAA", Origin: HistoryOriginNowPlaying, Source: "TUNEIN", Location: "s1", Track: "Song"}
+
+	same := prev
+	same.Time = historyBase.Add(5 * time.Minute)
+
+	if !same.Continues(&prev, historyBase) {
+		t.Error("Expected the same play within the gap to continue")
+	}
+
+	later := prev
+	later.Time = historyBase.Add(time.Hour)
+
+	if !later.Continues(&prev, historyBase.Add(55*time.Minute)) {
+		t.Error("Expected the play to continue when it was seen recently")
+	}
+
+	if later.Continues(&prev, historyBase) {
+		t.Error("Expected a new play after the gap")
+	}
+
+	next := same
+	next.Track = "Other Song"
+
+	if next.Continues(&prev, historyBase) {
+		t.Error("Expected a new play for another track")
+	}
+}
+
+func TestCollapseHistory(t *testing.T) {
+	entries := []HistoryEntry{
+		{Time: historyBase, DeviceID: "AAA", Origin: HistoryOriginNowPlaying, Source: "TUNEIN", Location: "s1"},
+		{Time: historyBase.Add(time.Minute), DeviceID: "aaa", Origin: HistoryOriginRecent, Source: "TUNEIN", Location: "s1"},
+		{Time: historyBase.Add(time.Hour), DeviceID: "AAA", Origin: HistoryOriginRecent, Source: "TUNEIN", Location: "s1"},
+		{Time: historyBase, DeviceID: "BBB", Origin: HistoryOriginRecent, Source: "TUNEIN", Location: "s1"},
+	}
+
+	got := CollapseHistory(entries)
+	if len(got) != 3 || got[1].Time != historyBase.Add(time.Hour) || got[2].DeviceID != "BBB" {
+		t.Errorf("Expected only the covered recent to be dropped, got %+v", got)
+	}
+}
+
+func TestHistoryQuery_Filter(t *testing.T) {
+	entries := []HistoryEntry{
+		{Time: historyBase, DeviceID: "AAA", Speaker: "Kitchen", Source: "TUNEIN", StationName: "Radio Paradise"},
+		{Time: historyBase.Add(time.Hour), DeviceID: "BBB", Speaker: "Office", Source: "SPOTIFY", Artist: "Band", Track: "Song"},
+		{Time: historyBase.Add(2 * time.Hour), DeviceID: "AAA", Speaker: "Kitchen", Source: "SPOTIFY", Artist: "Band", Track: "Other"},
+	}
+
+	tests := []struct {
+		name  string
+		query HistoryQuery
+		want  []time.Duration
+	}{
+		{"all newest first", HistoryQuery{}, []time.Duration{2 * time.Hour, time.Hour, 0}},
+		{"text", HistoryQuery{Text: "paradise"}, []time.Duration{0}},
+		{"speaker name", HistoryQuery{Speaker: "kitchen"}, []time.Duration{2 * time.Hour, 0}},
+		{"speaker id and source", HistoryQuery{Speaker: "aaa", Source: "spotify"}, []time.Duration{2 * time.Hour}},
+		{"range", HistoryQuery{From: historyBase.Add(time.Hour), To: historyBase.Add(2 * time.Hour)}, []time.Duration{time.Hour}},
+		{"limit", HistoryQuery{Text: "band", Limit: 1}, []time.Duration{2 * time.Hour}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.query.Filter(entries)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %d entries, got %+v", len(tt.want), got)
+			}
+
+			for i, offset := range tt.want {
+				if !got[i].Time.Equal(historyBase.Add(offset)) {
+					t.Errorf("Entry %d: expected %v, got %v", i, historyBase.Add(offset), got[i].Time)
+				}
+			}
+		})
+	}
+}
+
+func TestParseHistoryPeriod(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		period  string
+		want    time.Time
+		wantErr bool
+	}{
+		{"", time.Time{}, false},
+		{"today", time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), false},
+		{"week", now.AddDate(0, 0, -7), false},
+		{"Month", now.AddDate(0, -1, 0), false},
+		{"30d", now.AddDate(0, 0, -30), false},
+		{"12h", now.Add(-12 * time.Hour), false},
+		{"0d", time.Time{}, true},
+		{"soon", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseHistoryPeriod(tt.period, now)
+		if (err != nil) != tt.wantErr || !got.Equal(tt.want) {
+			t.Errorf("ParseHistoryPeriod(%q) = %v, %v; want %v, error %v", tt.period, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestNewHistoryStats(t *testing.T) {
+	entries := []HistoryEntry{
+		{Time: historyBase, DeviceID: "AAA", Speaker: "Kitchen", Source: "TUNEIN", StationName: "Radio Paradise"},
+		{Time: historyBase.Add(time.Hour), DeviceID: "AAA", Speaker: "Kitchen", Source: "TUNEIN", Type: "stationurl", Name: "radio paradise"},
+		{Time: historyBase.Add(2 * time.Hour), DeviceID: "BBB", Source: "SPOTIFY", Artist: "Band", Track: "Song"},
+	}
+
+	stats := NewHistoryStats(entries, 1)
+
+	if stats.Plays != 3 || !stats.First.Equal(historyBase) || !stats.Last.Equal(historyBase.Add(2*time.Hour)) {
+		t.Errorf("Unexpected totals: %+v", stats)
+	}
+
+	if len(stats.Stations) != 1 || stats.Stations[0] != (HistoryCount{Name: "Radio Paradise", Plays: 2}) {
+		t.Errorf("Expected Radio Paradise as top station, got %+v", stats.Stations)
+	}
+
+	if len(stats.Sources) != 1 || stats.Sources[0].Name != "TUNEIN" {
+		t.Errorf("Expected the sources limited to TUNEIN, got %+v", stats.Sources)
+	}
+
+	if len(stats.Speakers) != 2 || stats.Speakers[0].Speaker != "Kitchen" || stats.Speakers[0].Plays != 2 || stats.Speakers[1].Artists[0].Name != "Band" {
+		t.Errorf("Unexpected speaker breakdown: %+v", stats.Speakers)
+	}
+
+	if empty := NewHistoryStats(nil, 10); empty.Plays != 0 || empty.First != nil || empty.Speakers == nil {
+		t.Errorf("Unexpected stats without entries: %+v", empty)
+	}
+}
+
+func TestHistoryRetention_Apply(t *testing.T) {
+	entries := []HistoryEntry{
+		{Time: historyBase.AddDate(0, 0, -40), Name: "old"},
+		{Time: historyBase.AddDate(0, 0, -2), Name: "two"},
+		{Time: historyBase.AddDate(0, 0, -1), Name: "one"},
+		{Time: historyBase, Name: "now"},
+	}
+
+	if got := (HistoryRetention{}).Apply(entries, historyBase); len(got) != 4 {
+		t.Errorf("Expected everything kept without limits, got %d entries", len(got))
+	}
+
+	got := HistoryRetention{MaxAge: 30 * 24 * time.Hour, MaxEntries: 2}.Apply(entries, historyBase)
+	if len(got) != 2 || got[0].Name != "one" || got[1].Name != "now" {
+		t.Errorf("Expected the 2 newest entries in order, got %+v", got)
+	}
+}
+
+func TestParseHistoryAge(t *testing.T) {
+	for value, want := range map[string]time.Duration{"": 0, "0": 0, "365d": 365 * 24 * time.Hour, "720h": 720 * time.Hour} {
+		if got, err := ParseHistoryAge(value); err != nil || got != want {
+			t.Errorf("ParseHistoryAge(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+
+	if _, err := ParseHistoryAge("forever"); err == nil {
+		t.Error("Expected an error for an invalid age")
+	}
+}
+
+func TestWriteHistoryCSV(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := WriteHistoryCSV(&buf, []HistoryEntry{{Time: historyBase, DeviceID: "AAA", Origin: HistoryOriginRecent, Source: "TUNEIN", Name: "News, Weather"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "time,deviceId,") || !strings.Contains(lines[1], `"News, Weather"`) {
+		t.Errorf("Unexpected CSV: %q", buf.String())
+	}
+}
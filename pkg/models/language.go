@@ -0,0 +1,65 @@
+package models
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// SystemLanguage represents the response from and the request for the /language endpoint
+type SystemLanguage struct {
+	XMLName xml.Name `xml:"sysLanguage"`
+	Value   int      `xml:",chardata"`
+}
+
+// languageNames maps the language codes of /language to their names
+var languageNames = map[int]string{
+	1:  "Danish",
+	2:  "German",
+	3:  "English",
+	4:  "Spanish",
+	5:  "French",
+	6:  "Italian",
+	7:  "Dutch",
+	8:  "Swedish",
+	9:  "Japanese",
+	10: "Simplified Chinese",
+	11: "Traditional Chinese",
+	12: "Korean",
+	13: "Thai",
+	15: "Czech",
+	16: "Finnish",
+	17: "Greek",
+	18: "Norwegian",
+	19: "Polish",
+	20: "Portuguese",
+	21: "Romanian",
+	22: "Russian",
+	23: "Slovenian",
+	24: "Turkish",
+	25: "Hungarian",
+}
+
+// NewSystemLanguage creates a language request for the given language code
+func NewSystemLanguage(code int) *SystemLanguage {
+	return &SystemLanguage{Value: code}
+}
+
+// IsValidLanguageCode returns true if the code is a known device language
+func IsValidLanguageCode(code int) bool {
+	_, ok := languageNames[code]
+	return ok
+}
+
+// GetLanguageName returns the name of a language code, or "Unknown (code)"
+func GetLanguageName(code int) string {
+	if name, ok := languageNames[code]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Unknown (%d)", code)
+}
+
+// String returns the name of the language
+func (l *SystemLanguage) String() string {
+	return GetLanguageName(l.Value)
+}
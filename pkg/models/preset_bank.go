@@ -0,0 +1,115 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PresetBank is a named collection of up to six presets of a speaker, kept by soundtouch-service.
+// Activating a bank writes its presets to the speaker; slots without a preset are cleared.
+type PresetBank struct {
+	Name     string         `json:"name"`
+	DeviceID string         `json:"deviceId"`
+	Presets  []BackupPreset `json:"presets"`
+	// Schedule is the local time of day (HH:MM) at which the bank is activated every day; empty for manual activation
+	Schedule string `json:"schedule,omitempty"`
+	// Active marks the bank activated last on the speaker
+	Active bool `json:"active,omitempty"`
+}
+
+// Validate validates the preset bank
+func (pb *PresetBank) Validate() error {
+	if strings.TrimSpace(pb.Name) == "" {
+		return fmt.Errorf("preset bank name is required")
+	}
+
+	if strings.TrimSpace(pb.DeviceID) == "" {
+		return fmt.Errorf("device ID is required for preset bank %s", pb.Name)
+	}
+
+	if len(pb.Presets) > PresetSlots {
+		return fmt.Errorf("preset bank %s has %d presets, at most %d are allowed", pb.Name, len(pb.Presets), PresetSlots)
+	}
+
+	if err := pb.PresetSet().Validate(); err != nil {
+		return fmt.Errorf("preset bank %s: %w", pb.Name, err)
+	}
+
+	if pb.Schedule != "" {
+		if _, err := ParseTimeOfDay(pb.Schedule); err != nil {
+			return fmt.Errorf("preset bank %s: %w", pb.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// PresetSet returns the presets of the bank as a preset set, ready to be planned on the speaker
+func (pb *PresetBank) PresetSet() *PresetSet {
+	set := &PresetSet{
+		Version: PresetSetVersion,
+		Device:  BackupDevice{DeviceID: pb.DeviceID},
+		Presets: append([]BackupPreset{}, pb.Presets...),
+	}
+
+	set.sort()
+
+	return set
+}
+
+// Due returns the latest scheduled activation after from and up to to.
+// It returns false if the bank has no schedule or its time of day did not pass in between.
+func (pb *PresetBank) Due(from, to time.Time) (time.Time, bool) {
+	if pb.Schedule == "" {
+		return time.Time{}, false
+	}
+
+	offset, err := ParseTimeOfDay(pb.Schedule)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	hour, minute := int(offset/time.Hour), int(offset%time.Hour/time.Minute)
+
+	at := time.Date(to.Year(), to.Month(), to.Day(), hour, minute, 0, 0, to.Location())
+	if at.After(to) {
+		at = at.AddDate(0, 0, -1)
+	}
+
+	if !at.After(from) {
+		return time.Time{}, false
+	}
+
+	return at, true
+}
+
+// String returns a human-readable string representation
+func (pb *PresetBank) String() string {
+	var names []string
+
+	for _, preset := range pb.PresetSet().Presets {
+		names = append(names, fmt.Sprintf("%d=%s", preset.Slot, preset.Name))
+	}
+
+	line := fmt.Sprintf("%s: %s", pb.Name, strings.Join(names, ", "))
+	if len(names) == 0 {
+		line = pb.Name + ": no presets"
+	}
+
+	if pb.Schedule != "" {
+		line += " (daily at " + pb.Schedule + ")"
+	}
+
+	return line
+}
+
+// ParseTimeOfDay parses a time of day as HH:MM and returns its offset from midnight
+func ParseTimeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q (expected HH:MM)", value)
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPresetBank_Validate(t *testing.T) {
+	valid := PresetBank{Name: "morning", DeviceID: "AAA", Presets: []BackupPreset{{Slot: 1, Name: "News"}}, Schedule: "07:00"}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	tests := map[string]PresetBank{
+		"missing name":      {DeviceID: "AAA"},
+		"missing device":    {Name: "morning"},
+		"slot out of range": {Name: "morning", DeviceID: "AAA", Presets: []BackupPreset{{Slot: 7}}},
+		"duplicate slot":    {Name: "morning", DeviceID: "AAA", Presets: []BackupPreset{{Slot: 2}, {Slot: 2}}},
+		"invalid schedule":  {Name: "morning", DeviceID: "AAA", Schedule: "7am"},
+		"too many presets": {Name: "morning", DeviceID: "AAA", Presets: []BackupPreset{
+			{Slot: 1}, {Slot: 2}, {Slot: 3}, {Slot: 4}, {Slot: 5}, {Slot: 6}, {Slot: 6},
+		}},
+	}
+
+	for name, bank := range tests {
+		if err := bank.Validate(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestPresetBank_Due(t *testing.T) {
+	bank := PresetBank{Name: "evening", DeviceID: "AAA", Schedule: "18:30"}
+	day := func(hour, minute int) time.Time { return time.Date(2026, 3, 10, hour, minute, 0, 0, time.UTC) }
+
+	due, ok := bank.Due(day(18, 29), day(18, 30))
+	if !ok || !due.Equal(day(18, 30)) {
+		t.Errorf("Expected bank due at 18:30, got %v, %v", due, ok)
+	}
+
+	if _, ok := bank.Due(day(18, 30), day(18, 31)); ok {
+		t.Error("Expected bank not to be due twice")
+	}
+
+	if _, ok := bank.Due(day(8, 0), day(18, 0)); ok {
+		t.Error("Expected bank not to be due before 18:30")
+	}
+
+	// A check spanning midnight finds the time of the day before
+	due, ok = bank.Due(day(18, 0).AddDate(0, 0, -1), day(0, 10))
+	if !ok || !due.Equal(day(18, 30).AddDate(0, 0, -1)) {
+		t.Errorf("Expected bank due the day before, got %v, %v", due, ok)
+	}
+
+	manual := PresetBank{Name: "kids", DeviceID: "AAA"}
+	if _, ok := manual.Due(day(0, 0), day(23, 59)); ok {
+		t.Error("Expected a bank without schedule never to be due")
+	}
+}
@@ -0,0 +1,190 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// PresetSetVersion is the version of the preset file format
+const PresetSetVersion = 1
+
+// PresetSlots is the number of preset slots of a speaker
+const PresetSlots = 6
+
+// PresetSet is the presets of a speaker as exported to a file, or copied to other speakers.
+// Slots without a preset are empty.
+type PresetSet struct {
+	Version   int            `json:"version"`
+	CreatedAt time.Time      `json:"createdAt"`
+	Device    BackupDevice   `json:"device"`
+	Presets   []BackupPreset `json:"presets"`
+}
+
+// NewPresetSet copies the presets of a device
+func NewPresetSet(device BackupDevice, presets *Presets) *PresetSet {
+	set := &PresetSet{
+		Version:   PresetSetVersion,
+		CreatedAt: time.Now().UTC(),
+		Device:    device,
+		Presets:   []BackupPreset{},
+	}
+
+	for i := range presets.Preset {
+		if !presets.Preset[i].IsEmpty() {
+			set.Presets = append(set.Presets, NewBackupPreset(&presets.Preset[i]))
+		}
+	}
+
+	set.sort()
+
+	return set
+}
+
+// Preset returns the preset of a slot, or nil if the slot is empty
+func (s *PresetSet) Preset(slot int) *BackupPreset {
+	for i := range s.Presets {
+		if s.Presets[i].Slot == slot {
+			return &s.Presets[i]
+		}
+	}
+
+	return nil
+}
+
+// Swap exchanges the presets of two slots
+func (s *PresetSet) Swap(a, b int) error {
+	if err := ValidatePresetSlot(a); err != nil {
+		return err
+	}
+
+	if err := ValidatePresetSlot(b); err != nil {
+		return err
+	}
+
+	for i := range s.Presets {
+		switch s.Presets[i].Slot {
+		case a:
+			s.Presets[i].Slot = b
+		case b:
+			s.Presets[i].Slot = a
+		}
+	}
+
+	s.sort()
+
+	return nil
+}
+
+// Reorder moves the preset of slot order[i] to slot i+1.
+// The order must name the slots 1 to len(order) once each; later slots keep their presets.
+func (s *PresetSet) Reorder(order []int) error {
+	if len(order) == 0 || len(order) > PresetSlots {
+		return fmt.Errorf("order must name 1 to %d slots, got %d", PresetSlots, len(order))
+	}
+
+	moves := make(map[int]int)
+
+	for i, slot := range order {
+		if slot < 1 || slot > len(order) {
+			return fmt.Errorf("order must name the slots 1 to %d once each, got %v", len(order), order)
+		}
+
+		if _, ok := moves[slot]; ok {
+			return fmt.Errorf("slot %d is named twice in %v", slot, order)
+		}
+
+		moves[slot] = i + 1
+	}
+
+	for i := range s.Presets {
+		if target, ok := moves[s.Presets[i].Slot]; ok {
+			s.Presets[i].Slot = target
+		}
+	}
+
+	s.sort()
+
+	return nil
+}
+
+// DiffSlots returns the slots whose presets differ between both sets
+func (s *PresetSet) DiffSlots(other *PresetSet) []int {
+	var slots []int
+
+	for slot := 1; slot <= PresetSlots; slot++ {
+		a, b := s.Preset(slot), other.Preset(slot)
+		if (a == nil) != (b == nil) || a != nil && *a != *b {
+			slots = append(slots, slot)
+		}
+	}
+
+	return slots
+}
+
+// Validate checks the format version and the slots
+func (s *PresetSet) Validate() error {
+	switch {
+	case s.Version == 0:
+		return fmt.Errorf("not a preset file: version is missing")
+	case s.Version > PresetSetVersion:
+		return fmt.Errorf("preset file version %d is newer than the supported version %d", s.Version, PresetSetVersion)
+	}
+
+	seen := make(map[int]bool)
+
+	for _, preset := range s.Presets {
+		if err := ValidatePresetSlot(preset.Slot); err != nil {
+			return err
+		}
+
+		if seen[preset.Slot] {
+			return fmt.Errorf("preset slot %d is defined twice", preset.Slot)
+		}
+
+		seen[preset.Slot] = true
+	}
+
+	return nil
+}
+
+func (s *PresetSet) sort() {
+	sort.Slice(s.Presets, func(i, j int) bool { return s.Presets[i].Slot < s.Presets[j].Slot })
+}
+
+// ParsePresetSet reads a preset file and validates it.
+// Device backups are read as well, as long as they contain the presets section.
+func ParsePresetSet(data []byte) (*PresetSet, error) {
+	var file struct {
+		PresetSet
+		Sections []string `json:"sections"`
+	}
+
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse preset file: %w", err)
+	}
+
+	if file.Sections != nil && !slices.Contains(file.Sections, BackupSectionPresets) {
+		return nil, fmt.Errorf("backup does not contain the %s section", BackupSectionPresets)
+	}
+
+	set := file.PresetSet
+	if err := set.Validate(); err != nil {
+		return nil, err
+	}
+
+	set.sort()
+
+	return &set, nil
+}
+
+// ValidatePresetSlot returns an error for slots outside 1 to 6
+func ValidatePresetSlot(slot int) error {
+	if slot < 1 || slot > PresetSlots {
+		return fmt.Errorf("preset slot must be between 1 and %d, got %d", PresetSlots, slot)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func presetSlots(set *PresetSet) string {
+	var names []string
+	for _, preset := range set.Presets {
+		names = append(names, strconv.Itoa(preset.Slot)+"="+preset.Name)
+	}
+
+	return strings.Join(names, " ")
+}
+
+func TestPresetSet_SwapAndReorder(t *testing.T) {
+	set := &PresetSet{Version: PresetSetVersion, Presets: []BackupPreset{
+		{Slot: 1, Name: "A"}, {Slot: 2, Name: "B"}, {Slot: 3, Name: "C"}, {Slot: 5, Name: "E"},
+	}}
+
+	if err := set.Swap(1, 5); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := presetSlots(set); got != "1=E 2=B 3=C 5=A" {
+		t.Errorf("Unexpected presets after swap: %s", got)
+	}
+
+	// Swapping with an empty slot moves the preset
+	if err := set.Swap(2, 4); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := presetSlots(set); got != "1=E 3=C 4=B 5=A" {
+		t.Errorf("Unexpected presets after swap with an empty slot: %s", got)
+	}
+
+	if err := set.Reorder([]int{3, 1, 2}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := presetSlots(set); got != "1=C 2=E 4=B 5=A" {
+		t.Errorf("Unexpected presets after reorder: %s", got)
+	}
+
+	before := &PresetSet{Version: PresetSetVersion, Presets: []BackupPreset{{Slot: 1, Name: "C"}, {Slot: 2, Name: "X"}, {Slot: 3, Name: "B"}}}
+	if got := before.DiffSlots(set); !slices.Equal(got, []int{2, 3, 4, 5}) {
+		t.Errorf("Unexpected changed slots %v", got)
+	}
+
+	for _, order := range [][]int{{}, {1, 1}, {2, 3}, {1, 2, 3, 4, 5, 6, 7}} {
+		if err := set.Reorder(order); err == nil {
+			t.Errorf("Expected an error for order %v", order)
+		}
+	}
+
+	if err := set.Swap(0, 1); err == nil {
+		t.Error("Expected an error for slot 0")
+	}
+}
+
+func TestParsePresetSet(t *testing.T) {
+	set, err := ParsePresetSet([]byte(`{"version":1,"presets":[{"slot":3,"source":"TUNEIN","name":"C"},{"slot":1,"source":"TUNEIN","name":"A"}]}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := presetSlots(set); got != "1=A 3=C" {
+		t.Errorf("Expected presets sorted by slot, got %s", got)
+	}
+
+	// A device backup with presets can be read as a preset file
+	if _, err := ParsePresetSet([]byte(`{"version":1,"sections":["name","presets"],"presets":[]}`)); err != nil {
+		t.Errorf("Unexpected error for a backup: %v", err)
+	}
+
+	tests := map[string]string{
+		"backup without presets": `{"version":1,"sections":["name"]}`,
+		"missing version":        `{"presets":[]}`,
+		"newer version":          `{"version":2,"presets":[]}`,
+		"slot out of range":      `{"version":1,"presets":[{"slot":7}]}`,
+		"duplicate slot":         `{"version":1,"presets":[{"slot":2},{"slot":2}]}`,
+		"invalid JSON":           `{`,
+	}
+
+	for name, data := range tests {
+		if _, err := ParsePresetSet([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
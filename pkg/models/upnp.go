@@ -0,0 +1,183 @@
+package models
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// UPnP service types of a SoundTouch MediaRenderer
+const (
+	UPnPServiceAVTransport       = "urn:schemas-upnp-org:service:AVTransport:1"
+	UPnPServiceRenderingControl  = "urn:schemas-upnp-org:service:RenderingControl:1"
+	UPnPServiceConnectionManager = "urn:schemas-upnp-org:service:ConnectionManager:1"
+)
+
+// UPnPDescription is the UPnP device description found at the SSDP LOCATION URL
+type UPnPDescription struct {
+	XMLName xml.Name              `xml:"root"`
+	URLBase string                `xml:"URLBase"`
+	Device  UPnPDeviceDescription `xml:"device"`
+}
+
+// UPnPDeviceDescription describes a UPnP device and its embedded devices
+type UPnPDeviceDescription struct {
+	DeviceType   string                  `xml:"deviceType"`
+	FriendlyName string                  `xml:"friendlyName"`
+	Manufacturer string                  `xml:"manufacturer"`
+	ModelName    string                  `xml:"modelName"`
+	ModelNumber  string                  `xml:"modelNumber"`
+	SerialNumber string                  `xml:"serialNumber"`
+	UDN          string                  `xml:"UDN"`
+	Services     []UPnPService           `xml:"serviceList>service"`
+	Devices      []UPnPDeviceDescription `xml:"deviceList>device"`
+}
+
+// UPnPService is a service of a UPnP device
+type UPnPService struct {
+	ServiceType string `xml:"serviceType" json:"service_type"`
+	ServiceID   string `xml:"serviceId" json:"service_id"`
+	ControlURL  string `xml:"controlURL" json:"control_url"`
+	EventSubURL string `xml:"eventSubURL" json:"event_sub_url,omitempty"`
+	SCPDURL     string `xml:"SCPDURL" json:"scpd_url,omitempty"`
+}
+
+// Services returns the services of the device and all embedded devices, with URLs
+// resolved against URLBase or, if it is missing, the location of the description
+func (d *UPnPDescription) Services(location string) []UPnPService {
+	base := d.URLBase
+	if base == "" {
+		base = location
+	}
+
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		baseURL = nil
+	}
+
+	var services []UPnPService
+
+	var collect func(device *UPnPDeviceDescription)
+
+	collect = func(device *UPnPDeviceDescription) {
+		for _, service := range device.Services {
+			service.ServiceType = strings.TrimSpace(service.ServiceType)
+			service.ControlURL = resolveUPnPURL(baseURL, service.ControlURL)
+			service.EventSubURL = resolveUPnPURL(baseURL, service.EventSubURL)
+			service.SCPDURL = resolveUPnPURL(baseURL, service.SCPDURL)
+			services = append(services, service)
+		}
+
+		for i := range device.Devices {
+			collect(&device.Devices[i])
+		}
+	}
+
+	collect(&d.Device)
+
+	return services
+}
+
+// FindUPnPService returns the first service of the given type
+func FindUPnPService(services []UPnPService, serviceType string) (UPnPService, bool) {
+	for _, service := range services {
+		if service.ServiceType == serviceType {
+			return service, true
+		}
+	}
+
+	return UPnPService{}, false
+}
+
+func resolveUPnPURL(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || base == nil {
+		return ref
+	}
+
+	resolved, err := base.Parse(ref)
+	if err != nil {
+		return ref
+	}
+
+	return resolved.String()
+}
+
+// UPnP transport states reported by AVTransport
+const (
+	UPnPStateStopped       = "STOPPED"
+	UPnPStatePlaying       = "PLAYING"
+	UPnPStatePaused        = "PAUSED_PLAYBACK"
+	UPnPStateTransitioning = "TRANSITIONING"
+	UPnPStateNoMedia       = "NO_MEDIA_PRESENT"
+)
+
+// UPnPTransportInfo is the result of the AVTransport GetTransportInfo action
+type UPnPTransportInfo struct {
+	State  string `json:"state"`
+	Status string `json:"status"`
+	Speed  string `json:"speed"`
+}
+
+// UPnPPositionInfo is the result of the AVTransport GetPositionInfo action
+type UPnPPositionInfo struct {
+	Track         string `json:"track"`
+	TrackDuration string `json:"track_duration"`
+	TrackURI      string `json:"track_uri"`
+	RelTime       string `json:"rel_time"`
+}
+
+// UPnPError is a SOAP fault returned by a UPnP service
+type UPnPError struct {
+	Action      string
+	Code        int
+	Description string
+}
+
+// Error implements the error interface
+func (e *UPnPError) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("UPnP %s failed: %d %s", e.Action, e.Code, e.Description)
+	}
+
+	return fmt.Sprintf("UPnP %s failed: error %d", e.Action, e.Code)
+}
+
+// upnpAudioTypes maps file extensions to the MIME types announced in the DIDL-Lite protocol info
+var upnpAudioTypes = map[string]string{
+	".mp3":  "audio/mpeg",
+	".aac":  "audio/aac",
+	".m4a":  "audio/mp4",
+	".flac": "audio/flac",
+	".ogg":  "audio/ogg",
+	".wav":  "audio/wav",
+}
+
+// NewUPnPTrackMetadata returns DIDL-Lite metadata describing an audio URL, as expected by SetAVTransportURI
+func NewUPnPTrackMetadata(uri, title string) string {
+	if title == "" {
+		title = uri
+	}
+
+	mimeType := "*"
+
+	if u, err := url.Parse(uri); err == nil {
+		if audioType, ok := upnpAudioTypes[strings.ToLower(path.Ext(u.Path))]; ok {
+			mimeType = audioType
+		}
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(`<DIDL-Lite xmlns="urn:schemas-upnp-org:metadata-1-0/DIDL-Lite/" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:upnp="urn:schemas-upnp-org:metadata-1-0/upnp/">`)
+	sb.WriteString(`<item id="0" parentID="-1" restricted="1"><dc:title>`)
+	_ = xml.EscapeText(&sb, []byte(title))
+	sb.WriteString(`</dc:title><upnp:class>object.item.audioItem.musicTrack</upnp:class>`)
+	fmt.Fprintf(&sb, `<res protocolInfo="http-get:*:%s:*">`, mimeType)
+	_ = xml.EscapeText(&sb, []byte(uri))
+	sb.WriteString(`</res></item></DIDL-Lite>`)
+
+	return sb.String()
+}
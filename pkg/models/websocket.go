@@ -35,6 +35,8 @@ const (
 	EventTypeRecentsUpdated WebSocketEventType = "recentsUpdated"
 	// EventTypeLanguageUpdated indicates a language setting change
 	EventTypeLanguageUpdated WebSocketEventType = "languageUpdated"
+	// EventTypeSourcesUpdated indicates a change in the available sources
+	EventTypeSourcesUpdated WebSocketEventType = "sourcesUpdated"
 	// EventTypeUnknown indicates an unrecognized event type
 	EventTypeUnknown WebSocketEventType = "unknown"
 )
@@ -66,6 +68,8 @@ func (e WebSocketEventType) String() string {
 		return "Recents Updated"
 	case EventTypeLanguageUpdated:
 		return "Language Updated"
+	case EventTypeSourcesUpdated:
+		return "Sources Updated"
 	default:
 		return "Unknown Event"
 	}
@@ -87,6 +91,7 @@ type WebSocketEvent struct {
 	ErrorUpdated           *ErrorUpdatedEvent           `xml:"errorUpdated,omitempty"`
 	RecentsUpdated         *RecentsUpdatedEvent         `xml:"recentsUpdated,omitempty"`
 	LanguageUpdated        *LanguageUpdatedEvent        `xml:"languageUpdated,omitempty"`
+	SourcesUpdated         *SourcesUpdatedEvent         `xml:"sourcesUpdated,omitempty"`
 	Timestamp              time.Time                    `json:"timestamp"` // Added by client for tracking
 }
 
@@ -142,6 +147,10 @@ func (e *WebSocketEvent) GetEvents() []interface{} {
 		events = append(events, e.LanguageUpdated)
 	}
 
+	if e.SourcesUpdated != nil {
+		events = append(events, e.SourcesUpdated)
+	}
+
 	return events
 }
 
@@ -299,6 +308,85 @@ type Language struct {
 	Value   string   `xml:",chardata"`
 }
 
+// SourcesUpdatedEvent signals that the available sources changed.
+// The device sends no payload; the current list must be fetched via /sources.
+type SourcesUpdatedEvent struct {
+	XMLName  xml.Name `xml:"sourcesUpdated"`
+	DeviceID string   `xml:"deviceID,attr"`
+}
+
+// WebSocketMessage is the request/response envelope of the gabbo WebSocket protocol.
+// Requests carry an API path and method in the header and an optional XML body;
+// the device echoes the header including the request ID in its reply.
+type WebSocketMessage struct {
+	XMLName xml.Name               `xml:"msg"`
+	Header  WebSocketMessageHeader `xml:"header"`
+	Body    *WebSocketMessageBody  `xml:"body,omitempty"`
+}
+
+// WebSocketMessageHeader identifies the target endpoint and the request
+type WebSocketMessageHeader struct {
+	DeviceID string                  `xml:"deviceID,attr"`
+	URL      string                  `xml:"url,attr"`
+	Method   string                  `xml:"method,attr"`
+	Request  WebSocketMessageRequest `xml:"request"`
+}
+
+// WebSocketMessageRequest carries the request ID used to correlate replies
+type WebSocketMessageRequest struct {
+	RequestID string               `xml:"requestID,attr"`
+	Info      WebSocketMessageInfo `xml:"info"`
+}
+
+// WebSocketMessageInfo describes the message type
+type WebSocketMessageInfo struct {
+	Type         string `xml:"type,attr,omitempty"`
+	ResponseType string `xml:"responseType,attr,omitempty"`
+}
+
+// WebSocketMessageBody holds the raw XML payload of a request or reply
+type WebSocketMessageBody struct {
+	Content []byte `xml:",innerxml"`
+}
+
+// WebSocketErrors is the error payload returned in reply to a failed request
+type WebSocketErrors struct {
+	XMLName  xml.Name `xml:"errors"`
+	DeviceID string   `xml:"deviceID,attr"`
+	Errors   []Error  `xml:"error"`
+}
+
+// NewWebSocketRequest creates a request envelope for the given endpoint
+func NewWebSocketRequest(deviceID, method, path, requestID string, body []byte) *WebSocketMessage {
+	msg := &WebSocketMessage{
+		Header: WebSocketMessageHeader{
+			DeviceID: deviceID,
+			URL:      strings.TrimPrefix(path, "/"),
+			Method:   method,
+			Request: WebSocketMessageRequest{
+				RequestID: requestID,
+				Info:      WebSocketMessageInfo{Type: "new"},
+			},
+		},
+	}
+
+	if len(body) > 0 {
+		msg.Body = &WebSocketMessageBody{Content: body}
+	}
+
+	return msg
+}
+
+// ParseWebSocketMessage parses a request/response envelope
+func ParseWebSocketMessage(data []byte) (*WebSocketMessage, error) {
+	var msg WebSocketMessage
+	if err := xml.Unmarshal(data, &msg); err != nil {
+		return nil, fmt.Errorf("failed to parse WebSocket message: %w", err)
+	}
+
+	return &msg, nil
+}
+
 // SpecialMessageType represents message types that are not part of <updates>
 type SpecialMessageType string
 
@@ -353,6 +441,7 @@ type WebSocketEventHandlers struct {
 	OnErrorUpdated        TypedEventHandler[*ErrorUpdatedEvent]
 	OnRecentsUpdated      TypedEventHandler[*RecentsUpdatedEvent]
 	OnLanguageUpdated     TypedEventHandler[*LanguageUpdatedEvent]
+	OnSourcesUpdated      TypedEventHandler[*SourcesUpdatedEvent]
 	OnUnknownEvent        EventHandler
 	OnSpecialMessage      SpecialMessageHandler
 }
@@ -398,6 +487,8 @@ func (e *WebSocketEvent) getFieldByEventType(eventType WebSocketEventType) inter
 		field = e.RecentsUpdated
 	case EventTypeLanguageUpdated:
 		field = e.LanguageUpdated
+	case EventTypeSourcesUpdated:
+		field = e.SourcesUpdated
 	}
 
 	// Use reflection or a type-safe check to ensure we only return non-nil interfaces
@@ -449,6 +540,8 @@ func isNil(i interface{}) bool {
 		return v == nil
 	case *LanguageUpdatedEvent:
 		return v == nil
+	case *SourcesUpdatedEvent:
+		return v == nil
 	}
 
 	return false
@@ -495,6 +588,8 @@ func (e *WebSocketEvent) HasEventType(eventType WebSocketEventType) bool {
 		return e.RecentsUpdated != nil
 	case EventTypeLanguageUpdated:
 		return e.LanguageUpdated != nil
+	case EventTypeSourcesUpdated:
+		return e.SourcesUpdated != nil
 	}
 
 	return false
@@ -552,6 +647,10 @@ func (e *WebSocketEvent) GetEventTypes() []WebSocketEventType {
 		types = append(types, EventTypeLanguageUpdated)
 	}
 
+	if e.SourcesUpdated != nil {
+		types = append(types, EventTypeSourcesUpdated)
+	}
+
 	return types
 }
 
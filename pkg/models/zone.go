@@ -480,3 +480,48 @@ func (zsr *ZoneSlaveRequest) String() string {
 	return fmt.Sprintf("Zone slave operation: master=%s, slave=%s",
 		zsr.Master, slave.DeviceID)
 }
+
+// ZoneDefinition is a named, persistent grouping of speakers that can be restored in one step.
+// Master and members reference speakers by name, alias, IP address or device ID.
+type ZoneDefinition struct {
+	Name    string   `json:"name"`
+	Master  string   `json:"master"`
+	Members []string `json:"members"`
+}
+
+// Validate validates the zone definition
+func (zd *ZoneDefinition) Validate() error {
+	if strings.TrimSpace(zd.Name) == "" {
+		return fmt.Errorf("zone name is required")
+	}
+
+	if strings.TrimSpace(zd.Master) == "" {
+		return fmt.Errorf("master speaker is required for zone %s", zd.Name)
+	}
+
+	if len(zd.Members) == 0 {
+		return fmt.Errorf("zone %s requires at least one member besides the master", zd.Name)
+	}
+
+	seen := map[string]bool{strings.ToLower(zd.Master): true}
+
+	for _, member := range zd.Members {
+		key := strings.ToLower(strings.TrimSpace(member))
+		if key == "" {
+			return fmt.Errorf("zone %s contains an empty member", zd.Name)
+		}
+
+		if seen[key] {
+			return fmt.Errorf("speaker %s is listed more than once in zone %s", member, zd.Name)
+		}
+
+		seen[key] = true
+	}
+
+	return nil
+}
+
+// String returns a human-readable string representation
+func (zd *ZoneDefinition) String() string {
+	return fmt.Sprintf("%s: %s + %s", zd.Name, zd.Master, strings.Join(zd.Members, ", "))
+}
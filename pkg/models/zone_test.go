@@ -810,6 +810,30 @@ func TestZoneEdgeCases(t *testing.T) {
 	})
 }
 
+func TestZoneDefinition_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition ZoneDefinition
+		wantErr    bool
+	}{
+		{"valid", ZoneDefinition{Name: "downstairs", Master: "Kitchen", Members: []string{"Living Room"}}, false},
+		{"missing name", ZoneDefinition{Master: "Kitchen", Members: []string{"Living Room"}}, true},
+		{"missing master", ZoneDefinition{Name: "downstairs", Members: []string{"Living Room"}}, true},
+		{"no members", ZoneDefinition{Name: "downstairs", Master: "Kitchen"}, true},
+		{"master as member", ZoneDefinition{Name: "downstairs", Master: "Kitchen", Members: []string{"kitchen"}}, true},
+		{"duplicate member", ZoneDefinition{Name: "downstairs", Master: "Kitchen", Members: []string{"Office", "Office"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.definition.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func BenchmarkZoneInfo_GetAllDeviceIDs(b *testing.B) {
 	zi := &ZoneInfo{
 		Master: "MASTER123",
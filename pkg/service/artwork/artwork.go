@@ -0,0 +1,304 @@
+// Package artwork fetches, stores and serves cover art for content items.
+// Art URLs reported by speakers often point to retired Bose CDNs or HTTP-only hosts,
+// so the service keeps a local copy and hands out stable /media/art/{hash} URLs instead.
+package artwork
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PathPrefix is the URL path under which cached artwork is served.
+const PathPrefix = "/media/art/"
+
+// maxImageSize limits the size of fetched images.
+const maxImageSize = 10 << 20
+
+// Entry describes where the artwork for a hash comes from.
+type Entry struct {
+	URL    string `json:"url,omitempty"`
+	Source string `json:"source,omitempty"`
+	Label  string `json:"label,omitempty"`
+}
+
+// Cache keeps artwork on disk, keyed by the content it belongs to.
+type Cache struct {
+	dir        string
+	httpClient *http.Client
+
+	mu      sync.Mutex
+	entries map[string]Entry
+	loaded  bool
+}
+
+// NewCache creates an artwork cache storing its files in dir.
+func NewCache(dir string) *Cache {
+	return &Cache{
+		dir:        dir,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		entries:    make(map[string]Entry),
+	}
+}
+
+// SetHTTPClient replaces the client used to fetch remote artwork.
+func (c *Cache) SetHTTPClient(httpClient *http.Client) {
+	c.httpClient = httpClient
+}
+
+// Key returns the cache key for a piece of content.
+// Remote art is keyed by its URL; content without art is keyed by source and label.
+func Key(artURL, source, label string) string {
+	var input string
+	if artURL != "" {
+		input = "url:" + artURL
+	} else {
+		input = "placeholder:" + strings.ToUpper(source) + "|" + label
+	}
+
+	sum := sha256.Sum256([]byte(input))
+
+	return hex.EncodeToString(sum[:16])
+}
+
+// RewriteURL registers the content and returns the local URL serving its artwork.
+// Sources without an image get a generated placeholder.
+func (c *Cache) RewriteURL(artURL, source, label string) string {
+	artURL = strings.TrimSpace(artURL)
+	if artURL != "" && !strings.HasPrefix(artURL, "http://") && !strings.HasPrefix(artURL, "https://") {
+		// Already local or an unsupported scheme
+		return artURL
+	}
+
+	if artURL == "" && source == "" && label == "" {
+		return ""
+	}
+
+	hash := Key(artURL, source, label)
+	entry := Entry{URL: artURL, Source: source, Label: label}
+
+	c.mu.Lock()
+	c.loadLocked()
+
+	if _, ok := c.entries[hash]; !ok {
+		c.entries[hash] = entry
+		if err := c.saveIndexLocked(); err != nil {
+			log.Printf("[Artwork] Failed to save index: %v", err)
+		}
+	}
+	c.mu.Unlock()
+
+	return PathPrefix + hash
+}
+
+// Lookup returns the entry registered for a hash.
+func (c *Cache) Lookup(hash string) (Entry, bool) {
+	c.mu.Lock()
+	c.loadLocked()
+	entry, ok := c.entries[hash]
+	c.mu.Unlock()
+
+	return entry, ok
+}
+
+// Get returns the image for a hash, fetching and storing remote art on first access.
+// If the remote image cannot be fetched, a placeholder is returned instead.
+func (c *Cache) Get(hash string) ([]byte, string, error) {
+	data, contentType, _, err := c.Load(hash)
+
+	return data, contentType, err
+}
+
+// Load is like Get, but also reports whether the placeholder stands in for remote art that
+// could not be fetched; such a placeholder should not be cached, since the failure may be temporary.
+func (c *Cache) Load(hash string) ([]byte, string, bool, error) {
+	if !isValidHash(hash) {
+		return nil, "", false, fmt.Errorf("invalid artwork hash: %q", hash)
+	}
+
+	entry, ok := c.Lookup(hash)
+	if !ok {
+		return nil, "", false, fmt.Errorf("unknown artwork: %s", hash)
+	}
+
+	if entry.URL == "" {
+		return Placeholder(entry.Source, entry.Label, hash), "image/svg+xml", false, nil
+	}
+
+	if data, contentType, err := c.readFile(hash); err == nil {
+		return data, contentType, false, nil
+	}
+
+	data, contentType, err := c.fetch(entry.URL)
+	if err != nil {
+		return Placeholder(entry.Source, entry.Label, hash), "image/svg+xml", true, nil
+	}
+
+	if err := c.writeFile(hash, data, contentType); err != nil {
+		log.Printf("[Artwork] Failed to store %s: %v", hash, err)
+	}
+
+	return data, contentType, false, nil
+}
+
+// fetch downloads an image and checks that the response is actually one
+func (c *Cache) fetch(artURL string) ([]byte, string, error) {
+	resp, err := c.httpClient.Get(artURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch artwork: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("failed to fetch artwork: HTTP %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read artwork: %w", err)
+	}
+
+	if len(data) > maxImageSize {
+		return nil, "", fmt.Errorf("artwork exceeds %d bytes", maxImageSize)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if !strings.HasPrefix(contentType, "image/") {
+		contentType = http.DetectContentType(data)
+	}
+
+	if !strings.HasPrefix(contentType, "image/") {
+		return nil, "", fmt.Errorf("unexpected artwork content type: %s", contentType)
+	}
+
+	return data, contentType, nil
+}
+
+func (c *Cache) readFile(hash string) ([]byte, string, error) {
+	data, err := os.ReadFile(filepath.Join(c.dir, hash))
+	if err != nil {
+		return nil, "", err
+	}
+
+	contentType := "application/octet-stream"
+	if ct, err := os.ReadFile(filepath.Join(c.dir, hash+".type")); err == nil {
+		contentType = strings.TrimSpace(string(ct))
+	}
+
+	return data, contentType, nil
+}
+
+func (c *Cache) writeFile(hash string, data []byte, contentType string) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create artwork directory: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(c.dir, hash), data, 0644); err != nil {
+		return fmt.Errorf("failed to write artwork: %w", err)
+	}
+
+	return os.WriteFile(filepath.Join(c.dir, hash+".type"), []byte(contentType), 0644)
+}
+
+// loadLocked reads the index once; the caller must hold the lock
+func (c *Cache) loadLocked() {
+	if c.loaded {
+		return
+	}
+
+	c.loaded = true
+
+	data, err := os.ReadFile(filepath.Join(c.dir, "index.json"))
+	if err != nil {
+		return
+	}
+
+	var entries map[string]Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		log.Printf("[Artwork] Failed to parse index: %v", err)
+		return
+	}
+
+	for hash, entry := range entries {
+		if _, ok := c.entries[hash]; !ok {
+			c.entries[hash] = entry
+		}
+	}
+}
+
+// saveIndexLocked persists the index; the caller must hold the lock
+func (c *Cache) saveIndexLocked() error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create artwork directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal artwork index: %w", err)
+	}
+
+	return os.WriteFile(filepath.Join(c.dir, "index.json"), data, 0644)
+}
+
+func isValidHash(hash string) bool {
+	if len(hash) != 32 {
+		return false
+	}
+
+	_, err := hex.DecodeString(hash)
+
+	return err == nil
+}
+
+// placeholderColors are the background colors used for generated artwork.
+var placeholderColors = []string{"#1f6feb", "#8957e5", "#bf3989", "#d1242f", "#bc4c00", "#1a7f37", "#0969da", "#6e7781"}
+
+// Placeholder renders an SVG cover showing the initials of the label and the source name.
+func Placeholder(source, label, hash string) []byte {
+	color := placeholderColors[0]
+	if len(hash) >= 2 {
+		if b, err := hex.DecodeString(hash[:2]); err == nil {
+			color = placeholderColors[int(b[0])%len(placeholderColors)]
+		}
+	}
+
+	text := initials(label)
+	if text == "" {
+		text = initials(source)
+	}
+
+	return []byte(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="300" height="300" viewBox="0 0 300 300">`+
+		`<rect width="300" height="300" fill="%s"/>`+
+		`<text x="150" y="165" font-family="sans-serif" font-size="96" fill="#ffffff" text-anchor="middle">%s</text>`+
+		`<text x="150" y="270" font-family="sans-serif" font-size="20" fill="#ffffff" fill-opacity="0.8" text-anchor="middle">%s</text>`+
+		`</svg>`, color, html.EscapeString(text), html.EscapeString(strings.ToUpper(source))))
+}
+
+// initials returns up to two leading letters of the words in s
+func initials(s string) string {
+	var result []rune
+
+	for _, word := range strings.Fields(s) {
+		for _, r := range word {
+			result = append(result, []rune(strings.ToUpper(string(r)))...)
+			break
+		}
+
+		if len(result) == 2 {
+			break
+		}
+	}
+
+	return string(result)
+}
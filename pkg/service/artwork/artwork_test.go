@@ -0,0 +1,125 @@
+package artwork
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// pngHeader is enough of a PNG for content type detection
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestRewriteURL(t *testing.T) {
+	cache := NewCache(t.TempDir())
+
+	url := cache.RewriteURL("http://cdn.example.com/art.png", "TUNEIN", "Radio")
+	if !strings.HasPrefix(url, PathPrefix) {
+		t.Fatalf("Expected rewritten URL under %s, got %s", PathPrefix, url)
+	}
+
+	if again := cache.RewriteURL("http://cdn.example.com/art.png", "TUNEIN", "Other"); again != url {
+		t.Errorf("Expected the same URL for the same art, got %s and %s", url, again)
+	}
+
+	if local := cache.RewriteURL("/media/art/abc", "TUNEIN", "Radio"); local != "/media/art/abc" {
+		t.Errorf("Expected local URLs to be kept, got %s", local)
+	}
+
+	if empty := cache.RewriteURL("", "", ""); empty != "" {
+		t.Errorf("Expected no URL without any content, got %s", empty)
+	}
+
+	placeholder := cache.RewriteURL("", "AUX", "AUX IN")
+	if !strings.HasPrefix(placeholder, PathPrefix) {
+		t.Errorf("Expected placeholder URL, got %s", placeholder)
+	}
+}
+
+func TestGet_FetchesAndStores(t *testing.T) {
+	var requests int32
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(pngHeader)
+	}))
+	defer upstream.Close()
+
+	dir := t.TempDir()
+	cache := NewCache(dir)
+	hash := strings.TrimPrefix(cache.RewriteURL(upstream.URL+"/cover.png", "SPOTIFY", "Album"), PathPrefix)
+
+	for i := 0; i < 2; i++ {
+		data, contentType, err := cache.Get(hash)
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+
+		if contentType != "image/png" || !bytes.Equal(data, pngHeader) {
+			t.Errorf("Unexpected artwork: %s %q", contentType, data)
+		}
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("Expected one upstream request, got %d", got)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, hash)); err != nil {
+		t.Errorf("Expected artwork file on disk: %v", err)
+	}
+
+	// A new cache on the same directory still knows the hash
+	reloaded := NewCache(dir)
+	if _, ok := reloaded.Lookup(hash); !ok {
+		t.Error("Expected index to be persisted")
+	}
+}
+
+func TestGet_FallsBackToPlaceholder(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html>gone</html>"))
+	}))
+	defer upstream.Close()
+
+	cache := NewCache(t.TempDir())
+	hash := strings.TrimPrefix(cache.RewriteURL(upstream.URL+"/retired.jpg", "TUNEIN", "Jazz Radio"), PathPrefix)
+
+	data, contentType, fallback, err := cache.Load(hash)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if contentType != "image/svg+xml" || !strings.Contains(string(data), ">JR<") {
+		t.Errorf("Expected placeholder with initials, got %s %s", contentType, data)
+	}
+
+	if !fallback {
+		t.Error("Expected the placeholder to be reported as fallback for the failed fetch")
+	}
+}
+
+func TestGet_UnknownOrInvalidHash(t *testing.T) {
+	cache := NewCache(t.TempDir())
+
+	if _, _, err := cache.Get("../index.json"); err == nil {
+		t.Error("Expected invalid hash to be rejected")
+	}
+
+	if _, _, err := cache.Get(Key("http://example.com/x.png", "", "")); err == nil {
+		t.Error("Expected unregistered hash to be rejected")
+	}
+}
+
+func TestPlaceholderEscapesText(t *testing.T) {
+	svg := string(Placeholder("<SRC>", "", Key("", "<SRC>", "")))
+
+	if strings.Contains(svg, "<SRC>") {
+		t.Errorf("Expected source to be escaped, got %s", svg)
+	}
+}
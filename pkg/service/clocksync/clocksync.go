@@ -0,0 +1,260 @@
+// Package clocksync keeps the clocks of all known speakers in sync with the host clock.
+//
+// Speakers lose their time after power cuts and may keep the UTC offset of the wrong time zone or
+// miss a DST transition. The syncer checks the clock of every speaker periodically and shortly after
+// it powered on, and sets it when it drifted more than a threshold or applies a wrong UTC offset.
+package clocksync
+
+import (
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/client"
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/gesellix/bose-soundtouch/pkg/service/datastore"
+	"github.com/gesellix/bose-soundtouch/pkg/service/worker"
+)
+
+// EventType is the device event type recorded for clock corrections and failed checks
+const EventType = "clock-sync"
+
+// Syncer checks and corrects the clocks of the speakers
+type Syncer struct {
+	mu           sync.Mutex
+	loop         worker.Loop
+	ds           *datastore.DataStore
+	speakers     worker.SpeakerSource
+	sink         worker.EventSink
+	interval     time.Duration
+	threshold    time.Duration
+	powerOnDelay time.Duration
+	zone         *time.Location
+	results      map[string]models.ClockCheck
+}
+
+// New creates a syncer for the speakers returned by source, recording corrections and failed checks in sink.
+// Speaker time zones are read from ds.
+func New(ds *datastore.DataStore, source worker.SpeakerSource, sink worker.EventSink) *Syncer {
+	return &Syncer{
+		ds:           ds,
+		speakers:     source,
+		sink:         sink,
+		interval:     time.Hour,
+		threshold:    10 * time.Second,
+		powerOnDelay: 30 * time.Second,
+		zone:         time.Local,
+		results:      make(map[string]models.ClockCheck),
+	}
+}
+
+// SetInterval sets how often all speakers are checked
+func (s *Syncer) SetInterval(interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.interval = interval
+}
+
+// SetThreshold sets how far a clock may drift before it is corrected
+func (s *Syncer) SetThreshold(threshold time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.threshold = threshold
+}
+
+// SetDefaultZone sets the time zone of speakers without a configured zone
+func (s *Syncer) SetDefaultZone(zone *time.Location) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.zone = zone
+}
+
+// Start checks all speakers right away and then periodically until Stop is called
+func (s *Syncer) Start() {
+	s.mu.Lock()
+	interval, threshold, zone := s.interval, s.threshold, s.zone
+	s.mu.Unlock()
+
+	started := s.loop.Start(func(done <-chan struct{}) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			s.Check()
+
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	})
+
+	if started {
+		log.Printf("[ClockSync] Keeping speaker clocks in sync (interval=%v, threshold=%v, zone=%s)", interval, threshold, zone)
+	}
+}
+
+// Stop ends the periodic checks
+func (s *Syncer) Stop() {
+	s.loop.Stop()
+}
+
+// Check syncs the clocks of all speakers and returns the results
+func (s *Syncer) Check() []models.ClockCheck {
+	var results []models.ClockCheck
+
+	for _, speaker := range s.speakers() {
+		if speaker.Host == "" {
+			continue
+		}
+
+		results = append(results, s.Sync(speaker))
+	}
+
+	return results
+}
+
+// Sync checks the clock of one speaker and corrects it if needed
+func (s *Syncer) Sync(speaker worker.Speaker) models.ClockCheck {
+	s.mu.Lock()
+	threshold := s.threshold
+	s.mu.Unlock()
+
+	loc := s.zoneOf(speaker.DeviceID)
+
+	port := speaker.Port
+	if port == 0 {
+		port = 8090
+	}
+
+	c := client.NewClient(&client.Config{Host: speaker.Host, Port: port, Timeout: 5 * time.Second})
+
+	check, err := c.SyncClock(loc, threshold)
+	if check == nil {
+		check = &models.ClockCheck{Zone: loc.String(), CheckedAt: time.Now().UTC()}
+	}
+
+	if err != nil {
+		check.Error = err.Error()
+	}
+
+	check.DeviceID = speaker.DeviceID
+	check.Speaker = speaker.Name
+
+	s.mu.Lock()
+	previous, seen := s.results[strings.ToLower(speaker.DeviceID)]
+	s.results[strings.ToLower(speaker.DeviceID)] = *check
+	s.mu.Unlock()
+
+	// A speaker that stays offline records its failure once
+	if check.Corrected || check.Error != "" && (!seen || previous.Error == "") {
+		s.record(check)
+	}
+
+	return *check
+}
+
+// OnPowerOn syncs the speaker at the given address once it had time to boot, if the syncer is running.
+// Speakers report power_on to the service after every start, e.g. after a power cut.
+func (s *Syncer) OnPowerOn(host string) {
+	s.mu.Lock()
+	delay := s.powerOnDelay
+	s.mu.Unlock()
+
+	if !s.loop.Running() {
+		return
+	}
+
+	speaker, ok := s.Find(host)
+	if !ok {
+		return
+	}
+
+	time.AfterFunc(delay, func() { s.Sync(speaker) })
+}
+
+// Find returns the known speaker with the given device ID or host
+func (s *Syncer) Find(id string) (worker.Speaker, bool) {
+	for _, speaker := range s.speakers() {
+		if strings.EqualFold(speaker.DeviceID, id) || speaker.Host == id {
+			return speaker, true
+		}
+	}
+
+	return worker.Speaker{}, false
+}
+
+// Results returns the latest check of every speaker, sorted by speaker name
+func (s *Syncer) Results() []models.ClockCheck {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results := make([]models.ClockCheck, 0, len(s.results))
+	for _, check := range s.results {
+		results = append(results, check)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if a, b := strings.ToLower(results[i].Speaker), strings.ToLower(results[j].Speaker); a != b {
+			return a < b
+		}
+
+		return results[i].DeviceID < results[j].DeviceID
+	})
+
+	return results
+}
+
+// zoneOf returns the time zone configured for a speaker, or the default zone
+func (s *Syncer) zoneOf(deviceID string) *time.Location {
+	s.mu.Lock()
+	zone := s.zone
+	s.mu.Unlock()
+
+	name, err := s.ds.ClockZone(deviceID)
+	if err != nil {
+		log.Printf("[ClockSync] Failed to load time zone of %s: %v", deviceID, err)
+		return zone
+	}
+
+	if name == "" {
+		return zone
+	}
+
+	loc, err := models.ParseClockZone(name)
+	if err != nil {
+		log.Printf("[ClockSync] %v, using %s for %s", err, zone, deviceID)
+		return zone
+	}
+
+	return loc
+}
+
+// record logs a correction or failure and stores it as device event
+func (s *Syncer) record(check *models.ClockCheck) {
+	data := map[string]interface{}{
+		"zone":      check.Zone,
+		"drift":     check.DriftSeconds,
+		"corrected": check.Corrected,
+	}
+
+	if check.OffsetErrorSeconds != 0 {
+		data["offsetError"] = check.OffsetErrorSeconds
+	}
+
+	if check.Error != "" {
+		data["error"] = check.Error
+		log.Printf("[ClockSync] Clock of %s: %s", check.DeviceID, check.String())
+	} else {
+		data["deviceTime"] = check.DeviceTime.Format(time.RFC3339)
+		log.Printf("[ClockSync] Corrected clock of %s: %s", check.DeviceID, check.String())
+	}
+
+	s.sink.Record(check.DeviceID, EventType, data)
+}
@@ -0,0 +1,142 @@
+package clocksync
+
+import (
+	"encoding/xml"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/gesellix/bose-soundtouch/pkg/service/datastore"
+	"github.com/gesellix/bose-soundtouch/pkg/service/worker"
+)
+
+// fakeSpeaker serves /clockTime with a clock that is offset from the host clock
+type fakeSpeaker struct {
+	mu     sync.Mutex
+	offset time.Duration
+	sets   int
+}
+
+func (f *fakeSpeaker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Method == http.MethodPost {
+		var request models.ClockTimeRequest
+		if err := xml.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		f.offset = time.Until(time.Unix(request.UTC, 0)).Round(time.Second)
+		f.sets++
+
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8" ?><status>/clockTime</status>`))
+
+		return
+	}
+
+	t := time.Now().Add(f.offset).UTC()
+	response := models.ClockTime{
+		UTC: t.Unix(),
+		LocalTime: &models.LocalTime{
+			Year: t.Year(), Month: int(t.Month()) - 1, DayOfMonth: t.Day(),
+			Hour: t.Hour(), Minute: t.Minute(), Second: t.Second(),
+		},
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	_ = xml.NewEncoder(w).Encode(response)
+}
+
+func speakerAt(t *testing.T, id, url string) worker.Speaker {
+	t.Helper()
+
+	host, portStr, err := net.SplitHostPort(url[len("http://"):])
+	if err != nil {
+		t.Fatalf("invalid test server URL %s: %v", url, err)
+	}
+
+	port, _ := strconv.Atoi(portStr)
+
+	return worker.Speaker{DeviceID: id, Name: id, Host: host, Port: port}
+}
+
+func TestSyncer_CorrectsDrift(t *testing.T) {
+	fake := &fakeSpeaker{offset: -5 * time.Minute}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	offline := httptest.NewServer(http.NotFoundHandler())
+	offlineURL := offline.URL
+	offline.Close()
+
+	speakers := []worker.Speaker{speakerAt(t, "AAA", srv.URL), speakerAt(t, "BBB", offlineURL)}
+
+	var events []models.DeviceEvent
+
+	s := New(datastore.NewDataStore(t.TempDir()), func() []worker.Speaker { return speakers }, func(_ string, event models.DeviceEvent) {
+		events = append(events, event)
+	})
+	s.SetDefaultZone(time.UTC)
+
+	results := s.Check()
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %+v", results)
+	}
+
+	if !results[0].Corrected || results[0].DriftSeconds > -290 || fake.sets != 1 {
+		t.Errorf("Expected the clock 5 minutes behind to be corrected, got %+v (%d sets)", results[0], fake.sets)
+	}
+
+	if results[1].Error == "" {
+		t.Errorf("Expected the offline speaker to fail, got %+v", results[1])
+	}
+
+	// The corrected clock is left alone and the offline speaker does not record its failure again
+	results = s.Check()
+	if results[0].Corrected || fake.sets != 1 {
+		t.Errorf("Expected the synced clock to stay untouched, got %+v (%d sets)", results[0], fake.sets)
+	}
+
+	if len(events) != 2 || events[0].Type != EventType || events[0].Data["corrected"] != true || events[1].Data["error"] == nil {
+		t.Errorf("Expected a correction and a failure event, got %+v", events)
+	}
+
+	if got := s.Results(); len(got) != 2 || got[0].DeviceID != "AAA" {
+		t.Errorf("Expected the latest result per speaker, got %+v", got)
+	}
+
+	if _, ok := s.Find(speakers[1].Host); !ok {
+		t.Error("Expected the speaker to be found by its address")
+	}
+}
+
+func TestSyncer_SpeakerZone(t *testing.T) {
+	if _, err := time.LoadLocation("Asia/Tokyo"); err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+
+	// The speaker shows UTC wall clock time, which is 9 hours behind Tokyo
+	fake := &fakeSpeaker{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	ds := datastore.NewDataStore(t.TempDir())
+	if err := ds.SetClockZone("AAA", "Asia/Tokyo"); err != nil {
+		t.Fatalf("SetClockZone failed: %v", err)
+	}
+
+	s := New(ds, func() []worker.Speaker { return nil }, nil)
+	s.SetDefaultZone(time.UTC)
+
+	check := s.Sync(speakerAt(t, "AAA", srv.URL))
+	if !check.Corrected || check.Zone != "Asia/Tokyo" || check.OffsetErrorSeconds != -9*3600 {
+		t.Errorf("Expected the UTC offset to be corrected for Asia/Tokyo, got %+v", check)
+	}
+}
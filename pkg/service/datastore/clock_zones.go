@@ -0,0 +1,97 @@
+package datastore
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+// ClockZones returns the time zones configured per speaker, keyed by upper-case device ID.
+func (ds *DataStore) ClockZones() (map[string]string, error) {
+	ds.clockZonesMutex.Lock()
+	defer ds.clockZonesMutex.Unlock()
+
+	return ds.loadClockZones()
+}
+
+// ClockZone returns the time zone configured for a speaker, or an empty string for the default zone.
+func (ds *DataStore) ClockZone(deviceID string) (string, error) {
+	zones, err := ds.ClockZones()
+	if err != nil {
+		return "", err
+	}
+
+	return zones[strings.ToUpper(deviceID)], nil
+}
+
+// SetClockZone sets the IANA time zone of a speaker's clock; an empty zone returns it to the default zone.
+func (ds *DataStore) SetClockZone(deviceID, zone string) error {
+	if deviceID == "" {
+		return fmt.Errorf("device ID is required")
+	}
+
+	if _, err := models.ParseClockZone(zone); err != nil {
+		return err
+	}
+
+	ds.clockZonesMutex.Lock()
+	defer ds.clockZonesMutex.Unlock()
+
+	zones, err := ds.loadClockZones()
+	if err != nil {
+		return err
+	}
+
+	if zone == "" {
+		delete(zones, strings.ToUpper(deviceID))
+	} else {
+		zones[strings.ToUpper(deviceID)] = zone
+	}
+
+	return ds.storeClockZones(zones)
+}
+
+func (ds *DataStore) loadClockZones() (map[string]string, error) {
+	zones := make(map[string]string)
+
+	if ds == nil || ds.DataDir == "" {
+		return zones, nil
+	}
+
+	path := filepath.Join(ds.DataDir, "clock-zones.json")
+	if !exists(path) {
+		return zones, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &zones); err != nil {
+		return nil, fmt.Errorf("failed to parse clock zones: %w", err)
+	}
+
+	return zones, nil
+}
+
+func (ds *DataStore) storeClockZones(zones map[string]string) error {
+	if ds == nil || ds.DataDir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(ds.DataDir, 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(zones, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(ds.DataDir, "clock-zones.json"), data, 0644)
+}
@@ -0,0 +1,35 @@
+package datastore
+
+import (
+	"testing"
+)
+
+func TestClockZones(t *testing.T) {
+	dir := t.TempDir()
+	ds := NewDataStore(dir)
+
+	if zone, err := ds.ClockZone("aaa"); err != nil || zone != "" {
+		t.Fatalf("Expected no zone before one is set, got %q, %v", zone, err)
+	}
+
+	if err := ds.SetClockZone("aaa", "Europe/Berlin"); err != nil {
+		t.Fatalf("SetClockZone failed: %v", err)
+	}
+
+	if err := ds.SetClockZone("BBB", "Mars/Olympus_Mons"); err == nil {
+		t.Error("Expected an error for an invalid time zone")
+	}
+
+	// Zones are persisted and device IDs are case-insensitive
+	if zone, err := NewDataStore(dir).ClockZone("AAA"); err != nil || zone != "Europe/Berlin" {
+		t.Errorf("Expected the stored zone, got %q, %v", zone, err)
+	}
+
+	if err := ds.SetClockZone("AAA", ""); err != nil {
+		t.Fatalf("SetClockZone failed: %v", err)
+	}
+
+	if zones, err := ds.ClockZones(); err != nil || len(zones) != 0 {
+		t.Errorf("Expected the zone to be removed, got %v, %v", zones, err)
+	}
+}
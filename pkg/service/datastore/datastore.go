@@ -4,11 +4,13 @@ package datastore
 import (
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -23,9 +25,15 @@ func exists(path string) bool {
 
 // DataStore represents the device and configuration storage.
 type DataStore struct {
-	DataDir      string
-	eventMutex   sync.RWMutex
-	deviceEvents map[string][]models.DeviceEvent
+	DataDir          string
+	eventMutex       sync.RWMutex
+	deviceEvents     map[string][]models.DeviceEvent
+	zonesMutex       sync.Mutex
+	presetBanksMutex sync.Mutex
+	historyMutex     sync.Mutex
+	historyEnabled   bool
+	lastHistory      map[string]lastHistoryEntry
+	clockZonesMutex  sync.Mutex
 }
 
 // NewDataStore creates a new DataStore.
@@ -901,3 +909,129 @@ func (ds *DataStore) ClearDNSDiscoveries() error {
 
 	return os.Remove(path)
 }
+
+// ErrZoneNotFound is returned when no zone definition with the requested name exists.
+var ErrZoneNotFound = errors.New("zone not found")
+
+// ListZones returns all named zone definitions sorted by name.
+func (ds *DataStore) ListZones() ([]models.ZoneDefinition, error) {
+	ds.zonesMutex.Lock()
+	defer ds.zonesMutex.Unlock()
+
+	return ds.loadZones()
+}
+
+// GetZone returns the named zone definition (case-insensitive).
+func (ds *DataStore) GetZone(name string) (*models.ZoneDefinition, error) {
+	zones, err := ds.ListZones()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range zones {
+		if strings.EqualFold(zones[i].Name, name) {
+			return &zones[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrZoneNotFound, name)
+}
+
+// SaveZone creates or replaces a named zone definition.
+func (ds *DataStore) SaveZone(zone models.ZoneDefinition) error {
+	if err := zone.Validate(); err != nil {
+		return err
+	}
+
+	ds.zonesMutex.Lock()
+	defer ds.zonesMutex.Unlock()
+
+	zones, err := ds.loadZones()
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+
+	for i := range zones {
+		if strings.EqualFold(zones[i].Name, zone.Name) {
+			zones[i] = zone
+			replaced = true
+		}
+	}
+
+	if !replaced {
+		zones = append(zones, zone)
+	}
+
+	return ds.storeZones(zones)
+}
+
+// DeleteZone removes a named zone definition.
+func (ds *DataStore) DeleteZone(name string) error {
+	ds.zonesMutex.Lock()
+	defer ds.zonesMutex.Unlock()
+
+	zones, err := ds.loadZones()
+	if err != nil {
+		return err
+	}
+
+	remaining := make([]models.ZoneDefinition, 0, len(zones))
+
+	for _, zone := range zones {
+		if !strings.EqualFold(zone.Name, name) {
+			remaining = append(remaining, zone)
+		}
+	}
+
+	if len(remaining) == len(zones) {
+		return fmt.Errorf("%w: %s", ErrZoneNotFound, name)
+	}
+
+	return ds.storeZones(remaining)
+}
+
+func (ds *DataStore) loadZones() ([]models.ZoneDefinition, error) {
+	if ds == nil || ds.DataDir == "" {
+		return []models.ZoneDefinition{}, nil
+	}
+
+	path := filepath.Join(ds.DataDir, "zones.json")
+	if !exists(path) {
+		return []models.ZoneDefinition{}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var zones []models.ZoneDefinition
+	if err := json.Unmarshal(data, &zones); err != nil {
+		return nil, fmt.Errorf("failed to parse zones: %w", err)
+	}
+
+	return zones, nil
+}
+
+func (ds *DataStore) storeZones(zones []models.ZoneDefinition) error {
+	if ds == nil || ds.DataDir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(ds.DataDir, 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	sort.Slice(zones, func(i, j int) bool {
+		return strings.ToLower(zones[i].Name) < strings.ToLower(zones[j].Name)
+	})
+
+	data, err := json.MarshalIndent(zones, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(ds.DataDir, "zones.json"), data, 0644)
+}
@@ -0,0 +1,276 @@
+package datastore
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+// historyMonth is the layout of the monthly history file names
+const historyMonth = "2006-01"
+
+// lastHistoryEntry is the latest entry recorded for a speaker and origin
+type lastHistoryEntry struct {
+	entry models.HistoryEntry
+	seen  time.Time
+}
+
+// SetHistoryEnabled turns recording of the listening history on or off; it is off by default.
+func (ds *DataStore) SetHistoryEnabled(enabled bool) {
+	ds.historyMutex.Lock()
+	defer ds.historyMutex.Unlock()
+
+	ds.historyEnabled = enabled
+}
+
+// HistoryEnabled reports whether the listening history is recorded.
+func (ds *DataStore) HistoryEnabled() bool {
+	ds.historyMutex.Lock()
+	defer ds.historyMutex.Unlock()
+
+	return ds.historyEnabled
+}
+
+// RecordHistory appends a play to the listening history, stored in one JSON Lines file per month.
+// Repetitions of the previous play of the speaker are not recorded. It returns whether the entry was recorded.
+func (ds *DataStore) RecordHistory(entry models.HistoryEntry) (bool, error) {
+	if ds == nil || ds.DataDir == "" {
+		return false, nil
+	}
+
+	ds.historyMutex.Lock()
+	defer ds.historyMutex.Unlock()
+
+	if !ds.historyEnabled {
+		return false, nil
+	}
+
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+
+	key := strings.ToLower(entry.DeviceID) + "|" + entry.Origin
+
+	if last, ok := ds.lastHistory[key]; ok && entry.Continues(&last.entry, last.seen) {
+		last.seen = entry.Time
+		ds.lastHistory[key] = last
+
+		return false, nil
+	}
+
+	dir := filepath.Join(ds.DataDir, "history")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return false, fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return false, err
+	}
+
+	path := filepath.Join(dir, entry.Time.UTC().Format(historyMonth)+".jsonl")
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return false, err
+	}
+
+	if ds.lastHistory == nil {
+		ds.lastHistory = make(map[string]lastHistoryEntry)
+	}
+
+	ds.lastHistory[key] = lastHistoryEntry{entry: entry, seen: entry.Time}
+
+	return true, nil
+}
+
+// ListHistory returns the history entries selected by the query, newest first.
+// Plays recorded both as recent item and from now playing events are returned once.
+func (ds *DataStore) ListHistory(query models.HistoryQuery) ([]models.HistoryEntry, error) {
+	ds.historyMutex.Lock()
+	defer ds.historyMutex.Unlock()
+
+	files, err := ds.historyFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []models.HistoryEntry
+
+	for _, month := range files {
+		if !historyMonthOverlaps(month, query.From, query.To) {
+			continue
+		}
+
+		monthEntries, err := ds.loadHistoryMonth(month)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, monthEntries...)
+	}
+
+	return query.Filter(models.CollapseHistory(entries)), nil
+}
+
+// PruneHistory removes the entries the retention does not keep and returns how many were removed.
+func (ds *DataStore) PruneHistory(retention models.HistoryRetention) (int, error) {
+	ds.historyMutex.Lock()
+	defer ds.historyMutex.Unlock()
+
+	files, err := ds.historyFiles()
+	if err != nil {
+		return 0, err
+	}
+
+	var all []models.HistoryEntry
+
+	counts := make(map[string]int)
+
+	for _, month := range files {
+		entries, err := ds.loadHistoryMonth(month)
+		if err != nil {
+			return 0, err
+		}
+
+		counts[month] = len(entries)
+		all = append(all, entries...)
+	}
+
+	kept := retention.Apply(all, time.Now())
+	if len(kept) == len(all) {
+		return 0, nil
+	}
+
+	// Entries are stored in the file of their month, so only files that lost entries are rewritten
+	byMonth := make(map[string][]models.HistoryEntry)
+
+	for _, entry := range kept {
+		month := entry.Time.UTC().Format(historyMonth)
+		byMonth[month] = append(byMonth[month], entry)
+	}
+
+	for _, month := range files {
+		if len(byMonth[month]) == counts[month] {
+			continue
+		}
+
+		if err := ds.storeHistoryMonth(month, byMonth[month]); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(all) - len(kept), nil
+}
+
+// historyFiles returns the months with a history file, oldest first
+func (ds *DataStore) historyFiles() ([]string, error) {
+	if ds == nil || ds.DataDir == "" {
+		return nil, nil
+	}
+
+	dirEntries, err := os.ReadDir(filepath.Join(ds.DataDir, "history"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var months []string
+
+	for _, entry := range dirEntries {
+		name, ok := strings.CutSuffix(entry.Name(), ".jsonl")
+		if !ok || entry.IsDir() {
+			continue
+		}
+
+		if _, err := time.Parse(historyMonth, name); err == nil {
+			months = append(months, name)
+		}
+	}
+
+	sort.Strings(months)
+
+	return months, nil
+}
+
+// historyMonthOverlaps reports whether a month has entries between from and to; zero times are open ends
+func historyMonthOverlaps(month string, from, to time.Time) bool {
+	start, err := time.Parse(historyMonth, month)
+	if err != nil {
+		return false
+	}
+
+	end := start.AddDate(0, 1, 0)
+
+	return (from.IsZero() || from.Before(end)) && (to.IsZero() || to.After(start))
+}
+
+func (ds *DataStore) loadHistoryMonth(month string) ([]models.HistoryEntry, error) {
+	path := filepath.Join(ds.DataDir, "history", month+".jsonl")
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []models.HistoryEntry
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var entry models.HistoryEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			// A line cut short by a crash must not make the whole month unreadable
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history %s: %w", path, err)
+	}
+
+	return entries, nil
+}
+
+func (ds *DataStore) storeHistoryMonth(month string, entries []models.HistoryEntry) error {
+	path := filepath.Join(ds.DataDir, "history", month+".jsonl")
+
+	if len(entries) == 0 {
+		return os.Remove(path)
+	}
+
+	var data []byte
+
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+
+		data = append(append(data, line...), '\n')
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,104 @@
+package datastore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+func TestHistoryPersistence(t *testing.T) {
+	ds := NewDataStore(t.TempDir())
+
+	march := time.Date(2026, 3, 31, 23, 0, 0, 0, time.UTC)
+	play := models.HistoryEntry{Time: march, DeviceID: "AAA", Origin: models.HistoryOriginNowPlaying, Source: "TUNEIN", Location: "s1", StationName: "Radio Paradise"}
+
+	if recorded, err := ds.RecordHistory(play); err != nil || recorded {
+		t.Fatalf("Expected nothing recorded while history is disabled, got %v, %v", recorded, err)
+	}
+
+	ds.SetHistoryEnabled(true)
+
+	if recorded, err := ds.RecordHistory(play); err != nil || !recorded {
+		t.Fatalf("RecordHistory failed: %v, %v", recorded, err)
+	}
+
+	// Repeated now playing events of the same play are not recorded again
+	for _, offset := range []time.Duration{5 * time.Minute, 12 * time.Minute, 20 * time.Minute} {
+		again := play
+		again.Time = march.Add(offset)
+
+		if recorded, _ := ds.RecordHistory(again); recorded {
+			t.Errorf("Expected the play at +%v to continue the previous one", offset)
+		}
+	}
+
+	// The recent item of the same play is stored, but listed once
+	recent := models.HistoryEntry{Time: march.Add(time.Minute), DeviceID: "AAA", Origin: models.HistoryOriginRecent, Source: "TUNEIN", Location: "s1", Name: "Radio Paradise"}
+	april := models.HistoryEntry{Time: march.Add(2 * time.Hour), DeviceID: "BBB", Origin: models.HistoryOriginRecent, Source: "SPOTIFY", Name: "Morning Mix"}
+
+	for _, entry := range []models.HistoryEntry{recent, april} {
+		if recorded, err := ds.RecordHistory(entry); err != nil || !recorded {
+			t.Fatalf("RecordHistory failed: %v, %v", recorded, err)
+		}
+	}
+
+	for _, month := range []string{"2026-03", "2026-04"} {
+		if _, err := os.Stat(filepath.Join(ds.DataDir, "history", month+".jsonl")); err != nil {
+			t.Errorf("Expected history file for %s: %v", month, err)
+		}
+	}
+
+	entries, err := ds.ListHistory(models.HistoryQuery{})
+	if err != nil {
+		t.Fatalf("ListHistory failed: %v", err)
+	}
+
+	if len(entries) != 2 || entries[0].Name != "Morning Mix" || entries[1].Origin != models.HistoryOriginNowPlaying {
+		t.Errorf("Expected 2 plays newest first, got %+v", entries)
+	}
+
+	entries, _ = ds.ListHistory(models.HistoryQuery{To: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)})
+	if len(entries) != 1 || entries[0].DeviceID != "AAA" {
+		t.Errorf("Expected only the March play, got %+v", entries)
+	}
+
+	removed, err := ds.PruneHistory(models.HistoryRetention{MaxEntries: 1})
+	if err != nil || removed != 2 {
+		t.Fatalf("Expected 2 entries pruned, got %d, %v", removed, err)
+	}
+
+	if _, err := os.Stat(filepath.Join(ds.DataDir, "history", "2026-03.jsonl")); !os.IsNotExist(err) {
+		t.Errorf("Expected the emptied March file to be removed, got %v", err)
+	}
+
+	entries, _ = ds.ListHistory(models.HistoryQuery{})
+	if len(entries) != 1 || entries[0].Name != "Morning Mix" {
+		t.Errorf("Expected only the newest entry after pruning, got %+v", entries)
+	}
+
+	if removed, _ := ds.PruneHistory(models.HistoryRetention{MaxEntries: 1}); removed != 0 {
+		t.Errorf("Expected nothing to prune, got %d", removed)
+	}
+}
+
+func TestListHistory_SkipsBrokenLines(t *testing.T) {
+	ds := NewDataStore(t.TempDir())
+
+	dir := filepath.Join(ds.DataDir, "history")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	data := `{"time":"2026-03-10T08:00:00Z","deviceId":"AAA","origin":"recent","source":"TUNEIN","name":"News"}` + "\n" + `{"time":"2026-03-`
+	if err := os.WriteFile(filepath.Join(dir, "2026-03.jsonl"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := ds.ListHistory(models.HistoryQuery{})
+	if err != nil || len(entries) != 1 || entries[0].Name != "News" {
+		t.Errorf("Expected the complete entry only, got %+v, %v", entries, err)
+	}
+}
@@ -0,0 +1,195 @@
+package datastore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+// ErrPresetBankNotFound is returned when a speaker has no preset bank with the requested name.
+var ErrPresetBankNotFound = errors.New("preset bank not found")
+
+// ListPresetBanks returns the preset banks of a speaker sorted by name, or of all speakers if deviceID is empty.
+func (ds *DataStore) ListPresetBanks(deviceID string) ([]models.PresetBank, error) {
+	ds.presetBanksMutex.Lock()
+	defer ds.presetBanksMutex.Unlock()
+
+	banks, err := ds.loadPresetBanks()
+	if err != nil {
+		return nil, err
+	}
+
+	if deviceID == "" {
+		return banks, nil
+	}
+
+	result := []models.PresetBank{}
+
+	for _, bank := range banks {
+		if strings.EqualFold(bank.DeviceID, deviceID) {
+			result = append(result, bank)
+		}
+	}
+
+	return result, nil
+}
+
+// GetPresetBank returns the named preset bank of a speaker (case-insensitive).
+func (ds *DataStore) GetPresetBank(deviceID, name string) (*models.PresetBank, error) {
+	banks, err := ds.ListPresetBanks(deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range banks {
+		if strings.EqualFold(banks[i].Name, name) {
+			return &banks[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrPresetBankNotFound, name)
+}
+
+// SavePresetBank creates or replaces a preset bank of a speaker.
+// A replaced bank stays active if it was.
+func (ds *DataStore) SavePresetBank(bank models.PresetBank) error {
+	if err := bank.Validate(); err != nil {
+		return err
+	}
+
+	ds.presetBanksMutex.Lock()
+	defer ds.presetBanksMutex.Unlock()
+
+	banks, err := ds.loadPresetBanks()
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+
+	for i := range banks {
+		if samePresetBank(&banks[i], bank.DeviceID, bank.Name) {
+			bank.Active = banks[i].Active
+			banks[i] = bank
+			replaced = true
+		}
+	}
+
+	if !replaced {
+		bank.Active = false
+		banks = append(banks, bank)
+	}
+
+	return ds.storePresetBanks(banks)
+}
+
+// DeletePresetBank removes a preset bank of a speaker.
+func (ds *DataStore) DeletePresetBank(deviceID, name string) error {
+	ds.presetBanksMutex.Lock()
+	defer ds.presetBanksMutex.Unlock()
+
+	banks, err := ds.loadPresetBanks()
+	if err != nil {
+		return err
+	}
+
+	remaining := make([]models.PresetBank, 0, len(banks))
+
+	for i := range banks {
+		if !samePresetBank(&banks[i], deviceID, name) {
+			remaining = append(remaining, banks[i])
+		}
+	}
+
+	if len(remaining) == len(banks) {
+		return fmt.Errorf("%w: %s", ErrPresetBankNotFound, name)
+	}
+
+	return ds.storePresetBanks(remaining)
+}
+
+// SetActivePresetBank marks the named bank as the one activated last on the speaker.
+func (ds *DataStore) SetActivePresetBank(deviceID, name string) error {
+	ds.presetBanksMutex.Lock()
+	defer ds.presetBanksMutex.Unlock()
+
+	banks, err := ds.loadPresetBanks()
+	if err != nil {
+		return err
+	}
+
+	found := false
+
+	for i := range banks {
+		if !strings.EqualFold(banks[i].DeviceID, deviceID) {
+			continue
+		}
+
+		banks[i].Active = strings.EqualFold(banks[i].Name, name)
+		found = found || banks[i].Active
+	}
+
+	if !found {
+		return fmt.Errorf("%w: %s", ErrPresetBankNotFound, name)
+	}
+
+	return ds.storePresetBanks(banks)
+}
+
+func samePresetBank(bank *models.PresetBank, deviceID, name string) bool {
+	return strings.EqualFold(bank.DeviceID, deviceID) && strings.EqualFold(bank.Name, name)
+}
+
+func (ds *DataStore) loadPresetBanks() ([]models.PresetBank, error) {
+	if ds == nil || ds.DataDir == "" {
+		return []models.PresetBank{}, nil
+	}
+
+	path := filepath.Join(ds.DataDir, "preset-banks.json")
+	if !exists(path) {
+		return []models.PresetBank{}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var banks []models.PresetBank
+	if err := json.Unmarshal(data, &banks); err != nil {
+		return nil, fmt.Errorf("failed to parse preset banks: %w", err)
+	}
+
+	return banks, nil
+}
+
+func (ds *DataStore) storePresetBanks(banks []models.PresetBank) error {
+	if ds == nil || ds.DataDir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(ds.DataDir, 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	sort.Slice(banks, func(i, j int) bool {
+		if a, b := strings.ToLower(banks[i].DeviceID), strings.ToLower(banks[j].DeviceID); a != b {
+			return a < b
+		}
+
+		return strings.ToLower(banks[i].Name) < strings.ToLower(banks[j].Name)
+	})
+
+	data, err := json.MarshalIndent(banks, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(ds.DataDir, "preset-banks.json"), data, 0644)
+}
@@ -0,0 +1,83 @@
+package datastore
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+func TestPresetBankPersistence(t *testing.T) {
+	ds := NewDataStore(t.TempDir())
+
+	banks, err := ds.ListPresetBanks("")
+	if err != nil || len(banks) != 0 {
+		t.Fatalf("Expected no preset banks initially, got %v, %v", banks, err)
+	}
+
+	morning := models.PresetBank{Name: "morning", DeviceID: "AAA", Presets: []models.BackupPreset{{Slot: 1, Source: "TUNEIN", Name: "News"}}, Schedule: "07:00"}
+	evening := models.PresetBank{Name: "evening", DeviceID: "AAA", Presets: []models.BackupPreset{{Slot: 1, Source: "TUNEIN", Name: "Jazz"}}}
+	kitchen := models.PresetBank{Name: "morning", DeviceID: "BBB"}
+
+	for _, bank := range []models.PresetBank{morning, evening, kitchen} {
+		if err := ds.SavePresetBank(bank); err != nil {
+			t.Fatalf("SavePresetBank failed: %v", err)
+		}
+	}
+
+	banks, _ = ds.ListPresetBanks("aaa")
+	if len(banks) != 2 || banks[0].Name != "evening" {
+		t.Errorf("Expected 2 banks of AAA sorted by name, got %+v", banks)
+	}
+
+	if banks, _ := ds.ListPresetBanks(""); len(banks) != 3 {
+		t.Errorf("Expected 3 banks of all speakers, got %+v", banks)
+	}
+
+	if err := ds.SetActivePresetBank("AAA", "MORNING"); err != nil {
+		t.Fatalf("SetActivePresetBank failed: %v", err)
+	}
+
+	// Saving under an existing name replaces the presets, but keeps the bank active
+	morning.Presets = nil
+	if err := ds.SavePresetBank(morning); err != nil {
+		t.Fatalf("SavePresetBank failed: %v", err)
+	}
+
+	bank, err := ds.GetPresetBank("AAA", "Morning")
+	if err != nil || len(bank.Presets) != 0 || !bank.Active {
+		t.Errorf("Expected updated active morning bank, got %+v, %v", bank, err)
+	}
+
+	if bank, _ := ds.GetPresetBank("BBB", "morning"); bank.Active {
+		t.Error("Expected the bank of another speaker to stay inactive")
+	}
+
+	if err := ds.SetActivePresetBank("AAA", "evening"); err != nil {
+		t.Fatalf("SetActivePresetBank failed: %v", err)
+	}
+
+	if bank, _ := ds.GetPresetBank("AAA", "morning"); bank.Active {
+		t.Error("Expected the morning bank to be inactive after activating the evening bank")
+	}
+
+	if err := ds.DeletePresetBank("AAA", "evening"); err != nil {
+		t.Fatalf("DeletePresetBank failed: %v", err)
+	}
+
+	if _, err := ds.GetPresetBank("AAA", "evening"); !errors.Is(err, ErrPresetBankNotFound) {
+		t.Errorf("Expected ErrPresetBankNotFound, got %v", err)
+	}
+
+	if err := ds.DeletePresetBank("AAA", "evening"); !errors.Is(err, ErrPresetBankNotFound) {
+		t.Errorf("Expected ErrPresetBankNotFound deleting twice, got %v", err)
+	}
+
+	if err := ds.SetActivePresetBank("AAA", "evening"); !errors.Is(err, ErrPresetBankNotFound) {
+		t.Errorf("Expected ErrPresetBankNotFound activating a deleted bank, got %v", err)
+	}
+
+	if err := ds.SavePresetBank(models.PresetBank{Name: "broken"}); err == nil {
+		t.Error("Expected invalid preset bank to be rejected")
+	}
+}
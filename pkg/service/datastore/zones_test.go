@@ -0,0 +1,58 @@
+package datastore
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+func TestZonePersistence(t *testing.T) {
+	ds := NewDataStore(t.TempDir())
+
+	zones, err := ds.ListZones()
+	if err != nil || len(zones) != 0 {
+		t.Fatalf("Expected no zones initially, got %v, %v", zones, err)
+	}
+
+	party := models.ZoneDefinition{Name: "party", Master: "Kitchen", Members: []string{"Living Room", "Office"}}
+	downstairs := models.ZoneDefinition{Name: "downstairs", Master: "Kitchen", Members: []string{"Living Room"}}
+
+	for _, zone := range []models.ZoneDefinition{party, downstairs} {
+		if err := ds.SaveZone(zone); err != nil {
+			t.Fatalf("SaveZone failed: %v", err)
+		}
+	}
+
+	zones, _ = ds.ListZones()
+	if len(zones) != 2 || zones[0].Name != "downstairs" {
+		t.Errorf("Expected 2 zones sorted by name, got %+v", zones)
+	}
+
+	// Saving under an existing name replaces the definition
+	party.Members = []string{"Office"}
+	if err := ds.SaveZone(party); err != nil {
+		t.Fatalf("SaveZone failed: %v", err)
+	}
+
+	zone, err := ds.GetZone("PARTY")
+	if err != nil || len(zone.Members) != 1 {
+		t.Errorf("Expected updated party zone, got %+v, %v", zone, err)
+	}
+
+	if err := ds.DeleteZone("downstairs"); err != nil {
+		t.Fatalf("DeleteZone failed: %v", err)
+	}
+
+	if _, err := ds.GetZone("downstairs"); !errors.Is(err, ErrZoneNotFound) {
+		t.Errorf("Expected ErrZoneNotFound, got %v", err)
+	}
+
+	if err := ds.DeleteZone("downstairs"); !errors.Is(err, ErrZoneNotFound) {
+		t.Errorf("Expected ErrZoneNotFound deleting twice, got %v", err)
+	}
+
+	if err := ds.SaveZone(models.ZoneDefinition{Name: "broken"}); err == nil {
+		t.Error("Expected invalid zone to be rejected")
+	}
+}
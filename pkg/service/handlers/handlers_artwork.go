@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gesellix/bose-soundtouch/pkg/service/artwork"
+	"github.com/go-chi/chi/v5"
+)
+
+// SetArtworkCache enables rewriting art URLs in /api/speakers responses to locally cached copies.
+func (s *Server) SetArtworkCache(cache *artwork.Cache) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.artworkCache = cache
+}
+
+// artworkURL returns the cached artwork URL for content, or the original URL if caching is disabled.
+func (s *Server) artworkURL(artURL, source, label string) string {
+	s.mu.RLock()
+	cache := s.artworkCache
+	s.mu.RUnlock()
+
+	if cache == nil {
+		return artURL
+	}
+
+	return cache.RewriteURL(artURL, source, label)
+}
+
+// artworkLabel picks the first non-empty name to show on placeholder artwork.
+func artworkLabel(names ...string) string {
+	for _, name := range names {
+		if name != "" {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// HandleArtwork serves cached or placeholder artwork by hash.
+func (s *Server) HandleArtwork(w http.ResponseWriter, r *http.Request) {
+	hash := chi.URLParam(r, "hash")
+
+	s.mu.RLock()
+	cache := s.artworkCache
+	s.mu.RUnlock()
+
+	if cache == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	data, contentType, fallback, err := cache.Load(hash)
+	if err != nil {
+		log.Printf("[Artwork] %v", err)
+		http.NotFound(w, r)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+
+	if fallback {
+		// The remote art may be back soon, so clients must not keep the placeholder
+		w.Header().Set("Cache-Control", "no-store")
+	} else {
+		w.Header().Set("Cache-Control", "public, max-age=86400")
+	}
+
+	_, _ = w.Write(data)
+}
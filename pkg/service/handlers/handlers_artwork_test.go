@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gesellix/bose-soundtouch/pkg/service/artwork"
+)
+
+func TestArtworkURL_DisabledKeepsOriginal(t *testing.T) {
+	srv := &Server{}
+
+	if got := srv.artworkURL("http://cdn.example.com/a.png", "TUNEIN", "Radio"); got != "http://cdn.example.com/a.png" {
+		t.Errorf("Expected original URL without cache, got %s", got)
+	}
+}
+
+func TestHandleArtwork_ServesPlaceholder(t *testing.T) {
+	r, server := setupRouter("http://localhost:8001", nil)
+	server.SetArtworkCache(artwork.NewCache(t.TempDir()))
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	artURL := server.artworkURL("", "AUX", artworkLabel("", "AUX IN"))
+
+	res, err := http.Get(ts.URL + artURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status OK, got %v", res.Status)
+	}
+
+	if ct := res.Header.Get("Content-Type"); ct != "image/svg+xml" {
+		t.Errorf("Expected SVG placeholder, got %s", ct)
+	}
+
+	body, _ := io.ReadAll(res.Body)
+	if !strings.Contains(string(body), ">AI<") {
+		t.Errorf("Expected initials in placeholder, got %s", body)
+	}
+
+	if cc := res.Header.Get("Cache-Control"); cc != "public, max-age=86400" {
+		t.Errorf("Expected placeholder for content without art to be cached, got %q", cc)
+	}
+
+	// A placeholder standing in for art that failed to load must not be cached
+	unreachable := server.artworkURL("http://127.0.0.1:1/cover.png", "TUNEIN", "Jazz Radio")
+
+	fallback, err := http.Get(ts.URL + unreachable)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = fallback.Body.Close()
+
+	if cc := fallback.Header.Get("Cache-Control"); cc != "no-store" {
+		t.Errorf("Expected fallback placeholder not to be cached, got %q", cc)
+	}
+
+	missing, err := http.Get(ts.URL + "/media/art/00000000000000000000000000000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = missing.Body.Close()
+
+	if missing.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown artwork, got %v", missing.Status)
+	}
+}
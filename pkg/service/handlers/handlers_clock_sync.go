@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/gesellix/bose-soundtouch/pkg/service/clocksync"
+	"github.com/go-chi/chi/v5"
+)
+
+// HandleAPIClock returns the latest clock check of every speaker.
+func (s *Server) HandleAPIClock(w http.ResponseWriter, _ *http.Request) {
+	syncer, ok := s.clockSync(w)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, syncer.Results())
+}
+
+// HandleAPIClockSync checks the clocks of all speakers, or of the one given by speaker (device ID or address),
+// and corrects those that drifted.
+func (s *Server) HandleAPIClockSync(w http.ResponseWriter, r *http.Request) {
+	syncer, ok := s.clockSync(w)
+	if !ok {
+		return
+	}
+
+	id := r.URL.Query().Get("speaker")
+	if id == "" {
+		results := syncer.Check()
+		if results == nil {
+			results = []models.ClockCheck{}
+		}
+
+		writeJSON(w, http.StatusOK, results)
+
+		return
+	}
+
+	speaker, found := syncer.Find(id)
+	if !found {
+		writeJSONError(w, http.StatusNotFound, "speaker not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, syncer.Sync(speaker))
+}
+
+// HandleAPIClockZones returns the time zones configured per speaker.
+func (s *Server) HandleAPIClockZones(w http.ResponseWriter, _ *http.Request) {
+	zones, err := s.ds.ClockZones()
+	if err != nil {
+		log.Printf("[ClockSync] Failed to load clock zones: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to load clock zones")
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, zones)
+}
+
+// HandleAPIClockZoneSet sets the IANA time zone of a speaker's clock, e.g. {"zone": "Europe/Berlin"}.
+func (s *Server) HandleAPIClockZoneSet(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Zone string `json:"zone"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if _, err := models.ParseClockZone(body.Zone); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.storeClockZone(w, chi.URLParam(r, "id"), body.Zone)
+}
+
+// HandleAPIClockZoneDelete returns a speaker's clock to the default time zone.
+func (s *Server) HandleAPIClockZoneDelete(w http.ResponseWriter, r *http.Request) {
+	s.storeClockZone(w, chi.URLParam(r, "id"), "")
+}
+
+func (s *Server) storeClockZone(w http.ResponseWriter, deviceID, zone string) {
+	if err := s.ds.SetClockZone(deviceID, zone); err != nil {
+		log.Printf("[ClockSync] Failed to store clock zone of %s: %v", deviceID, err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to store clock zone")
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"deviceId": deviceID, "zone": zone})
+}
+
+// clockSync returns the clock syncer or writes an error if the service runs without one
+func (s *Server) clockSync(w http.ResponseWriter) (*clocksync.Syncer, bool) {
+	s.mu.RLock()
+	syncer := s.clockSyncer
+	s.mu.RUnlock()
+
+	if syncer == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "clock sync is not available")
+		return nil, false
+	}
+
+	return syncer, true
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/gesellix/bose-soundtouch/pkg/service/clocksync"
+	"github.com/gesellix/bose-soundtouch/pkg/service/datastore"
+	"github.com/gesellix/bose-soundtouch/pkg/service/worker"
+)
+
+func TestHandleAPIClock(t *testing.T) {
+	ds := datastore.NewDataStore(t.TempDir())
+	r, server := setupRouter("http://localhost:8001", ds)
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	do := func(method, path, body string) *http.Response {
+		t.Helper()
+
+		req, _ := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Cleanup(func() { _ = res.Body.Close() })
+
+		return res
+	}
+
+	if res := do(http.MethodPost, "/api/clock/sync", ""); res.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 without a clock syncer, got %d", res.StatusCode)
+	}
+
+	server.SetClockSyncer(clocksync.New(ds, func() []worker.Speaker { return nil }, nil))
+
+	if res := do(http.MethodPost, "/api/clock/sync?speaker=AAA", ""); res.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown speaker, got %d", res.StatusCode)
+	}
+
+	var results []models.ClockCheck
+	if res := do(http.MethodPost, "/api/clock/sync", ""); res.StatusCode != http.StatusOK || json.NewDecoder(res.Body).Decode(&results) != nil || len(results) != 0 {
+		t.Errorf("Expected an empty result list, got %d %+v", res.StatusCode, results)
+	}
+
+	if res := do(http.MethodPut, "/api/clock/zones/AAA", `{"zone":"Mars/Olympus_Mons"}`); res.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid zone, got %d", res.StatusCode)
+	}
+
+	if res := do(http.MethodPut, "/api/clock/zones/AAA", `{"zone":"UTC"}`); res.StatusCode != http.StatusOK {
+		t.Errorf("Expected the zone to be stored, got %d", res.StatusCode)
+	}
+
+	var zones map[string]string
+	if res := do(http.MethodGet, "/api/clock/zones", ""); json.NewDecoder(res.Body).Decode(&zones) != nil || zones["AAA"] != "UTC" {
+		t.Errorf("Expected the stored zone, got %v", zones)
+	}
+
+	do(http.MethodDelete, "/api/clock/zones/AAA", "")
+
+	if zone, _ := ds.ClockZone("AAA"); zone != "" {
+		t.Errorf("Expected the zone to be removed, got %q", zone)
+	}
+}
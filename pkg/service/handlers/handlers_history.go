@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+)
+
+// HandleAPIHistory returns the listening history, newest first.
+// Query parameters: q (full-text search), speaker, source, period, from, to and limit.
+func (s *Server) HandleAPIHistory(w http.ResponseWriter, r *http.Request) {
+	entries, ok := s.queryHistory(w, r, 100)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// HandleAPIHistoryStats returns the number of plays with the top stations, artists and sources,
+// overall and per speaker. It accepts the parameters of HandleAPIHistory and top (default 10).
+func (s *Server) HandleAPIHistoryStats(w http.ResponseWriter, r *http.Request) {
+	top := 10
+
+	if value := r.URL.Query().Get("top"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			writeJSONError(w, http.StatusBadRequest, "invalid top")
+			return
+		}
+
+		top = n
+	}
+
+	entries, ok := s.queryHistory(w, r, 0)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.NewHistoryStats(entries, top))
+}
+
+// HandleAPIHistoryExport downloads the listening history as CSV or JSON (format=csv|json, default csv).
+// It accepts the parameters of HandleAPIHistory; without limit all entries are exported.
+func (s *Server) HandleAPIHistoryExport(w http.ResponseWriter, r *http.Request) {
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = "csv"
+	}
+
+	if format != "csv" && format != "json" {
+		writeJSONError(w, http.StatusBadRequest, "invalid format (expected csv or json)")
+		return
+	}
+
+	entries, ok := s.queryHistory(w, r, 0)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="soundtouch-history.%s"`, format))
+
+	if format == "json" {
+		writeJSON(w, http.StatusOK, entries)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	if err := models.WriteHistoryCSV(w, entries); err != nil {
+		log.Printf("[History] Failed to write CSV export: %v", err)
+	}
+}
+
+// queryHistory loads the history entries selected by the request; it writes the error response on failure
+func (s *Server) queryHistory(w http.ResponseWriter, r *http.Request, defaultLimit int) ([]models.HistoryEntry, bool) {
+	query, err := historyQuery(r, defaultLimit, time.Now())
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+
+	entries, err := s.ds.ListHistory(query)
+	if err != nil {
+		log.Printf("[History] Failed to load history: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to load history")
+
+		return nil, false
+	}
+
+	return entries, true
+}
+
+// historyQuery reads a history query from the request parameters
+func historyQuery(r *http.Request, defaultLimit int, now time.Time) (models.HistoryQuery, error) {
+	params := r.URL.Query()
+
+	query := models.HistoryQuery{
+		Text:    params.Get("q"),
+		Speaker: params.Get("speaker"),
+		Source:  params.Get("source"),
+		Limit:   defaultLimit,
+	}
+
+	from, err := models.ParseHistoryPeriod(params.Get("period"), now)
+	if err != nil {
+		return query, err
+	}
+
+	query.From = from
+
+	for name, target := range map[string]*time.Time{"from": &query.From, "to": &query.To} {
+		value := params.Get(name)
+		if value == "" {
+			continue
+		}
+
+		t, err := parseHistoryTime(value)
+		if err != nil {
+			return query, fmt.Errorf("invalid %s (expected RFC 3339 time or YYYY-MM-DD)", name)
+		}
+
+		*target = t
+	}
+
+	if value := params.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return query, fmt.Errorf("invalid limit")
+		}
+
+		query.Limit = limit
+	}
+
+	return query, nil
+}
+
+func parseHistoryTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	return time.ParseInLocation("2006-01-02", value, time.Local)
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/gesellix/bose-soundtouch/pkg/service/datastore"
+)
+
+func TestHandleAPIHistory(t *testing.T) {
+	ds := datastore.NewDataStore(t.TempDir())
+	ds.SetHistoryEnabled(true)
+
+	now := time.Now().UTC()
+
+	for _, entry := range []models.HistoryEntry{
+		{Time: now.Add(-3 * time.Hour), DeviceID: "AAA", Speaker: "Kitchen", Origin: models.HistoryOriginNowPlaying, Source: "TUNEIN", Location: "s1", StationName: "Radio Paradise"},
+		{Time: now.Add(-2 * time.Hour), DeviceID: "BBB", Speaker: "Office", Origin: models.HistoryOriginNowPlaying, Source: "SPOTIFY", Artist: "Band", Track: "Song"},
+		{Time: now.Add(-time.Hour), DeviceID: "AAA", Speaker: "Kitchen", Origin: models.HistoryOriginRecent, Source: "TUNEIN", Location: "s2", Type: "stationurl", Name: "Radio Paradise"},
+		{Time: now.AddDate(0, 0, -10), DeviceID: "AAA", Speaker: "Kitchen", Origin: models.HistoryOriginRecent, Source: "TUNEIN", Location: "s3", Name: "Old News"},
+	} {
+		if _, err := ds.RecordHistory(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, _ := setupRouter("http://localhost:8001", ds)
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	get := func(path string) *http.Response {
+		t.Helper()
+
+		res, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Cleanup(func() { _ = res.Body.Close() })
+
+		return res
+	}
+
+	var entries []models.HistoryEntry
+	if err := json.NewDecoder(get("/api/history?q=paradise&speaker=kitchen").Body).Decode(&entries); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 2 || entries[0].Location != "s2" {
+		t.Errorf("Expected 2 Radio Paradise plays newest first, got %+v", entries)
+	}
+
+	var stats models.HistoryStats
+	if err := json.NewDecoder(get("/api/history/stats?period=week&top=1").Body).Decode(&stats); err != nil {
+		t.Fatal(err)
+	}
+
+	if stats.Plays != 3 || len(stats.Stations) != 1 || stats.Stations[0].Name != "Radio Paradise" || stats.Stations[0].Plays != 2 || len(stats.Speakers) != 2 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	res := get("/api/history/export?source=tunein")
+	if ct := res.Header.Get("Content-Type"); ct != "text/csv; charset=utf-8" {
+		t.Errorf("Expected CSV content type, got %q", ct)
+	}
+
+	records, err := csv.NewReader(res.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 4 || records[0][0] != "time" || records[3][8] != "Old News" {
+		t.Errorf("Unexpected CSV export: %v", records)
+	}
+
+	for _, path := range []string{"/api/history?period=soon", "/api/history?limit=-1", "/api/history?from=yesterday", "/api/history/stats?top=x", "/api/history/export?format=xml"} {
+		if res := get(path); res.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s, got %v", path, res.Status)
+		}
+	}
+}
@@ -4,9 +4,9 @@ import (
 	"encoding/xml"
 	"io"
 	"log"
+	"net"
 	"net/http"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/gesellix/bose-soundtouch/pkg/models"
@@ -56,8 +56,7 @@ func (s *Server) HandleMargeAccountFull(w http.ResponseWriter, r *http.Request)
 	_, _ = w.Write(data)
 
 	if s.zeroconfPrimer != nil {
-		ip := strings.Split(r.RemoteAddr, ":")[0]
-		s.zeroconfPrimer.RegisterSpeaker(account, "", ip)
+		s.zeroconfPrimer.RegisterSpeaker(account, "", remoteIP(r))
 	}
 }
 
@@ -65,10 +64,26 @@ func (s *Server) HandleMargeAccountFull(w http.ResponseWriter, r *http.Request)
 func (s *Server) HandleMargePowerOn(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 
+	ip := remoteIP(r)
+
 	if s.zeroconfPrimer != nil {
-		ip := strings.Split(r.RemoteAddr, ":")[0]
 		go s.zeroconfPrimer.OnPowerOn("", "", ip)
 	}
+
+	// Speakers lose their time on power cuts, so their clock is checked once they are up again
+	if s.clockSyncer != nil {
+		go s.clockSyncer.OnPowerOn(ip)
+	}
+}
+
+// remoteIP returns the address of the peer of r without its port, also for IPv6 peers
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
 }
 
 // HandleMargeAccountProfile returns the account profile.
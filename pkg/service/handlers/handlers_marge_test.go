@@ -426,6 +426,23 @@ func TestMargePowerOn(t *testing.T) {
 	}
 }
 
+func TestRemoteIP(t *testing.T) {
+	tests := map[string]string{
+		"192.168.1.10:51234": "192.168.1.10",
+		"[fe80::1]:1234":     "fe80::1",
+		"192.168.1.10":       "192.168.1.10",
+	}
+
+	for remoteAddr, expected := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/marge/streaming/support/power_on", nil)
+		req.RemoteAddr = remoteAddr
+
+		if got := remoteIP(req); got != expected {
+			t.Errorf("remoteIP(%q) = %q, expected %q", remoteAddr, got, expected)
+		}
+	}
+}
+
 func TestMargeAdvancedFeatures(t *testing.T) {
 	tempDir, err := os.MkdirTemp("", "st-test-*")
 	if err != nil {
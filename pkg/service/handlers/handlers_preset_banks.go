@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/gesellix/bose-soundtouch/pkg/service/datastore"
+	"github.com/gesellix/bose-soundtouch/pkg/service/presetbank"
+	"github.com/gesellix/bose-soundtouch/pkg/service/worker"
+	"github.com/go-chi/chi/v5"
+)
+
+// HandleAPIPresetBanksList returns the preset banks of a speaker.
+func (s *Server) HandleAPIPresetBanksList(w http.ResponseWriter, r *http.Request) {
+	speaker, ok := s.presetBankSpeaker(w, chi.URLParam(r, "id"))
+	if !ok {
+		return
+	}
+
+	banks, err := s.ds.ListPresetBanks(speaker.DeviceID)
+	if err != nil {
+		log.Printf("[PresetBanks] Failed to list preset banks: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to list preset banks")
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, banks)
+}
+
+// HandleAPIPresetBankGet returns a single preset bank of a speaker.
+func (s *Server) HandleAPIPresetBankGet(w http.ResponseWriter, r *http.Request) {
+	speaker, ok := s.presetBankSpeaker(w, chi.URLParam(r, "id"))
+	if !ok {
+		return
+	}
+
+	bank, err := s.ds.GetPresetBank(speaker.DeviceID, chi.URLParam(r, "name"))
+	if err != nil {
+		writePresetBankStoreError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, bank)
+}
+
+// HandleAPIPresetBankCreate stores a new preset bank of a speaker; the name is taken from the body.
+func (s *Server) HandleAPIPresetBankCreate(w http.ResponseWriter, r *http.Request) {
+	var bank models.PresetBank
+	if err := json.NewDecoder(r.Body).Decode(&bank); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	speaker, ok := s.presetBankSpeaker(w, chi.URLParam(r, "id"))
+	if !ok {
+		return
+	}
+
+	if _, err := s.ds.GetPresetBank(speaker.DeviceID, bank.Name); err == nil {
+		writeJSONError(w, http.StatusConflict, "preset bank already exists")
+		return
+	}
+
+	bank.DeviceID = speaker.DeviceID
+
+	s.savePresetBank(w, http.StatusCreated, bank)
+}
+
+// HandleAPIPresetBankUpdate creates or replaces a preset bank of a speaker.
+func (s *Server) HandleAPIPresetBankUpdate(w http.ResponseWriter, r *http.Request) {
+	var bank models.PresetBank
+	if err := json.NewDecoder(r.Body).Decode(&bank); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	speaker, ok := s.presetBankSpeaker(w, chi.URLParam(r, "id"))
+	if !ok {
+		return
+	}
+
+	bank.Name = chi.URLParam(r, "name")
+	bank.DeviceID = speaker.DeviceID
+
+	s.savePresetBank(w, http.StatusOK, bank)
+}
+
+// HandleAPIPresetBankDelete removes a preset bank of a speaker.
+func (s *Server) HandleAPIPresetBankDelete(w http.ResponseWriter, r *http.Request) {
+	speaker, ok := s.presetBankSpeaker(w, chi.URLParam(r, "id"))
+	if !ok {
+		return
+	}
+
+	if err := s.ds.DeletePresetBank(speaker.DeviceID, chi.URLParam(r, "name")); err != nil {
+		writePresetBankStoreError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+}
+
+// HandleAPIPresetBankActivate writes a preset bank to the speaker and to the Marge presets of its account.
+func (s *Server) HandleAPIPresetBankActivate(w http.ResponseWriter, r *http.Request) {
+	speaker, ok := s.presetBankSpeaker(w, chi.URLParam(r, "id"))
+	if !ok {
+		return
+	}
+
+	bank, err := s.ds.GetPresetBank(speaker.DeviceID, chi.URLParam(r, "name"))
+	if err != nil {
+		writePresetBankStoreError(w, err)
+		return
+	}
+
+	activation, err := presetbank.Activate(s.ds, speaker, bank)
+	if err != nil {
+		log.Printf("[PresetBanks] Failed to activate %s on %s: %v", bank.Name, speaker.Host, err)
+		writeJSONError(w, http.StatusBadGateway, err.Error())
+
+		return
+	}
+
+	log.Printf("[PresetBanks] Activated %s on %s (%d changes)", bank.Name, speaker.Host, len(activation.Changes))
+
+	writeJSON(w, http.StatusOK, activation)
+}
+
+// presetBankSpeaker resolves the speaker of a route; the id is the IP address or device ID of a known speaker,
+// or the IP address of a speaker that is asked for its device ID.
+func (s *Server) presetBankSpeaker(w http.ResponseWriter, id string) (worker.Speaker, bool) {
+	devices, err := s.ds.ListAllDevices()
+	if err != nil {
+		log.Printf("[PresetBanks] Failed to list devices: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to list devices")
+
+		return worker.Speaker{}, false
+	}
+
+	for _, device := range devices {
+		if device.IPAddress == id || strings.EqualFold(device.DeviceID, id) {
+			return worker.Speaker{
+				DeviceID:  device.DeviceID,
+				Name:      device.Name,
+				Host:      device.IPAddress,
+				AccountID: device.AccountID,
+			}, true
+		}
+	}
+
+	data, err := s.proxySpeakerGET(id, "/info")
+	if err != nil {
+		log.Printf("[PresetBanks] info error for %s: %v", id, err)
+		writeJSONError(w, http.StatusBadGateway, "failed to reach speaker")
+
+		return worker.Speaker{}, false
+	}
+
+	var info xmlInfo
+	if err := xml.Unmarshal(data, &info); err != nil || info.DeviceID == "" {
+		writeJSONError(w, http.StatusNotFound, "speaker not found")
+		return worker.Speaker{}, false
+	}
+
+	return worker.Speaker{
+		DeviceID:  info.DeviceID,
+		Name:      info.Name,
+		Host:      id,
+		AccountID: info.MargeAccountUUID,
+	}, true
+}
+
+// savePresetBank validates and stores a preset bank and writes it back as response.
+func (s *Server) savePresetBank(w http.ResponseWriter, status int, bank models.PresetBank) {
+	if err := bank.Validate(); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := s.ds.SavePresetBank(bank); err != nil {
+		log.Printf("[PresetBanks] Failed to save preset bank %s: %v", bank.Name, err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to save preset bank")
+
+		return
+	}
+
+	saved, err := s.ds.GetPresetBank(bank.DeviceID, bank.Name)
+	if err != nil {
+		writePresetBankStoreError(w, err)
+		return
+	}
+
+	writeJSON(w, status, saved)
+}
+
+func writePresetBankStoreError(w http.ResponseWriter, err error) {
+	if errors.Is(err, datastore.ErrPresetBankNotFound) {
+		writeJSONError(w, http.StatusNotFound, "preset bank not found")
+		return
+	}
+
+	log.Printf("[PresetBanks] Datastore error: %v", err)
+	writeJSONError(w, http.StatusInternalServerError, "failed to read preset banks")
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/gesellix/bose-soundtouch/pkg/service/datastore"
+)
+
+func TestHandleAPIPresetBanks_CRUD(t *testing.T) {
+	ds := datastore.NewDataStore(t.TempDir())
+	if err := ds.SaveDeviceInfo("1234", "AAA", &models.ServiceDeviceInfo{DeviceID: "AAA", Name: "Living Room", IPAddress: "192.0.2.10"}); err != nil {
+		t.Fatal(err)
+	}
+
+	r, _ := setupRouter("http://localhost:8001", ds)
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	do := func(method, path, body string) *http.Response {
+		t.Helper()
+
+		req, _ := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Cleanup(func() { _ = res.Body.Close() })
+
+		return res
+	}
+
+	const banks = "/api/speakers/192.0.2.10/preset-banks"
+
+	res := do(http.MethodPost, banks, `{"name":"morning","presets":[{"slot":1,"source":"TUNEIN","location":"/v1/playback/station/s1","name":"News"}],"schedule":"07:00"}`)
+	if res.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201 on create, got %v", res.Status)
+	}
+
+	var created models.PresetBank
+	if err := json.NewDecoder(res.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+
+	if created.DeviceID != "AAA" {
+		t.Errorf("Expected the bank to belong to AAA, got %+v", created)
+	}
+
+	if res := do(http.MethodPost, banks, `{"name":"morning"}`); res.StatusCode != http.StatusConflict {
+		t.Errorf("Expected 409 for duplicate preset bank, got %v", res.Status)
+	}
+
+	if res := do(http.MethodPut, banks+"/kids", `{"presets":[{"slot":9}]}`); res.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid slot, got %v", res.Status)
+	}
+
+	if res := do(http.MethodPut, banks+"/kids", `{"presets":[],"schedule":"25:00"}`); res.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid schedule, got %v", res.Status)
+	}
+
+	if res := do(http.MethodPut, banks+"/kids", `{"presets":[{"slot":6,"source":"TUNEIN","name":"Stories"}]}`); res.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 on update, got %v", res.Status)
+	}
+
+	// The device ID works as well as the IP address
+	res = do(http.MethodGet, "/api/speakers/AAA/preset-banks", "")
+
+	var list []models.PresetBank
+	if err := json.NewDecoder(res.Body).Decode(&list); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(list) != 2 || list[0].Name != "kids" || list[1].Schedule != "07:00" {
+		t.Errorf("Unexpected preset banks: %+v", list)
+	}
+
+	if res := do(http.MethodDelete, banks+"/kids", ""); res.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 on delete, got %v", res.Status)
+	}
+
+	if res := do(http.MethodGet, banks+"/kids", ""); res.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 after delete, got %v", res.Status)
+	}
+
+	if res := do(http.MethodPost, banks+"/kids/activate", ""); res.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 activating unknown preset bank, got %v", res.Status)
+	}
+}
@@ -15,10 +15,36 @@ import (
 
 // HandleProxyRequest handles requests to the logging proxy.
 func (s *Server) HandleProxyRequest(w http.ResponseWriter, r *http.Request) {
+	target, ok := proxyTarget(w, r)
+	if !ok {
+		return
+	}
+
+	s.ServeProxy(target)(w, r)
+}
+
+// HandleSpeakerProxyRequest handles requests to the logging proxy that may change something,
+// so they are only forwarded to the API port of known speakers.
+func (s *Server) HandleSpeakerProxyRequest(w http.ResponseWriter, r *http.Request) {
+	target, ok := proxyTarget(w, r)
+	if !ok {
+		return
+	}
+
+	if target.Port() != "8090" || !s.isKnownSpeakerHost(target.Hostname()) {
+		http.Error(w, "Only the API of known speakers can be reached with "+r.Method, http.StatusForbidden)
+		return
+	}
+
+	s.ServeProxy(target)(w, r)
+}
+
+// proxyTarget returns the target URL taken from the path of a proxy request, or writes an error
+func proxyTarget(w http.ResponseWriter, r *http.Request) (*url.URL, bool) {
 	targetURLStr := strings.TrimPrefix(r.URL.Path, "/proxy/")
 	if targetURLStr == "" {
 		http.Error(w, "Target URL is required", http.StatusBadRequest)
-		return
+		return nil, false
 	}
 
 	// Reconstruct original URL (it might have lost its double slashes in the path)
@@ -34,10 +60,27 @@ func (s *Server) HandleProxyRequest(w http.ResponseWriter, r *http.Request) {
 	target, err := url.Parse(targetURLStr)
 	if err != nil {
 		http.Error(w, "Invalid target URL: "+err.Error(), http.StatusBadRequest)
-		return
+		return nil, false
 	}
 
-	s.ServeProxy(target)(w, r)
+	return target, true
+}
+
+// isKnownSpeakerHost reports whether host is the address of a speaker stored in the datastore
+func (s *Server) isKnownSpeakerHost(host string) bool {
+	devices, err := s.ds.ListAllDevices()
+	if err != nil {
+		log.Printf("[PROXY] Failed to list devices: %v", err)
+		return false
+	}
+
+	for _, device := range devices {
+		if device.IPAddress != "" && strings.EqualFold(device.IPAddress, host) {
+			return true
+		}
+	}
+
+	return false
 }
 
 // ServeProxy returns a handler that proxies to the given target.
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/gesellix/bose-soundtouch/pkg/models"
 	"github.com/gesellix/bose-soundtouch/pkg/service/datastore"
 	"github.com/gesellix/bose-soundtouch/pkg/service/proxy"
 )
@@ -94,3 +95,33 @@ func TestHandleProxyRequest_RequestBodyRecording(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleSpeakerProxyRequest_OnlyKnownSpeakers(t *testing.T) {
+	ds := datastore.NewDataStore(t.TempDir())
+	if err := ds.SaveDeviceInfo("1234", "AAA", &models.ServiceDeviceInfo{DeviceID: "AAA", Name: "Living Room", IPAddress: "127.0.0.1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	server := NewServer(ds, nil, "http://localhost:8000", false, false, false, false)
+
+	tests := []struct {
+		target    string
+		forbidden bool
+	}{
+		{"http://192.0.2.99:8090/volume", true},
+		{"http://127.0.0.1:8080/volume", true},
+		{"https://example.com/", true},
+		{"http://127.0.0.1:8090/volume", false},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/proxy/"+tt.target, strings.NewReader("<volume>20</volume>"))
+		w := httptest.NewRecorder()
+
+		server.HandleSpeakerProxyRequest(w, req)
+
+		if forbidden := w.Code == http.StatusForbidden; forbidden != tt.forbidden {
+			t.Errorf("%s: expected forbidden=%v, got status %d", tt.target, tt.forbidden, w.Code)
+		}
+	}
+}
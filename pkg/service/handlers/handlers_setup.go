@@ -220,6 +220,8 @@ func (s *Server) HandleUpdateSettings(w http.ResponseWriter, r *http.Request) {
 	}
 
 	s.discoveryEnabled = settings.DiscoveryEnabled
+	s.updateDeviceWatchLocked()
+
 	s.dnsEnabled = settings.DNSEnabled
 	s.dnsUpstream = settings.DNSUpstream
 	s.dnsBindAddr = settings.DNSBindAddr
@@ -14,7 +14,12 @@ import (
 )
 
 // proxySpeakerGET forwards a GET request to the speaker and returns the raw response body.
+// Reads covered by a live state mirror are answered locally.
 func (s *Server) proxySpeakerGET(ip, path string) ([]byte, error) {
+	if data, ok := s.mirroredSpeakerGET(ip, path); ok {
+		return data, nil
+	}
+
 	client := &http.Client{Timeout: 10 * time.Second}
 
 	resp, err := client.Get(fmt.Sprintf("http://%s:8090%s", ip, path))
@@ -201,6 +206,7 @@ type xmlNowPlaying struct {
 		Source        string `xml:"source,attr"`
 		Location      string `xml:"location,attr"`
 		SourceAccount string `xml:"sourceAccount,attr"`
+		Name          string `xml:"itemName"`
 	} `xml:"ContentItem"`
 }
 
@@ -229,7 +235,7 @@ func (s *Server) HandleAPISpeakerNowPlaying(w http.ResponseWriter, r *http.Reque
 		"track":      np.Track,
 		"artist":     np.Artist,
 		"album":      np.Album,
-		"art":        np.Art,
+		"art":        s.artworkURL(np.Art, np.Source, artworkLabel(np.Track, np.Album, np.ContentItem.Name)),
 		"playStatus": np.PlayStatus,
 		"contentItem": map[string]string{
 			"source":        np.ContentItem.Source,
@@ -329,7 +335,7 @@ func (s *Server) HandleAPISpeakerPresets(w http.ResponseWriter, r *http.Request)
 			Location:      p.ContentItem.Location,
 			SourceAccount: p.ContentItem.SourceAccount,
 			Name:          p.ContentItem.Name,
-			Image:         p.ContentItem.Image,
+			Image:         s.artworkURL(p.ContentItem.Image, p.ContentItem.Source, p.ContentItem.Name),
 		})
 	}
 
@@ -454,7 +460,7 @@ func (s *Server) HandleAPISpeakerRecents(w http.ResponseWriter, r *http.Request)
 			Location:      rc.ContentItem.Location,
 			SourceAccount: rc.ContentItem.SourceAccount,
 			Name:          rc.ContentItem.Name,
-			Image:         rc.ContentItem.Image,
+			Image:         s.artworkURL(rc.ContentItem.Image, rc.ContentItem.Source, rc.ContentItem.Name),
 		})
 	}
 
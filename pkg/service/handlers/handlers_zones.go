@@ -0,0 +1,355 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gesellix/bose-soundtouch/pkg/client"
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/gesellix/bose-soundtouch/pkg/service/datastore"
+	"github.com/go-chi/chi/v5"
+)
+
+// HandleAPIZonesList returns all named zone definitions, saved and configured.
+func (s *Server) HandleAPIZonesList(w http.ResponseWriter, _ *http.Request) {
+	zones, err := s.namedZones()
+	if err != nil {
+		log.Printf("[Zones] Failed to list zones: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to list zones")
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, zones)
+}
+
+// HandleAPIZoneGet returns a single named zone definition.
+func (s *Server) HandleAPIZoneGet(w http.ResponseWriter, r *http.Request) {
+	zone, err := s.namedZone(chi.URLParam(r, "name"))
+	if err != nil {
+		writeZoneStoreError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, zone)
+}
+
+// HandleAPIZoneCreate stores a new named zone definition; the name is taken from the body.
+func (s *Server) HandleAPIZoneCreate(w http.ResponseWriter, r *http.Request) {
+	var zone models.ZoneDefinition
+	if err := json.NewDecoder(r.Body).Decode(&zone); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if _, err := s.ds.GetZone(zone.Name); err == nil {
+		writeJSONError(w, http.StatusConflict, "zone already exists")
+		return
+	}
+
+	s.saveZone(w, http.StatusCreated, zone)
+}
+
+// HandleAPIZoneUpdate creates or replaces the named zone definition.
+func (s *Server) HandleAPIZoneUpdate(w http.ResponseWriter, r *http.Request) {
+	var zone models.ZoneDefinition
+	if err := json.NewDecoder(r.Body).Decode(&zone); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	zone.Name = chi.URLParam(r, "name")
+
+	s.saveZone(w, http.StatusOK, zone)
+}
+
+// HandleAPIZoneDelete removes the named zone definition. Zones configured in ZONES cannot be deleted.
+func (s *Server) HandleAPIZoneDelete(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	if err := s.ds.DeleteZone(name); err != nil {
+		if errors.Is(err, datastore.ErrZoneNotFound) && s.isConfiguredZone(name) {
+			writeJSONError(w, http.StatusConflict, "configured in ZONES, remove it from the configuration")
+			return
+		}
+
+		writeZoneStoreError(w, err)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+}
+
+// HandleAPIZoneApply reconciles the live speaker topology with the named zone definition
+// and reports the outcome for every member.
+func (s *Server) HandleAPIZoneApply(w http.ResponseWriter, r *http.Request) {
+	zone, err := s.namedZone(chi.URLParam(r, "name"))
+	if err != nil {
+		writeZoneStoreError(w, err)
+		return
+	}
+
+	manager, err := s.zoneManager()
+	if err != nil {
+		log.Printf("[Zones] Failed to list devices: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to list devices")
+
+		return
+	}
+
+	result, err := manager.ApplyDefinition(zone)
+	if err != nil {
+		log.Printf("[Zones] Failed to apply zone %s: %v", zone.Name, err)
+		writeJSONError(w, http.StatusBadGateway, err.Error())
+
+		return
+	}
+
+	type outcomeJSON struct {
+		Speaker   string `json:"speaker"`
+		Name      string `json:"name,omitempty"`
+		DeviceID  string `json:"deviceId,omitempty"`
+		Status    string `json:"status"`
+		PoweredOn bool   `json:"poweredOn,omitempty"`
+		LeftZone  string `json:"leftZone,omitempty"`
+		Error     string `json:"error,omitempty"`
+	}
+
+	outcomes := make([]outcomeJSON, 0, len(result.Outcomes))
+
+	for _, outcome := range result.Outcomes {
+		entry := outcomeJSON{
+			Speaker:   outcome.Ref,
+			Status:    string(outcome.Status),
+			PoweredOn: outcome.PoweredOn,
+			LeftZone:  outcome.LeftZone,
+		}
+
+		if outcome.Speaker != nil {
+			entry.Name = outcome.Speaker.Name
+			entry.DeviceID = outcome.Speaker.DeviceID
+		}
+
+		if outcome.Err != nil {
+			entry.Error = outcome.Err.Error()
+		}
+
+		outcomes = append(outcomes, entry)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"zone":     zone.Name,
+		"ok":       !result.Failed(),
+		"summary":  manager.ZoneSummary(result.Zone),
+		"outcomes": outcomes,
+	})
+}
+
+// HandleAPIZoneVolume returns the group volume of a zone. The id is a named zone or any speaker of the zone.
+func (s *Server) HandleAPIZoneVolume(w http.ResponseWriter, r *http.Request) {
+	group, ok := s.zoneGroupVolume(w, chi.URLParam(r, "name"))
+	if !ok {
+		return
+	}
+
+	level, err := group.Get()
+	if err != nil {
+		log.Printf("[Zones] Failed to get group volume: %v", err)
+		writeJSONError(w, http.StatusBadGateway, err.Error())
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, level)
+}
+
+// HandleAPIZoneSetVolume changes the group volume of a zone.
+// The body sets an absolute "level", a relative "delta" or "muted".
+func (s *Server) HandleAPIZoneSetVolume(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Level *int  `json:"level"`
+		Delta *int  `json:"delta"`
+		Muted *bool `json:"muted"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.Level == nil && req.Delta == nil && req.Muted == nil {
+		writeJSONError(w, http.StatusBadRequest, "one of level, delta or muted is required")
+		return
+	}
+
+	if req.Level != nil && (*req.Level < 0 || *req.Level > 100) {
+		writeJSONError(w, http.StatusBadRequest, "level must be between 0 and 100")
+		return
+	}
+
+	group, ok := s.zoneGroupVolume(w, chi.URLParam(r, "name"))
+	if !ok {
+		return
+	}
+
+	var (
+		level *client.GroupVolumeLevel
+		err   error
+	)
+
+	switch {
+	case req.Muted != nil:
+		level, err = group.SetMuted(*req.Muted)
+	case req.Level != nil:
+		level, err = group.Set(*req.Level)
+	default:
+		level, err = group.Change(*req.Delta)
+	}
+
+	if err != nil {
+		log.Printf("[Zones] Failed to set group volume: %v", err)
+		writeJSONError(w, http.StatusBadGateway, err.Error())
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, level)
+}
+
+// zoneGroupVolume resolves a named zone or speaker reference to the group volume of its zone.
+func (s *Server) zoneGroupVolume(w http.ResponseWriter, id string) (*client.GroupVolume, bool) {
+	ref := id
+	if zone, err := s.namedZone(id); err == nil {
+		ref = zone.Master
+	}
+
+	manager, err := s.zoneManager()
+	if err != nil {
+		log.Printf("[Zones] Failed to list devices: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to list devices")
+
+		return nil, false
+	}
+
+	if _, err := manager.Resolve(ref); err != nil {
+		writeJSONError(w, http.StatusNotFound, "zone or speaker not found")
+		return nil, false
+	}
+
+	group, err := manager.GroupVolume(ref)
+	if err != nil {
+		log.Printf("[Zones] Failed to get zone of %s: %v", ref, err)
+		writeJSONError(w, http.StatusBadGateway, err.Error())
+
+		return nil, false
+	}
+
+	return group, true
+}
+
+// saveZone validates and stores a zone definition and writes it back as response.
+func (s *Server) saveZone(w http.ResponseWriter, status int, zone models.ZoneDefinition) {
+	if err := zone.Validate(); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := s.ds.SaveZone(zone); err != nil {
+		log.Printf("[Zones] Failed to save zone %s: %v", zone.Name, err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to save zone")
+
+		return
+	}
+
+	writeJSON(w, status, zone)
+}
+
+// zoneManager creates a zone manager for all known speakers.
+func (s *Server) zoneManager() (*client.ZoneManager, error) {
+	devices, err := s.ds.ListAllDevices()
+	if err != nil {
+		return nil, err
+	}
+
+	manager := client.NewZoneManager(nil)
+
+	if registry := s.getDeviceRegistry(); registry != nil {
+		manager.SetResolver(registry)
+	}
+
+	for _, device := range devices {
+		if device.IPAddress == "" {
+			continue
+		}
+
+		manager.AddSpeaker(client.ZoneSpeaker{
+			Name:     device.Name,
+			DeviceID: device.DeviceID,
+			Host:     device.IPAddress,
+		})
+	}
+
+	return manager, nil
+}
+
+// namedZones merges saved zones with those configured in ZONES; saved zones win on name clashes.
+func (s *Server) namedZones() ([]models.ZoneDefinition, error) {
+	zones, err := s.ds.ListZones()
+	if err != nil {
+		return nil, err
+	}
+
+	saved := make(map[string]bool)
+	for _, zone := range zones {
+		saved[strings.ToLower(zone.Name)] = true
+	}
+
+	for _, zone := range s.getSpeakerConfig().Zones {
+		if !saved[strings.ToLower(zone.Name)] {
+			zones = append(zones, zone)
+		}
+	}
+
+	return zones, nil
+}
+
+// isConfiguredZone reports whether a zone of that name is defined in the ZONES configuration.
+func (s *Server) isConfiguredZone(name string) bool {
+	for _, zone := range s.getSpeakerConfig().Zones {
+		if strings.EqualFold(zone.Name, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// namedZone looks up a zone by name among saved and configured zones.
+func (s *Server) namedZone(name string) (*models.ZoneDefinition, error) {
+	zones, err := s.namedZones()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range zones {
+		if strings.EqualFold(zones[i].Name, name) {
+			return &zones[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", datastore.ErrZoneNotFound, name)
+}
+
+func writeZoneStoreError(w http.ResponseWriter, err error) {
+	if errors.Is(err, datastore.ErrZoneNotFound) {
+		writeJSONError(w, http.StatusNotFound, "zone not found")
+		return
+	}
+
+	log.Printf("[Zones] Datastore error: %v", err)
+	writeJSONError(w, http.StatusInternalServerError, "failed to read zones")
+}
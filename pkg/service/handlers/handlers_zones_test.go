@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gesellix/bose-soundtouch/pkg/config"
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/gesellix/bose-soundtouch/pkg/service/datastore"
+)
+
+func TestHandleAPIZones_CRUD(t *testing.T) {
+	ds := datastore.NewDataStore(t.TempDir())
+	r, _ := setupRouter("http://localhost:8001", ds)
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	do := func(method, path, body string) *http.Response {
+		t.Helper()
+
+		req, _ := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Cleanup(func() { _ = res.Body.Close() })
+
+		return res
+	}
+
+	res := do(http.MethodPost, "/api/zones/", `{"name":"downstairs","master":"Kitchen","members":["Living Room"]}`)
+	if res.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201 on create, got %v", res.Status)
+	}
+
+	if res := do(http.MethodPost, "/api/zones/", `{"name":"downstairs","master":"Kitchen","members":["Office"]}`); res.StatusCode != http.StatusConflict {
+		t.Errorf("Expected 409 for duplicate zone, got %v", res.Status)
+	}
+
+	if res := do(http.MethodPut, "/api/zones/party", `{"master":"Kitchen","members":[]}`); res.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for zone without members, got %v", res.Status)
+	}
+
+	if res := do(http.MethodPut, "/api/zones/party", `{"master":"Kitchen","members":["Living Room","Office"]}`); res.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 on update, got %v", res.Status)
+	}
+
+	res = do(http.MethodGet, "/api/zones/", "")
+
+	var zones []models.ZoneDefinition
+	if err := json.NewDecoder(res.Body).Decode(&zones); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(zones) != 2 || zones[1].Name != "party" || len(zones[1].Members) != 2 {
+		t.Errorf("Unexpected zones: %+v", zones)
+	}
+
+	if res := do(http.MethodDelete, "/api/zones/downstairs", ""); res.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 on delete, got %v", res.Status)
+	}
+
+	if res := do(http.MethodGet, "/api/zones/downstairs", ""); res.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 after delete, got %v", res.Status)
+	}
+
+	if res := do(http.MethodPost, "/api/zones/downstairs/apply", ""); res.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 applying unknown zone, got %v", res.Status)
+	}
+}
+
+func TestHandleAPIZones_Configured(t *testing.T) {
+	ds := datastore.NewDataStore(t.TempDir())
+	r, server := setupRouter("http://localhost:8001", ds)
+
+	cfg := config.DefaultConfig()
+	cfg.Zones = []models.ZoneDefinition{
+		{Name: "upstairs", Master: "Bedroom", Members: []string{"Office"}},
+		{Name: "party", Master: "Kitchen", Members: []string{"Office"}},
+	}
+	server.SetSpeakerConfig(cfg)
+
+	if err := ds.SaveZone(models.ZoneDefinition{Name: "Party", Master: "Kitchen", Members: []string{"Living Room"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "/api/zones/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = res.Body.Close() }()
+
+	var zones []models.ZoneDefinition
+	if err := json.NewDecoder(res.Body).Decode(&zones); err != nil {
+		t.Fatal(err)
+	}
+
+	// The saved zone wins over the configured one of the same name
+	if len(zones) != 2 || zones[0].Members[0] != "Living Room" || zones[1].Name != "upstairs" {
+		t.Errorf("Expected saved and configured zones, got %+v", zones)
+	}
+
+	get, err := http.Get(ts.URL + "/api/zones/upstairs")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = get.Body.Close()
+
+	if get.StatusCode != http.StatusOK {
+		t.Errorf("Expected configured zone to be found, got %v", get.Status)
+	}
+
+	apply, err := http.Post(ts.URL+"/api/zones/upstairs/apply", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = apply.Body.Close()
+
+	if apply.StatusCode == http.StatusNotFound {
+		t.Errorf("Expected configured zone to be applied, got %v", apply.Status)
+	}
+
+	// Configured zones cannot be deleted; a saved zone that overrides one can
+	for name, expected := range map[string]int{"upstairs": http.StatusConflict, "party": http.StatusOK} {
+		req, _ := http.NewRequest(http.MethodDelete, ts.URL+"/api/zones/"+name, nil)
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_ = res.Body.Close()
+
+		if res.StatusCode != expected {
+			t.Errorf("Expected status %d deleting %s, got %v", expected, name, res.Status)
+		}
+	}
+}
+
+func TestHandleAPIZoneVolume_Errors(t *testing.T) {
+	r, _ := setupRouter("http://localhost:8001", datastore.NewDataStore(t.TempDir()))
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "/api/zones/nowhere/volume")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = res.Body.Close()
+
+	if res.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown zone, got %v", res.Status)
+	}
+
+	for body, status := range map[string]int{
+		`{}`:              http.StatusBadRequest,
+		`{"level": 120}`:  http.StatusBadRequest,
+		`not json`:        http.StatusBadRequest,
+		`{"delta": -5}`:   http.StatusNotFound,
+		`{"muted": true}`: http.StatusNotFound,
+	} {
+		res, err := http.Post(ts.URL+"/api/zones/nowhere/volume", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_ = res.Body.Close()
+
+		if res.StatusCode != status {
+			t.Errorf("Body %s: expected %d, got %v", body, status, res.Status)
+		}
+	}
+}
@@ -17,6 +17,7 @@ func setupRouter(targetURL string, ds *datastore.DataStore) (*chi.Mux, *Server)
 	r.Get("/", server.HandleRoot)
 
 	// Setup media and web directories for tests
+	r.Get("/media/art/{hash}", server.HandleArtwork)
 	r.Get("/media/*", server.HandleMedia())
 	r.Get("/web/*", server.HandleWeb())
 
@@ -98,6 +99,40 @@ func setupRouter(targetURL string, ds *datastore.DataStore) (*chi.Mux, *Server)
 		r.Get("/ca.crt", server.HandleGetCACert)
 	})
 
+	r.Route("/api/speakers", func(r chi.Router) {
+		r.Get("/{id}/preset-banks", server.HandleAPIPresetBanksList)
+		r.Post("/{id}/preset-banks", server.HandleAPIPresetBankCreate)
+		r.Get("/{id}/preset-banks/{name}", server.HandleAPIPresetBankGet)
+		r.Put("/{id}/preset-banks/{name}", server.HandleAPIPresetBankUpdate)
+		r.Delete("/{id}/preset-banks/{name}", server.HandleAPIPresetBankDelete)
+		r.Post("/{id}/preset-banks/{name}/activate", server.HandleAPIPresetBankActivate)
+	})
+
+	r.Route("/api/history", func(r chi.Router) {
+		r.Get("/", server.HandleAPIHistory)
+		r.Get("/stats", server.HandleAPIHistoryStats)
+		r.Get("/export", server.HandleAPIHistoryExport)
+	})
+
+	r.Route("/api/clock", func(r chi.Router) {
+		r.Get("/", server.HandleAPIClock)
+		r.Post("/sync", server.HandleAPIClockSync)
+		r.Get("/zones", server.HandleAPIClockZones)
+		r.Put("/zones/{id}", server.HandleAPIClockZoneSet)
+		r.Delete("/zones/{id}", server.HandleAPIClockZoneDelete)
+	})
+
+	r.Route("/api/zones", func(r chi.Router) {
+		r.Get("/", server.HandleAPIZonesList)
+		r.Post("/", server.HandleAPIZoneCreate)
+		r.Get("/{name}", server.HandleAPIZoneGet)
+		r.Put("/{name}", server.HandleAPIZoneUpdate)
+		r.Delete("/{name}", server.HandleAPIZoneDelete)
+		r.Post("/{name}/apply", server.HandleAPIZoneApply)
+		r.Get("/{name}/volume", server.HandleAPIZoneVolume)
+		r.Post("/{name}/volume", server.HandleAPIZoneSetVolume)
+	})
+
 	r.NotFound(server.HandleNotFound)
 
 	return r, server
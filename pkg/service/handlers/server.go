@@ -5,11 +5,15 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/gesellix/bose-soundtouch/pkg/config"
 	"github.com/gesellix/bose-soundtouch/pkg/discovery"
 	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/gesellix/bose-soundtouch/pkg/service/artwork"
+	"github.com/gesellix/bose-soundtouch/pkg/service/clocksync"
 	"github.com/gesellix/bose-soundtouch/pkg/service/datastore"
 	"github.com/gesellix/bose-soundtouch/pkg/service/proxy"
 	"github.com/gesellix/bose-soundtouch/pkg/service/setup"
@@ -50,6 +54,15 @@ type Server struct {
 	baseURL              string
 	spotifyService       *spotify.SpotifyService
 	zeroconfPrimer       *spotify.ZeroConfPrimer
+	speakerMirrorEnabled bool
+	speakerMirrors       map[string]*mirrorEntry
+	artworkCache         *artwork.Cache
+	deviceWatch          *discovery.UnifiedDiscoveryService
+	deviceWatchCtx       context.Context
+	deviceWatchCancel    context.CancelFunc
+	deviceRegistry       *discovery.DeviceRegistry
+	clockSyncer          *clocksync.Syncer
+	speakerConfig        *config.Config
 }
 
 // NewServer creates a new SoundTouch service server.
@@ -86,6 +99,58 @@ func (s *Server) SetDiscoverySettings(interval time.Duration, enabled bool) {
 
 	s.discoveryInterval = interval
 	s.discoveryEnabled = enabled
+
+	s.updateDeviceWatchLocked()
+}
+
+// SetSpeakerConfig sets the configuration shared with the CLI, e.g. named zones from ZONES
+// and the discovery settings like SCAN_SUBNETS. A running device watch is restarted with it.
+func (s *Server) SetSpeakerConfig(cfg *config.Config) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.speakerConfig = cfg
+
+	if s.deviceWatchCancel != nil {
+		s.deviceWatchCancel()
+		s.deviceWatchCancel = nil
+		s.deviceWatch = nil
+	}
+
+	s.updateDeviceWatchLocked()
+}
+
+// getSpeakerConfig returns the shared configuration, or the defaults if none was set.
+func (s *Server) getSpeakerConfig() *config.Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.speakerConfigLocked()
+}
+
+// speakerConfigLocked is getSpeakerConfig for callers holding s.mu.
+func (s *Server) speakerConfigLocked() *config.Config {
+	if s.speakerConfig == nil {
+		return config.DefaultConfig()
+	}
+
+	return s.speakerConfig
+}
+
+// SetDeviceRegistry sets the registry that keeps device identities across IP address changes.
+func (s *Server) SetDeviceRegistry(registry *discovery.DeviceRegistry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deviceRegistry = registry
+}
+
+// getDeviceRegistry returns the device registry, if any.
+func (s *Server) getDeviceRegistry() *discovery.DeviceRegistry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.deviceRegistry
 }
 
 // SetDNSSettings sets the DNS discovery settings for the server.
@@ -276,6 +341,14 @@ func (s *Server) SetZeroConfPrimer(p *spotify.ZeroConfPrimer) {
 	s.zeroconfPrimer = p
 }
 
+// SetClockSyncer sets the syncer that keeps the speaker clocks in sync.
+func (s *Server) SetClockSyncer(syncer *clocksync.Syncer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.clockSyncer = syncer
+}
+
 // GetRecordEnabled returns whether recording is enabled.
 func (s *Server) GetRecordEnabled() bool {
 	s.mu.RLock()
@@ -319,7 +392,11 @@ func (s *Server) DiscoverDevices(ctx context.Context) {
 	discoveryCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	svc := discovery.NewService(10 * time.Second)
+	// Search on the configured interfaces, but keep the scan at its own timeout
+	cfg := *s.getSpeakerConfig()
+	cfg.DiscoveryTimeout = 10 * time.Second
+
+	svc := discovery.NewServiceWithConfig(&cfg)
 
 	devices, err := svc.DiscoverDevices(discoveryCtx)
 	if err != nil {
@@ -335,6 +412,76 @@ func (s *Server) DiscoverDevices(ctx context.Context) {
 	s.mergeOverlappingDevices()
 }
 
+// WatchDevices keeps the device list in sync with discovery watch events until ctx is done.
+// Speakers are picked up as soon as they announce themselves; the discovery interval
+// only paces the active probes in between. While discovery is disabled the watch is
+// stopped, so the service neither probes the network nor listens to announcements.
+func (s *Server) WatchDevices(ctx context.Context) {
+	s.mu.Lock()
+	s.deviceWatchCtx = ctx
+	s.updateDeviceWatchLocked()
+	s.mu.Unlock()
+
+	<-ctx.Done()
+
+	s.mu.Lock()
+	s.deviceWatchCtx = nil
+	s.updateDeviceWatchLocked()
+	s.mu.Unlock()
+}
+
+// updateDeviceWatchLocked starts or stops the device watch to match the discovery settings.
+// The caller must hold s.mu.
+func (s *Server) updateDeviceWatchLocked() {
+	enabled := s.discoveryEnabled && s.deviceWatchCtx != nil
+
+	if !enabled {
+		if s.deviceWatchCancel != nil {
+			s.deviceWatchCancel()
+			s.deviceWatchCancel = nil
+			s.deviceWatch = nil
+
+			log.Printf("Device discovery watch stopped")
+		}
+
+		return
+	}
+
+	if s.deviceWatch != nil {
+		s.deviceWatch.SetWatchInterval(s.discoveryInterval)
+		return
+	}
+
+	svc := discovery.NewUnifiedDiscoveryService(s.speakerConfigLocked())
+	svc.SetWatchInterval(s.discoveryInterval)
+	svc.SetRegistry(s.deviceRegistry)
+
+	ctx, cancel := context.WithCancel(s.deviceWatchCtx)
+	s.deviceWatch = svc
+	s.deviceWatchCancel = cancel
+
+	log.Printf("Device discovery watch started")
+
+	go s.runDeviceWatch(ctx, svc)
+}
+
+// runDeviceWatch applies the events of one device watch until it is stopped
+func (s *Server) runDeviceWatch(ctx context.Context, svc *discovery.UnifiedDiscoveryService) {
+	for event := range svc.Watch(ctx) {
+		switch event.Type {
+		case discovery.DeviceAppeared, discovery.DeviceChanged:
+			if event.Type == discovery.DeviceChanged {
+				log.Printf("Bose device %s changed: %s", event.Device.Name, strings.Join(event.Changes, ", "))
+			}
+
+			s.handleDiscoveredDevice(*event.Device)
+			s.mergeOverlappingDevices()
+		case discovery.DeviceDisappeared:
+			log.Printf("Bose device %s at %s disappeared", event.Device.Name, event.Device.Host)
+		}
+	}
+}
+
 func (s *Server) handleDiscoveredDevice(d models.DiscoveredDevice) {
 	log.Printf("Discovered Bose device: %s at %s (Serial: %s)", d.Name, d.Host, d.SerialNo)
 
@@ -495,5 +642,39 @@ func (s *Server) findExistingDeviceInfo(d models.DiscoveredDevice) *models.Servi
 		}
 	}
 
+	return s.findRegisteredDeviceInfo(d, allDevices)
+}
+
+// findRegisteredDeviceInfo matches a device that changed its IP address through the
+// address history of the device registry.
+func (s *Server) findRegisteredDeviceInfo(d models.DiscoveredDevice, allDevices []models.ServiceDeviceInfo) *models.ServiceDeviceInfo {
+	registry := s.getDeviceRegistry()
+	if registry == nil {
+		return nil
+	}
+
+	ref := d.Metadata["deviceID"]
+	if ref == "" {
+		ref = d.Host
+	}
+
+	entry, ok := registry.Lookup(ref)
+	if !ok {
+		return nil
+	}
+
+	for i := range allDevices {
+		known := allDevices[i]
+
+		if entry.SerialNo != "" && (known.DeviceID == entry.SerialNo || known.DeviceSerialNumber == entry.SerialNo) {
+			return &known
+		}
+
+		// An old address only counts if no other device took it over since
+		if owner, ok := registry.Lookup(known.IPAddress); ok && known.IPAddress != "" && owner.DeviceID == entry.DeviceID {
+			return &known
+		}
+	}
+
 	return nil
 }
@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/gesellix/bose-soundtouch/pkg/discovery"
 	"github.com/gesellix/bose-soundtouch/pkg/models"
 	"github.com/gesellix/bose-soundtouch/pkg/service/datastore"
 )
@@ -128,3 +130,39 @@ func TestFindExistingDeviceID(t *testing.T) {
 		t.Errorf("After merge, expected to find Serial ID for IP, got %s", foundID)
 	}
 }
+
+func TestFindExistingDeviceID_AfterIPChange(t *testing.T) {
+	ds := datastore.NewDataStore(t.TempDir())
+	registry, _ := discovery.OpenDeviceRegistry(filepath.Join(t.TempDir(), "devices.json"))
+	s := &Server{ds: ds, deviceRegistry: registry}
+
+	_ = ds.SaveDeviceInfo("default", "SERIAL123", &models.ServiceDeviceInfo{
+		DeviceID:           "SERIAL123",
+		DeviceSerialNumber: "SERIAL123",
+		IPAddress:          "192.168.1.10",
+		Name:               "Kitchen",
+		AccountID:          "default",
+	})
+
+	for _, host := range []string{"192.168.1.10", "192.168.1.23"} {
+		registry.Record(&models.DiscoveredDevice{
+			Name:     "Kitchen",
+			Host:     host,
+			Port:     8090,
+			Metadata: map[string]string{"deviceID": "AABBCC000001"},
+		})
+	}
+
+	// The device comes back on its new address without a serial number
+	foundID := s.findExistingDeviceID(models.DiscoveredDevice{
+		Host:     "192.168.1.23",
+		Metadata: map[string]string{"deviceID": "AABBCC000001"},
+	})
+	if foundID != "SERIAL123" {
+		t.Errorf("Expected the moved device to be matched through the registry, got %q", foundID)
+	}
+
+	if foundID := s.findExistingDeviceID(models.DiscoveredDevice{Host: "192.168.1.99"}); foundID != "" {
+		t.Errorf("Expected no match for an unknown device, got %q", foundID)
+	}
+}
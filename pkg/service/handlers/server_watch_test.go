@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/config"
+	"github.com/gesellix/bose-soundtouch/pkg/discovery"
+)
+
+func TestWatchDevices_FollowsDiscoverySetting(t *testing.T) {
+	server := NewServer(nil, nil, "http://localhost:8000", false, false, false, false)
+	server.SetDiscoverySettings(time.Hour, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		server.WatchDevices(ctx)
+		close(done)
+	}()
+
+	watching := func() bool {
+		server.mu.RLock()
+		defer server.mu.RUnlock()
+
+		return server.deviceWatch != nil
+	}
+
+	waitFor := func(want bool) {
+		t.Helper()
+
+		deadline := time.Now().Add(2 * time.Second)
+		for watching() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("Expected watching=%v", want)
+			}
+
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if watching() {
+		t.Fatal("Expected no watch while discovery is disabled")
+	}
+
+	server.SetDiscoverySettings(time.Hour, true)
+	waitFor(true)
+
+	server.SetDiscoverySettings(time.Hour, false)
+	waitFor(false)
+
+	server.SetDiscoverySettings(time.Hour, true)
+	waitFor(true)
+
+	cancel()
+	<-done
+
+	if watching() {
+		t.Error("Expected the watch to stop with the context")
+	}
+}
+
+func TestWatchDevices_RestartsWithSpeakerConfig(t *testing.T) {
+	server := NewServer(nil, nil, "http://localhost:8000", false, false, false, false)
+	server.SetDiscoverySettings(time.Hour, true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		server.WatchDevices(ctx)
+		close(done)
+	}()
+
+	current := func() *discovery.UnifiedDiscoveryService {
+		server.mu.RLock()
+		defer server.mu.RUnlock()
+
+		return server.deviceWatch
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for current() == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the watch to start")
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	first := current()
+
+	cfg := config.DefaultConfig()
+	cfg.ScanSubnets = []string{"192.0.2.0/30"}
+	server.SetSpeakerConfig(cfg)
+
+	if watch := current(); watch == nil || watch == first {
+		t.Error("Expected the watch to restart with the new configuration")
+	}
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"log"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/client"
+)
+
+// speakerMirrorReconnects bounds the reconnect attempts of a mirror; a mirror that stays
+// disconnected for longer than speakerMirrorGrace is replaced on the next access.
+const (
+	speakerMirrorReconnects = 12
+	speakerMirrorGrace      = time.Minute
+)
+
+// mirrorEntry is a speaker mirror with the time it was last seen live
+type mirrorEntry struct {
+	state    *client.DeviceState
+	started  bool
+	lastLive time.Time
+}
+
+// SetSpeakerMirrorEnabled enables answering /api/speakers reads from live device state mirrors.
+// Each speaker gets a mirror on first access, kept in sync over its WebSocket connection.
+func (s *Server) SetSpeakerMirrorEnabled(enabled bool) {
+	var stopped []*client.DeviceState
+
+	s.mu.Lock()
+	s.speakerMirrorEnabled = enabled
+
+	if !enabled {
+		for ip, entry := range s.speakerMirrors {
+			stopped = append(stopped, entry.state)
+
+			delete(s.speakerMirrors, ip)
+		}
+	}
+	s.mu.Unlock()
+
+	// Closing the connections may block, so it happens without holding the server lock
+	for _, state := range stopped {
+		_ = state.Stop()
+	}
+}
+
+// SpeakerState returns the live state mirror for the speaker at ip, so that workers can follow it
+// without connecting themselves. It returns nil while mirroring is disabled.
+func (s *Server) SpeakerState(ip string) *client.DeviceState {
+	return s.speakerMirror(ip)
+}
+
+// speakerMirror returns the state mirror for a speaker, starting one in the background if needed.
+// A mirror that stopped being live, e.g. after its reconnects gave up, is replaced.
+// It returns nil while mirroring is disabled.
+func (s *Server) speakerMirror(ip string) *client.DeviceState {
+	s.mu.Lock()
+
+	if !s.speakerMirrorEnabled {
+		s.mu.Unlock()
+		return nil
+	}
+
+	var stale *client.DeviceState
+
+	if entry, ok := s.speakerMirrors[ip]; ok {
+		switch {
+		case !entry.started:
+			s.mu.Unlock()
+			return entry.state
+		case entry.state.IsLive():
+			entry.lastLive = time.Now()
+			s.mu.Unlock()
+
+			return entry.state
+		case time.Since(entry.lastLive) < speakerMirrorGrace:
+			s.mu.Unlock()
+			return entry.state
+		}
+
+		log.Printf("[SpeakerMirror] Mirror for %s is no longer live, reconnecting", ip)
+
+		stale = entry.state
+		delete(s.speakerMirrors, ip)
+	}
+
+	if s.speakerMirrors == nil {
+		s.speakerMirrors = make(map[string]*mirrorEntry)
+	}
+
+	entry := &mirrorEntry{state: client.NewDeviceState(client.NewClientFromHost(ip))}
+	s.speakerMirrors[ip] = entry
+	s.mu.Unlock()
+
+	if stale != nil {
+		go func() { _ = stale.Stop() }()
+	}
+
+	go s.startSpeakerMirror(ip, entry)
+
+	return entry.state
+}
+
+// startSpeakerMirror connects a new mirror and drops it again if that fails or mirroring was disabled meanwhile
+func (s *Server) startSpeakerMirror(ip string, entry *mirrorEntry) {
+	config := client.DefaultWebSocketConfig()
+	config.MaxReconnectAttempts = speakerMirrorReconnects
+
+	if err := entry.state.Start(config); err != nil {
+		log.Printf("[SpeakerMirror] Failed to start mirror for %s: %v", ip, err)
+
+		s.mu.Lock()
+		if s.speakerMirrors[ip] == entry {
+			delete(s.speakerMirrors, ip)
+		}
+		s.mu.Unlock()
+
+		return
+	}
+
+	s.mu.Lock()
+	current := s.speakerMirrors[ip] == entry
+	if current {
+		entry.started = true
+		entry.lastLive = time.Now()
+	}
+	s.mu.Unlock()
+
+	if !current {
+		// Mirroring was disabled or the mirror replaced while the connection was being established
+		_ = entry.state.Stop()
+
+		return
+	}
+
+	log.Printf("[SpeakerMirror] Mirroring state of %s", ip)
+}
+
+// mirroredSpeakerGET answers a speaker GET from its mirror, if the mirror is live and covers the path.
+func (s *Server) mirroredSpeakerGET(ip, path string) ([]byte, bool) {
+	state := s.speakerMirror(ip)
+	if state == nil || !state.IsLive() {
+		return nil, false
+	}
+
+	var v interface{}
+
+	switch path {
+	case "/info":
+		if info := state.DeviceInfo(); info != nil {
+			v = info
+		}
+	case "/volume":
+		if volume := state.Volume(); volume != nil {
+			v = volume
+		}
+	case "/nowPlaying", "/now_playing":
+		if np := state.NowPlaying(); np != nil {
+			v = np
+		}
+	case "/presets":
+		if presets := state.Presets(); presets != nil {
+			v = presets
+		}
+	case "/recents":
+		if recents := state.Recents(); recents != nil {
+			v = recents
+		}
+	case "/getZone":
+		if zone := state.Zone(); zone != nil {
+			v = zone
+		}
+	}
+
+	if v == nil {
+		return nil, false
+	}
+
+	data, err := xml.Marshal(v)
+	if err != nil {
+		log.Printf("[SpeakerMirror] Failed to marshal %s for %s: %v", path, ip, err)
+		return nil, false
+	}
+
+	return data, true
+}
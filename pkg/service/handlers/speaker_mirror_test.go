@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/client"
+)
+
+func TestSpeakerMirror_Disabled(t *testing.T) {
+	srv := &Server{}
+
+	if state := srv.speakerMirror("192.0.2.1"); state != nil {
+		t.Fatal("expected no mirror while mirroring is disabled")
+	}
+
+	if _, ok := srv.mirroredSpeakerGET("192.0.2.1", "/volume"); ok {
+		t.Fatal("expected mirrored GET to fall through while disabled")
+	}
+}
+
+func TestSpeakerMirror_NotLiveFallsThrough(t *testing.T) {
+	srv := &Server{}
+	srv.SetSpeakerMirrorEnabled(true)
+
+	first := srv.speakerMirror("192.0.2.1")
+	if first == nil {
+		t.Fatal("expected a mirror once enabled")
+	}
+
+	// The address is unreachable, so the mirror never becomes live
+	if _, ok := srv.mirroredSpeakerGET("192.0.2.1", "/volume"); ok {
+		t.Fatal("expected mirrored GET to fall through while the mirror is not live")
+	}
+
+	srv.SetSpeakerMirrorEnabled(false)
+
+	srv.mu.RLock()
+	remaining := len(srv.speakerMirrors)
+	srv.mu.RUnlock()
+
+	if remaining != 0 {
+		t.Fatalf("expected mirrors to be dropped when disabled, got %d", remaining)
+	}
+}
+
+func TestSpeakerMirror_ReplacesDeadMirror(t *testing.T) {
+	srv := &Server{}
+	srv.SetSpeakerMirrorEnabled(true)
+
+	dead := client.NewDeviceState(client.NewClientFromHost("192.0.2.1"))
+
+	srv.mu.Lock()
+	srv.speakerMirrors = map[string]*mirrorEntry{
+		"192.0.2.1": {state: dead, started: true, lastLive: time.Now().Add(-2 * speakerMirrorGrace)},
+	}
+	srv.mu.Unlock()
+
+	if state := srv.speakerMirror("192.0.2.1"); state == nil || state == dead {
+		t.Fatal("expected a mirror that stopped being live to be replaced")
+	}
+
+	srv.SetSpeakerMirrorEnabled(false)
+}
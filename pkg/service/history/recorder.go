@@ -0,0 +1,264 @@
+// Package history records the long-term listening history of all known speakers.
+//
+// Recent items reported to Marge are recorded by the Marge handlers. The recorder adds what the
+// speakers report in nowPlayingUpdated events, so plays of sources without recents are kept as well,
+// and applies the retention policy.
+package history
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/client"
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/gesellix/bose-soundtouch/pkg/service/datastore"
+	"github.com/gesellix/bose-soundtouch/pkg/service/worker"
+)
+
+// StateSource returns the live state mirror of the speaker at host, or nil if there is none
+type StateSource func(host string) *client.DeviceState
+
+// watch follows the now playing updates of one speaker address, through its state mirror or an own connection
+type watch struct {
+	speaker worker.Speaker
+	state   *client.DeviceState
+	ws      *client.WebSocketClient
+}
+
+// Recorder records the now playing events of all speakers and prunes the history
+type Recorder struct {
+	mu         sync.Mutex
+	loop       worker.Loop
+	ds         *datastore.DataStore
+	speakers   worker.SpeakerSource
+	states     StateSource
+	retention  models.HistoryRetention
+	interval   time.Duration
+	pruneEvery time.Duration
+	lastPrune  time.Time
+	watches    map[string]*watch
+	now        func() time.Time
+}
+
+// NewRecorder creates a recorder for the speakers returned by source
+func NewRecorder(ds *datastore.DataStore, source worker.SpeakerSource) *Recorder {
+	return &Recorder{
+		ds:         ds,
+		speakers:   source,
+		interval:   time.Minute,
+		pruneEvery: time.Hour,
+		watches:    make(map[string]*watch),
+		now:        time.Now,
+	}
+}
+
+// SetStateSource makes the recorder follow the state mirrors of the speakers, so that it does not need
+// its own WebSocket connections. Speakers without a mirror are still connected to directly.
+func (r *Recorder) SetStateSource(source StateSource) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.states = source
+}
+
+// SetRetention sets how much history is kept
+func (r *Recorder) SetRetention(retention models.HistoryRetention) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.retention = retention
+}
+
+// Start watches the speakers in the background until Stop is called
+func (r *Recorder) Start() {
+	started := r.loop.Start(func(done <-chan struct{}) {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			r.refreshWatchers()
+			r.Prune()
+
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	})
+
+	if started {
+		log.Printf("[History] Recording listening history (max age=%v, max entries=%d)", r.retention.MaxAge, r.retention.MaxEntries)
+	}
+}
+
+// Stop ends the background work and closes all WebSocket connections
+func (r *Recorder) Stop() {
+	if !r.loop.Stop() {
+		return
+	}
+
+	r.mu.Lock()
+	watches := r.watches
+	r.watches = make(map[string]*watch)
+	r.mu.Unlock()
+
+	for _, w := range watches {
+		w.close()
+	}
+}
+
+// Record adds what a speaker plays to the history; it is called for every now playing event
+func (r *Recorder) Record(speaker worker.Speaker, np *models.NowPlaying) {
+	entry := models.NewNowPlayingHistoryEntry(speaker.DeviceID, speaker.Name, np, r.now())
+	if entry == nil {
+		return
+	}
+
+	if _, err := r.ds.RecordHistory(*entry); err != nil {
+		log.Printf("[History] Failed to record history for %s: %v", speaker.DeviceID, err)
+	}
+}
+
+// Prune applies the retention, at most once per prune interval
+func (r *Recorder) Prune() {
+	r.mu.Lock()
+	retention := r.retention
+	now := r.now()
+
+	due := now.Sub(r.lastPrune) >= r.pruneEvery
+	if due {
+		r.lastPrune = now
+	}
+	r.mu.Unlock()
+
+	if !due || retention.MaxAge <= 0 && retention.MaxEntries <= 0 {
+		return
+	}
+
+	removed, err := r.ds.PruneHistory(retention)
+	if err != nil {
+		log.Printf("[History] Failed to prune history: %v", err)
+		return
+	}
+
+	if removed > 0 {
+		log.Printf("[History] Pruned %d history entries", removed)
+	}
+}
+
+// refreshWatchers follows every listed speaker, through its state mirror if there is one.
+// Speakers that are no longer listed and connections that dropped are closed; the latter are opened again.
+func (r *Recorder) refreshWatchers() {
+	listed := make(map[string]worker.Speaker)
+
+	for _, speaker := range r.speakers() {
+		if speaker.DeviceID != "" && speaker.Host != "" {
+			listed[speakerAddress(speaker)] = speaker
+		}
+	}
+
+	r.mu.Lock()
+	states := r.states
+
+	var stale []*watch
+
+	for address, w := range r.watches {
+		if _, ok := listed[address]; !ok {
+			stale = append(stale, w)
+			delete(r.watches, address)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, w := range stale {
+		w.close()
+	}
+
+	for address, speaker := range listed {
+		var state *client.DeviceState
+		if states != nil {
+			state = states(speaker.Host)
+		}
+
+		r.mu.Lock()
+		w := r.watches[address]
+
+		current := w != nil && (state != nil && w.state == state || state == nil && w.ws != nil && w.ws.IsConnected())
+		if current {
+			w.speaker = speaker
+		}
+		r.mu.Unlock()
+
+		if !current {
+			r.follow(address, speaker, state)
+		}
+	}
+}
+
+// follow subscribes to the state mirror of a speaker, or connects to the speaker if it has none,
+// and replaces the previous watch of the address
+func (r *Recorder) follow(address string, speaker worker.Speaker, state *client.DeviceState) {
+	w := &watch{speaker: speaker, state: state}
+
+	if state != nil {
+		state.OnChange(func(field client.StateField, s *client.DeviceState) {
+			// The initial load and reloads after reconnects are not plays
+			if field == client.StateFieldNowPlaying && s.IsLive() {
+				r.recordWatched(address, w, s.NowPlaying())
+			}
+		})
+	} else {
+		c := client.NewClient(&client.Config{Host: speaker.Host, Port: speaker.Port, Timeout: 5 * time.Second})
+
+		w.ws = c.NewWebSocketClient(&client.WebSocketConfig{Logger: client.DiscardLogger{}})
+		w.ws.OnNowPlaying(func(event *models.NowPlayingUpdatedEvent) { r.recordWatched(address, w, &event.NowPlaying) })
+
+		if err := w.ws.Connect(); err != nil {
+			return
+		}
+	}
+
+	log.Printf("[History] Watching %s (%s)", speaker.DeviceID, address)
+
+	r.mu.Lock()
+	previous := r.watches[address]
+	r.watches[address] = w
+	r.mu.Unlock()
+
+	if previous != nil {
+		previous.close()
+	}
+}
+
+// recordWatched records a now playing update, unless its watch was replaced or closed meanwhile
+func (r *Recorder) recordWatched(address string, w *watch, np *models.NowPlaying) {
+	r.mu.Lock()
+	current := r.watches[address] == w
+	speaker := w.speaker
+	r.mu.Unlock()
+
+	if current && np != nil {
+		r.Record(speaker, np)
+	}
+}
+
+// close ends an own connection; handlers on a state mirror stay registered but are ignored from now on
+func (w *watch) close() {
+	if w.ws != nil {
+		_ = w.ws.Disconnect()
+	}
+}
+
+// speakerAddress returns host:port of a speaker
+func speakerAddress(speaker worker.Speaker) string {
+	port := speaker.Port
+	if port == 0 {
+		port = 8090
+	}
+
+	return net.JoinHostPort(speaker.Host, fmt.Sprint(port))
+}
@@ -0,0 +1,107 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/client"
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/gesellix/bose-soundtouch/pkg/service/datastore"
+	"github.com/gesellix/bose-soundtouch/pkg/service/worker"
+)
+
+func TestRecorder_RecordAndPrune(t *testing.T) {
+	ds := datastore.NewDataStore(t.TempDir())
+	ds.SetHistoryEnabled(true)
+
+	r := NewRecorder(ds, func() []worker.Speaker { return nil })
+	r.SetRetention(models.HistoryRetention{MaxAge: 24 * time.Hour})
+
+	clock := time.Now().UTC().Add(-48 * time.Hour)
+	r.now = func() time.Time { return clock }
+
+	kitchen := worker.Speaker{DeviceID: "AAA", Name: "Kitchen"}
+	playing := &models.NowPlaying{
+		Source:      "TUNEIN",
+		PlayStatus:  models.PlayStatusPlaying,
+		StationName: "Radio Paradise",
+		ContentItem: &models.ContentItem{Source: "TUNEIN", Location: "s1"},
+	}
+
+	r.Record(kitchen, playing)
+	r.Record(kitchen, &models.NowPlaying{Source: "STANDBY"})
+
+	clock = clock.Add(47 * time.Hour)
+	r.Record(kitchen, playing)
+
+	entries, _ := ds.ListHistory(models.HistoryQuery{})
+	if len(entries) != 2 || entries[0].Speaker != "Kitchen" || entries[0].Origin != models.HistoryOriginNowPlaying {
+		t.Fatalf("Expected 2 recorded plays, got %+v", entries)
+	}
+
+	r.Prune()
+
+	entries, _ = ds.ListHistory(models.HistoryQuery{})
+	if len(entries) != 1 || !entries[0].Time.Equal(clock) {
+		t.Errorf("Expected the play older than a day to be pruned, got %+v", entries)
+	}
+
+	// Pruning runs at most once per interval
+	clock = clock.Add(30 * time.Hour)
+	r.lastPrune = clock.Add(-time.Minute)
+	r.Prune()
+
+	if entries, _ := ds.ListHistory(models.HistoryQuery{}); len(entries) != 1 {
+		t.Errorf("Expected no pruning before the interval passed, got %+v", entries)
+	}
+}
+
+func TestRecorder_FollowsStateMirrors(t *testing.T) {
+	ds := datastore.NewDataStore(t.TempDir())
+	ds.SetHistoryEnabled(true)
+
+	kitchen := worker.Speaker{DeviceID: "AAA", Name: "Kitchen", Host: "192.168.1.10", Port: 8090}
+	speakers := []worker.Speaker{kitchen}
+
+	r := NewRecorder(ds, func() []worker.Speaker { return speakers })
+
+	mirrors := map[string]*client.DeviceState{"192.168.1.10": client.NewDeviceState(client.NewClientFromHost("192.168.1.10"))}
+	r.SetStateSource(func(host string) *client.DeviceState { return mirrors[host] })
+
+	r.refreshWatchers()
+
+	first := r.watches["192.168.1.10:8090"]
+	if first == nil || first.state != mirrors["192.168.1.10"] || first.ws != nil {
+		t.Fatalf("Expected the kitchen to be followed through its mirror, got %+v", r.watches)
+	}
+
+	// The same mirror is not subscribed to again; a renamed speaker is recorded under its new name
+	speakers[0].Name = "Cuisine"
+	r.refreshWatchers()
+
+	if r.watches["192.168.1.10:8090"] != first || first.speaker.Name != "Cuisine" {
+		t.Errorf("Expected the watch to be kept and updated, got %+v", r.watches)
+	}
+
+	// A replaced mirror is followed instead and updates of the old one are ignored
+	mirrors["192.168.1.10"] = client.NewDeviceState(client.NewClientFromHost("192.168.1.10"))
+	r.refreshWatchers()
+
+	if w := r.watches["192.168.1.10:8090"]; w == first || w.state != mirrors["192.168.1.10"] {
+		t.Errorf("Expected the new mirror to be followed, got %+v", w)
+	}
+
+	r.recordWatched("192.168.1.10:8090", first, &models.NowPlaying{Source: "TUNEIN", PlayStatus: models.PlayStatusPlaying, StationName: "Radio Paradise"})
+
+	if entries, _ := ds.ListHistory(models.HistoryQuery{}); len(entries) != 0 {
+		t.Errorf("Expected updates of a replaced watch to be ignored, got %+v", entries)
+	}
+
+	// Speakers that are no longer known are dropped
+	speakers = nil
+	r.refreshWatchers()
+
+	if len(r.watches) != 0 {
+		t.Errorf("Expected no watches, got %+v", r.watches)
+	}
+}
@@ -5,6 +5,7 @@ package marge
 import (
 	"encoding/xml"
 	"fmt"
+	"log"
 	"os"
 	"strconv"
 	"time"
@@ -413,9 +414,33 @@ func AddRecent(ds *datastore.DataStore, account, device string, sourceXML []byte
 		return nil, err
 	}
 
+	recordRecentHistory(ds, account, device, matchingSrc, newRecentElem.ContentItemType, newRecentElem.Location, newRecentElem.Name, utcTime)
+
 	return formatRecentResponse(recentObj, matchingSrc, createdOn, utcTime), nil
 }
 
+// recordRecentHistory adds a recent item to the listening history; failures do not fail the request
+func recordRecentHistory(ds *datastore.DataStore, account, device string, src *models.ConfiguredSource, contentItemType, location, name string, utcTime int64) {
+	entry := models.HistoryEntry{
+		Time:          time.Unix(utcTime, 0).UTC(),
+		DeviceID:      device,
+		Origin:        models.HistoryOriginRecent,
+		Source:        src.SourceKeyType,
+		SourceAccount: src.SourceKeyAccount,
+		Type:          contentItemType,
+		Location:      location,
+		Name:          name,
+	}
+
+	if info, err := ds.GetDeviceInfo(account, device); err == nil {
+		entry.Speaker = info.Name
+	}
+
+	if _, err := ds.RecordHistory(entry); err != nil {
+		log.Printf("[Marge] Failed to record history for device %s: %v", device, err)
+	}
+}
+
 func findMatchingSource(sources []models.ConfiguredSource, sourceID string) *models.ConfiguredSource {
 	for i := range sources {
 		if sources[i].ID == sourceID {
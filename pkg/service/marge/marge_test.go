@@ -140,3 +140,42 @@ func TestAddRecent_TimestampPreservation(t *testing.T) {
 		// Since we slept, it should be different.
 	}
 }
+
+func TestAddRecent_RecordsHistory(t *testing.T) {
+	ds := datastore.NewDataStore(t.TempDir())
+	ds.SetHistoryEnabled(true)
+
+	src := models.ConfiguredSource{ID: "101", SourceKeyType: "TUNEIN", SourceKeyAccount: "test-user"}
+	_ = ds.SaveConfiguredSources("test-acc", "test-dev", []models.ConfiguredSource{src})
+	_ = ds.SaveRecents("test-acc", "test-dev", []models.ServiceRecent{})
+
+	sourceXML := []byte(`<recent><name>News Radio</name><sourceid>101</sourceid><location>station-1</location>` +
+		`<contentItemType>stationurl</contentItemType><lastplayedat>2026-03-10T08:00:00Z</lastplayedat></recent>`)
+
+	if _, err := AddRecent(ds, "test-acc", "test-dev", sourceXML); err != nil {
+		t.Fatalf("AddRecent failed: %v", err)
+	}
+
+	entries, err := ds.ListHistory(models.HistoryQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 history entry, got %+v", entries)
+	}
+
+	want := models.HistoryEntry{
+		Time:          time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC),
+		DeviceID:      "test-dev",
+		Origin:        models.HistoryOriginRecent,
+		Source:        "TUNEIN",
+		SourceAccount: "test-user",
+		Type:          "stationurl",
+		Location:      "station-1",
+		Name:          "News Radio",
+	}
+	if entries[0] != want {
+		t.Errorf("Expected %+v, got %+v", want, entries[0])
+	}
+}
@@ -0,0 +1,179 @@
+// Package presetbank activates preset banks, named sets of up to six presets kept per speaker,
+// so that the six preset buttons can hold different stations in the morning, the evening or for the kids.
+//
+// Activating a bank writes its presets to the speaker and to the presets the Marge emulation serves
+// for the speaker's account. A scheduler switches banks at their configured time of day.
+package presetbank
+
+import (
+	"encoding/xml"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/client"
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/gesellix/bose-soundtouch/pkg/service/datastore"
+	"github.com/gesellix/bose-soundtouch/pkg/service/marge"
+	"github.com/gesellix/bose-soundtouch/pkg/service/worker"
+)
+
+// Activation is the outcome of activating a bank
+type Activation struct {
+	Bank     string   `json:"bank"`
+	DeviceID string   `json:"deviceId"`
+	Changes  []string `json:"changes"`
+	// Stored lists the slots written to the Marge presets of the account
+	Stored []int `json:"stored,omitempty"`
+	// StoreSkipped explains why the Marge presets were not updated, completely or for single slots
+	StoreSkipped []string `json:"storeSkipped,omitempty"`
+}
+
+// Activate writes the presets of a bank to the speaker, clearing the slots the bank leaves empty,
+// then updates the Marge presets of the speaker's account and marks the bank as active.
+func Activate(ds *datastore.DataStore, speaker worker.Speaker, bank *models.PresetBank) (*Activation, error) {
+	if err := bank.Validate(); err != nil {
+		return nil, err
+	}
+
+	port := speaker.Port
+	if port == 0 {
+		port = 8090
+	}
+
+	c := client.NewClient(&client.Config{Host: speaker.Host, Port: port, Timeout: 10 * time.Second})
+
+	plan, err := c.PlanPresets(bank.PresetSet(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to plan presets: %w", err)
+	}
+
+	if !strings.EqualFold(plan.Device.DeviceID, bank.DeviceID) {
+		return nil, fmt.Errorf("speaker at %s is %s, but preset bank %s belongs to %s", speaker.Host, plan.Device.DeviceID, bank.Name, bank.DeviceID)
+	}
+
+	activation := &Activation{Bank: bank.Name, DeviceID: bank.DeviceID, Changes: []string{}}
+	for _, change := range plan.Changes {
+		activation.Changes = append(activation.Changes, change.String())
+	}
+
+	if err := plan.Apply(); err != nil {
+		return activation, fmt.Errorf("failed to write presets: %w", err)
+	}
+
+	if speaker.AccountID == "" {
+		activation.StoreSkipped = append(activation.StoreSkipped, "speaker has no Marge account")
+	} else {
+		activation.Stored, activation.StoreSkipped = storePresets(ds, speaker.AccountID, bank)
+	}
+
+	if err := ds.SetActivePresetBank(bank.DeviceID, bank.Name); err != nil {
+		return activation, fmt.Errorf("failed to mark preset bank as active: %w", err)
+	}
+
+	return activation, nil
+}
+
+// margePreset is the preset body marge.UpdatePreset takes
+type margePreset struct {
+	XMLName         xml.Name `xml:"preset"`
+	Name            string   `xml:"name"`
+	SourceID        string   `xml:"sourceid"`
+	Location        string   `xml:"location"`
+	ContentItemType string   `xml:"contentItemType"`
+	ContainerArt    string   `xml:"containerArt"`
+}
+
+// storePresets writes the presets of a bank to the Marge presets of an account and clears the other slots.
+// Presets whose source is not configured in the account are skipped and their slots cleared as well.
+func storePresets(ds *datastore.DataStore, account string, bank *models.PresetBank) ([]int, []string) {
+	sources, err := ds.GetConfiguredSources(account, bank.DeviceID)
+	if err != nil {
+		return nil, []string{fmt.Sprintf("failed to read configured sources: %v", err)}
+	}
+
+	var (
+		stored  []int
+		skipped []string
+	)
+
+	set := bank.PresetSet()
+
+	for slot := 1; slot <= models.PresetSlots; slot++ {
+		preset := set.Preset(slot)
+		if preset == nil {
+			continue
+		}
+
+		source := configuredSource(sources, preset)
+		if source == nil {
+			skipped = append(skipped, fmt.Sprintf("preset %d: source %s is not configured in account %s", slot, preset.Source, account))
+			continue
+		}
+
+		body, err := xml.Marshal(margePreset{
+			Name:            preset.Name,
+			SourceID:        source.ID,
+			Location:        preset.Location,
+			ContentItemType: preset.Type,
+			ContainerArt:    preset.Artwork,
+		})
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("preset %d: %v", slot, err))
+			continue
+		}
+
+		if _, err := marge.UpdatePreset(ds, account, bank.DeviceID, slot, body); err != nil {
+			skipped = append(skipped, fmt.Sprintf("preset %d: %v", slot, err))
+			continue
+		}
+
+		stored = append(stored, slot)
+	}
+
+	if err := clearPresets(ds, account, bank.DeviceID, stored); err != nil {
+		skipped = append(skipped, fmt.Sprintf("failed to clear empty slots: %v", err))
+	}
+
+	return stored, skipped
+}
+
+// configuredSource finds the configured source of the account a preset plays from
+func configuredSource(sources []models.ConfiguredSource, preset *models.BackupPreset) *models.ConfiguredSource {
+	for i := range sources {
+		if sources[i].SourceKeyType == preset.Source && sources[i].SourceKeyAccount == preset.Account {
+			return &sources[i]
+		}
+	}
+
+	return nil
+}
+
+// clearPresets empties the stored presets of all but the given slots.
+// Stored presets are kept in slot order, so cleared slots stay in place unless they are the last ones.
+func clearPresets(ds *datastore.DataStore, account, device string, keep []int) error {
+	presets, err := ds.GetPresets(account, device)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+
+	for i := range presets {
+		if presets[i].ID != "" && !slices.Contains(keep, i+1) {
+			presets[i] = models.ServicePreset{}
+			changed = true
+		}
+	}
+
+	for len(presets) > 0 && presets[len(presets)-1].ID == "" {
+		presets = presets[:len(presets)-1]
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return ds.SavePresets(account, device, presets)
+}
@@ -0,0 +1,231 @@
+package presetbank
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/gesellix/bose-soundtouch/pkg/service/datastore"
+	"github.com/gesellix/bose-soundtouch/pkg/service/worker"
+)
+
+// fakeSpeaker keeps presets stored and removed through the API
+type fakeSpeaker struct {
+	mu      sync.Mutex
+	presets map[int]*models.ContentItem
+}
+
+func (f *fakeSpeaker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/info":
+		_, _ = w.Write([]byte(`<info deviceID="AAA"><name>Living Room</name><type>SoundTouch 20</type></info>`))
+	case "/sources":
+		_, _ = w.Write([]byte(`<sources deviceID="AAA"><sourceItem source="TUNEIN" status="READY" /><sourceItem source="LOCAL_INTERNET_RADIO" status="READY" /></sources>`))
+	case "/presets":
+		presets := models.Presets{}
+
+		for slot := 1; slot <= models.PresetSlots; slot++ {
+			if item, ok := f.presets[slot]; ok {
+				presets.Preset = append(presets.Preset, models.Preset{ID: slot, ContentItem: item})
+			}
+		}
+
+		data, _ := xml.Marshal(presets)
+		_, _ = w.Write(data)
+	case "/storePreset", "/removePreset":
+		var preset models.Preset
+		if err := xml.NewDecoder(r.Body).Decode(&preset); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if r.URL.Path == "/storePreset" {
+			f.presets[preset.ID] = preset.ContentItem
+		} else {
+			delete(f.presets, preset.ID)
+		}
+
+		_, _ = w.Write([]byte(`<status>ok</status>`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeSpeaker) slots() map[int]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	names := make(map[int]string)
+	for slot, item := range f.presets {
+		names[slot] = item.ItemName
+	}
+
+	return names
+}
+
+// newFakeSpeaker starts a speaker with a preset in slot 4
+func newFakeSpeaker(t *testing.T) (*fakeSpeaker, worker.Speaker) {
+	t.Helper()
+
+	f := &fakeSpeaker{presets: map[int]*models.ContentItem{
+		4: {Source: "TUNEIN", Type: "stationurl", Location: "/v1/playback/station/s4", IsPresetable: true, ItemName: "Radio 4"},
+	}}
+
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+
+	u, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(u.Port())
+
+	return f, worker.Speaker{DeviceID: "AAA", Name: "Living Room", Host: u.Hostname(), Port: port, AccountID: "1234"}
+}
+
+// newBankStore creates a datastore with a Marge account that has TuneIn configured and a preset in slot 3
+func newBankStore(t *testing.T) *datastore.DataStore {
+	t.Helper()
+
+	ds := datastore.NewDataStore(t.TempDir())
+
+	source := models.ConfiguredSource{ID: "20001", DisplayName: "TuneIn", SourceKeyType: "TUNEIN"}
+	if err := ds.SaveConfiguredSources("1234", "AAA", []models.ConfiguredSource{source}); err != nil {
+		t.Fatal(err)
+	}
+
+	stored := []models.ServicePreset{{}, {}, {ServiceContentItem: models.ServiceContentItem{ID: "3", Name: "Old", Source: "TUNEIN", Location: "/v1/playback/station/s3"}}}
+	if err := ds.SavePresets("1234", "AAA", stored); err != nil {
+		t.Fatal(err)
+	}
+
+	return ds
+}
+
+var morning = models.PresetBank{
+	Name:     "morning",
+	DeviceID: "AAA",
+	Presets: []models.BackupPreset{
+		{Slot: 1, Source: "TUNEIN", Type: "stationurl", Location: "/v1/playback/station/s1", IsPresetable: true, Name: "News"},
+		{Slot: 2, Source: "LOCAL_INTERNET_RADIO", Type: "stationurl", Location: "http://radio/stream", IsPresetable: true, Name: "Stream"},
+	},
+	Schedule: "07:00",
+}
+
+func TestActivate(t *testing.T) {
+	speaker, target := newFakeSpeaker(t)
+	ds := newBankStore(t)
+
+	bank := morning
+	if err := ds.SavePresetBank(bank); err != nil {
+		t.Fatal(err)
+	}
+
+	activation, err := Activate(ds, target, &bank)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := speaker.slots(); len(got) != 2 || got[1] != "News" || got[2] != "Stream" {
+		t.Errorf("Unexpected presets on the speaker: %v", got)
+	}
+
+	if len(activation.Changes) != 3 {
+		t.Errorf("Expected 3 changes, got %v", activation.Changes)
+	}
+
+	if len(activation.Stored) != 1 || activation.Stored[0] != 1 || len(activation.StoreSkipped) != 1 {
+		t.Errorf("Expected slot 1 stored and slot 2 skipped, got %v, %v", activation.Stored, activation.StoreSkipped)
+	}
+
+	stored, err := ds.GetPresets("1234", "AAA")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stored) != 1 || stored[0].ID != "1" || stored[0].Name != "News" {
+		t.Errorf("Expected only slot 1 in the Marge presets, got %+v", stored)
+	}
+
+	if saved, _ := ds.GetPresetBank("AAA", "morning"); !saved.Active {
+		t.Error("Expected the bank to be active")
+	}
+
+	// Banks of another speaker are refused
+	other := bank
+	other.DeviceID = "BBB"
+
+	if _, err := Activate(ds, target, &other); err == nil {
+		t.Error("Expected an error for the bank of another speaker")
+	}
+}
+
+func TestScheduler_Check(t *testing.T) {
+	speaker, target := newFakeSpeaker(t)
+	ds := newBankStore(t)
+
+	evening := models.PresetBank{Name: "evening", DeviceID: "AAA", Presets: []models.BackupPreset{
+		{Slot: 1, Source: "TUNEIN", Type: "stationurl", Location: "/v1/playback/station/s9", IsPresetable: true, Name: "Jazz"},
+	}, Schedule: "19:00"}
+
+	for _, bank := range []models.PresetBank{morning, evening} {
+		if err := ds.SavePresetBank(bank); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var events []models.DeviceEvent
+
+	speakers := []worker.Speaker{}
+	s := NewScheduler(ds, func() []worker.Speaker { return speakers }, func(_ string, event models.DeviceEvent) { events = append(events, event) })
+
+	clock := time.Date(2026, 3, 10, 6, 59, 0, 0, time.Local)
+	s.now = func() time.Time { return clock }
+
+	s.Check()
+
+	// The speaker is unknown when the morning bank is due; the activation stays pending
+	clock = clock.Add(time.Minute)
+	s.Check()
+
+	if got := speaker.slots(); got[1] != "" {
+		t.Errorf("Expected no activation for an unknown speaker, got %v", got)
+	}
+
+	speakers = []worker.Speaker{target}
+	clock = clock.Add(30 * time.Second)
+	s.Check()
+
+	if got := speaker.slots(); got[1] != "News" || got[4] != "" {
+		t.Errorf("Expected the morning bank on the speaker, got %v", got)
+	}
+
+	if len(events) != 1 || events[0].Type != EventType || events[0].Data["bank"] != "morning" {
+		t.Errorf("Expected one event for the morning bank, got %+v", events)
+	}
+
+	// Nothing is due until the evening
+	clock = clock.Add(time.Hour)
+	s.Check()
+
+	if len(events) != 1 {
+		t.Errorf("Expected no further activation, got %+v", events)
+	}
+
+	clock = time.Date(2026, 3, 10, 19, 0, 30, 0, time.Local)
+	s.Check()
+
+	if got := speaker.slots(); got[1] != "Jazz" || got[2] != "" {
+		t.Errorf("Expected the evening bank on the speaker, got %v", got)
+	}
+
+	if bank, _ := ds.GetPresetBank("AAA", "evening"); !bank.Active {
+		t.Error("Expected the evening bank to be active")
+	}
+}
@@ -0,0 +1,220 @@
+package presetbank
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/gesellix/bose-soundtouch/pkg/service/datastore"
+	"github.com/gesellix/bose-soundtouch/pkg/service/worker"
+)
+
+// EventType is the device event type recorded for scheduled activations
+const EventType = "preset-bank"
+
+// pendingActivation is a scheduled activation that did not succeed yet
+type pendingActivation struct {
+	bank string
+	due  time.Time
+	// failed is set after the first failed attempt; retries are not recorded again
+	failed bool
+}
+
+// Scheduler activates preset banks at their scheduled time of day.
+// Activations that fail, for example because the speaker is offline, are retried on every check
+// until they succeed, the next scheduled bank of the speaker takes over, or the retry window ends.
+type Scheduler struct {
+	mu       sync.Mutex
+	checkMu  sync.Mutex
+	loop     worker.Loop
+	ds       *datastore.DataStore
+	speakers worker.SpeakerSource
+	sink     worker.EventSink
+	interval time.Duration
+	retry    time.Duration
+	now      func() time.Time
+	last     time.Time
+	pending  map[string]pendingActivation
+}
+
+// NewScheduler creates a scheduler for the banks in ds and the speakers returned by source,
+// recording every scheduled activation in sink
+func NewScheduler(ds *datastore.DataStore, source worker.SpeakerSource, sink worker.EventSink) *Scheduler {
+	return &Scheduler{
+		ds:       ds,
+		speakers: source,
+		sink:     sink,
+		interval: 30 * time.Second,
+		retry:    time.Hour,
+		now:      time.Now,
+		pending:  make(map[string]pendingActivation),
+	}
+}
+
+// SetInterval sets how often schedules are checked
+func (s *Scheduler) SetInterval(interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.interval = interval
+}
+
+// Start checks schedules in the background until Stop is called.
+// Only times of day passing after the start activate banks.
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	interval := s.interval
+	s.mu.Unlock()
+
+	s.checkMu.Lock()
+	defer s.checkMu.Unlock()
+
+	started := s.loop.Start(func(done <-chan struct{}) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.Check()
+			case <-done:
+				return
+			}
+		}
+	})
+
+	if started {
+		s.last = s.now()
+
+		log.Printf("[PresetBanks] Scheduling preset banks (interval=%v)", interval)
+	}
+}
+
+// Stop ends the background checks
+func (s *Scheduler) Stop() {
+	s.loop.Stop()
+}
+
+// Check activates the banks whose time of day passed since the last check and retries failed activations
+func (s *Scheduler) Check() {
+	s.checkMu.Lock()
+	defer s.checkMu.Unlock()
+
+	now := s.now()
+	from := s.last
+	s.last = now
+
+	banks, err := s.ds.ListPresetBanks("")
+	if err != nil {
+		log.Printf("[PresetBanks] Failed to list preset banks: %v", err)
+		return
+	}
+
+	if !from.IsZero() {
+		s.schedule(banks, from, now)
+	}
+
+	if len(s.pending) == 0 {
+		return
+	}
+
+	speakers := make(map[string]worker.Speaker)
+
+	if s.speakers != nil {
+		for _, speaker := range s.speakers() {
+			speakers[strings.ToLower(speaker.DeviceID)] = speaker
+		}
+	}
+
+	for key, pending := range s.pending {
+		if now.Sub(pending.due) > s.retry {
+			log.Printf("[PresetBanks] Giving up activating preset bank %s on %s", pending.bank, key)
+			delete(s.pending, key)
+
+			continue
+		}
+
+		s.activate(key, pending, banks, speakers)
+	}
+}
+
+// schedule records the latest bank of every speaker whose time of day passed after from and up to now
+func (s *Scheduler) schedule(banks []models.PresetBank, from, now time.Time) {
+	for i := range banks {
+		due, ok := banks[i].Due(from, now)
+		if !ok {
+			continue
+		}
+
+		key := strings.ToLower(banks[i].DeviceID)
+		if current, ok := s.pending[key]; ok && !due.After(current.due) {
+			continue
+		}
+
+		s.pending[key] = pendingActivation{bank: banks[i].Name, due: due}
+	}
+}
+
+// activate activates a pending bank; the activation stays pending if the speaker cannot be reached
+func (s *Scheduler) activate(key string, pending pendingActivation, banks []models.PresetBank, speakers map[string]worker.Speaker) {
+	var bank *models.PresetBank
+
+	for i := range banks {
+		if strings.EqualFold(banks[i].DeviceID, key) && strings.EqualFold(banks[i].Name, pending.bank) {
+			bank = &banks[i]
+		}
+	}
+
+	if bank == nil {
+		// The bank was deleted since it was scheduled
+		delete(s.pending, key)
+		return
+	}
+
+	speaker, ok := speakers[key]
+	if !ok || speaker.Host == "" {
+		return
+	}
+
+	activation, err := Activate(s.ds, speaker, bank)
+	if err != nil {
+		if !pending.failed {
+			s.record(bank, pending, nil, err)
+		}
+
+		pending.failed = true
+		s.pending[key] = pending
+
+		return
+	}
+
+	delete(s.pending, key)
+	s.record(bank, pending, activation, nil)
+}
+
+// record logs a scheduled activation and stores it as device event
+func (s *Scheduler) record(bank *models.PresetBank, pending pendingActivation, activation *Activation, err error) {
+	data := map[string]interface{}{
+		"bank":     bank.Name,
+		"schedule": bank.Schedule,
+		"due":      pending.due.Format(time.RFC3339),
+	}
+
+	if err != nil {
+		data["error"] = err.Error()
+		log.Printf("[PresetBanks] Scheduled activation of %s on %s failed: %v", bank.Name, bank.DeviceID, err)
+	} else {
+		data["changes"] = activation.Changes
+		data["stored"] = activation.Stored
+
+		if len(activation.StoreSkipped) > 0 {
+			data["storeSkipped"] = activation.StoreSkipped
+		}
+
+		log.Printf("[PresetBanks] Activated %s on %s (%d changes)", bank.Name, bank.DeviceID, len(activation.Changes))
+	}
+
+	s.sink.Record(bank.DeviceID, EventType, data)
+}
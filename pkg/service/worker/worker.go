@@ -0,0 +1,116 @@
+// Package worker holds what the background workers of the service share: the speakers they work on,
+// the device events they record and the loop they run in.
+package worker
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/gesellix/bose-soundtouch/pkg/service/datastore"
+)
+
+// Speaker is a speaker known to the service
+type Speaker struct {
+	DeviceID string
+	Name     string
+	Host     string
+	Port     int
+	// AccountID is the Marge account of the speaker, if known
+	AccountID string
+}
+
+// SpeakerSource returns the speakers to work on; workers call it before every check
+type SpeakerSource func() []Speaker
+
+// EventSink receives the device events recorded by the workers
+type EventSink func(deviceID string, event models.DeviceEvent)
+
+// Record sends an event of the given type with data to the sink, if there is one
+func (sink EventSink) Record(deviceID, eventType string, data map[string]interface{}) {
+	if sink == nil {
+		return
+	}
+
+	now := time.Now()
+
+	sink(deviceID, models.DeviceEvent{
+		Type:     eventType,
+		Time:     now.Format(time.RFC3339),
+		MonoTime: now.UnixNano() / int64(time.Millisecond),
+		Data:     data,
+	})
+}
+
+// KnownSpeakers returns a source listing the devices stored in ds
+func KnownSpeakers(ds *datastore.DataStore) SpeakerSource {
+	return func() []Speaker {
+		devices, err := ds.ListAllDevices()
+		if err != nil {
+			log.Printf("[Worker] Failed to list devices: %v", err)
+			return nil
+		}
+
+		speakers := make([]Speaker, 0, len(devices))
+		for _, device := range devices {
+			speakers = append(speakers, Speaker{
+				DeviceID:  device.DeviceID,
+				Name:      device.Name,
+				Host:      device.IPAddress,
+				Port:      8090,
+				AccountID: device.AccountID,
+			})
+		}
+
+		return speakers
+	}
+}
+
+// Loop runs the background work of a worker between Start and Stop
+type Loop struct {
+	mu      sync.Mutex
+	done    chan struct{}
+	running bool
+}
+
+// Start runs fn in the background, unless the loop runs already. fn must return once done is closed.
+// It reports whether the loop was started.
+func (l *Loop) Start(fn func(done <-chan struct{})) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.running {
+		return false
+	}
+
+	l.running = true
+	l.done = make(chan struct{})
+
+	go fn(l.done)
+
+	return true
+}
+
+// Stop closes the done channel of the running loop and reports whether it was running
+func (l *Loop) Stop() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.running {
+		return false
+	}
+
+	l.running = false
+	close(l.done)
+
+	return true
+}
+
+// Running reports whether the loop runs
+func (l *Loop) Running() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.running
+}
@@ -0,0 +1,75 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/gesellix/bose-soundtouch/pkg/service/datastore"
+)
+
+func TestKnownSpeakers(t *testing.T) {
+	ds := datastore.NewDataStore(t.TempDir())
+
+	info := &models.ServiceDeviceInfo{DeviceID: "AAA", Name: "Kitchen", IPAddress: "192.168.1.10", AccountID: "1234"}
+	if err := ds.SaveDeviceInfo("1234", "AAA", info); err != nil {
+		t.Fatal(err)
+	}
+
+	speakers := KnownSpeakers(ds)()
+
+	expected := Speaker{DeviceID: "AAA", Name: "Kitchen", Host: "192.168.1.10", Port: 8090, AccountID: "1234"}
+	if len(speakers) != 1 || speakers[0] != expected {
+		t.Errorf("Unexpected speakers: %+v", speakers)
+	}
+}
+
+func TestEventSink_Record(t *testing.T) {
+	var nilSink EventSink
+	nilSink.Record("AAA", "test", map[string]interface{}{})
+
+	var got models.DeviceEvent
+
+	sink := EventSink(func(deviceID string, event models.DeviceEvent) {
+		if deviceID == "AAA" {
+			got = event
+		}
+	})
+	sink.Record("AAA", "test", map[string]interface{}{"action": "done"})
+
+	if got.Type != "test" || got.Data["action"] != "done" || got.MonoTime == 0 || got.Time == "" {
+		t.Errorf("Unexpected event: %+v", got)
+	}
+}
+
+func TestLoop(t *testing.T) {
+	var loop Loop
+
+	stopped := make(chan struct{})
+	run := func(done <-chan struct{}) {
+		<-done
+		close(stopped)
+	}
+
+	if !loop.Start(run) || !loop.Running() {
+		t.Fatal("Expected the loop to start")
+	}
+
+	if loop.Start(run) {
+		t.Error("Expected a running loop not to start again")
+	}
+
+	if !loop.Stop() || loop.Running() {
+		t.Fatal("Expected the loop to stop")
+	}
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the loop function to return")
+	}
+
+	if loop.Stop() {
+		t.Error("Expected a stopped loop not to stop again")
+	}
+}
@@ -0,0 +1,708 @@
+// Package zonesupervisor watches multiroom zones and heals them after the master or a member dropped out.
+//
+// When a zone master reboots or loses Wi-Fi, its members stay orphaned in SLAVE_SOURCE. The supervisor
+// remembers every healthy zone it sees and, once a zone breaks, re-adds members, promotes a new master
+// carrying the same content, or releases members back to standalone, depending on the policy.
+package zonesupervisor
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gesellix/bose-soundtouch/pkg/client"
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/gesellix/bose-soundtouch/pkg/service/worker"
+)
+
+// EventType is the device event type used for everything the supervisor detects or does
+const EventType = "zone-supervisor"
+
+// Policy decides how a broken zone is healed
+type Policy string
+
+const (
+	// PolicyRejoin waits for the master to return and re-adds the members
+	PolicyRejoin Policy = "rejoin"
+	// PolicyPromote makes a remaining member the new master, playing the same content
+	PolicyPromote Policy = "promote"
+	// PolicyRelease returns orphaned members to standalone
+	PolicyRelease Policy = "release"
+)
+
+// ParsePolicy parses a policy name
+func ParsePolicy(value string) (Policy, error) {
+	switch policy := Policy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case PolicyRejoin, PolicyPromote, PolicyRelease:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown zone supervisor policy %q (expected rejoin, promote or release)", value)
+	}
+}
+
+// trackedZone is the last healthy layout of a zone
+type trackedZone struct {
+	master  string
+	members map[string]bool
+	content *models.ContentItem
+}
+
+// observation is the state of a speaker as seen during a check
+type observation struct {
+	speaker   worker.Speaker
+	reachable bool
+	zone      *models.ZoneInfo
+	source    string
+	content   *models.ContentItem
+}
+
+// Supervisor watches zones across speakers and heals broken ones
+type Supervisor struct {
+	mu       sync.Mutex
+	checkMu  sync.Mutex
+	policy   Policy
+	grace    time.Duration
+	interval time.Duration
+	settle   time.Duration
+	events   bool
+	loop     worker.Loop
+	speakers worker.SpeakerSource
+	sink     worker.EventSink
+	clients  *client.ClientPool
+	watchers map[string]*client.WebSocketClient
+	zones    map[string]*trackedZone
+	dropped  map[string]time.Time
+	trigger  chan struct{}
+}
+
+// New creates a supervisor for the speakers returned by source, recording every detection and action in sink
+func New(source worker.SpeakerSource, sink worker.EventSink, policy Policy) *Supervisor {
+	return &Supervisor{
+		policy:   policy,
+		grace:    30 * time.Second,
+		interval: 30 * time.Second,
+		settle:   2 * time.Second,
+		speakers: source,
+		sink:     sink,
+		clients:  client.NewClientPool(5 * time.Second),
+		watchers: make(map[string]*client.WebSocketClient),
+		zones:    make(map[string]*trackedZone),
+		dropped:  make(map[string]time.Time),
+		trigger:  make(chan struct{}, 1),
+	}
+}
+
+// SetGracePeriod sets how long a master may be unreachable before members are promoted or released
+func (s *Supervisor) SetGracePeriod(grace time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.grace = grace
+}
+
+// SetInterval sets how often all speakers are checked
+func (s *Supervisor) SetInterval(interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.interval = interval
+}
+
+// SetEventsEnabled makes the supervisor watch zoneUpdated and connection events over WebSocket
+// in addition to the periodic checks
+func (s *Supervisor) SetEventsEnabled(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = enabled
+}
+
+// Start runs checks in the background until Stop is called
+func (s *Supervisor) Start() {
+	s.mu.Lock()
+	interval := s.interval
+	s.mu.Unlock()
+
+	started := s.loop.Start(func(done <-chan struct{}) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			s.refreshWatchers()
+			s.Check()
+
+			select {
+			case <-ticker.C:
+			case <-s.trigger:
+				// Zone changes arrive in bursts; let the speakers settle first
+				settle := time.NewTimer(s.settle)
+
+				select {
+				case <-settle.C:
+				case <-done:
+					settle.Stop()
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	})
+
+	if started {
+		log.Printf("[ZoneSupervisor] Watching zones (policy=%s, interval=%v)", s.policy, interval)
+	}
+}
+
+// Stop ends the background checks and closes all WebSocket connections
+func (s *Supervisor) Stop() {
+	if !s.loop.Stop() {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, ws := range s.watchers {
+		_ = ws.Disconnect()
+
+		delete(s.watchers, id)
+	}
+}
+
+// Trigger requests a check as soon as possible
+func (s *Supervisor) Trigger() {
+	select {
+	case s.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Check observes all speakers once, learns healthy zones and heals broken ones
+func (s *Supervisor) Check() {
+	s.checkMu.Lock()
+	defer s.checkMu.Unlock()
+
+	observations := s.observe()
+	s.learn(observations)
+	s.heal(observations)
+	s.forgetRecovered(observations)
+}
+
+// observe reads zone and playback state of every speaker
+func (s *Supervisor) observe() map[string]*observation {
+	observations := make(map[string]*observation)
+
+	for _, speaker := range s.speakers() {
+		if speaker.DeviceID == "" || speaker.Host == "" {
+			continue
+		}
+
+		id := normalize(speaker.DeviceID)
+		o := &observation{speaker: speaker}
+		observations[id] = o
+
+		c := s.client(speaker)
+
+		zone, err := c.GetZone()
+		if err != nil {
+			s.markDropped(id, observations)
+			continue
+		}
+
+		o.reachable = true
+		o.zone = zone
+
+		if nowPlaying, err := c.GetNowPlaying(); err == nil {
+			o.source = nowPlaying.Source
+			o.content = nowPlaying.ContentItem
+		}
+	}
+
+	return observations
+}
+
+// learn remembers the layout of every zone led by a reachable master.
+// Members that dropped out are kept; members that left while reachable are forgotten.
+func (s *Supervisor) learn(observations map[string]*observation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, o := range observations {
+		if !o.reachable || normalize(o.zone.Master) != id {
+			continue
+		}
+
+		members := zoneMembers(o.zone)
+		if len(members) == 0 {
+			continue
+		}
+
+		tracked, ok := s.zones[id]
+		if !ok {
+			tracked = &trackedZone{master: id}
+			s.zones[id] = tracked
+
+			log.Printf("[ZoneSupervisor] Tracking zone of %s with %d member(s)", o.speaker.DeviceID, len(members))
+		}
+
+		for member := range tracked.members {
+			if members[member] {
+				continue
+			}
+
+			if _, dropped := s.dropped[member]; dropped || !isReachable(observations[member]) || isOrphanOf(observations[member], id) {
+				members[member] = true
+			}
+		}
+
+		tracked.members = members
+
+		if isPlaying(o) {
+			tracked.content = o.content
+		}
+	}
+}
+
+// heal checks every tracked zone against the observed state and repairs it according to the policy
+func (s *Supervisor) heal(observations map[string]*observation) {
+	s.mu.Lock()
+	zones := make([]*trackedZone, 0, len(s.zones))
+
+	for _, tracked := range s.zones {
+		zones = append(zones, tracked)
+	}
+	s.mu.Unlock()
+
+	for _, tracked := range zones {
+		master := observations[tracked.master]
+
+		switch {
+		case master == nil:
+			s.forget(tracked.master)
+		case !master.reachable:
+			s.healMissingMaster(tracked, observations)
+		case master.zone.Master != "" && normalize(master.zone.Master) != tracked.master:
+			// The master joined another zone; that was a deliberate change
+			s.forget(tracked.master)
+		default:
+			s.healMembers(tracked, master, observations)
+		}
+	}
+}
+
+// healMissingMaster promotes or releases the orphaned members once the grace period is over
+func (s *Supervisor) healMissingMaster(tracked *trackedZone, observations map[string]*observation) {
+	s.mu.Lock()
+	since := s.dropped[tracked.master]
+	grace := s.grace
+	s.mu.Unlock()
+
+	if s.policy == PolicyRejoin || time.Since(since) < grace {
+		return
+	}
+
+	var orphans []*observation
+
+	for _, id := range sortedIDs(tracked.members) {
+		if o := observations[id]; isReachable(o) && isOrphanOf(o, tracked.master) {
+			orphans = append(orphans, o)
+		}
+	}
+
+	if len(orphans) == 0 {
+		return
+	}
+
+	if s.policy == PolicyPromote && tracked.content != nil {
+		s.promote(tracked, orphans)
+		return
+	}
+
+	if s.policy == PolicyPromote {
+		s.record(orphans[0].speaker.DeviceID, "promote-skipped", map[string]interface{}{
+			"master": tracked.master,
+			"reason": "content of the lost master is unknown, releasing members instead",
+		}, nil)
+	}
+
+	for _, orphan := range orphans {
+		s.release(orphan, tracked.master)
+	}
+
+	s.forget(tracked.master)
+}
+
+// healMembers brings members back that dropped out of a zone whose master is reachable
+func (s *Supervisor) healMembers(tracked *trackedZone, master *observation, observations map[string]*observation) {
+	s.mu.Lock()
+	_, masterDropped := s.dropped[tracked.master]
+	s.mu.Unlock()
+
+	var broken, left []*observation
+
+	for _, id := range sortedIDs(tracked.members) {
+		o := observations[id]
+		if !isReachable(o) {
+			continue
+		}
+
+		if !master.zone.IsStandalone() && master.zone.IsInZone(o.speaker.DeviceID) {
+			s.clearDropped(o)
+			continue
+		}
+
+		s.mu.Lock()
+		_, memberDropped := s.dropped[id]
+		s.mu.Unlock()
+
+		if masterDropped || memberDropped || isOrphanOf(o, tracked.master) {
+			broken = append(broken, o)
+		} else {
+			left = append(left, o)
+		}
+	}
+
+	s.mu.Lock()
+	for _, o := range left {
+		delete(tracked.members, normalize(o.speaker.DeviceID))
+	}
+
+	remaining := len(tracked.members)
+	s.mu.Unlock()
+
+	if len(broken) == 0 {
+		s.clearDropped(master)
+
+		if remaining == 0 {
+			s.forget(tracked.master)
+		}
+
+		return
+	}
+
+	if s.policy == PolicyRelease {
+		for _, o := range broken {
+			if isOrphanOf(o, tracked.master) {
+				s.release(o, tracked.master)
+			}
+
+			s.mu.Lock()
+			delete(tracked.members, normalize(o.speaker.DeviceID))
+			s.mu.Unlock()
+		}
+
+		s.clearDropped(master)
+
+		return
+	}
+
+	s.rejoin(tracked, master, broken, observations)
+}
+
+// rejoin restores the zone on its master, re-creating it if the master came back standalone
+func (s *Supervisor) rejoin(tracked *trackedZone, master *observation, broken []*observation, observations map[string]*observation) {
+	c := s.client(master.speaker)
+	masterID := master.speaker.DeviceID
+
+	if !master.zone.IsStandalone() {
+		for _, o := range broken {
+			err := c.AddZoneSlave(masterID, o.speaker.DeviceID, client.ZoneMemberIP(o.speaker.Host))
+			s.record(o.speaker.DeviceID, "member-readded", map[string]interface{}{"master": masterID}, err)
+
+			if err == nil {
+				s.clearDropped(o)
+			}
+		}
+
+		return
+	}
+
+	// The master came back without its zone, typically after a reboot
+	if master.source == "STANDBY" && tracked.content != nil {
+		if err := c.SelectContentItem(tracked.content); err != nil {
+			s.record(masterID, "content-restore-failed", map[string]interface{}{"content": tracked.content.ItemName}, err)
+		}
+	}
+
+	request := models.NewZoneRequest(masterID)
+
+	var members []string
+
+	for _, id := range sortedIDs(tracked.members) {
+		if o := observations[id]; isReachable(o) {
+			request.AddMember(o.speaker.DeviceID, client.ZoneMemberIP(o.speaker.Host))
+			members = append(members, o.speaker.DeviceID)
+		}
+	}
+
+	err := c.SetZone(request)
+	s.record(masterID, "zone-restored", map[string]interface{}{"members": members}, err)
+
+	if err == nil {
+		s.clearDropped(master)
+
+		for _, o := range broken {
+			s.clearDropped(o)
+		}
+	}
+}
+
+// promote makes the first orphan the new master, plays the old content on it and adds the other orphans
+func (s *Supervisor) promote(tracked *trackedZone, orphans []*observation) {
+	newMaster := orphans[0]
+	id := newMaster.speaker.DeviceID
+	c := s.client(newMaster.speaker)
+
+	request := models.NewZoneRequest(id)
+	members := make(map[string]bool)
+
+	var memberIDs []string
+
+	for _, o := range orphans[1:] {
+		request.AddMember(o.speaker.DeviceID, client.ZoneMemberIP(o.speaker.Host))
+		members[normalize(o.speaker.DeviceID)] = true
+		memberIDs = append(memberIDs, o.speaker.DeviceID)
+	}
+
+	data := map[string]interface{}{
+		"previousMaster": tracked.master,
+		"members":        memberIDs,
+		"content":        tracked.content.ItemName,
+	}
+
+	err := c.SetZone(models.NewZoneRequest(id))
+	if err == nil {
+		err = c.SelectContentItem(tracked.content)
+	}
+
+	if err == nil && len(memberIDs) > 0 {
+		err = c.SetZone(request)
+	}
+
+	s.record(id, "master-promoted", data, err)
+
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	delete(s.zones, tracked.master)
+
+	if len(members) > 0 {
+		s.zones[normalize(id)] = &trackedZone{master: normalize(id), members: members, content: tracked.content}
+	}
+	s.mu.Unlock()
+
+	for _, o := range orphans {
+		s.clearDropped(o)
+	}
+}
+
+// release makes an orphaned member standalone again
+func (s *Supervisor) release(o *observation, master string) {
+	err := s.client(o.speaker).SetZone(models.NewZoneRequest(o.speaker.DeviceID))
+	s.record(o.speaker.DeviceID, "member-released", map[string]interface{}{"master": master}, err)
+
+	if err == nil {
+		s.clearDropped(o)
+	}
+}
+
+// markDropped remembers when a speaker became unreachable and records it once
+func (s *Supervisor) markDropped(id string, observations map[string]*observation) {
+	s.mu.Lock()
+	_, known := s.dropped[id]
+
+	if !known {
+		s.dropped[id] = time.Now()
+	}
+
+	_, isMaster := s.zones[id]
+	s.mu.Unlock()
+
+	if known {
+		return
+	}
+
+	action := "speaker-unreachable"
+	if isMaster {
+		action = "master-unreachable"
+	}
+
+	deviceID := id
+	if o := observations[id]; o != nil {
+		deviceID = o.speaker.DeviceID
+	}
+
+	s.record(deviceID, action, map[string]interface{}{}, nil)
+}
+
+// clearDropped forgets that a speaker dropped out
+func (s *Supervisor) clearDropped(o *observation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.dropped, normalize(o.speaker.DeviceID))
+}
+
+// forgetRecovered clears the dropout of reachable speakers that are not part of any tracked zone
+func (s *Supervisor) forgetRecovered(observations map[string]*observation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id := range s.dropped {
+		o := observations[id]
+		if !isReachable(o) {
+			continue
+		}
+
+		tracked := false
+
+		for master, zone := range s.zones {
+			if master == id || zone.members[id] {
+				tracked = true
+				break
+			}
+		}
+
+		if !tracked {
+			delete(s.dropped, id)
+		}
+	}
+}
+
+// forget stops tracking the zone of a master
+func (s *Supervisor) forget(master string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.zones, master)
+}
+
+// record logs an action and stores it as device event
+func (s *Supervisor) record(deviceID, action string, data map[string]interface{}, err error) {
+	data["action"] = action
+	data["policy"] = string(s.policy)
+
+	if err != nil {
+		data["error"] = err.Error()
+		log.Printf("[ZoneSupervisor] %s for %s failed: %v", action, deviceID, err)
+	} else {
+		log.Printf("[ZoneSupervisor] %s for %s", action, deviceID)
+	}
+
+	s.sink.Record(deviceID, EventType, data)
+}
+
+// refreshWatchers connects to the WebSocket of every speaker not watched yet
+func (s *Supervisor) refreshWatchers() {
+	s.mu.Lock()
+	enabled := s.events
+	s.mu.Unlock()
+
+	if !enabled {
+		return
+	}
+
+	for _, speaker := range s.speakers() {
+		id := normalize(speaker.DeviceID)
+		if id == "" || speaker.Host == "" {
+			continue
+		}
+
+		s.mu.Lock()
+		_, watched := s.watchers[id]
+		s.mu.Unlock()
+
+		if watched {
+			continue
+		}
+
+		ws := s.client(speaker).NewWebSocketClient(&client.WebSocketConfig{Logger: client.DiscardLogger{}})
+		ws.OnZoneUpdated(func(*models.ZoneUpdatedEvent) { s.Trigger() })
+		ws.OnConnectionState(func(*models.ConnectionStateUpdatedEvent) { s.Trigger() })
+		ws.OnReconnect(func() {
+			// The connection was lost in between, e.g. because the speaker rebooted
+			s.mu.Lock()
+			if _, known := s.dropped[id]; !known {
+				s.dropped[id] = time.Now()
+			}
+			s.mu.Unlock()
+
+			s.Trigger()
+		})
+
+		if err := ws.Connect(); err != nil {
+			continue
+		}
+
+		s.mu.Lock()
+		s.watchers[id] = ws
+		s.mu.Unlock()
+	}
+}
+
+// client returns a cached API client for a speaker
+func (s *Supervisor) client(speaker worker.Speaker) *client.Client {
+	return s.clients.Get(speaker.Host, speaker.Port)
+}
+
+// zoneMembers returns the normalized device IDs of the zone members other than the master
+func zoneMembers(zone *models.ZoneInfo) map[string]bool {
+	members := make(map[string]bool)
+
+	for _, member := range zone.Members {
+		if id := normalize(member.DeviceID); id != "" && id != normalize(zone.Master) {
+			members[id] = true
+		}
+	}
+
+	return members
+}
+
+// isOrphanOf reports whether a speaker still considers itself a member of the master's zone
+func isOrphanOf(o *observation, master string) bool {
+	if !isReachable(o) {
+		return false
+	}
+
+	// A speaker following another master, even in SLAVE_SOURCE, moved on intentionally
+	return o.zone != nil && normalize(o.zone.Master) == master && normalize(o.speaker.DeviceID) != master
+}
+
+func isReachable(o *observation) bool {
+	return o != nil && o.reachable
+}
+
+// isPlaying reports whether a speaker plays content that can be carried over to another master
+func isPlaying(o *observation) bool {
+	if o.content == nil || o.content.Source == "" {
+		return false
+	}
+
+	switch o.source {
+	case "STANDBY", "SLAVE_SOURCE", "INVALID_SOURCE":
+		return false
+	}
+
+	return true
+}
+
+func sortedIDs(ids map[string]bool) []string {
+	sorted := make([]string, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+
+	sort.Strings(sorted)
+
+	return sorted
+}
+
+func normalize(deviceID string) string {
+	return strings.ToUpper(strings.TrimSpace(deviceID))
+}
@@ -0,0 +1,408 @@
+package zonesupervisor
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gesellix/bose-soundtouch/pkg/models"
+	"github.com/gesellix/bose-soundtouch/pkg/service/worker"
+)
+
+// fakeSpeakers simulates speakers that keep their own view of the zone, like real devices do
+// when the master disappears
+type fakeSpeakers struct {
+	mu       sync.Mutex
+	speakers map[string]*fakeSpeaker
+	order    []string
+	events   []models.DeviceEvent
+}
+
+type fakeSpeaker struct {
+	deviceID string
+	host     string
+	port     int
+	down     bool
+	master   string
+	members  []string
+	content  *models.ContentItem
+}
+
+var radio = &models.ContentItem{Source: "TUNEIN", Location: "/v1/playback/station/s1", ItemName: "Radio One"}
+
+func newFakeSpeakers(t *testing.T, ids ...string) *fakeSpeakers {
+	t.Helper()
+
+	f := &fakeSpeakers{speakers: make(map[string]*fakeSpeaker)}
+
+	for _, id := range ids {
+		speaker := &fakeSpeaker{deviceID: id}
+		server := httptest.NewServer(f.handler(speaker))
+		t.Cleanup(server.Close)
+
+		u, _ := url.Parse(server.URL)
+		speaker.host = u.Hostname()
+		speaker.port, _ = strconv.Atoi(u.Port())
+
+		f.speakers[id] = speaker
+		f.order = append(f.order, id)
+	}
+
+	return f
+}
+
+func (f *fakeSpeakers) source() []worker.Speaker {
+	speakers := make([]worker.Speaker, 0, len(f.order))
+	for _, id := range f.order {
+		speakers = append(speakers, worker.Speaker{DeviceID: id, Host: f.speakers[id].host, Port: f.speakers[id].port})
+	}
+
+	return speakers
+}
+
+func (f *fakeSpeakers) sink(_ string, event models.DeviceEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.events = append(f.events, event)
+}
+
+func (f *fakeSpeakers) handler(speaker *fakeSpeaker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		if speaker.down {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/xml")
+
+		switch r.URL.Path {
+		case "/getZone":
+			if speaker.master == "" {
+				_, _ = w.Write([]byte(`<zone />`))
+				return
+			}
+
+			var sb strings.Builder
+
+			_, _ = fmt.Fprintf(&sb, `<zone master="%s">`, speaker.master)
+			for _, id := range speaker.members {
+				_, _ = fmt.Fprintf(&sb, `<member ipaddress="127.0.0.1">%s</member>`, id)
+			}
+
+			sb.WriteString(`</zone>`)
+			_, _ = w.Write([]byte(sb.String()))
+		case "/now_playing":
+			switch {
+			case speaker.master != "" && speaker.master != speaker.deviceID:
+				_, _ = fmt.Fprintf(w, `<nowPlaying deviceID="%s" source="SLAVE_SOURCE"><ContentItem source="SLAVE_SOURCE" /></nowPlaying>`, speaker.deviceID)
+			case speaker.content == nil:
+				_, _ = fmt.Fprintf(w, `<nowPlaying deviceID="%s" source="STANDBY"><ContentItem source="STANDBY" /></nowPlaying>`, speaker.deviceID)
+			default:
+				_, _ = fmt.Fprintf(w, `<nowPlaying deviceID="%s" source="%s"><ContentItem source="%s" location="%s"><itemName>%s</itemName></ContentItem><playStatus>PLAY_STATE</playStatus></nowPlaying>`,
+					speaker.deviceID, speaker.content.Source, speaker.content.Source, speaker.content.Location, speaker.content.ItemName)
+			}
+		case "/select":
+			var item models.ContentItem
+			_ = xml.Unmarshal(body, &item)
+			speaker.content = &item
+			_, _ = w.Write([]byte(`<status>/select</status>`))
+		case "/setZone":
+			var request models.ZoneRequest
+			_ = xml.Unmarshal(body, &request)
+
+			var members []string
+			for _, member := range request.Members {
+				members = append(members, member.DeviceID)
+			}
+
+			f.applyZone(speaker, members)
+			_, _ = w.Write([]byte(`<status>/setZone</status>`))
+		case "/addZoneSlave":
+			var request models.ZoneSlaveRequest
+			_ = xml.Unmarshal(body, &request)
+
+			members := append([]string(nil), speaker.members...)
+			for _, member := range request.Members {
+				members = append(members, member.DeviceID)
+			}
+
+			f.applyZone(speaker, members)
+			_, _ = w.Write([]byte(`<status>/addZoneSlave</status>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+// applyZone makes speaker the master of members; an empty list makes it standalone
+func (f *fakeSpeakers) applyZone(master *fakeSpeaker, members []string) {
+	for _, id := range master.members {
+		if slave := f.speakers[id]; slave.master == master.deviceID {
+			slave.master, slave.members = "", nil
+		}
+	}
+
+	if len(members) == 0 {
+		master.master, master.members = "", nil
+		return
+	}
+
+	if master.master != "" && master.master != master.deviceID {
+		return
+	}
+
+	master.master, master.members = master.deviceID, members
+
+	for _, id := range members {
+		slave := f.speakers[id]
+		slave.master, slave.members, slave.content = master.deviceID, members, nil
+	}
+}
+
+// group creates a healthy zone
+func (f *fakeSpeakers) group(master string, members ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.speakers[master].content = radio
+	f.applyZone(f.speakers[master], members)
+}
+
+// reboot brings a speaker back standalone and without content
+func (f *fakeSpeakers) reboot(id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	speaker := f.speakers[id]
+	speaker.down = false
+	speaker.master, speaker.members, speaker.content = "", nil, nil
+}
+
+func (f *fakeSpeakers) setDown(id string, down bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.speakers[id].down = down
+}
+
+func (f *fakeSpeakers) zoneOf(id string) (string, []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.speakers[id].master, append([]string(nil), f.speakers[id].members...)
+}
+
+func (f *fakeSpeakers) actions() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var actions []string
+	for _, event := range f.events {
+		actions = append(actions, fmt.Sprint(event.Data["action"]))
+	}
+
+	return actions
+}
+
+func TestSupervisor_RejoinAfterMasterReboot(t *testing.T) {
+	f := newFakeSpeakers(t, "AAA", "BBB", "CCC")
+	f.group("AAA", "BBB", "CCC")
+
+	s := New(f.source, f.sink, PolicyRejoin)
+	s.Check()
+
+	f.setDown("AAA", true)
+	s.Check()
+
+	if master, _ := f.zoneOf("BBB"); master != "AAA" {
+		t.Fatalf("Expected BBB to wait for its master, got master %q", master)
+	}
+
+	f.reboot("AAA")
+	s.Check()
+
+	master, members := f.zoneOf("AAA")
+	if master != "AAA" || strings.Join(members, ",") != "BBB,CCC" {
+		t.Errorf("Expected zone AAA with BBB,CCC, got %q with %v", master, members)
+	}
+
+	if f.speakers["AAA"].content == nil || f.speakers["AAA"].content.Location != radio.Location {
+		t.Errorf("Expected the content to be restored on the master, got %+v", f.speakers["AAA"].content)
+	}
+
+	if got := strings.Join(f.actions(), ","); got != "master-unreachable,zone-restored" {
+		t.Errorf("Unexpected actions: %s", got)
+	}
+
+	if f.events[0].Type != EventType {
+		t.Errorf("Expected event type %s, got %s", EventType, f.events[0].Type)
+	}
+}
+
+func TestSupervisor_PromoteAfterGrace(t *testing.T) {
+	f := newFakeSpeakers(t, "AAA", "BBB", "CCC")
+	f.group("AAA", "BBB", "CCC")
+
+	s := New(f.source, f.sink, PolicyPromote)
+	s.SetGracePeriod(0)
+	s.Check()
+
+	f.setDown("AAA", true)
+	s.Check()
+
+	master, members := f.zoneOf("CCC")
+	if master != "BBB" || strings.Join(members, ",") != "CCC" {
+		t.Errorf("Expected BBB to be promoted with CCC, got %q with %v", master, members)
+	}
+
+	if content := f.speakers["BBB"].content; content == nil || content.Location != radio.Location {
+		t.Errorf("Expected the new master to play the same content, got %+v", content)
+	}
+
+	if got := strings.Join(f.actions(), ","); got != "master-unreachable,master-promoted" {
+		t.Errorf("Unexpected actions: %s", got)
+	}
+}
+
+func TestSupervisor_WaitsForGracePeriod(t *testing.T) {
+	f := newFakeSpeakers(t, "AAA", "BBB")
+	f.group("AAA", "BBB")
+
+	s := New(f.source, f.sink, PolicyRelease)
+	s.Check()
+
+	f.setDown("AAA", true)
+	s.Check()
+
+	if master, _ := f.zoneOf("BBB"); master != "AAA" {
+		t.Errorf("Expected BBB to stay in the zone during the grace period, got master %q", master)
+	}
+}
+
+func TestSupervisor_ReleaseOrphans(t *testing.T) {
+	f := newFakeSpeakers(t, "AAA", "BBB", "CCC")
+	f.group("AAA", "BBB", "CCC")
+
+	s := New(f.source, f.sink, PolicyRelease)
+	s.SetGracePeriod(0)
+	s.Check()
+
+	f.setDown("AAA", true)
+	s.Check()
+
+	for _, id := range []string{"BBB", "CCC"} {
+		if master, _ := f.zoneOf(id); master != "" {
+			t.Errorf("Expected %s to be standalone, got master %q", id, master)
+		}
+	}
+
+	if got := strings.Join(f.actions(), ","); got != "master-unreachable,member-released,member-released" {
+		t.Errorf("Unexpected actions: %s", got)
+	}
+}
+
+func TestSupervisor_ReaddsMemberAfterDropout(t *testing.T) {
+	f := newFakeSpeakers(t, "AAA", "BBB", "CCC")
+	f.group("AAA", "BBB", "CCC")
+
+	s := New(f.source, f.sink, PolicyRejoin)
+	s.Check()
+
+	// The master drops the unreachable member, which comes back standalone
+	f.mu.Lock()
+	f.speakers["BBB"].down = true
+	f.applyZone(f.speakers["AAA"], []string{"CCC"})
+	f.mu.Unlock()
+	s.Check()
+
+	f.reboot("BBB")
+	s.Check()
+
+	master, members := f.zoneOf("AAA")
+	if master != "AAA" || strings.Join(members, ",") != "CCC,BBB" {
+		t.Errorf("Expected BBB to be re-added, got %q with %v", master, members)
+	}
+
+	if got := strings.Join(f.actions(), ","); got != "speaker-unreachable,member-readded" {
+		t.Errorf("Unexpected actions: %s", got)
+	}
+}
+
+func TestSupervisor_IgnoresIntentionalChanges(t *testing.T) {
+	f := newFakeSpeakers(t, "AAA", "BBB", "CCC")
+	f.group("AAA", "BBB", "CCC")
+
+	s := New(f.source, f.sink, PolicyRejoin)
+	s.Check()
+
+	f.mu.Lock()
+	f.applyZone(f.speakers["AAA"], []string{"BBB"})
+	f.mu.Unlock()
+	s.Check()
+	s.Check()
+
+	if master, _ := f.zoneOf("CCC"); master != "" {
+		t.Errorf("Expected CCC to stay standalone, got master %q", master)
+	}
+
+	if actions := f.actions(); len(actions) != 0 {
+		t.Errorf("Expected no actions, got %v", actions)
+	}
+}
+
+func TestSupervisor_IgnoresMemberMovedToOtherMaster(t *testing.T) {
+	for _, policy := range []Policy{PolicyRejoin, PolicyRelease} {
+		t.Run(string(policy), func(t *testing.T) {
+			f := newFakeSpeakers(t, "AAA", "BBB", "CCC", "DDD")
+			f.group("AAA", "BBB", "CCC")
+
+			s := New(f.source, f.sink, policy)
+			s.Check()
+
+			// CCC leaves the zone of AAA and follows DDD instead
+			f.mu.Lock()
+			f.applyZone(f.speakers["AAA"], []string{"BBB"})
+			f.applyZone(f.speakers["DDD"], []string{"CCC"})
+			f.mu.Unlock()
+			s.Check()
+			s.Check()
+
+			if master, _ := f.zoneOf("CCC"); master != "DDD" {
+				t.Errorf("Expected CCC to stay in the zone of DDD, got master %q", master)
+			}
+
+			if _, members := f.zoneOf("AAA"); len(members) != 1 || members[0] != "BBB" {
+				t.Errorf("Expected AAA to keep only BBB, got %v", members)
+			}
+
+			if actions := f.actions(); len(actions) != 0 {
+				t.Errorf("Expected no actions, got %v", actions)
+			}
+		})
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	for _, value := range []string{"rejoin", "Promote", " release "} {
+		if _, err := ParsePolicy(value); err != nil {
+			t.Errorf("ParsePolicy(%q) failed: %v", value, err)
+		}
+	}
+
+	if _, err := ParsePolicy("heal"); err == nil {
+		t.Error("Expected an error for an unknown policy")
+	}
+}